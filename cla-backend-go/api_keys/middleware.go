@@ -0,0 +1,87 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package api_keys
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/sirupsen/logrus"
+
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// request headers read by the v2 API security and the handlers
+const (
+	headerACL      = "X-ACL"
+	headerUserName = "X-USERNAME"
+	headerEmail    = "X-EMAIL"
+)
+
+// Middleware authenticates the requests sent with an API key. The ACL, the user name and the email headers of the
+// request are replaced with the ones of the key, the handlers then check the scope of the key like the ones of the
+// users - with the IsUserAuthorizedForProjectTree or the IsUserAuthorizedForOrganization checks. The requests without
+// an API key are passed through. The middleware executes after routing, the key must allow the routed operation.
+func Middleware(service Service, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rawKey := r.Header.Get(HeaderAPIKey)
+		if rawKey == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		reqID := r.Header.Get(utils.XREQUESTID)
+		ctx := context.WithValue(r.Context(), utils.XREQUESTID, reqID) // nolint
+		f := logrus.Fields{
+			"functionName":   "v1.api_keys.middleware.Middleware",
+			utils.XREQUESTID: reqID,
+			"method":         r.Method,
+			"path":           r.URL.Path,
+		}
+
+		var operationID string
+		if route := middleware.MatchedRouteFrom(r); route != nil && route.Operation != nil {
+			operationID = route.Operation.ID
+		}
+
+		key, err := service.Authenticate(ctx, rawKey, operationID)
+		if err != nil {
+			if err == ErrOperationNotAllowed {
+				writeError(w, http.StatusForbidden, utils.ErrorResponseForbiddenWithError(reqID, "api key rejected", err))
+				return
+			}
+			if err == ErrInvalidKey || err == ErrKeyExpired || err == ErrKeyRevoked {
+				writeError(w, http.StatusUnauthorized, utils.ErrorResponseUnauthorizedWithError(reqID, "api key rejected", err))
+				return
+			}
+			log.WithFields(f).WithError(err).Warn("unable to authenticate the api key")
+			writeError(w, http.StatusInternalServerError, utils.ErrorResponseInternalServerErrorWithError(reqID, "unable to authenticate the api key", err))
+			return
+		}
+
+		acl, err := key.EncodedACL()
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("unable to encode the acl of the api key")
+			writeError(w, http.StatusInternalServerError, utils.ErrorResponseInternalServerErrorWithError(reqID, "unable to encode the acl of the api key", err))
+			return
+		}
+		r.Header.Set(headerACL, acl)
+		r.Header.Set(headerUserName, key.PrincipalName())
+		r.Header.Del(headerEmail)
+		log.WithFields(f).Debugf("authenticated the api key: %s for the operation: %s", key.KeyID, operationID)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeError writes the error response
+func writeError(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		log.WithError(err).Warn("unable to write the api key error response")
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package api_keys
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+)
+
+// scope types - the keys act on the project tree or on the organization like the users with the same ACL scopes
+const (
+	ScopeTypeProject      = "project"
+	ScopeTypeOrganization = "organization"
+)
+
+// key statuses
+const (
+	StatusActive  = "active"
+	StatusExpired = "expired"
+	StatusRevoked = "revoked"
+)
+
+// constants
+const (
+	// HeaderAPIKey is the request header carrying the API key
+	HeaderAPIKey = "X-API-Key"
+
+	// keyPrefix starts every API key, the key ID and the secret follow separated by underscores
+	keyPrefix = "ecla_"
+
+	// secretLength is the number of random bytes of the secret
+	secretLength = 32
+
+	// the role and the level of the ACL scope of the keys
+	aclRole  = "api-key"
+	aclLevel = "staff"
+
+	// DefaultExpiryDays is the lifetime of the keys created without an expiry
+	DefaultExpiryDays = 90
+	// MaxExpiryDays is the longest lifetime of a key
+	MaxExpiryDays = 365
+
+	// DefaultRotationGracePeriod is the time the rotated keys remain valid, to roll the new key out
+	DefaultRotationGracePeriod = 24 * time.Hour
+	// MaxRotationGracePeriod is the longest time a rotated key remains valid
+	MaxRotationGracePeriod = 30 * 24 * time.Hour
+
+	// LastUsedInterval is the shortest time between two recorded uses of a key, the uses in between are neither
+	// recorded nor logged
+	LastUsedInterval = time.Minute
+	// LastRejectedInterval is the shortest time between two logged rejected uses of a key, the rejections in
+	// between are not logged - the rejected uses of a leaked or a misconfigured key don't flood the events
+	LastRejectedInterval = time.Minute
+)
+
+// managementOperations are never granted to the keys - a key can't create or extend other keys
+var managementOperations = []string{
+	"listAPIKeys",
+	"createAPIKey",
+	"getAPIKey",
+	"rotateAPIKey",
+	"revokeAPIKey",
+}
+
+// APIKey is a project or organization scoped key for the machine to machine calls of the v2 API. Only the hash of
+// the secret is stored, the secret is returned once when the key is created or rotated.
+type APIKey struct {
+	KeyID string `dynamodbav:"key_id"`
+	Name  string `dynamodbav:"name"`
+	// Scope is the scope type and the scope ID separated by a hash, the key of the scope index
+	Scope     string `dynamodbav:"scope"`
+	ScopeType string `dynamodbav:"scope_type"`
+	// ScopeID is the project SFID or the organization (company) SFID
+	ScopeID string `dynamodbav:"scope_id"`
+	// Operations are the operation IDs of the v2 API the key may call
+	Operations []string `dynamodbav:"operations,stringset"`
+	SecretHash string   `dynamodbav:"secret_hash"`
+
+	DateExpires  string `dynamodbav:"date_expires"`
+	DateRevoked  string `dynamodbav:"date_revoked"`
+	DateLastUsed string `dynamodbav:"date_last_used"`
+	// DateLastRejected is the time of the last logged rejected use
+	DateLastRejected string `dynamodbav:"date_last_rejected"`
+	// RotatedFrom and RotatedTo link the keys of a rotation
+	RotatedFrom string `dynamodbav:"rotated_from"`
+	RotatedTo   string `dynamodbav:"rotated_to"`
+	CreatedBy   string `dynamodbav:"created_by"`
+	RevokedBy   string `dynamodbav:"revoked_by"`
+
+	DateCreated  string `dynamodbav:"date_created"`
+	DateModified string `dynamodbav:"date_modified"`
+	Version      string `dynamodbav:"version"`
+}
+
+// KeyInput is the request to create a key
+type KeyInput struct {
+	Name       string
+	ScopeType  string
+	ScopeID    string
+	Operations []string
+	// ExpiresInDays is the lifetime of the key, DefaultExpiryDays when not set
+	ExpiresInDays int
+	CreatedBy     string
+}
+
+// scopeKey returns the value of the scope index key
+func scopeKey(scopeType, scopeID string) string {
+	return fmt.Sprintf("%s#%s", scopeType, scopeID)
+}
+
+// Status returns the status of the key at the time
+func (k *APIKey) Status(now time.Time) string {
+	if k.DateRevoked != "" {
+		return StatusRevoked
+	}
+	expires, err := time.Parse(time.RFC3339, k.DateExpires)
+	if err != nil || !now.Before(expires) {
+		return StatusExpired
+	}
+	return StatusActive
+}
+
+// AllowsOperation returns true when the key may call the operation
+func (k *APIKey) AllowsOperation(operationID string) bool {
+	for _, operation := range k.Operations {
+		if operation == operationID {
+			return true
+		}
+	}
+	return false
+}
+
+// PrincipalName is the user name of the requests authenticated with the key
+func (k *APIKey) PrincipalName() string {
+	return fmt.Sprintf("api-key-%s", k.KeyID)
+}
+
+// ACL returns the access control list of the requests authenticated with the key, the scope of the key is checked
+// by the permission checks of the users
+func (k *APIKey) ACL() auth.ACL {
+	return auth.ACL{
+		Admin:   false,
+		Allowed: true,
+		Context: aclLevel,
+		Scopes: []auth.Scope{
+			{
+				Type:  k.ScopeType,
+				ID:    k.ScopeID,
+				Role:  aclRole,
+				Level: aclLevel,
+			},
+		},
+	}
+}
+
+// EncodedACL returns the ACL in the X-ACL header format
+func (k *APIKey) EncodedACL() (string, error) {
+	data, err := json.Marshal(k.ACL())
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
@@ -0,0 +1,310 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package api_keys
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
+)
+
+// indexes
+const (
+	ScopeIndex = "scope-index"
+)
+
+// attribute names
+const (
+	keyIDAttributeName            = "key_id"
+	scopeAttributeName            = "scope"
+	dateLastUsedAttributeName     = "date_last_used"
+	dateLastRejectedAttributeName = "date_last_rejected"
+	rotatedToAttributeName        = "rotated_to"
+)
+
+// ErrKeyDoesNotExist is returned when the API key does not exist
+var ErrKeyDoesNotExist = errors.New("api key does not exist")
+
+// Repository interface defines the functions for the API keys data model
+type Repository interface {
+	PutKey(ctx context.Context, key *APIKey) error
+	GetKey(ctx context.Context, keyID string) (*APIKey, error)
+	ListKeys(ctx context.Context, scopeType, scopeID string) ([]*APIKey, error)
+	// RotateKey stores the rotated key along with the new key of the rotation in a single transaction, it returns
+	// ErrKeyAlreadyRotated when the key was rotated meanwhile
+	RotateKey(ctx context.Context, key, rotated *APIKey) error
+	// UpdateLastUsed records the use of the key when its last recorded use is not after usedBefore, it returns false
+	// when a later use was recorded meanwhile
+	UpdateLastUsed(ctx context.Context, keyID, dateLastUsed, usedBefore string) (bool, error)
+	// UpdateLastRejected records the rejected use of the key when its last recorded rejection is not after
+	// rejectedBefore, it returns false when a later rejection was recorded meanwhile
+	UpdateLastRejected(ctx context.Context, keyID, dateLastRejected, rejectedBefore string) (bool, error)
+}
+
+type repository struct {
+	stage          string
+	dynamoDBClient *dynamodb.DynamoDB
+	keysTableName  string
+}
+
+// NewRepository creates a new instance of the API keys repository
+func NewRepository(awsSession *session.Session, stage string) Repository {
+	return &repository{
+		stage:          stage,
+		dynamoDBClient: dynamodb.New(awsSession),
+		keysTableName:  fmt.Sprintf("cla-%s-api-keys", stage),
+	}
+}
+
+// PutKey creates or replaces the key record
+func (repo *repository) PutKey(ctx context.Context, key *APIKey) error {
+	ctx, span := telemetry.StartSpan(ctx, "api_keys.repository.PutKey")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.api_keys.repository.PutKey",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"keyID":          key.KeyID,
+		"scope":          key.Scope,
+	}
+
+	av, err := dynamodbattribute.MarshalMap(key)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to marshall the api key")
+		return err
+	}
+
+	_, err = repo.dynamoDBClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		Item:      av,
+		TableName: aws.String(repo.keysTableName),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("cannot put the api key in dynamodb")
+		return err
+	}
+	return nil
+}
+
+// GetKey returns the key
+func (repo *repository) GetKey(ctx context.Context, keyID string) (*APIKey, error) {
+	ctx, span := telemetry.StartSpan(ctx, "api_keys.repository.GetKey")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.api_keys.repository.GetKey",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"keyID":          keyID,
+	}
+
+	result, err := repo.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			keyIDAttributeName: {S: aws.String(keyID)},
+		},
+		TableName:      aws.String(repo.keysTableName),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("error retrieving the api key")
+		return nil, err
+	}
+	if len(result.Item) == 0 {
+		return nil, ErrKeyDoesNotExist
+	}
+
+	var key APIKey
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// ListKeys returns the keys of the scope
+func (repo *repository) ListKeys(ctx context.Context, scopeType, scopeID string) ([]*APIKey, error) {
+	ctx, span := telemetry.StartSpan(ctx, "api_keys.repository.ListKeys")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.api_keys.repository.ListKeys",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"scopeType":      scopeType,
+		"scopeID":        scopeID,
+	}
+
+	condition := expression.Key(scopeAttributeName).Equal(expression.Value(scopeKey(scopeType, scopeID)))
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).Build()
+	if err != nil {
+		log.WithFields(f).Warnf("problem building query expression, error: %+v", err)
+		return nil, err
+	}
+
+	var keys []*APIKey
+	err = repo.dynamoDBClient.QueryPagesWithContext(ctx, &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		TableName:                 aws.String(repo.keysTableName),
+		IndexName:                 aws.String(ScopeIndex),
+	}, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		var pageKeys []*APIKey
+		if unmarshalErr := dynamodbattribute.UnmarshalListOfMaps(page.Items, &pageKeys); unmarshalErr != nil {
+			err = unmarshalErr
+			return false
+		}
+		keys = append(keys, pageKeys...)
+		return true
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("error retrieving the api keys")
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// RotateKey stores the rotated key along with the new key of the rotation in a single transaction, it returns
+// ErrKeyAlreadyRotated when the key was rotated meanwhile
+func (repo *repository) RotateKey(ctx context.Context, key, rotated *APIKey) error {
+	ctx, span := telemetry.StartSpan(ctx, "api_keys.repository.RotateKey")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.api_keys.repository.RotateKey",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"keyID":          key.KeyID,
+		"rotatedKeyID":   rotated.KeyID,
+		"scope":          key.Scope,
+	}
+
+	keyItem, err := dynamodbattribute.MarshalMap(key)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to marshall the api key")
+		return err
+	}
+	rotatedItem, err := dynamodbattribute.MarshalMap(rotated)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to marshall the new api key")
+		return err
+	}
+
+	// the key must not have been deleted or rotated meanwhile, the new key must not exist
+	keyCondition := expression.AttributeExists(expression.Name(keyIDAttributeName)).And(
+		expression.AttributeNotExists(expression.Name(rotatedToAttributeName)).Or(
+			expression.Name(rotatedToAttributeName).AttributeType(expression.Null)))
+	keyExpr, err := expression.NewBuilder().WithCondition(keyCondition).Build()
+	if err != nil {
+		log.WithFields(f).Warnf("problem building condition expression, error: %+v", err)
+		return err
+	}
+	rotatedExpr, err := expression.NewBuilder().WithCondition(expression.AttributeNotExists(expression.Name(keyIDAttributeName))).Build()
+	if err != nil {
+		log.WithFields(f).Warnf("problem building condition expression, error: %+v", err)
+		return err
+	}
+
+	_, err = repo.dynamoDBClient.TransactWriteItemsWithContext(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []*dynamodb.TransactWriteItem{
+			{Put: &dynamodb.Put{
+				TableName:                 aws.String(repo.keysTableName),
+				Item:                      rotatedItem,
+				ConditionExpression:       rotatedExpr.Condition(),
+				ExpressionAttributeNames:  rotatedExpr.Names(),
+				ExpressionAttributeValues: rotatedExpr.Values(),
+			}},
+			{Put: &dynamodb.Put{
+				TableName:                 aws.String(repo.keysTableName),
+				Item:                      keyItem,
+				ConditionExpression:       keyExpr.Condition(),
+				ExpressionAttributeNames:  keyExpr.Names(),
+				ExpressionAttributeValues: keyExpr.Values(),
+			}},
+		},
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeTransactionCanceledException {
+			log.WithFields(f).WithError(err).Warn("the api key was rotated meanwhile")
+			return ErrKeyAlreadyRotated
+		}
+		log.WithFields(f).WithError(err).Warn("cannot rotate the api key in dynamodb")
+		return err
+	}
+	return nil
+}
+
+// UpdateLastUsed records the use of the key when its last recorded use is not after usedBefore, it returns false
+// when a later use was recorded meanwhile
+func (repo *repository) UpdateLastUsed(ctx context.Context, keyID, dateLastUsed, usedBefore string) (bool, error) {
+	ctx, span := telemetry.StartSpan(ctx, "api_keys.repository.UpdateLastUsed")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.api_keys.repository.UpdateLastUsed",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"keyID":          keyID,
+	}
+	return repo.updateDateIfNotAfter(ctx, f, keyID, dateLastUsedAttributeName, dateLastUsed, usedBefore)
+}
+
+// UpdateLastRejected records the rejected use of the key when its last recorded rejection is not after
+// rejectedBefore, it returns false when a later rejection was recorded meanwhile
+func (repo *repository) UpdateLastRejected(ctx context.Context, keyID, dateLastRejected, rejectedBefore string) (bool, error) {
+	ctx, span := telemetry.StartSpan(ctx, "api_keys.repository.UpdateLastRejected")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.api_keys.repository.UpdateLastRejected",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"keyID":          keyID,
+	}
+	return repo.updateDateIfNotAfter(ctx, f, keyID, dateLastRejectedAttributeName, dateLastRejected, rejectedBefore)
+}
+
+// updateDateIfNotAfter sets the date attribute of the key when the attribute is not after the before date, it returns
+// false when a later date was set meanwhile
+func (repo *repository) updateDateIfNotAfter(ctx context.Context, f logrus.Fields, keyID, attributeName, date, before string) (bool, error) {
+	// the key may have been deleted meanwhile - the update doesn't create it again. The dates are RFC 3339 UTC
+	// strings, they compare in time order.
+	update := expression.Set(expression.Name(attributeName), expression.Value(date))
+	condition := expression.AttributeExists(expression.Name(keyIDAttributeName)).And(
+		expression.AttributeNotExists(expression.Name(attributeName)).Or(
+			expression.Name(attributeName).AttributeType(expression.Null),
+			expression.Name(attributeName).LessThanEqual(expression.Value(before))))
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(condition).Build()
+	if err != nil {
+		log.WithFields(f).Warnf("problem building update expression, error: %+v", err)
+		return false, err
+	}
+
+	_, err = repo.dynamoDBClient.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			keyIDAttributeName: {S: aws.String(keyID)},
+		},
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		TableName:                 aws.String(repo.keysTableName),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			log.WithFields(f).Debugf("a later %s of the api key was recorded meanwhile", attributeName)
+			return false, nil
+		}
+		log.WithFields(f).WithError(err).Warnf("cannot update the %s of the api key", attributeName)
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,122 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package api_keys
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// memoryRepository is an embedded, in-memory implementation of the API keys Repository. It is intended for local
+// development and tests where DynamoDB is not available.
+type memoryRepository struct {
+	lock sync.RWMutex
+	keys map[string]APIKey
+}
+
+// NewMemoryRepository creates a new instance of the in-memory API keys repository
+func NewMemoryRepository() Repository {
+	return &memoryRepository{
+		keys: map[string]APIKey{},
+	}
+}
+
+// PutKey creates or replaces the key record
+func (repo *memoryRepository) PutKey(ctx context.Context, key *APIKey) error {
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	stored := *key
+	stored.Operations = append([]string(nil), key.Operations...)
+	repo.keys[key.KeyID] = stored
+	return nil
+}
+
+// GetKey returns the key
+func (repo *memoryRepository) GetKey(ctx context.Context, keyID string) (*APIKey, error) {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	key, ok := repo.keys[keyID]
+	if !ok {
+		return nil, ErrKeyDoesNotExist
+	}
+	key.Operations = append([]string(nil), key.Operations...)
+	return &key, nil
+}
+
+// ListKeys returns the keys of the scope, oldest first
+func (repo *memoryRepository) ListKeys(ctx context.Context, scopeType, scopeID string) ([]*APIKey, error) {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	scope := scopeKey(scopeType, scopeID)
+	var keys []*APIKey
+	for _, key := range repo.keys {
+		if key.Scope == scope {
+			out := key
+			out.Operations = append([]string(nil), key.Operations...)
+			keys = append(keys, &out)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].DateCreated != keys[j].DateCreated {
+			return keys[i].DateCreated < keys[j].DateCreated
+		}
+		return keys[i].KeyID < keys[j].KeyID
+	})
+	return keys, nil
+}
+
+// RotateKey stores the rotated key along with the new key of the rotation, it returns ErrKeyAlreadyRotated when the
+// key was rotated meanwhile
+func (repo *memoryRepository) RotateKey(ctx context.Context, key, rotated *APIKey) error {
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	stored, ok := repo.keys[key.KeyID]
+	if !ok {
+		return ErrKeyDoesNotExist
+	}
+	if stored.RotatedTo != "" {
+		return ErrKeyAlreadyRotated
+	}
+	for _, k := range []*APIKey{rotated, key} {
+		out := *k
+		out.Operations = append([]string(nil), k.Operations...)
+		repo.keys[k.KeyID] = out
+	}
+	return nil
+}
+
+// UpdateLastUsed records the use of the key when its last recorded use is not after usedBefore, it returns false
+// when a later use was recorded meanwhile
+func (repo *memoryRepository) UpdateLastUsed(ctx context.Context, keyID, dateLastUsed, usedBefore string) (bool, error) {
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	key, ok := repo.keys[keyID]
+	if !ok {
+		return false, ErrKeyDoesNotExist
+	}
+	if key.DateLastUsed != "" && key.DateLastUsed > usedBefore {
+		return false, nil
+	}
+	key.DateLastUsed = dateLastUsed
+	repo.keys[keyID] = key
+	return true, nil
+}
+
+// UpdateLastRejected records the rejected use of the key when its last recorded rejection is not after
+// rejectedBefore, it returns false when a later rejection was recorded meanwhile
+func (repo *memoryRepository) UpdateLastRejected(ctx context.Context, keyID, dateLastRejected, rejectedBefore string) (bool, error) {
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	key, ok := repo.keys[keyID]
+	if !ok {
+		return false, ErrKeyDoesNotExist
+	}
+	if key.DateLastRejected != "" && key.DateLastRejected > rejectedBefore {
+		return false, nil
+	}
+	key.DateLastRejected = dateLastRejected
+	repo.keys[keyID] = key
+	return true, nil
+}
@@ -0,0 +1,426 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package api_keys
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// errors
+var (
+	ErrInvalidName         = errors.New("invalid name - the name of the key is required")
+	ErrInvalidScope        = errors.New("invalid scope - expecting a project or an organization scope with the scope ID")
+	ErrInvalidOperations   = errors.New("invalid operations - expecting one or more operation IDs of the v2 API, the API key operations are not allowed")
+	ErrInvalidExpiry       = fmt.Errorf("invalid expiry - expecting 1 to %d days", MaxExpiryDays)
+	ErrInvalidGracePeriod  = fmt.Errorf("invalid grace period - expecting up to %d hours", int(MaxRotationGracePeriod.Hours()))
+	ErrKeyNotActive        = errors.New("the api key is expired or revoked")
+	ErrKeyAlreadyRotated   = errors.New("the api key was already rotated")
+	ErrInvalidKey          = errors.New("invalid api key")
+	ErrKeyExpired          = errors.New("the api key is expired")
+	ErrKeyRevoked          = errors.New("the api key was revoked")
+	ErrOperationNotAllowed = errors.New("the api key is not allowed to call the operation")
+	errMalformedKey        = errors.New("malformed api key")
+	errSecretDoesNotMatch  = errors.New("the secret of the api key does not match")
+)
+
+// Service interface defines the API keys service methods
+type Service interface {
+	CreateKey(ctx context.Context, input *KeyInput) (*APIKey, string, error)
+	GetKey(ctx context.Context, keyID string) (*APIKey, error)
+	ListKeys(ctx context.Context, scopeType, scopeID string) ([]*APIKey, error)
+	RotateKey(ctx context.Context, keyID string, gracePeriod time.Duration, rotatedBy string) (*APIKey, string, error)
+	RevokeKey(ctx context.Context, keyID, revokedBy string) (*APIKey, error)
+	Authenticate(ctx context.Context, rawKey, operationID string) (*APIKey, error)
+}
+
+type service struct {
+	repo          Repository
+	eventsService events.Service
+	// operations are the operation IDs of the v2 API that may be granted to the keys
+	operations map[string]bool
+	now        func() time.Time
+}
+
+// NewService creates a new API keys service, the keys may be granted the operations of the list
+func NewService(repo Repository, eventsService events.Service, operationIDs []string) Service {
+	operations := map[string]bool{}
+	for _, operationID := range operationIDs {
+		operations[operationID] = true
+	}
+	for _, operationID := range managementOperations {
+		delete(operations, operationID)
+	}
+	return &service{
+		repo:          repo,
+		eventsService: eventsService,
+		operations:    operations,
+		now:           time.Now,
+	}
+}
+
+// hashSecret returns the hash of the secret stored with the key
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// newSecret returns a random secret
+func newSecret() (string, error) {
+	data := make([]byte, secretLength)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// formatKey returns the API key sent by the clients
+func formatKey(keyID, secret string) string {
+	return keyPrefix + keyID + "_" + secret
+}
+
+// parseKey returns the key ID and the secret of the API key
+func parseKey(rawKey string) (string, string, error) {
+	if !strings.HasPrefix(rawKey, keyPrefix) {
+		return "", "", errMalformedKey
+	}
+	// the key ID is a UUID, the secret is base64 URL encoded - both may contain dashes but the key ID has no underscore
+	parts := strings.SplitN(strings.TrimPrefix(rawKey, keyPrefix), "_", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errMalformedKey
+	}
+	return parts[0], parts[1], nil
+}
+
+// validateOperations returns the sorted operations, when the operations can be granted to a key
+func (s *service) validateOperations(operations []string) ([]string, error) {
+	unique := map[string]bool{}
+	for _, operationID := range operations {
+		if !s.operations[operationID] {
+			return nil, ErrInvalidOperations
+		}
+		unique[operationID] = true
+	}
+	if len(unique) == 0 {
+		return nil, ErrInvalidOperations
+	}
+	var result []string
+	for operationID := range unique {
+		result = append(result, operationID)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// newKey returns a new key with the secret
+func newKey(now time.Time) (*APIKey, string, error) {
+	keyID, err := uuid.NewV4()
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := newSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	currentTime := utils.TimeToString(now)
+	return &APIKey{
+		KeyID:        keyID.String(),
+		SecretHash:   hashSecret(secret),
+		DateCreated:  currentTime,
+		DateModified: currentTime,
+		Version:      "v1",
+	}, formatKey(keyID.String(), secret), nil
+}
+
+// CreateKey creates the key, the API key with the secret is returned once
+func (s *service) CreateKey(ctx context.Context, input *KeyInput) (*APIKey, string, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.api_keys.service.CreateKey",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"scopeType":      input.ScopeType,
+		"scopeID":        input.ScopeID,
+		"createdBy":      input.CreatedBy,
+	}
+
+	if strings.TrimSpace(input.Name) == "" {
+		return nil, "", ErrInvalidName
+	}
+	if (input.ScopeType != ScopeTypeProject && input.ScopeType != ScopeTypeOrganization) || input.ScopeID == "" {
+		return nil, "", ErrInvalidScope
+	}
+	operations, err := s.validateOperations(input.Operations)
+	if err != nil {
+		return nil, "", err
+	}
+	expiresInDays := input.ExpiresInDays
+	if expiresInDays == 0 {
+		expiresInDays = DefaultExpiryDays
+	}
+	if expiresInDays < 0 || expiresInDays > MaxExpiryDays {
+		return nil, "", ErrInvalidExpiry
+	}
+
+	now := s.now().UTC()
+	key, apiKey, err := newKey(now)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to generate the api key")
+		return nil, "", err
+	}
+	key.Name = strings.TrimSpace(input.Name)
+	key.Scope = scopeKey(input.ScopeType, input.ScopeID)
+	key.ScopeType = input.ScopeType
+	key.ScopeID = input.ScopeID
+	key.Operations = operations
+	key.DateExpires = utils.TimeToString(now.AddDate(0, 0, expiresInDays))
+	key.CreatedBy = input.CreatedBy
+	if err = s.repo.PutKey(ctx, key); err != nil {
+		return nil, "", err
+	}
+	log.WithFields(f).Debugf("created the api key: %s", key.KeyID)
+
+	s.logEvent(ctx, key, events.APIKeyCreated, input.CreatedBy, &events.APIKeyCreatedEventData{
+		KeyID:       key.KeyID,
+		Name:        key.Name,
+		Operations:  key.Operations,
+		DateExpires: key.DateExpires,
+	})
+	return key, apiKey, nil
+}
+
+// GetKey returns the key
+func (s *service) GetKey(ctx context.Context, keyID string) (*APIKey, error) {
+	return s.repo.GetKey(ctx, keyID)
+}
+
+// ListKeys returns the keys of the scope
+func (s *service) ListKeys(ctx context.Context, scopeType, scopeID string) ([]*APIKey, error) {
+	if (scopeType != ScopeTypeProject && scopeType != ScopeTypeOrganization) || scopeID == "" {
+		return nil, ErrInvalidScope
+	}
+	return s.repo.ListKeys(ctx, scopeType, scopeID)
+}
+
+// RotateKey replaces the key with a new key of the same scope, operations and expiry. The rotated key remains valid
+// for the grace period, to roll the new key out - the API key of the new key with the secret is returned once
+func (s *service) RotateKey(ctx context.Context, keyID string, gracePeriod time.Duration, rotatedBy string) (*APIKey, string, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.api_keys.service.RotateKey",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"keyID":          keyID,
+		"gracePeriod":    gracePeriod.String(),
+		"rotatedBy":      rotatedBy,
+	}
+
+	if gracePeriod < 0 || gracePeriod > MaxRotationGracePeriod {
+		return nil, "", ErrInvalidGracePeriod
+	}
+	key, err := s.repo.GetKey(ctx, keyID)
+	if err != nil {
+		return nil, "", err
+	}
+	now := s.now().UTC()
+	if key.Status(now) != StatusActive {
+		return nil, "", ErrKeyNotActive
+	}
+	if key.RotatedTo != "" {
+		return nil, "", ErrKeyAlreadyRotated
+	}
+
+	rotated, apiKey, err := newKey(now)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to generate the api key")
+		return nil, "", err
+	}
+	rotated.Name = key.Name
+	rotated.Scope = key.Scope
+	rotated.ScopeType = key.ScopeType
+	rotated.ScopeID = key.ScopeID
+	rotated.Operations = key.Operations
+	rotated.DateExpires = key.DateExpires
+	rotated.RotatedFrom = key.KeyID
+	rotated.CreatedBy = rotatedBy
+
+	// the rotated key expires at the end of the grace period, unless it expires before
+	graceEnd := utils.TimeToString(now.Add(gracePeriod))
+	if graceEnd < key.DateExpires {
+		key.DateExpires = graceEnd
+	}
+	key.RotatedTo = rotated.KeyID
+	key.DateModified = utils.TimeToString(now)
+	// both keys are stored at once, a failed or a concurrent rotation doesn't leave an unlinked new key
+	if err = s.repo.RotateKey(ctx, key, rotated); err != nil {
+		return nil, "", err
+	}
+	log.WithFields(f).Debugf("rotated the api key to the api key: %s", rotated.KeyID)
+
+	s.logEvent(ctx, key, events.APIKeyRotated, rotatedBy, &events.APIKeyRotatedEventData{
+		KeyID:          key.KeyID,
+		Name:           key.Name,
+		NewKeyID:       rotated.KeyID,
+		GracePeriodEnd: key.DateExpires,
+	})
+	return rotated, apiKey, nil
+}
+
+// RevokeKey revokes the key, the revoked keys are rejected immediately
+func (s *service) RevokeKey(ctx context.Context, keyID, revokedBy string) (*APIKey, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.api_keys.service.RevokeKey",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"keyID":          keyID,
+		"revokedBy":      revokedBy,
+	}
+
+	key, err := s.repo.GetKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if key.DateRevoked != "" {
+		log.WithFields(f).Debug("the api key was already revoked")
+		return key, nil
+	}
+
+	currentTime := utils.TimeToString(s.now())
+	key.DateRevoked = currentTime
+	key.RevokedBy = revokedBy
+	key.DateModified = currentTime
+	if err = s.repo.PutKey(ctx, key); err != nil {
+		return nil, err
+	}
+	log.WithFields(f).Debug("revoked the api key")
+
+	s.logEvent(ctx, key, events.APIKeyRevoked, revokedBy, &events.APIKeyRevokedEventData{
+		KeyID: key.KeyID,
+		Name:  key.Name,
+	})
+	return key, nil
+}
+
+// Authenticate returns the key of the API key when the key is active and allowed to call the operation. The rejected
+// uses of a known key are logged once per LastRejectedInterval, the successful uses are recorded and logged once per
+// LastUsedInterval.
+func (s *service) Authenticate(ctx context.Context, rawKey, operationID string) (*APIKey, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.api_keys.service.Authenticate",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"operationID":    operationID,
+	}
+
+	keyID, secret, err := parseKey(rawKey)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("rejecting the api key")
+		return nil, ErrInvalidKey
+	}
+	f["keyID"] = keyID
+
+	key, err := s.repo.GetKey(ctx, keyID)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("rejecting the api key")
+		if err == ErrKeyDoesNotExist {
+			return nil, ErrInvalidKey
+		}
+		return nil, err
+	}
+
+	var rejection error
+	switch {
+	case subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(key.SecretHash)) != 1:
+		rejection = errSecretDoesNotMatch
+	case key.Status(s.now()) == StatusRevoked:
+		rejection = ErrKeyRevoked
+	case key.Status(s.now()) == StatusExpired:
+		rejection = ErrKeyExpired
+	case !key.AllowsOperation(operationID):
+		rejection = ErrOperationNotAllowed
+	}
+	if rejection != nil {
+		log.WithFields(f).WithError(rejection).Warn("rejecting the api key")
+		s.logRejection(ctx, f, key, operationID, rejection)
+		if rejection == errSecretDoesNotMatch {
+			return nil, ErrInvalidKey
+		}
+		return nil, rejection
+	}
+
+	// the use is recorded and logged at most once per interval, the first use of the interval wins
+	currentTime := s.now()
+	usedBefore := utils.TimeToString(currentTime.Add(-LastUsedInterval))
+	if key.DateLastUsed != "" && key.DateLastUsed > usedBefore {
+		return key, nil
+	}
+	recorded, err := s.repo.UpdateLastUsed(ctx, key.KeyID, utils.TimeToString(currentTime), usedBefore)
+	if err != nil {
+		// the use is still logged, the audit trail doesn't depend on the last use
+		log.WithFields(f).WithError(err).Warn("unable to record the last use of the api key")
+	}
+	if err != nil || recorded {
+		s.logEvent(ctx, key, events.APIKeyUsed, "", &events.APIKeyUsedEventData{
+			KeyID:       key.KeyID,
+			Name:        key.Name,
+			OperationID: operationID,
+		})
+	}
+	return key, nil
+}
+
+// logRejection logs the rejected use of the key at most once per interval, the first rejection of the interval wins
+func (s *service) logRejection(ctx context.Context, f logrus.Fields, key *APIKey, operationID string, rejection error) {
+	currentTime := s.now()
+	rejectedBefore := utils.TimeToString(currentTime.Add(-LastRejectedInterval))
+	if key.DateLastRejected != "" && key.DateLastRejected > rejectedBefore {
+		return
+	}
+	recorded, err := s.repo.UpdateLastRejected(ctx, key.KeyID, utils.TimeToString(currentTime), rejectedBefore)
+	if err != nil {
+		// the rejection is still logged, the audit trail doesn't depend on the last rejection
+		log.WithFields(f).WithError(err).Warn("unable to record the last rejected use of the api key")
+	}
+	if err != nil || recorded {
+		s.logEvent(ctx, key, events.APIKeyRejected, "", &events.APIKeyRejectedEventData{
+			KeyID:       key.KeyID,
+			Name:        key.Name,
+			OperationID: operationID,
+			Reason:      rejection.Error(),
+		})
+	}
+}
+
+// logEvent logs the event of the key, the events of the key uses are logged for the key itself
+func (s *service) logEvent(ctx context.Context, key *APIKey, eventType, lfUsername string, eventData events.EventData) {
+	args := &events.LogEventArgs{
+		EventType: eventType,
+		EventData: eventData,
+	}
+	if lfUsername != "" {
+		args.LfUsername = lfUsername
+	} else {
+		args.UserModel = &models.User{
+			UserID:   key.PrincipalName(),
+			Username: key.Name,
+		}
+	}
+	if key.ScopeType == ScopeTypeOrganization {
+		args.CompanySFID = key.ScopeID
+	} else {
+		args.ProjectSFID = key.ScopeID
+	}
+	s.eventsService.LogEventWithContext(ctx, args)
+}
@@ -4,49 +4,57 @@
 package auth
 
 import (
-	"encoding/json"
 	"errors"
-	"net/http"
-	"path"
+	"strings"
 
 	"github.com/dgrijalva/jwt-go"
 )
 
-// Validator data model
+// Validator data model - validates the tokens of the trusted OIDC issuers
 type Validator struct {
-	clientID      string
-	usernameClaim string
-	algorithm     string
-	wellKnownURL  string
-	nameClaim     string
-	emailClaim    string
+	issuers []*trustedIssuer
 }
 
-// NewAuthValidator creates a new auth0 validator based on the specified parameters
-func NewAuthValidator(domain, clientID, usernameClaim, algorithm string) (Validator, error) { // nolint
+// NewAuthValidator creates a new auth0 validator based on the specified parameters, the tokens of the additional
+// OIDC issuers are trusted as well
+func NewAuthValidator(domain, clientID, usernameClaim, algorithm string, issuers ...IssuerConfig) (Validator, error) { // nolint
+	auth0Issuer, err := NewAuth0IssuerConfig(domain, clientID, usernameClaim, algorithm)
+	if err != nil {
+		return Validator{}, err
+	}
+
+	return NewOIDCValidator(append([]IssuerConfig{auth0Issuer}, issuers...), nil)
+}
+
+// NewAuth0IssuerConfig returns the issuer configuration of the auth0 tenant
+func NewAuth0IssuerConfig(domain, clientID, usernameClaim, algorithm string) (IssuerConfig, error) {
 	if domain == "" {
-		return Validator{}, errors.New("missing Domain")
+		return IssuerConfig{}, errors.New("missing Domain")
 	}
 	if clientID == "" {
-		return Validator{}, errors.New("missing ClientID")
+		return IssuerConfig{}, errors.New("missing ClientID")
 	}
 	if usernameClaim == "" {
-		return Validator{}, errors.New("missing UsernameClaim")
+		return IssuerConfig{}, errors.New("missing UsernameClaim")
 	}
 	if algorithm == "" {
-		return Validator{}, errors.New("missing Algorithm")
-	}
-
-	validator := Validator{
-		clientID:      clientID,
-		usernameClaim: usernameClaim,
-		algorithm:     algorithm,
-		wellKnownURL:  "https://" + path.Join(domain, ".well-known/jwks.json"),
-		nameClaim:     "name",
-		emailClaim:    "email",
+		return IssuerConfig{}, errors.New("missing Algorithm")
 	}
-
-	return validator, nil
+	domain = strings.TrimSuffix(domain, "/")
+
+	// the audience of the auth0 tokens isn't checked, the tokens of the other auth0 applications are accepted
+	return IssuerConfig{
+		Issuer:             "https://" + domain + "/",
+		SkipAudienceCheck:  true,
+		LFIdentityProvider: true,
+		Algorithms:         []string{algorithm},
+		JWKSURL:            "https://" + domain + "/.well-known/jwks.json",
+		ClaimMapping: ClaimMapping{
+			UsernameClaim: usernameClaim,
+			NameClaim:     "name",
+			EmailClaim:    "email",
+		},
+	}, nil
 }
 
 // VerifyToken verifies the specified token
@@ -54,7 +62,7 @@ func (av Validator) VerifyToken(token string) (map[string]interface{}, error) {
 	// Using jwt.MapClaims because our username field is set dynamically
 	// based on environment
 	claims := jwt.MapClaims{}
-	jwtToken, err := jwt.ParseWithClaims(token, claims, av.getPemCert)
+	jwtToken, err := jwt.ParseWithClaims(token, claims, av.getKey)
 	if err != nil {
 		return nil, err
 	}
@@ -71,50 +79,10 @@ func (av Validator) VerifyToken(token string) (map[string]interface{}, error) {
 		return nil, errors.New("claims are not valid")
 	}
 
-	return allClaims, nil
-}
-
-type jwks struct {
-	Keys []jsonWebKeys `json:"keys"`
-}
-
-type jsonWebKeys struct {
-	Kty string   `json:"kty"`
-	Kid string   `json:"kid"`
-	Use string   `json:"use"`
-	N   string   `json:"n"`
-	E   string   `json:"e"`
-	X5c []string `json:"x5c"`
-}
-
-func (av Validator) getPemCert(token *jwt.Token) (interface{}, error) {
-	cert := ""
-	resp, err := http.Get(av.wellKnownURL)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	var j = jwks{}
-	err = json.NewDecoder(resp.Body).Decode(&j)
-	if err != nil {
-		return "", err
-	}
-
-	for k := range j.Keys {
-		if token.Header["kid"] == j.Keys[k].Kid {
-			cert = "-----BEGIN CERTIFICATE-----\n" + j.Keys[k].X5c[0] + "\n-----END CERTIFICATE-----"
-		}
+	// the key lookup has already checked the issuer is trusted
+	if !av.issuerOf(allClaims).allowsAudience(allClaims["aud"]) {
+		return nil, ErrInvalidAudience
 	}
 
-	if cert == "" {
-		return "", errors.New("unable to find appropriate key")
-	}
-
-	rsaPublicKey, err := jwt.ParseRSAPublicKeyFromPEM([]byte(cert))
-	if err != nil {
-		return "", err
-	}
-
-	return rsaPublicKey, nil
+	return allClaims, nil
 }
@@ -71,10 +71,13 @@ func (a Authorizer) SecurityAuth(token string, scopes []string) (*user.CLAUser,
 	}
 	f["claims"] = fmt.Sprintf("%+v", claims)
 
+	// the claim names are configured per issuer
+	mapping := a.authValidator.claimMapping(claims)
+
 	// Get the username from the token claims
-	usernameClaim, ok := claims[a.authValidator.usernameClaim]
+	usernameClaim, ok := claims[mapping.UsernameClaim]
 	if !ok {
-		log.WithFields(f).Warnf("username not found in claims with key: %s", a.authValidator.usernameClaim)
+		log.WithFields(f).Warnf("username not found in claims with key: %s", mapping.UsernameClaim)
 		return nil, errors.New("username not found")
 	}
 
@@ -83,11 +86,13 @@ func (a Authorizer) SecurityAuth(token string, scopes []string) (*user.CLAUser,
 		log.WithFields(f).Warnf("invalid username: %+v", usernameClaim)
 		return nil, errors.New("invalid username")
 	}
+	// the user names of the other issuers can't be taken for the LF user names
+	username = a.authValidator.lfUsername(claims, username)
 	f["username"] = username
 
-	nameClaim, ok := claims[a.authValidator.nameClaim]
+	nameClaim, ok := claims[mapping.NameClaim]
 	if !ok {
-		log.WithFields(f).Warnf("name not found: %+v", a.authValidator.nameClaim)
+		log.WithFields(f).Warnf("name not found: %+v", mapping.NameClaim)
 		return nil, errors.New("name not found")
 	}
 	f["nameClaim"] = nameClaim
@@ -99,9 +104,9 @@ func (a Authorizer) SecurityAuth(token string, scopes []string) (*user.CLAUser,
 	}
 	f["name"] = name
 
-	emailClaim, ok := claims[a.authValidator.emailClaim]
+	emailClaim, ok := claims[mapping.EmailClaim]
 	if !ok {
-		log.WithFields(f).Warnf("email not found: %+v", a.authValidator.emailClaim)
+		log.WithFields(f).Warnf("email not found: %+v", mapping.EmailClaim)
 		return nil, errors.New("email not found")
 	}
 	email, ok := emailClaim.(string)
@@ -110,6 +115,11 @@ func (a Authorizer) SecurityAuth(token string, scopes []string) (*user.CLAUser,
 		return nil, errors.New("invalid email")
 	}
 	f["email"] = email
+	// the email is used to look up and create the user, an unverified email could be the email of another user
+	if !a.authValidator.emailVerified(claims) {
+		log.WithFields(f).Warnf("email not verified: %+v", mapping.EmailVerifiedClaim)
+		return nil, ErrEmailNotVerified
+	}
 
 	// Get User by LFID
 	log.WithFields(f).Debugf("loading user and profiles by LFID: %s", username)
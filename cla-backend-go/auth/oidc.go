@@ -0,0 +1,471 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/sirupsen/logrus"
+
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+)
+
+const (
+	// keysCacheTTL is how long the fetched signing keys of an issuer are used before they are fetched again
+	keysCacheTTL = time.Hour
+	// keysRefreshInterval limits how often the signing keys are fetched again for a token signed with an unknown key
+	keysRefreshInterval = time.Minute
+	// httpTimeout is the timeout of the discovery document and the signing keys requests
+	httpTimeout = 10 * time.Second
+)
+
+// the signing algorithms a trusted issuer can be configured with - the symmetric and the none algorithms are never allowed
+var supportedAlgorithms = map[string]bool{
+	"RS256": true, "RS384": true, "RS512": true,
+	"PS256": true, "PS384": true, "PS512": true,
+	"ES256": true, "ES384": true, "ES512": true,
+}
+
+var (
+	// ErrUntrustedIssuer is returned when the issuer of the token is not one of the trusted issuers
+	ErrUntrustedIssuer = errors.New("untrusted issuer")
+	// ErrInvalidAudience is returned when the token was not issued for one of the audiences of the issuer
+	ErrInvalidAudience = errors.New("invalid audience")
+	// ErrKeyNotFound is returned when the token is not signed with one of the signing keys of the issuer
+	ErrKeyNotFound = errors.New("unable to find appropriate key")
+	// ErrEmailNotVerified is returned when the issuer has not verified the email of the token
+	ErrEmailNotVerified = errors.New("email not verified")
+)
+
+// ClaimMapping names the token claims holding the user name, the name and the email of the user and whether the
+// issuer has verified the email
+type ClaimMapping struct {
+	UsernameClaim      string
+	NameClaim          string
+	EmailClaim         string
+	EmailVerifiedClaim string
+}
+
+// IssuerConfig is the configuration of a trusted OIDC issuer
+type IssuerConfig struct {
+	// Issuer is the iss claim of the tokens, e.g. https://sso.example.org/
+	Issuer string
+	// Audiences are the accepted aud claim values, at least one is required unless SkipAudienceCheck is set
+	Audiences []string
+	// SkipAudienceCheck accepts the tokens of any audience, only set for the auth0 tenant of EasyCLA
+	SkipAudienceCheck bool
+	// Algorithms are the accepted signing algorithms, RS256 when empty
+	Algorithms []string
+	// DiscoveryURL is the OpenID configuration document of the issuer, the /.well-known/openid-configuration of the issuer when empty
+	DiscoveryURL string
+	// JWKSURL is the signing keys URL - when set, the discovery document isn't fetched
+	JWKSURL string
+	// ClaimMapping names the user claims, the preferred_username, the name, the email and the email_verified claims when empty
+	ClaimMapping
+	// UsernameNamespace prefixes the user names of the issuer, so they can't be taken for the LF user names or the user
+	// names of another issuer - the issuer without its scheme when empty
+	UsernameNamespace string
+	// LFIdentityProvider marks the issuer of the LF user names - its user names aren't prefixed and its emails are
+	// verified by the LF, only set for the auth0 tenant of EasyCLA
+	LFIdentityProvider bool
+}
+
+// trustedIssuer is a validated issuer configuration with its signing keys
+type trustedIssuer struct {
+	config     IssuerConfig
+	algorithms map[string]bool
+	keys       *keySet
+}
+
+// NewOIDCValidator creates a new validator of the tokens of the specified issuers. The signing keys of the issuers are
+// fetched on the first token and cached - they are fetched again after an hour or when a token is signed with an
+// unknown key, which is the case once the issuer rotates its keys.
+func NewOIDCValidator(issuers []IssuerConfig, httpClient *http.Client) (Validator, error) {
+	if len(issuers) == 0 {
+		return Validator{}, errors.New("missing Issuers")
+	}
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: httpTimeout}
+	}
+
+	validator := Validator{}
+	seen := map[string]bool{}
+	namespaces := map[string]bool{}
+	for _, config := range issuers {
+		if config.Issuer == "" {
+			return Validator{}, errors.New("missing Issuer")
+		}
+		issuer := normalizeIssuer(config.Issuer)
+		if seen[issuer] {
+			return Validator{}, fmt.Errorf("duplicate issuer: %s", config.Issuer)
+		}
+		seen[issuer] = true
+
+		if len(config.Audiences) == 0 && !config.SkipAudienceCheck {
+			return Validator{}, fmt.Errorf("missing Audiences for the issuer: %s", config.Issuer)
+		}
+		if len(config.Algorithms) == 0 {
+			config.Algorithms = []string{"RS256"}
+		}
+		algorithms := map[string]bool{}
+		for _, algorithm := range config.Algorithms {
+			if !supportedAlgorithms[algorithm] {
+				return Validator{}, fmt.Errorf("unsupported algorithm: %s for the issuer: %s", algorithm, config.Issuer)
+			}
+			algorithms[algorithm] = true
+		}
+
+		if config.DiscoveryURL == "" {
+			config.DiscoveryURL = issuer + "/.well-known/openid-configuration"
+		}
+		if config.UsernameClaim == "" {
+			config.UsernameClaim = "preferred_username"
+		}
+		if config.NameClaim == "" {
+			config.NameClaim = "name"
+		}
+		if config.EmailClaim == "" {
+			config.EmailClaim = "email"
+		}
+		if config.EmailVerifiedClaim == "" {
+			config.EmailVerifiedClaim = "email_verified"
+		}
+		if config.UsernameNamespace == "" {
+			config.UsernameNamespace = issuer
+			if i := strings.Index(issuer, "://"); i >= 0 {
+				config.UsernameNamespace = issuer[i+len("://"):]
+			}
+		}
+		if !config.LFIdentityProvider {
+			if namespaces[config.UsernameNamespace] {
+				return Validator{}, fmt.Errorf("duplicate username namespace: %s for the issuer: %s", config.UsernameNamespace, config.Issuer)
+			}
+			namespaces[config.UsernameNamespace] = true
+		}
+
+		validator.issuers = append(validator.issuers, &trustedIssuer{
+			config:     config,
+			algorithms: algorithms,
+			keys: &keySet{
+				client:       httpClient,
+				issuer:       issuer,
+				discoveryURL: config.DiscoveryURL,
+				jwksURL:      config.JWKSURL,
+			},
+		})
+	}
+
+	return validator, nil
+}
+
+// normalizeIssuer drops the trailing slash of the issuer, the issuers are compared without it
+func normalizeIssuer(issuer string) string {
+	return strings.TrimSuffix(issuer, "/")
+}
+
+// allowsAudience returns true if the aud claim - a string or an array - has one of the audiences of the issuer
+func (ti *trustedIssuer) allowsAudience(aud interface{}) bool {
+	if ti.config.SkipAudienceCheck {
+		return true
+	}
+	var tokenAudiences []string
+	switch value := aud.(type) {
+	case string:
+		tokenAudiences = []string{value}
+	case []interface{}:
+		for _, item := range value {
+			if audience, ok := item.(string); ok {
+				tokenAudiences = append(tokenAudiences, audience)
+			}
+		}
+	}
+	for _, tokenAudience := range tokenAudiences {
+		for _, audience := range ti.config.Audiences {
+			if tokenAudience == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// keySet holds the cached signing keys of an issuer
+type keySet struct {
+	client       *http.Client
+	issuer       string
+	discoveryURL string
+
+	lock            sync.Mutex
+	jwksURL         string
+	keys            map[string]interface{}
+	fetched         time.Time
+	lastMissRefresh time.Time
+}
+
+// key returns the signing key with the specified key ID
+func (ks *keySet) key(kid string) (interface{}, error) {
+	f := logrus.Fields{
+		"functionName": "auth.oidc.key",
+		"issuer":       ks.issuer,
+		"kid":          kid,
+	}
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	refreshed := false
+	if ks.keys == nil || time.Since(ks.fetched) > keysCacheTTL {
+		if err := ks.refresh(); err != nil {
+			if ks.keys == nil {
+				return nil, err
+			}
+			log.WithFields(f).WithError(err).Warn("unable to refresh the signing keys - using the cached keys")
+		} else {
+			refreshed = true
+		}
+	}
+	if key, ok := ks.lookup(kid); ok {
+		return key, nil
+	}
+
+	// the issuer may have rotated its keys since they were fetched
+	if refreshed || time.Since(ks.lastMissRefresh) < keysRefreshInterval {
+		return nil, ErrKeyNotFound
+	}
+	ks.lastMissRefresh = time.Now()
+	log.WithFields(f).Debug("unknown key - refreshing the signing keys")
+	if err := ks.refresh(); err != nil {
+		return nil, err
+	}
+	if key, ok := ks.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, ErrKeyNotFound
+}
+
+// lookup returns the cached key - a token without a key ID is accepted when the issuer has a single key
+func (ks *keySet) lookup(kid string) (interface{}, bool) {
+	if kid == "" && len(ks.keys) == 1 {
+		for _, key := range ks.keys {
+			return key, true
+		}
+	}
+	key, ok := ks.keys[kid]
+	return key, ok
+}
+
+type discoveryDocument struct {
+	Issuer  string `json:"issuer"`
+	JWKSURI string `json:"jwks_uri"`
+}
+
+type jwks struct {
+	Keys []jsonWebKeys `json:"keys"`
+}
+
+type jsonWebKeys struct {
+	Kty string   `json:"kty"`
+	Kid string   `json:"kid"`
+	Use string   `json:"use"`
+	N   string   `json:"n"`
+	E   string   `json:"e"`
+	Crv string   `json:"crv"`
+	X   string   `json:"x"`
+	Y   string   `json:"y"`
+	X5c []string `json:"x5c"`
+}
+
+// refresh fetches the signing keys, the signing keys URL is read from the discovery document when not configured
+func (ks *keySet) refresh() error {
+	f := logrus.Fields{
+		"functionName": "auth.oidc.refresh",
+		"issuer":       ks.issuer,
+	}
+	if ks.jwksURL == "" {
+		var document discoveryDocument
+		if err := ks.getJSON(ks.discoveryURL, &document); err != nil {
+			return err
+		}
+		if normalizeIssuer(document.Issuer) != ks.issuer {
+			return fmt.Errorf("discovery document issuer: %s does not match the issuer: %s", document.Issuer, ks.issuer)
+		}
+		if document.JWKSURI == "" {
+			return errors.New("discovery document is missing the jwks_uri")
+		}
+		ks.jwksURL = document.JWKSURI
+	}
+
+	var j jwks
+	if err := ks.getJSON(ks.jwksURL, &j); err != nil {
+		return err
+	}
+	keys := map[string]interface{}{}
+	for _, jwk := range j.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			log.WithFields(f).WithError(err).Warnf("skipping the signing key: %s", jwk.Kid)
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("no signing keys found at: %s", ks.jwksURL)
+	}
+
+	ks.keys = keys
+	ks.fetched = time.Now()
+	log.WithFields(f).Debugf("loaded %d signing keys from: %s", len(keys), ks.jwksURL)
+	return nil
+}
+
+func (ks *keySet) getJSON(url string, out interface{}) error {
+	resp, err := ks.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %d fetching: %s", resp.StatusCode, url)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// publicKey returns the RSA or the EC public key of the JSON web key
+func (jwk jsonWebKeys) publicKey() (interface{}, error) {
+	switch {
+	case jwk.Kty == "RSA" && jwk.N != "" && jwk.E != "":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case jwk.Kty == "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve: %s", jwk.Crv)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("invalid EC key")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case len(jwk.X5c) > 0:
+		der, err := base64.StdEncoding.DecodeString(jwk.X5c[0])
+		if err != nil {
+			return nil, err
+		}
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, err
+		}
+		return cert.PublicKey, nil
+	}
+	return nil, fmt.Errorf("unsupported key type: %s", jwk.Kty)
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+// getKey returns the signing key of the token - the issuer of the token must be trusted and the token signed with one
+// of the algorithms of the issuer
+func (av Validator) getKey(token *jwt.Token) (interface{}, error) {
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errors.New("unable to map claims")
+	}
+	issuer := av.issuerOf(claims)
+	if issuer == nil {
+		return nil, ErrUntrustedIssuer
+	}
+	if !issuer.algorithms[token.Method.Alg()] {
+		return nil, fmt.Errorf("unexpected signing algorithm: %s", token.Method.Alg())
+	}
+	kid, _ := token.Header["kid"].(string)
+	return issuer.keys.key(kid)
+}
+
+// issuerOf returns the trusted issuer of the iss claim, nil when the issuer isn't trusted
+func (av Validator) issuerOf(claims map[string]interface{}) *trustedIssuer {
+	iss, ok := claims["iss"].(string)
+	if !ok {
+		return nil
+	}
+	iss = normalizeIssuer(iss)
+	for _, issuer := range av.issuers {
+		if issuer.keys.issuer == iss {
+			return issuer
+		}
+	}
+	return nil
+}
+
+// claimMapping returns the claim mapping of the issuer of the verified claims
+func (av Validator) claimMapping(claims map[string]interface{}) ClaimMapping {
+	if issuer := av.issuerOf(claims); issuer != nil {
+		return issuer.config.ClaimMapping
+	}
+	return ClaimMapping{}
+}
+
+// lfUsername returns the user name of the verified claims as it is looked up and stored - the user names of the
+// issuers other than the LF identity provider are prefixed with the namespace of their issuer
+func (av Validator) lfUsername(claims map[string]interface{}, username string) string {
+	issuer := av.issuerOf(claims)
+	if issuer == nil || issuer.config.LFIdentityProvider {
+		return username
+	}
+	return issuer.config.UsernameNamespace + "/" + username
+}
+
+// emailVerified returns true if the issuer of the verified claims has verified the email - the issuers other than
+// the LF identity provider must set the email_verified claim to true
+func (av Validator) emailVerified(claims map[string]interface{}) bool {
+	issuer := av.issuerOf(claims)
+	if issuer == nil {
+		return false
+	}
+	if issuer.config.LFIdentityProvider {
+		return true
+	}
+	verified, ok := claims[issuer.config.EmailVerifiedClaim].(bool)
+	return ok && verified
+}
@@ -0,0 +1,133 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+// Package oidctest provides a stand-in OIDC identity provider signing the test tokens, in the spirit of httptest.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Provider is a local identity provider serving the OpenID configuration document and the signing keys
+type Provider struct {
+	// Issuer is the issuer of the tokens, the URL of the provider
+	Issuer string
+
+	server        *httptest.Server
+	keyRequests   int64
+	lock          sync.RWMutex
+	keys          []*signingKey
+	nextKeyNumber int
+}
+
+type signingKey struct {
+	kid        string
+	privateKey *rsa.PrivateKey
+}
+
+// NewProvider starts a new provider with a single RS256 signing key - Close stops it
+func NewProvider() (*Provider, error) {
+	p := &Provider{}
+	if err := p.RotateKey(); err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.serveDiscovery)
+	mux.HandleFunc("/jwks", p.serveKeys)
+	p.server = httptest.NewServer(mux)
+	p.Issuer = p.server.URL + "/"
+	return p, nil
+}
+
+// Close stops the provider
+func (p *Provider) Close() {
+	p.server.Close()
+}
+
+// RotateKey makes a new key the signing key, the previous key is still published like an issuer rotating its keys does
+func (p *Provider) RotateKey() error {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return err
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.nextKeyNumber++
+	key := &signingKey{kid: fmt.Sprintf("key-%d", p.nextKeyNumber), privateKey: privateKey}
+	p.keys = append([]*signingKey{key}, p.keys...)
+	if len(p.keys) > 2 {
+		p.keys = p.keys[:2]
+	}
+	return nil
+}
+
+// KeyRequests returns the number of the signing keys requests served
+func (p *Provider) KeyRequests() int {
+	return int(atomic.LoadInt64(&p.keyRequests))
+}
+
+// Sign returns a token of the claims signed with the current signing key, the iss, iat and exp claims default to the
+// issuer of the provider, now and in an hour
+func (p *Provider) Sign(claims map[string]interface{}) (string, error) {
+	mapClaims := jwt.MapClaims{
+		"iss": p.Issuer,
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for name, value := range claims {
+		mapClaims[name] = value
+	}
+
+	p.lock.RLock()
+	key := p.keys[0]
+	p.lock.RUnlock()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, mapClaims)
+	token.Header["kid"] = key.kid
+	return token.SignedString(key.privateKey)
+}
+
+func (p *Provider) serveDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]interface{}{
+		"issuer":                                p.Issuer,
+		"jwks_uri":                              p.server.URL + "/jwks",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (p *Provider) serveKeys(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt64(&p.keyRequests, 1)
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	var keys []map[string]string
+	for _, key := range p.keys {
+		keys = append(keys, map[string]string{
+			"kty": "RSA",
+			"kid": key.kid,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(key.privateKey.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.privateKey.E)).Bytes()),
+		})
+	}
+	writeJSON(w, map[string]interface{}{"keys": keys})
+}
+
+func writeJSON(w http.ResponseWriter, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
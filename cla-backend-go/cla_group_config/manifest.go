@@ -0,0 +1,116 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package cla_group_config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+)
+
+// ManifestVersion is the version of the manifest format
+const ManifestVersion = "easycla/v1"
+
+// manifest formats
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+// errors
+var (
+	ErrInvalidManifest  = errors.New("invalid cla group manifest")
+	ErrCLAGroupNotFound = errors.New("cla group does not exist")
+	ErrInvalidFormat    = fmt.Errorf("invalid manifest format - expecting %s or %s", FormatYAML, FormatJSON)
+)
+
+// ParseManifest decodes the YAML or JSON manifest and validates it
+func ParseManifest(data []byte) (*models.ClaGroupManifest, error) {
+	// YAML is a superset of JSON, the manifest is converted to JSON to be decoded with the model JSON tags
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+	}
+
+	var manifest models.ClaGroupManifest
+	if err := json.Unmarshal(jsonData, &manifest); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+	}
+	if err := ValidateManifest(&manifest); err != nil {
+		return nil, err
+	}
+
+	return &manifest, nil
+}
+
+// FormatManifest encodes the manifest in the YAML or JSON format
+func FormatManifest(manifest *models.ClaGroupManifest, format string) ([]byte, error) {
+	switch strings.ToLower(format) {
+	case "", FormatYAML:
+		return yaml.Marshal(manifest)
+	case FormatJSON:
+		return json.MarshalIndent(manifest, "", "  ")
+	default:
+		return nil, ErrInvalidFormat
+	}
+}
+
+// ValidateManifest checks the manifest identifies the CLA Group and has no duplicate resources
+func ValidateManifest(manifest *models.ClaGroupManifest) error {
+	if manifest == nil {
+		return fmt.Errorf("%w: empty manifest", ErrInvalidManifest)
+	}
+	if manifest.Version != ManifestVersion {
+		return fmt.Errorf("%w: unsupported version: '%s' - expecting %s", ErrInvalidManifest, manifest.Version, ManifestVersion)
+	}
+	if manifest.ClaGroup == nil || strings.TrimSpace(manifest.ClaGroup.Name) == "" {
+		return fmt.Errorf("%w: the cla_group name is required", ErrInvalidManifest)
+	}
+	if strings.TrimSpace(manifest.ClaGroup.FoundationSfid) == "" {
+		return fmt.Errorf("%w: the cla_group foundation_sfid is required", ErrInvalidManifest)
+	}
+
+	orgNames := map[string]bool{}
+	repositoryNames := map[string]bool{}
+	for _, org := range manifest.GithubOrganizations {
+		if org == nil || org.Name == "" {
+			return fmt.Errorf("%w: the github organization name is required", ErrInvalidManifest)
+		}
+		if orgNames[strings.ToLower(org.Name)] {
+			return fmt.Errorf("%w: duplicate github organization: %s", ErrInvalidManifest, org.Name)
+		}
+		orgNames[strings.ToLower(org.Name)] = true
+		for _, repository := range org.Repositories {
+			if repository == nil || repository.Name == "" {
+				return fmt.Errorf("%w: the repository name is required for the repositories of github organization: %s", ErrInvalidManifest, org.Name)
+			}
+			if !strings.HasPrefix(strings.ToLower(repository.Name), strings.ToLower(org.Name)+"/") {
+				return fmt.Errorf("%w: repository: %s is not a repository of github organization: %s - expecting the full name, e.g. %s/<repository>",
+					ErrInvalidManifest, repository.Name, org.Name, org.Name)
+			}
+			if repositoryNames[strings.ToLower(repository.Name)] {
+				return fmt.Errorf("%w: duplicate repository: %s", ErrInvalidManifest, repository.Name)
+			}
+			repositoryNames[strings.ToLower(repository.Name)] = true
+		}
+	}
+
+	gerritNames := map[string]bool{}
+	for _, gerrit := range manifest.Gerrits {
+		if gerrit == nil || gerrit.Name == "" || gerrit.URL == "" {
+			return fmt.Errorf("%w: the gerrit name and url are required", ErrInvalidManifest)
+		}
+		if gerritNames[strings.ToLower(gerrit.Name)] {
+			return fmt.Errorf("%w: duplicate gerrit: %s", ErrInvalidManifest, gerrit.Name)
+		}
+		gerritNames[strings.ToLower(gerrit.Name)] = true
+	}
+
+	return nil
+}
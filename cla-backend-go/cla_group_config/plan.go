@@ -0,0 +1,730 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package cla_group_config
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/jinzhu/copier"
+
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
+	"github.com/communitybridge/easycla/cla-backend-go/github/branch_protection"
+	"github.com/communitybridge/easycla/cla-backend-go/template"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/cla_groups"
+)
+
+// change resources
+const (
+	ResourceCLAGroup           = "cla-group"
+	ResourceProject            = "project"
+	ResourceTemplate           = "template"
+	ResourceGithubOrganization = "github-organization"
+	ResourceGithubRepository   = "github-repository"
+	ResourceBranchProtection   = "branch-protection"
+	ResourceGerrit             = "gerrit"
+)
+
+// change actions - the unmanaged resources are missing from a manifest which does not prune, they are kept
+const (
+	ActionCreate    = "create"
+	ActionUpdate    = "update"
+	ActionDelete    = "delete"
+	ActionUnmanaged = "unmanaged"
+)
+
+// change statuses
+const (
+	StatusPlanned = "planned"
+	StatusApplied = "applied"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// step is a planned change along with the function applying it, the reported only changes have no apply function
+type step struct {
+	change *models.ClaGroupConfigChange
+	apply  func(ctx context.Context) error
+}
+
+// repositoryRef identifies a repository enabled for the CLA Group
+type repositoryRef struct {
+	repositoryID string
+	projectSFID  string
+}
+
+// plan is the changes bringing the CLA Group in line with the manifest, the steps are applied in order
+type plan struct {
+	manifest *models.ClaGroupManifest
+	authUser *auth.User
+	// claGroup is nil until the CLA Group is created
+	claGroup *v1Models.ClaGroup
+	// repositories are the repositories enabled for the CLA Group keyed by the lower case name, completed as the
+	// repositories are added
+	repositories map[string]*repositoryRef
+	steps        []*step
+}
+
+// claGroupID returns the CLA Group ID, empty until the CLA Group is created
+func (p *plan) claGroupID() string {
+	if p.claGroup == nil {
+		return ""
+	}
+	return p.claGroup.ProjectID
+}
+
+// add appends a change to the plan
+func (p *plan) add(resource, name, action, details string, apply func(ctx context.Context) error) *step {
+	status := StatusPlanned
+	if apply == nil {
+		status = StatusSkipped
+	}
+	st := &step{
+		change: &models.ClaGroupConfigChange{
+			Resource: resource,
+			Name:     name,
+			Action:   action,
+			Details:  details,
+			Status:   status,
+		},
+		apply: apply,
+	}
+	p.steps = append(p.steps, st)
+	return st
+}
+
+// result returns the plan response model
+func (p *plan) result(applied bool) *models.ClaGroupConfigPlan {
+	out := &models.ClaGroupConfigPlan{
+		ClaGroupID:   p.claGroupID(),
+		ClaGroupName: p.manifest.ClaGroup.Name,
+		Applied:      applied,
+		Changes:      make([]*models.ClaGroupConfigChange, 0, len(p.steps)),
+	}
+	for _, st := range p.steps {
+		out.Changes = append(out.Changes, st.change)
+	}
+	return out
+}
+
+// plan diffs the manifest against the current state of the CLA Group, identified by its name
+func (s *service) plan(ctx context.Context, authUser *auth.User, manifest *models.ClaGroupManifest) (*plan, error) {
+	if err := ValidateManifest(manifest); err != nil {
+		return nil, err
+	}
+
+	claGroup, err := s.claGroupService.GetCLAGroupByName(ctx, manifest.ClaGroup.Name)
+	if err != nil {
+		return nil, err
+	}
+	if claGroup != nil && claGroup.FoundationSFID != manifest.ClaGroup.FoundationSfid {
+		return nil, fmt.Errorf("%w: cla group: %s belongs to foundation: %s, not to foundation: %s",
+			ErrInvalidManifest, claGroup.ProjectName, claGroup.FoundationSFID, manifest.ClaGroup.FoundationSfid)
+	}
+
+	p := &plan{
+		manifest:     manifest,
+		authUser:     authUser,
+		claGroup:     claGroup,
+		repositories: map[string]*repositoryRef{},
+	}
+
+	var projectSFIDs []string
+	if claGroup == nil {
+		s.planCLAGroupCreate(p)
+	} else {
+		projectSFIDs, err = s.enrolledProjects(ctx, claGroup.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		s.planCLAGroupUpdate(p, projectSFIDs)
+	}
+
+	if err := s.planGithubOrganizations(ctx, p, projectSFIDs); err != nil {
+		return nil, err
+	}
+	if err := s.planGerrits(ctx, p); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// planCLAGroupCreate plans the creation of the CLA Group along with its template and enrolled projects
+func (s *service) planCLAGroupCreate(p *plan) {
+	desired := p.manifest.ClaGroup
+	projectSFIDs := sortedUnique(desired.ProjectSfidList)
+	details := fmt.Sprintf("icla_enabled: %t, ccla_enabled: %t, ccla_requires_icla: %t, template_id: %s, projects: %s",
+		desired.IclaEnabled, desired.CclaEnabled, desired.CclaRequiresIcla, desiredTemplateID(desired.Template), formatList(projectSFIDs))
+
+	p.add(ResourceCLAGroup, desired.Name, ActionCreate, details, func(ctx context.Context) error {
+		v1TemplateFields, err := s.templateFields(ctx, desired.Template)
+		if err != nil {
+			return err
+		}
+		var templateFields models.CreateClaGroupTemplate
+		if err := copier.Copy(&templateFields, v1TemplateFields); err != nil {
+			return err
+		}
+
+		summary, err := s.claGroupSetupService.CreateCLAGroup(ctx, p.authUser, &models.CreateClaGroupInput{
+			ClaGroupName:        aws.String(desired.Name),
+			ClaGroupDescription: desired.Description,
+			FoundationSfid:      aws.String(desired.FoundationSfid),
+			IclaEnabled:         aws.Bool(desired.IclaEnabled),
+			CclaEnabled:         aws.Bool(desired.CclaEnabled),
+			CclaRequiresIcla:    aws.Bool(desired.CclaRequiresIcla),
+			ProjectSfidList:     projectSFIDs,
+			TemplateFields:      templateFields,
+		}, p.authUser.UserName)
+		if err != nil {
+			return err
+		}
+
+		claGroup, err := s.claGroupService.GetCLAGroupByID(ctx, summary.ClaGroupID)
+		if err != nil {
+			return err
+		}
+		if claGroup == nil {
+			return ErrCLAGroupNotFound
+		}
+		p.claGroup = claGroup
+		return nil
+	})
+}
+
+// planCLAGroupUpdate plans the changes of the CLA Group settings, template and enrolled projects
+func (s *service) planCLAGroupUpdate(p *plan, projectSFIDs []string) {
+	current := p.claGroup
+	desired := p.manifest.ClaGroup
+
+	var differences []string
+	if current.ProjectDescription != desired.Description {
+		differences = append(differences, fmt.Sprintf("description: '%s' -> '%s'", current.ProjectDescription, desired.Description))
+	}
+	if current.ProjectICLAEnabled != desired.IclaEnabled {
+		differences = append(differences, fmt.Sprintf("icla_enabled: %t -> %t", current.ProjectICLAEnabled, desired.IclaEnabled))
+	}
+	if current.ProjectCCLAEnabled != desired.CclaEnabled {
+		differences = append(differences, fmt.Sprintf("ccla_enabled: %t -> %t", current.ProjectCCLAEnabled, desired.CclaEnabled))
+	}
+	if current.ProjectCCLARequiresICLA != desired.CclaRequiresIcla {
+		differences = append(differences, fmt.Sprintf("ccla_requires_icla: %t -> %t", current.ProjectCCLARequiresICLA, desired.CclaRequiresIcla))
+	}
+	if len(differences) > 0 {
+		p.add(ResourceCLAGroup, desired.Name, ActionUpdate, strings.Join(differences, ", "), func(ctx context.Context) error {
+			updated := *p.claGroup
+			updated.ProjectDescription = desired.Description
+			updated.ProjectICLAEnabled = desired.IclaEnabled
+			updated.ProjectCCLAEnabled = desired.CclaEnabled
+			updated.ProjectCCLARequiresICLA = desired.CclaRequiresIcla
+			claGroup, err := s.claGroupService.UpdateCLAGroup(ctx, &updated)
+			if err != nil {
+				return err
+			}
+			if claGroup == nil {
+				claGroup = &updated
+			}
+			p.claGroup = claGroup
+			return nil
+		})
+	}
+
+	// the template is compared by ID, the field values are not stored once the documents are generated
+	if desired.Template != nil && desired.Template.TemplateID != "" && desired.Template.TemplateID != current.ProjectTemplateID {
+		details := fmt.Sprintf("template_id: %s -> %s, the CLA documents are regenerated", current.ProjectTemplateID, desired.Template.TemplateID)
+		p.add(ResourceTemplate, desired.Template.TemplateID, ActionUpdate, details, func(ctx context.Context) error {
+			templateFields, err := s.templateFields(ctx, desired.Template)
+			if err != nil {
+				return err
+			}
+			_, err = s.templateService.CreateCLAGroupTemplate(ctx, p.claGroupID(), templateFields)
+			return err
+		})
+	}
+
+	enrolled := map[string]bool{}
+	for _, projectSFID := range projectSFIDs {
+		enrolled[projectSFID] = true
+	}
+	wanted := map[string]bool{}
+	for _, projectSFID := range sortedUnique(desired.ProjectSfidList) {
+		wanted[projectSFID] = true
+		if enrolled[projectSFID] {
+			continue
+		}
+		projectSFID := projectSFID
+		p.add(ResourceProject, projectSFID, ActionCreate, "enroll the project in the CLA Group", func(ctx context.Context) error {
+			return s.claGroupSetupService.EnrollProjectsInClaGroup(ctx, &cla_groups.EnrollProjectsModel{
+				AuthUser:        p.authUser,
+				CLAGroupID:      p.claGroupID(),
+				FoundationSFID:  desired.FoundationSfid,
+				ProjectSFIDList: []string{projectSFID},
+			})
+		})
+	}
+	for _, projectSFID := range projectSFIDs {
+		if wanted[projectSFID] {
+			continue
+		}
+		if !p.manifest.Prune {
+			p.add(ResourceProject, projectSFID, ActionUnmanaged, "enrolled in the CLA Group but missing from the manifest", nil)
+			continue
+		}
+		projectSFID := projectSFID
+		p.add(ResourceProject, projectSFID, ActionDelete, "unenroll the project from the CLA Group", func(ctx context.Context) error {
+			return s.claGroupSetupService.UnenrollProjectsInClaGroup(ctx, &cla_groups.UnenrollProjectsModel{
+				AuthUser:        p.authUser,
+				CLAGroupID:      p.claGroupID(),
+				FoundationSFID:  desired.FoundationSfid,
+				ProjectSFIDList: []string{projectSFID},
+			})
+		})
+	}
+}
+
+// desiredTemplateID returns the template ID of the manifest, the Apache Style template when not set
+func desiredTemplateID(manifestTemplate *models.ClaGroupManifestTemplate) string {
+	if manifestTemplate == nil || manifestTemplate.TemplateID == "" {
+		return template.ApacheStyleTemplateID
+	}
+	return manifestTemplate.TemplateID
+}
+
+// templateFields builds the template input from the manifest field values keyed by the template field names
+func (s *service) templateFields(ctx context.Context, manifestTemplate *models.ClaGroupManifestTemplate) (*v1Models.CreateClaGroupTemplate, error) {
+	templateID := desiredTemplateID(manifestTemplate)
+	templates, err := s.templateService.GetTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var definition *v1Models.Template
+	for i := range templates {
+		if templates[i].ID == templateID {
+			definition = &templates[i]
+			break
+		}
+	}
+	if definition == nil {
+		return nil, fmt.Errorf("template: %s does not exist", templateID)
+	}
+
+	var values map[string]string
+	if manifestTemplate != nil {
+		values = manifestTemplate.Fields
+	}
+	out := &v1Models.CreateClaGroupTemplate{
+		TemplateID: templateID,
+	}
+	for _, metaField := range definition.MetaFields {
+		value := strings.TrimSpace(values[metaField.Name])
+		if value == "" {
+			return nil, fmt.Errorf("the value of field: %s of template: %s is required", metaField.Name, definition.Name)
+		}
+		out.MetaFields = append(out.MetaFields, &v1Models.MetaField{
+			Name:             metaField.Name,
+			Description:      metaField.Description,
+			TemplateVariable: metaField.TemplateVariable,
+			Value:            value,
+		})
+	}
+	return out, nil
+}
+
+// planGithubOrganizations plans the changes of the GitHub organizations, their repositories and branch protection
+func (s *service) planGithubOrganizations(ctx context.Context, p *plan, projectSFIDs []string) error {
+	claGroupID := p.claGroupID()
+
+	// the organizations are looked up under the foundation, the enrolled projects and the projects of the manifest
+	lookup := append([]string{p.manifest.ClaGroup.FoundationSfid}, projectSFIDs...)
+	lookup = append(lookup, p.manifest.ClaGroup.ProjectSfidList...)
+	for _, desiredOrg := range p.manifest.GithubOrganizations {
+		lookup = append(lookup, desiredOrg.ProjectSfid)
+	}
+	current, err := s.githubOrganizations(ctx, lookup)
+	if err != nil {
+		return err
+	}
+
+	wanted := map[string]bool{}
+	for _, desiredOrg := range p.manifest.GithubOrganizations {
+		key := strings.ToLower(desiredOrg.Name)
+		wanted[key] = true
+
+		var existing *models.ProjectGithubOrganization
+		projectSFID := desiredOrg.ProjectSfid
+		if currentOrg, ok := current[key]; ok {
+			existing = currentOrg.org
+			if projectSFID == "" {
+				projectSFID = currentOrg.projectSFID
+			}
+		}
+		if projectSFID == "" {
+			projectSFID = p.manifest.ClaGroup.FoundationSfid
+		}
+		s.planGithubOrganization(ctx, p, projectSFID, desiredOrg, existing)
+	}
+
+	// the organizations of the CLA Group missing from the manifest
+	for _, key := range sortedOrganizationKeys(current) {
+		currentOrg := current[key]
+		if wanted[key] || !isCLAGroupOrganization(currentOrg.org, claGroupID) {
+			continue
+		}
+		for _, repository := range claGroupRepositories(currentOrg.org, claGroupID) {
+			s.planRepositoryRemoval(p, repository)
+		}
+
+		orgName := currentOrg.org.GithubOrganizationName
+		projectSFID := currentOrg.projectSFID
+		switch {
+		case !p.manifest.Prune:
+			p.add(ResourceGithubOrganization, orgName, ActionUnmanaged, "configured for the CLA Group but missing from the manifest", nil)
+		case hasOtherCLAGroupRepositories(currentOrg.org, claGroupID):
+			if !currentOrg.org.AutoEnabled || currentOrg.org.AutoEnableCLAGroupID != claGroupID {
+				p.add(ResourceGithubOrganization, orgName, ActionUnmanaged, "the organization has repositories of other CLA Groups", nil)
+				continue
+			}
+			branchProtectionEnabled := currentOrg.org.BranchProtectionEnabled
+			p.add(ResourceGithubOrganization, orgName, ActionUpdate, "auto_enabled: true -> false, the organization has repositories of other CLA Groups",
+				func(ctx context.Context) error {
+					return s.githubOrgService.UpdateGithubOrganization(ctx, projectSFID, orgName, false, "", branchProtectionEnabled, nil)
+				})
+		default:
+			p.add(ResourceGithubOrganization, orgName, ActionDelete, fmt.Sprintf("remove the organization from project: %s", projectSFID),
+				func(ctx context.Context) error {
+					return s.githubOrgService.DeleteGithubOrganization(ctx, projectSFID, orgName)
+				})
+		}
+	}
+
+	return nil
+}
+
+// planGithubOrganization plans the changes of the GitHub organization, the existing organization is nil when the
+// organization is yet to be added
+func (s *service) planGithubOrganization(ctx context.Context, p *plan, projectSFID string, desiredOrg *models.ClaGroupManifestGithubOrganization, existing *models.ProjectGithubOrganization) {
+	claGroupID := p.claGroupID()
+	orgName := desiredOrg.Name
+
+	if existing == nil {
+		details := fmt.Sprintf("add the organization to project: %s with auto_enabled: %t, branch_protection_enabled: %t",
+			projectSFID, desiredOrg.AutoEnabled, desiredOrg.BranchProtectionEnabled)
+		p.add(ResourceGithubOrganization, orgName, ActionCreate, details, func(ctx context.Context) error {
+			input := &models.CreateGithubOrganization{
+				OrganizationName:        aws.String(orgName),
+				AutoEnabled:             aws.Bool(desiredOrg.AutoEnabled),
+				BranchProtectionEnabled: aws.Bool(desiredOrg.BranchProtectionEnabled),
+			}
+			if desiredOrg.AutoEnabled {
+				input.AutoEnabledClaGroupID = p.claGroupID()
+			}
+			_, err := s.githubOrgService.AddGithubOrganization(ctx, projectSFID, input)
+			return err
+		})
+	} else {
+		orgName = existing.GithubOrganizationName
+		// an organization auto-enabled for another CLA Group is not auto-enabled for this one
+		currentAutoEnabled := claGroupID != "" && existing.AutoEnabled && existing.AutoEnableCLAGroupID == claGroupID
+		var differences []string
+		if currentAutoEnabled != desiredOrg.AutoEnabled {
+			differences = append(differences, fmt.Sprintf("auto_enabled: %t -> %t", currentAutoEnabled, desiredOrg.AutoEnabled))
+		}
+		if existing.BranchProtectionEnabled != desiredOrg.BranchProtectionEnabled {
+			differences = append(differences, fmt.Sprintf("branch_protection_enabled: %t -> %t", existing.BranchProtectionEnabled, desiredOrg.BranchProtectionEnabled))
+		}
+		if len(differences) > 0 {
+			p.add(ResourceGithubOrganization, orgName, ActionUpdate, strings.Join(differences, ", "), func(ctx context.Context) error {
+				autoEnabledClaGroupID := ""
+				if desiredOrg.AutoEnabled {
+					autoEnabledClaGroupID = p.claGroupID()
+				}
+				return s.githubOrgService.UpdateGithubOrganization(ctx, projectSFID, orgName, desiredOrg.AutoEnabled, autoEnabledClaGroupID, desiredOrg.BranchProtectionEnabled, nil)
+			})
+		}
+	}
+
+	currentRepositories := map[string]*models.ProjectGithubRepository{}
+	if existing != nil {
+		for _, repository := range existing.Repositories {
+			currentRepositories[strings.ToLower(repository.RepositoryName)] = repository
+		}
+	}
+
+	wanted := map[string]bool{}
+	for _, desiredRepository := range desiredOrg.Repositories {
+		key := strings.ToLower(desiredRepository.Name)
+		wanted[key] = true
+		currentRepository := currentRepositories[key]
+
+		configured := claGroupID != "" && currentRepository != nil && currentRepository.Enabled && currentRepository.ClaGroupID == claGroupID
+		if configured {
+			p.repositories[key] = &repositoryRef{
+				repositoryID: currentRepository.RepositoryID,
+				projectSFID:  currentRepository.ProjectID,
+			}
+		} else {
+			s.planRepositoryCreate(p, projectSFID, orgName, desiredRepository, currentRepository)
+		}
+
+		if desiredRepository.BranchProtection != nil {
+			s.planBranchProtection(ctx, p, desiredRepository, configured)
+		}
+	}
+
+	for _, repository := range claGroupRepositories(existing, claGroupID) {
+		if !wanted[strings.ToLower(repository.RepositoryName)] {
+			s.planRepositoryRemoval(p, repository)
+		}
+	}
+}
+
+// planRepositoryCreate plans enabling the repository for the CLA Group, the current repository is nil when the
+// repository is not visible to the GitHub app
+func (s *service) planRepositoryCreate(p *plan, projectSFID, orgName string, desiredRepository *models.ClaGroupManifestGithubRepository, currentRepository *models.ProjectGithubRepository) {
+	if currentRepository != nil && currentRepository.Enabled && currentRepository.ClaGroupID != "" && currentRepository.ClaGroupID != p.claGroupID() {
+		st := p.add(ResourceGithubRepository, desiredRepository.Name, ActionUpdate, "", nil)
+		st.change.Error = fmt.Sprintf("the repository is enabled for CLA Group: %s - disable it first", currentRepository.ClaGroupID)
+		return
+	}
+
+	githubID := desiredRepository.GithubID
+	if githubID == "" && currentRepository != nil && currentRepository.RepositoryGithubID != 0 {
+		githubID = strconv.FormatInt(currentRepository.RepositoryGithubID, 10)
+	}
+	if githubID == "" {
+		st := p.add(ResourceGithubRepository, desiredRepository.Name, ActionCreate, "", nil)
+		st.change.Error = "the github_id is required, the repository is not visible to the EasyCLA GitHub app"
+		return
+	}
+
+	key := strings.ToLower(desiredRepository.Name)
+	details := fmt.Sprintf("enable the repository for the CLA Group in project: %s", projectSFID)
+	p.add(ResourceGithubRepository, desiredRepository.Name, ActionCreate, details, func(ctx context.Context) error {
+		added, err := s.repositoryService.AddGithubRepositories(ctx, projectSFID, &models.GithubRepositoryInput{
+			ClaGroupID:             aws.String(p.claGroupID()),
+			GithubOrganizationName: aws.String(orgName),
+			RepositoryGithubIds:    []string{githubID},
+		})
+		if err != nil {
+			return err
+		}
+		for _, repository := range added {
+			p.repositories[strings.ToLower(repository.RepositoryName)] = &repositoryRef{
+				repositoryID: repository.RepositoryID,
+				projectSFID:  repository.ProjectSFID,
+			}
+		}
+		if _, ok := p.repositories[key]; !ok {
+			return fmt.Errorf("github id: %s is not the id of repository: %s", githubID, desiredRepository.Name)
+		}
+		return nil
+	})
+}
+
+// planRepositoryRemoval plans disabling the repository missing from the manifest
+func (s *service) planRepositoryRemoval(p *plan, repository *models.ProjectGithubRepository) {
+	if !p.manifest.Prune {
+		p.add(ResourceGithubRepository, repository.RepositoryName, ActionUnmanaged, "enabled for the CLA Group but missing from the manifest", nil)
+		return
+	}
+	repositoryID := repository.RepositoryID
+	p.add(ResourceGithubRepository, repository.RepositoryName, ActionDelete, "disable the repository", func(ctx context.Context) error {
+		return s.repositoryService.DisableRepository(ctx, repositoryID)
+	})
+}
+
+// planBranchProtection plans the branch protection of the repository, the current protection is read when the
+// repository is already enabled for the CLA Group
+func (s *service) planBranchProtection(ctx context.Context, p *plan, desiredRepository *models.ClaGroupManifestGithubRepository, configured bool) {
+	desired := desiredRepository.BranchProtection
+	branchName := desired.BranchName
+	if branchName == "" {
+		branchName = branch_protection.DefaultBranchName
+	}
+	name := fmt.Sprintf("%s:%s", desiredRepository.Name, branchName)
+	key := strings.ToLower(desiredRepository.Name)
+	desiredChecks := sortedUnique(desired.StatusChecks)
+
+	action := ActionCreate
+	details := fmt.Sprintf("enforce_admin: %t, status_checks: %s", desired.EnforceAdmin, formatList(desiredChecks))
+	// the checks the input enables or disables, the required checks not in the manifest are disabled
+	checks := append([]string{}, desiredChecks...)
+	if configured {
+		ref := p.repositories[key]
+		current, err := s.repositoryService.GetProtectedBranch(ctx, ref.projectSFID, ref.repositoryID, branchName)
+		if err != nil {
+			st := p.add(ResourceBranchProtection, name, ActionUpdate, details, nil)
+			st.change.Error = fmt.Sprintf("unable to load the current branch protection: %v", err)
+			return
+		}
+		currentChecks := enabledStatusChecks(current)
+		if current.ProtectionEnabled && current.EnforceAdmin == desired.EnforceAdmin && strings.Join(currentChecks, ",") == strings.Join(desiredChecks, ",") {
+			return
+		}
+		if current.ProtectionEnabled {
+			action = ActionUpdate
+			details = fmt.Sprintf("enforce_admin: %t -> %t, status_checks: %s -> %s",
+				current.EnforceAdmin, desired.EnforceAdmin, formatList(currentChecks), formatList(desiredChecks))
+		}
+		for _, check := range current.StatusChecks {
+			if check != nil {
+				checks = append(checks, aws.StringValue(check.Name))
+			}
+		}
+		checks = sortedUnique(checks)
+	}
+
+	p.add(ResourceBranchProtection, name, action, details, func(ctx context.Context) error {
+		ref, ok := p.repositories[key]
+		if !ok {
+			return fmt.Errorf("repository: %s is not enabled for the CLA Group", desiredRepository.Name)
+		}
+		input := &models.GithubRepositoryBranchProtectionInput{
+			BranchName:   branchName,
+			EnforceAdmin: aws.Bool(desired.EnforceAdmin),
+		}
+		for _, check := range checks {
+			index := sort.SearchStrings(desiredChecks, check)
+			enabled := index < len(desiredChecks) && desiredChecks[index] == check
+			input.StatusChecks = append(input.StatusChecks, &models.GithubRepositoryBranchProtectionStatusChecks{
+				Name:    aws.String(check),
+				Enabled: aws.Bool(enabled),
+			})
+		}
+		_, err := s.repositoryService.UpdateProtectedBranch(ctx, ref.projectSFID, ref.repositoryID, input)
+		return err
+	})
+}
+
+// planGerrits plans the changes of the gerrit instances - the instances can't be updated, a changed instance is replaced
+func (s *service) planGerrits(ctx context.Context, p *plan) error {
+	current := map[string]*v1Models.Gerrit{}
+	var currentGerrits []*v1Models.Gerrit
+	if claGroupID := p.claGroupID(); claGroupID != "" {
+		gerritList, err := s.gerritRepo.GetClaGroupGerrits(ctx, claGroupID)
+		if err != nil {
+			return err
+		}
+		currentGerrits = sortedGerrits(gerritList)
+		for _, gerritModel := range currentGerrits {
+			current[strings.ToLower(gerritModel.GerritName)] = gerritModel
+		}
+	}
+
+	wanted := map[string]bool{}
+	for _, desiredGerrit := range p.manifest.Gerrits {
+		desiredGerrit := desiredGerrit
+		key := strings.ToLower(desiredGerrit.Name)
+		wanted[key] = true
+
+		existing, ok := current[key]
+		if !ok {
+			projectSFID := gerritProjectSFID(p, desiredGerrit, nil)
+			details := fmt.Sprintf("add the gerrit instance %s to project: %s", desiredGerrit.URL, projectSFID)
+			p.add(ResourceGerrit, desiredGerrit.Name, ActionCreate, details, func(ctx context.Context) error {
+				return s.addGerrit(ctx, p, desiredGerrit, projectSFID)
+			})
+			continue
+		}
+
+		differences := gerritDifferences(existing, desiredGerrit)
+		if len(differences) == 0 {
+			continue
+		}
+		gerritID := existing.GerritID.String()
+		projectSFID := gerritProjectSFID(p, desiredGerrit, existing)
+		details := strings.Join(differences, ", ") + " - the gerrit instance is replaced"
+		p.add(ResourceGerrit, desiredGerrit.Name, ActionUpdate, details, func(ctx context.Context) error {
+			if err := s.gerritService.DeleteGerrit(ctx, gerritID); err != nil {
+				return err
+			}
+			return s.addGerrit(ctx, p, desiredGerrit, projectSFID)
+		})
+	}
+
+	for _, gerritModel := range currentGerrits {
+		if wanted[strings.ToLower(gerritModel.GerritName)] {
+			continue
+		}
+		if !p.manifest.Prune {
+			p.add(ResourceGerrit, gerritModel.GerritName, ActionUnmanaged, "added to the CLA Group but missing from the manifest", nil)
+			continue
+		}
+		gerritID := gerritModel.GerritID.String()
+		p.add(ResourceGerrit, gerritModel.GerritName, ActionDelete, "remove the gerrit instance", func(ctx context.Context) error {
+			return s.gerritService.DeleteGerrit(ctx, gerritID)
+		})
+	}
+
+	return nil
+}
+
+// addGerrit adds the gerrit instance of the manifest to the CLA Group
+func (s *service) addGerrit(ctx context.Context, p *plan, desiredGerrit *models.ClaGroupManifestGerrit, projectSFID string) error {
+	_, err := s.gerritService.AddGerrit(ctx, p.claGroupID(), projectSFID, &v1Models.AddGerritInput{
+		GerritName:          aws.String(desiredGerrit.Name),
+		GerritURL:           aws.String(desiredGerrit.URL),
+		GroupIDIcla:         desiredGerrit.GroupIDIcla,
+		GroupIDCcla:         desiredGerrit.GroupIDCcla,
+		GroupSyncBackend:    desiredGerrit.GroupSyncBackend,
+		GroupReconcileApply: desiredGerrit.GroupReconcileApply,
+		Version:             "v2",
+	}, p.claGroup)
+	return err
+}
+
+// gerritProjectSFID returns the project the gerrit instance is added to, the foundation when not set
+func gerritProjectSFID(p *plan, desiredGerrit *models.ClaGroupManifestGerrit, existing *v1Models.Gerrit) string {
+	if desiredGerrit.ProjectSfid != "" {
+		return desiredGerrit.ProjectSfid
+	}
+	if existing != nil && existing.ProjectSFID != "" {
+		return existing.ProjectSFID
+	}
+	return p.manifest.ClaGroup.FoundationSfid
+}
+
+// gerritDifferences returns the differences between the gerrit instance and the manifest
+func gerritDifferences(existing *v1Models.Gerrit, desiredGerrit *models.ClaGroupManifestGerrit) []string {
+	var differences []string
+	currentURL := strings.TrimSuffix(existing.GerritURL.String(), "/")
+	desiredURL := strings.TrimSuffix(desiredGerrit.URL, "/")
+	if currentURL != desiredURL {
+		differences = append(differences, fmt.Sprintf("url: %s -> %s", currentURL, desiredURL))
+	}
+	if existing.GroupIDIcla != desiredGerrit.GroupIDIcla {
+		differences = append(differences, fmt.Sprintf("group_id_icla: '%s' -> '%s'", existing.GroupIDIcla, desiredGerrit.GroupIDIcla))
+	}
+	if existing.GroupIDCcla != desiredGerrit.GroupIDCcla {
+		differences = append(differences, fmt.Sprintf("group_id_ccla: '%s' -> '%s'", existing.GroupIDCcla, desiredGerrit.GroupIDCcla))
+	}
+	if groupSyncBackend(existing.GroupSyncBackend) != groupSyncBackend(desiredGerrit.GroupSyncBackend) {
+		differences = append(differences, fmt.Sprintf("group_sync_backend: %s -> %s",
+			groupSyncBackend(existing.GroupSyncBackend), groupSyncBackend(desiredGerrit.GroupSyncBackend)))
+	}
+	if existing.GroupReconcileApply != desiredGerrit.GroupReconcileApply {
+		differences = append(differences, fmt.Sprintf("group_reconcile_apply: %t -> %t", existing.GroupReconcileApply, desiredGerrit.GroupReconcileApply))
+	}
+	if desiredGerrit.ProjectSfid != "" && existing.ProjectSFID != desiredGerrit.ProjectSfid {
+		differences = append(differences, fmt.Sprintf("project_sfid: %s -> %s", existing.ProjectSFID, desiredGerrit.ProjectSfid))
+	}
+	return differences
+}
+
+// groupSyncBackend returns the group sync backend, the LF Group API when not set
+func groupSyncBackend(backend string) string {
+	if backend == "" {
+		return gerrits.GroupSyncBackendLFGroup
+	}
+	return backend
+}
@@ -0,0 +1,403 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package cla_group_config
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/sirupsen/logrus"
+
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	"github.com/communitybridge/easycla/cla-backend-go/github/branch_protection"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/cla_groups"
+)
+
+// CLAGroupService is the CLA Group behavior needed to read and update the CLA Groups, implemented by the v1 project service
+type CLAGroupService interface {
+	GetCLAGroupByID(ctx context.Context, claGroupID string) (*v1Models.ClaGroup, error)
+	GetCLAGroupByName(ctx context.Context, claGroupName string) (*v1Models.ClaGroup, error)
+	UpdateCLAGroup(ctx context.Context, claGroupModel *v1Models.ClaGroup) (*v1Models.ClaGroup, error)
+}
+
+// CLAGroupSetupService is the CLA Group behavior needed to create the CLA Groups and enroll their projects, implemented
+// by the v2 cla groups service
+type CLAGroupSetupService interface {
+	CreateCLAGroup(ctx context.Context, authUser *auth.User, input *models.CreateClaGroupInput, projectManagerLFID string) (*models.ClaGroupSummary, error)
+	EnrollProjectsInClaGroup(ctx context.Context, request *cla_groups.EnrollProjectsModel) error
+	UnenrollProjectsInClaGroup(ctx context.Context, request *cla_groups.UnenrollProjectsModel) error
+}
+
+// ProjectCLAGroupRepository is the project/CLA Group mapping behavior needed to load the enrolled projects
+type ProjectCLAGroupRepository interface {
+	GetProjectsIdsForClaGroup(ctx context.Context, claGroupID string) ([]*projects_cla_groups.ProjectClaGroup, error)
+}
+
+// GithubOrganizationService is the GitHub organization behavior needed to manage the organizations, implemented by the
+// v2 github organizations service
+type GithubOrganizationService interface {
+	GetGithubOrganizations(ctx context.Context, projectSFID string) (*models.ProjectGithubOrganizations, error)
+	AddGithubOrganization(ctx context.Context, projectSFID string, input *models.CreateGithubOrganization) (*models.GithubOrganization, error)
+	DeleteGithubOrganization(ctx context.Context, projectSFID string, githubOrgName string) error
+	UpdateGithubOrganization(ctx context.Context, projectSFID string, organizationName string, autoEnabled bool, autoEnabledClaGroupID string, branchProtectionEnabled bool, branchProtectionPolicy *models.GithubBranchProtectionPolicy) error
+}
+
+// RepositoryService is the GitHub repository behavior needed to manage the repositories and their branch protection,
+// implemented by the v2 repositories service
+type RepositoryService interface {
+	AddGithubRepositories(ctx context.Context, projectSFID string, input *models.GithubRepositoryInput) ([]*v1Models.GithubRepository, error)
+	DisableRepository(ctx context.Context, repositoryID string) error
+	GetProtectedBranch(ctx context.Context, projectSFID, repositoryID, branchName string) (*models.GithubRepositoryBranchProtection, error)
+	UpdateProtectedBranch(ctx context.Context, projectSFID, repositoryID string, input *models.GithubRepositoryBranchProtectionInput) (*models.GithubRepositoryBranchProtection, error)
+}
+
+// GerritRepository is the gerrit behavior needed to load the gerrit instances of the CLA Group
+type GerritRepository interface {
+	GetClaGroupGerrits(ctx context.Context, claGroupID string) (*v1Models.GerritList, error)
+}
+
+// GerritService is the gerrit behavior needed to add and remove the gerrit instances
+type GerritService interface {
+	AddGerrit(ctx context.Context, claGroupID string, projectSFID string, input *v1Models.AddGerritInput, claGroupModel *v1Models.ClaGroup) (*v1Models.Gerrit, error)
+	DeleteGerrit(ctx context.Context, gerritID string) error
+}
+
+// TemplateService is the template behavior needed to attach the template to the CLA Group
+type TemplateService interface {
+	GetTemplates(ctx context.Context) ([]v1Models.Template, error)
+	CreateCLAGroupTemplate(ctx context.Context, claGroupID string, claGroupFields *v1Models.CreateClaGroupTemplate) (v1Models.TemplatePdfs, error)
+}
+
+// Service interface defines the CLA Group configuration service methods
+type Service interface {
+	Export(ctx context.Context, claGroupID string) (*models.ClaGroupManifest, error)
+	Plan(ctx context.Context, authUser *auth.User, manifest *models.ClaGroupManifest) (*models.ClaGroupConfigPlan, error)
+	Apply(ctx context.Context, authUser *auth.User, manifest *models.ClaGroupManifest) (*models.ClaGroupConfigPlan, error)
+}
+
+type service struct {
+	claGroupService       CLAGroupService
+	claGroupSetupService  CLAGroupSetupService
+	projectsClaGroupsRepo ProjectCLAGroupRepository
+	githubOrgService      GithubOrganizationService
+	repositoryService     RepositoryService
+	gerritRepo            GerritRepository
+	gerritService         GerritService
+	templateService       TemplateService
+}
+
+// NewService creates a new CLA Group configuration service
+func NewService(claGroupService CLAGroupService, claGroupSetupService CLAGroupSetupService, projectsClaGroupsRepo ProjectCLAGroupRepository,
+	githubOrgService GithubOrganizationService, repositoryService RepositoryService, gerritRepo GerritRepository, gerritService GerritService,
+	templateService TemplateService) Service {
+	return &service{
+		claGroupService:       claGroupService,
+		claGroupSetupService:  claGroupSetupService,
+		projectsClaGroupsRepo: projectsClaGroupsRepo,
+		githubOrgService:      githubOrgService,
+		repositoryService:     repositoryService,
+		gerritRepo:            gerritRepo,
+		gerritService:         gerritService,
+		templateService:       templateService,
+	}
+}
+
+// Export returns the manifest describing the current state of the CLA Group - the template field values are not
+// stored once the documents are generated and the branch protection is read from the default branch, so neither is
+// fully exported
+func (s *service) Export(ctx context.Context, claGroupID string) (*models.ClaGroupManifest, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.cla_group_config.service.Export",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+	}
+
+	claGroup, err := s.claGroupService.GetCLAGroupByID(ctx, claGroupID)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the CLA Group")
+		return nil, err
+	}
+	if claGroup == nil {
+		return nil, ErrCLAGroupNotFound
+	}
+
+	projectSFIDs, err := s.enrolledProjects(ctx, claGroupID)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the projects enrolled in the CLA Group")
+		return nil, err
+	}
+
+	manifest := &models.ClaGroupManifest{
+		Version: ManifestVersion,
+		ClaGroup: &models.ClaGroupManifestClaGroup{
+			Name:             claGroup.ProjectName,
+			Description:      claGroup.ProjectDescription,
+			FoundationSfid:   claGroup.FoundationSFID,
+			IclaEnabled:      claGroup.ProjectICLAEnabled,
+			CclaEnabled:      claGroup.ProjectCCLAEnabled,
+			CclaRequiresIcla: claGroup.ProjectCCLARequiresICLA,
+			ProjectSfidList:  projectSFIDs,
+			Template: &models.ClaGroupManifestTemplate{
+				TemplateID: claGroup.ProjectTemplateID,
+			},
+		},
+	}
+
+	// the organizations are added to the enrolled projects or to the foundation
+	lookup := append([]string{claGroup.FoundationSFID}, projectSFIDs...)
+	orgs, err := s.githubOrganizations(ctx, lookup)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the GitHub organizations of the CLA Group projects")
+		return nil, err
+	}
+	for _, key := range sortedOrganizationKeys(orgs) {
+		org := orgs[key]
+		if !isCLAGroupOrganization(org.org, claGroupID) {
+			continue
+		}
+		manifestOrg := &models.ClaGroupManifestGithubOrganization{
+			Name:                    org.org.GithubOrganizationName,
+			ProjectSfid:             org.projectSFID,
+			AutoEnabled:             org.org.AutoEnabled && org.org.AutoEnableCLAGroupID == claGroupID,
+			BranchProtectionEnabled: org.org.BranchProtectionEnabled,
+		}
+		for _, repository := range claGroupRepositories(org.org, claGroupID) {
+			manifestRepository := &models.ClaGroupManifestGithubRepository{
+				Name: repository.RepositoryName,
+			}
+			if repository.RepositoryGithubID != 0 {
+				manifestRepository.GithubID = strconv.FormatInt(repository.RepositoryGithubID, 10)
+			}
+			protection, protectionErr := s.repositoryService.GetProtectedBranch(ctx, repository.ProjectID, repository.RepositoryID, branch_protection.DefaultBranchName)
+			if protectionErr != nil {
+				log.WithFields(f).WithError(protectionErr).Warnf("unable to load the branch protection of repository: %s - not exported", repository.RepositoryName)
+			} else if protection.ProtectionEnabled {
+				manifestRepository.BranchProtection = &models.ClaGroupManifestBranchProtection{
+					BranchName:   branch_protection.DefaultBranchName,
+					EnforceAdmin: protection.EnforceAdmin,
+					StatusChecks: enabledStatusChecks(protection),
+				}
+			}
+			manifestOrg.Repositories = append(manifestOrg.Repositories, manifestRepository)
+		}
+		manifest.GithubOrganizations = append(manifest.GithubOrganizations, manifestOrg)
+	}
+
+	gerritList, err := s.gerritRepo.GetClaGroupGerrits(ctx, claGroupID)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the gerrit instances of the CLA Group")
+		return nil, err
+	}
+	for _, gerritModel := range sortedGerrits(gerritList) {
+		manifest.Gerrits = append(manifest.Gerrits, &models.ClaGroupManifestGerrit{
+			Name:                gerritModel.GerritName,
+			URL:                 gerritModel.GerritURL.String(),
+			ProjectSfid:         gerritModel.ProjectSFID,
+			GroupIDIcla:         gerritModel.GroupIDIcla,
+			GroupIDCcla:         gerritModel.GroupIDCcla,
+			GroupSyncBackend:    gerritModel.GroupSyncBackend,
+			GroupReconcileApply: gerritModel.GroupReconcileApply,
+		})
+	}
+
+	log.WithFields(f).Debugf("exported CLA Group with %d projects, %d GitHub organizations and %d gerrit instances",
+		len(projectSFIDs), len(manifest.GithubOrganizations), len(manifest.Gerrits))
+	return manifest, nil
+}
+
+// Plan returns the changes bringing the CLA Group in line with the manifest, nothing is changed
+func (s *service) Plan(ctx context.Context, authUser *auth.User, manifest *models.ClaGroupManifest) (*models.ClaGroupConfigPlan, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.cla_group_config.service.Plan",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"authUserName":   authUser.UserName,
+		"authUserEmail":  authUser.Email,
+	}
+
+	p, err := s.plan(ctx, authUser, manifest)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to plan the CLA Group configuration")
+		return nil, err
+	}
+
+	log.WithFields(f).Debugf("planned %d changes for CLA Group: %s", len(p.steps), manifest.ClaGroup.Name)
+	return p.result(false), nil
+}
+
+// Apply brings the CLA Group in line with the manifest, the CLA Group is created when it does not exist. The changes are
+// applied in order and a failing change doesn't stop the others - applying the manifest again retries the failed changes.
+func (s *service) Apply(ctx context.Context, authUser *auth.User, manifest *models.ClaGroupManifest) (*models.ClaGroupConfigPlan, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.cla_group_config.service.Apply",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"authUserName":   authUser.UserName,
+		"authUserEmail":  authUser.Email,
+	}
+
+	p, err := s.plan(ctx, authUser, manifest)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to plan the CLA Group configuration")
+		return nil, err
+	}
+
+	for _, st := range p.steps {
+		if st.apply == nil {
+			continue
+		}
+		// nothing can be attached to a CLA Group which failed to be created
+		if p.claGroup == nil && st.change.Resource != ResourceCLAGroup {
+			st.change.Status = StatusSkipped
+			st.change.Error = "the CLA Group was not created"
+			continue
+		}
+		if applyErr := st.apply(ctx); applyErr != nil {
+			log.WithFields(f).WithError(applyErr).Warnf("unable to %s %s: %s", st.change.Action, st.change.Resource, st.change.Name)
+			st.change.Status = StatusFailed
+			st.change.Error = applyErr.Error()
+			continue
+		}
+		log.WithFields(f).Debugf("applied %s %s: %s", st.change.Action, st.change.Resource, st.change.Name)
+		st.change.Status = StatusApplied
+	}
+
+	return p.result(true), nil
+}
+
+// enrolledProjects returns the sorted SFIDs of the projects enrolled in the CLA Group
+func (s *service) enrolledProjects(ctx context.Context, claGroupID string) ([]string, error) {
+	mappings, err := s.projectsClaGroupsRepo.GetProjectsIdsForClaGroup(ctx, claGroupID)
+	if err != nil {
+		return nil, err
+	}
+	projectSFIDs := make([]string, 0, len(mappings))
+	for _, mapping := range mappings {
+		projectSFIDs = append(projectSFIDs, mapping.ProjectSFID)
+	}
+	return sortedUnique(projectSFIDs), nil
+}
+
+// githubOrganization is a GitHub organization along with the project it was found under
+type githubOrganization struct {
+	projectSFID string
+	org         *models.ProjectGithubOrganization
+}
+
+// githubOrganizations loads the GitHub organizations of the projects keyed by the lower case organization name, an
+// organization listed under several projects is kept with the first project
+func (s *service) githubOrganizations(ctx context.Context, projectSFIDs []string) (map[string]*githubOrganization, error) {
+	out := map[string]*githubOrganization{}
+	for _, projectSFID := range sortedUnique(projectSFIDs) {
+		orgs, err := s.githubOrgService.GetGithubOrganizations(ctx, projectSFID)
+		if err != nil {
+			return nil, err
+		}
+		for _, org := range orgs.List {
+			key := strings.ToLower(org.GithubOrganizationName)
+			if _, ok := out[key]; !ok {
+				out[key] = &githubOrganization{projectSFID: projectSFID, org: org}
+			}
+		}
+	}
+	return out, nil
+}
+
+// isCLAGroupOrganization returns true when the organization is auto-enabled for the CLA Group or has repositories
+// enabled for the CLA Group
+func isCLAGroupOrganization(org *models.ProjectGithubOrganization, claGroupID string) bool {
+	if claGroupID == "" {
+		return false
+	}
+	if org.AutoEnabled && org.AutoEnableCLAGroupID == claGroupID {
+		return true
+	}
+	return len(claGroupRepositories(org, claGroupID)) > 0
+}
+
+// claGroupRepositories returns the repositories of the organization enabled for the CLA Group
+func claGroupRepositories(org *models.ProjectGithubOrganization, claGroupID string) []*models.ProjectGithubRepository {
+	var repositories []*models.ProjectGithubRepository
+	if org == nil || claGroupID == "" {
+		return repositories
+	}
+	for _, repository := range org.Repositories {
+		if repository.Enabled && repository.ClaGroupID == claGroupID {
+			repositories = append(repositories, repository)
+		}
+	}
+	return repositories
+}
+
+// hasOtherCLAGroupRepositories returns true when the organization has repositories enabled for other CLA Groups
+func hasOtherCLAGroupRepositories(org *models.ProjectGithubOrganization, claGroupID string) bool {
+	for _, repository := range org.Repositories {
+		if repository.Enabled && repository.ClaGroupID != "" && repository.ClaGroupID != claGroupID {
+			return true
+		}
+	}
+	return false
+}
+
+// enabledStatusChecks returns the sorted names of the enabled status checks of the protected branch
+func enabledStatusChecks(protection *models.GithubRepositoryBranchProtection) []string {
+	var checks []string
+	for _, check := range protection.StatusChecks {
+		if check != nil && utils.BoolValue(check.Enabled) {
+			checks = append(checks, utils.StringValue(check.Name))
+		}
+	}
+	sort.Strings(checks)
+	return checks
+}
+
+// sortedOrganizationKeys returns the keys of the organizations in order
+func sortedOrganizationKeys(orgs map[string]*githubOrganization) []string {
+	keys := make([]string, 0, len(orgs))
+	for key := range orgs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedGerrits returns the gerrit instances ordered by name
+func sortedGerrits(gerritList *v1Models.GerritList) []*v1Models.Gerrit {
+	var gerrits []*v1Models.Gerrit
+	if gerritList == nil {
+		return gerrits
+	}
+	gerrits = append(gerrits, gerritList.List...)
+	sort.Slice(gerrits, func(i, j int) bool {
+		return strings.ToLower(gerrits[i].GerritName) < strings.ToLower(gerrits[j].GerritName)
+	})
+	return gerrits
+}
+
+// sortedUnique returns the non-empty values in order without duplicates
+func sortedUnique(values []string) []string {
+	seen := map[string]bool{}
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		out = append(out, value)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// formatList formats the values for the change details
+func formatList(values []string) string {
+	return fmt.Sprintf("[%s]", strings.Join(values, ", "))
+}
@@ -0,0 +1,95 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/config"
+	"github.com/communitybridge/easycla/cla-backend-go/github"
+	"github.com/communitybridge/easycla/cla-backend-go/github_organizations"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+)
+
+var (
+	// version the application version
+	version string
+
+	// build/Commit the application build number
+	commit string
+
+	// branch the build branch
+	branch string
+
+	// build date
+	buildDate string
+)
+
+var branchProtectionAuditor github_organizations.BranchProtectionAuditor
+
+func init() {
+	var awsSession = session.Must(session.NewSession(&aws.Config{}))
+	stage := os.Getenv("STAGE")
+	if stage == "" {
+		log.Fatal("stage not set")
+	}
+	log.Infof("STAGE set to %s\n", stage)
+	configFile, err := config.LoadConfig("", awsSession, stage)
+	if err != nil {
+		log.Panicf("Unable to load config - Error: %v", err)
+	}
+
+	repositoriesRepo := repositories.NewRepository(awsSession, stage)
+	githubOrganizationsRepo := github_organizations.NewRepository(awsSession, stage)
+
+	github.Init(configFile.GitHub.AppID, configFile.GitHub.AppPrivateKey, configFile.GitHub.AccessToken)
+
+	branchProtectionAuditor = github_organizations.NewBranchProtectionAuditor(githubOrganizationsRepo, repositoriesRepo, github_organizations.NewBranchProtectionClient)
+}
+
+func handler(ctx context.Context, event events.CloudWatchEvent) {
+	f := logrus.Fields{
+		"functionName":   "handler",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"eventID":        event.ID,
+	}
+
+	// the drifted repositories are only reported unless the remediate mode is enabled
+	remediate := os.Getenv("BRANCH_PROTECTION_AUDIT_REMEDIATE") == "true"
+	summary, err := branchProtectionAuditor.AuditAll(ctx, remediate)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to audit the branch protection of the github organizations")
+		return
+	}
+	log.WithFields(f).Infof("audited %d repositories of %d github organizations (remediate: %t) - %d drifted, %d remediated, %d errors",
+		summary.RepositoriesAudited, summary.OrganizationsAudited, summary.Remediate, summary.RepositoriesDrifted, summary.RepositoriesRemediated, summary.Errors)
+}
+
+func printBuildInfo() {
+	log.Infof("Version                 : %s", version)
+	log.Infof("Git commit hash         : %s", commit)
+	log.Infof("Branch                  : %s", branch)
+	log.Infof("Build date              : %s", buildDate)
+}
+
+func main() {
+	log.Info("Lambda server starting...")
+	printBuildInfo()
+	if os.Getenv("LOCAL_MODE") == "true" {
+		handler(utils.NewContext(), events.CloudWatchEvent{})
+	} else {
+		lambda.Start(handler)
+	}
+	log.Infof("Lambda shutting down...")
+}
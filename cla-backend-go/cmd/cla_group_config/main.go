@@ -0,0 +1,260 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+// The cla_group_config command keeps the CLA Group configurations in git - it exports the manifest of a CLA Group and
+// plans/applies a manifest through the EasyCLA v4 API:
+//
+//	cla_group_config export -cla-group-id <id> [-format yaml|json] [-output <file>]
+//	cla_group_config plan -file <manifest> [-exit-code]
+//	cla_group_config apply -file <manifest>
+//
+// The API endpoint and the bearer token are read from the EASYCLA_API_URL and EASYCLA_API_TOKEN environment variables.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/cla_group_config"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+)
+
+const (
+	// defaultAPIURL is the development v4 API endpoint, through the API gateway
+	defaultAPIURL = "https://api-gw.dev.platform.linuxfoundation.org/cla-service/v4"
+
+	// exitCodeChanges is the plan exit code when the CLA Group drifted from the manifest, with the -exit-code flag
+	exitCodeChanges = 2
+)
+
+// changeSymbols are the prefixes of the changes in the plan output
+var changeSymbols = map[string]string{
+	cla_group_config.ActionCreate:    "+",
+	cla_group_config.ActionUpdate:    "~",
+	cla_group_config.ActionDelete:    "-",
+	cla_group_config.ActionUnmanaged: "?",
+}
+
+// client calls the CLA Group configuration endpoints
+type client struct {
+	apiURL     string
+	token      string
+	httpClient *http.Client
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+
+	apiURL := os.Getenv("EASYCLA_API_URL")
+	if apiURL == "" {
+		apiURL = defaultAPIURL
+	}
+	c := &client{
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		token:      os.Getenv("EASYCLA_API_TOKEN"),
+		httpClient: &http.Client{Timeout: 5 * time.Minute},
+	}
+
+	var err error
+	exitCode := 0
+	switch os.Args[1] {
+	case "export":
+		err = c.export(os.Args[2:])
+	case "plan":
+		exitCode, err = c.plan(os.Args[2:])
+	case "apply":
+		exitCode, err = c.apply(os.Args[2:])
+	default:
+		usage()
+		os.Exit(1)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(exitCode)
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: %s export|plan|apply [flags]\n", os.Args[0])
+	fmt.Fprintln(os.Stderr, "  export -cla-group-id <id> [-format yaml|json] [-output <file>]")
+	fmt.Fprintln(os.Stderr, "  plan -file <manifest> [-exit-code]")
+	fmt.Fprintln(os.Stderr, "  apply -file <manifest>")
+	fmt.Fprintln(os.Stderr, "environment: EASYCLA_API_URL (default: "+defaultAPIURL+"), EASYCLA_API_TOKEN")
+}
+
+// export writes the manifest of the CLA Group
+func (c *client) export(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	claGroupID := flags.String("cla-group-id", "", "the CLA Group ID")
+	format := flags.String("format", cla_group_config.FormatYAML, "the manifest format, yaml or json")
+	output := flags.String("output", "", "the manifest file, the standard output when not set")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *claGroupID == "" {
+		return fmt.Errorf("the -cla-group-id flag is required")
+	}
+
+	var manifest models.ClaGroupManifest
+	if err := c.call(http.MethodGet, fmt.Sprintf("/cla-group/%s/config", url.PathEscape(*claGroupID)), nil, &manifest); err != nil {
+		return err
+	}
+	data, err := cla_group_config.FormatManifest(&manifest, *format)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return ioutil.WriteFile(*output, data, 0600)
+}
+
+// plan prints the changes apply would make
+func (c *client) plan(args []string) (int, error) {
+	flags := flag.NewFlagSet("plan", flag.ExitOnError)
+	file := flags.String("file", "", "the manifest file, YAML or JSON")
+	exitCode := flags.Bool("exit-code", false, fmt.Sprintf("exit with code %d when the CLA Group drifted from the manifest", exitCodeChanges))
+	if err := flags.Parse(args); err != nil {
+		return 0, err
+	}
+
+	result, err := c.post("/cla-group-config/plan", *file)
+	if err != nil {
+		return 0, err
+	}
+	printPlan(result)
+
+	if *exitCode && hasChanges(result) {
+		return exitCodeChanges, nil
+	}
+	return 0, nil
+}
+
+// apply brings the CLA Group in line with the manifest, the exit code is 1 when a change failed
+func (c *client) apply(args []string) (int, error) {
+	flags := flag.NewFlagSet("apply", flag.ExitOnError)
+	file := flags.String("file", "", "the manifest file, YAML or JSON")
+	if err := flags.Parse(args); err != nil {
+		return 0, err
+	}
+
+	result, err := c.post("/cla-group-config/apply", *file)
+	if err != nil {
+		return 0, err
+	}
+	printPlan(result)
+
+	for _, change := range result.Changes {
+		if change.Status == cla_group_config.StatusFailed {
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// post sends the manifest file to the plan or apply endpoint
+func (c *client) post(path, file string) (*models.ClaGroupConfigPlan, error) {
+	if file == "" {
+		return nil, fmt.Errorf("the -file flag is required")
+	}
+	data, err := ioutil.ReadFile(file) // nolint
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := cla_group_config.ParseManifest(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var result models.ClaGroupConfigPlan
+	if err := c.call(http.MethodPost, path, manifest, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// call invokes the API endpoint, the JSON response is decoded into out
+func (c *client) call(method, path string, in, out interface{}) error {
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, c.apiURL+path, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() // nolint
+
+	responseBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s %s failed, status: %s, response: %s", method, path, resp.Status, string(bytes.TrimSpace(responseBody)))
+	}
+	return json.Unmarshal(responseBody, out)
+}
+
+// hasChanges returns true when the plan has changes to apply
+func hasChanges(result *models.ClaGroupConfigPlan) bool {
+	for _, change := range result.Changes {
+		if change.Action != cla_group_config.ActionUnmanaged {
+			return true
+		}
+	}
+	return false
+}
+
+// printPlan prints one line per change
+func printPlan(result *models.ClaGroupConfigPlan) {
+	claGroup := result.ClaGroupName
+	if result.ClaGroupID != "" {
+		claGroup = fmt.Sprintf("%s (%s)", result.ClaGroupName, result.ClaGroupID)
+	}
+	if len(result.Changes) == 0 {
+		fmt.Printf("CLA Group %s is up to date with the manifest\n", claGroup)
+		return
+	}
+
+	fmt.Printf("CLA Group %s:\n", claGroup)
+	for _, change := range result.Changes {
+		line := fmt.Sprintf("  %s %s %s", changeSymbols[change.Action], change.Resource, change.Name)
+		if change.Details != "" {
+			line += " - " + change.Details
+		}
+		if result.Applied || change.Status != cla_group_config.StatusPlanned {
+			line += fmt.Sprintf(" [%s]", change.Status)
+		}
+		if change.Error != "" {
+			line += ": " + change.Error
+		}
+		fmt.Println(line)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"encoding/json"
 	"os"
 
+	"github.com/communitybridge/easycla/cla-backend-go/event_search"
+	"github.com/communitybridge/easycla/cla-backend-go/event_webhooks"
 	"github.com/communitybridge/easycla/cla-backend-go/github_organizations"
 
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
@@ -96,12 +98,6 @@ func init() {
 	project_service.InitClient(configFile.APIGatewayURL)
 	githubOrganizationsService := github_organizations.NewService(githubOrganizationsRepo, repositoriesRepo, projectClaGroupRepo)
 	repositoriesService := repositories.NewService(repositoriesRepo, githubOrganizationsRepo, projectClaGroupRepo)
-	gerritService := gerrits.NewService(gerritRepo, &gerrits.LFGroup{
-		LfBaseURL:    configFile.LFGroup.ClientURL,
-		ClientID:     configFile.LFGroup.ClientID,
-		ClientSecret: configFile.LFGroup.ClientSecret,
-		RefreshToken: configFile.LFGroup.RefreshToken,
-	})
 	// Services
 	projectService := project.NewService(projectRepo, repositoriesRepo, gerritRepo, projectClaGroupRepo, usersRepo)
 
@@ -119,6 +115,8 @@ func init() {
 		projectClaGroupRepo,
 	})
 
+	gerritService := gerrits.NewServiceFromConfig(gerritRepo, configFile, usersRepo, eventsService)
+
 	signaturesRepo := signatures.NewRepository(awsSession, stage, companyRepo, usersRepo, eventsService, repositoriesRepo, githubOrganizationsRepo, gerritService)
 
 	usersService := users.NewService(usersRepo, eventsService)
@@ -139,7 +137,9 @@ func init() {
 		repositoriesService,
 		gerritService,
 		claManagerRequestsRepo,
-		approvalListRequestsRepo)
+		approvalListRequestsRepo,
+		event_webhooks.NewService(event_webhooks.NewRepository(awsSession, stage), nil, event_webhooks.DefaultRetryPolicy),
+		event_search.NewService(event_search.NewRepository(awsSession, stage)))
 }
 
 func handler(ctx context.Context, event events.DynamoDBEvent) {
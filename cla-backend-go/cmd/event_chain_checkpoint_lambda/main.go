@@ -0,0 +1,124 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/config"
+	claevents "github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+)
+
+var (
+	// version the application version
+	version string
+
+	// build/Commit the application build number
+	commit string
+
+	// branch the build branch
+	branch string
+
+	// build date
+	buildDate string
+)
+
+// checkpointsPrefix is the folder of the signature files bucket holding the checkpoint exports
+const checkpointsPrefix = "event-chain-checkpoints"
+
+var eventsService claevents.Service
+var signingKey ed25519.PrivateKey
+var s3Client *s3.S3
+var bucketName string
+
+func init() {
+	var awsSession = session.Must(session.NewSession(&aws.Config{}))
+	stage := os.Getenv("STAGE")
+	if stage == "" {
+		log.Fatal("stage not set")
+	}
+	log.Infof("STAGE set to %s\n", stage)
+	configFile, err := config.LoadConfig("", awsSession, stage)
+	if err != nil {
+		log.Panicf("Unable to load config - Error: %v", err)
+	}
+
+	signingKey, err = claevents.ParseChainSigningKey(configFile.EventChainSigningKey)
+	if err != nil {
+		log.Panicf("Unable to load the event chain signing key - Error: %v", err)
+	}
+
+	// the checkpoints don't log events, the service doesn't need the other repositories
+	eventsService = claevents.NewService(claevents.NewRepository(awsSession, stage), nil)
+	s3Client = s3.New(awsSession)
+	bucketName = configFile.SignatureFilesBucket
+}
+
+func handler(ctx context.Context, event events.CloudWatchEvent) {
+	f := logrus.Fields{
+		"functionName":   "handler",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"eventID":        event.ID,
+	}
+
+	export, err := eventsService.CreateChainCheckpoints(ctx, signingKey)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to create the event chain checkpoints")
+		return
+	}
+
+	body, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to marshal the event chain checkpoints")
+		return
+	}
+
+	// one export per run, the previous exports are kept to compare the streams with
+	key := fmt.Sprintf("%s/%s.json", checkpointsPrefix, time.Now().UTC().Format("2006-01-02T15-04-05Z"))
+	_, err = s3Client.PutObject(&s3.PutObjectInput{
+		Bucket:      aws.String(bucketName),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(body),
+		ContentType: aws.String("application/json"),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warnf("unable to upload the event chain checkpoints to s3 bucket: %s key: %s", bucketName, key)
+		return
+	}
+	log.WithFields(f).Infof("exported the checkpoints of %d event streams to s3 bucket: %s key: %s", len(export.Checkpoints), bucketName, key)
+}
+
+func printBuildInfo() {
+	log.Infof("Version                 : %s", version)
+	log.Infof("Git commit hash         : %s", commit)
+	log.Infof("Branch                  : %s", branch)
+	log.Infof("Build date              : %s", buildDate)
+}
+
+func main() {
+	log.Info("Lambda server starting...")
+	printBuildInfo()
+	if os.Getenv("LOCAL_MODE") == "true" {
+		handler(utils.NewContext(), events.CloudWatchEvent{})
+	} else {
+		lambda.Start(handler)
+	}
+	log.Infof("Lambda shutting down...")
+}
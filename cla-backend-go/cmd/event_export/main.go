@@ -0,0 +1,218 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+// The event_export command exports the audit events of a CLA Group or a foundation through the EasyCLA v4 API, as
+// CSV, JSON Lines, or CEF records - optionally with a syslog header - for SIEM ingestion:
+//
+//	event_export (-cla-group-id <id> | -foundation-sfid <sfid>) [-company-id <id>] [-event-type <type>]
+//	    [-after <time>] [-before <time>] [-format csv|jsonl|cef|syslog] [-batch-size <n>] [-cursor <cursor>]
+//	    [-output <file>]
+//
+// The events are exported oldest first, in batches. When an export is interrupted the cursor of the last exported
+// event is printed, the export resumes with the same flags and the -cursor flag - the records are then appended to
+// the output file.
+//
+// The API endpoint and the bearer token are read from the EASYCLA_API_URL and EASYCLA_API_TOKEN environment variables.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultAPIURL is the development v4 API endpoint, through the API gateway
+	defaultAPIURL = "https://api-gw.dev.platform.linuxfoundation.org/cla-service/v4"
+
+	// defaultBatchSize is the number of events exported per request
+	defaultBatchSize = 5000
+
+	// export response headers, sent as trailers when the export is streamed
+	nextCursorHeader = "X-Export-Next-Cursor"
+	countHeader      = "X-Export-Count"
+	errorHeader      = "X-Export-Error"
+)
+
+// client calls the event export endpoint
+type client struct {
+	apiURL     string
+	token      string
+	httpClient *http.Client
+}
+
+func main() {
+	apiURL := os.Getenv("EASYCLA_API_URL")
+	if apiURL == "" {
+		apiURL = defaultAPIURL
+	}
+	c := &client{
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		token:      os.Getenv("EASYCLA_API_TOKEN"),
+		httpClient: &http.Client{Timeout: 10 * time.Minute},
+	}
+
+	if err := c.export(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// export writes the events batch by batch until the export is complete
+func (c *client) export(args []string) error {
+	flags := flag.NewFlagSet("event_export", flag.ExitOnError)
+	claGroupID := flags.String("cla-group-id", "", "the CLA Group of the events")
+	foundationSFID := flags.String("foundation-sfid", "", "the foundation of the events")
+	companyID := flags.String("company-id", "", "the optional company filter")
+	eventType := flags.String("event-type", "", "the optional event type filter")
+	after := flags.String("after", "", "the optional start of the time range, inclusive - epoch seconds, YYYY-MM-DD or RFC 3339")
+	before := flags.String("before", "", "the optional end of the time range, inclusive - epoch seconds, YYYY-MM-DD or RFC 3339")
+	format := flags.String("format", "csv", "the record format, csv, jsonl, cef or syslog")
+	batchSize := flags.Int64("batch-size", defaultBatchSize, "the number of events exported per request")
+	cursor := flags.String("cursor", "", "the cursor printed by an interrupted export, the export resumes after it")
+	output := flags.String("output", "", "the output file, the standard output when not set")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if (*claGroupID == "") == (*foundationSFID == "") {
+		return fmt.Errorf("either the -cla-group-id or the -foundation-sfid flag is required")
+	}
+	if *batchSize <= 0 {
+		return fmt.Errorf("the -batch-size flag must be positive")
+	}
+
+	query := url.Values{}
+	setQueryValue(query, "claGroupID", *claGroupID)
+	setQueryValue(query, "foundationSFID", *foundationSFID)
+	setQueryValue(query, "companyID", *companyID)
+	setQueryValue(query, "eventType", *eventType)
+	setQueryValue(query, "format", *format)
+	query.Set("limit", strconv.FormatInt(*batchSize, 10))
+	for name, value := range map[string]string{"after": *after, "before": *before} {
+		if value == "" {
+			continue
+		}
+		epoch, err := parseTime(value)
+		if err != nil {
+			return fmt.Errorf("invalid -%s flag: %v", name, err)
+		}
+		query.Set(name, strconv.FormatInt(epoch, 10))
+	}
+
+	out := io.Writer(os.Stdout)
+	if *output != "" {
+		// a resumed export appends to the records of the interrupted one
+		fileFlags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+		if *cursor != "" {
+			fileFlags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+		}
+		file, err := os.OpenFile(*output, fileFlags, 0600)
+		if err != nil {
+			return err
+		}
+		defer file.Close() // nolint
+		out = file
+	}
+
+	var total int64
+	for {
+		setQueryValue(query, "cursor", *cursor)
+		next, count, err := c.exportBatch(query, out)
+		total += count
+		if next != "" {
+			*cursor = next
+		}
+		if err != nil {
+			if *cursor != "" {
+				fmt.Fprintf(os.Stderr, "exported %d events, resume the export with: -cursor %s\n", total, *cursor)
+			}
+			return err
+		}
+		if next == "" {
+			break
+		}
+	}
+	fmt.Fprintf(os.Stderr, "exported %d events\n", total)
+	return nil
+}
+
+// exportBatch writes the events of one request, it returns the cursor of the last written event when the export
+// continues
+func (c *client) exportBatch(query url.Values, out io.Writer) (string, int64, error) {
+	req, err := http.NewRequest(http.MethodGet, c.apiURL+"/events/export?"+query.Encode(), nil)
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Accept", "*/*")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close() // nolint
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		responseBody, readErr := ioutil.ReadAll(resp.Body)
+		if readErr != nil {
+			return "", 0, readErr
+		}
+		return "", 0, fmt.Errorf("GET /events/export failed, status: %s, response: %s", resp.Status, string(bytes.TrimSpace(responseBody)))
+	}
+	if _, err = io.Copy(out, resp.Body); err != nil {
+		return "", 0, err
+	}
+
+	// the trailers are only set once the body is read, the buffered exports send the values as headers
+	next, count, exportErr := responseValue(resp, nextCursorHeader), responseValue(resp, countHeader), responseValue(resp, errorHeader)
+	exported, _ := strconv.ParseInt(count, 10, 64) // nolint
+	if exportErr != "" {
+		return next, exported, fmt.Errorf("the export failed: %s", exportErr)
+	}
+	if count == "" {
+		// the stream was cut - the records following the last cursor are exported again when the export resumes
+		return next, exported, fmt.Errorf("the export response is incomplete, the %s trailer is missing", countHeader)
+	}
+	return next, exported, nil
+}
+
+// responseValue returns the value of the trailer or of the header
+func responseValue(resp *http.Response, name string) string {
+	if value := resp.Trailer.Get(name); value != "" {
+		return value
+	}
+	return resp.Header.Get(name)
+}
+
+// parseTime parses epoch seconds, a date or an RFC 3339 time
+func parseTime(value string) (int64, error) {
+	if epoch, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return epoch, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t.Unix(), nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0, fmt.Errorf("expecting epoch seconds, YYYY-MM-DD or RFC 3339: %s", value)
+	}
+	return t.Unix(), nil
+}
+
+func setQueryValue(query url.Values, name, value string) {
+	if value == "" {
+		query.Del(name)
+		return
+	}
+	query.Set(name, value)
+}
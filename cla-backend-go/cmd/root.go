@@ -31,6 +31,9 @@ func init() {
 		"DB_MAX_CONNECTIONS": 1,
 		"STAGE":              "dev",
 
+		// the internal port of the Prometheus metrics, 0 disables them
+		"METRICS_PORT": 9090,
+
 		// should we validate the user's GitHub organizations?
 		"GH_ORG_VALIDATION": "true",
 		// should we validate company API queries against the current authenticated user?
@@ -4,6 +4,7 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -13,17 +14,26 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/communitybridge/easycla/cla-backend-go/emails"
 
+	"github.com/communitybridge/easycla/cla-backend-go/api_keys"
+	"github.com/communitybridge/easycla/cla-backend-go/cla_group_config"
+	v2APIKeys "github.com/communitybridge/easycla/cla-backend-go/v2/api_keys"
+	v2ClaGroupConfig "github.com/communitybridge/easycla/cla-backend-go/v2/cla_group_config"
 	"github.com/communitybridge/easycla/cla-backend-go/v2/dynamo_events"
+	v2EventWebhooks "github.com/communitybridge/easycla/cla-backend-go/v2/event_webhooks"
 	v2GithubActivity "github.com/communitybridge/easycla/cla-backend-go/v2/github_activity"
+	v2GitlabActivity "github.com/communitybridge/easycla/cla-backend-go/v2/gitlab_activity"
+	v2ResignCampaigns "github.com/communitybridge/easycla/cla-backend-go/v2/resign_campaigns"
 
 	"github.com/gofrs/uuid"
 
 	"github.com/communitybridge/easycla/cla-backend-go/approval_list"
 	"github.com/communitybridge/easycla/cla-backend-go/v2/cla_groups"
 	openapi_runtime "github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/middleware"
 
 	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
 
@@ -36,13 +46,20 @@ import (
 	acs_service "github.com/communitybridge/easycla/cla-backend-go/v2/acs-service"
 	organization_service "github.com/communitybridge/easycla/cla-backend-go/v2/organization-service"
 
+	"github.com/communitybridge/easycla/cla-backend-go/event_search"
+	"github.com/communitybridge/easycla/cla-backend-go/event_webhooks"
 	"github.com/communitybridge/easycla/cla-backend-go/github_organizations"
+	"github.com/communitybridge/easycla/cla-backend-go/gitlab"
+	"github.com/communitybridge/easycla/cla-backend-go/gitlab_organizations"
+	"github.com/communitybridge/easycla/cla-backend-go/resign_campaigns"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
 	v2GithubOrganizations "github.com/communitybridge/easycla/cla-backend-go/v2/github_organizations"
 	"github.com/communitybridge/easycla/cla-backend-go/v2/metrics"
 
 	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
 	v2Gerrits "github.com/communitybridge/easycla/cla-backend-go/v2/gerrits"
 
+	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 
 	lfxAuth "github.com/LF-Engineering/lfx-kit/auth"
@@ -67,6 +84,8 @@ import (
 	v2Signatures "github.com/communitybridge/easycla/cla-backend-go/v2/signatures"
 
 	ini "github.com/communitybridge/easycla/cla-backend-go/init"
+
+	"github.com/communitybridge/easycla/cla-backend-go/config"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 
 	"github.com/communitybridge/easycla/cla-backend-go/auth"
@@ -106,6 +125,9 @@ var (
 
 	// BuildDate is the date of the build
 	BuildDate string
+
+	// shutdownTracing flushes the pending spans when the server stops
+	shutdownTracing = func(context.Context) error { return nil }
 )
 
 // serveCmd represents the serve command
@@ -197,9 +219,15 @@ func server(localMode bool) http.Handler {
 	if err != nil {
 		log.WithFields(f).WithError(err).Panic("Unable to load AWS session")
 	}
+	telemetry.InstrumentAWSSession(awsSession)
 
 	configFile := ini.GetConfig()
 
+	shutdownTracing, err = telemetry.InitTracing(context.Background(), "easycla-api", configFile.Tracing)
+	if err != nil {
+		log.WithFields(f).WithError(err).Panic("unable to set up the trace exporter")
+	}
+
 	swaggerSpec, err := loads.Analyzed(restapi.SwaggerJSON, "")
 	if err != nil {
 		log.WithFields(f).WithError(err).Panic("Invalid swagger file for initializing EasyCLA v1")
@@ -218,11 +246,29 @@ func server(localMode bool) http.Handler {
 		log.WithFields(f).WithError(err).Panic("unable to setup docraptor client")
 	}
 
+	var oidcIssuers []auth.IssuerConfig
+	for _, issuer := range configFile.OIDCIssuers {
+		oidcIssuers = append(oidcIssuers, auth.IssuerConfig{
+			Issuer:       issuer.Issuer,
+			Audiences:    issuer.Audiences,
+			Algorithms:   issuer.Algorithms,
+			DiscoveryURL: issuer.DiscoveryURL,
+			JWKSURL:      issuer.JWKSURL,
+			ClaimMapping: auth.ClaimMapping{
+				UsernameClaim:      issuer.UsernameClaim,
+				NameClaim:          issuer.NameClaim,
+				EmailClaim:         issuer.EmailClaim,
+				EmailVerifiedClaim: issuer.EmailVerifiedClaim,
+			},
+			UsernameNamespace: issuer.UsernameNamespace,
+		})
+	}
 	authValidator, err := auth.NewAuthValidator(
 		configFile.Auth0.Domain,
 		configFile.Auth0.ClientID,
 		configFile.Auth0.UsernameClaim,
-		configFile.Auth0.Algorithm)
+		configFile.Auth0.Algorithm,
+		oidcIssuers...)
 	if err != nil {
 		logrus.Panic(err)
 	}
@@ -230,17 +276,62 @@ func server(localMode bool) http.Handler {
 
 	// Our backend repository handlers
 	userRepo := user.NewDynamoRepository(awsSession, stage)
-	usersRepo := users.NewRepository(awsSession, stage)
 	repositoriesRepo := repositories.NewRepository(awsSession, stage)
 	gerritRepo := gerrits.NewRepository(awsSession, stage)
 	templateRepo := template.NewRepository(awsSession, stage)
 	approvalListRepo := approval_list.NewRepository(awsSession, stage)
-	v1CompanyRepo := v1Company.NewRepository(awsSession, stage)
-	eventsRepo := events.NewRepository(awsSession, stage)
 	v1ProjectClaGroupRepo := projects_cla_groups.NewRepository(awsSession, stage)
-	v1CLAGroupRepo := project.NewRepository(awsSession, stage, repositoriesRepo, gerritRepo, v1ProjectClaGroupRepo)
+
+	// The user, company, event, CLA group and signature records can be kept in memory for offline development
+	memoryStorage := configFile.StorageBackend == config.StorageBackendMemory
+	var usersRepo users.UserRepository
+	var v1CompanyRepo v1Company.IRepository
+	var eventsRepo events.Repository
+	var v1CLAGroupRepo project.ProjectRepository
+	if memoryStorage {
+		log.WithFields(f).Warn("using the in-memory storage backend - records will not be persisted")
+		usersRepo = users.NewMemoryRepository()
+		v1CompanyRepo = v1Company.NewMemoryRepository()
+		eventsRepo = events.NewMemoryRepository()
+		v1CLAGroupRepo = project.NewMemoryRepository(repositoriesRepo, gerritRepo, v1ProjectClaGroupRepo)
+	} else {
+		usersRepo = users.NewRepository(awsSession, stage)
+		v1CompanyRepo = v1Company.NewRepository(awsSession, stage)
+		eventsRepo = events.NewRepository(awsSession, stage)
+		v1CLAGroupRepo = project.NewRepository(awsSession, stage, repositoriesRepo, gerritRepo, v1ProjectClaGroupRepo)
+	}
 	metricsRepo := metrics.NewRepository(awsSession, stage, configFile.APIGatewayURL, v1ProjectClaGroupRepo)
 	githubOrganizationsRepo := github_organizations.NewRepository(awsSession, stage)
+	var gitlabGroupsRepo gitlab_organizations.RepositoryInterface
+	if memoryStorage {
+		gitlabGroupsRepo = gitlab_organizations.NewMemoryRepository()
+	} else {
+		gitlabGroupsRepo = gitlab_organizations.NewRepository(awsSession, stage)
+	}
+	var eventWebhooksRepo event_webhooks.Repository
+	if memoryStorage {
+		eventWebhooksRepo = event_webhooks.NewMemoryRepository()
+	} else {
+		eventWebhooksRepo = event_webhooks.NewRepository(awsSession, stage)
+	}
+	// The search index is fed by the DynamoDB stream of the events table, the memory storage has no stream and the
+	// event searches fall back to the repository filters
+	var eventSearchService event_search.Service
+	if !memoryStorage {
+		eventSearchService = event_search.NewService(event_search.NewRepository(awsSession, stage))
+	}
+	var resignCampaignsRepo resign_campaigns.Repository
+	if memoryStorage {
+		resignCampaignsRepo = resign_campaigns.NewMemoryRepository()
+	} else {
+		resignCampaignsRepo = resign_campaigns.NewRepository(awsSession, stage)
+	}
+	var apiKeysRepo api_keys.Repository
+	if memoryStorage {
+		apiKeysRepo = api_keys.NewMemoryRepository()
+	} else {
+		apiKeysRepo = api_keys.NewRepository(awsSession, stage)
+	}
 	claManagerReqRepo := cla_manager.NewRepository(awsSession, stage)
 
 	// Our service layer handlers
@@ -251,16 +342,15 @@ func server(localMode bool) http.Handler {
 		v1ProjectClaGroupRepo,
 	})
 
-	gerritService := gerrits.NewService(gerritRepo, &gerrits.LFGroup{
-		LfBaseURL:     configFile.LFGroup.ClientURL,
-		ClientID:      configFile.LFGroup.ClientID,
-		ClientSecret:  configFile.LFGroup.ClientSecret,
-		RefreshToken:  configFile.LFGroup.RefreshToken,
-		EventsService: eventsService,
-	})
+	gerritService := gerrits.NewServiceFromConfig(gerritRepo, configFile, usersRepo, eventsService)
 
 	// Signature repository handler
-	signaturesRepo := signatures.NewRepository(awsSession, stage, v1CompanyRepo, usersRepo, eventsService, repositoriesRepo, githubOrganizationsRepo, gerritService)
+	var signaturesRepo signatures.SignatureRepository
+	if memoryStorage {
+		signaturesRepo = signatures.NewMemoryRepository(v1CompanyRepo, usersRepo, eventsService, repositoriesRepo, githubOrganizationsRepo, gerritService)
+	} else {
+		signaturesRepo = signatures.NewRepository(awsSession, stage, v1CompanyRepo, usersRepo, eventsService, repositoriesRepo, githubOrganizationsRepo, gerritService)
+	}
 
 	// Initialize the external platform services - these are external APIs that
 	// we download the swagger specification, generate the models, and have
@@ -272,7 +362,7 @@ func server(localMode bool) http.Handler {
 
 	v1ProjectClaGroupService := projects_cla_groups.NewService(v1ProjectClaGroupRepo)
 	usersService := users.NewService(usersRepo, eventsService)
-	healthService := health.New(Version, Commit, Branch, BuildDate)
+	healthService := health.New(Version, Commit, Branch, BuildDate, healthChecks(awsSession, stage, configFile, docraptorClient, memoryStorage)...)
 	templateService := template.NewService(stage, templateRepo, docraptorClient, awsSession)
 	v1ProjectService := project.NewService(v1CLAGroupRepo, repositoriesRepo, gerritRepo, v1ProjectClaGroupRepo, usersRepo)
 	emailTemplateService := emails.NewEmailTemplateService(v1CLAGroupRepo, v1ProjectClaGroupRepo, v1ProjectService, configFile.CorporateConsoleV1URL, configFile.CorporateConsoleV2URL)
@@ -282,26 +372,40 @@ func server(localMode bool) http.Handler {
 	v2CompanyService := v2Company.NewService(v1CompanyService, signaturesRepo, v1CLAGroupRepo, usersRepo, v1CompanyRepo, v1ProjectClaGroupRepo, eventsService)
 	v2SignService := sign.NewService(configFile.ClaV1ApiURL, v1CompanyRepo, v1CLAGroupRepo, v1ProjectClaGroupRepo, v1CompanyService)
 	v1SignaturesService := signatures.NewService(signaturesRepo, v1CompanyService, usersService, eventsService, githubOrgValidation)
+	gerritGroupReconciler := signatures.NewGerritGroupReconciler(signaturesRepo, gerritService)
+	documentIntegrityService := signatures.NewDocumentIntegrityService(signaturesRepo, utils.NewS3Storage(awsSession, configFile.SignatureFilesBucket), eventsService)
 	v2SignatureService := v2Signatures.NewService(awsSession, configFile.SignatureFilesBucket, v1ProjectService, v1CompanyService, v1SignaturesService, v1ProjectClaGroupRepo, signaturesRepo, usersService)
 	v1ClaManagerService := cla_manager.NewService(claManagerReqRepo, v1ProjectClaGroupRepo, v1CompanyService, v1ProjectService, usersService, v1SignaturesService, eventsService, emailTemplateService, configFile.CorporateConsoleV1URL)
 	v1RepositoriesService := repositories.NewService(repositoriesRepo, githubOrganizationsRepo, v1ProjectClaGroupRepo)
 	v2RepositoriesService := v2Repositories.NewService(repositoriesRepo, v1ProjectClaGroupRepo, githubOrganizationsRepo)
 	v2ClaManagerService := v2ClaManager.NewService(emailTemplateService, v1CompanyService, v1ProjectService, v1ClaManagerService, usersService, v1RepositoriesService, v2CompanyService, eventsService, v1ProjectClaGroupRepo)
-	v1ApprovalListService := approval_list.NewService(approvalListRepo, v1ProjectClaGroupRepo, v1ProjectService, usersRepo, v1CompanyRepo, v1CLAGroupRepo, signaturesRepo, emailTemplateService, configFile.CorporateConsoleV2URL, http.DefaultClient)
+	v1ApprovalListService := approval_list.NewService(approvalListRepo, v1ProjectClaGroupRepo, v1ProjectService, usersRepo, v1CompanyRepo, v1CLAGroupRepo, signaturesRepo, emailTemplateService, configFile.CorporateConsoleV2URL, telemetry.NewHTTPClient(0))
 	authorizer := auth.NewAuthorizer(authValidator, userRepo)
 	v2MetricsService := metrics.NewService(metricsRepo, v1ProjectClaGroupRepo)
 	githubOrganizationsService := github_organizations.NewService(githubOrganizationsRepo, repositoriesRepo, v1ProjectClaGroupRepo)
-	v2GithubOrganizationsService := v2GithubOrganizations.NewService(githubOrganizationsRepo, repositoriesRepo, v1ProjectClaGroupRepo, githubOrganizationsService)
+	branchProtectionAuditor := github_organizations.NewBranchProtectionAuditor(githubOrganizationsRepo, repositoriesRepo, github_organizations.NewBranchProtectionClient)
+	v2GithubOrganizationsService := v2GithubOrganizations.NewService(githubOrganizationsRepo, repositoriesRepo, v1ProjectClaGroupRepo, githubOrganizationsService, branchProtectionAuditor)
 	autoEnableService := dynamo_events.NewAutoEnableService(v1RepositoriesService, repositoriesRepo, githubOrganizationsRepo, v1ProjectClaGroupRepo, v1ProjectService)
-	v2GithubActivityService := v2GithubActivity.NewService(repositoriesRepo, githubOrganizationsRepo, eventsService, autoEnableService, emailService)
+	v2GithubActivityService := v2GithubActivity.NewService(repositoriesRepo, githubOrganizationsRepo, eventsService, autoEnableService, emailService,
+		v2GithubActivity.NewCLAChecker(usersService, v1SignaturesService), github.NewChecksClient, configFile.ClaV1ApiURL)
+	gitlabClient := gitlab.NewClient(configFile.GitLab.APIURL, configFile.GitLab.AccessToken, nil)
+	v2GitlabActivityService := v2GitlabActivity.NewService(repositoriesRepo, gitlabGroupsRepo, v1ProjectClaGroupRepo, eventsService, gitlabClient,
+		v2GitlabActivity.NewCLAChecker(usersService, v1SignaturesService), configFile.GitLab.SignURL)
+	eventWebhooksService := event_webhooks.NewService(eventWebhooksRepo, nil, event_webhooks.DefaultRetryPolicy)
+	resignCampaignsService := resign_campaigns.NewService(resignCampaignsRepo, signaturesRepo, v1CLAGroupRepo, usersRepo, v1CompanyRepo, eventsService)
+	// the API keys may be granted the operations of the v2 API
+	apiKeysService := api_keys.NewService(apiKeysRepo, eventsService, v2SwaggerSpec.Analyzer.OperationIDs())
 
 	v2ClaGroupService := cla_groups.NewService(v1ProjectService, templateService, v1ProjectClaGroupRepo, v1ClaManagerService, v1SignaturesService, metricsRepo, gerritService, v1RepositoriesService, eventsService)
+	claGroupConfigService := cla_group_config.NewService(v1ProjectService, v2ClaGroupService, v1ProjectClaGroupRepo, v2GithubOrganizationsService, v2RepositoriesService, gerritRepo, gerritService, templateService)
 
 	sessionStore, err := dynastore.New(dynastore.Path("/"), dynastore.HTTPOnly(), dynastore.TableName(configFile.SessionStoreTableName), dynastore.DynamoDB(dynamodb.New(awsSession)))
 	if err != nil {
 		log.WithFields(f).WithError(err).Panic("unable to create new Dynastore session")
 	}
-	utils.SetSnsEmailSender(awsSession, configFile.SNSEventTopicARN, configFile.SenderEmailAddress)
+	if err := utils.SetEmailSenderFromConfig(awsSession, configFile); err != nil {
+		log.WithFields(f).WithError(err).Fatal("unable to set up the email sender")
+	}
 	utils.SetS3Storage(awsSession, configFile.SignatureFilesBucket)
 
 	// Setup security handlers
@@ -318,28 +422,33 @@ func server(localMode bool) http.Handler {
 	v2Template.Configure(v2API, templateService, v1ProjectClaGroupService, eventsService)
 	github.Configure(api, configFile.GitHub.ClientID, configFile.GitHub.ClientSecret, configFile.GitHub.AccessToken, sessionStore)
 	signatures.Configure(api, v1SignaturesService, sessionStore, eventsService)
-	v2Signatures.Configure(v2API, v1ProjectService, v1CLAGroupRepo, v1CompanyService, v1SignaturesService, sessionStore, eventsService, v2SignatureService, v1ProjectClaGroupRepo)
+	v2Signatures.Configure(v2API, v1ProjectService, v1CLAGroupRepo, v1CompanyService, v1SignaturesService, sessionStore, eventsService, v2SignatureService, v1ProjectClaGroupRepo, documentIntegrityService)
 	approval_list.Configure(api, v1ApprovalListService, sessionStore, v1SignaturesService, eventsService)
 	v1Company.Configure(api, v1CompanyService, usersService, companyUserValidation, eventsService)
 	docs.Configure(api)
 	v2Docs.Configure(v2API)
 	version.Configure(api, Version, Commit, Branch, BuildDate)
 	v2Version.Configure(v2API, Version, Commit, Branch, BuildDate)
-	events.Configure(api, eventsService)
-	v2Events.Configure(v2API, eventsService, v1CompanyRepo, v1ProjectClaGroupRepo)
+	events.Configure(api, eventsService, eventSearchService)
+	v2Events.Configure(v2API, eventsService, eventSearchService, v1CompanyRepo, v1ProjectClaGroupRepo)
 	v2Metrics.Configure(v2API, v2MetricsService, v1CompanyRepo)
 	github_organizations.Configure(api, githubOrganizationsService, eventsService)
 	v2GithubOrganizations.Configure(v2API, v2GithubOrganizationsService, eventsService)
 	repositories.Configure(api, v1RepositoriesService, eventsService)
 	v2Repositories.Configure(v2API, v2RepositoriesService, eventsService)
 	gerrits.Configure(api, gerritService, v1ProjectService, eventsService)
-	v2Gerrits.Configure(v2API, gerritService, v1ProjectService, eventsService, v1ProjectClaGroupRepo)
+	v2Gerrits.Configure(v2API, gerritService, v1ProjectService, eventsService, v1ProjectClaGroupRepo, gerritGroupReconciler)
 	v2Company.Configure(v2API, v2CompanyService, v1ProjectClaGroupRepo, configFile.LFXPortalURL, configFile.CorporateConsoleV1URL)
 	cla_manager.Configure(api, v1ClaManagerService, v1CompanyService, v1ProjectService, usersService, v1SignaturesService, eventsService, emailTemplateService)
 	v2ClaManager.Configure(v2API, v2ClaManagerService, v1CompanyService, configFile.LFXPortalURL, configFile.CorporateConsoleV2URL, v1ProjectClaGroupRepo, userRepo)
 	sign.Configure(v2API, v2SignService)
 	cla_groups.Configure(v2API, v2ClaGroupService, v1ProjectService, v1ProjectClaGroupRepo, eventsService)
 	v2GithubActivity.Configure(v2API, v2GithubActivityService)
+	v2GitlabActivity.Configure(v2API, v2GitlabActivityService, configFile.GitLab.WebhookSecret)
+	v2EventWebhooks.Configure(v2API, eventWebhooksService, v1ProjectService)
+	v2ResignCampaigns.Configure(v2API, resignCampaignsService, v1ProjectService)
+	v2ClaGroupConfig.Configure(v2API, claGroupConfigService, v1ProjectService)
+	v2APIKeys.Configure(v2API, apiKeysService)
 
 	userCreaterMiddleware := func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -354,6 +463,11 @@ func server(localMode bool) http.Handler {
 		return setRequestIDHandler(responseLoggingMiddleware(userCreaterMiddleware(handler)))
 	}
 
+	// The v2 API also accepts the scoped API keys, the key replaces the ACL of the request before authentication
+	v2MiddlewareSetupfunc := func(handler http.Handler) http.Handler {
+		return middlewareSetupfunc(api_keys.Middleware(apiKeysService, handler))
+	}
+
 	v2API.CsvProducer = openapi_runtime.ProducerFunc(func(w io.Writer, data interface{}) error {
 		switch v := data.(type) {
 		case []byte:
@@ -407,17 +521,49 @@ func server(localMode bool) http.Handler {
 				// v1 API => /v3, python side is /v1 and /v2
 				api.Serve(middlewareSetupfunc), swaggerSpec.BasePath(),
 				// v2 API => /v4
-				v2API.Serve(middlewareSetupfunc), v2SwaggerSpec.BasePath()))
+				v2API.Serve(v2MiddlewareSetupfunc), v2SwaggerSpec.BasePath()))
 	} else {
 		apiHandler = setupCORSHandler(
 			wrapHandlers(
 				// v1 API => /v3, python side is /v1 and /v2
 				api.Serve(middlewareSetupfunc), swaggerSpec.BasePath(),
 				// v2 API => /v4
-				v2API.Serve(middlewareSetupfunc), v2SwaggerSpec.BasePath()),
+				v2API.Serve(v2MiddlewareSetupfunc), v2SwaggerSpec.BasePath()),
 			configFile.AllowedOrigins)
 	}
-	return apiHandler
+
+	// The lambda is frozen between the invocations - the spans are flushed after each request
+	return telemetry.TracingMiddleware(apiHandler, !localMode)
+}
+
+// healthChecks returns the readiness checks of the dependencies used with the configuration
+func healthChecks(awsSession *session.Session, stage string, configFile config.Config, docraptorClient docraptor.Client, memoryStorage bool) []health.Check {
+	var checks []health.Check
+	if !memoryStorage {
+		checks = append(checks, health.DynamoDBTableChecks(awsSession, stage)...)
+	}
+	if configFile.SignatureFilesBucket != "" {
+		checks = append(checks, health.S3BucketCheck(awsSession, configFile.SignatureFilesBucket))
+	}
+	if (configFile.EmailSender == "" || configFile.EmailSender == config.EmailSenderSNS) && configFile.SNSEventTopicARN != "" {
+		checks = append(checks, health.SNSTopicCheck(awsSession, configFile.SNSEventTopicARN))
+	}
+	checks = append(checks, health.Auth0JWKSCheck(configFile.Auth0.Domain))
+	if configFile.GitHub.AppID != 0 {
+		// each run creates an installation token - checked less often
+		checks = append(checks, health.Check{
+			Name:     "EasyCLA - GitHub - App installation token",
+			Timeout:  5 * time.Second,
+			CacheTTL: 5 * time.Minute,
+			Run:      github.CheckAppInstallationToken,
+		})
+	}
+	checks = append(checks, health.Check{
+		Name:     "EasyCLA - DocRaptor",
+		CacheTTL: 5 * time.Minute,
+		Run:      docraptorClient.Ping,
+	})
+	return checks
 }
 
 // setupCORSHandler sets up the CORS logic and creates the middleware HTTP handler
@@ -552,34 +698,36 @@ func setRequestIDHandler(next http.Handler) http.Handler {
 	})
 }
 
-// responseLoggingMiddleware logs the responses from API endpoints
+// responseLoggingMiddleware logs the responses from API endpoints and records their latency
 func responseLoggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		requestStart(r.Header.Get(utils.XREQUESTID), r.Method)
+		start := time.Now()
 		log.Debugf("BEGIN - %s %s", r.Method, r.URL.String())
-		next.ServeHTTP(NewLoggingResponseWriter(w), r)
-		if r.Response != nil {
-			reqMetrics := getRequestMetrics(r.Header.Get(utils.XREQUESTID))
-			if reqMetrics != nil {
-				log.Debugf("END - %s %s - response code: %d response status: %s, elapsed: %v",
-					r.Method, r.URL.String(), r.Response.StatusCode, r.Response.Status, reqMetrics.elapsed)
-			} else {
-				log.Debugf("END - %s %s - response code: %d response status: %s",
-					r.Method, r.URL.String(), r.Response.StatusCode, r.Response.Status)
-			}
-			clearRequestMetrics(r.Header.Get(utils.XREQUESTID))
-		} else {
-			reqMetrics := getRequestMetrics(r.Header.Get(utils.XREQUESTID))
-			if reqMetrics != nil {
-				log.Debugf("END - %s %s, elapsed: %v", r.Method, r.URL.String(), reqMetrics.elapsed)
-			} else {
-				log.Debugf("END - %s %s", r.Method, r.URL.String())
-			}
-			clearRequestMetrics(r.Header.Get(utils.XREQUESTID))
+		lrw := NewLoggingResponseWriter(w)
+		next.ServeHTTP(lrw, r)
+		elapsed := time.Since(start)
+
+		// handlers writing the body without a header respond with 200
+		statusCode := lrw.StatusCode
+		if statusCode == 0 {
+			statusCode = http.StatusOK
 		}
+		operation := operationID(r)
+		telemetry.AnnotateServerSpan(r, operation, r.Header.Get(utils.XREQUESTID))
+		telemetry.ObserveHTTPRequest(operation, r.Method, statusCode, elapsed)
+		log.Debugf("END - %s %s - response code: %d, elapsed: %v", r.Method, r.URL.String(), statusCode, elapsed)
 	})
 }
 
+// operationID returns the swagger operation ID of the routed request
+func operationID(r *http.Request) string {
+	route := middleware.MatchedRouteFrom(r)
+	if route == nil || route.Operation == nil {
+		return telemetry.UnknownOperation
+	}
+	return route.Operation.ID
+}
+
 // create user form http authorization token
 // this function creates user if user does not exist and token is valid
 func createUserFromRequest(authorizer auth.Authorizer, usersService users.Service, eventsService events.Service, r *http.Request) {
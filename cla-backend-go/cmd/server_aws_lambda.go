@@ -9,12 +9,14 @@ import (
 	"github.com/LF-Engineering/aws-lambda-go-api-proxy/httpadapter"
 	"github.com/aws/aws-lambda-go/lambda"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
 	"github.com/spf13/cobra"
 )
 
 func runServer(cmd *cobra.Command, args []string) {
 	log.Info("Lambda server starting...")
-	handler := server(false)
+	// The metrics can't be scraped from a lambda, a snapshot is logged periodically instead
+	handler := telemetry.SnapshotMiddleware(server(false), telemetry.DefaultSnapshotInterval)
 
 	lambdaHandler := httpadapter.New(handler)
 
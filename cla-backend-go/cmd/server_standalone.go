@@ -6,6 +6,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
@@ -13,6 +14,7 @@ import (
 	"syscall"
 
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -21,13 +23,22 @@ import (
 func runServer(cmd *cobra.Command, args []string) {
 	log.Info("Staring the HTTP server in local mode...")
 
-	handler := server(true)
-
-	errs := make(chan error, 2)
+	errs := make(chan error, 3)
 	go func() {
 		log.Infof("Running http server on port: %d - set PORT environment variable to change port", viper.GetInt("PORT"))
-		errs <- http.ListenAndServe(fmt.Sprintf(":%d", viper.GetInt("PORT")), handler)
+		errs <- http.ListenAndServe(fmt.Sprintf(":%d", viper.GetInt("PORT")), server(true))
 	}()
+
+	// The Prometheus metrics are not part of the API, they are served on an internal port which is not exposed
+	// with the API port
+	if metricsPort := viper.GetInt("METRICS_PORT"); metricsPort > 0 {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("/metrics", telemetry.Handler())
+		go func() {
+			log.Infof("Running metrics server on port: %d - set METRICS_PORT environment variable to change port", metricsPort)
+			errs <- http.ListenAndServe(fmt.Sprintf(":%d", metricsPort), metricsMux)
+		}()
+	}
 	go func() {
 		c := make(chan os.Signal)
 		signal.Notify(c, syscall.SIGINT) // nolint
@@ -35,4 +46,7 @@ func runServer(cmd *cobra.Command, args []string) {
 	}()
 
 	log.Infof("HTTP Server terminated - errors: %v", <-errs)
+	if err := shutdownTracing(context.Background()); err != nil {
+		log.Warnf("unable to flush the traces - error: %v", err)
+	}
 }
@@ -5,6 +5,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
+	awslambda "github.com/aws/aws-sdk-go/service/lambda"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 )
 
@@ -39,6 +41,7 @@ type BuildZipEvent struct {
 }
 
 var zipBuilder signatures.ZipBuilder
+var lambdaClient *awslambda.Lambda
 
 func init() {
 	var awsSession = session.Must(session.NewSession(&aws.Config{}))
@@ -52,24 +55,55 @@ func init() {
 		log.Fatal("CLA_SIGNATURE_FILES_BUCKET is not set in environment")
 	}
 	log.Infof("CLA_SIGNATURE_FILES_BUCKET : %s", signaturesFileBucket)
-	zipBuilder = signatures.NewZipBuilder(awsSession, signaturesFileBucket)
+	zipBuilder = signatures.NewZipBuilder(awsSession, stage, signaturesFileBucket)
+	lambdaClient = awslambda.New(awsSession)
 }
 
 func handler(ctx context.Context, event BuildZipEvent) error {
+	var result *signatures.BuildZipResult
 	var err error
 	log.WithField("event", event).Debug("zip builder called")
 	switch event.SignatureType {
 	case signatures.ICLA:
-		err = zipBuilder.BuildICLAZip(event.ClaGroupID)
+		result, err = zipBuilder.BuildICLAZip(ctx, event.ClaGroupID)
 	case signatures.CCLA:
-		err = zipBuilder.BuildCCLAZip(event.ClaGroupID)
+		result, err = zipBuilder.BuildCCLAZip(ctx, event.ClaGroupID)
 	default:
 		log.WithField("event", event).Debug("Invalid event")
+		return nil
 	}
 	if err != nil {
 		log.WithField("args", event).Error("failed to build zip", err)
+		return err
+	}
+	log.WithField("args", event).Debugf("added %d files, %d files in zip", result.FilesAdded, result.FilesInZip)
+	// the zip saved so far is valid, another invocation resumes from it - unless this one made no progress
+	if !result.Complete && result.FilesAdded > 0 {
+		resume(event)
+	}
+	return nil
+}
+
+// resume invokes the lambda asynchronously to add the remaining files
+func resume(event BuildZipEvent) {
+	functionName := os.Getenv("AWS_LAMBDA_FUNCTION_NAME")
+	if functionName == "" {
+		log.WithField("event", event).Info("zip is incomplete, run the zip builder again to add the remaining files")
+		return
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Error("Error marshalling BuildZip request", err)
+		return
+	}
+	_, err = lambdaClient.Invoke(&awslambda.InvokeInput{
+		FunctionName:   aws.String(functionName),
+		InvocationType: aws.String(awslambda.InvocationTypeEvent),
+		Payload:        payload,
+	})
+	if err != nil {
+		log.WithField("event", event).Error("unable to resume zip build", err)
 	}
-	return err
 }
 
 func printBuildInfo() {
@@ -19,6 +19,7 @@ import (
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
 
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -73,6 +74,9 @@ func NewRepository(awsSession *session.Session, stage string) IRepository {
 
 // GetCompanies retrieves all the companies
 func (repo repository) GetCompanies(ctx context.Context) (*models.Companies, error) {
+	ctx, span := telemetry.StartSpan(ctx, "company.repository.GetCompanies")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "company.repository.GetCompanies",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -99,7 +103,7 @@ func (repo repository) GetCompanies(ctx context.Context) (*models.Companies, err
 	// Loop until we have all the records
 	for ok := true; ok; ok = lastEvaluatedKey != "" {
 		// Make the DynamoDB Query API call
-		results, dbErr := repo.dynamoDBClient.Scan(scanInput)
+		results, dbErr := repo.dynamoDBClient.ScanWithContext(ctx, scanInput)
 		if dbErr != nil {
 			log.WithFields(f).Warnf("error retrieving get all companies, error: %v", dbErr)
 			return nil, dbErr
@@ -151,6 +155,9 @@ func (repo repository) GetCompanies(ctx context.Context) (*models.Companies, err
 
 // GetCompanyByExternalID returns a company based on the company external ID
 func (repo repository) GetCompanyByExternalID(ctx context.Context, companySFID string) (*models.Company, error) {
+	ctx, span := telemetry.StartSpan(ctx, "company.repository.GetCompanyByExternalID")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "company.repository.GetCompanyByExternalID",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -178,6 +185,9 @@ func (repo repository) GetCompanyByExternalID(ctx context.Context, companySFID s
 
 // GetCompaniesByExternalID returns a list of companies based on the company external ID. A company will have more than one if/when the SF record has multiple entity names - for which we create separate EasyCLA company records
 func (repo repository) GetCompaniesByExternalID(ctx context.Context, companySFID string, includeChildCompanies bool) ([]*models.Company, error) {
+	ctx, span := telemetry.StartSpan(ctx, "company.repository.GetCompaniesByExternalID")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":          "company.repository.GetCompaniesByExternalID",
 		utils.XREQUESTID:        ctx.Value(utils.XREQUESTID),
@@ -205,7 +215,7 @@ func (repo repository) GetCompaniesByExternalID(ctx context.Context, companySFID
 		IndexName:                 aws.String("external-company-index"),
 	}
 
-	results, err := repo.dynamoDBClient.Query(queryInput)
+	results, err := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).WithError(err).Warn("error retrieving company using company_external_id")
 		return nil, err
@@ -232,6 +242,9 @@ func (repo repository) GetCompaniesByExternalID(ctx context.Context, companySFID
 
 // GetCompanyBySigningEntityName search the company by signing entity name
 func (repo repository) GetCompanyBySigningEntityName(ctx context.Context, signingEntityName string) (*models.Company, error) {
+	ctx, span := telemetry.StartSpan(ctx, "company.repository.GetCompanyBySigningEntityName")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":      "company.repository.GetCompanyBySigningEntityName",
 		utils.XREQUESTID:    ctx.Value(utils.XREQUESTID),
@@ -256,7 +269,7 @@ func (repo repository) GetCompanyBySigningEntityName(ctx context.Context, signin
 		IndexName:                 aws.String("company-signing-entity-name-index"),
 	}
 
-	results, err := repo.dynamoDBClient.Query(queryInput)
+	results, err := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).Warnf("error retrieving company using signing_entity_name. error = %s", err.Error())
 		return nil, err
@@ -281,6 +294,9 @@ func (repo repository) GetCompanyBySigningEntityName(ctx context.Context, signin
 
 // GetCompanyByName searches the database and returns the matching company names
 func (repo repository) GetCompanyByName(ctx context.Context, companyName string) (*models.Company, error) {
+	ctx, span := telemetry.StartSpan(ctx, "company.repository.GetCompanyByName")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "company.repository.GetCompanyByName",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -308,7 +324,7 @@ func (repo repository) GetCompanyByName(ctx context.Context, companyName string)
 	}
 
 	// Make the DynamoDB Query API call
-	results, queryErr := repo.dynamoDBClient.Query(queryInput)
+	results, queryErr := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if queryErr != nil {
 		log.WithFields(f).Warnf("error retrieving company by companyName: %s, error: %+v", companyName, queryErr)
 		return nil, queryErr
@@ -335,12 +351,15 @@ func (repo repository) GetCompanyByName(ctx context.Context, companyName string)
 
 // GetCompany returns a company based on the company ID
 func (repo repository) GetCompany(ctx context.Context, companyID string) (*models.Company, error) {
+	ctx, span := telemetry.StartSpan(ctx, "company.repository.GetCompany")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "company.repository.GetCompany",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
 		"companyID":      companyID,
 	}
-	companyTableData, err := repo.dynamoDBClient.GetItem(&dynamodb.GetItemInput{
+	companyTableData, err := repo.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(repo.companyTableName),
 		Key: map[string]*dynamodb.AttributeValue{
 			"company_id": {
@@ -373,6 +392,9 @@ func (repo repository) GetCompany(ctx context.Context, companyID string) (*model
 
 // SearchCompanyByName locates companies by the matching name and return any potential matches
 func (repo repository) SearchCompanyByName(ctx context.Context, companyName string, nextKey string) (*models.Companies, error) {
+	ctx, span := telemetry.StartSpan(ctx, "company.repository.SearchCompanyByName")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "company.repository.SearchCompanyByName",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -431,7 +453,7 @@ func (repo repository) SearchCompanyByName(ctx context.Context, companyName stri
 	// Loop until we have all the records
 	for ok := true; ok; ok = lastEvaluatedKey != "" {
 		// Make the DynamoDB Query API call
-		results, dbErr := repo.dynamoDBClient.Scan(scanInput)
+		results, dbErr := repo.dynamoDBClient.ScanWithContext(ctx, scanInput)
 		if dbErr != nil {
 			log.Warnf("error retrieving companies for search term: %s, error: %v", companyName, dbErr)
 			return nil, dbErr
@@ -483,13 +505,16 @@ func (repo repository) SearchCompanyByName(ctx context.Context, companyName stri
 
 // DeleteCompanyByID deletes the company by ID
 func (repo repository) DeleteCompanyByID(ctx context.Context, companyID string) error {
+	ctx, span := telemetry.StartSpan(ctx, "company.repository.DeleteCompanyByID")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "company.repository.DeleteCompanyByID",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
 		"companyID":      companyID,
 	}
 	log.WithFields(f).Debug("deleting company by ID")
-	_, err := repo.dynamoDBClient.DeleteItem(&dynamodb.DeleteItemInput{
+	_, err := repo.dynamoDBClient.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
 		Key: map[string]*dynamodb.AttributeValue{
 			"company_id": {S: aws.String(companyID)},
 		},
@@ -505,6 +530,9 @@ func (repo repository) DeleteCompanyByID(ctx context.Context, companyID string)
 
 // DeleteCompanyBySFID deletes the company by SFID
 func (repo repository) DeleteCompanyBySFID(ctx context.Context, companySFID string) error {
+	ctx, span := telemetry.StartSpan(ctx, "company.repository.DeleteCompanyBySFID")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "company.repository.DeleteCompanyBySFID",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -512,7 +540,7 @@ func (repo repository) DeleteCompanyBySFID(ctx context.Context, companySFID stri
 	}
 
 	log.WithFields(f).Debug("deleting company by SFID...")
-	_, err := repo.dynamoDBClient.DeleteItem(&dynamodb.DeleteItemInput{
+	_, err := repo.dynamoDBClient.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
 		Key: map[string]*dynamodb.AttributeValue{
 			"company_external_id": {S: aws.String(companySFID)},
 		},
@@ -528,6 +556,9 @@ func (repo repository) DeleteCompanyBySFID(ctx context.Context, companySFID stri
 
 // GetCompanyUserManager the get a list of companies when provided the company id and user manager
 func (repo repository) GetCompaniesByUserManager(ctx context.Context, userID string, userModel user.User) (*models.Companies, error) {
+	ctx, span := telemetry.StartSpan(ctx, "company.repository.GetCompaniesByUserManager")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "company.repository.GetCompaniesByUserManager",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -584,7 +615,7 @@ func (repo repository) GetCompaniesByUserManager(ctx context.Context, userID str
 	// Loop until we have all the records
 	for ok := true; ok; ok = lastEvaluatedKey != "" {
 		// Make the DynamoDB Query API call
-		results, dbErr := repo.dynamoDBClient.Scan(scanInput)
+		results, dbErr := repo.dynamoDBClient.ScanWithContext(ctx, scanInput)
 		if dbErr != nil {
 			log.WithFields(f).Warnf("error retrieving companies for userID %s in ACL, error: %v", userID, dbErr)
 			return nil, dbErr
@@ -636,6 +667,9 @@ func (repo repository) GetCompaniesByUserManager(ctx context.Context, userID str
 
 // GetCompanyUserManagerWithInvites the get a list of companies including status when provided the company id and user manager
 func (repo repository) GetCompaniesByUserManagerWithInvites(ctx context.Context, userID string, userModel user.User) (*models.CompaniesWithInvites, error) {
+	ctx, span := telemetry.StartSpan(ctx, "company.repository.GetCompaniesByUserManagerWithInvites")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "company.repository.GetCompaniesByUserManagerWithInvites",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -656,10 +690,11 @@ func (repo repository) GetCompaniesByUserManagerWithInvites(ctx context.Context,
 		return nil, err
 	}
 
-	return repo.buildCompaniesByUserManagerWithInvites(ctx, companies, invites), nil
+	return buildCompaniesByUserManagerWithInvites(ctx, repo, companies, invites), nil
 }
 
-func (repo repository) buildCompaniesByUserManagerWithInvites(ctx context.Context, companies *models.Companies, invites []Invite) *models.CompaniesWithInvites {
+// buildCompaniesByUserManagerWithInvites merges the managed companies and the user's invites into a single response model
+func buildCompaniesByUserManagerWithInvites(ctx context.Context, repo IRepository, companies *models.Companies, invites []Invite) *models.CompaniesWithInvites {
 	f := logrus.Fields{
 		"functionName":   "company.repository.buildCompaniesByUserManagerWithInvites",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -773,6 +808,9 @@ func buildCompanyModels(ctx context.Context, results *dynamodb.ScanOutput) ([]mo
 
 // GetCompanyInviteRequest returns the specified request
 func (repo repository) GetCompanyInviteRequest(ctx context.Context, companyInviteID string) (*Invite, error) {
+	ctx, span := telemetry.StartSpan(ctx, "company.repository.GetCompanyInviteRequest")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":    "company.repository.GetCompanyInviteRequest",
 		utils.XREQUESTID:  ctx.Value(utils.XREQUESTID),
@@ -799,7 +837,7 @@ func (repo repository) GetCompanyInviteRequest(ctx context.Context, companyInvit
 		TableName:                 aws.String(repo.companyInvitesTableName),
 	}
 
-	queryResults, err := repo.dynamoDBClient.Query(queryInput)
+	queryResults, err := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).Warnf("Unable to query the company invite based on invite ID: %s, error: %v", companyInviteID, err)
 		return nil, err
@@ -821,6 +859,9 @@ func (repo repository) GetCompanyInviteRequest(ctx context.Context, companyInvit
 
 // GetCompanyInviteRequests returns a list of company invites when provided the company ID
 func (repo repository) GetCompanyInviteRequests(ctx context.Context, companyID string, status *string) ([]Invite, error) {
+	ctx, span := telemetry.StartSpan(ctx, "company.repository.GetCompanyInviteRequests")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "company.repository.GetCompanyInviteRequests",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -858,7 +899,7 @@ func (repo repository) GetCompanyInviteRequests(ctx context.Context, companyID s
 		IndexName:                 aws.String("requested-company-index"), // Name of a secondary index
 	}
 
-	companyInviteAV, err := repo.dynamoDBClient.Query(queryInput)
+	companyInviteAV, err := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).Warnf("Unable to retrieve data from Company-Invites table, error: %v", err)
 		return nil, err
@@ -876,6 +917,9 @@ func (repo repository) GetCompanyInviteRequests(ctx context.Context, companyID s
 
 // GetCompanyUserInviteRequests returns a list of company invites when provided the company ID and user ID
 func (repo repository) GetCompanyUserInviteRequests(ctx context.Context, companyID string, userID string) (*Invite, error) {
+	ctx, span := telemetry.StartSpan(ctx, "company.repository.GetCompanyUserInviteRequests")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "company.repository.GetCompanyUserInviteRequests",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -909,7 +953,7 @@ func (repo repository) GetCompanyUserInviteRequests(ctx context.Context, company
 		IndexName:                 aws.String("requested-company-index"), // Name of a secondary index
 	}
 
-	queryResults, err := repo.dynamoDBClient.Query(queryInput)
+	queryResults, err := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).Warnf("Unable to retrieve data from Company-Invites table using company id: %s and user id: %s, error: %v", companyID, userID, err)
 		return nil, err
@@ -938,6 +982,9 @@ func (repo repository) GetCompanyUserInviteRequests(ctx context.Context, company
 
 // GetUserInviteRequests returns a list of company invites when provided the user ID
 func (repo repository) GetUserInviteRequests(ctx context.Context, userID string) ([]Invite, error) {
+	ctx, span := telemetry.StartSpan(ctx, "company.repository.GetUserInviteRequests")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "company.repository.GetUserInviteRequests",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -970,7 +1017,7 @@ func (repo repository) GetUserInviteRequests(ctx context.Context, userID string)
 	// Loop until we have all the records
 	for ok := true; ok; ok = lastEvaluatedKey != "" {
 
-		queryResults, err := repo.dynamoDBClient.Scan(scanInput)
+		queryResults, err := repo.dynamoDBClient.ScanWithContext(ctx, scanInput)
 		if err != nil {
 			log.WithFields(f).Warnf("Unable to retrieve data from Company-Invites table using user id: %s, error: %v", userID, err)
 			return nil, err
@@ -1005,6 +1052,9 @@ func (repo repository) GetUserInviteRequests(ctx context.Context, userID string)
 
 // AddPendingCompanyInviteRequest adds a pending company invite when provided the company ID and user ID
 func (repo repository) AddPendingCompanyInviteRequest(ctx context.Context, companyID string, userModel user.User) (*Invite, error) {
+	ctx, span := telemetry.StartSpan(ctx, "company.repository.AddPendingCompanyInviteRequest")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":       "company.repository.AddPendingCompanyInviteRequest",
 		utils.XREQUESTID:     ctx.Value(utils.XREQUESTID),
@@ -1090,7 +1140,7 @@ func (repo repository) AddPendingCompanyInviteRequest(ctx context.Context, compa
 		TableName: aws.String(fmt.Sprintf("cla-%s-company-invites", repo.stage)),
 	}
 
-	_, err = repo.dynamoDBClient.PutItem(input)
+	_, err = repo.dynamoDBClient.PutItemWithContext(ctx, input)
 	if err != nil {
 		log.WithFields(f).Warnf("Unable to create a new pending invite, error: %v", err)
 		return nil, err
@@ -1108,11 +1158,17 @@ func (repo repository) AddPendingCompanyInviteRequest(ctx context.Context, compa
 
 // ApproveCompanyAccessRequest approves the specified company invite
 func (repo repository) ApproveCompanyAccessRequest(ctx context.Context, companyInviteID string) error {
+	ctx, span := telemetry.StartSpan(ctx, "company.repository.ApproveCompanyAccessRequest")
+	defer span.End()
+
 	return repo.updateInviteRequestStatus(ctx, companyInviteID, "approved")
 }
 
 // RejectCompanyInviteRequest rejects the specified company invite
 func (repo repository) RejectCompanyAccessRequest(ctx context.Context, companyInviteID string) error {
+	ctx, span := telemetry.StartSpan(ctx, "company.repository.RejectCompanyAccessRequest")
+	defer span.End()
+
 	return repo.updateInviteRequestStatus(ctx, companyInviteID, "rejected")
 }
 
@@ -1163,7 +1219,7 @@ func (repo repository) updateInviteRequestStatus(ctx context.Context, companyInv
 		TableName:        aws.String(fmt.Sprintf("cla-%s-company-invites", repo.stage)),
 	}
 
-	_, updateErr := repo.dynamoDBClient.UpdateItem(input)
+	_, updateErr := repo.dynamoDBClient.UpdateItemWithContext(ctx, input)
 	if updateErr != nil {
 		log.WithFields(f).Warnf("ApproveCompanyAccessRequest - unable to update request with approved status, error: %v",
 			updateErr)
@@ -1175,6 +1231,9 @@ func (repo repository) updateInviteRequestStatus(ctx context.Context, companyInv
 
 // UpdateCompanyAccessList updates the company ACL when provided the company ID and ACL list
 func (repo repository) UpdateCompanyAccessList(ctx context.Context, companyID string, companyACL []string) error {
+	ctx, span := telemetry.StartSpan(ctx, "company.repository.UpdateCompanyAccessList")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "company.repository.UpdateCompanyAccessList",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -1205,7 +1264,7 @@ func (repo repository) UpdateCompanyAccessList(ctx context.Context, companyID st
 		UpdateExpression: aws.String("SET #S = :s, #M = :m"),
 	}
 
-	_, err := repo.dynamoDBClient.UpdateItem(input)
+	_, err := repo.dynamoDBClient.UpdateItemWithContext(ctx, input)
 	if err != nil {
 		log.WithFields(f).Warnf("Error updating Company Access List, error: %v", err)
 		return err
@@ -1216,6 +1275,9 @@ func (repo repository) UpdateCompanyAccessList(ctx context.Context, companyID st
 
 // CreateCompany creates a new company record
 func (repo repository) CreateCompany(ctx context.Context, in *models.Company) (*models.Company, error) {
+	ctx, span := telemetry.StartSpan(ctx, "company.repository.CreateCompany")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":      "company.repository.CreateCompany",
 		utils.XREQUESTID:    ctx.Value(utils.XREQUESTID),
@@ -1272,7 +1334,7 @@ func (repo repository) CreateCompany(ctx context.Context, in *models.Company) (*
 		log.WithFields(f).WithError(err).Warnf("problem marshing company record")
 		return nil, err
 	}
-	_, err = repo.dynamoDBClient.PutItem(&dynamodb.PutItemInput{
+	_, err = repo.dynamoDBClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
 		Item:      av,
 		TableName: aws.String(repo.companyTableName),
 	})
@@ -0,0 +1,389 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package company
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	"github.com/communitybridge/easycla/cla-backend-go/user"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/gofrs/uuid"
+)
+
+// memoryRepository is an embedded, in-memory implementation of the company IRepository. It is intended for local
+// development and tests where DynamoDB is not available.
+type memoryRepository struct {
+	lock      sync.RWMutex
+	companies map[string]DBModel
+	invites   map[string]Invite
+}
+
+// NewMemoryRepository creates a new instance of the in-memory company repository
+func NewMemoryRepository() IRepository {
+	return &memoryRepository{
+		companies: map[string]DBModel{},
+		invites:   map[string]Invite{},
+	}
+}
+
+// CreateCompany creates a new company record
+func (repo *memoryRepository) CreateCompany(ctx context.Context, in *models.Company) (*models.Company, error) {
+	// Don't create duplicates - check to see if any exist
+	existingModel, err := repo.GetCompanyByName(ctx, in.CompanyName)
+	if err != nil || existingModel != nil {
+		return existingModel, err
+	}
+
+	companyID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	_, now := utils.CurrentTime()
+	comp := DBModel{
+		CompanyID:         companyID.String(),
+		CompanyName:       in.CompanyName,
+		CompanyExternalID: in.CompanyExternalID,
+		SigningEntityName: in.SigningEntityName,
+		CompanyACL:        copyStrings(in.CompanyACL),
+		CompanyManagerID:  in.CompanyManagerID,
+		Note:              in.Note,
+		Created:           now,
+		Updated:           now,
+		Version:           "v1",
+	}
+	// Use the company name if signing entity name is not provided
+	if in.SigningEntityName == "" {
+		comp.SigningEntityName = in.CompanyName
+	}
+
+	repo.lock.Lock()
+	repo.companies[comp.CompanyID] = comp
+	repo.lock.Unlock()
+
+	return comp.toModel()
+}
+
+// GetCompanies retrieves all the companies
+func (repo *memoryRepository) GetCompanies(ctx context.Context) (*models.Companies, error) {
+	return repo.companyList(func(c *DBModel) bool {
+		return true
+	}, "")
+}
+
+// GetCompany returns a company based on the company ID
+func (repo *memoryRepository) GetCompany(ctx context.Context, companyID string) (*models.Company, error) {
+	repo.lock.RLock()
+	comp, ok := repo.companies[companyID]
+	repo.lock.RUnlock()
+	if !ok {
+		return nil, &utils.CompanyNotFound{
+			Message:   "no company matching company record",
+			CompanyID: companyID,
+		}
+	}
+	return comp.toModel()
+}
+
+// GetCompanyByExternalID returns a company based on the company external ID
+func (repo *memoryRepository) GetCompanyByExternalID(ctx context.Context, companySFID string) (*models.Company, error) {
+	companyRecords, err := repo.GetCompaniesByExternalID(ctx, companySFID, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(companyRecords) == 0 {
+		return nil, &utils.CompanyNotFound{
+			Message:   "no company records found for SFID",
+			CompanyID: companySFID,
+		}
+	}
+	return companyRecords[0], nil
+}
+
+// GetCompaniesByExternalID returns a list of companies based on the company external ID
+func (repo *memoryRepository) GetCompaniesByExternalID(ctx context.Context, companySFID string, includeChildCompanies bool) ([]*models.Company, error) {
+	dbModels := repo.query(func(c *DBModel) bool {
+		return c.CompanyExternalID == companySFID
+	})
+	if len(dbModels) == 0 {
+		return nil, &utils.CompanyNotFound{
+			Message:     "no company records found with matching external SFID",
+			CompanySFID: companySFID,
+		}
+	}
+	return dbModelsToResponseModels(ctx, dbModels, includeChildCompanies)
+}
+
+// GetCompanyBySigningEntityName search the company by signing entity name
+func (repo *memoryRepository) GetCompanyBySigningEntityName(ctx context.Context, signingEntityName string) (*models.Company, error) {
+	dbModels := repo.query(func(c *DBModel) bool {
+		return c.SigningEntityName == signingEntityName
+	})
+	if len(dbModels) == 0 {
+		return nil, &utils.CompanyNotFound{
+			Message:                  "no company with signing entity name found",
+			CompanySigningEntityName: signingEntityName,
+		}
+	}
+	return dbModels[0].toModel()
+}
+
+// GetCompanyByName returns the company with the matching name or nil if not found
+func (repo *memoryRepository) GetCompanyByName(ctx context.Context, companyName string) (*models.Company, error) {
+	dbModels := repo.query(func(c *DBModel) bool {
+		return c.CompanyName == companyName
+	})
+	if len(dbModels) == 0 {
+		return nil, nil
+	}
+	return toSwaggerModel(&dbModels[0])
+}
+
+// SearchCompanyByName locates companies by the matching name and return any potential matches
+func (repo *memoryRepository) SearchCompanyByName(ctx context.Context, companyName string, nextKey string) (*models.Companies, error) {
+	if strings.TrimSpace(companyName) == "" {
+		return &models.Companies{
+			Companies:   []models.Company{},
+			SearchTerms: companyName,
+		}, nil
+	}
+	return repo.companyList(func(c *DBModel) bool {
+		return strings.Contains(c.CompanyName, companyName)
+	}, nextKey)
+}
+
+// DeleteCompanyByID deletes the company by ID
+func (repo *memoryRepository) DeleteCompanyByID(ctx context.Context, companyID string) error {
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	delete(repo.companies, companyID)
+	return nil
+}
+
+// DeleteCompanyBySFID deletes the company by SFID
+func (repo *memoryRepository) DeleteCompanyBySFID(ctx context.Context, companySFID string) error {
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	for companyID, comp := range repo.companies {
+		if comp.CompanyExternalID == companySFID {
+			delete(repo.companies, companyID)
+		}
+	}
+	return nil
+}
+
+// GetCompaniesByUserManager returns the list of companies where the user is in the company ACL
+func (repo *memoryRepository) GetCompaniesByUserManager(ctx context.Context, userID string, userModel user.User) (*models.Companies, error) {
+	userName := userModel.LFUsername
+	if userName == "" {
+		userName = userModel.UserName
+	}
+	if strings.TrimSpace(userID) == "" || userName == "" {
+		return &models.Companies{
+			Companies: []models.Company{},
+		}, nil
+	}
+	return repo.companyList(func(c *DBModel) bool {
+		return utils.StringInSlice(userName, c.CompanyACL)
+	}, "")
+}
+
+// GetCompaniesByUserManagerWithInvites returns the list of companies including the invite status for the user
+func (repo *memoryRepository) GetCompaniesByUserManagerWithInvites(ctx context.Context, userID string, userModel user.User) (*models.CompaniesWithInvites, error) {
+	companies, err := repo.GetCompaniesByUserManager(ctx, userID, userModel)
+	if err != nil {
+		return nil, err
+	}
+	invites, err := repo.GetUserInviteRequests(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return buildCompaniesByUserManagerWithInvites(ctx, repo, companies, invites), nil
+}
+
+// AddPendingCompanyInviteRequest adds a pending company invite when provided the company ID and user ID
+func (repo *memoryRepository) AddPendingCompanyInviteRequest(ctx context.Context, companyID string, userModel user.User) (*Invite, error) {
+	previousInvite, err := repo.GetCompanyUserInviteRequests(ctx, companyID, userModel.UserID)
+	if err != nil {
+		return nil, err
+	}
+	// We we already have an invite...don't create another one
+	if previousInvite != nil {
+		if previousInvite.Status == "rejected" {
+			if updateErr := repo.updateInviteRequestStatus(ctx, previousInvite.CompanyInviteID, "pending"); updateErr != nil {
+				return nil, updateErr
+			}
+		}
+		return previousInvite, nil
+	}
+
+	companyInviteID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	_, now := utils.CurrentTime()
+	invite := Invite{
+		CompanyInviteID:    companyInviteID.String(),
+		RequestedCompanyID: companyID,
+		UserID:             userModel.UserID,
+		Status:             "pending",
+		Created:            now,
+		Updated:            now,
+	}
+
+	repo.lock.Lock()
+	repo.invites[invite.CompanyInviteID] = invite
+	repo.lock.Unlock()
+
+	return &invite, nil
+}
+
+// GetCompanyInviteRequest returns the specified request
+func (repo *memoryRepository) GetCompanyInviteRequest(ctx context.Context, companyInviteID string) (*Invite, error) {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	invite, ok := repo.invites[companyInviteID]
+	if !ok {
+		return nil, nil
+	}
+	return &invite, nil
+}
+
+// GetCompanyInviteRequests returns a list of company invites when provided the company ID
+func (repo *memoryRepository) GetCompanyInviteRequests(ctx context.Context, companyID string, status *string) ([]Invite, error) {
+	return repo.queryInvites(func(i *Invite) bool {
+		return i.RequestedCompanyID == companyID && (status == nil || i.Status == *status)
+	}), nil
+}
+
+// GetCompanyUserInviteRequests returns the company invite when provided the company ID and user ID
+func (repo *memoryRepository) GetCompanyUserInviteRequests(ctx context.Context, companyID string, userID string) (*Invite, error) {
+	invites := repo.queryInvites(func(i *Invite) bool {
+		return i.RequestedCompanyID == companyID && i.UserID == userID
+	})
+	if len(invites) == 0 {
+		return nil, nil
+	}
+	return &invites[0], nil
+}
+
+// GetUserInviteRequests returns a list of company invites when provided the user ID
+func (repo *memoryRepository) GetUserInviteRequests(ctx context.Context, userID string) ([]Invite, error) {
+	return repo.queryInvites(func(i *Invite) bool {
+		return i.UserID == userID
+	}), nil
+}
+
+// ApproveCompanyAccessRequest approves the specified company invite
+func (repo *memoryRepository) ApproveCompanyAccessRequest(ctx context.Context, companyInviteID string) error {
+	return repo.updateInviteRequestStatus(ctx, companyInviteID, "approved")
+}
+
+// RejectCompanyAccessRequest rejects the specified company invite
+func (repo *memoryRepository) RejectCompanyAccessRequest(ctx context.Context, companyInviteID string) error {
+	return repo.updateInviteRequestStatus(ctx, companyInviteID, "rejected")
+}
+
+// updateInviteRequestStatus updates the specified invite with the specified status
+func (repo *memoryRepository) updateInviteRequestStatus(ctx context.Context, companyInviteID, status string) error {
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	invite, ok := repo.invites[companyInviteID]
+	if !ok {
+		return nil
+	}
+	_, now := utils.CurrentTime()
+	invite.Status = status
+	invite.Updated = now
+	repo.invites[companyInviteID] = invite
+	return nil
+}
+
+// UpdateCompanyAccessList updates the company ACL when provided the company ID and ACL list
+func (repo *memoryRepository) UpdateCompanyAccessList(ctx context.Context, companyID string, companyACL []string) error {
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	comp, ok := repo.companies[companyID]
+	if !ok {
+		return &utils.CompanyNotFound{
+			Message:   "no company matching company record",
+			CompanyID: companyID,
+		}
+	}
+	_, now := utils.CurrentTime()
+	comp.CompanyACL = copyStrings(companyACL)
+	comp.Updated = now
+	repo.companies[companyID] = comp
+	return nil
+}
+
+// query returns the companies matching the filter, ordered by company ID
+func (repo *memoryRepository) query(match func(c *DBModel) bool) []DBModel {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	var dbModels []DBModel
+	for _, comp := range repo.companies {
+		if match(&comp) {
+			comp.CompanyACL = copyStrings(comp.CompanyACL)
+			dbModels = append(dbModels, comp)
+		}
+	}
+	sort.Slice(dbModels, func(i, j int) bool {
+		return dbModels[i].CompanyID < dbModels[j].CompanyID
+	})
+	return dbModels
+}
+
+// companyList returns the companies matching the filter which follow the next key company ID
+func (repo *memoryRepository) companyList(match func(c *DBModel) bool, nextKey string) (*models.Companies, error) {
+	companies := []models.Company{}
+	for _, dbModel := range repo.query(match) {
+		if nextKey != "" && dbModel.CompanyID <= nextKey {
+			continue
+		}
+		comp, err := dbModel.toModel()
+		if err != nil {
+			return nil, err
+		}
+		companies = append(companies, *comp)
+	}
+
+	repo.lock.RLock()
+	totalCount := int64(len(repo.companies))
+	repo.lock.RUnlock()
+
+	return &models.Companies{
+		ResultCount: int64(len(companies)),
+		TotalCount:  totalCount,
+		Companies:   companies,
+	}, nil
+}
+
+// queryInvites returns the invites matching the filter, ordered by invite ID
+func (repo *memoryRepository) queryInvites(match func(i *Invite) bool) []Invite {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	var invites []Invite
+	for _, invite := range repo.invites {
+		if match(&invite) {
+			invites = append(invites, invite)
+		}
+	}
+	sort.Slice(invites, func(i, j int) bool {
+		return invites[i].CompanyInviteID < invites[j].CompanyInviteID
+	})
+	return invites
+}
+
+// copyStrings returns a copy of the list so callers can't modify the stored record
+func copyStrings(in []string) []string {
+	if in == nil {
+		return nil
+	}
+	return append([]string{}, in...)
+}
@@ -13,6 +13,34 @@ import (
 
 var easyCLAConfig Config
 
+// Storage backends
+const (
+	// StorageBackendDynamoDB stores the records in the stage DynamoDB tables - the default
+	StorageBackendDynamoDB = "dynamodb"
+	// StorageBackendMemory stores the records in process memory - for local development and tests only
+	StorageBackendMemory = "memory"
+)
+
+// Email senders
+const (
+	// EmailSenderSNS publishes the emails to the SNS event topic - the default
+	EmailSenderSNS = "sns"
+	// EmailSenderSMTP sends the emails through the configured SMTP server
+	EmailSenderSMTP = "smtp"
+	// EmailSenderFile writes the emails to the capture directory - for local development and tests only
+	EmailSenderFile = "file"
+)
+
+// SMTP TLS modes
+const (
+	// SMTPTLSModeNone sends the emails over a plain connection
+	SMTPTLSModeNone = "none"
+	// SMTPTLSModeStartTLS upgrades the plain connection with STARTTLS - the default
+	SMTPTLSModeStartTLS = "starttls"
+	// SMTPTLSModeTLS connects using implicit TLS, typically on port 465
+	SMTPTLSModeTLS = "tls"
+)
+
 // Config data model
 type Config struct {
 	// Auth0
@@ -51,12 +79,22 @@ type Config struct {
 	// GitHub Application
 	GitHub GitHub `json:"github"`
 
+	// GitLab
+	GitLab GitLab `json:"gitlab"`
+
 	// Dynamo Session Store
 	SessionStoreTableName string `json:"sessionStoreTableName"`
 
 	// Sender Email Address
 	SenderEmailAddress string `json:"senderEmailAddress"`
 
+	// EmailSender selects the email transport - one of sns (default), smtp or file
+	EmailSender string `json:"email_sender"`
+	// SMTP is the server used when the email sender is smtp
+	SMTP SMTP `json:"smtp"`
+	// EmailCaptureDir is the directory the emails are written to when the email sender is file
+	EmailCaptureDir string `json:"email_capture_dir"`
+
 	AllowedOriginsCommaSeparated string   `json:"allowedOriginsCommaSeparated"`
 	AllowedOrigins               []string `json:"-"`
 
@@ -73,6 +111,10 @@ type Config struct {
 	// LF Group
 	LFGroup LFGroup `json:"lf_group"`
 
+	// GerritAccounts are the accounts managing the internal groups of the gerrit instances using the gerrit group sync
+	// backend, keyed by the gerrit host
+	GerritAccounts map[string]GerritAccount `json:"gerrit_accounts"`
+
 	// CLAV1ApiURL is api url of v1. it is used in v2 sign service
 	ClaV1ApiURL string `json:"cla_v1_api_url"`
 
@@ -84,6 +126,18 @@ type Config struct {
 
 	// MetricsReport has the transport config to send the metrics data
 	MetricsReport MetricsReport `json:"metrics_report"`
+
+	// StorageBackend selects the signature, company, project, user and event repository implementation - one of dynamodb (default) or memory
+	StorageBackend string `json:"storage_backend"`
+
+	// Tracing configures the OpenTelemetry trace export
+	Tracing Tracing `json:"tracing"`
+
+	// EventChainSigningKey is the base64 encoded ed25519 seed signing the event chain checkpoint exports
+	EventChainSigningKey string `json:"event_chain_signing_key"`
+
+	// OIDCIssuers are the OIDC issuers whose tokens are trusted in addition to the Auth0 ones
+	OIDCIssuers []OIDCIssuer `json:"oidc_issuers"`
 }
 
 // Auth0 model
@@ -94,6 +148,22 @@ type Auth0 struct {
 	Algorithm     string `json:"auth0-algorithm"`
 }
 
+// OIDCIssuer model - a trusted OIDC issuer, the signing keys are read from the discovery document unless the jwks_url is set.
+// At least one audience is required. The user names of the issuer are prefixed with the username_namespace, the issuer
+// without its scheme when empty, and its emails are used once the email_verified_claim is true.
+type OIDCIssuer struct {
+	Issuer             string   `json:"issuer"`
+	Audiences          []string `json:"audiences"`
+	Algorithms         []string `json:"algorithms"`
+	DiscoveryURL       string   `json:"discovery_url"`
+	JWKSURL            string   `json:"jwks_url"`
+	UsernameClaim      string   `json:"username_claim"`
+	NameClaim          string   `json:"name_claim"`
+	EmailClaim         string   `json:"email_claim"`
+	EmailVerifiedClaim string   `json:"email_verified_claim"`
+	UsernameNamespace  string   `json:"username_namespace"`
+}
+
 // Auth0Platform model
 type Auth0Platform struct {
 	ClientID     string `json:"auth0-clientId"`
@@ -116,6 +186,12 @@ type LFGroup struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+// GerritAccount contains the HTTP credentials of the EasyCLA account of a gerrit instance
+type GerritAccount struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
 // AWS model
 type AWS struct {
 	Region string `json:"region"`
@@ -134,6 +210,34 @@ type GitHub struct {
 	TestRepositoryID               string `json:"test_repository_id"`
 }
 
+// GitLab model
+type GitLab struct {
+	APIURL        string `json:"api_url"`
+	AccessToken   string `json:"access_token"`
+	WebhookSecret string `json:"webhook_secret"`
+	SignURL       string `json:"sign_url"`
+}
+
+// SMTP model
+type SMTP struct {
+	Host               string `json:"host"`
+	Port               int    `json:"port"`
+	Username           string `json:"username"`
+	Password           string `json:"password"`
+	TLSMode            string `json:"tls_mode"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify"`
+}
+
+// Tracing model - the traces are exported to the OTLP/HTTP collector when the endpoint is set
+type Tracing struct {
+	// OTLPEndpoint is the host:port of the collector, e.g. localhost:4318
+	OTLPEndpoint string `json:"otlp_endpoint"`
+	// Insecure sends the spans over plain HTTP, for a local collector
+	Insecure bool `json:"insecure"`
+	// SampleRatio is the ratio of the root traces sampled, all the traces are sampled when not set
+	SampleRatio float64 `json:"sample_ratio"`
+}
+
 // MetricsReport keeps the config needed to send the metrics data report
 type MetricsReport struct {
 	AwsSQSRegion   string `json:"aws_sqs_region"`
@@ -4,6 +4,7 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -232,5 +233,93 @@ func loadSSMConfig(awsSession *session.Session, stage string) Config { //nolint
 		}
 	}
 
+	loadOptionalSSMConfig(ssmClient, stage, &config)
+
 	return config
 }
+
+// loadOptionalSSMConfig loads the keys of the optional integrations - a missing key leaves the integration disabled
+func loadOptionalSSMConfig(ssmClient *ssm.SSM, stage string, config *Config) {
+	f := logrus.Fields{
+		"functionName": "config.ssm.loadOptionalSSMConfig",
+		"stage":        stage,
+	}
+
+	optionalKeys := map[string]*string{
+		fmt.Sprintf("cla-gitlab-api-url-%s", stage):          &config.GitLab.APIURL,
+		fmt.Sprintf("cla-gitlab-access-token-%s", stage):     &config.GitLab.AccessToken,
+		fmt.Sprintf("cla-gitlab-webhook-secret-%s", stage):   &config.GitLab.WebhookSecret,
+		fmt.Sprintf("cla-gitlab-sign-url-%s", stage):         &config.GitLab.SignURL,
+		fmt.Sprintf("cla-email-sender-%s", stage):            &config.EmailSender,
+		fmt.Sprintf("cla-smtp-host-%s", stage):               &config.SMTP.Host,
+		fmt.Sprintf("cla-smtp-username-%s", stage):           &config.SMTP.Username,
+		fmt.Sprintf("cla-smtp-password-%s", stage):           &config.SMTP.Password,
+		fmt.Sprintf("cla-smtp-tls-mode-%s", stage):           &config.SMTP.TLSMode,
+		fmt.Sprintf("cla-otlp-endpoint-%s", stage):           &config.Tracing.OTLPEndpoint,
+		fmt.Sprintf("cla-event-chain-signing-key-%s", stage): &config.EventChainSigningKey,
+	}
+
+	for key, value := range optionalKeys {
+		theValue, err := getSSMString(ssmClient, key)
+		if err != nil {
+			log.WithFields(f).Debugf("optional key: %s not set", key)
+			continue
+		}
+		*value = theValue
+	}
+
+	smtpPortKey := fmt.Sprintf("cla-smtp-port-%s", stage)
+	if smtpPort, err := getSSMString(ssmClient, smtpPortKey); err == nil {
+		port, convErr := strconv.Atoi(smtpPort)
+		if convErr != nil {
+			log.WithFields(f).WithError(convErr).Warnf("invalid value of key: %s - using the default port", smtpPortKey)
+		} else {
+			config.SMTP.Port = port
+		}
+	}
+
+	sampleRatioKey := fmt.Sprintf("cla-trace-sample-ratio-%s", stage)
+	if sampleRatio, err := getSSMString(ssmClient, sampleRatioKey); err == nil {
+		ratio, convErr := strconv.ParseFloat(sampleRatio, 64)
+		if convErr != nil {
+			log.WithFields(f).WithError(convErr).Warnf("invalid value of key: %s - sampling all the traces", sampleRatioKey)
+		} else {
+			config.Tracing.SampleRatio = ratio
+		}
+	}
+
+	// a JSON object of the gerrit host to the account, e.g. {"review.example.org": {"username": "easycla", "password": "..."}}
+	gerritAccountsKey := fmt.Sprintf("cla-gerrit-accounts-%s", stage)
+	if gerritAccounts, err := getSSMString(ssmClient, gerritAccountsKey); err == nil {
+		var accounts map[string]GerritAccount
+		if jsonErr := json.Unmarshal([]byte(gerritAccounts), &accounts); jsonErr != nil {
+			log.WithFields(f).WithError(jsonErr).Warnf("invalid value of key: %s - the gerrit group sync backend is disabled", gerritAccountsKey)
+		} else {
+			config.GerritAccounts = accounts
+		}
+	}
+
+	// a JSON array of the trusted OIDC issuers, e.g. [{"issuer": "https://sso.example.org/", "audiences": ["easycla"]}]
+	oidcIssuersKey := fmt.Sprintf("cla-oidc-issuers-%s", stage)
+	if oidcIssuers, err := getSSMString(ssmClient, oidcIssuersKey); err == nil {
+		var issuers []OIDCIssuer
+		if jsonErr := json.Unmarshal([]byte(oidcIssuers), &issuers); jsonErr != nil {
+			log.WithFields(f).WithError(jsonErr).Warnf("invalid value of key: %s - only the auth0 tokens are trusted", oidcIssuersKey)
+		} else if issuer := issuerWithoutAudiences(issuers); issuer != "" {
+			log.WithFields(f).Warnf("invalid value of key: %s - the issuer: %s has no audiences - only the auth0 tokens are trusted", oidcIssuersKey, issuer)
+		} else {
+			config.OIDCIssuers = issuers
+		}
+	}
+}
+
+// issuerWithoutAudiences returns the first OIDC issuer without audiences, empty if all the issuers have one - the
+// tokens an issuer grants to the other applications must not be accepted
+func issuerWithoutAudiences(issuers []OIDCIssuer) string {
+	for _, issuer := range issuers {
+		if len(issuer.Audiences) == 0 {
+			return issuer.Issuer
+		}
+	}
+	return ""
+}
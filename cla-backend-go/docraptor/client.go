@@ -5,6 +5,7 @@ package docraptor
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -22,6 +23,8 @@ var (
 
 const (
 	docraptorURL = "https://%s@docraptor.com/docs"
+	// docLogsURL lists the recent documents, a cheap authenticated call
+	docLogsURL = "https://docraptor.com/doc_logs.json?per_page=1"
 )
 
 // Client structure model
@@ -74,3 +77,27 @@ func (dc Client) CreatePDF(html string, claType string) (io.ReadCloser, error) {
 	}
 	return resp.Body, nil
 }
+
+// Ping checks that DocRaptor is reachable and accepts the API key
+func (dc Client) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, docLogsURL, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(dc.apiKey, "")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			log.WithError(closeErr).Warn("problem closing the docraptor response body")
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected docraptor response status: %s", resp.Status)
+	}
+	return nil
+}
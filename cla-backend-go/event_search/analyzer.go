@@ -0,0 +1,155 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package event_search
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+)
+
+// filter term prefixes - the filter terms contain a '=' and never collide with the tokens
+const (
+	filterEventType = "type"
+	filterUser      = "user"
+	filterCompany   = "company"
+	filterCLAGroup  = "cla_group"
+	filterProject   = "project"
+)
+
+// fieldWeights boosts the names over the free text of the events
+var fieldWeights = map[string]float64{
+	FieldEventData:    1,
+	FieldEventSummary: 1,
+	FieldUserName:     2,
+	FieldCompanyName:  2,
+	FieldCLAGroupName: 2,
+	FieldProjectName:  1.5,
+}
+
+var stopWords = map[string]struct{}{
+	"a": {}, "an": {}, "and": {}, "are": {}, "as": {}, "at": {}, "be": {}, "been": {}, "by": {}, "for": {},
+	"from": {}, "has": {}, "have": {}, "in": {}, "is": {}, "of": {}, "on": {}, "or": {}, "the": {}, "to": {},
+	"was": {}, "were": {}, "with": {},
+}
+
+// Tokenize splits the text into lowercase letter and number runs, the stop words are dropped
+func Tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	tokens := make([]string, 0, len(words))
+	for _, word := range words {
+		if _, stop := stopWords[word]; !stop {
+			tokens = append(tokens, word)
+		}
+	}
+	return tokens
+}
+
+// uniqueTokens returns the tokens of the text without repetition, in their order of appearance
+func uniqueTokens(text string) []string {
+	seen := map[string]struct{}{}
+	var tokens []string
+	for _, token := range Tokenize(text) {
+		if _, ok := seen[token]; !ok {
+			seen[token] = struct{}{}
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// filterTerm returns the term of an exact match filter
+func filterTerm(name, value string) string {
+	return name + "=" + value
+}
+
+// analyze builds the document and the postings of the event, every posting carries the attributes of the document
+func analyze(event *models.Event) (*Document, []*Posting) {
+	postings := map[string]*Posting{}
+	var length float64
+	for _, field := range []struct {
+		name string
+		text string
+	}{
+		{FieldEventData, event.EventData},
+		{FieldEventSummary, event.EventSummary},
+		{FieldUserName, event.UserName + " " + event.LfUsername},
+		{FieldCompanyName, event.EventCompanyName},
+		{FieldCLAGroupName, event.EventCLAGroupName},
+		{FieldProjectName, event.EventProjectName},
+	} {
+		weight := fieldWeights[field.name]
+		for _, token := range Tokenize(field.text) {
+			posting, ok := postings[token]
+			if !ok {
+				posting = &Posting{Term: token, EventID: event.EventID}
+				postings[token] = posting
+			}
+			posting.Frequency += weight
+			if !containsString(posting.Fields, field.name) {
+				posting.Fields = append(posting.Fields, field.name)
+			}
+			length += weight
+		}
+	}
+
+	for name, value := range map[string]string{
+		filterEventType: event.EventType,
+		filterUser:      event.UserID,
+		filterCompany:   event.EventCompanyID,
+		filterCLAGroup:  event.EventCLAGroupID,
+		filterProject:   event.EventProjectID,
+	} {
+		if value != "" {
+			term := filterTerm(name, value)
+			postings[term] = &Posting{Term: term, EventID: event.EventID}
+		}
+	}
+
+	terms := make([]string, 0, len(postings))
+	for term := range postings {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+	list := make([]*Posting, 0, len(terms))
+	for _, term := range terms {
+		posting := postings[term]
+		posting.EventType = event.EventType
+		posting.CompanyID = event.EventCompanyID
+		posting.CompanyName = event.EventCompanyName
+		posting.CLAGroupID = event.EventCLAGroupID
+		posting.CLAGroupName = event.EventCLAGroupName
+		posting.EventTimeEpoch = event.EventTimeEpoch
+		posting.Length = length
+		list = append(list, posting)
+	}
+
+	return &Document{
+		EventID:        event.EventID,
+		EventType:      event.EventType,
+		UserID:         event.UserID,
+		CompanyID:      event.EventCompanyID,
+		CompanyName:    event.EventCompanyName,
+		CLAGroupID:     event.EventCLAGroupID,
+		CLAGroupName:   event.EventCLAGroupName,
+		ProjectID:      event.EventProjectID,
+		EventTimeEpoch: event.EventTimeEpoch,
+		Length:         length,
+		Terms:          terms,
+		Event:          event,
+	}, list
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
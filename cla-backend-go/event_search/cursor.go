@@ -0,0 +1,83 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package event_search
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// sortKey is the position of a hit in the results - the event ID breaks the ties, the order is total
+type sortKey struct {
+	Score   float64 `json:"s"`
+	Epoch   int64   `json:"e"`
+	EventID string  `json:"id"`
+}
+
+// before returns true when the key comes before the other key in the sort order
+func (k sortKey) before(other sortKey, order string) bool {
+	switch order {
+	case SortRelevance:
+		if k.Score != other.Score {
+			return k.Score > other.Score
+		}
+		if k.Epoch != other.Epoch {
+			return k.Epoch > other.Epoch
+		}
+	case SortNewest:
+		if k.Epoch != other.Epoch {
+			return k.Epoch > other.Epoch
+		}
+	case SortOldest:
+		if k.Epoch != other.Epoch {
+			return k.Epoch < other.Epoch
+		}
+	}
+	return k.EventID < other.EventID
+}
+
+// cursor is the position of the last hit of a page. It keeps the collection statistics of the first page, the
+// scores of the next pages don't move when events are indexed in between.
+type cursor struct {
+	Query   string           `json:"q"`
+	Key     sortKey          `json:"k"`
+	Stats   Stats            `json:"st"`
+	DocFreq map[string]int64 `json:"df,omitempty"`
+}
+
+func encodeCursor(c *cursor) (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeCursor(encoded string) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// fingerprint identifies the query of a cursor, the page size may change between the pages
+func (q *Query) fingerprint() string {
+	copied := *q
+	copied.Cursor = ""
+	copied.PageSize = 0
+	data, err := json.Marshal(copied)
+	if err != nil {
+		// a struct of strings and numbers always marshals
+		panic(err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,143 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package event_search
+
+import (
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+)
+
+// searchable fields of the events
+const (
+	FieldEventData    = "event_data"
+	FieldEventSummary = "event_summary"
+	FieldUserName     = "user_name"
+	FieldCompanyName  = "company_name"
+	FieldCLAGroupName = "cla_group_name"
+	FieldProjectName  = "project_name"
+)
+
+// facets of the search results
+const (
+	FacetEventType = "event_type"
+	FacetCompany   = "company"
+	FacetCLAGroup  = "cla_group"
+)
+
+// sort orders of the search results
+const (
+	SortRelevance = "relevance"
+	SortNewest    = "newest"
+	SortOldest    = "oldest"
+)
+
+// page sizes
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 500
+)
+
+// MaxTermPostings is the number of postings of a term read by a search - the terms with more postings are only
+// looked up for the events matching the other terms of the query
+const MaxTermPostings = 10000
+
+// Document is an event of the index with the attributes used by the filters, the facets and the ranking
+type Document struct {
+	EventID        string `dynamodbav:"event_id"`
+	EventType      string `dynamodbav:"event_type"`
+	UserID         string `dynamodbav:"user_id"`
+	CompanyID      string `dynamodbav:"company_id"`
+	CompanyName    string `dynamodbav:"company_name"`
+	CLAGroupID     string `dynamodbav:"cla_group_id"`
+	CLAGroupName   string `dynamodbav:"cla_group_name"`
+	ProjectID      string `dynamodbav:"project_id"`
+	EventTimeEpoch int64  `dynamodbav:"event_time_epoch"`
+	// Length is the field weighted number of tokens of the document
+	Length float64 `dynamodbav:"length"`
+	// Terms are the keys of the postings of the document, used to remove them when the event changes
+	Terms []string      `dynamodbav:"terms,stringset,omitempty"`
+	Event *models.Event `dynamodbav:"event"`
+}
+
+// Posting is the occurrence of a term in a document. It carries the attributes of the document used by the
+// ranking, the time range and the facets, the searches only load the documents of the returned page.
+type Posting struct {
+	Term    string `dynamodbav:"term"`
+	EventID string `dynamodbav:"event_id"`
+	// Frequency is the field weighted number of occurrences of the term, zero for the filter terms
+	Frequency float64 `dynamodbav:"frequency"`
+	// Fields are the searchable fields containing the term
+	Fields []string `dynamodbav:"fields,stringset,omitempty"`
+
+	EventType      string  `dynamodbav:"event_type,omitempty"`
+	CompanyID      string  `dynamodbav:"company_id,omitempty"`
+	CompanyName    string  `dynamodbav:"company_name,omitempty"`
+	CLAGroupID     string  `dynamodbav:"cla_group_id,omitempty"`
+	CLAGroupName   string  `dynamodbav:"cla_group_name,omitempty"`
+	EventTimeEpoch int64   `dynamodbav:"event_time_epoch"`
+	Length         float64 `dynamodbav:"length"`
+}
+
+// Stats are the collection statistics of the ranking
+type Stats struct {
+	DocumentCount int64   `dynamodbav:"document_count" json:"n"`
+	TotalLength   float64 `dynamodbav:"total_length" json:"l"`
+}
+
+// BackfillState is the progress of the indexing of the events recorded before the index was fed by the stream of
+// the events table
+type BackfillState struct {
+	// NextKey is the scan key of the next page of events to index
+	NextKey  string `dynamodbav:"next_key,omitempty"`
+	Indexed  int64  `dynamodbav:"indexed"`
+	Complete bool   `dynamodbav:"complete"`
+}
+
+// ReindexSummary summarizes a reindex run
+type ReindexSummary struct {
+	Indexed  int64 `json:"indexed"`
+	Complete bool  `json:"complete"`
+}
+
+// Query is a full-text search of the events. The text terms must all match, in any of the searchable fields, the
+// user and company name terms must match in their field.
+type Query struct {
+	Text        string
+	UserName    string
+	CompanyName string
+
+	EventType  string
+	UserID     string
+	CompanyID  string
+	CLAGroupID string
+	ProjectID  string
+	After      *int64
+	Before     *int64
+
+	// Sort is one of SortRelevance, SortNewest or SortOldest - the queries without text terms default to the newest
+	Sort     string
+	PageSize int64
+	Cursor   string
+}
+
+// Hit is an event matching the query
+type Hit struct {
+	Event *models.Event
+	Score float64
+}
+
+// FacetValue is the number of matching events with the value
+type FacetValue struct {
+	Value string
+	Name  string
+	Count int64
+}
+
+// Result is a page of the events matching the query
+type Result struct {
+	Hits []*Hit
+	// Facets are keyed by FacetEventType, FacetCompany and FacetCLAGroup, they count all the matching events
+	Facets     map[string][]*FacetValue
+	Total      int64
+	NextCursor string
+}
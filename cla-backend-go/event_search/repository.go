@@ -0,0 +1,478 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package event_search
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
+)
+
+// attribute names
+const (
+	termAttributeName    = "term"
+	eventIDAttributeName = "event_id"
+)
+
+// constants
+const (
+	// statsDocumentID is the key of the collection statistics item of the documents table
+	statsDocumentID = "#stats"
+
+	// backfillDocumentID is the key of the backfill state item of the documents table
+	backfillDocumentID = "#backfill"
+
+	// maxBatchGetSize and maxBatchWriteSize are the DynamoDB limits of the batch requests
+	maxBatchGetSize   = 100
+	maxBatchWriteSize = 25
+
+	// maxBatchAttempts is the number of times the unprocessed items of a batch request are sent again
+	maxBatchAttempts = 5
+)
+
+// Repository interface defines the functions of the event search index storage
+type Repository interface {
+	// PutDocument adds the document and its postings to the index, replacing the previous version of the document
+	PutDocument(ctx context.Context, document *Document, postings []*Posting) error
+	// DeleteDocument removes the document and its postings from the index, it does nothing when the document is missing
+	DeleteDocument(ctx context.Context, eventID string) error
+	GetDocuments(ctx context.Context, eventIDs []string) ([]*Document, error)
+	// GetPostings returns up to limit postings of the term, ordered by event ID, and true when the term has more
+	GetPostings(ctx context.Context, term string, limit int) ([]*Posting, bool, error)
+	// GetEventPostings returns the postings of the term for the events, the events without the term are skipped
+	GetEventPostings(ctx context.Context, term string, eventIDs []string) ([]*Posting, error)
+	GetStats(ctx context.Context) (*Stats, error)
+	GetBackfillState(ctx context.Context) (*BackfillState, error)
+	PutBackfillState(ctx context.Context, state *BackfillState) error
+}
+
+type repository struct {
+	stage              string
+	dynamoDBClient     *dynamodb.DynamoDB
+	termsTableName     string
+	documentsTableName string
+}
+
+// NewRepository creates a new instance of the event search repository
+func NewRepository(awsSession *session.Session, stage string) Repository {
+	return &repository{
+		stage:              stage,
+		dynamoDBClient:     dynamodb.New(awsSession),
+		termsTableName:     fmt.Sprintf("cla-%s-event-search-terms", stage),
+		documentsTableName: fmt.Sprintf("cla-%s-event-search-documents", stage),
+	}
+}
+
+// PutDocument adds the document and its postings to the index, replacing the previous version of the document
+func (repo *repository) PutDocument(ctx context.Context, document *Document, postings []*Posting) error {
+	ctx, span := telemetry.StartSpan(ctx, "event_search.repository.PutDocument")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.event_search.repository.PutDocument",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"eventID":        document.EventID,
+		"postings":       len(postings),
+	}
+
+	previous, err := repo.getDocument(ctx, document.EventID)
+	if err != nil {
+		return err
+	}
+
+	var requests []*dynamodb.WriteRequest
+	if previous != nil {
+		// the terms kept by the new version are overwritten by its postings
+		for _, term := range previous.Terms {
+			if !containsString(document.Terms, term) {
+				requests = append(requests, &dynamodb.WriteRequest{DeleteRequest: &dynamodb.DeleteRequest{Key: postingKey(term, document.EventID)}})
+			}
+		}
+	}
+	for _, posting := range postings {
+		av, marshalErr := dynamodbattribute.MarshalMap(posting)
+		if marshalErr != nil {
+			log.WithFields(f).WithError(marshalErr).Warn("unable to marshall the posting")
+			return marshalErr
+		}
+		requests = append(requests, &dynamodb.WriteRequest{PutRequest: &dynamodb.PutRequest{Item: av}})
+	}
+	if err = repo.batchWrite(ctx, repo.termsTableName, requests); err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to write the postings of the document")
+		return err
+	}
+
+	av, err := dynamodbattribute.MarshalMap(document)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to marshall the document")
+		return err
+	}
+	_, err = repo.dynamoDBClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		Item:      av,
+		TableName: aws.String(repo.documentsTableName),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("cannot put the document in dynamodb")
+		return err
+	}
+
+	count, length := int64(1), document.Length
+	if previous != nil {
+		count, length = 0, document.Length-previous.Length
+	}
+	return repo.updateStats(ctx, count, length)
+}
+
+// DeleteDocument removes the document and its postings from the index
+func (repo *repository) DeleteDocument(ctx context.Context, eventID string) error {
+	ctx, span := telemetry.StartSpan(ctx, "event_search.repository.DeleteDocument")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.event_search.repository.DeleteDocument",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"eventID":        eventID,
+	}
+
+	previous, err := repo.getDocument(ctx, eventID)
+	if err != nil || previous == nil {
+		return err
+	}
+
+	requests := make([]*dynamodb.WriteRequest, 0, len(previous.Terms))
+	for _, term := range previous.Terms {
+		requests = append(requests, &dynamodb.WriteRequest{DeleteRequest: &dynamodb.DeleteRequest{Key: postingKey(term, eventID)}})
+	}
+	if err = repo.batchWrite(ctx, repo.termsTableName, requests); err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to delete the postings of the document")
+		return err
+	}
+
+	_, err = repo.dynamoDBClient.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			eventIDAttributeName: {S: aws.String(eventID)},
+		},
+		TableName: aws.String(repo.documentsTableName),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("cannot delete the document from dynamodb")
+		return err
+	}
+
+	return repo.updateStats(ctx, -1, -previous.Length)
+}
+
+// GetDocuments returns the documents of the events, the missing documents are skipped
+func (repo *repository) GetDocuments(ctx context.Context, eventIDs []string) ([]*Document, error) {
+	ctx, span := telemetry.StartSpan(ctx, "event_search.repository.GetDocuments")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.event_search.repository.GetDocuments",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"eventIDs":       len(eventIDs),
+	}
+
+	keys := make([]map[string]*dynamodb.AttributeValue, 0, len(eventIDs))
+	for _, eventID := range eventIDs {
+		keys = append(keys, map[string]*dynamodb.AttributeValue{eventIDAttributeName: {S: aws.String(eventID)}})
+	}
+	items, err := repo.batchGet(ctx, repo.documentsTableName, keys)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("error retrieving the documents")
+		return nil, err
+	}
+
+	var documents []*Document
+	if err = dynamodbattribute.UnmarshalListOfMaps(items, &documents); err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to unmarshall the documents")
+		return nil, err
+	}
+	return documents, nil
+}
+
+// GetPostings returns up to limit postings of the term, ordered by event ID, and true when the term has more
+func (repo *repository) GetPostings(ctx context.Context, term string, limit int) ([]*Posting, bool, error) {
+	ctx, span := telemetry.StartSpan(ctx, "event_search.repository.GetPostings")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.event_search.repository.GetPostings",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"term":           term,
+		"limit":          limit,
+	}
+
+	condition := expression.Key(termAttributeName).Equal(expression.Value(term))
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).Build()
+	if err != nil {
+		log.WithFields(f).Warnf("problem building query expression, error: %+v", err)
+		return nil, false, err
+	}
+
+	// one more posting than the limit tells if the term has more
+	var postings []*Posting
+	err = repo.dynamoDBClient.QueryPagesWithContext(ctx, &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		TableName:                 aws.String(repo.termsTableName),
+		Limit:                     aws.Int64(int64(limit) + 1),
+	}, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		var pagePostings []*Posting
+		if unmarshalErr := dynamodbattribute.UnmarshalListOfMaps(page.Items, &pagePostings); unmarshalErr != nil {
+			err = unmarshalErr
+			return false
+		}
+		postings = append(postings, pagePostings...)
+		return len(postings) <= limit
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("error retrieving the postings")
+		return nil, false, err
+	}
+
+	if len(postings) > limit {
+		return postings[:limit], true, nil
+	}
+	return postings, false, nil
+}
+
+// GetEventPostings returns the postings of the term for the events, the events without the term are skipped
+func (repo *repository) GetEventPostings(ctx context.Context, term string, eventIDs []string) ([]*Posting, error) {
+	ctx, span := telemetry.StartSpan(ctx, "event_search.repository.GetEventPostings")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.event_search.repository.GetEventPostings",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"term":           term,
+		"eventIDs":       len(eventIDs),
+	}
+
+	keys := make([]map[string]*dynamodb.AttributeValue, 0, len(eventIDs))
+	for _, eventID := range eventIDs {
+		keys = append(keys, postingKey(term, eventID))
+	}
+	items, err := repo.batchGet(ctx, repo.termsTableName, keys)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("error retrieving the postings of the events")
+		return nil, err
+	}
+
+	var postings []*Posting
+	if err = dynamodbattribute.UnmarshalListOfMaps(items, &postings); err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to unmarshall the postings")
+		return nil, err
+	}
+	return postings, nil
+}
+
+// GetStats returns the collection statistics of the index
+func (repo *repository) GetStats(ctx context.Context) (*Stats, error) {
+	ctx, span := telemetry.StartSpan(ctx, "event_search.repository.GetStats")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.event_search.repository.GetStats",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+	}
+
+	result, err := repo.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			eventIDAttributeName: {S: aws.String(statsDocumentID)},
+		},
+		TableName: aws.String(repo.documentsTableName),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("error retrieving the index statistics")
+		return nil, err
+	}
+
+	var stats Stats
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// GetBackfillState returns the progress of the backfill, the zero state when the backfill didn't start
+func (repo *repository) GetBackfillState(ctx context.Context) (*BackfillState, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.event_search.repository.GetBackfillState",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+	}
+
+	result, err := repo.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			eventIDAttributeName: {S: aws.String(backfillDocumentID)},
+		},
+		TableName:      aws.String(repo.documentsTableName),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("error retrieving the backfill state")
+		return nil, err
+	}
+
+	var state BackfillState
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// PutBackfillState saves the progress of the backfill
+func (repo *repository) PutBackfillState(ctx context.Context, state *BackfillState) error {
+	f := logrus.Fields{
+		"functionName":   "v1.event_search.repository.PutBackfillState",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"indexed":        state.Indexed,
+		"complete":       state.Complete,
+	}
+
+	av, err := dynamodbattribute.MarshalMap(state)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to marshall the backfill state")
+		return err
+	}
+	av[eventIDAttributeName] = &dynamodb.AttributeValue{S: aws.String(backfillDocumentID)}
+	_, err = repo.dynamoDBClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		Item:      av,
+		TableName: aws.String(repo.documentsTableName),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("cannot put the backfill state in dynamodb")
+		return err
+	}
+	return nil
+}
+
+// getDocument returns the document of the event, nil when the event is not indexed
+func (repo *repository) getDocument(ctx context.Context, eventID string) (*Document, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.event_search.repository.getDocument",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"eventID":        eventID,
+	}
+
+	result, err := repo.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			eventIDAttributeName: {S: aws.String(eventID)},
+		},
+		TableName: aws.String(repo.documentsTableName),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("error retrieving the document")
+		return nil, err
+	}
+	if len(result.Item) == 0 {
+		return nil, nil
+	}
+
+	var document Document
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &document); err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
+
+// updateStats adds the deltas to the collection statistics
+func (repo *repository) updateStats(ctx context.Context, count int64, length float64) error {
+	f := logrus.Fields{
+		"functionName":   "v1.event_search.repository.updateStats",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"count":          count,
+		"length":         length,
+	}
+
+	update := expression.Add(expression.Name("document_count"), expression.Value(count)).
+		Add(expression.Name("total_length"), expression.Value(length))
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		log.WithFields(f).Warnf("problem building update expression, error: %+v", err)
+		return err
+	}
+
+	_, err = repo.dynamoDBClient.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			eventIDAttributeName: {S: aws.String(statsDocumentID)},
+		},
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		UpdateExpression:          expr.Update(),
+		TableName:                 aws.String(repo.documentsTableName),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to update the index statistics")
+		return err
+	}
+	return nil
+}
+
+// batchGet loads the items in batches, the unprocessed keys are requested again and the missing items are skipped
+func (repo *repository) batchGet(ctx context.Context, tableName string, keys []map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, error) {
+	items := make([]map[string]*dynamodb.AttributeValue, 0, len(keys))
+	for start := 0; start < len(keys); start += maxBatchGetSize {
+		end := start + maxBatchGetSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		requestItems := map[string]*dynamodb.KeysAndAttributes{
+			tableName: {Keys: keys[start:end]},
+		}
+		for attempt := 1; len(requestItems) > 0; attempt++ {
+			if attempt > maxBatchAttempts {
+				return nil, fmt.Errorf("unable to load %d items of %s after %d attempts", len(requestItems[tableName].Keys), tableName, maxBatchAttempts)
+			}
+			output, err := repo.dynamoDBClient.BatchGetItemWithContext(ctx, &dynamodb.BatchGetItemInput{RequestItems: requestItems})
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, output.Responses[tableName]...)
+			requestItems = output.UnprocessedKeys
+		}
+	}
+	return items, nil
+}
+
+// batchWrite sends the write requests in batches, the unprocessed requests are sent again
+func (repo *repository) batchWrite(ctx context.Context, tableName string, requests []*dynamodb.WriteRequest) error {
+	for start := 0; start < len(requests); start += maxBatchWriteSize {
+		end := start + maxBatchWriteSize
+		if end > len(requests) {
+			end = len(requests)
+		}
+		requestItems := map[string][]*dynamodb.WriteRequest{tableName: requests[start:end]}
+		for attempt := 1; len(requestItems) > 0; attempt++ {
+			if attempt > maxBatchAttempts {
+				return fmt.Errorf("unable to write %d items to %s after %d attempts", len(requestItems[tableName]), tableName, maxBatchAttempts)
+			}
+			output, err := repo.dynamoDBClient.BatchWriteItemWithContext(ctx, &dynamodb.BatchWriteItemInput{RequestItems: requestItems})
+			if err != nil {
+				return err
+			}
+			requestItems = output.UnprocessedItems
+		}
+	}
+	return nil
+}
+
+func postingKey(term, eventID string) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		termAttributeName:    {S: aws.String(term)},
+		eventIDAttributeName: {S: aws.String(eventID)},
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package event_search
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// memoryRepository is an embedded, in-memory implementation of the event search Repository. It is intended for
+// local development and tests where DynamoDB is not available.
+type memoryRepository struct {
+	lock      sync.RWMutex
+	documents map[string]Document
+	// postings are keyed by term, then by event ID
+	postings map[string]map[string]Posting
+	backfill BackfillState
+}
+
+// NewMemoryRepository creates a new instance of the in-memory event search repository
+func NewMemoryRepository() Repository {
+	return &memoryRepository{
+		documents: map[string]Document{},
+		postings:  map[string]map[string]Posting{},
+	}
+}
+
+// PutDocument adds the document and its postings to the index, replacing the previous version of the document
+func (repo *memoryRepository) PutDocument(ctx context.Context, document *Document, postings []*Posting) error {
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	repo.deleteDocument(document.EventID)
+	repo.documents[document.EventID] = *document
+	for _, posting := range postings {
+		if repo.postings[posting.Term] == nil {
+			repo.postings[posting.Term] = map[string]Posting{}
+		}
+		repo.postings[posting.Term][posting.EventID] = *posting
+	}
+	return nil
+}
+
+// DeleteDocument removes the document and its postings from the index
+func (repo *memoryRepository) DeleteDocument(ctx context.Context, eventID string) error {
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	repo.deleteDocument(eventID)
+	return nil
+}
+
+func (repo *memoryRepository) deleteDocument(eventID string) {
+	previous, ok := repo.documents[eventID]
+	if !ok {
+		return
+	}
+	for _, term := range previous.Terms {
+		delete(repo.postings[term], eventID)
+		if len(repo.postings[term]) == 0 {
+			delete(repo.postings, term)
+		}
+	}
+	delete(repo.documents, eventID)
+}
+
+// GetDocuments returns the documents of the events, the missing documents are skipped
+func (repo *memoryRepository) GetDocuments(ctx context.Context, eventIDs []string) ([]*Document, error) {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	documents := make([]*Document, 0, len(eventIDs))
+	for _, eventID := range eventIDs {
+		if document, ok := repo.documents[eventID]; ok {
+			documents = append(documents, &document)
+		}
+	}
+	return documents, nil
+}
+
+// GetPostings returns up to limit postings of the term, ordered by event ID, and true when the term has more
+func (repo *memoryRepository) GetPostings(ctx context.Context, term string, limit int) ([]*Posting, bool, error) {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	postings := make([]*Posting, 0, len(repo.postings[term]))
+	for _, posting := range repo.postings[term] {
+		out := posting
+		postings = append(postings, &out)
+	}
+	sort.Slice(postings, func(i, j int) bool {
+		return postings[i].EventID < postings[j].EventID
+	})
+	if len(postings) > limit {
+		return postings[:limit], true, nil
+	}
+	return postings, false, nil
+}
+
+// GetEventPostings returns the postings of the term for the events, the events without the term are skipped
+func (repo *memoryRepository) GetEventPostings(ctx context.Context, term string, eventIDs []string) ([]*Posting, error) {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	postings := make([]*Posting, 0, len(eventIDs))
+	for _, eventID := range eventIDs {
+		if posting, ok := repo.postings[term][eventID]; ok {
+			postings = append(postings, &posting)
+		}
+	}
+	return postings, nil
+}
+
+// GetStats returns the collection statistics of the index
+func (repo *memoryRepository) GetStats(ctx context.Context) (*Stats, error) {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	stats := &Stats{DocumentCount: int64(len(repo.documents))}
+	for _, document := range repo.documents {
+		stats.TotalLength += document.Length
+	}
+	return stats, nil
+}
+
+// GetBackfillState returns the progress of the backfill, the zero state when the backfill didn't start
+func (repo *memoryRepository) GetBackfillState(ctx context.Context) (*BackfillState, error) {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	state := repo.backfill
+	return &state, nil
+}
+
+// PutBackfillState saves the progress of the backfill
+func (repo *memoryRepository) PutBackfillState(ctx context.Context, state *BackfillState) error {
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	repo.backfill = *state
+	return nil
+}
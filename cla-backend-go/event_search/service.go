@@ -0,0 +1,519 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package event_search
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	eventOps "github.com/communitybridge/easycla/cla-backend-go/gen/v1/restapi/operations/events"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// errors
+var (
+	ErrEmptyQuery    = errors.New("the event search requires a search term or a filter")
+	ErrInvalidCursor = errors.New("invalid cursor, the cursor doesn't belong to the query")
+	ErrInvalidSort   = errors.New("invalid sort order, expecting relevance, newest or oldest")
+	ErrQueryTooBroad = errors.New("every term of the event search matches too many events, add a more specific term or a filter")
+)
+
+// BM25 ranking parameters
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// reindexPageSize is the number of events of the source indexed between two saves of the backfill state
+const reindexPageSize = 500
+
+// EventSource is the store of the events indexed by the backfill
+type EventSource interface {
+	// ScanEvents returns a page of events following the start key and the key of the next page, empty after the last page
+	ScanEvents(startKey string, pageSize int64) ([]*models.Event, string, error)
+}
+
+// Service interface defines the functions of the event search
+type Service interface {
+	IndexEvent(ctx context.Context, event *models.Event) error
+	RemoveEvent(ctx context.Context, eventID string) error
+	Search(ctx context.Context, query *Query) (*Result, error)
+	SearchEvents(ctx context.Context, params *eventOps.SearchEventsParams) (*models.EventList, error)
+	// Ready returns true once the events recorded before the index was fed by the stream are indexed
+	Ready(ctx context.Context) (bool, error)
+	Reindex(ctx context.Context, source EventSource) (*ReindexSummary, error)
+}
+
+type service struct {
+	repo            Repository
+	maxTermPostings int
+
+	// ready caches the completion of the backfill, a complete backfill stays complete
+	readyLock sync.RWMutex
+	ready     bool
+}
+
+// NewService creates a new instance of the event search service
+func NewService(repo Repository) Service {
+	return &service{
+		repo:            repo,
+		maxTermPostings: MaxTermPostings,
+	}
+}
+
+// IndexEvent adds the event to the index, an event indexed again replaces its previous version
+func (s *service) IndexEvent(ctx context.Context, event *models.Event) error {
+	if event.EventID == "" {
+		return errors.New("missing event ID")
+	}
+	document, postings := analyze(event)
+	return s.repo.PutDocument(ctx, document, postings)
+}
+
+// RemoveEvent removes the event from the index
+func (s *service) RemoveEvent(ctx context.Context, eventID string) error {
+	return s.repo.DeleteDocument(ctx, eventID)
+}
+
+// Ready returns true once the events recorded before the index was fed by the stream are indexed, the searches
+// must not run on the index before
+func (s *service) Ready(ctx context.Context) (bool, error) {
+	s.readyLock.RLock()
+	ready := s.ready
+	s.readyLock.RUnlock()
+	if ready {
+		return true, nil
+	}
+
+	state, err := s.repo.GetBackfillState(ctx)
+	if err != nil {
+		return false, err
+	}
+	if state.Complete {
+		s.readyLock.Lock()
+		s.ready = true
+		s.readyLock.Unlock()
+	}
+	return state.Complete, nil
+}
+
+// Reindex indexes the events of the source page by page, from the position saved by the previous run. The run stops
+// between two pages when the context is done, the next run resumes it. The backfill is complete once the last page
+// is indexed, the events recorded in between are indexed by the stream.
+func (s *service) Reindex(ctx context.Context, source EventSource) (*ReindexSummary, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.event_search.service.Reindex",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+	}
+
+	state, err := s.repo.GetBackfillState(ctx)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the backfill state")
+		return nil, err
+	}
+
+	summary := &ReindexSummary{}
+	for !state.Complete {
+		if ctx.Err() != nil {
+			log.WithFields(f).Infof("stopping the reindex after %d events, the next run resumes it", summary.Indexed)
+			break
+		}
+
+		events, nextKey, scanErr := source.ScanEvents(state.NextKey, reindexPageSize)
+		if scanErr != nil {
+			log.WithFields(f).WithError(scanErr).Warn("unable to scan the events")
+			return summary, scanErr
+		}
+		for _, event := range events {
+			if event.EventID == "" {
+				continue
+			}
+			if indexErr := s.IndexEvent(ctx, event); indexErr != nil {
+				log.WithFields(f).WithError(indexErr).Warnf("unable to index the event: %s", event.EventID)
+				return summary, indexErr
+			}
+			summary.Indexed++
+			state.Indexed++
+		}
+
+		state.NextKey = nextKey
+		state.Complete = nextKey == ""
+		if putErr := s.repo.PutBackfillState(ctx, state); putErr != nil {
+			log.WithFields(f).WithError(putErr).Warn("unable to save the backfill state")
+			return summary, putErr
+		}
+	}
+
+	summary.Complete = state.Complete
+	log.WithFields(f).Infof("indexed %d events, %d in total - backfill complete: %t", summary.Indexed, state.Indexed, state.Complete)
+	return summary, nil
+}
+
+// clause is a term of the query, its postings are restricted to the field when the field is set. The filter terms
+// are not scored.
+type clause struct {
+	term   string
+	field  string
+	scored bool
+}
+
+func (q *Query) clauses() []clause {
+	var clauses []clause
+	for _, token := range uniqueTokens(q.Text) {
+		clauses = append(clauses, clause{term: token, scored: true})
+	}
+	for _, token := range uniqueTokens(q.UserName) {
+		clauses = append(clauses, clause{term: token, field: FieldUserName, scored: true})
+	}
+	for _, token := range uniqueTokens(q.CompanyName) {
+		clauses = append(clauses, clause{term: token, field: FieldCompanyName, scored: true})
+	}
+	for _, filter := range []struct {
+		name  string
+		value string
+	}{
+		{filterEventType, q.EventType},
+		{filterUser, q.UserID},
+		{filterCompany, q.CompanyID},
+		{filterCLAGroup, q.CLAGroupID},
+		{filterProject, q.ProjectID},
+	} {
+		if filter.value != "" {
+			clauses = append(clauses, clause{term: filterTerm(filter.name, filter.value)})
+		}
+	}
+	return clauses
+}
+
+// Search returns a page of the events matching all the terms and filters of the query, with the facets of all the
+// matching events. The events are matched and ranked from the postings, only the documents of the page are loaded -
+// the terms with more than the maximum number of postings are only looked up for the events matching the other terms.
+func (s *service) Search(ctx context.Context, query *Query) (*Result, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.event_search.service.Search",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"text":           query.Text,
+		"sort":           query.Sort,
+	}
+
+	clauses := query.clauses()
+	if len(clauses) == 0 {
+		return nil, ErrEmptyQuery
+	}
+	scored := false
+	for _, c := range clauses {
+		scored = scored || c.scored
+	}
+
+	order := query.Sort
+	if order == "" {
+		order = SortNewest
+		if scored {
+			order = SortRelevance
+		}
+	}
+	if order != SortRelevance && order != SortNewest && order != SortOldest {
+		return nil, ErrInvalidSort
+	}
+	pageSize := query.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	fingerprint := query.fingerprint()
+	var after *cursor
+	if query.Cursor != "" {
+		var err error
+		after, err = decodeCursor(query.Cursor)
+		if err != nil || after.Query != fingerprint {
+			log.WithFields(f).Warn("the cursor doesn't belong to the query")
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	// the postings of the clauses keyed by event ID, nil for the clauses with too many postings
+	postings := make([]map[string]*Posting, len(clauses))
+	docFreq := map[string]int64{}
+	var broad []int
+	for i, c := range clauses {
+		list, more, err := s.repo.GetPostings(ctx, c.term, s.maxTermPostings)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warnf("unable to load the postings of the term: %s", c.term)
+			return nil, err
+		}
+		if c.scored {
+			// a term with too many postings is ranked with the maximum as its document frequency
+			docFreq[c.term] = int64(len(list))
+		}
+		if more {
+			broad = append(broad, i)
+			continue
+		}
+		postings[i] = fieldPostings(list, c.field)
+	}
+	if len(broad) == len(clauses) {
+		log.WithFields(f).Warn("every term of the query has too many postings")
+		return nil, ErrQueryTooBroad
+	}
+
+	// the events in the postings of every bounded clause, starting from the shortest postings
+	shortest := -1
+	for i := range postings {
+		if postings[i] != nil && (shortest < 0 || len(postings[i]) < len(postings[shortest])) {
+			shortest = i
+		}
+	}
+	var eventIDs []string
+	for eventID := range postings[shortest] {
+		matched := true
+		for i := range postings {
+			if postings[i] == nil {
+				continue
+			}
+			if _, ok := postings[i][eventID]; !ok {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			eventIDs = append(eventIDs, eventID)
+		}
+	}
+	sort.Strings(eventIDs)
+
+	// the postings of the broad clauses are only loaded for the matching events
+	for _, i := range broad {
+		list, err := s.repo.GetEventPostings(ctx, clauses[i].term, eventIDs)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warnf("unable to load the postings of the term: %s", clauses[i].term)
+			return nil, err
+		}
+		postings[i] = fieldPostings(list, clauses[i].field)
+		matched := eventIDs[:0]
+		for _, eventID := range eventIDs {
+			if _, ok := postings[i][eventID]; ok {
+				matched = append(matched, eventID)
+			}
+		}
+		eventIDs = matched
+	}
+
+	// the next pages rank with the statistics of the first page
+	var stats *Stats
+	if after != nil {
+		stats, docFreq = &after.Stats, after.DocFreq
+	} else {
+		var err error
+		stats, err = s.repo.GetStats(ctx)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("unable to load the index statistics")
+			return nil, err
+		}
+	}
+	var averageLength float64
+	if stats.DocumentCount > 0 {
+		averageLength = stats.TotalLength / float64(stats.DocumentCount)
+	}
+
+	facets := newFacetCounter()
+	ranked := make([]sortKey, 0, len(eventIDs))
+	for _, eventID := range eventIDs {
+		// every posting carries the attributes of the event
+		posting := postings[shortest][eventID]
+		if query.After != nil && posting.EventTimeEpoch < *query.After {
+			continue
+		}
+		if query.Before != nil && posting.EventTimeEpoch > *query.Before {
+			continue
+		}
+		facets.add(posting)
+
+		// the clauses are summed in the order of the query, the score of an event is the same on every page
+		var score float64
+		for i, c := range clauses {
+			if c.scored {
+				score += bm25(postings[i][eventID].Frequency, posting.Length, averageLength, docFreq[c.term], stats.DocumentCount)
+			}
+		}
+		ranked = append(ranked, sortKey{Score: score, Epoch: posting.EventTimeEpoch, EventID: eventID})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		return ranked[i].before(ranked[j], order)
+	})
+
+	start := 0
+	if after != nil {
+		start = sort.Search(len(ranked), func(i int) bool {
+			return after.Key.before(ranked[i], order)
+		})
+	}
+	end := start + int(pageSize)
+	if end > len(ranked) {
+		end = len(ranked)
+	}
+
+	pageIDs := make([]string, 0, end-start)
+	for _, key := range ranked[start:end] {
+		pageIDs = append(pageIDs, key.EventID)
+	}
+	documents, err := s.repo.GetDocuments(ctx, pageIDs)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the documents of the page")
+		return nil, err
+	}
+	documentsByID := make(map[string]*Document, len(documents))
+	for _, document := range documents {
+		documentsByID[document.EventID] = document
+	}
+
+	result := &Result{
+		Hits:   make([]*Hit, 0, end-start),
+		Facets: facets.values(),
+		Total:  int64(len(ranked)),
+	}
+	for _, key := range ranked[start:end] {
+		// the events removed since their postings were loaded are skipped
+		if document, ok := documentsByID[key.EventID]; ok {
+			result.Hits = append(result.Hits, &Hit{Event: document.Event, Score: key.Score})
+		}
+	}
+	if end < len(ranked) {
+		result.NextCursor, err = encodeCursor(&cursor{
+			Query:   fingerprint,
+			Key:     ranked[end-1],
+			Stats:   *stats,
+			DocFreq: docFreq,
+		})
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("unable to encode the cursor")
+			return nil, err
+		}
+	}
+
+	log.WithFields(f).Debugf("returning %d of %d matching events", len(result.Hits), result.Total)
+	return result, nil
+}
+
+// fieldPostings keys the postings by event ID, the postings are restricted to the field when the field is set
+func fieldPostings(list []*Posting, field string) map[string]*Posting {
+	postings := make(map[string]*Posting, len(list))
+	for _, posting := range list {
+		if field == "" || containsString(posting.Fields, field) {
+			postings[posting.EventID] = posting
+		}
+	}
+	return postings
+}
+
+// SearchEvents runs the search criteria of the v1 search events API on the index
+func (s *service) SearchEvents(ctx context.Context, params *eventOps.SearchEventsParams) (*models.EventList, error) {
+	query := &Query{
+		Text:        aws.StringValue(params.SearchTerm),
+		UserName:    aws.StringValue(params.UserName),
+		CompanyName: aws.StringValue(params.CompanyName),
+		EventType:   aws.StringValue(params.EventType),
+		UserID:      aws.StringValue(params.UserID),
+		CompanyID:   aws.StringValue(params.CompanyID),
+		ProjectID:   aws.StringValue(params.ProjectID),
+		After:       params.After,
+		Before:      params.Before,
+		PageSize:    aws.Int64Value(params.PageSize),
+		Cursor:      aws.StringValue(params.NextKey),
+	}
+	switch aws.StringValue(params.SortOrder) {
+	case "asc":
+		query.Sort = SortOldest
+	case "desc":
+		query.Sort = SortNewest
+	}
+
+	result, err := s.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]*models.Event, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		events = append(events, hit.Event)
+	}
+	return &models.EventList{
+		Events:  events,
+		NextKey: result.NextCursor,
+	}, nil
+}
+
+// bm25 returns the score of a term of the document
+func bm25(frequency, length, averageLength float64, docFreq, documentCount int64) float64 {
+	if frequency == 0 {
+		return 0
+	}
+	idf := math.Log(1 + (float64(documentCount-docFreq)+0.5)/(float64(docFreq)+0.5))
+	norm := 1.0
+	if averageLength > 0 {
+		norm = 1 - bm25B + bm25B*length/averageLength
+	}
+	return idf * frequency * (bm25K1 + 1) / (frequency + bm25K1*norm)
+}
+
+// facetCounter counts the matching events by event type, company and CLA group
+type facetCounter struct {
+	counts map[string]map[string]*FacetValue
+}
+
+func newFacetCounter() *facetCounter {
+	return &facetCounter{
+		counts: map[string]map[string]*FacetValue{
+			FacetEventType: {},
+			FacetCompany:   {},
+			FacetCLAGroup:  {},
+		},
+	}
+}
+
+func (c *facetCounter) add(posting *Posting) {
+	c.count(FacetEventType, posting.EventType, "")
+	c.count(FacetCompany, posting.CompanyID, posting.CompanyName)
+	c.count(FacetCLAGroup, posting.CLAGroupID, posting.CLAGroupName)
+}
+
+func (c *facetCounter) count(facet, value, name string) {
+	if value == "" {
+		return
+	}
+	facetValue, ok := c.counts[facet][value]
+	if !ok {
+		facetValue = &FacetValue{Value: value, Name: name}
+		c.counts[facet][value] = facetValue
+	}
+	facetValue.Count++
+}
+
+// values returns the values of every facet, the most frequent first
+func (c *facetCounter) values() map[string][]*FacetValue {
+	facets := make(map[string][]*FacetValue, len(c.counts))
+	for facet, counts := range c.counts {
+		values := make([]*FacetValue, 0, len(counts))
+		for _, value := range counts {
+			values = append(values, value)
+		}
+		sort.Slice(values, func(i, j int) bool {
+			if values[i].Count != values[j].Count {
+				return values[i].Count > values[j].Count
+			}
+			return values[i].Value < values[j].Value
+		})
+		facets[facet] = values
+	}
+	return facets
+}
@@ -0,0 +1,356 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package event_search
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	eventOps "github.com/communitybridge/easycla/cla-backend-go/gen/v1/restapi/operations/events"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testCLAGroupID = "d5412fa7-0a29-4a6b-a0d1-6a8d4e6ebf4c"
+	testCompanyID  = "0b1e0a4c-8d3b-4b55-9a3e-1d3c2a4f5e6d"
+	otherCompanyID = "7c3f9a1e-2b4d-4e6f-8a0b-1c2d3e4f5a6b"
+)
+
+func newTestService(t *testing.T, events ...*models.Event) Service {
+	s := NewService(NewMemoryRepository())
+	for _, event := range events {
+		assert.NoError(t, s.IndexEvent(context.Background(), event))
+	}
+	return s
+}
+
+func testEvents() []*models.Event {
+	return []*models.Event{
+		{
+			EventID:           "event-1",
+			EventType:         "cla_manager.added",
+			UserID:            "user-1",
+			UserName:          "John Doe",
+			EventCLAGroupID:   testCLAGroupID,
+			EventCLAGroupName: "Kubernetes",
+			EventCompanyID:    testCompanyID,
+			EventCompanyName:  "Acme Corporation",
+			EventData:         "John Doe added Jane Smith as CLA Manager for Acme Corporation",
+			EventTimeEpoch:    100,
+		},
+		{
+			EventID:           "event-2",
+			EventType:         "cla_manager.removed",
+			UserID:            "user-1",
+			UserName:          "John Doe",
+			EventCLAGroupID:   testCLAGroupID,
+			EventCLAGroupName: "Kubernetes",
+			EventCompanyID:    otherCompanyID,
+			EventCompanyName:  "Globex",
+			EventData:         "John Doe removed Jane Smith as CLA Manager, requested by acme",
+			EventTimeEpoch:    200,
+		},
+		{
+			EventID:           "event-3",
+			EventType:         "signature.approval_list.updated",
+			UserID:            "user-2",
+			UserName:          "Jane Smith",
+			EventCLAGroupID:   testCLAGroupID,
+			EventCLAGroupName: "Kubernetes",
+			EventCompanyID:    testCompanyID,
+			EventCompanyName:  "Acme Corporation",
+			EventData:         "Jane Smith added the email john@example.org to the approval list",
+			EventTimeEpoch:    300,
+		},
+	}
+}
+
+func eventIDs(result *Result) []string {
+	ids := make([]string, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		ids = append(ids, hit.Event.EventID)
+	}
+	return ids
+}
+
+func TestSearchRanksTheMatchingEvents(t *testing.T) {
+	s := newTestService(t, testEvents()...)
+	ctx := context.Background()
+
+	// the company name is boosted over the event data
+	result, err := s.Search(ctx, &Query{Text: "acme"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), result.Total)
+	assert.Equal(t, "event-2", eventIDs(result)[2])
+	assert.True(t, result.Hits[0].Score > result.Hits[2].Score)
+
+	// all the terms must match, the stop words are ignored
+	result, err = s.Search(ctx, &Query{Text: "the CLA manager of Acme"})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"event-1", "event-2"}, eventIDs(result))
+
+	// the user name terms only match the user name
+	result, err = s.Search(ctx, &Query{UserName: "john"})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"event-1", "event-2"}, eventIDs(result))
+
+	result, err = s.Search(ctx, &Query{Text: "kubernetes"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), result.Total)
+
+	result, err = s.Search(ctx, &Query{Text: "initech"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), result.Total)
+	assert.Empty(t, result.Hits)
+
+	_, err = s.Search(ctx, &Query{Text: "the of"})
+	assert.Equal(t, ErrEmptyQuery, err)
+	_, err = s.Search(ctx, &Query{Text: "acme", Sort: "alphabetical"})
+	assert.Equal(t, ErrInvalidSort, err)
+}
+
+func TestSearchFiltersAndFacets(t *testing.T) {
+	s := newTestService(t, testEvents()...)
+	ctx := context.Background()
+
+	// the queries without text are sorted by the newest events
+	result, err := s.Search(ctx, &Query{CLAGroupID: testCLAGroupID})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"event-3", "event-2", "event-1"}, eventIDs(result))
+
+	companies := result.Facets[FacetCompany]
+	if assert.Len(t, companies, 2) {
+		assert.Equal(t, &FacetValue{Value: testCompanyID, Name: "Acme Corporation", Count: 2}, companies[0])
+		assert.Equal(t, &FacetValue{Value: otherCompanyID, Name: "Globex", Count: 1}, companies[1])
+	}
+	assert.Len(t, result.Facets[FacetEventType], 3)
+	assert.Equal(t, []*FacetValue{{Value: testCLAGroupID, Name: "Kubernetes", Count: 3}}, result.Facets[FacetCLAGroup])
+
+	result, err = s.Search(ctx, &Query{Text: "jane", CompanyID: testCompanyID, After: aws.Int64(150)})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"event-3"}, eventIDs(result))
+	assert.Equal(t, []*FacetValue{{Value: "signature.approval_list.updated", Count: 1}}, result.Facets[FacetEventType])
+
+	result, err = s.Search(ctx, &Query{EventType: "cla_manager.added", Sort: SortOldest})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"event-1"}, eventIDs(result))
+}
+
+func TestSearchCursorsAreStable(t *testing.T) {
+	var events []*models.Event
+	for i := 1; i <= 7; i++ {
+		events = append(events, &models.Event{
+			EventID:        fmt.Sprintf("event-%d", i),
+			EventType:      "cla_manager.added",
+			UserID:         "user-1",
+			EventData:      fmt.Sprintf("cla manager added %d %s", i, "approval"),
+			EventTimeEpoch: int64(i % 3),
+		})
+	}
+	s := newTestService(t, events...)
+	ctx := context.Background()
+
+	query := &Query{Text: "cla manager", PageSize: 3}
+	seen := map[string]int{}
+	for page := 0; ; page++ {
+		result, err := s.Search(ctx, query)
+		assert.NoError(t, err)
+		for _, id := range eventIDs(result) {
+			seen[id]++
+		}
+		if page == 0 {
+			// the events indexed between two pages don't move the events of the first page
+			assert.NoError(t, s.IndexEvent(ctx, &models.Event{
+				EventID:        "event-0",
+				EventType:      "cla_manager.added",
+				UserID:         "user-1",
+				EventData:      "cla manager manager manager",
+				EventTimeEpoch: 10,
+			}))
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		query.Cursor = result.NextCursor
+	}
+	for _, event := range events {
+		assert.Equal(t, 1, seen[event.EventID], event.EventID)
+	}
+
+	// a cursor only continues its query
+	first, err := s.Search(ctx, &Query{Text: "cla manager", PageSize: 3})
+	assert.NoError(t, err)
+	_, err = s.Search(ctx, &Query{Text: "approval", PageSize: 3, Cursor: first.NextCursor})
+	assert.Equal(t, ErrInvalidCursor, err)
+	_, err = s.Search(ctx, &Query{Text: "cla manager", Cursor: "not a cursor"})
+	assert.Equal(t, ErrInvalidCursor, err)
+}
+
+func TestIndexEventReplacesAndRemovesTheEvent(t *testing.T) {
+	s := newTestService(t, testEvents()...)
+	ctx := context.Background()
+
+	// the event is indexed again when the names are added to it
+	updated := *testEvents()[1]
+	updated.EventCompanyName = "Globex Industries"
+	updated.EventData = "John Doe removed Jane Smith as CLA Manager"
+	assert.NoError(t, s.IndexEvent(ctx, &updated))
+
+	result, err := s.Search(ctx, &Query{Text: "industries"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"event-2"}, eventIDs(result))
+	result, err = s.Search(ctx, &Query{Text: "acme"})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"event-1", "event-3"}, eventIDs(result))
+
+	assert.NoError(t, s.RemoveEvent(ctx, "event-2"))
+	assert.NoError(t, s.RemoveEvent(ctx, "event-2"))
+	result, err = s.Search(ctx, &Query{CLAGroupID: testCLAGroupID})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"event-3", "event-1"}, eventIDs(result))
+
+	stats, err := s.(*service).repo.GetStats(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), stats.DocumentCount)
+}
+
+func TestSearchEventsParams(t *testing.T) {
+	s := newTestService(t, testEvents()...)
+
+	list, err := s.SearchEvents(context.Background(), &eventOps.SearchEventsParams{
+		SearchTerm:  aws.String("jane smith"),
+		CompanyName: aws.String("acme"),
+		SortOrder:   aws.String("desc"),
+		PageSize:    aws.Int64(1),
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, list.Events, 1) {
+		assert.Equal(t, "event-3", list.Events[0].EventID)
+	}
+	assert.NotEmpty(t, list.NextKey)
+
+	list, err = s.SearchEvents(context.Background(), &eventOps.SearchEventsParams{
+		SearchTerm:  aws.String("jane smith"),
+		CompanyName: aws.String("acme"),
+		SortOrder:   aws.String("desc"),
+		PageSize:    aws.Int64(1),
+		NextKey:     aws.String(list.NextKey),
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, list.Events, 1) {
+		assert.Equal(t, "event-1", list.Events[0].EventID)
+	}
+	assert.Empty(t, list.NextKey)
+}
+
+// documentsRecorder records the events of the documents loaded by the searches
+type documentsRecorder struct {
+	Repository
+	loaded []string
+}
+
+func (r *documentsRecorder) GetDocuments(ctx context.Context, eventIDs []string) ([]*Document, error) {
+	r.loaded = append(r.loaded, eventIDs...)
+	return r.Repository.GetDocuments(ctx, eventIDs)
+}
+
+func TestSearchBoundsThePostingReads(t *testing.T) {
+	repo := &documentsRecorder{Repository: NewMemoryRepository()}
+	s := &service{repo: repo, maxTermPostings: 2}
+	ctx := context.Background()
+	for _, event := range testEvents() {
+		assert.NoError(t, s.IndexEvent(ctx, event))
+	}
+
+	// a query needs a term with a bounded number of postings
+	_, err := s.Search(ctx, &Query{Text: "acme"})
+	assert.Equal(t, ErrQueryTooBroad, err)
+
+	// only the documents of the page are loaded, the facets count all the matching events
+	result, err := s.Search(ctx, &Query{CompanyID: testCompanyID, PageSize: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"event-3"}, eventIDs(result))
+	assert.Equal(t, int64(2), result.Total)
+	assert.Equal(t, []*FacetValue{{Value: testCompanyID, Name: "Acme Corporation", Count: 2}}, result.Facets[FacetCompany])
+	assert.Equal(t, []string{"event-3"}, repo.loaded)
+
+	// the broad terms are looked up for the events matching the other terms
+	result, err = s.Search(ctx, &Query{Text: "jane kubernetes", CompanyID: testCompanyID, Before: aws.Int64(250)})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"event-1"}, eventIDs(result))
+	result, err = s.Search(ctx, &Query{Text: "jane", EventType: "cla_manager.removed"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"event-2"}, eventIDs(result))
+	assert.True(t, result.Hits[0].Score > 0)
+}
+
+// eventSource pages the events, the start key is the position of the page
+type eventSource struct {
+	events []*models.Event
+	// failAt is the position of the page failing to load, zero when no page fails
+	failAt int
+}
+
+func (s *eventSource) ScanEvents(startKey string, pageSize int64) ([]*models.Event, string, error) {
+	start := 0
+	if startKey != "" {
+		fmt.Sscanf(startKey, "%d", &start) // nolint
+	}
+	if s.failAt > 0 && start == s.failAt {
+		return nil, "", fmt.Errorf("unable to scan the events at %d", start)
+	}
+	end := start + int(pageSize)
+	if end >= len(s.events) {
+		return s.events[start:], "", nil
+	}
+	return s.events[start:end], fmt.Sprintf("%d", end), nil
+}
+
+func TestReindexBackfillsTheIndex(t *testing.T) {
+	var events []*models.Event
+	for i := 1; i <= reindexPageSize+2; i++ {
+		events = append(events, &models.Event{
+			EventID:         fmt.Sprintf("event-%d", i),
+			EventType:       "cla_manager.added",
+			EventCLAGroupID: testCLAGroupID,
+			EventData:       "cla manager added",
+			EventTimeEpoch:  int64(i),
+		})
+	}
+	s := newTestService(t)
+	ctx := context.Background()
+
+	ready, err := s.Ready(ctx)
+	assert.NoError(t, err)
+	assert.False(t, ready)
+
+	// a failed run resumes from the last indexed page
+	source := &eventSource{events: events, failAt: reindexPageSize}
+	summary, err := s.Reindex(ctx, source)
+	assert.Error(t, err)
+	assert.Equal(t, int64(reindexPageSize), summary.Indexed)
+	ready, err = s.Ready(ctx)
+	assert.NoError(t, err)
+	assert.False(t, ready)
+
+	source.failAt = 0
+	summary, err = s.Reindex(ctx, source)
+	assert.NoError(t, err)
+	assert.Equal(t, &ReindexSummary{Indexed: 2, Complete: true}, summary)
+	ready, err = s.Ready(ctx)
+	assert.NoError(t, err)
+	assert.True(t, ready)
+
+	result, err := s.Search(ctx, &Query{CLAGroupID: testCLAGroupID, PageSize: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(reindexPageSize+2), result.Total)
+
+	// a complete backfill is not run again
+	summary, err = s.Reindex(ctx, source)
+	assert.NoError(t, err)
+	assert.Equal(t, &ReindexSummary{Complete: true}, summary)
+}
@@ -0,0 +1,114 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package event_webhooks
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
+)
+
+// ErrForbiddenAddress is returned when the subscription endpoint resolves to an internal address
+var ErrForbiddenAddress = errors.New("subscription url must not resolve to a loopback, private or link-local address")
+
+// internalNetworks are the address ranges the deliveries must never reach - the loopback, link-local (including
+// the 169.254.169.254 instance metadata endpoint) and unspecified addresses are checked separately
+var internalNetworks = mustParseCIDRs(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"fc00::/7",
+)
+
+// isPublicAddress returns true if the IP address may be the target of a delivery
+func isPublicAddress(ip net.IP) bool {
+	if ip == nil || ip.IsLoopback() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+	for _, network := range internalNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkEndpoint resolves the host of the subscription endpoint and checks all of its addresses
+func (s *service) checkEndpoint(ctx context.Context, endpoint *url.URL) error {
+	host := endpoint.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		if !s.allowAddress(ip) {
+			return ErrForbiddenAddress
+		}
+		return nil
+	}
+
+	addresses, err := s.lookupIPAddr(ctx, host)
+	if err != nil {
+		return err
+	}
+	if len(addresses) == 0 {
+		return ErrInvalidURL
+	}
+	for _, address := range addresses {
+		if !s.allowAddress(address.IP) {
+			return ErrForbiddenAddress
+		}
+	}
+	return nil
+}
+
+// newDeliveryHTTPClient returns the http client of the deliveries - the address is checked again when the
+// connection is made, the DNS records of the endpoint could have changed since the subscription was created and
+// the redirects are dialed the same way
+func newDeliveryHTTPClient(allowAddress func(ip net.IP) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   defaultHTTPTimeout,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if !allowAddress(net.ParseIP(host)) {
+				return ErrForbiddenAddress
+			}
+			return nil
+		},
+	}
+	transport := &http.Transport{
+		// no proxy, the checked address must be the one of the endpoint
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   defaultHTTPTimeout,
+		ExpectContinueTimeout: time.Second,
+	}
+	return &http.Client{Transport: telemetry.NewHTTPTransport(transport), Timeout: defaultHTTPTimeout}
+}
+
+// mustParseCIDRs parses the CIDR blocks, it panics on an invalid block
+func mustParseCIDRs(blocks ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(blocks))
+	for _, block := range blocks {
+		_, network, err := net.ParseCIDR(block)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
@@ -0,0 +1,107 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package event_webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// webhook request headers
+const (
+	EventHeader     = "X-EasyCLA-Event"
+	DeliveryHeader  = "X-EasyCLA-Delivery"
+	TimestampHeader = "X-EasyCLA-Timestamp"
+	// SignatureHeader holds the hex encoded HMAC-SHA256 of the timestamp and the body, prefixed with 'sha256='
+	SignatureHeader = "X-EasyCLA-Signature"
+)
+
+// signaturePrefix is the prefix of the signature header value
+const signaturePrefix = "sha256="
+
+// RetryPolicy defines how often a failed delivery is retried before it is moved to the dead letter list
+type RetryPolicy struct {
+	MaxAttempts     int
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+}
+
+// DefaultRetryPolicy retries for about a day - 1m, 2m, 4m ... up to 6h between the attempts
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:     12,
+	InitialInterval: time.Minute,
+	MaxInterval:     6 * time.Hour,
+}
+
+// NextInterval returns the exponential backoff to wait after the failed attempt, the attempts start at 1
+func (p RetryPolicy) NextInterval(attempts int) time.Duration {
+	interval := p.InitialInterval
+	for i := 1; i < attempts; i++ {
+		interval *= 2
+		if interval >= p.MaxInterval {
+			return p.MaxInterval
+		}
+	}
+	return interval
+}
+
+// Sign returns the signature header value of the body sent at the timestamp
+func Sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp)) // nolint
+	mac.Write([]byte("."))       // nolint
+	mac.Write(body)              // nolint
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks the signature header value sent with the body, receivers can use it to authenticate
+// the deliveries
+func VerifySignature(secret string, timestamp string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// deliveryResult is the outcome of a single delivery attempt
+type deliveryResult struct {
+	statusCode int
+	err        error
+}
+
+// post sends the signed payload to the subscription endpoint, any non 2xx response is a failure
+func post(ctx context.Context, httpClient *http.Client, subscription *Subscription, delivery *Delivery, now time.Time) deliveryResult {
+	body := []byte(delivery.Payload)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return deliveryResult{err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "EasyCLA-Webhooks")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, delivery.DeliveryID)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(subscription.Secret, timestamp, body))
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return deliveryResult{err: err}
+	}
+	defer resp.Body.Close()
+	// drain the body so the connection can be reused
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return deliveryResult{statusCode: resp.StatusCode, err: fmt.Errorf("webhook endpoint responded with status: %d", resp.StatusCode)}
+	}
+	return deliveryResult{statusCode: resp.StatusCode}
+}
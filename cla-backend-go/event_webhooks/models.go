@@ -0,0 +1,104 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package event_webhooks
+
+import "strings"
+
+// subscription owner types
+const (
+	OwnerTypeCLAGroup = "cla_group"
+	OwnerTypeCompany  = "company"
+)
+
+// delivery statuses
+const (
+	DeliveryStatusPending    = "pending"
+	DeliveryStatusDelivered  = "delivered"
+	DeliveryStatusDeadLetter = "dead_letter"
+)
+
+// Subscription is a webhook endpoint registered by a CLA group or a company to receive the events
+type Subscription struct {
+	SubscriptionID string `dynamodbav:"subscription_id"`
+	OwnerType      string `dynamodbav:"owner_type"`
+	OwnerID        string `dynamodbav:"owner_id"`
+	// OwnerKey is the owner type and ID joined by '#', used as the key of the owner index
+	OwnerKey    string   `dynamodbav:"owner_key"`
+	URL         string   `dynamodbav:"url"`
+	Secret      string   `dynamodbav:"secret"`
+	EventTypes  []string `dynamodbav:"event_types,stringset,omitempty"`
+	Description string   `dynamodbav:"description"`
+	Enabled     bool     `dynamodbav:"enabled"`
+	CreatedBy   string   `dynamodbav:"created_by"`
+
+	DateCreated  string `dynamodbav:"date_created"`
+	DateModified string `dynamodbav:"date_modified"`
+	Version      string `dynamodbav:"version"`
+}
+
+// Matches returns true if the subscription is enabled and subscribed to the event type, an empty event type list
+// subscribes to all the events
+func (s *Subscription) Matches(eventType string) bool {
+	if !s.Enabled {
+		return false
+	}
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+	for _, subscribed := range s.EventTypes {
+		if strings.EqualFold(subscribed, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery is the outbox record of an event sent to a subscription
+type Delivery struct {
+	DeliveryID     string `dynamodbav:"delivery_id"`
+	SubscriptionID string `dynamodbav:"subscription_id"`
+	EventID        string `dynamodbav:"event_id"`
+	EventType      string `dynamodbav:"event_type"`
+	// Payload is the signed JSON body, it is kept as is so a redelivery sends the same content
+	Payload string `dynamodbav:"payload"`
+	Status  string `dynamodbav:"status"`
+
+	Attempts         int    `dynamodbav:"attempts"`
+	NextAttemptEpoch int64  `dynamodbav:"next_attempt_epoch"`
+	LastAttemptTime  string `dynamodbav:"last_attempt_time"`
+	LastStatusCode   int    `dynamodbav:"last_status_code"`
+	LastError        string `dynamodbav:"last_error"`
+	// Expires is the TTL of the delivered records
+	Expires int64 `dynamodbav:"expires,omitempty"`
+
+	DateCreated  string `dynamodbav:"date_created"`
+	DateModified string `dynamodbav:"date_modified"`
+	Version      string `dynamodbav:"version"`
+}
+
+// Payload is the JSON body posted to the webhook endpoints
+type Payload struct {
+	DeliveryID     string `json:"delivery_id"`
+	SubscriptionID string `json:"subscription_id"`
+	EventID        string `json:"event_id"`
+	EventType      string `json:"event_type"`
+	EventTime      string `json:"event_time"`
+	EventTimeEpoch int64  `json:"event_time_epoch"`
+	CLAGroupID     string `json:"cla_group_id,omitempty"`
+	CLAGroupName   string `json:"cla_group_name,omitempty"`
+	ProjectSFID    string `json:"project_sfid,omitempty"`
+	ProjectName    string `json:"project_name,omitempty"`
+	CompanyID      string `json:"company_id,omitempty"`
+	CompanySFID    string `json:"company_sfid,omitempty"`
+	CompanyName    string `json:"company_name,omitempty"`
+	UserID         string `json:"user_id,omitempty"`
+	LfUsername     string `json:"lf_username,omitempty"`
+	EventData      string `json:"event_data,omitempty"`
+	EventSummary   string `json:"event_summary,omitempty"`
+}
+
+// ownerKey returns the key of the owner index
+func ownerKey(ownerType, ownerID string) string {
+	return ownerType + "#" + ownerID
+}
@@ -0,0 +1,329 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package event_webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
+)
+
+// indexes
+const (
+	OwnerKeyIndex             = "owner-key-index"
+	StatusNextAttemptIndex    = "status-next-attempt-epoch-index"
+	SubscriptionIDStatusIndex = "subscription-id-status-index"
+)
+
+// attribute names
+const (
+	subscriptionIDAttributeName   = "subscription_id"
+	ownerKeyAttributeName         = "owner_key"
+	deliveryIDAttributeName       = "delivery_id"
+	deliveryStatusAttributeName   = "status"
+	nextAttemptEpochAttributeName = "next_attempt_epoch"
+)
+
+// errors
+var (
+	ErrSubscriptionDoesNotExist = errors.New("event webhook subscription does not exist")
+	ErrDeliveryDoesNotExist     = errors.New("event webhook delivery does not exist")
+)
+
+// Repository interface defines the functions for the event webhook subscriptions and deliveries data model
+type Repository interface {
+	CreateSubscription(ctx context.Context, subscription *Subscription) error
+	GetSubscription(ctx context.Context, subscriptionID string) (*Subscription, error)
+	ListSubscriptions(ctx context.Context, ownerType, ownerID string) ([]*Subscription, error)
+	DeleteSubscription(ctx context.Context, subscriptionID string) error
+
+	PutDelivery(ctx context.Context, delivery *Delivery) error
+	GetDelivery(ctx context.Context, deliveryID string) (*Delivery, error)
+	ListDueDeliveries(ctx context.Context, epoch int64) ([]*Delivery, error)
+	ListSubscriptionDeliveries(ctx context.Context, subscriptionID, status string) ([]*Delivery, error)
+}
+
+type repository struct {
+	stage                  string
+	dynamoDBClient         *dynamodb.DynamoDB
+	subscriptionsTableName string
+	deliveriesTableName    string
+}
+
+// NewRepository creates a new instance of the event webhooks repository
+func NewRepository(awsSession *session.Session, stage string) Repository {
+	return &repository{
+		stage:                  stage,
+		dynamoDBClient:         dynamodb.New(awsSession),
+		subscriptionsTableName: fmt.Sprintf("cla-%s-event-webhook-subscriptions", stage),
+		deliveriesTableName:    fmt.Sprintf("cla-%s-event-webhook-deliveries", stage),
+	}
+}
+
+// CreateSubscription adds the webhook subscription
+func (repo *repository) CreateSubscription(ctx context.Context, subscription *Subscription) error {
+	ctx, span := telemetry.StartSpan(ctx, "event_webhooks.repository.CreateSubscription")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.event_webhooks.repository.CreateSubscription",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"subscriptionID": subscription.SubscriptionID,
+		"ownerType":      subscription.OwnerType,
+		"ownerID":        subscription.OwnerID,
+	}
+
+	av, err := dynamodbattribute.MarshalMap(subscription)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to marshall the subscription")
+		return err
+	}
+
+	_, err = repo.dynamoDBClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		Item:                av,
+		TableName:           aws.String(repo.subscriptionsTableName),
+		ConditionExpression: aws.String("attribute_not_exists(subscription_id)"),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("cannot put the subscription in dynamodb")
+		return err
+	}
+	return nil
+}
+
+// GetSubscription returns the webhook subscription
+func (repo *repository) GetSubscription(ctx context.Context, subscriptionID string) (*Subscription, error) {
+	ctx, span := telemetry.StartSpan(ctx, "event_webhooks.repository.GetSubscription")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.event_webhooks.repository.GetSubscription",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"subscriptionID": subscriptionID,
+	}
+
+	result, err := repo.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			subscriptionIDAttributeName: {S: aws.String(subscriptionID)},
+		},
+		TableName: aws.String(repo.subscriptionsTableName),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("error retrieving the subscription")
+		return nil, err
+	}
+	if len(result.Item) == 0 {
+		return nil, ErrSubscriptionDoesNotExist
+	}
+
+	var subscription Subscription
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &subscription); err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// ListSubscriptions returns the webhook subscriptions of the owner
+func (repo *repository) ListSubscriptions(ctx context.Context, ownerType, ownerID string) ([]*Subscription, error) {
+	ctx, span := telemetry.StartSpan(ctx, "event_webhooks.repository.ListSubscriptions")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.event_webhooks.repository.ListSubscriptions",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"ownerType":      ownerType,
+		"ownerID":        ownerID,
+	}
+
+	condition := expression.Key(ownerKeyAttributeName).Equal(expression.Value(ownerKey(ownerType, ownerID)))
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).Build()
+	if err != nil {
+		log.WithFields(f).Warnf("problem building query expression, error: %+v", err)
+		return nil, err
+	}
+
+	var subscriptions []*Subscription
+	err = repo.dynamoDBClient.QueryPagesWithContext(ctx, &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		TableName:                 aws.String(repo.subscriptionsTableName),
+		IndexName:                 aws.String(OwnerKeyIndex),
+	}, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		var pageSubscriptions []*Subscription
+		if unmarshalErr := dynamodbattribute.UnmarshalListOfMaps(page.Items, &pageSubscriptions); unmarshalErr != nil {
+			err = unmarshalErr
+			return false
+		}
+		subscriptions = append(subscriptions, pageSubscriptions...)
+		return true
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("error retrieving the subscriptions")
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// DeleteSubscription deletes the webhook subscription
+func (repo *repository) DeleteSubscription(ctx context.Context, subscriptionID string) error {
+	ctx, span := telemetry.StartSpan(ctx, "event_webhooks.repository.DeleteSubscription")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.event_webhooks.repository.DeleteSubscription",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"subscriptionID": subscriptionID,
+	}
+
+	_, err := repo.dynamoDBClient.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			subscriptionIDAttributeName: {S: aws.String(subscriptionID)},
+		},
+		TableName:           aws.String(repo.subscriptionsTableName),
+		ConditionExpression: aws.String("attribute_exists(subscription_id)"),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return ErrSubscriptionDoesNotExist
+		}
+		log.WithFields(f).WithError(err).Warn("error deleting the subscription")
+		return err
+	}
+	return nil
+}
+
+// PutDelivery creates or replaces the delivery record
+func (repo *repository) PutDelivery(ctx context.Context, delivery *Delivery) error {
+	ctx, span := telemetry.StartSpan(ctx, "event_webhooks.repository.PutDelivery")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.event_webhooks.repository.PutDelivery",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"deliveryID":     delivery.DeliveryID,
+		"subscriptionID": delivery.SubscriptionID,
+		"status":         delivery.Status,
+	}
+
+	av, err := dynamodbattribute.MarshalMap(delivery)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to marshall the delivery")
+		return err
+	}
+
+	_, err = repo.dynamoDBClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		Item:      av,
+		TableName: aws.String(repo.deliveriesTableName),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("cannot put the delivery in dynamodb")
+		return err
+	}
+	return nil
+}
+
+// GetDelivery returns the delivery record
+func (repo *repository) GetDelivery(ctx context.Context, deliveryID string) (*Delivery, error) {
+	ctx, span := telemetry.StartSpan(ctx, "event_webhooks.repository.GetDelivery")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.event_webhooks.repository.GetDelivery",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"deliveryID":     deliveryID,
+	}
+
+	result, err := repo.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			deliveryIDAttributeName: {S: aws.String(deliveryID)},
+		},
+		TableName: aws.String(repo.deliveriesTableName),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("error retrieving the delivery")
+		return nil, err
+	}
+	if len(result.Item) == 0 {
+		return nil, ErrDeliveryDoesNotExist
+	}
+
+	var delivery Delivery
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &delivery); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// ListDueDeliveries returns the pending deliveries with a next attempt time before the epoch
+func (repo *repository) ListDueDeliveries(ctx context.Context, epoch int64) ([]*Delivery, error) {
+	ctx, span := telemetry.StartSpan(ctx, "event_webhooks.repository.ListDueDeliveries")
+	defer span.End()
+
+	condition := expression.Key(deliveryStatusAttributeName).Equal(expression.Value(DeliveryStatusPending)).
+		And(expression.Key(nextAttemptEpochAttributeName).LessThanEqual(expression.Value(epoch)))
+	return repo.queryDeliveries(ctx, "v1.event_webhooks.repository.ListDueDeliveries", StatusNextAttemptIndex, condition)
+}
+
+// ListSubscriptionDeliveries returns the deliveries of the subscription with the status
+func (repo *repository) ListSubscriptionDeliveries(ctx context.Context, subscriptionID, status string) ([]*Delivery, error) {
+	ctx, span := telemetry.StartSpan(ctx, "event_webhooks.repository.ListSubscriptionDeliveries")
+	defer span.End()
+
+	condition := expression.Key(subscriptionIDAttributeName).Equal(expression.Value(subscriptionID)).
+		And(expression.Key(deliveryStatusAttributeName).Equal(expression.Value(status)))
+	return repo.queryDeliveries(ctx, "v1.event_webhooks.repository.ListSubscriptionDeliveries", SubscriptionIDStatusIndex, condition)
+}
+
+// queryDeliveries returns all the pages of the deliveries index query
+func (repo *repository) queryDeliveries(ctx context.Context, functionName, indexName string, condition expression.KeyConditionBuilder) ([]*Delivery, error) {
+	f := logrus.Fields{
+		"functionName":   functionName,
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"indexName":      indexName,
+	}
+
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).Build()
+	if err != nil {
+		log.WithFields(f).Warnf("problem building query expression, error: %+v", err)
+		return nil, err
+	}
+
+	var deliveries []*Delivery
+	err = repo.dynamoDBClient.QueryPagesWithContext(ctx, &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		TableName:                 aws.String(repo.deliveriesTableName),
+		IndexName:                 aws.String(indexName),
+	}, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		var pageDeliveries []*Delivery
+		if unmarshalErr := dynamodbattribute.UnmarshalListOfMaps(page.Items, &pageDeliveries); unmarshalErr != nil {
+			err = unmarshalErr
+			return false
+		}
+		deliveries = append(deliveries, pageDeliveries...)
+		return true
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("error retrieving the deliveries")
+		return nil, err
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,126 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package event_webhooks
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// memoryRepository is an embedded, in-memory implementation of the event webhooks Repository. It is intended
+// for local development and tests where DynamoDB is not available.
+type memoryRepository struct {
+	lock          sync.RWMutex
+	subscriptions map[string]Subscription
+	deliveries    map[string]Delivery
+}
+
+// NewMemoryRepository creates a new instance of the in-memory event webhooks repository
+func NewMemoryRepository() Repository {
+	return &memoryRepository{
+		subscriptions: map[string]Subscription{},
+		deliveries:    map[string]Delivery{},
+	}
+}
+
+// CreateSubscription adds the webhook subscription
+func (repo *memoryRepository) CreateSubscription(ctx context.Context, subscription *Subscription) error {
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	repo.subscriptions[subscription.SubscriptionID] = *subscription
+	return nil
+}
+
+// GetSubscription returns the webhook subscription
+func (repo *memoryRepository) GetSubscription(ctx context.Context, subscriptionID string) (*Subscription, error) {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	subscription, ok := repo.subscriptions[subscriptionID]
+	if !ok {
+		return nil, ErrSubscriptionDoesNotExist
+	}
+	return &subscription, nil
+}
+
+// ListSubscriptions returns the webhook subscriptions of the owner, oldest first
+func (repo *memoryRepository) ListSubscriptions(ctx context.Context, ownerType, ownerID string) ([]*Subscription, error) {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	var subscriptions []*Subscription
+	for _, subscription := range repo.subscriptions {
+		if subscription.OwnerKey == ownerKey(ownerType, ownerID) {
+			out := subscription
+			subscriptions = append(subscriptions, &out)
+		}
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].DateCreated < subscriptions[j].DateCreated
+	})
+	return subscriptions, nil
+}
+
+// DeleteSubscription deletes the webhook subscription
+func (repo *memoryRepository) DeleteSubscription(ctx context.Context, subscriptionID string) error {
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	if _, ok := repo.subscriptions[subscriptionID]; !ok {
+		return ErrSubscriptionDoesNotExist
+	}
+	delete(repo.subscriptions, subscriptionID)
+	return nil
+}
+
+// PutDelivery creates or replaces the delivery record
+func (repo *memoryRepository) PutDelivery(ctx context.Context, delivery *Delivery) error {
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	repo.deliveries[delivery.DeliveryID] = *delivery
+	return nil
+}
+
+// GetDelivery returns the delivery record
+func (repo *memoryRepository) GetDelivery(ctx context.Context, deliveryID string) (*Delivery, error) {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	delivery, ok := repo.deliveries[deliveryID]
+	if !ok {
+		return nil, ErrDeliveryDoesNotExist
+	}
+	return &delivery, nil
+}
+
+// ListDueDeliveries returns the pending deliveries with a next attempt time before the epoch
+func (repo *memoryRepository) ListDueDeliveries(ctx context.Context, epoch int64) ([]*Delivery, error) {
+	return repo.filterDeliveries(func(delivery Delivery) bool {
+		return delivery.Status == DeliveryStatusPending && delivery.NextAttemptEpoch <= epoch
+	}), nil
+}
+
+// ListSubscriptionDeliveries returns the deliveries of the subscription with the status
+func (repo *memoryRepository) ListSubscriptionDeliveries(ctx context.Context, subscriptionID, status string) ([]*Delivery, error) {
+	return repo.filterDeliveries(func(delivery Delivery) bool {
+		return delivery.SubscriptionID == subscriptionID && delivery.Status == status
+	}), nil
+}
+
+// filterDeliveries returns the matching deliveries, oldest first
+func (repo *memoryRepository) filterDeliveries(match func(delivery Delivery) bool) []*Delivery {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	var deliveries []*Delivery
+	for _, delivery := range repo.deliveries {
+		if match(delivery) {
+			out := delivery
+			deliveries = append(deliveries, &out)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		if deliveries[i].DateCreated == deliveries[j].DateCreated {
+			return deliveries[i].DeliveryID < deliveries[j].DeliveryID
+		}
+		return deliveries[i].DateCreated < deliveries[j].DateCreated
+	})
+	return deliveries
+}
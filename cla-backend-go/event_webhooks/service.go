@@ -0,0 +1,363 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package event_webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// defaultHTTPTimeout is the timeout of a single delivery attempt
+const defaultHTTPTimeout = 10 * time.Second
+
+// deliveredRetention is how long the delivered records are kept before the table TTL removes them
+const deliveredRetention = 30 * 24 * time.Hour
+
+// errors
+var (
+	ErrInvalidOwnerType        = errors.New("invalid subscription owner type")
+	ErrInvalidURL              = errors.New("subscription url must be an absolute https url")
+	ErrSubscriptionDisabled    = errors.New("event webhook subscription is disabled")
+	ErrDeliveryNotDeadLettered = errors.New("only dead lettered deliveries can be redelivered")
+)
+
+// SubscriptionInput is the input of a new webhook subscription
+type SubscriptionInput struct {
+	OwnerType   string
+	OwnerID     string
+	URL         string
+	Description string
+	EventTypes  []string
+	// Secret is the HMAC key of the signatures, a random secret is generated when empty
+	Secret    string
+	CreatedBy string
+}
+
+// Service interface defines the event webhooks service methods
+type Service interface {
+	CreateSubscription(ctx context.Context, input *SubscriptionInput) (*Subscription, error)
+	GetSubscription(ctx context.Context, subscriptionID string) (*Subscription, error)
+	ListSubscriptions(ctx context.Context, ownerType, ownerID string) ([]*Subscription, error)
+	DeleteSubscription(ctx context.Context, subscriptionID string) error
+
+	PublishEvent(ctx context.Context, event *events.Event) error
+	ProcessDueDeliveries(ctx context.Context) (int, error)
+	ListDeadLetters(ctx context.Context, subscriptionID string) ([]*Delivery, error)
+	Redeliver(ctx context.Context, subscriptionID, deliveryID string) (*Delivery, error)
+}
+
+type service struct {
+	repo         Repository
+	httpClient   *http.Client
+	retryPolicy  RetryPolicy
+	now          func() time.Time
+	lookupIPAddr func(ctx context.Context, host string) ([]net.IPAddr, error)
+	allowAddress func(ip net.IP) bool
+}
+
+// NewService creates a new event webhooks service, a default http client refusing to connect to internal
+// addresses is used when nil
+func NewService(repo Repository, httpClient *http.Client, retryPolicy RetryPolicy) Service {
+	if httpClient == nil {
+		httpClient = newDeliveryHTTPClient(isPublicAddress)
+	}
+	return &service{
+		repo:         repo,
+		httpClient:   httpClient,
+		retryPolicy:  retryPolicy,
+		now:          time.Now,
+		lookupIPAddr: net.DefaultResolver.LookupIPAddr,
+		allowAddress: isPublicAddress,
+	}
+}
+
+// CreateSubscription validates and adds the webhook subscription, the endpoint must not resolve to an internal address
+func (s *service) CreateSubscription(ctx context.Context, input *SubscriptionInput) (*Subscription, error) {
+	if input.OwnerType != OwnerTypeCLAGroup && input.OwnerType != OwnerTypeCompany {
+		return nil, ErrInvalidOwnerType
+	}
+	endpoint, err := url.Parse(input.URL)
+	if err != nil || endpoint.Scheme != "https" || endpoint.Host == "" {
+		return nil, ErrInvalidURL
+	}
+	if err := s.checkEndpoint(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	secret := input.Secret
+	if secret == "" {
+		secret, err = newSecret()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var eventTypes []string
+	for _, eventType := range input.EventTypes {
+		if eventType = strings.TrimSpace(eventType); eventType != "" {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+
+	subscriptionID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	_, currentTime := utils.CurrentTime()
+	subscription := &Subscription{
+		SubscriptionID: subscriptionID.String(),
+		OwnerType:      input.OwnerType,
+		OwnerID:        input.OwnerID,
+		OwnerKey:       ownerKey(input.OwnerType, input.OwnerID),
+		URL:            endpoint.String(),
+		Secret:         secret,
+		EventTypes:     eventTypes,
+		Description:    input.Description,
+		Enabled:        true,
+		CreatedBy:      input.CreatedBy,
+		DateCreated:    currentTime,
+		DateModified:   currentTime,
+		Version:        "v1",
+	}
+	if err := s.repo.CreateSubscription(ctx, subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// GetSubscription returns the webhook subscription
+func (s *service) GetSubscription(ctx context.Context, subscriptionID string) (*Subscription, error) {
+	return s.repo.GetSubscription(ctx, subscriptionID)
+}
+
+// ListSubscriptions returns the webhook subscriptions of the CLA group or the company
+func (s *service) ListSubscriptions(ctx context.Context, ownerType, ownerID string) ([]*Subscription, error) {
+	return s.repo.ListSubscriptions(ctx, ownerType, ownerID)
+}
+
+// DeleteSubscription deletes the webhook subscription, its pending deliveries are dead lettered on their next attempt
+func (s *service) DeleteSubscription(ctx context.Context, subscriptionID string) error {
+	return s.repo.DeleteSubscription(ctx, subscriptionID)
+}
+
+// PublishEvent adds a pending delivery to the outbox for each subscription of the event CLA group and company,
+// the deliveries are sent by ProcessDueDeliveries so a slow or failing endpoint does not hold up the caller
+func (s *service) PublishEvent(ctx context.Context, event *events.Event) error {
+	f := logrus.Fields{
+		"functionName":   "v1.event_webhooks.service.PublishEvent",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"eventID":        event.EventID,
+		"eventType":      event.EventType,
+		"claGroupID":     event.EventCLAGroupID,
+		"companySFID":    event.EventCompanySFID,
+	}
+
+	var subscriptions []*Subscription
+	if event.EventCLAGroupID != "" {
+		claGroupSubscriptions, err := s.repo.ListSubscriptions(ctx, OwnerTypeCLAGroup, event.EventCLAGroupID)
+		if err != nil {
+			return err
+		}
+		subscriptions = append(subscriptions, claGroupSubscriptions...)
+	}
+	if event.EventCompanySFID != "" {
+		companySubscriptions, err := s.repo.ListSubscriptions(ctx, OwnerTypeCompany, event.EventCompanySFID)
+		if err != nil {
+			return err
+		}
+		subscriptions = append(subscriptions, companySubscriptions...)
+	}
+
+	for _, subscription := range subscriptions {
+		if !subscription.Matches(event.EventType) {
+			continue
+		}
+		delivery, err := s.newDelivery(subscription, event)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.PutDelivery(ctx, delivery); err != nil {
+			return err
+		}
+		log.WithFields(f).Debugf("event delivery %s to subscription %s is queued", delivery.DeliveryID, subscription.SubscriptionID)
+	}
+	return nil
+}
+
+// ProcessDueDeliveries sends the new pending deliveries and retries the failed ones which are due, it returns the
+// number of attempts made
+func (s *service) ProcessDueDeliveries(ctx context.Context) (int, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.event_webhooks.service.ProcessDueDeliveries",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+	}
+
+	deliveries, err := s.repo.ListDueDeliveries(ctx, s.now().Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	subscriptions := map[string]*Subscription{}
+	attempts := 0
+	for _, delivery := range deliveries {
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			subscription, err = s.repo.GetSubscription(ctx, delivery.SubscriptionID)
+			if err != nil && err != ErrSubscriptionDoesNotExist {
+				return attempts, err
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+
+		switch {
+		case subscription == nil:
+			err = s.deadLetter(ctx, delivery, ErrSubscriptionDoesNotExist)
+		case !subscription.Enabled:
+			err = s.deadLetter(ctx, delivery, ErrSubscriptionDisabled)
+		default:
+			attempts++
+			err = s.attempt(ctx, subscription, delivery)
+		}
+		if err != nil {
+			log.WithFields(f).WithError(err).Warnf("unable to process the delivery: %s", delivery.DeliveryID)
+			return attempts, err
+		}
+	}
+	return attempts, nil
+}
+
+// ListDeadLetters returns the deliveries of the subscription which ran out of attempts
+func (s *service) ListDeadLetters(ctx context.Context, subscriptionID string) ([]*Delivery, error) {
+	return s.repo.ListSubscriptionDeliveries(ctx, subscriptionID, DeliveryStatusDeadLetter)
+}
+
+// Redeliver resets the attempts of the dead lettered delivery and sends it again
+func (s *service) Redeliver(ctx context.Context, subscriptionID, deliveryID string) (*Delivery, error) {
+	delivery, err := s.repo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.SubscriptionID != subscriptionID {
+		return nil, ErrDeliveryDoesNotExist
+	}
+	if delivery.Status != DeliveryStatusDeadLetter {
+		return nil, ErrDeliveryNotDeadLettered
+	}
+	subscription, err := s.repo.GetSubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if !subscription.Enabled {
+		return nil, ErrSubscriptionDisabled
+	}
+
+	delivery.Status = DeliveryStatusPending
+	delivery.Attempts = 0
+	if err := s.attempt(ctx, subscription, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// newDelivery returns the pending delivery of the event to the subscription
+func (s *service) newDelivery(subscription *Subscription, event *events.Event) (*Delivery, error) {
+	deliveryID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(&Payload{
+		DeliveryID:     deliveryID.String(),
+		SubscriptionID: subscription.SubscriptionID,
+		EventID:        event.EventID,
+		EventType:      event.EventType,
+		EventTime:      event.EventTime,
+		EventTimeEpoch: event.EventTimeEpoch,
+		CLAGroupID:     event.EventCLAGroupID,
+		CLAGroupName:   event.EventCLAGroupName,
+		ProjectSFID:    event.EventProjectSFID,
+		ProjectName:    event.EventProjectName,
+		CompanyID:      event.EventCompanyID,
+		CompanySFID:    event.EventCompanySFID,
+		CompanyName:    event.EventCompanyName,
+		UserID:         event.EventUserID,
+		LfUsername:     event.EventLfUsername,
+		EventData:      event.EventData,
+		EventSummary:   event.EventSummary,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	return &Delivery{
+		DeliveryID:       deliveryID.String(),
+		SubscriptionID:   subscription.SubscriptionID,
+		EventID:          event.EventID,
+		EventType:        event.EventType,
+		Payload:          string(payload),
+		Status:           DeliveryStatusPending,
+		NextAttemptEpoch: now.Unix(),
+		DateCreated:      utils.TimeToString(now),
+		DateModified:     utils.TimeToString(now),
+		Version:          "v1",
+	}, nil
+}
+
+// attempt posts the delivery and records the outcome - the delivery is scheduled for a retry with exponential
+// backoff or dead lettered when it runs out of attempts
+func (s *service) attempt(ctx context.Context, subscription *Subscription, delivery *Delivery) error {
+	now := s.now()
+	result := post(ctx, s.httpClient, subscription, delivery, now)
+
+	delivery.Attempts++
+	delivery.LastAttemptTime = utils.TimeToString(now)
+	delivery.LastStatusCode = result.statusCode
+	delivery.DateModified = utils.TimeToString(now)
+	switch {
+	case result.err == nil:
+		delivery.Status = DeliveryStatusDelivered
+		delivery.LastError = ""
+		delivery.Expires = now.Add(deliveredRetention).Unix()
+	case delivery.Attempts >= s.retryPolicy.MaxAttempts:
+		delivery.Status = DeliveryStatusDeadLetter
+		delivery.LastError = result.err.Error()
+	default:
+		delivery.Status = DeliveryStatusPending
+		delivery.LastError = result.err.Error()
+		delivery.NextAttemptEpoch = now.Add(s.retryPolicy.NextInterval(delivery.Attempts)).Unix()
+	}
+	return s.repo.PutDelivery(ctx, delivery)
+}
+
+// deadLetter moves the delivery to the dead letter list without an attempt
+func (s *service) deadLetter(ctx context.Context, delivery *Delivery, reason error) error {
+	delivery.Status = DeliveryStatusDeadLetter
+	delivery.LastError = reason.Error()
+	delivery.DateModified = utils.TimeToString(s.now())
+	return s.repo.PutDelivery(ctx, delivery)
+}
+
+// newSecret returns a random hex encoded signing secret
+func newSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
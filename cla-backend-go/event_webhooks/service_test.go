@@ -0,0 +1,252 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package event_webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+// receiver is a webhook endpoint verifying the signatures and failing the first requests
+type receiver struct {
+	lock     sync.Mutex
+	secret   string
+	failures int
+	payloads []Payload
+	invalid  int
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	body, _ := ioutil.ReadAll(req.Body)
+	if !VerifySignature(r.secret, req.Header.Get(TimestampHeader), body, req.Header.Get(SignatureHeader)) {
+		r.invalid++
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.failures > 0 {
+		r.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	var payload Payload
+	_ = json.Unmarshal(body, &payload)
+	if payload.DeliveryID != req.Header.Get(DeliveryHeader) || payload.EventType != req.Header.Get(EventHeader) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.payloads = append(r.payloads, payload)
+}
+
+// newTestService returns a service delivering to the loopback test server
+func newTestService(server *httptest.Server, policy RetryPolicy) (*service, Repository) {
+	repo := NewMemoryRepository()
+	s := NewService(repo, server.Client(), policy).(*service)
+	s.allowAddress = func(ip net.IP) bool { return ip.IsLoopback() }
+	return s, repo
+}
+
+// staticLookup resolves the hosts from the map
+func staticLookup(hosts map[string]string) func(ctx context.Context, host string) ([]net.IPAddr, error) {
+	return func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		address, ok := hosts[host]
+		if !ok {
+			return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+		}
+		return []net.IPAddr{{IP: net.ParseIP(address)}}, nil
+	}
+}
+
+func TestCreateSubscriptionValidation(t *testing.T) {
+	s := NewService(NewMemoryRepository(), nil, DefaultRetryPolicy).(*service)
+	s.lookupIPAddr = staticLookup(map[string]string{"example.org": "93.184.216.34"})
+	ctx := context.Background()
+
+	_, err := s.CreateSubscription(ctx, &SubscriptionInput{OwnerType: OwnerTypeCLAGroup, OwnerID: "cla-group-1", URL: "http://example.org/hook"})
+	assert.Equal(t, ErrInvalidURL, err)
+	_, err = s.CreateSubscription(ctx, &SubscriptionInput{OwnerType: "project", OwnerID: "cla-group-1", URL: "https://example.org/hook"})
+	assert.Equal(t, ErrInvalidOwnerType, err)
+
+	subscription, err := s.CreateSubscription(ctx, &SubscriptionInput{
+		OwnerType:  OwnerTypeCompany,
+		OwnerID:    "company-sfid",
+		URL:        "https://example.org/hook",
+		EventTypes: []string{" cla_manager.added ", ""},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, subscription.Secret, 64)
+	assert.Equal(t, []string{"cla_manager.added"}, subscription.EventTypes)
+	assert.True(t, subscription.Matches(events.ClaManagerCreated))
+	assert.False(t, subscription.Matches(events.InvalidatedSignature))
+}
+
+func TestCreateSubscriptionRejectsInternalAddresses(t *testing.T) {
+	s := NewService(NewMemoryRepository(), nil, DefaultRetryPolicy).(*service)
+	s.lookupIPAddr = staticLookup(map[string]string{
+		"localhost":         "127.0.0.1",
+		"intranet.example":  "10.1.2.3",
+		"router.example":    "192.168.0.1",
+		"docker.example":    "172.17.0.1",
+		"ula.example":       "fd00::1",
+		"public.example":    "93.184.216.34",
+		"public-v6.example": "2606:2800:220:1:248:1893:25c8:1946",
+	})
+	ctx := context.Background()
+
+	for _, endpoint := range []string{
+		"https://localhost/hook",
+		"https://intranet.example/hook",
+		"https://router.example:8443/hook",
+		"https://docker.example/hook",
+		"https://ula.example/hook",
+		"https://169.254.169.254/latest/meta-data/",
+		"https://127.0.0.1/hook",
+		"https://[::1]/hook",
+		"https://[::ffff:10.0.0.1]/hook",
+		"https://0.0.0.0/hook",
+	} {
+		_, err := s.CreateSubscription(ctx, &SubscriptionInput{OwnerType: OwnerTypeCLAGroup, OwnerID: "cla-group-1", URL: endpoint})
+		assert.Equal(t, ErrForbiddenAddress, err, endpoint)
+	}
+
+	_, err := s.CreateSubscription(ctx, &SubscriptionInput{OwnerType: OwnerTypeCLAGroup, OwnerID: "cla-group-1", URL: "https://unknown.example/hook"})
+	assert.Error(t, err)
+
+	for _, endpoint := range []string{"https://public.example/hook", "https://public-v6.example/hook"} {
+		_, err = s.CreateSubscription(ctx, &SubscriptionInput{OwnerType: OwnerTypeCLAGroup, OwnerID: "cla-group-1", URL: endpoint})
+		assert.Nil(t, err, endpoint)
+	}
+}
+
+func TestDeliveryClientRefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewTLSServer(&receiver{})
+	defer server.Close()
+
+	// the endpoint could resolve to another address by the time of the delivery
+	_, err := newDeliveryHTTPClient(isPublicAddress).Get(server.URL)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), ErrForbiddenAddress.Error())
+	}
+}
+
+func TestPublishEventDeliversSignedPayloads(t *testing.T) {
+	ctx := context.Background()
+	r := &receiver{secret: "0123456789abcdef"}
+	server := httptest.NewTLSServer(r)
+	defer server.Close()
+	s, _ := newTestService(server, DefaultRetryPolicy)
+
+	claGroupSubscription, err := s.CreateSubscription(ctx, &SubscriptionInput{
+		OwnerType: OwnerTypeCLAGroup, OwnerID: "cla-group-1", URL: server.URL, Secret: r.secret,
+	})
+	assert.Nil(t, err)
+	_, err = s.CreateSubscription(ctx, &SubscriptionInput{
+		OwnerType: OwnerTypeCompany, OwnerID: "company-sfid", URL: server.URL, Secret: r.secret,
+		EventTypes: []string{events.ClaApprovalListUpdated},
+	})
+	assert.Nil(t, err)
+
+	// the CLA group subscription receives all the events, the company one only the approval list updates
+	assert.Nil(t, s.PublishEvent(ctx, &events.Event{
+		EventID: "event-1", EventType: events.ClaManagerCreated, EventCLAGroupID: "cla-group-1", EventCompanySFID: "company-sfid",
+	}))
+	assert.Nil(t, s.PublishEvent(ctx, &events.Event{
+		EventID: "event-2", EventType: events.ClaApprovalListUpdated, EventCLAGroupID: "cla-group-1", EventCompanySFID: "company-sfid",
+	}))
+	assert.Nil(t, s.PublishEvent(ctx, &events.Event{
+		EventID: "event-3", EventType: events.ClaManagerCreated, EventCLAGroupID: "cla-group-2",
+	}))
+
+	// the deliveries are only queued by the publication
+	assert.Empty(t, r.payloads)
+	attempts, err := s.ProcessDueDeliveries(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, attempts)
+
+	assert.Equal(t, 0, r.invalid)
+	if assert.Len(t, r.payloads, 3) {
+		assert.Equal(t, "event-1", r.payloads[0].EventID)
+		assert.Equal(t, claGroupSubscription.SubscriptionID, r.payloads[0].SubscriptionID)
+		assert.Equal(t, "event-2", r.payloads[1].EventID)
+		assert.Equal(t, "event-2", r.payloads[2].EventID)
+	}
+}
+
+func TestFailedDeliveriesAreRetriedThenDeadLettered(t *testing.T) {
+	ctx := context.Background()
+	r := &receiver{secret: "0123456789abcdef", failures: 3}
+	server := httptest.NewTLSServer(r)
+	defer server.Close()
+	s, repo := newTestService(server, RetryPolicy{MaxAttempts: 3, InitialInterval: time.Minute, MaxInterval: time.Hour})
+	now := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	subscription, err := s.CreateSubscription(ctx, &SubscriptionInput{
+		OwnerType: OwnerTypeCLAGroup, OwnerID: "cla-group-1", URL: server.URL, Secret: r.secret,
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, s.PublishEvent(ctx, &events.Event{EventID: "event-1", EventType: events.InvalidatedSignature, EventCLAGroupID: "cla-group-1"}))
+
+	// the queued delivery is sent right away and fails
+	attempts, err := s.ProcessDueDeliveries(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, attempts)
+
+	// nothing is due before the backoff interval
+	attempts, err = s.ProcessDueDeliveries(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, attempts)
+
+	// 1 minute then 2 minutes of backoff
+	now = now.Add(time.Minute)
+	attempts, err = s.ProcessDueDeliveries(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, attempts)
+	now = now.Add(time.Minute)
+	attempts, _ = s.ProcessDueDeliveries(ctx)
+	assert.Equal(t, 0, attempts)
+	now = now.Add(time.Minute)
+	attempts, _ = s.ProcessDueDeliveries(ctx)
+	assert.Equal(t, 1, attempts)
+
+	deadLetters, err := s.ListDeadLetters(ctx, subscription.SubscriptionID)
+	assert.Nil(t, err)
+	if !assert.Len(t, deadLetters, 1) {
+		return
+	}
+	assert.Equal(t, 3, deadLetters[0].Attempts)
+	assert.Equal(t, http.StatusServiceUnavailable, deadLetters[0].LastStatusCode)
+	assert.Empty(t, r.payloads)
+
+	// the endpoint recovered - the redelivery sends the original payload
+	delivery, err := s.Redeliver(ctx, subscription.SubscriptionID, deadLetters[0].DeliveryID)
+	assert.Nil(t, err)
+	assert.Equal(t, DeliveryStatusDelivered, delivery.Status)
+	if assert.Len(t, r.payloads, 1) {
+		assert.Equal(t, deadLetters[0].DeliveryID, r.payloads[0].DeliveryID)
+	}
+	_, err = s.Redeliver(ctx, subscription.SubscriptionID, delivery.DeliveryID)
+	assert.Equal(t, ErrDeliveryNotDeadLettered, err)
+
+	// pending deliveries of deleted subscriptions are dead lettered without an attempt
+	assert.Nil(t, s.PublishEvent(ctx, &events.Event{EventID: "event-2", EventType: events.InvalidatedSignature, EventCLAGroupID: "cla-group-1"}))
+	assert.Nil(t, repo.DeleteSubscription(ctx, subscription.SubscriptionID))
+	now = now.Add(time.Hour)
+	attempts, err = s.ProcessDueDeliveries(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, attempts)
+	deadLetters, _ = s.ListDeadLetters(ctx, subscription.SubscriptionID)
+	assert.Len(t, deadLetters, 1)
+}
@@ -0,0 +1,360 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package events
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+)
+
+// event chain stream types - the events of a CLA group are chained in the CLA group stream, the other events of a
+// company are chained in the company stream
+const (
+	ChainStreamTypeCLAGroup = "cla-group"
+	ChainStreamTypeCompany  = "company"
+)
+
+// ChainGenesisHash is the previous hash of the first event of a stream
+var ChainGenesisHash = strings.Repeat("0", sha256.Size*2)
+
+// ChainCheckpointAlgorithm is the algorithm signing the checkpoint exports
+const ChainCheckpointAlgorithm = "ed25519"
+
+// errors
+var (
+	ErrInvalidChainStreamType = errors.New("invalid event stream type, expecting cla-group or company")
+	ErrInvalidChainSigningKey = errors.New("invalid checkpoint signing key, expecting a base64 encoded ed25519 seed or private key")
+)
+
+// ChainHead is the last event appended to an event stream
+type ChainHead struct {
+	Stream       string `dynamodbav:"chain_stream" json:"stream"`
+	Sequence     int64  `dynamodbav:"chain_sequence" json:"sequence"`
+	HeadHash     string `dynamodbav:"chain_head_hash" json:"head_hash"`
+	HeadEventID  string `dynamodbav:"chain_head_event_id" json:"head_event_id"`
+	DateModified string `dynamodbav:"date_modified" json:"date_modified"`
+}
+
+// ChainBreak is an event where the stream doesn't match its hash chain
+type ChainBreak struct {
+	Sequence int64  `json:"sequence"`
+	EventID  string `json:"event_id,omitempty"`
+	Reason   string `json:"reason"`
+}
+
+// ChainVerification is the result of walking an event stream
+type ChainVerification struct {
+	Stream         string        `json:"stream"`
+	EventsVerified int64         `json:"events_verified"`
+	HeadSequence   int64         `json:"head_sequence"`
+	HeadHash       string        `json:"head_hash"`
+	Valid          bool          `json:"valid"`
+	Breaks         []*ChainBreak `json:"breaks"`
+	DateVerified   string        `json:"date_verified"`
+}
+
+// ChainCheckpoint is the head of an event stream at the time of the export
+type ChainCheckpoint struct {
+	Stream   string `json:"stream"`
+	Sequence int64  `json:"sequence"`
+	HeadHash string `json:"head_hash"`
+}
+
+// ChainCheckpointExport is the signed list of the stream heads, a later truncation or rewrite of a stream no
+// longer matches the exported checkpoint
+type ChainCheckpointExport struct {
+	DateCreated string             `json:"date_created"`
+	Algorithm   string             `json:"algorithm"`
+	PublicKey   string             `json:"public_key"`
+	Checkpoints []*ChainCheckpoint `json:"checkpoints"`
+	Signature   string             `json:"signature"`
+}
+
+// chainedEventContent is the part of the event covered by the hash - the SFIDs and names added to the event after
+// it is created are not covered
+type chainedEventContent struct {
+	Stream         string `json:"stream"`
+	Sequence       int64  `json:"sequence"`
+	PrevHash       string `json:"prev_hash"`
+	EventID        string `json:"event_id"`
+	EventType      string `json:"event_type"`
+	UserID         string `json:"user_id"`
+	UserName       string `json:"user_name"`
+	LfUsername     string `json:"lf_username"`
+	CLAGroupID     string `json:"cla_group_id"`
+	CompanyID      string `json:"company_id"`
+	ProjectID      string `json:"project_id"`
+	EventTime      string `json:"event_time"`
+	EventTimeEpoch int64  `json:"event_time_epoch"`
+	EventData      string `json:"event_data"`
+	EventSummary   string `json:"event_summary"`
+	ContainsPII    bool   `json:"contains_pii"`
+}
+
+// ChainStreamKey returns the key of the event stream
+func ChainStreamKey(streamType, streamID string) (string, error) {
+	if streamType != ChainStreamTypeCLAGroup && streamType != ChainStreamTypeCompany {
+		return "", ErrInvalidChainStreamType
+	}
+	if streamID == "" {
+		return "", fmt.Errorf("missing %s id of the event stream", streamType)
+	}
+	return fmt.Sprintf("%s#%s", streamType, streamID), nil
+}
+
+// ChainStream returns the key of the stream the event is chained in, empty when the event belongs to neither a
+// CLA group nor a company
+func ChainStream(event *models.Event) string {
+	switch {
+	case event.EventCLAGroupID != "":
+		return fmt.Sprintf("%s#%s", ChainStreamTypeCLAGroup, event.EventCLAGroupID)
+	case event.EventCompanyID != "":
+		return fmt.Sprintf("%s#%s", ChainStreamTypeCompany, event.EventCompanyID)
+	default:
+		return ""
+	}
+}
+
+// parseChainStream returns the type and the ID of the stream
+func parseChainStream(stream string) (string, string) {
+	parts := strings.SplitN(stream, "#", 2)
+	if len(parts) != 2 {
+		return "", ""
+	}
+	return parts[0], parts[1]
+}
+
+// ChainEventHash returns the hex encoded SHA-256 of the event content and its chain fields
+func ChainEventHash(event *models.Event) string {
+	content, err := json.Marshal(chainedEventContent{
+		Stream:         event.EventChainStream,
+		Sequence:       event.EventChainSequence,
+		PrevHash:       event.EventPrevHash,
+		EventID:        event.EventID,
+		EventType:      event.EventType,
+		UserID:         event.UserID,
+		UserName:       event.UserName,
+		LfUsername:     event.LfUsername,
+		CLAGroupID:     event.EventCLAGroupID,
+		CompanyID:      event.EventCompanyID,
+		ProjectID:      event.EventProjectID,
+		EventTime:      event.EventTime,
+		EventTimeEpoch: event.EventTimeEpoch,
+		EventData:      event.EventData,
+		EventSummary:   event.EventSummary,
+		ContainsPII:    event.ContainsPII,
+	})
+	if err != nil {
+		// a struct of strings, numbers and booleans always marshals
+		panic(err)
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// linkEvent sets the chain fields of the event appended after the head, a nil head starts the stream. It returns
+// the new head of the stream.
+func linkEvent(head *ChainHead, stream string, event *models.Event) *ChainHead {
+	event.EventChainStream = stream
+	event.EventChainSequence = 1
+	event.EventPrevHash = ChainGenesisHash
+	if head != nil {
+		event.EventChainSequence = head.Sequence + 1
+		event.EventPrevHash = head.HeadHash
+	}
+	event.EventHash = ChainEventHash(event)
+
+	return &ChainHead{
+		Stream:       stream,
+		Sequence:     event.EventChainSequence,
+		HeadHash:     event.EventHash,
+		HeadEventID:  event.EventID,
+		DateModified: event.EventTime,
+	}
+}
+
+// chainVerifier walks the events of a stream ordered by sequence and records the breaks of the chain
+type chainVerifier struct {
+	result   *ChainVerification
+	sequence int64
+	prevHash string
+	// epochs and sequences of the verified events, the unchained events are placed after the last event preceding
+	// them
+	epochs    []int64
+	sequences []int64
+}
+
+func newChainVerifier(stream string) *chainVerifier {
+	return &chainVerifier{
+		result: &ChainVerification{
+			Stream: stream,
+			Breaks: []*ChainBreak{},
+		},
+		prevHash: ChainGenesisHash,
+	}
+}
+
+func (v *chainVerifier) addBreak(sequence int64, eventID, reason string) {
+	v.result.Breaks = append(v.result.Breaks, &ChainBreak{Sequence: sequence, EventID: eventID, Reason: reason})
+}
+
+// verify checks the next event of the stream
+func (v *chainVerifier) verify(event *models.Event) {
+	v.result.EventsVerified++
+	expected := v.sequence + 1
+	switch {
+	case event.EventChainSequence > expected:
+		v.addBreak(expected, "", fmt.Sprintf("events %d to %d are missing", expected, event.EventChainSequence-1))
+	case event.EventChainSequence < expected:
+		v.addBreak(event.EventChainSequence, event.EventID, "duplicate sequence number")
+	}
+	if event.EventPrevHash != v.prevHash {
+		v.addBreak(event.EventChainSequence, event.EventID, "previous hash does not match the previous event")
+	}
+	if ChainEventHash(event) != event.EventHash {
+		v.addBreak(event.EventChainSequence, event.EventID, "event content does not match its hash")
+	}
+
+	// continue from the stored hash, a single altered event is reported once
+	v.sequence = event.EventChainSequence
+	v.prevHash = event.EventHash
+	v.epochs = append(v.epochs, event.EventTimeEpoch)
+	v.sequences = append(v.sequences, event.EventChainSequence)
+}
+
+// coveredRange returns the event time epochs of the first and the last verified events, false when no event was
+// verified
+func (v *chainVerifier) coveredRange() (int64, int64, bool) {
+	if len(v.epochs) == 0 {
+		return 0, 0, false
+	}
+	first, last := v.epochs[0], v.epochs[0]
+	for _, epoch := range v.epochs {
+		if epoch < first {
+			first = epoch
+		}
+		if epoch > last {
+			last = epoch
+		}
+	}
+	return first, last, true
+}
+
+// unchained reports an event of the stream written without its chain fields, a write bypassing the chain is a gap
+// following the last chained event logged before it
+func (v *chainVerifier) unchained(event *models.Event) {
+	var sequence int64
+	for i, epoch := range v.epochs {
+		if epoch <= event.EventTimeEpoch {
+			sequence = v.sequences[i]
+		}
+	}
+	v.addBreak(sequence, event.EventID, "event is not chained in the stream")
+}
+
+// finish compares the last event with the head of the stream - events removed from the end of the stream are
+// only detected by the head
+func (v *chainVerifier) finish(head *ChainHead, dateVerified string) *ChainVerification {
+	v.result.HeadSequence = v.sequence
+	v.result.HeadHash = v.prevHash
+	if head != nil {
+		if head.Sequence > v.sequence {
+			v.addBreak(v.sequence+1, "", fmt.Sprintf("events %d to %d are missing from the end of the stream", v.sequence+1, head.Sequence))
+		} else if head.HeadHash != v.prevHash {
+			v.addBreak(v.sequence, "", "the head of the stream does not match the last event")
+		}
+	} else if v.sequence > 0 {
+		v.addBreak(v.sequence, "", "the head of the stream is missing")
+	}
+	v.result.Valid = len(v.result.Breaks) == 0
+	v.result.DateVerified = dateVerified
+	return v.result
+}
+
+// VerifyChainEvents walks the events of the stream, ordered by sequence, and reports any break of the chain along
+// with the unchained events of the stream
+func VerifyChainEvents(stream string, events []*models.Event, unchained []*models.Event, head *ChainHead, dateVerified string) *ChainVerification {
+	verifier := newChainVerifier(stream)
+	for _, event := range events {
+		verifier.verify(event)
+	}
+	for _, event := range unchained {
+		verifier.unchained(event)
+	}
+	return verifier.finish(head, dateVerified)
+}
+
+// ParseChainSigningKey decodes the base64 encoded ed25519 seed or private key signing the checkpoint exports
+func ParseChainSigningKey(encoded string) (ed25519.PrivateKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, ErrInvalidChainSigningKey
+	}
+	switch len(key) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(key), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(key), nil
+	default:
+		return nil, ErrInvalidChainSigningKey
+	}
+}
+
+// checkpointSigningPayload returns the signed content of the export
+func checkpointSigningPayload(export *ChainCheckpointExport) []byte {
+	payload, err := json.Marshal(struct {
+		DateCreated string             `json:"date_created"`
+		Algorithm   string             `json:"algorithm"`
+		PublicKey   string             `json:"public_key"`
+		Checkpoints []*ChainCheckpoint `json:"checkpoints"`
+	}{
+		DateCreated: export.DateCreated,
+		Algorithm:   export.Algorithm,
+		PublicKey:   export.PublicKey,
+		Checkpoints: export.Checkpoints,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return payload
+}
+
+// SignChainCheckpoints returns the export of the stream heads signed with the key
+func SignChainCheckpoints(key ed25519.PrivateKey, heads []*ChainHead, dateCreated string) *ChainCheckpointExport {
+	export := &ChainCheckpointExport{
+		DateCreated: dateCreated,
+		Algorithm:   ChainCheckpointAlgorithm,
+		PublicKey:   base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+		Checkpoints: make([]*ChainCheckpoint, 0, len(heads)),
+	}
+	for _, head := range heads {
+		export.Checkpoints = append(export.Checkpoints, &ChainCheckpoint{
+			Stream:   head.Stream,
+			Sequence: head.Sequence,
+			HeadHash: head.HeadHash,
+		})
+	}
+	export.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, checkpointSigningPayload(export)))
+	return export
+}
+
+// VerifyChainCheckpoints checks the signature of the export with the published public key
+func VerifyChainCheckpoints(publicKey ed25519.PublicKey, export *ChainCheckpointExport) bool {
+	if export.Algorithm != ChainCheckpointAlgorithm || export.PublicKey != base64.StdEncoding.EncodeToString(publicKey) {
+		return false
+	}
+	signature, err := base64.StdEncoding.DecodeString(export.Signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(publicKey, checkpointSigningPayload(export), signature)
+}
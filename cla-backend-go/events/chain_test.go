@@ -0,0 +1,92 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package events
+
+import (
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testChainCLAGroupID = "d5412fa7-0a29-4a6b-a0d1-6a8d4e6ebf4c"
+	testChainCompanyID  = "0b1e0a4c-8d3b-4b55-9a3e-1d3c2a4f5e6d"
+)
+
+func TestChainEventHash(t *testing.T) {
+	// the python backend hashes the events it chains the same way, see cla/tests/unit/test_event_chain.py
+	event := &models.Event{
+		EventChainStream:   "cla-group#" + testChainCLAGroupID,
+		EventChainSequence: 7,
+		EventPrevHash:      ChainGenesisHash,
+		EventID:            "8f1b5a8e-5b0a-4c8e-9d6a-2f3e4d5c6b7a",
+		EventType:          "AddCLAManager",
+		UserID:             "user-1234",
+		UserName:           "Jane Doë",
+		EventCLAGroupID:    testChainCLAGroupID,
+		EventCompanyID:     testChainCompanyID,
+		EventTime:          "2020-03-04T05:06:07.000008+0000",
+		EventTimeEpoch:     1583298367,
+		EventData:          "<jdoe@example.org> & \"jane\" added as CLA manager",
+		EventSummary:       "CLA manager added\ttwice",
+	}
+	assert.Equal(t, "219db0a84878e5e81ba16c00398fcc734916e3dcdd773d3838fc692909503ced", ChainEventHash(event))
+}
+
+func TestVerifyEventChainReportsUnchainedEvents(t *testing.T) {
+	repo := NewMemoryRepository().(*memoryRepository)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, repo.CreateEvent(&models.Event{
+			EventType:       ClaManagerCreated,
+			UserID:          "user-1234",
+			EventCLAGroupID: testChainCLAGroupID,
+			EventData:       "cla manager added",
+		}))
+	}
+	stream, err := ChainStreamKey(ChainStreamTypeCLAGroup, testChainCLAGroupID)
+	assert.NoError(t, err)
+	chained, err := repo.GetChainEvents(stream, 0, HugePageSize)
+	assert.NoError(t, err)
+	if !assert.Len(t, chained, 3) {
+		return
+	}
+
+	// an event written without the chain within the time range of the stream is a gap, the events logged before
+	// the first chained event are not covered
+	repo.events = append(repo.events,
+		models.Event{
+			EventID:         "unchained-in-range",
+			EventType:       ClaManagerDeleted,
+			UserID:          "user-1234",
+			EventCLAGroupID: testChainCLAGroupID,
+			EventTimeEpoch:  chained[2].EventTimeEpoch,
+		},
+		models.Event{
+			EventID:         "unchained-before",
+			EventType:       ClaManagerDeleted,
+			UserID:          "user-1234",
+			EventCLAGroupID: testChainCLAGroupID,
+			EventTimeEpoch:  chained[0].EventTimeEpoch - 60,
+		},
+		models.Event{
+			EventID:        "unchained-company",
+			EventType:      CompanyACLUserAdded,
+			UserID:         "user-1234",
+			EventCompanyID: testChainCompanyID,
+			EventTimeEpoch: chained[2].EventTimeEpoch,
+		},
+	)
+
+	result, err := NewService(repo, nil).VerifyEventChain(utils.NewContext(), ChainStreamTypeCLAGroup, testChainCLAGroupID)
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, int64(3), result.EventsVerified)
+	if assert.Len(t, result.Breaks, 1) {
+		assert.Equal(t, int64(3), result.Breaks[0].Sequence)
+		assert.Equal(t, "unchained-in-range", result.Breaks[0].EventID)
+		assert.Equal(t, "event is not chained in the stream", result.Breaks[0].Reason)
+	}
+}
@@ -5,6 +5,7 @@ package events
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
@@ -99,12 +100,14 @@ type GerritDeletedEventData struct {
 type GerritUserAddedEventData struct {
 	Username  string
 	GroupName string
+	Reason    string
 }
 
 // GerritUserRemovedEventData data model
 type GerritUserRemovedEventData struct {
 	Username  string
 	GroupName string
+	Reason    string
 }
 
 // GitHubProjectDeletedEventData data model
@@ -126,6 +129,77 @@ type SignatureInvalidatedApprovalRejectionEventData struct {
 	CLAGroupID  string
 }
 
+// SignatureIntegrityFailedEventData data model
+type SignatureIntegrityFailedEventData struct {
+	SignatureID    string
+	Status         string
+	ExpectedSHA256 string
+	ActualSHA256   string
+}
+
+// ResignCampaignStartedEventData data model
+type ResignCampaignStartedEventData struct {
+	CampaignID     string
+	ClaType        string
+	MajorVersion   int
+	GracePeriodEnd string
+	SignerCount    int
+}
+
+// ResignCampaignCancelledEventData data model
+type ResignCampaignCancelledEventData struct {
+	CampaignID   string
+	ClaType      string
+	MajorVersion int
+}
+
+// ResignCampaignCompletedEventData data model
+type ResignCampaignCompletedEventData struct {
+	CampaignID       string
+	ClaType          string
+	MajorVersion     int
+	ResignedCount    int
+	ExpiredCount     int
+	InvalidatedCount int
+}
+
+// APIKeyCreatedEventData data model
+type APIKeyCreatedEventData struct {
+	KeyID       string
+	Name        string
+	Operations  []string
+	DateExpires string
+}
+
+// APIKeyRotatedEventData data model
+type APIKeyRotatedEventData struct {
+	KeyID          string
+	Name           string
+	NewKeyID       string
+	GracePeriodEnd string
+}
+
+// APIKeyRevokedEventData data model
+type APIKeyRevokedEventData struct {
+	KeyID string
+	Name  string
+}
+
+// APIKeyUsedEventData data model
+type APIKeyUsedEventData struct {
+	KeyID       string
+	Name        string
+	OperationID string
+}
+
+// APIKeyRejectedEventData data model
+type APIKeyRejectedEventData struct {
+	KeyID       string
+	Name        string
+	OperationID string
+	Reason      string
+}
+
 // UserCreatedEventData data model
 type UserCreatedEventData struct{}
 
@@ -1066,6 +1140,9 @@ func (ed *GerritUserAddedEventData) GetEventDetailsString(args *LogEventArgs) (s
 	if args.UserName != "" {
 		data = data + fmt.Sprintf(" by the user %s", args.UserName)
 	}
+	if ed.Reason != "" {
+		data = data + fmt.Sprintf(" - reason: %s", ed.Reason)
+	}
 	data = data + "."
 	return data, true
 }
@@ -1076,6 +1153,9 @@ func (ed *GerritUserRemovedEventData) GetEventDetailsString(args *LogEventArgs)
 	if args.UserName != "" {
 		data = data + fmt.Sprintf(" by the user %s", args.UserName)
 	}
+	if ed.Reason != "" {
+		data = data + fmt.Sprintf(" - reason: %s", ed.Reason)
+	}
 	data = data + "."
 	return data, true
 }
@@ -1102,6 +1182,115 @@ func (ed *SignatureProjectInvalidatedEventData) GetEventDetailsString(args *LogE
 	return data, true
 }
 
+// GetEventDetailsString returns the details string for this event
+func (ed *SignatureIntegrityFailedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The signed document of the signature ID: %s failed the integrity check - the document is %s",
+		ed.SignatureID, ed.Status)
+	if ed.ExpectedSHA256 != "" {
+		data = data + fmt.Sprintf(", expected SHA-256: %s", ed.ExpectedSHA256)
+	}
+	if ed.ActualSHA256 != "" {
+		data = data + fmt.Sprintf(", stored document SHA-256: %s", ed.ActualSHA256)
+	}
+	if args.CLAGroupName != "" {
+		data = data + fmt.Sprintf(" for the CLA Group %s", args.CLAGroupName)
+	}
+	data = data + "."
+	return data, true
+}
+
+// GetEventDetailsString returns the details string for this event
+func (ed *ResignCampaignStartedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The re-sign campaign ID: %s asked %d %s signers to sign the major version %d of the document by %s",
+		ed.CampaignID, ed.SignerCount, ed.ClaType, ed.MajorVersion, ed.GracePeriodEnd)
+	if args.CLAGroupName != "" {
+		data = data + fmt.Sprintf(" for the CLA Group %s", args.CLAGroupName)
+	}
+	if args.UserName != "" {
+		data = data + fmt.Sprintf(" by the user %s", args.UserName)
+	}
+	data = data + "."
+	return data, true
+}
+
+// GetEventDetailsString returns the details string for this event
+func (ed *ResignCampaignCancelledEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The re-sign campaign ID: %s for the major version %d of the %s document was cancelled",
+		ed.CampaignID, ed.MajorVersion, ed.ClaType)
+	if args.CLAGroupName != "" {
+		data = data + fmt.Sprintf(" for the CLA Group %s", args.CLAGroupName)
+	}
+	if args.UserName != "" {
+		data = data + fmt.Sprintf(" by the user %s", args.UserName)
+	}
+	data = data + "."
+	return data, true
+}
+
+// GetEventDetailsString returns the details string for this event
+func (ed *ResignCampaignCompletedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The grace period of the re-sign campaign ID: %s for the major version %d of the %s document ended - "+
+		"%d signers re-signed, %d signers did not re-sign and %d stale signatures were invalidated",
+		ed.CampaignID, ed.MajorVersion, ed.ClaType, ed.ResignedCount, ed.ExpiredCount, ed.InvalidatedCount)
+	if args.CLAGroupName != "" {
+		data = data + fmt.Sprintf(" for the CLA Group %s", args.CLAGroupName)
+	}
+	data = data + "."
+	return data, true
+}
+
+// GetEventDetailsString returns the details string for this event
+func (ed *APIKeyCreatedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The API key ID: %s named %s was created for the operations: %s, expiring %s",
+		ed.KeyID, ed.Name, strings.Join(ed.Operations, ", "), ed.DateExpires)
+	data = data + apiKeyScopeString(args)
+	if args.UserName != "" {
+		data = data + fmt.Sprintf(" by the user %s", args.UserName)
+	}
+	data = data + "."
+	return data, true
+}
+
+// GetEventDetailsString returns the details string for this event
+func (ed *APIKeyRotatedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The API key ID: %s named %s was rotated to the API key ID: %s, the rotated key expires %s",
+		ed.KeyID, ed.Name, ed.NewKeyID, ed.GracePeriodEnd)
+	data = data + apiKeyScopeString(args)
+	if args.UserName != "" {
+		data = data + fmt.Sprintf(" by the user %s", args.UserName)
+	}
+	data = data + "."
+	return data, true
+}
+
+// GetEventDetailsString returns the details string for this event
+func (ed *APIKeyRevokedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The API key ID: %s named %s was revoked", ed.KeyID, ed.Name)
+	data = data + apiKeyScopeString(args)
+	if args.UserName != "" {
+		data = data + fmt.Sprintf(" by the user %s", args.UserName)
+	}
+	data = data + "."
+	return data, true
+}
+
+// GetEventDetailsString returns the details string for this event
+func (ed *APIKeyUsedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The API key ID: %s named %s called the operation %s", ed.KeyID, ed.Name, ed.OperationID)
+	data = data + apiKeyScopeString(args)
+	data = data + "."
+	return data, false
+}
+
+// GetEventDetailsString returns the details string for this event
+func (ed *APIKeyRejectedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The API key ID: %s named %s was rejected calling the operation %s, reason: %s",
+		ed.KeyID, ed.Name, ed.OperationID, ed.Reason)
+	data = data + apiKeyScopeString(args)
+	data = data + "."
+	return data, false
+}
+
 // GetEventDetailsString returns the details string for this event
 func (ed *SignatureInvalidatedApprovalRejectionEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
 	reason := noReason
@@ -2029,6 +2218,9 @@ func (ed *GerritUserAddedEventData) GetEventSummaryString(args *LogEventArgs) (s
 	if args.UserName != "" {
 		data = data + fmt.Sprintf(" by the user %s", args.UserName)
 	}
+	if ed.Reason != "" {
+		data = data + fmt.Sprintf(" - reason: %s", ed.Reason)
+	}
 	data = data + "."
 	return data, true
 }
@@ -2045,6 +2237,9 @@ func (ed *GerritUserRemovedEventData) GetEventSummaryString(args *LogEventArgs)
 	if args.UserName != "" {
 		data = data + fmt.Sprintf(" by the user %s", args.UserName)
 	}
+	if ed.Reason != "" {
+		data = data + fmt.Sprintf(" - reason: %s", ed.Reason)
+	}
 	data = data + "."
 	return data, true
 }
@@ -2086,6 +2281,122 @@ func (ed *SignatureProjectInvalidatedEventData) GetEventSummaryString(args *LogE
 	return data, true
 }
 
+// GetEventSummaryString returns the summary string for this event
+func (ed *SignatureIntegrityFailedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The signed document of a signature is %s", ed.Status)
+	if args.CLAGroupName != "" {
+		data = data + fmt.Sprintf(" for the CLA Group %s", args.CLAGroupName)
+	}
+	if args.CompanyName != "" {
+		data = data + fmt.Sprintf(" for the company %s", args.CompanyName)
+	}
+	if args.UserName != "" {
+		data = data + fmt.Sprintf(" for the user %s", args.UserName)
+	}
+	data = data + "."
+	return data, true
+}
+
+// GetEventSummaryString returns the summary string for this event
+func (ed *ResignCampaignStartedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("%d %s signers were asked to sign the new version of the document by %s", ed.SignerCount, ed.ClaType, ed.GracePeriodEnd)
+	if args.CLAGroupName != "" {
+		data = data + fmt.Sprintf(" for the CLA Group %s", args.CLAGroupName)
+	}
+	data = data + "."
+	return data, true
+}
+
+// GetEventSummaryString returns the summary string for this event
+func (ed *ResignCampaignCancelledEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The re-sign campaign for the new version of the %s document was cancelled", ed.ClaType)
+	if args.CLAGroupName != "" {
+		data = data + fmt.Sprintf(" for the CLA Group %s", args.CLAGroupName)
+	}
+	if args.UserName != "" {
+		data = data + fmt.Sprintf(" by the user %s", args.UserName)
+	}
+	data = data + "."
+	return data, true
+}
+
+// GetEventSummaryString returns the summary string for this event
+func (ed *ResignCampaignCompletedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The re-sign campaign for the new version of the %s document ended with %d signers re-signed and %d signatures invalidated",
+		ed.ClaType, ed.ResignedCount, ed.InvalidatedCount)
+	if args.CLAGroupName != "" {
+		data = data + fmt.Sprintf(" for the CLA Group %s", args.CLAGroupName)
+	}
+	data = data + "."
+	return data, true
+}
+
+// GetEventSummaryString returns the summary string for this event
+func (ed *APIKeyCreatedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The API key %s was created", ed.Name)
+	data = data + apiKeyScopeString(args)
+	if args.UserName != "" {
+		data = data + fmt.Sprintf(" by the user %s", args.UserName)
+	}
+	data = data + "."
+	return data, true
+}
+
+// GetEventSummaryString returns the summary string for this event
+func (ed *APIKeyRotatedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The API key %s was rotated", ed.Name)
+	data = data + apiKeyScopeString(args)
+	if args.UserName != "" {
+		data = data + fmt.Sprintf(" by the user %s", args.UserName)
+	}
+	data = data + "."
+	return data, true
+}
+
+// GetEventSummaryString returns the summary string for this event
+func (ed *APIKeyRevokedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The API key %s was revoked", ed.Name)
+	data = data + apiKeyScopeString(args)
+	if args.UserName != "" {
+		data = data + fmt.Sprintf(" by the user %s", args.UserName)
+	}
+	data = data + "."
+	return data, true
+}
+
+// GetEventSummaryString returns the summary string for this event
+func (ed *APIKeyUsedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The API key %s called the operation %s", ed.Name, ed.OperationID)
+	data = data + apiKeyScopeString(args)
+	data = data + "."
+	return data, false
+}
+
+// GetEventSummaryString returns the summary string for this event
+func (ed *APIKeyRejectedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The API key %s was rejected calling the operation %s", ed.Name, ed.OperationID)
+	data = data + apiKeyScopeString(args)
+	data = data + "."
+	return data, false
+}
+
+// apiKeyScopeString returns the project or the company of the API key events
+func apiKeyScopeString(args *LogEventArgs) string {
+	if args.ProjectName != "" {
+		return fmt.Sprintf(" for the project %s", args.ProjectName)
+	}
+	if args.CompanyName != "" {
+		return fmt.Sprintf(" for the company %s", args.CompanyName)
+	}
+	if args.ProjectSFID != "" {
+		return fmt.Sprintf(" for the project %s", args.ProjectSFID)
+	}
+	if args.CompanySFID != "" {
+		return fmt.Sprintf(" for the company %s", args.CompanySFID)
+	}
+	return ""
+}
+
 // GetEventSummaryString returns the summary string for this event
 func (ed *SignatureInvalidatedApprovalRejectionEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
 	reason := noReason
@@ -79,7 +79,18 @@ const (
 	CLAGroupEnrolledProject   = "cla_group.enrolled.project"
 	CLAGroupUnenrolledProject = "cla_group.unenrolled.project"
 
-	InvalidatedSignature = "signature.invalidated"
+	InvalidatedSignature     = "signature.invalidated"
+	SignatureIntegrityFailed = "signature.integrity_failed"
+
+	ResignCampaignStarted   = "resign_campaign.started"
+	ResignCampaignCancelled = "resign_campaign.cancelled"
+	ResignCampaignCompleted = "resign_campaign.completed"
+
+	APIKeyCreated  = "api_key.created"
+	APIKeyRotated  = "api_key.rotated"
+	APIKeyRevoked  = "api_key.revoked"
+	APIKeyUsed     = "api_key.used"
+	APIKeyRejected = "api_key.rejected"
 
 	ContributorNotifyCompanyAdminType = "contributor.notify_company_admin"
 	ContributorNotifyCLADesigneeType  = "contributor.notify_cla_designee"
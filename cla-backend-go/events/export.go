@@ -0,0 +1,377 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package events
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+)
+
+// export formats
+const (
+	ExportFormatCSV       = "csv"
+	ExportFormatJSONLines = "jsonl"
+	ExportFormatCEF       = "cef"
+	ExportFormatSyslog    = "syslog"
+)
+
+// constants
+const (
+	// CEF and syslog header values
+	exportVendor         = "Linux Foundation"
+	exportProduct        = "EasyCLA"
+	exportProductVersion = "2"
+	exportAppName        = "easycla"
+
+	// syslog facility of the records - log audit
+	syslogFacilityLogAudit = 13
+)
+
+// errors
+var (
+	ErrInvalidExportFormat = errors.New("invalid export format, expecting csv, jsonl, cef or syslog")
+	ErrInvalidExportScope  = errors.New("the export requires either a CLA Group ID or a foundation SFID")
+	ErrInvalidExportRange  = errors.New("invalid export time range, the start is after the end")
+	ErrInvalidExportCursor = errors.New("invalid export cursor, expecting <epoch>:<event id>")
+)
+
+// ExportParams are the time range and the filters of an export. The export is scoped to the events of a CLA Group
+// or of a foundation, the company and event type filters are optional - the company filter matches the events with
+// either the company ID or the company SFID.
+type ExportParams struct {
+	CLAGroupID     string
+	FoundationSFID string
+	CompanyID      string
+	CompanySFID    string
+	EventType      string
+	// After and Before bound the event time epoch, inclusive - zero is unbounded
+	After  int64
+	Before int64
+}
+
+// Validate checks the scope and the time range of the export
+func (p *ExportParams) Validate() error {
+	if (p.CLAGroupID == "") == (p.FoundationSFID == "") {
+		return ErrInvalidExportScope
+	}
+	if p.After > 0 && p.Before > 0 && p.After > p.Before {
+		return ErrInvalidExportRange
+	}
+	return nil
+}
+
+// matches returns true when the event is in the scope, the time range and the filters of the export
+func (p *ExportParams) matches(event *models.Event) bool {
+	switch {
+	case p.CLAGroupID != "" && event.EventCLAGroupID != p.CLAGroupID:
+		return false
+	case p.FoundationSFID != "" && event.EventParentProjectSFID != p.FoundationSFID:
+		return false
+	case (p.CompanyID != "" || p.CompanySFID != "") && !p.matchesCompany(event):
+		return false
+	case p.EventType != "" && event.EventType != p.EventType:
+		return false
+	case p.After > 0 && event.EventTimeEpoch < p.After:
+		return false
+	case p.Before > 0 && event.EventTimeEpoch > p.Before:
+		return false
+	}
+	return true
+}
+
+// matchesCompany returns true when either company ID of the export is the one of the event, the events only record
+// the IDs known when they were logged
+func (p *ExportParams) matchesCompany(event *models.Event) bool {
+	return (p.CompanyID != "" && event.EventCompanyID == p.CompanyID) ||
+		(p.CompanySFID != "" && event.EventCompanySFID == p.CompanySFID)
+}
+
+// ExportCursor is the last exported event - the events are exported oldest first and an export resumes after the
+// cursor. Its text form is <epoch>:<event id>, it can be rebuilt from the last record of an interrupted export.
+type ExportCursor struct {
+	EventTimeEpoch int64
+	EventID        string
+}
+
+// String returns the text form of the cursor
+func (c *ExportCursor) String() string {
+	return fmt.Sprintf("%d:%s", c.EventTimeEpoch, c.EventID)
+}
+
+// ParseExportCursor parses the text form of the cursor
+func ParseExportCursor(value string) (*ExportCursor, error) {
+	parts := strings.SplitN(value, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, ErrInvalidExportCursor
+	}
+	epoch, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidExportCursor
+	}
+	return &ExportCursor{EventTimeEpoch: epoch, EventID: parts[1]}, nil
+}
+
+// after returns true when the event comes after the cursor in the export order
+func (c *ExportCursor) after(event *models.Event) bool {
+	if event.EventTimeEpoch != c.EventTimeEpoch {
+		return event.EventTimeEpoch > c.EventTimeEpoch
+	}
+	return event.EventID > c.EventID
+}
+
+// ExportWriter writes the exported events in one of the export formats
+type ExportWriter interface {
+	WriteEvent(event *models.Event) error
+	Flush() error
+}
+
+// ExportContentType returns the MIME type of the export format
+func ExportContentType(format string) string {
+	switch format {
+	case ExportFormatCSV:
+		return "text/csv"
+	case ExportFormatJSONLines:
+		return "application/x-ndjson"
+	default:
+		return "text/plain"
+	}
+}
+
+// NewExportWriter creates the writer of the format - the CSV header is only written with the header flag, a
+// resumed export appends to the records of the previous one
+func NewExportWriter(w io.Writer, format string, header bool) (ExportWriter, error) {
+	switch format {
+	case ExportFormatCSV:
+		return &csvExportWriter{writer: csv.NewWriter(w), header: header}, nil
+	case ExportFormatJSONLines:
+		return &jsonLinesExportWriter{encoder: json.NewEncoder(w)}, nil
+	case ExportFormatCEF:
+		return &cefExportWriter{w: w}, nil
+	case ExportFormatSyslog:
+		return &cefExportWriter{w: w, syslog: true}, nil
+	default:
+		return nil, ErrInvalidExportFormat
+	}
+}
+
+// exportRecord is the exported form of an event, shared by the CSV columns and the JSON Lines records
+type exportRecord struct {
+	EventID            string `json:"event_id"`
+	EventType          string `json:"event_type"`
+	EventTime          string `json:"event_time"`
+	EventTimeEpoch     int64  `json:"event_time_epoch"`
+	UserID             string `json:"user_id"`
+	UserName           string `json:"user_name"`
+	LfUsername         string `json:"lf_username"`
+	CLAGroupID         string `json:"cla_group_id"`
+	CLAGroupName       string `json:"cla_group_name"`
+	ProjectSFID        string `json:"project_sfid"`
+	ProjectName        string `json:"project_name"`
+	ParentProjectSFID  string `json:"parent_project_sfid"`
+	ParentProjectName  string `json:"parent_project_name"`
+	CompanyID          string `json:"company_id"`
+	CompanySFID        string `json:"company_sfid"`
+	CompanyName        string `json:"company_name"`
+	EventSummary       string `json:"event_summary"`
+	EventData          string `json:"event_data"`
+	ContainsPII        bool   `json:"contains_pii"`
+	EventChainStream   string `json:"event_chain_stream"`
+	EventChainSequence int64  `json:"event_chain_sequence"`
+	EventHash          string `json:"event_hash"`
+}
+
+var exportColumns = []string{
+	"event_id", "event_type", "event_time", "event_time_epoch", "user_id", "user_name", "lf_username",
+	"cla_group_id", "cla_group_name", "project_sfid", "project_name", "parent_project_sfid", "parent_project_name",
+	"company_id", "company_sfid", "company_name", "event_summary", "event_data", "contains_pii",
+	"event_chain_stream", "event_chain_sequence", "event_hash",
+}
+
+func toExportRecord(event *models.Event) *exportRecord {
+	return &exportRecord{
+		EventID:            event.EventID,
+		EventType:          event.EventType,
+		EventTime:          event.EventTime,
+		EventTimeEpoch:     event.EventTimeEpoch,
+		UserID:             event.UserID,
+		UserName:           event.UserName,
+		LfUsername:         event.LfUsername,
+		CLAGroupID:         event.EventCLAGroupID,
+		CLAGroupName:       event.EventCLAGroupName,
+		ProjectSFID:        event.EventProjectSFID,
+		ProjectName:        event.EventProjectName,
+		ParentProjectSFID:  event.EventParentProjectSFID,
+		ParentProjectName:  event.EventParentProjectName,
+		CompanyID:          event.EventCompanyID,
+		CompanySFID:        event.EventCompanySFID,
+		CompanyName:        event.EventCompanyName,
+		EventSummary:       event.EventSummary,
+		EventData:          event.EventData,
+		ContainsPII:        event.ContainsPII,
+		EventChainStream:   event.EventChainStream,
+		EventChainSequence: event.EventChainSequence,
+		EventHash:          event.EventHash,
+	}
+}
+
+func (r *exportRecord) columns() []string {
+	return []string{
+		r.EventID, r.EventType, r.EventTime, strconv.FormatInt(r.EventTimeEpoch, 10), r.UserID, r.UserName, r.LfUsername,
+		r.CLAGroupID, r.CLAGroupName, r.ProjectSFID, r.ProjectName, r.ParentProjectSFID, r.ParentProjectName,
+		r.CompanyID, r.CompanySFID, r.CompanyName, r.EventSummary, r.EventData, strconv.FormatBool(r.ContainsPII),
+		r.EventChainStream, strconv.FormatInt(r.EventChainSequence, 10), r.EventHash,
+	}
+}
+
+type csvExportWriter struct {
+	writer *csv.Writer
+	header bool
+}
+
+func (w *csvExportWriter) WriteEvent(event *models.Event) error {
+	if w.header {
+		w.header = false
+		if err := w.writer.Write(exportColumns); err != nil {
+			return err
+		}
+	}
+	return w.writer.Write(toExportRecord(event).columns())
+}
+
+func (w *csvExportWriter) Flush() error {
+	// an export without events is still a CSV document with its header
+	if w.header {
+		w.header = false
+		if err := w.writer.Write(exportColumns); err != nil {
+			return err
+		}
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type jsonLinesExportWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *jsonLinesExportWriter) WriteEvent(event *models.Event) error {
+	return w.encoder.Encode(toExportRecord(event))
+}
+
+func (w *jsonLinesExportWriter) Flush() error {
+	return nil
+}
+
+// cefExportWriter writes ArcSight Common Event Format records, one per line, optionally wrapped in an RFC 5424
+// syslog header
+type cefExportWriter struct {
+	w      io.Writer
+	syslog bool
+}
+
+func (w *cefExportWriter) WriteEvent(event *models.Event) error {
+	severity := cefSeverity(event.EventType)
+	line := cefRecord(event, severity)
+	if w.syslog {
+		line = syslogRecord(event, severity, line)
+	}
+	_, err := io.WriteString(w.w, line+"\n")
+	return err
+}
+
+func (w *cefExportWriter) Flush() error {
+	return nil
+}
+
+// cefSeverity returns the CEF severity of the event type - the removals are reported above the other changes
+func cefSeverity(eventType string) int {
+	for _, suffix := range []string{".deleted", ".removed", ".disabled", ".invalidated", ".revoked"} {
+		if strings.HasSuffix(eventType, suffix) {
+			return 6
+		}
+	}
+	return 3
+}
+
+func cefRecord(event *models.Event, severity int) string {
+	name := event.EventSummary
+	if name == "" {
+		name = event.EventType
+	}
+	extensions := []struct {
+		key   string
+		label string
+		value string
+	}{
+		{key: "rt", value: strconv.FormatInt(event.EventTimeEpoch*1000, 10)},
+		{key: "externalId", value: event.EventID},
+		{key: "suid", value: event.UserID},
+		{key: "suser", value: firstNonEmpty(event.LfUsername, event.UserName)},
+		{key: "msg", value: event.EventData},
+		{key: "cs1", label: "claGroupID", value: event.EventCLAGroupID},
+		{key: "cs2", label: "companyID", value: event.EventCompanyID},
+		{key: "cs3", label: "projectSFID", value: event.EventProjectSFID},
+		{key: "cs4", label: "companySFID", value: event.EventCompanySFID},
+		{key: "cs5", label: "eventHash", value: event.EventHash},
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "CEF:0|%s|%s|%s|%s|%s|%d|", cefHeader(exportVendor), cefHeader(exportProduct), cefHeader(exportProductVersion),
+		cefHeader(event.EventType), cefHeader(name), severity)
+	separator := ""
+	for _, extension := range extensions {
+		if extension.value == "" {
+			continue
+		}
+		// the custom strings are preceded by their label
+		if extension.label != "" {
+			fmt.Fprintf(&b, "%s%sLabel=%s", separator, extension.key, extension.label)
+			separator = " "
+		}
+		fmt.Fprintf(&b, "%s%s=%s", separator, extension.key, cefExtension(extension.value))
+		separator = " "
+	}
+	return b.String()
+}
+
+// cefHeader escapes the backslashes and the pipes of a header field
+func cefHeader(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\r", " ", "\n", " ").Replace(value)
+}
+
+// cefExtension escapes the backslashes, the equal signs and the new lines of an extension value
+func cefExtension(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\r", `\r`, "\n", `\n`).Replace(value)
+}
+
+// syslogRecord wraps the CEF record in an RFC 5424 header - <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID SD MSG
+func syslogRecord(event *models.Event, severity int, message string) string {
+	// the CEF severities 0-10 go from low to very high, the syslog severities from emergency (0) to debug (7)
+	syslogSeverity := 6
+	if severity >= 6 {
+		syslogSeverity = 4
+	}
+	msgID := event.EventType
+	if msgID == "" || len(msgID) > 32 {
+		msgID = "-"
+	}
+	return fmt.Sprintf("<%d>1 %s %s %s - %s - %s", syslogFacilityLogAudit*8+syslogSeverity,
+		time.Unix(event.EventTimeEpoch, 0).UTC().Format(time.RFC3339), exportAppName, exportAppName, msgID, message)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -4,19 +4,41 @@
 package events
 
 import (
+	"context"
+
+	"github.com/communitybridge/easycla/cla-backend-go/event_search"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/restapi/operations"
 	eventOps "github.com/communitybridge/easycla/cla-backend-go/gen/v1/restapi/operations/events"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 	"github.com/communitybridge/easycla/cla-backend-go/user"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
 	"github.com/go-openapi/runtime/middleware"
 )
 
-// Configure setups handlers on api with service
-func Configure(api *operations.ClaAPI, service Service) {
+// Configure setups handlers on api with service - the text searches run on the search index once the events recorded
+// before the index was fed by the stream are indexed
+func Configure(api *operations.ClaAPI, service Service, searchService event_search.Service) {
 	api.EventsSearchEventsHandler = eventOps.SearchEventsHandlerFunc(
 		func(params eventOps.SearchEventsParams, claUser *user.CLAUser) middleware.Responder {
-			result, err := service.SearchEvents(&params)
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			ctx := context.WithValue(context.Background(), utils.XREQUESTID, reqID) // nolint
+			useIndex := false
+			if searchService != nil && (params.SearchTerm != nil || params.UserName != nil || params.CompanyName != nil) {
+				ready, readyErr := searchService.Ready(ctx)
+				if readyErr != nil {
+					log.Warnf("unable to load the state of the event search index, searching the events table, error: %+v", readyErr)
+				}
+				useIndex = ready
+			}
+
+			var result *models.EventList
+			var err error
+			if useIndex {
+				result, err = searchService.SearchEvents(ctx, &params)
+			} else {
+				result, err = service.SearchEvents(&params)
+			}
 			if err != nil {
 				log.Debugf("error retrieving events, error: %s", err.Error())
 				return eventOps.NewSearchEventsBadRequest().WithPayload(errorResponse(err))
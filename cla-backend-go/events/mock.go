@@ -10,6 +10,7 @@ package events
 
 import (
 	context "context"
+	ed25519 "crypto/ed25519"
 	reflect "reflect"
 
 	models "github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
@@ -40,6 +41,37 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// CreateChainCheckpoints mocks base method
+func (m *MockService) CreateChainCheckpoints(arg0 context.Context, arg1 ed25519.PrivateKey) (*ChainCheckpointExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChainCheckpoints", arg0, arg1)
+	ret0, _ := ret[0].(*ChainCheckpointExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateChainCheckpoints indicates an expected call of CreateChainCheckpoints
+func (mr *MockServiceMockRecorder) CreateChainCheckpoints(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChainCheckpoints", reflect.TypeOf((*MockService)(nil).CreateChainCheckpoints), arg0, arg1)
+}
+
+// ExportEvents mocks base method
+func (m *MockService) ExportEvents(arg0 context.Context, arg1 *ExportParams, arg2 *ExportCursor, arg3 int64, arg4 ExportWriter) (*ExportCursor, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportEvents", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(*ExportCursor)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ExportEvents indicates an expected call of ExportEvents
+func (mr *MockServiceMockRecorder) ExportEvents(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportEvents", reflect.TypeOf((*MockService)(nil).ExportEvents), arg0, arg1, arg2, arg3, arg4)
+}
+
 // GetClaGroupEvents mocks base method
 func (m *MockService) GetClaGroupEvents(arg0 string, arg1 *string, arg2 *int64, arg3 bool, arg4 *string) (*models.EventList, error) {
 	m.ctrl.T.Helper()
@@ -168,3 +200,18 @@ func (mr *MockServiceMockRecorder) SearchEvents(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchEvents", reflect.TypeOf((*MockService)(nil).SearchEvents), arg0)
 }
+
+// VerifyEventChain mocks base method
+func (m *MockService) VerifyEventChain(arg0 context.Context, arg1, arg2 string) (*ChainVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEventChain", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*ChainVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyEventChain indicates an expected call of VerifyEventChain
+func (mr *MockServiceMockRecorder) VerifyEventChain(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEventChain", reflect.TypeOf((*MockService)(nil).VerifyEventChain), arg0, arg1, arg2)
+}
@@ -44,6 +44,30 @@ func (repo *mockRepository) GetClaGroupEvents(claGroupID string, nextKey *string
 	panic("implement me")
 }
 
+func (repo *mockRepository) GetChainHead(stream string) (*ChainHead, error) {
+	panic("implement me")
+}
+
+func (repo *mockRepository) GetChainHeads() ([]*ChainHead, error) {
+	panic("implement me")
+}
+
+func (repo *mockRepository) GetChainEvents(stream string, afterSequence int64, pageSize int64) ([]*models.Event, error) {
+	panic("implement me")
+}
+
+func (repo *mockRepository) GetUnchainedEvents(stream string, after, before int64) ([]*models.Event, error) {
+	panic("implement me")
+}
+
+func (repo *mockRepository) ExportEvents(params *ExportParams, after *ExportCursor, pageSize int64) ([]*models.Event, error) {
+	panic("implement me")
+}
+
+func (repo *mockRepository) ScanEvents(startKey string, pageSize int64) ([]*models.Event, string, error) {
+	panic("implement me")
+}
+
 func (repo *mockRepository) GetClaGroupIDForProject(ctx context.Context, projectSFID string) (*projects_cla_groups.ProjectClaGroup, error) {
 	return nil, nil
 }
@@ -36,6 +36,13 @@ type Event struct {
 
 	EventTime      string `dynamodbav:"event_time"`
 	EventTimeEpoch int64  `dynamodbav:"event_time_epoch"`
+
+	ContainsPII bool `dynamodbav:"contains_pii"`
+
+	EventChainStream   string `dynamodbav:"event_chain_stream"`
+	EventChainSequence int64  `dynamodbav:"event_chain_sequence"`
+	EventPrevHash      string `dynamodbav:"event_prev_hash"`
+	EventHash          string `dynamodbav:"event_hash"`
 }
 
 // DBUser data model
@@ -56,7 +63,8 @@ type DBUser struct {
 	Note               string   `json:"note"`
 }
 
-func (e *Event) toEvent() *models.Event { //nolint
+// ToEvent converts the event record to the event model
+func (e *Event) ToEvent() *models.Event {
 	event := &models.Event{
 		EventID:   e.EventID,
 		EventType: e.EventType,
@@ -84,6 +92,13 @@ func (e *Event) toEvent() *models.Event { //nolint
 
 		EventData:    e.EventData,
 		EventSummary: e.EventSummary,
+
+		ContainsPII: e.ContainsPII,
+
+		EventChainStream:   e.EventChainStream,
+		EventChainSequence: e.EventChainSequence,
+		EventPrevHash:      e.EventPrevHash,
+		EventHash:          e.EventHash,
 	}
 	// Disregard Company details for ICLA event
 	if event.EventType != IndividualSignedEvent {
@@ -20,6 +20,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 
 	"github.com/gofrs/uuid"
@@ -45,12 +46,16 @@ const (
 	EventFoundationSFIDEpochIndex       = "event-foundation-sfid-event-time-epoch-index"
 	EventProjectIDEpochIndex            = "event-project-id-event-time-epoch-index"
 	EventCLAGroupIDEpochIndex           = "event-cla-group-id-event-time-epoch-index"
+	EventChainStreamSequenceIndex       = "event-chain-stream-sequence-index"
 )
 
 // constants
 const (
 	HugePageSize    = 10000
 	DefaultPageSize = 10
+
+	// maxChainAppendAttempts is the number of times an event is linked again after a concurrent append to its stream
+	maxChainAppendAttempts = 5
 )
 
 // Repository interface defines methods of event repository service
@@ -65,6 +70,14 @@ type Repository interface {
 	GetCompanyEvents(companyID, eventType string, nextKey *string, paramPageSize *int64, all bool) (*models.EventList, error)
 	GetFoundationEvents(foundationSFID string, nextKey *string, paramPageSize *int64, all bool, searchTerm *string) (*models.EventList, error)
 	GetClaGroupEvents(claGroupID string, nextKey *string, paramPageSize *int64, all bool, searchTerm *string) (*models.EventList, error)
+
+	GetChainHead(stream string) (*ChainHead, error)
+	GetChainHeads() ([]*ChainHead, error)
+	GetChainEvents(stream string, afterSequence int64, pageSize int64) ([]*models.Event, error)
+	GetUnchainedEvents(stream string, after, before int64) ([]*models.Event, error)
+
+	ExportEvents(params *ExportParams, after *ExportCursor, pageSize int64) ([]*models.Event, error)
+	ScanEvents(startKey string, pageSize int64) ([]*models.Event, string, error)
 }
 
 // repository data model
@@ -72,6 +85,7 @@ type repository struct {
 	stage          string
 	dynamoDBClient *dynamodb.DynamoDB
 	eventsTable    string
+	chainsTable    string
 }
 
 // NewRepository creates a new instance of the event repository
@@ -80,6 +94,7 @@ func NewRepository(awsSession *session.Session, stage string) Repository {
 		stage:          stage,
 		dynamoDBClient: dynamodb.New(awsSession),
 		eventsTable:    fmt.Sprintf("cla-%s-events", stage),
+		chainsTable:    fmt.Sprintf("cla-%s-event-chains", stage),
 	}
 }
 
@@ -155,7 +170,16 @@ func (repo *repository) CreateEvent(event *models.Event) error {
 		addAttribute(input.Item, "company_id_external_project_id", companyIDExternalProjectID)
 	}
 
-	_, err = repo.dynamoDBClient.PutItem(input)
+	// The events of a CLA group or a company are hash chained in their stream
+	chained := *event
+	chained.EventID = eventID.String()
+	chained.EventTime = currentTimeString
+	chained.EventTimeEpoch = currentTime.Unix()
+	if stream := ChainStream(&chained); stream != "" {
+		err = repo.appendChainedEvent(f, stream, &chained, input)
+	} else {
+		_, err = repo.dynamoDBClient.PutItem(input)
+	}
 	if err != nil {
 		log.WithFields(f).WithError(err).Warnf("Unable to create a new event, error: %v", err)
 		return err
@@ -165,6 +189,427 @@ func (repo *repository) CreateEvent(event *models.Event) error {
 	return nil
 }
 
+// appendChainedEvent links the event to the head of its stream and writes the event along with the new head in a
+// single transaction - a concurrent append to the same stream cancels the transaction and the event is linked again
+func (repo *repository) appendChainedEvent(f logrus.Fields, stream string, event *models.Event, input *dynamodb.PutItemInput) error {
+	for attempt := 1; ; attempt++ {
+		head, err := repo.GetChainHead(stream)
+		if err != nil {
+			return err
+		}
+
+		newHead := linkEvent(head, stream, event)
+		addAttribute(input.Item, "event_chain_stream", event.EventChainStream)
+		addAttribute(input.Item, "event_prev_hash", event.EventPrevHash)
+		addAttribute(input.Item, "event_hash", event.EventHash)
+		input.Item["event_chain_sequence"] = &dynamodb.AttributeValue{N: aws.String(strconv.FormatInt(event.EventChainSequence, 10))}
+
+		headItem, err := dynamodbattribute.MarshalMap(newHead)
+		if err != nil {
+			return err
+		}
+		headPut := &dynamodb.Put{
+			TableName: aws.String(repo.chainsTable),
+			Item:      headItem,
+		}
+		if head == nil {
+			headPut.ConditionExpression = aws.String("attribute_not_exists(chain_stream)")
+		} else {
+			headPut.ConditionExpression = aws.String("chain_sequence = :sequence")
+			headPut.ExpressionAttributeValues = map[string]*dynamodb.AttributeValue{
+				":sequence": {N: aws.String(strconv.FormatInt(head.Sequence, 10))},
+			}
+		}
+
+		_, err = repo.dynamoDBClient.TransactWriteItems(&dynamodb.TransactWriteItemsInput{
+			TransactItems: []*dynamodb.TransactWriteItem{
+				{Put: headPut},
+				{Put: &dynamodb.Put{TableName: input.TableName, Item: input.Item}},
+			},
+		})
+		if err == nil {
+			return nil
+		}
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeTransactionCanceledException && attempt < maxChainAppendAttempts {
+			log.WithFields(f).Debugf("concurrent append to the event stream: %s - linking the event again, attempt: %d", stream, attempt)
+			continue
+		}
+		return err
+	}
+}
+
+// GetChainHead returns the head of the event stream, nil when no event was appended to the stream
+func (repo *repository) GetChainHead(stream string) (*ChainHead, error) {
+	f := logrus.Fields{
+		"functionName": "v1.events.repository.GetChainHead",
+		"stream":       stream,
+	}
+
+	result, err := repo.dynamoDBClient.GetItem(&dynamodb.GetItemInput{
+		TableName:      aws.String(repo.chainsTable),
+		Key:            map[string]*dynamodb.AttributeValue{"chain_stream": {S: aws.String(stream)}},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the head of the event stream")
+		return nil, err
+	}
+	if len(result.Item) == 0 {
+		return nil, nil
+	}
+
+	var head ChainHead
+	err = dynamodbattribute.UnmarshalMap(result.Item, &head)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("error unmarshalling the head of the event stream")
+		return nil, err
+	}
+	return &head, nil
+}
+
+// GetChainHeads returns the heads of all the event streams
+func (repo *repository) GetChainHeads() ([]*ChainHead, error) {
+	f := logrus.Fields{
+		"functionName": "v1.events.repository.GetChainHeads",
+	}
+
+	heads := make([]*ChainHead, 0)
+	scanInput := &dynamodb.ScanInput{
+		TableName: aws.String(repo.chainsTable),
+	}
+	for {
+		results, err := repo.dynamoDBClient.Scan(scanInput)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("unable to scan the event stream heads")
+			return nil, err
+		}
+
+		var page []*ChainHead
+		err = dynamodbattribute.UnmarshalListOfMaps(results.Items, &page)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("error unmarshalling the event stream heads")
+			return nil, err
+		}
+		heads = append(heads, page...)
+
+		if len(results.LastEvaluatedKey) == 0 {
+			break
+		}
+		scanInput.ExclusiveStartKey = results.LastEvaluatedKey
+	}
+	return heads, nil
+}
+
+// GetChainEvents returns up to page size events of the stream following the sequence, ordered by sequence
+func (repo *repository) GetChainEvents(stream string, afterSequence int64, pageSize int64) ([]*models.Event, error) {
+	f := logrus.Fields{
+		"functionName":  "v1.events.repository.GetChainEvents",
+		"stream":        stream,
+		"afterSequence": afterSequence,
+		"pageSize":      pageSize,
+	}
+
+	condition := expression.Key("event_chain_stream").Equal(expression.Value(stream)).And(
+		expression.Key("event_chain_sequence").GreaterThan(expression.Value(afterSequence)))
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).Build()
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem building event stream query")
+		return nil, err
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		TableName:                 aws.String(repo.eventsTable),
+		IndexName:                 aws.String(EventChainStreamSequenceIndex),
+		Limit:                     aws.Int64(pageSize),
+	}
+
+	events := make([]*models.Event, 0)
+	for {
+		results, errQuery := repo.dynamoDBClient.Query(queryInput)
+		if errQuery != nil {
+			log.WithFields(f).WithError(errQuery).Warn("error retrieving the events of the stream")
+			return nil, errQuery
+		}
+
+		eventsList, modelErr := buildEventListModels(results)
+		if modelErr != nil {
+			log.WithFields(f).WithError(modelErr).Warn("error convert event list models")
+			return nil, modelErr
+		}
+		events = append(events, eventsList...)
+
+		if int64(len(events)) >= pageSize || len(results.LastEvaluatedKey) == 0 {
+			break
+		}
+		queryInput.ExclusiveStartKey = results.LastEvaluatedKey
+	}
+	if int64(len(events)) > pageSize {
+		events = events[:pageSize]
+	}
+	return events, nil
+}
+
+// GetUnchainedEvents returns the events of the stream logged between the event time epochs, inclusive, without
+// their chain fields. The events of a CLA group are queried by the CLA group index, the events of a company outside
+// of a CLA group have no index and are scanned.
+func (repo *repository) GetUnchainedEvents(stream string, after, before int64) ([]*models.Event, error) {
+	f := logrus.Fields{
+		"functionName": "v1.events.repository.GetUnchainedEvents",
+		"stream":       stream,
+		"after":        after,
+		"before":       before,
+	}
+
+	streamType, streamID := parseChainStream(stream)
+	epochRange := expression.Name("event_time_epoch").Between(expression.Value(after), expression.Value(before))
+	unchained := expression.AttributeNotExists(expression.Name("event_chain_stream"))
+
+	events := make([]*models.Event, 0)
+	switch streamType {
+	case ChainStreamTypeCLAGroup:
+		keyCondition := expression.Key("event_cla_group_id").Equal(expression.Value(streamID)).And(
+			expression.Key("event_time_epoch").Between(expression.Value(after), expression.Value(before)))
+		expr, err := expression.NewBuilder().WithKeyCondition(keyCondition).WithFilter(unchained).Build()
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem building the unchained events query")
+			return nil, err
+		}
+		queryInput := &dynamodb.QueryInput{
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+			KeyConditionExpression:    expr.KeyCondition(),
+			FilterExpression:          expr.Filter(),
+			TableName:                 aws.String(repo.eventsTable),
+			IndexName:                 aws.String(EventCLAGroupIDEpochIndex),
+		}
+		for {
+			results, errQuery := repo.dynamoDBClient.Query(queryInput)
+			if errQuery != nil {
+				log.WithFields(f).WithError(errQuery).Warn("error retrieving the unchained events of the stream")
+				return nil, errQuery
+			}
+			eventsList, modelErr := buildEventListModels(results)
+			if modelErr != nil {
+				log.WithFields(f).WithError(modelErr).Warn("error convert event list models")
+				return nil, modelErr
+			}
+			events = append(events, eventsList...)
+			if len(results.LastEvaluatedKey) == 0 {
+				break
+			}
+			queryInput.ExclusiveStartKey = results.LastEvaluatedKey
+		}
+	case ChainStreamTypeCompany:
+		filter := expression.Name("event_company_id").Equal(expression.Value(streamID)).
+			And(expression.AttributeNotExists(expression.Name("event_cla_group_id"))).
+			And(epochRange).
+			And(unchained)
+		expr, err := expression.NewBuilder().WithFilter(filter).Build()
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem building the unchained events scan")
+			return nil, err
+		}
+		scanInput := &dynamodb.ScanInput{
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+			FilterExpression:          expr.Filter(),
+			TableName:                 aws.String(repo.eventsTable),
+		}
+		for {
+			results, errScan := repo.dynamoDBClient.Scan(scanInput)
+			if errScan != nil {
+				log.WithFields(f).WithError(errScan).Warn("unable to scan the unchained events of the stream")
+				return nil, errScan
+			}
+			var items []Event
+			err = dynamodbattribute.UnmarshalListOfMaps(results.Items, &items)
+			if err != nil {
+				log.WithFields(f).WithError(err).Warn("error unmarshalling the unchained events")
+				return nil, err
+			}
+			for _, e := range items {
+				events = append(events, e.ToEvent())
+			}
+			if len(results.LastEvaluatedKey) == 0 {
+				break
+			}
+			scanInput.ExclusiveStartKey = results.LastEvaluatedKey
+		}
+	default:
+		return nil, ErrInvalidChainStreamType
+	}
+	return events, nil
+}
+
+// ExportEvents returns up to page size events of the export following the cursor, oldest first
+func (repo *repository) ExportEvents(params *ExportParams, after *ExportCursor, pageSize int64) ([]*models.Event, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.events.repository.ExportEvents",
+		"claGroupID":     params.CLAGroupID,
+		"foundationSFID": params.FoundationSFID,
+		"companyID":      params.CompanyID,
+		"companySFID":    params.CompanySFID,
+		"eventType":      params.EventType,
+		"after":          params.After,
+		"before":         params.Before,
+		"pageSize":       pageSize,
+	}
+
+	// a foundation export of a company only known by its SFID queries the company and foundation index, the other
+	// filters are applied to the items of the index - the events of a company are matched by either of its IDs
+	var indexName string
+	var keyCondition expression.KeyConditionBuilder
+	switch {
+	case params.CLAGroupID != "":
+		indexName = EventCLAGroupIDEpochIndex
+		keyCondition = expression.Key("event_cla_group_id").Equal(expression.Value(params.CLAGroupID))
+	case params.CompanySFID != "" && params.CompanyID == "":
+		indexName = CompanySFIDFoundationSFIDEpochIndex
+		keyCondition = expression.Key("company_sfid_foundation_sfid").Equal(
+			expression.Value(fmt.Sprintf("%s#%s", params.CompanySFID, params.FoundationSFID)))
+	default:
+		indexName = EventFoundationSFIDEpochIndex
+		keyCondition = expression.Key("event_parent_project_sfid").Equal(expression.Value(params.FoundationSFID))
+	}
+	switch {
+	case params.After > 0 && params.Before > 0:
+		keyCondition = keyCondition.And(expression.Key("event_time_epoch").Between(expression.Value(params.After), expression.Value(params.Before)))
+	case params.After > 0:
+		keyCondition = keyCondition.And(expression.Key("event_time_epoch").GreaterThanEqual(expression.Value(params.After)))
+	case params.Before > 0:
+		keyCondition = keyCondition.And(expression.Key("event_time_epoch").LessThanEqual(expression.Value(params.Before)))
+	}
+
+	builder := expression.NewBuilder().WithKeyCondition(keyCondition)
+	var filter expression.ConditionBuilder
+	filterAdded := false
+	if params.EventType != "" {
+		filter = addConditionToFilter(filter, expression.Name("event_type").Equal(expression.Value(params.EventType)), &filterAdded)
+	}
+	switch {
+	case params.CompanyID != "" && params.CompanySFID != "":
+		companyCondition := expression.Name("event_company_id").Equal(expression.Value(params.CompanyID)).
+			Or(expression.Name("event_company_sfid").Equal(expression.Value(params.CompanySFID)))
+		filter = addConditionToFilter(filter, companyCondition, &filterAdded)
+	case params.CompanyID != "":
+		filter = addConditionToFilter(filter, expression.Name("event_company_id").Equal(expression.Value(params.CompanyID)), &filterAdded)
+	case params.CompanySFID != "" && indexName != CompanySFIDFoundationSFIDEpochIndex:
+		filter = addConditionToFilter(filter, expression.Name("event_company_sfid").Equal(expression.Value(params.CompanySFID)), &filterAdded)
+	}
+	if filterAdded {
+		builder = builder.WithFilter(filter)
+	}
+	expr, err := builder.Build()
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem building the event export query")
+		return nil, err
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+		TableName:                 aws.String(repo.eventsTable),
+		IndexName:                 aws.String(indexName),
+		ScanIndexForward:          aws.Bool(true),
+		Limit:                     aws.Int64(pageSize),
+	}
+	if after != nil {
+		// the start key only needs the keys of the index and of the table, the cursor event may be filtered out
+		startKey, keyErr := buildNextKey(indexName, &models.Event{
+			EventID:                after.EventID,
+			EventTimeEpoch:         after.EventTimeEpoch,
+			EventCLAGroupID:        params.CLAGroupID,
+			EventParentProjectSFID: params.FoundationSFID,
+			EventCompanySFID:       params.CompanySFID,
+		})
+		if keyErr != nil {
+			log.WithFields(f).WithError(keyErr).Warn("problem building the event export start key")
+			return nil, keyErr
+		}
+		queryInput.ExclusiveStartKey, err = decodeNextKey(startKey)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem decoding the event export start key")
+			return nil, err
+		}
+	}
+
+	events := make([]*models.Event, 0)
+	for {
+		results, errQuery := repo.dynamoDBClient.Query(queryInput)
+		if errQuery != nil {
+			log.WithFields(f).WithError(errQuery).Warn("error retrieving the events of the export")
+			return nil, errQuery
+		}
+
+		eventsList, modelErr := buildEventListModels(results)
+		if modelErr != nil {
+			log.WithFields(f).WithError(modelErr).Warn("error convert event list models")
+			return nil, modelErr
+		}
+		events = append(events, eventsList...)
+
+		if int64(len(events)) >= pageSize || len(results.LastEvaluatedKey) == 0 {
+			break
+		}
+		queryInput.ExclusiveStartKey = results.LastEvaluatedKey
+	}
+	if int64(len(events)) > pageSize {
+		events = events[:pageSize]
+	}
+	return events, nil
+}
+
+// ScanEvents returns a page of up to page size events of the table following the start key, in no particular order,
+// along with the key of the next page - the key is empty after the last page
+func (repo *repository) ScanEvents(startKey string, pageSize int64) ([]*models.Event, string, error) {
+	f := logrus.Fields{
+		"functionName": "v1.events.repository.ScanEvents",
+		"pageSize":     pageSize,
+	}
+
+	scanInput := &dynamodb.ScanInput{
+		TableName: aws.String(repo.eventsTable),
+		Limit:     aws.Int64(pageSize),
+	}
+	if startKey != "" {
+		exclusiveStartKey, err := decodeNextKey(startKey)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem decoding the scan start key")
+			return nil, "", err
+		}
+		scanInput.ExclusiveStartKey = exclusiveStartKey
+	}
+
+	results, err := repo.dynamoDBClient.Scan(scanInput)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to scan the events")
+		return nil, "", err
+	}
+
+	var items []Event
+	err = dynamodbattribute.UnmarshalListOfMaps(results.Items, &items)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("error unmarshalling the scanned events")
+		return nil, "", err
+	}
+	events := make([]*models.Event, 0, len(items))
+	for _, e := range items {
+		events = append(events, e.ToEvent())
+	}
+
+	nextKey, err := encodeNextKey(results.LastEvaluatedKey)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem encoding the scan next key")
+		return nil, "", err
+	}
+	return events, nextKey, nil
+}
+
 func addAttribute(item map[string]*dynamodb.AttributeValue, key string, value string) {
 	if value != "" {
 		item[key] = &dynamodb.AttributeValue{S: aws.String(value)}
@@ -666,7 +1111,7 @@ func buildEventListModels(results *dynamodb.QueryOutput) ([]*models.Event, error
 	}
 
 	for _, e := range items {
-		events = append(events, e.toEvent())
+		events = append(events, e.ToEvent())
 	}
 
 	return events, nil
@@ -0,0 +1,354 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package events
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	eventOps "github.com/communitybridge/easycla/cla-backend-go/gen/v1/restapi/operations/events"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/gofrs/uuid"
+)
+
+// memoryRepository is an embedded, in-memory implementation of the event Repository. It is intended for local
+// development and tests where DynamoDB is not available.
+type memoryRepository struct {
+	lock   sync.RWMutex
+	events []models.Event
+	heads  map[string]*ChainHead
+}
+
+// NewMemoryRepository creates a new instance of the in-memory event repository
+func NewMemoryRepository() Repository {
+	return &memoryRepository{
+		heads: map[string]*ChainHead{},
+	}
+}
+
+// CreateEvent event will create event in the store
+func (repo *memoryRepository) CreateEvent(event *models.Event) error {
+	if event.UserID == "" {
+		return ErrUserIDRequired
+	}
+	if event.EventType == "" {
+		return ErrEventTypeRequired
+	}
+	eventID, err := uuid.NewV4()
+	if err != nil {
+		return err
+	}
+
+	currentTime, currentTimeString := utils.CurrentTime()
+	stored := *event
+	stored.EventID = eventID.String()
+	stored.EventTime = currentTimeString
+	stored.EventTimeEpoch = currentTime.Unix()
+	stored.EventCLAGroupNameLower = strings.ToLower(event.EventCLAGroupName)
+
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	if stream := ChainStream(&stored); stream != "" {
+		repo.heads[stream] = linkEvent(repo.heads[stream], stream, &stored)
+	}
+	repo.events = append(repo.events, stored)
+	return nil
+}
+
+// AddDataToEvent adds the project and company external IDs to the event
+func (repo *memoryRepository) AddDataToEvent(eventID, parentProjectSFID, projectSFID, projectSFName, companySFID, projectID string) error {
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	for i := range repo.events {
+		if repo.events[i].EventID != eventID {
+			continue
+		}
+		if parentProjectSFID != "" {
+			repo.events[i].EventParentProjectSFID = parentProjectSFID
+		}
+		if projectSFID != "" {
+			repo.events[i].EventProjectSFID = projectSFID
+		}
+		if projectSFName != "" {
+			repo.events[i].EventProjectName = projectSFName
+		}
+		if companySFID != "" {
+			repo.events[i].EventCompanySFID = companySFID
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// SearchEvents returns list of events matching with filter criteria.
+func (repo *memoryRepository) SearchEvents(params *eventOps.SearchEventsParams, pageSize int64) (*models.EventList, error) {
+	if params.ProjectID == nil {
+		return nil, errors.New("invalid request. projectID is compulsory")
+	}
+
+	descending := params.SortOrder != nil && *params.SortOrder == utils.SortOrderDescending
+	events := repo.query(func(e *models.Event) bool {
+		if e.EventProjectID != *params.ProjectID {
+			return false
+		}
+		if params.CompanyID != nil && e.EventCompanyID != *params.CompanyID {
+			return false
+		}
+		if params.UserID != nil && e.UserID != *params.UserID {
+			return false
+		}
+		if params.EventType != nil && e.EventType != *params.EventType {
+			return false
+		}
+		if params.After != nil && e.EventTimeEpoch < *params.After {
+			return false
+		}
+		if params.Before != nil && e.EventTimeEpoch > *params.Before {
+			return false
+		}
+		if params.UserName != nil && !strings.Contains(strings.ToLower(e.UserName), strings.ToLower(*params.UserName)) {
+			return false
+		}
+		if params.CompanyName != nil && !strings.Contains(strings.ToLower(e.EventCompanyName), strings.ToLower(*params.CompanyName)) {
+			return false
+		}
+		if params.SearchTerm != nil && !strings.Contains(strings.ToLower(e.EventData), strings.ToLower(*params.SearchTerm)) {
+			return false
+		}
+		return true
+	}, descending)
+
+	return pageEvents(events, params.NextKey, pageSize), nil
+}
+
+// GetRecentEvents returns the most recent events from the last 30 days
+func (repo *memoryRepository) GetRecentEvents(pageSize int64) (*models.EventList, error) {
+	after := time.Now().Add(-30 * 24 * time.Hour).Unix()
+	events := repo.query(func(e *models.Event) bool {
+		return e.EventProjectID != "" && !e.ContainsPII && e.EventTimeEpoch >= after
+	}, true)
+	if int64(len(events)) > pageSize {
+		events = events[:pageSize]
+	}
+
+	return &models.EventList{
+		Events: events,
+	}, nil
+}
+
+// GetCompanyFoundationEvents returns the list of events for foundation and company
+func (repo *memoryRepository) GetCompanyFoundationEvents(companySFID, companyID, foundationSFID string, nextKey *string, paramPageSize *int64, all bool) (*models.EventList, error) {
+	events := repo.query(func(e *models.Event) bool {
+		return e.EventParentProjectSFID == foundationSFID && e.EventCompanySFID == companySFID
+	}, true)
+	return pageEvents(events, nextKey, queryPageSize(paramPageSize, all)), nil
+}
+
+// GetCompanyClaGroupEvents returns the list of events for cla group and the company
+func (repo *memoryRepository) GetCompanyClaGroupEvents(companySFID, companyID, claGroupID string, nextKey *string, paramPageSize *int64, all bool) (*models.EventList, error) {
+	events := repo.query(func(e *models.Event) bool {
+		return e.EventCLAGroupID == claGroupID && e.EventCompanySFID == companySFID
+	}, true)
+	return pageEvents(events, nextKey, queryPageSize(paramPageSize, all)), nil
+}
+
+// GetCompanyEvents returns the list of events for given company id and event types
+func (repo *memoryRepository) GetCompanyEvents(companyID, eventType string, nextKey *string, paramPageSize *int64, all bool) (*models.EventList, error) {
+	events := repo.query(func(e *models.Event) bool {
+		return e.EventCompanyID == companyID && e.EventType == eventType
+	}, true)
+	return pageEvents(events, nextKey, queryPageSize(paramPageSize, all)), nil
+}
+
+// GetFoundationEvents returns the list of foundation events
+func (repo *memoryRepository) GetFoundationEvents(foundationSFID string, nextKey *string, paramPageSize *int64, all bool, searchTerm *string) (*models.EventList, error) {
+	events := repo.query(func(e *models.Event) bool {
+		return e.EventParentProjectSFID == foundationSFID && matchesEventSearchTerm(e, searchTerm)
+	}, true)
+	return pageEvents(events, nextKey, queryPageSize(paramPageSize, all)), nil
+}
+
+// GetClaGroupEvents returns the list of cla-group events
+func (repo *memoryRepository) GetClaGroupEvents(claGroupID string, nextKey *string, paramPageSize *int64, all bool, searchTerm *string) (*models.EventList, error) {
+	events := repo.query(func(e *models.Event) bool {
+		return e.EventCLAGroupID == claGroupID && matchesEventSearchTerm(e, searchTerm)
+	}, true)
+	return pageEvents(events, nextKey, queryPageSize(paramPageSize, all)), nil
+}
+
+// GetChainHead returns the head of the event stream, nil when no event was appended to the stream
+func (repo *memoryRepository) GetChainHead(stream string) (*ChainHead, error) {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	head, ok := repo.heads[stream]
+	if !ok {
+		return nil, nil
+	}
+	copied := *head
+	return &copied, nil
+}
+
+// GetChainHeads returns the heads of all the event streams
+func (repo *memoryRepository) GetChainHeads() ([]*ChainHead, error) {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	heads := make([]*ChainHead, 0, len(repo.heads))
+	for _, head := range repo.heads {
+		copied := *head
+		heads = append(heads, &copied)
+	}
+	sort.Slice(heads, func(i, j int) bool {
+		return heads[i].Stream < heads[j].Stream
+	})
+	return heads, nil
+}
+
+// GetChainEvents returns up to page size events of the stream following the sequence, ordered by sequence
+func (repo *memoryRepository) GetChainEvents(stream string, afterSequence int64, pageSize int64) ([]*models.Event, error) {
+	events := repo.query(func(e *models.Event) bool {
+		return e.EventChainStream == stream && e.EventChainSequence > afterSequence
+	}, false)
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].EventChainSequence < events[j].EventChainSequence
+	})
+	if int64(len(events)) > pageSize {
+		events = events[:pageSize]
+	}
+	return events, nil
+}
+
+// GetUnchainedEvents returns the events of the stream logged between the event time epochs, inclusive, without
+// their chain fields
+func (repo *memoryRepository) GetUnchainedEvents(stream string, after, before int64) ([]*models.Event, error) {
+	return repo.query(func(e *models.Event) bool {
+		return e.EventChainStream == "" && ChainStream(e) == stream && e.EventTimeEpoch >= after && e.EventTimeEpoch <= before
+	}, false), nil
+}
+
+// ExportEvents returns up to page size events of the export following the cursor, oldest first
+func (repo *memoryRepository) ExportEvents(params *ExportParams, after *ExportCursor, pageSize int64) ([]*models.Event, error) {
+	events := repo.query(func(e *models.Event) bool {
+		return params.matches(e) && (after == nil || after.after(e))
+	}, false)
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].EventTimeEpoch != events[j].EventTimeEpoch {
+			return events[i].EventTimeEpoch < events[j].EventTimeEpoch
+		}
+		return events[i].EventID < events[j].EventID
+	})
+	if int64(len(events)) > pageSize {
+		events = events[:pageSize]
+	}
+	return events, nil
+}
+
+// ScanEvents returns a page of up to page size events of the store following the start key, along with the key of
+// the next page - the key is the position of the next event in the store, empty after the last page
+func (repo *memoryRepository) ScanEvents(startKey string, pageSize int64) ([]*models.Event, string, error) {
+	start := 0
+	if startKey != "" {
+		var err error
+		start, err = strconv.Atoi(startKey)
+		if err != nil || start < 0 {
+			return nil, "", errors.New("invalid scan start key")
+		}
+	}
+
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	if start > len(repo.events) {
+		start = len(repo.events)
+	}
+	end := start + int(pageSize)
+	if end > len(repo.events) {
+		end = len(repo.events)
+	}
+	events := make([]*models.Event, 0, end-start)
+	for i := start; i < end; i++ {
+		e := repo.events[i]
+		events = append(events, &e)
+	}
+	nextKey := ""
+	if end < len(repo.events) {
+		nextKey = strconv.Itoa(end)
+	}
+	return events, nextKey, nil
+}
+
+// query returns copies of the events matching the filter, ordered by event time
+func (repo *memoryRepository) query(match func(e *models.Event) bool, descending bool) []*models.Event {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	events := make([]*models.Event, 0)
+	for i := range repo.events {
+		// The store is append only - walk it backwards for newest first so events created in the same second keep their order
+		idx := i
+		if descending {
+			idx = len(repo.events) - 1 - i
+		}
+		if match(&repo.events[idx]) {
+			e := repo.events[idx]
+			events = append(events, &e)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if descending {
+			return events[i].EventTimeEpoch > events[j].EventTimeEpoch
+		}
+		return events[i].EventTimeEpoch < events[j].EventTimeEpoch
+	})
+	return events
+}
+
+// queryPageSize returns the page size to use for the query, mirroring the DynamoDB repository limits
+func queryPageSize(pageSize *int64, all bool) int64 {
+	if all {
+		return HugePageSize
+	}
+	if pageSize == nil {
+		return DefaultPageSize
+	}
+	if *pageSize > HugePageSize {
+		return HugePageSize
+	}
+	return *pageSize
+}
+
+// pageEvents returns the page of events following the event with the next key ID
+func pageEvents(events []*models.Event, nextKey *string, pageSize int64) *models.EventList {
+	start := 0
+	if nextKey != nil && *nextKey != "" {
+		for i, e := range events {
+			if e.EventID == *nextKey {
+				start = i + 1
+				break
+			}
+		}
+	}
+	events = events[start:]
+
+	response := &models.EventList{
+		Events: events,
+	}
+	if pageSize > 0 && int64(len(events)) > pageSize {
+		response.Events = events[:pageSize]
+		response.NextKey = events[pageSize-1].EventID
+	}
+
+	return response
+}
+
+// matchesEventSearchTerm returns true if the event data contains the search term - a nil search term matches everything
+func matchesEventSearchTerm(e *models.Event, searchTerm *string) bool {
+	if searchTerm == nil {
+		return true
+	}
+	return strings.Contains(strings.ToLower(e.EventData), strings.ToLower(*searchTerm))
+}
@@ -5,8 +5,10 @@ package events
 
 import (
 	"context"
+	"crypto/ed25519"
 	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
 
@@ -21,6 +23,7 @@ import (
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
 	eventOps "github.com/communitybridge/easycla/cla-backend-go/gen/v1/restapi/operations/events"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
 )
 
 // constants
@@ -28,6 +31,12 @@ const (
 	ReturnAllEvents     = true
 	LoadRepoDetails     = true
 	DontLoadRepoDetails = false
+
+	// chainPageSize is the number of events loaded at once while walking an event stream
+	chainPageSize = 500
+
+	// exportPageSize is the number of events loaded at once while exporting
+	exportPageSize = 500
 )
 
 // Service interface defines methods of event service
@@ -42,6 +51,11 @@ type Service interface {
 	GetCompanyFoundationEvents(companySFID, companyID, foundationSFID string, nextKey *string, paramPageSize *int64, all bool) (*models.EventList, error)
 	GetCompanyClaGroupEvents(companySFID, companyID, claGroupID string, nextKey *string, paramPageSize *int64, all bool) (*models.EventList, error)
 	GetCompanyEvents(companyID, eventType string, nextKey *string, paramPageSize *int64, all bool) (*models.EventList, error)
+
+	VerifyEventChain(ctx context.Context, streamType, streamID string) (*ChainVerification, error)
+	CreateChainCheckpoints(ctx context.Context, signingKey ed25519.PrivateKey) (*ChainCheckpointExport, error)
+
+	ExportEvents(ctx context.Context, params *ExportParams, after *ExportCursor, limit int64, writer ExportWriter) (*ExportCursor, int64, error)
 }
 
 // CombinedRepo contains the various methods of other repositories
@@ -114,6 +128,140 @@ func (s *service) GetCompanyEvents(companyID, eventType string, nextKey *string,
 	return s.repo.GetCompanyEvents(companyID, eventType, nextKey, paramPageSize, all)
 }
 
+// VerifyEventChain walks the hash chained events of the CLA group or company stream and reports any break, including
+// the events of the stream logged without the chain
+func (s *service) VerifyEventChain(ctx context.Context, streamType, streamID string) (*ChainVerification, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.events.service.VerifyEventChain",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"streamType":     streamType,
+		"streamID":       streamID,
+	}
+
+	stream, err := ChainStreamKey(streamType, streamID)
+	if err != nil {
+		return nil, err
+	}
+
+	// load the head first - the events appended while walking the stream are ignored
+	head, err := s.repo.GetChainHead(stream)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the head of the event stream")
+		return nil, err
+	}
+
+	verifier := newChainVerifier(stream)
+	var afterSequence int64
+	for head != nil && afterSequence < head.Sequence {
+		events, err := s.repo.GetChainEvents(stream, afterSequence, chainPageSize)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warnf("unable to load the events of the stream after sequence: %d", afterSequence)
+			return nil, err
+		}
+		for _, event := range events {
+			if event.EventChainSequence > head.Sequence {
+				break
+			}
+			verifier.verify(event)
+		}
+		if len(events) < chainPageSize {
+			break
+		}
+		afterSequence = events[len(events)-1].EventChainSequence
+	}
+
+	// the events of the stream written without the chain within the time range of the chained events are gaps
+	if after, before, ok := verifier.coveredRange(); ok {
+		unchained, err := s.repo.GetUnchainedEvents(stream, after, before)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("unable to load the unchained events of the stream")
+			return nil, err
+		}
+		for _, event := range unchained {
+			verifier.unchained(event)
+		}
+	}
+
+	_, now := utils.CurrentTime()
+	result := verifier.finish(head, now)
+	log.WithFields(f).Debugf("verified %d events of the stream - %d breaks", result.EventsVerified, len(result.Breaks))
+	return result, nil
+}
+
+// CreateChainCheckpoints returns the heads of all the event streams signed with the key
+func (s *service) CreateChainCheckpoints(ctx context.Context, signingKey ed25519.PrivateKey) (*ChainCheckpointExport, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.events.service.CreateChainCheckpoints",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+	}
+
+	heads, err := s.repo.GetChainHeads()
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the heads of the event streams")
+		return nil, err
+	}
+	sort.Slice(heads, func(i, j int) bool {
+		return heads[i].Stream < heads[j].Stream
+	})
+
+	_, now := utils.CurrentTime()
+	log.WithFields(f).Debugf("signing the checkpoints of %d event streams", len(heads))
+	return SignChainCheckpoints(signingKey, heads, now), nil
+}
+
+// ExportEvents writes the events of the export following the cursor, oldest first, and flushes the writer after
+// every page. The export stops after limit events when the limit is set - it returns the cursor of the last written
+// event when the limit or an error stops the export, and a nil cursor once all the events are written.
+func (s *service) ExportEvents(ctx context.Context, params *ExportParams, after *ExportCursor, limit int64, writer ExportWriter) (*ExportCursor, int64, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.events.service.ExportEvents",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     params.CLAGroupID,
+		"foundationSFID": params.FoundationSFID,
+		"companyID":      params.CompanyID,
+		"eventType":      params.EventType,
+		"limit":          limit,
+	}
+
+	if err := params.Validate(); err != nil {
+		return after, 0, err
+	}
+
+	var count int64
+	for {
+		pageSize := int64(exportPageSize)
+		if limit > 0 && limit-count < pageSize {
+			pageSize = limit - count
+		}
+		events, err := s.repo.ExportEvents(params, after, pageSize)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warnf("unable to load the events of the export after: %v", after)
+			return after, count, err
+		}
+		for _, event := range events {
+			if err := writer.WriteEvent(event); err != nil {
+				log.WithFields(f).WithError(err).Warnf("unable to write the event: %s", event.EventID)
+				return after, count, err
+			}
+			after = &ExportCursor{EventTimeEpoch: event.EventTimeEpoch, EventID: event.EventID}
+			count++
+		}
+		if err := writer.Flush(); err != nil {
+			log.WithFields(f).WithError(err).Warn("unable to flush the export")
+			return after, count, err
+		}
+
+		if int64(len(events)) < pageSize {
+			log.WithFields(f).Debugf("exported %d events", count)
+			return nil, count, nil
+		}
+		if limit > 0 && count >= limit {
+			log.WithFields(f).Debugf("exported %d events, stopping at the limit", count)
+			return after, count, nil
+		}
+	}
+}
+
 // LogEventArgs is argument to LogEvent function
 // EventType, EventData are compulsory.
 // One of LfUsername, UserID must be present
@@ -471,6 +619,7 @@ func (s *service) LogEventWithContext(ctx context.Context, args *LogEventArgs) {
 		ContainsPII: containsPII,
 	}
 	err = s.repo.CreateEvent(&event)
+	telemetry.ObserveEvent(args.EventType, err)
 	if err != nil {
 		log.WithFields(f).Error(fmt.Sprintf("unable to create event for args %#v", args), err)
 	}
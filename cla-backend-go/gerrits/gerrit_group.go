@@ -0,0 +1,318 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package gerrits
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/config"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	v2Models "github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// gerritMagicPrefix is the line the gerrit REST API prefixes the JSON responses with
+// See: https://gerrit-review.googlesource.com/Documentation/rest-api.html#output
+const gerritMagicPrefix = ")]}'"
+
+// errors
+var (
+	ErrGerritAccountNotFound  = errors.New("no gerrit account matches the emails of the user")
+	ErrGerritAccountAmbiguous = errors.New("more than one gerrit account matches the emails of the user")
+)
+
+// UserLookup loads the EasyCLA users, the gerrit accounts of the users are resolved with their emails
+type UserLookup interface {
+	GetUserByLFUserName(lfUserName string) (*models.User, error)
+}
+
+// GerritGroup manages the internal groups of a gerrit instance through its REST API
+// See: https://gerrit-review.googlesource.com/Documentation/rest-api-groups.html
+type GerritGroup struct {
+	// BaseURL is the URL of the gerrit instance including the path prefix, e.g. https://gerrit.onap.org/r
+	BaseURL       string
+	Account       config.GerritAccount
+	Users         UserLookup
+	EventsService events.Service
+	client        *http.Client
+	// accountIDs caches the gerrit account IDs resolved for the LF usernames
+	accountIDs map[string]string
+}
+
+// AccountInfo entity contains information about an account. https://gerrit-review.googlesource.com/Documentation/rest-api-accounts.html#account-info
+type AccountInfo struct {
+	AccountID int    `json:"_account_id"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	Username  string `json:"username"`
+}
+
+// newGerritGroup creates the REST API client of the gerrit instance
+func newGerritGroup(baseURL string, account config.GerritAccount, users UserLookup, eventsService events.Service) *GerritGroup {
+	return &GerritGroup{
+		BaseURL:       baseURL,
+		Account:       account,
+		Users:         users,
+		EventsService: eventsService,
+		client: &http.Client{
+			Timeout:   DefaultHTTPTimeout,
+			Transport: telemetry.NewHTTPTransport(nil),
+		},
+		accountIDs: map[string]string{},
+	}
+}
+
+// MemberID returns the ID of the gerrit account of the user - the gerrit usernames are unrelated to the LF
+// usernames, the account is looked up with the emails of the EasyCLA user and must be the only match
+func (g *GerritGroup) MemberID(ctx context.Context, userName string) (string, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.gerrits.gerrit_group.MemberID",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"gerritURL":      g.BaseURL,
+		"userName":       userName,
+	}
+
+	if accountID, ok := g.accountIDs[userName]; ok {
+		return accountID, nil
+	}
+	if g.Users == nil {
+		return "", fmt.Errorf("unable to resolve the gerrit account of user: %s - no user lookup configured", userName)
+	}
+
+	userModel, err := g.Users.GetUserByLFUserName(userName)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the user")
+		return "", err
+	}
+	if userModel == nil {
+		return "", ErrGerritAccountNotFound
+	}
+
+	accountIDs := map[int]bool{}
+	for _, email := range utils.RemoveDuplicates(append([]string{userModel.LfEmail}, userModel.Emails...)) {
+		email = strings.TrimSpace(email)
+		if email == "" {
+			continue
+		}
+		var accounts []AccountInfo
+		err := g.call(ctx, http.MethodGet, fmt.Sprintf("/a/accounts/?q=%s", url.QueryEscape("email:"+email)), &accounts)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warnf("unable to query the gerrit accounts with email: %s", email)
+			return "", err
+		}
+		for _, account := range accounts {
+			accountIDs[account.AccountID] = true
+		}
+	}
+
+	switch len(accountIDs) {
+	case 0:
+		return "", ErrGerritAccountNotFound
+	case 1:
+		for accountID := range accountIDs {
+			g.accountIDs[userName] = strconv.Itoa(accountID)
+		}
+		log.WithFields(f).Debugf("resolved the gerrit account: %s of user: %s", g.accountIDs[userName], userName)
+		return g.accountIDs[userName], nil
+	default:
+		log.WithFields(f).Warnf("found %d gerrit accounts matching the emails of user: %s", len(accountIDs), userName)
+		return "", ErrGerritAccountAmbiguous
+	}
+}
+
+// GetGroup returns the gerrit group, the group ID is the group UUID or name
+func (g *GerritGroup) GetGroup(ctx context.Context, groupID string) (*LDAPGroup, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.gerrits.gerrit_group.GetGroup",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"gerritURL":      g.BaseURL,
+		"groupID":        groupID,
+	}
+
+	var group GroupInfo
+	err := g.call(ctx, http.MethodGet, fmt.Sprintf("/a/groups/%s", url.PathEscape(groupID)), &group)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to get gerrit group")
+		return nil, err
+	}
+
+	return &LDAPGroup{Title: group.Name}, nil
+}
+
+// GetUsersOfGroup returns the members of the gerrit group
+func (g *GerritGroup) GetUsersOfGroup(ctx context.Context, authUser *auth.User, claGroupID, groupName string) (*v2Models.GerritGroupResponse, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.gerrits.gerrit_group.GetUsersOfGroup",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"gerritURL":      g.BaseURL,
+		"claGroupID":     claGroupID,
+		"groupName":      groupName,
+		"authUserName":   authUser.UserName,
+		"authUserEmail":  authUser.Email,
+	}
+
+	log.WithFields(f).Debug("getting users of group...")
+	var accounts []AccountInfo
+	err := g.call(ctx, http.MethodGet, fmt.Sprintf("/a/groups/%s/members/", url.PathEscape(groupName)), &accounts)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to get the gerrit group members")
+		return nil, err
+	}
+
+	result := &v2Models.GerritGroupResponse{
+		Title: groupName,
+	}
+	for _, account := range accounts {
+		result.Members = append(result.Members, &v2Models.GerritGroupResponseMembersItems0{
+			Mail:     account.Email,
+			UID:      fmt.Sprintf("%d", account.AccountID),
+			Username: account.Username,
+		})
+	}
+	log.WithFields(f).Debugf("successfully fetched %d members from group: %s", len(result.Members), groupName)
+
+	return result, nil
+}
+
+// AddUserToGroup adds the gerrit account of the user with the LF username to the gerrit group
+func (g *GerritGroup) AddUserToGroup(ctx context.Context, authUser *auth.User, claGroupID, groupName, userName string) error {
+	f := logrus.Fields{
+		"functionName":   "v1.gerrits.gerrit_group.AddUserToGroup",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"gerritURL":      g.BaseURL,
+		"claGroupID":     claGroupID,
+		"groupName":      groupName,
+		"userName":       userName,
+		"authUserName":   authUser.UserName,
+		"authUserEmail":  authUser.Email,
+	}
+
+	accountID, err := g.MemberID(ctx, userName)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warnf("unable to resolve the gerrit account of user: %s", userName)
+		return err
+	}
+
+	log.WithFields(f).Debugf("adding gerrit account: %s to group...", accountID)
+	err = g.call(ctx, http.MethodPut, fmt.Sprintf("/a/groups/%s/members/%s", url.PathEscape(groupName), url.PathEscape(accountID)), nil)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warnf("error adding user: %s to group: %s", userName, groupName)
+		return err
+	}
+
+	log.WithFields(f).Debugf("successfully added user: %s to group: %s", userName, groupName)
+	g.EventsService.LogEventWithContext(ctx, &events.LogEventArgs{
+		EventType:  events.GerritUserAdded,
+		LfUsername: authUser.UserName,
+		UserName:   authUser.UserName,
+		CLAGroupID: claGroupID,
+		EventData: &events.GerritUserAddedEventData{
+			Username:  userName,
+			GroupName: groupName,
+			Reason:    GroupChangeReason(ctx),
+		},
+	})
+
+	return nil
+}
+
+// RemoveUserFromGroup removes the gerrit account of the user with the LF username from the gerrit group
+func (g *GerritGroup) RemoveUserFromGroup(ctx context.Context, authUser *auth.User, claGroupID, groupName, userName string) error {
+	f := logrus.Fields{
+		"functionName":   "v1.gerrits.gerrit_group.RemoveUserFromGroup",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"gerritURL":      g.BaseURL,
+		"claGroupID":     claGroupID,
+		"groupName":      groupName,
+		"userName":       userName,
+		"authUserName":   authUser.UserName,
+		"authUserEmail":  authUser.Email,
+	}
+
+	accountID, err := g.MemberID(ctx, userName)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warnf("unable to resolve the gerrit account of user: %s", userName)
+		return err
+	}
+
+	log.WithFields(f).Debugf("removing gerrit account: %s from group...", accountID)
+	err = g.call(ctx, http.MethodDelete, fmt.Sprintf("/a/groups/%s/members/%s", url.PathEscape(groupName), url.PathEscape(accountID)), nil)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warnf("error removing user: %s from group: %s", userName, groupName)
+		return err
+	}
+
+	log.WithFields(f).Debugf("successfully removed user: %s from group: %s", userName, groupName)
+	g.EventsService.LogEventWithContext(ctx, &events.LogEventArgs{
+		EventType:  events.GerritUserRemoved,
+		LfUsername: authUser.UserName,
+		UserName:   authUser.UserName,
+		CLAGroupID: claGroupID,
+		EventData: &events.GerritUserRemovedEventData{
+			Username:  userName,
+			GroupName: groupName,
+			Reason:    GroupChangeReason(ctx),
+		},
+	})
+
+	return nil
+}
+
+// call invokes the authenticated REST API endpoint, the JSON response is decoded into out when set
+func (g *GerritGroup) call(ctx context.Context, method, path string, out interface{}) error {
+	f := logrus.Fields{
+		"functionName":   "v1.gerrits.gerrit_group.call",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"method":         method,
+		"gerritURL":      g.BaseURL,
+	}
+
+	requestURL := g.BaseURL + path
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(g.Account.Username, g.Account.Password)
+	req.Header.Add("Accept", "application/json")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := resp.Body.Close()
+		if closeErr != nil {
+			log.WithFields(f).WithError(closeErr).Warn("error closing response body")
+		}
+	}()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("non-success response from gerrit for %s %s, status: %s, response: %s",
+			method, requestURL, resp.Status, string(bytes.TrimSpace(body)))
+	}
+	if out == nil {
+		return nil
+	}
+
+	return json.Unmarshal(bytes.TrimPrefix(body, []byte(gerritMagicPrefix)), out)
+}
@@ -0,0 +1,84 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package gerrits
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+
+	"github.com/communitybridge/easycla/cla-backend-go/config"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	v2Models "github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+)
+
+// group sync backends - the backend manages the ICLA/CCLA group membership of a gerrit instance
+const (
+	// GroupSyncBackendLFGroup manages the LDAP groups through the LF Group API, LF hosted instances only
+	GroupSyncBackendLFGroup = "lf-group"
+	// GroupSyncBackendGerrit manages the internal groups through the REST API of the gerrit instance
+	GroupSyncBackendGerrit = "gerrit"
+)
+
+// GroupSync manages the members of the groups the gerrit instance grants the CLA access with
+type GroupSync interface {
+	GetGroup(ctx context.Context, groupID string) (*LDAPGroup, error)
+	GetUsersOfGroup(ctx context.Context, authUser *auth.User, claGroupID, groupName string) (*v2Models.GerritGroupResponse, error)
+	// MemberID returns the ID of the user with the LF username among the group members, see listedMemberID
+	MemberID(ctx context.Context, userName string) (string, error)
+	AddUserToGroup(ctx context.Context, authUser *auth.User, claGroupID, groupName, userName string) error
+	RemoveUserFromGroup(ctx context.Context, authUser *auth.User, claGroupID, groupName, userName string) error
+}
+
+// GerritGroupSync contains the access information of the gerrit instances managing their internal groups
+type GerritGroupSync struct {
+	// Accounts keyed by the gerrit host
+	Accounts map[string]config.GerritAccount
+	// Users resolves the gerrit accounts of the LF users by their emails
+	Users         UserLookup
+	EventsService events.Service
+}
+
+// groupSync returns the group sync backend of the gerrit instance
+func (s service) groupSync(backend, gerritURL string) (GroupSync, error) {
+	switch backend {
+	case "", GroupSyncBackendLFGroup:
+		if s.lfGroup == nil {
+			return nil, fmt.Errorf("the %s group sync backend is not configured", GroupSyncBackendLFGroup)
+		}
+		return s.lfGroup, nil
+	case GroupSyncBackendGerrit:
+		if s.gerritGroupSync == nil {
+			return nil, fmt.Errorf("the %s group sync backend is not configured", GroupSyncBackendGerrit)
+		}
+		return s.gerritGroupSync.forInstance(gerritURL)
+	default:
+		return nil, fmt.Errorf("unsupported group sync backend: %s", backend)
+	}
+}
+
+// forInstance returns the REST API client of the gerrit instance, the instance must have an account
+func (g *GerritGroupSync) forInstance(gerritURL string) (GroupSync, error) {
+	u, err := url.Parse(gerritURL)
+	if err != nil {
+		return nil, err
+	}
+	account, ok := g.Accounts[u.Host]
+	if !ok {
+		return nil, fmt.Errorf("no gerrit account configured for gerrit host: %s", u.Host)
+	}
+	return newGerritGroup(strings.TrimSuffix(gerritURL, "/"), account, g.Users, g.EventsService), nil
+}
+
+// listedMemberID returns the ID of the member listed by GetUsersOfGroup, the same ID MemberID returns for the user -
+// the account ID of the gerrit internal groups, the lower case LF username of the LDAP groups
+func listedMemberID(backend string, member *v2Models.GerritGroupResponseMembersItems0) string {
+	if backend == GroupSyncBackendGerrit {
+		return member.UID
+	}
+	return strings.ToLower(member.Username)
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"time"
 
 	v2Models "github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
@@ -18,6 +19,7 @@ import (
 	"github.com/communitybridge/easycla/cla-backend-go/events"
 
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
 	"github.com/sirupsen/logrus"
 )
@@ -57,7 +59,7 @@ func (lfg *LFGroup) getAccessToken(ctx context.Context) (string, error) {
 		return "", err
 	}
 	OauthURL := fmt.Sprintf("%s/oauth2/token", lfg.LfBaseURL)
-	req, err := http.NewRequest("POST", OauthURL, bytes.NewBuffer(requestBody))
+	req, err := http.NewRequestWithContext(ctx, "POST", OauthURL, bytes.NewBuffer(requestBody))
 	if err != nil {
 		log.WithFields(f).WithError(err).Warnf("problem creating a new request to URL: %s", OauthURL)
 		return "", err
@@ -66,7 +68,8 @@ func (lfg *LFGroup) getAccessToken(ctx context.Context) (string, error) {
 	req.Header.Add("Content-Type", "application/json")
 
 	client := http.Client{
-		Timeout: DefaultHTTPTimeout,
+		Timeout:   DefaultHTTPTimeout,
+		Transport: telemetry.NewHTTPTransport(nil),
 	}
 	res, err := client.Do(req)
 	if err != nil {
@@ -114,7 +117,7 @@ func (lfg *LFGroup) GetGroup(ctx context.Context, groupID string) (*LDAPGroup, e
 		return nil, err
 	}
 	getGroupURL := fmt.Sprintf("%s/rest/auth0/og/%s", lfg.LfBaseURL, groupID)
-	req, err := http.NewRequest("GET", getGroupURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", getGroupURL, nil)
 	if err != nil {
 		log.WithFields(f).WithError(err).Warnf("problem creating a new request to URL: %s", getGroupURL)
 		return nil, err
@@ -123,7 +126,8 @@ func (lfg *LFGroup) GetGroup(ctx context.Context, groupID string) (*LDAPGroup, e
 	req.Header.Add("Authorization", "Bearer "+accessToken)
 
 	client := http.Client{
-		Timeout: DefaultHTTPTimeout,
+		Timeout:   DefaultHTTPTimeout,
+		Transport: telemetry.NewHTTPTransport(nil),
 	}
 	res, err := client.Do(req)
 	if err != nil {
@@ -179,7 +183,7 @@ func (lfg *LFGroup) GetUsersOfGroup(ctx context.Context, authUser *auth.User, cl
 	url := fmt.Sprintf("%s/rest/auth0/og/%s", lfg.LfBaseURL, groupName)
 
 	// Setup the request
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		log.WithFields(f).WithError(err).Warnf("problem creating a new request to URL: %s", url)
 		return nil, err
@@ -187,7 +191,8 @@ func (lfg *LFGroup) GetUsersOfGroup(ctx context.Context, authUser *auth.User, cl
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("Authorization", "Bearer "+accessToken)
 	client := http.Client{
-		Timeout: LongHTTPTimeout,
+		Timeout:   LongHTTPTimeout,
+		Transport: telemetry.NewHTTPTransport(nil),
 	}
 
 	// Invoke the request
@@ -230,6 +235,11 @@ func (lfg *LFGroup) GetUsersOfGroup(ctx context.Context, authUser *auth.User, cl
 	return nil, nil
 }
 
+// MemberID returns the ID of the user among the LDAP group members, the LDAP groups are made of the LF accounts
+func (lfg *LFGroup) MemberID(ctx context.Context, userName string) (string, error) {
+	return strings.ToLower(userName), nil
+}
+
 // AddUserToGroup adds the specified user to the group
 func (lfg *LFGroup) AddUserToGroup(ctx context.Context, authUser *auth.User, claGroupID, groupName, userName string) error {
 	f := logrus.Fields{
@@ -266,7 +276,7 @@ func (lfg *LFGroup) AddUserToGroup(ctx context.Context, authUser *auth.User, cla
 	}
 
 	// Setup the request
-	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(payloadBytes))
+	req, err := http.NewRequestWithContext(ctx, "PUT", url, bytes.NewBuffer(payloadBytes))
 	if err != nil {
 		log.WithFields(f).WithError(err).Warnf("problem creating a new request to URL: %s", url)
 		return err
@@ -274,7 +284,8 @@ func (lfg *LFGroup) AddUserToGroup(ctx context.Context, authUser *auth.User, cla
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("Authorization", "Bearer "+accessToken)
 	client := http.Client{
-		Timeout: DefaultHTTPTimeout,
+		Timeout:   DefaultHTTPTimeout,
+		Transport: telemetry.NewHTTPTransport(nil),
 	}
 
 	// Invoke the request
@@ -304,6 +315,7 @@ func (lfg *LFGroup) AddUserToGroup(ctx context.Context, authUser *auth.User, cla
 			EventData: &events.GerritUserAddedEventData{
 				Username:  userName,
 				GroupName: groupName,
+				Reason:    GroupChangeReason(ctx),
 			},
 		})
 	} else {
@@ -348,7 +360,7 @@ func (lfg *LFGroup) RemoveUserFromGroup(ctx context.Context, authUser *auth.User
 	}
 
 	// Setup the request
-	req, err := http.NewRequest("DELETE", url, bytes.NewBuffer(payloadBytes))
+	req, err := http.NewRequestWithContext(ctx, "DELETE", url, bytes.NewBuffer(payloadBytes))
 	if err != nil {
 		log.WithFields(f).WithError(err).Warnf("problem creating a new request to URL: %s", url)
 		return err
@@ -356,7 +368,8 @@ func (lfg *LFGroup) RemoveUserFromGroup(ctx context.Context, authUser *auth.User
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("Authorization", "Bearer "+accessToken)
 	client := http.Client{
-		Timeout: DefaultHTTPTimeout,
+		Timeout:   DefaultHTTPTimeout,
+		Transport: telemetry.NewHTTPTransport(nil),
 	}
 
 	// Invoke the request
@@ -386,6 +399,7 @@ func (lfg *LFGroup) RemoveUserFromGroup(ctx context.Context, authUser *auth.User
 			EventData: &events.GerritUserRemovedEventData{
 				Username:  userName,
 				GroupName: groupName,
+				Reason:    GroupChangeReason(ctx),
 			},
 		})
 	} else {
@@ -10,35 +10,40 @@ import (
 
 // Gerrit represent gerrit instances table
 type Gerrit struct {
-	DateCreated   string `json:"date_created,omitempty"`
-	DateModified  string `json:"date_modified,omitempty"`
-	GerritID      string `json:"gerrit_id,omitempty"`
-	GerritName    string `json:"gerrit_name,omitempty"`
-	GerritURL     string `json:"gerrit_url,omitempty"`
-	GroupIDCcla   string `json:"group_id_ccla,omitempty"`
-	GroupIDIcla   string `json:"group_id_icla,omitempty"`
-	GroupNameCcla string `json:"group_name_ccla,omitempty"`
-	GroupNameIcla string `json:"group_name_icla,omitempty"`
-	ProjectSFID   string `json:"project_sfid,omitempty"`
-	ProjectID     string `json:"project_id,omitempty"`
-	Version       string `json:"version,omitempty"`
+	DateCreated      string `json:"date_created,omitempty"`
+	DateModified     string `json:"date_modified,omitempty"`
+	GerritID         string `json:"gerrit_id,omitempty"`
+	GerritName       string `json:"gerrit_name,omitempty"`
+	GerritURL        string `json:"gerrit_url,omitempty"`
+	GroupIDCcla      string `json:"group_id_ccla,omitempty"`
+	GroupIDIcla      string `json:"group_id_icla,omitempty"`
+	GroupNameCcla    string `json:"group_name_ccla,omitempty"`
+	GroupNameIcla    string `json:"group_name_icla,omitempty"`
+	GroupSyncBackend string `json:"group_sync_backend,omitempty"`
+	// GroupReconcileApply is set when the group reconciliation may change the group members of the instance
+	GroupReconcileApply bool   `json:"group_reconcile_apply,omitempty"`
+	ProjectSFID         string `json:"project_sfid,omitempty"`
+	ProjectID           string `json:"project_id,omitempty"`
+	Version             string `json:"version,omitempty"`
 }
 
 // toModel converts the gerrit structure into a response model
 func (g *Gerrit) toModel() *models.Gerrit {
 	return &models.Gerrit{
-		DateCreated:   g.DateCreated,
-		DateModified:  g.DateModified,
-		GerritID:      strfmt.UUID4(g.GerritID),
-		GerritName:    g.GerritName,
-		GerritURL:     strfmt.URI(g.GerritURL),
-		GroupIDCcla:   g.GroupIDCcla,
-		GroupIDIcla:   g.GroupIDIcla,
-		GroupNameCcla: g.GroupNameCcla,
-		GroupNameIcla: g.GroupNameIcla,
-		ProjectID:     g.ProjectID,
-		Version:       g.Version,
-		ProjectSFID:   g.ProjectSFID,
+		DateCreated:         g.DateCreated,
+		DateModified:        g.DateModified,
+		GerritID:            strfmt.UUID4(g.GerritID),
+		GerritName:          g.GerritName,
+		GerritURL:           strfmt.URI(g.GerritURL),
+		GroupIDCcla:         g.GroupIDCcla,
+		GroupIDIcla:         g.GroupIDIcla,
+		GroupNameCcla:       g.GroupNameCcla,
+		GroupNameIcla:       g.GroupNameIcla,
+		GroupSyncBackend:    g.GroupSyncBackend,
+		GroupReconcileApply: g.GroupReconcileApply,
+		ProjectID:           g.ProjectID,
+		Version:             g.Version,
+		ProjectSFID:         g.ProjectSFID,
 	}
 }
 
@@ -0,0 +1,240 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package gerrits
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/sirupsen/logrus"
+
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// groupChangeReasonKey is the context key of the reason recorded on the group membership change events
+type groupChangeReasonKey struct{}
+
+// WithGroupChangeReason returns a context recording the reason of the group membership changes made with it
+func WithGroupChangeReason(ctx context.Context, reason string) context.Context {
+	return context.WithValue(ctx, groupChangeReasonKey{}, reason)
+}
+
+// GroupChangeReason returns the reason of the group membership change, empty if not set
+func GroupChangeReason(ctx context.Context) string {
+	reason, _ := ctx.Value(groupChangeReasonKey{}).(string)
+	return reason
+}
+
+// ExpectedMembers are the users expected in the CLA type group along with the reason they belong to it, the users
+// are identified by their LF username
+type ExpectedMembers struct {
+	// Members maps the expected user names to the reason they are expected
+	Members map[string]string
+	// RemovalReasons maps the user names to the reason they must not be members, e.g. the approval list entry denying them
+	RemovalReasons map[string]string
+}
+
+// NewExpectedMembers creates an empty set of expected members
+func NewExpectedMembers() *ExpectedMembers {
+	return &ExpectedMembers{
+		Members:        map[string]string{},
+		RemovalReasons: map[string]string{},
+	}
+}
+
+// Expect adds the user to the expected members, the first reason is kept
+func (e *ExpectedMembers) Expect(userName, reason string) {
+	userName = strings.TrimSpace(userName)
+	if userName == "" {
+		return
+	}
+	if _, ok := e.Members[userName]; !ok {
+		e.Members[userName] = reason
+	}
+}
+
+// Revoke records why the user must not be a member, an expected user stays expected
+func (e *ExpectedMembers) Revoke(userName, reason string) {
+	userName = strings.TrimSpace(userName)
+	if userName == "" {
+		return
+	}
+	if _, ok := e.RemovalReasons[userName]; !ok {
+		e.RemovalReasons[userName] = reason
+	}
+}
+
+// GroupReconcileResult is the outcome of the reconciliation of the group of a gerrit instance
+type GroupReconcileResult struct {
+	GerritID   string
+	GerritName string
+	GroupName  string
+	// DryRun is true when the changes were only computed, not applied
+	DryRun bool
+	// Added are the users added to the group
+	Added []string
+	// Removed are the members removed from the group
+	Removed []string
+	// Failed are the users whose add or remove failed
+	Failed []string
+	// Reasons maps the added and removed users to the reason of the change
+	Reasons map[string]string
+}
+
+// ReconcileGroup fixes the drift between the members of the CLA type group of the CLA Group gerrit instances and the
+// expected members - the missing users are added and the revoked users are removed. The users are matched with the
+// group members through their account of the group sync backend, the members which don't map to a revoked user are
+// left alone. In dry-run mode, or when the instance didn't opt in to the group reconciliation, the changes are only
+// reported.
+func (s service) ReconcileGroup(ctx context.Context, authUser *auth.User, claGroupID, claType string, expectedMembers *ExpectedMembers, dryRun bool) ([]*GroupReconcileResult, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.gerrits.reconcile.ReconcileGroup",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+		"claType":        claType,
+		"dryRun":         dryRun,
+		"authUserName":   authUser.UserName,
+		"authUserEmail":  authUser.Email,
+	}
+
+	gerritList, err := s.repo.GetClaGroupGerrits(ctx, claGroupID)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warnf("unable to locate gerrits associated with CLA Group ID: %s", claGroupID)
+		return nil, err
+	}
+
+	results := make([]*GroupReconcileResult, 0, len(gerritList.List))
+	for _, gerritModel := range gerritList.List {
+		var groupName string
+		switch claType {
+		case utils.ClaTypeICLA:
+			groupName = gerritModel.GroupNameIcla
+		case utils.ClaTypeECLA:
+			groupName = gerritModel.GroupNameCcla
+		default:
+			return nil, &utils.InvalidCLAType{
+				CLAType: claType,
+			}
+		}
+		if groupName == "" {
+			log.WithFields(f).Debugf("gerrit: %s has no %s group - skipping", gerritModel.GerritName, claType)
+			continue
+		}
+
+		groupSync, groupSyncErr := s.groupSync(gerritModel.GroupSyncBackend, gerritModel.GerritURL.String())
+		if groupSyncErr != nil {
+			log.WithFields(f).WithError(groupSyncErr).Warnf("unable to load the group sync backend of gerrit: %s", gerritModel.GerritName)
+			return nil, groupSyncErr
+		}
+
+		members, membersErr := groupSync.GetUsersOfGroup(ctx, authUser, claGroupID, groupName)
+		if membersErr != nil {
+			return nil, membersErr
+		}
+		if members == nil {
+			return nil, fmt.Errorf("unable to load the members of group: %s of gerrit: %s", groupName, gerritModel.GerritName)
+		}
+		current := make(map[string]bool, len(members.Members))
+		for _, member := range members.Members {
+			// the members without an ID, e.g. the included groups, are not managed by EasyCLA
+			if member == nil {
+				continue
+			}
+			if memberID := listedMemberID(gerritModel.GroupSyncBackend, member); memberID != "" {
+				current[memberID] = true
+			}
+		}
+
+		// the group members are only changed on the instances which opted in
+		instanceDryRun := dryRun || !gerritModel.GroupReconcileApply
+		if instanceDryRun && !dryRun {
+			log.WithFields(f).Infof("gerrit: %s did not opt in to the group reconciliation - only reporting the changes", gerritModel.GerritName)
+		}
+
+		result := &GroupReconcileResult{
+			GerritID:   gerritModel.GerritID.String(),
+			GerritName: gerritModel.GerritName,
+			GroupName:  groupName,
+			DryRun:     instanceDryRun,
+			Reasons:    map[string]string{},
+		}
+		// the member IDs of the expected users, the users without an account can't be added
+		expected := map[string]bool{}
+		for _, userName := range sortedKeys(expectedMembers.Members) {
+			memberID, memberErr := groupSync.MemberID(ctx, userName)
+			if memberErr != nil {
+				log.WithFields(f).WithError(memberErr).Warnf("unable to resolve the account of user %s on gerrit: %s", userName, gerritModel.GerritName)
+				result.Failed = append(result.Failed, userName)
+				result.Reasons[userName] = fmt.Sprintf("unable to resolve the account: %s", memberErr)
+				continue
+			}
+			expected[memberID] = true
+			if current[memberID] {
+				continue
+			}
+			reason := expectedMembers.Members[userName]
+			result.Reasons[userName] = reason
+			if instanceDryRun {
+				log.WithFields(f).Infof("dry-run: would add user %s to group: %s of gerrit: %s - %s", userName, groupName, gerritModel.GerritName, reason)
+				result.Added = append(result.Added, userName)
+				continue
+			}
+			if addErr := groupSync.AddUserToGroup(WithGroupChangeReason(ctx, reason), authUser, claGroupID, groupName, userName); addErr != nil {
+				log.WithFields(f).WithError(addErr).Warnf("unable to add user %s to group: %s", userName, groupName)
+				result.Failed = append(result.Failed, userName)
+				continue
+			}
+			result.Added = append(result.Added, userName)
+		}
+		for _, userName := range sortedKeys(expectedMembers.RemovalReasons) {
+			memberID, memberErr := groupSync.MemberID(ctx, userName)
+			if memberErr == ErrGerritAccountNotFound {
+				// a user without an account is not a member
+				continue
+			}
+			if memberErr != nil {
+				log.WithFields(f).WithError(memberErr).Warnf("unable to resolve the account of user %s on gerrit: %s", userName, gerritModel.GerritName)
+				result.Failed = append(result.Failed, userName)
+				result.Reasons[userName] = fmt.Sprintf("unable to resolve the account: %s", memberErr)
+				continue
+			}
+			if !current[memberID] || expected[memberID] {
+				continue
+			}
+			reason := expectedMembers.RemovalReasons[userName]
+			result.Reasons[userName] = reason
+			if instanceDryRun {
+				log.WithFields(f).Infof("dry-run: would remove user %s from group: %s of gerrit: %s - %s", userName, groupName, gerritModel.GerritName, reason)
+				result.Removed = append(result.Removed, userName)
+				continue
+			}
+			if removeErr := groupSync.RemoveUserFromGroup(WithGroupChangeReason(ctx, reason), authUser, claGroupID, groupName, userName); removeErr != nil {
+				log.WithFields(f).WithError(removeErr).Warnf("unable to remove user %s from group: %s", userName, groupName)
+				result.Failed = append(result.Failed, userName)
+				continue
+			}
+			result.Removed = append(result.Removed, userName)
+		}
+
+		log.WithFields(f).Debugf("reconciled group: %s of gerrit: %s - added %d, removed %d, failed %d",
+			groupName, gerritModel.GerritName, len(result.Added), len(result.Removed), len(result.Failed))
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// sortedKeys returns the keys of the map in order
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
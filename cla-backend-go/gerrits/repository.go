@@ -25,6 +25,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
 )
 
 // errors
@@ -40,6 +41,7 @@ type Repository interface {
 	GetGerritsByID(ctx context.Context, ID string, IDType string) (*models.GerritList, error)
 	GetGerritsByProjectSFID(ctx context.Context, projectSFID string) (*models.GerritList, error)
 	GetClaGroupGerrits(ctx context.Context, claGroupID string) (*models.GerritList, error)
+	GetAllGerrits(ctx context.Context) (*models.GerritList, error)
 	ExistsByName(ctx context.Context, gerritName string) ([]*models.Gerrit, error)
 	DeleteGerrit(ctx context.Context, gerritID string) error
 }
@@ -61,6 +63,9 @@ type repo struct {
 
 // AddGerrit creates a new gerrit instance
 func (repo *repo) AddGerrit(ctx context.Context, input *models.Gerrit) (*models.Gerrit, error) {
+	ctx, span := telemetry.StartSpan(ctx, "gerrits.repository.AddGerrit")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.gerrits.repository.AddGerrit",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -71,25 +76,27 @@ func (repo *repo) AddGerrit(ctx context.Context, input *models.Gerrit) (*models.
 	}
 	_, currentTime := utils.CurrentTime()
 	gerrit := &Gerrit{
-		DateCreated:   currentTime,
-		DateModified:  currentTime,
-		GerritID:      gerritID.String(),
-		GerritName:    input.GerritName,
-		GerritURL:     input.GerritURL.String(),
-		GroupIDCcla:   input.GroupIDCcla,
-		GroupIDIcla:   input.GroupIDIcla,
-		GroupNameCcla: input.GroupNameCcla,
-		GroupNameIcla: input.GroupNameIcla,
-		ProjectID:     input.ProjectID,
-		ProjectSFID:   input.ProjectSFID,
-		Version:       input.Version,
+		DateCreated:         currentTime,
+		DateModified:        currentTime,
+		GerritID:            gerritID.String(),
+		GerritName:          input.GerritName,
+		GerritURL:           input.GerritURL.String(),
+		GroupIDCcla:         input.GroupIDCcla,
+		GroupIDIcla:         input.GroupIDIcla,
+		GroupNameCcla:       input.GroupNameCcla,
+		GroupNameIcla:       input.GroupNameIcla,
+		GroupSyncBackend:    input.GroupSyncBackend,
+		GroupReconcileApply: input.GroupReconcileApply,
+		ProjectID:           input.ProjectID,
+		ProjectSFID:         input.ProjectSFID,
+		Version:             input.Version,
 	}
 	av, err := dynamodbattribute.MarshalMap(gerrit)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = repo.dynamoDBClient.PutItem(&dynamodb.PutItemInput{
+	_, err = repo.dynamoDBClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
 		Item:      av,
 		TableName: aws.String(repo.tableName),
 	})
@@ -103,6 +110,9 @@ func (repo *repo) AddGerrit(ctx context.Context, input *models.Gerrit) (*models.
 
 // GetGerrit returns the gerrit instances based on the ID
 func (repo *repo) GetGerrit(ctx context.Context, gerritID string) (*models.Gerrit, error) {
+	ctx, span := telemetry.StartSpan(ctx, "gerrits.repository.GetGerrit")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.gerrits.repository.GetGerrit",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -118,7 +128,7 @@ func (repo *repo) GetGerrit(ctx context.Context, gerritID string) (*models.Gerri
 		TableName: aws.String(repo.tableName),
 	}
 
-	result, err := repo.dynamoDBClient.GetItem(input)
+	result, err := repo.dynamoDBClient.GetItemWithContext(ctx, input)
 	if err != nil {
 		log.WithFields(f).Warnf("error getting gerrit repository : %s. error = %s", gerritID, err)
 		return nil, err
@@ -137,6 +147,9 @@ func (repo *repo) GetGerrit(ctx context.Context, gerritID string) (*models.Gerri
 }
 
 func (repo repo) GetGerritsByID(ctx context.Context, ID string, IDType string) (*models.GerritList, error) {
+	ctx, span := telemetry.StartSpan(ctx, "gerrits.repository.GetGerritsByID")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.gerrits.repository.GetGerritsByID",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -169,7 +182,7 @@ func (repo repo) GetGerritsByID(ctx context.Context, ID string, IDType string) (
 	}
 
 	for {
-		results, err := repo.dynamoDBClient.Scan(scanInput)
+		results, err := repo.dynamoDBClient.ScanWithContext(ctx, scanInput)
 		if err != nil {
 			log.WithFields(f).Warnf("error retrieving gerrit instances, error: %v", err)
 			return nil, err
@@ -200,6 +213,9 @@ func (repo repo) GetGerritsByID(ctx context.Context, ID string, IDType string) (
 }
 
 func (repo repo) GetGerritsByProjectSFID(ctx context.Context, projectSFID string) (*models.GerritList, error) {
+	ctx, span := telemetry.StartSpan(ctx, "gerrits.repository.GetGerritsByProjectSFID")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.gerrits.repository.GetGerritsByProjectSFID",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -226,7 +242,7 @@ func (repo repo) GetGerritsByProjectSFID(ctx context.Context, projectSFID string
 	}
 
 	for {
-		results, err := repo.dynamoDBClient.Query(queryInput)
+		results, err := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 		if err != nil {
 			log.WithFields(f).WithError(err).Warnf("error retrieving gerrit instances by projectSFID, error: %v", err)
 			return nil, err
@@ -261,6 +277,9 @@ func (repo repo) GetGerritsByProjectSFID(ctx context.Context, projectSFID string
 
 // GetClaGroupGerrits returns the CLA Group gerrit instances based on the CLA Group ID
 func (repo repo) GetClaGroupGerrits(ctx context.Context, claGroupID string) (*models.GerritList, error) {
+	ctx, span := telemetry.StartSpan(ctx, "gerrits.repository.GetClaGroupGerrits")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.gerrits.repository.GetClaGroupGerrits",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -289,7 +308,7 @@ func (repo repo) GetClaGroupGerrits(ctx context.Context, claGroupID string) (*mo
 	}
 
 	for {
-		results, err := repo.dynamoDBClient.Query(queryInput)
+		results, err := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 		if err != nil {
 			log.WithFields(f).WithError(err).Warnf("error retrieving gerrit instances, error: %v", err)
 			return nil, err
@@ -322,8 +341,71 @@ func (repo repo) GetClaGroupGerrits(ctx context.Context, claGroupID string) (*mo
 	return &models.GerritList{List: resultList}, nil
 }
 
+// GetAllGerrits returns all the gerrit instances
+func (repo repo) GetAllGerrits(ctx context.Context) (*models.GerritList, error) {
+	ctx, span := telemetry.StartSpan(ctx, "gerrits.repository.GetAllGerrits")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.gerrits.repository.GetAllGerrits",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+	}
+
+	resultList := make([]*models.Gerrit, 0)
+	expr, err := expression.NewBuilder().WithProjection(buildProjection()).Build()
+	if err != nil {
+		log.WithFields(f).Warnf("error building expression for gerrit instances scan, error: %v", err)
+		return nil, err
+	}
+
+	// Assemble the scan input parameters
+	scanInput := &dynamodb.ScanInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ProjectionExpression:      expr.Projection(),
+		TableName:                 aws.String(repo.tableName),
+		Limit:                     aws.Int64(HugePageSize),
+	}
+
+	for {
+		results, err := repo.dynamoDBClient.ScanWithContext(ctx, scanInput)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warnf("error scanning gerrit instances, error: %v", err)
+			return nil, err
+		}
+
+		var gerrits []*Gerrit
+
+		err = dynamodbattribute.UnmarshalListOfMaps(results.Items, &gerrits)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warnf("error unmarshalling gerrit from database. error: %v", err)
+			return nil, err
+		}
+
+		for _, g := range gerrits {
+			resultList = append(resultList, g.toModel())
+		}
+
+		if len(results.LastEvaluatedKey) != 0 {
+			scanInput.ExclusiveStartKey = results.LastEvaluatedKey
+		} else {
+			break
+		}
+	}
+
+	// Sort the results
+	sort.Slice(resultList, func(i, j int) bool {
+		return resultList[i].GerritName < resultList[j].GerritName
+	})
+
+	return &models.GerritList{List: resultList}, nil
+}
+
 // DeleteGerrit removes the gerrit instance based on the gerrit ID
 func (repo *repo) DeleteGerrit(ctx context.Context, gerritID string) error {
+	ctx, span := telemetry.StartSpan(ctx, "gerrits.repository.DeleteGerrit")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.gerrits.repository.DeleteGerrit",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -339,7 +421,7 @@ func (repo *repo) DeleteGerrit(ctx context.Context, gerritID string) error {
 		TableName: aws.String(repo.tableName),
 	}
 
-	_, err := repo.dynamoDBClient.DeleteItem(input)
+	_, err := repo.dynamoDBClient.DeleteItemWithContext(ctx, input)
 	if err != nil {
 		log.WithFields(f).WithError(err).Warnf("error updating gerrit repository : %s during delete project process ", gerritID)
 		return err
@@ -349,6 +431,9 @@ func (repo *repo) DeleteGerrit(ctx context.Context, gerritID string) error {
 }
 
 func (repo *repo) ExistsByName(ctx context.Context, gerritName string) ([]*models.Gerrit, error) {
+	ctx, span := telemetry.StartSpan(ctx, "gerrits.repository.ExistsByName")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.gerrits.repository.ExistsByName",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -386,7 +471,7 @@ func (repo *repo) ExistsByName(ctx context.Context, gerritName string) ([]*model
 	}
 
 	for {
-		results, errQuery := repo.dynamoDBClient.Query(input)
+		results, errQuery := repo.dynamoDBClient.QueryWithContext(ctx, input)
 		if errQuery != nil {
 			log.WithFields(f).WithError(errQuery).Warnf("error retrieving Gerrit. error = %s", errQuery.Error())
 			return nil, errQuery
@@ -421,6 +506,9 @@ func (repo *repo) ExistsByName(ctx context.Context, gerritName string) ([]*model
 }
 
 func (repo *repo) ExistsByID(ctx context.Context, gerritID string) ([]*models.Gerrit, error) {
+	ctx, span := telemetry.StartSpan(ctx, "gerrits.repository.ExistsByID")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.gerrits.repository.ExistsByID",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -459,7 +547,7 @@ func (repo *repo) ExistsByID(ctx context.Context, gerritID string) ([]*models.Ge
 	}
 
 	for {
-		results, errQuery := repo.dynamoDBClient.Query(input)
+		results, errQuery := repo.dynamoDBClient.QueryWithContext(ctx, input)
 		if errQuery != nil {
 			log.WithFields(f).WithError(errQuery).Warnf("error retrieving Gerrit. error = %s", errQuery.Error())
 			return nil, errQuery
@@ -506,6 +594,8 @@ func buildProjection() expression.ProjectionBuilder {
 		expression.Name("group_id_icla"),
 		expression.Name("group_name_ccla"),
 		expression.Name("group_name_icla"),
+		expression.Name("group_sync_backend"),
+		expression.Name("group_reconcile_apply"),
 		expression.Name("project_id"),
 		expression.Name("project_sfid"),
 	)
@@ -18,12 +18,15 @@ import (
 	"github.com/go-resty/resty/v2"
 	"github.com/sirupsen/logrus"
 
+	"github.com/communitybridge/easycla/cla-backend-go/config"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
 
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
 	v2Models "github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
 
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
 )
 
 // Service handles gerrit Repository service
@@ -32,6 +35,7 @@ type Service interface {
 	GetGerrit(ctx context.Context, gerritID string) (*models.Gerrit, error)
 	GetGerritsByProjectSFID(ctx context.Context, projectSFID string) (*models.GerritList, error)
 	GetClaGroupGerrits(ctx context.Context, claGroupID string) (*models.GerritList, error)
+	GetAllGerrits(ctx context.Context) (*models.GerritList, error)
 	GetGerritRepos(ctx context.Context, gerritName string) (*models.GerritRepoList, error)
 	DeleteClaGroupGerrits(ctx context.Context, claGroupID string) (int, error)
 	DeleteGerrit(ctx context.Context, gerritID string) error
@@ -40,21 +44,40 @@ type Service interface {
 	AddUsersToGroup(ctx context.Context, authUser *auth.User, claGroupID string, userNameList []string, claType string) error
 	RemoveUserFromGroup(ctx context.Context, authUser *auth.User, claGroupID, userName, claType string) error
 	RemoveUsersFromGroup(ctx context.Context, authUser *auth.User, claGroupID string, userNameList []string, claType string) error
+	ReconcileGroup(ctx context.Context, authUser *auth.User, claGroupID, claType string, expectedMembers *ExpectedMembers, dryRun bool) ([]*GroupReconcileResult, error)
 }
 
 type service struct {
-	repo    Repository
-	lfGroup *LFGroup
+	repo            Repository
+	lfGroup         *LFGroup
+	gerritGroupSync *GerritGroupSync
 }
 
-// NewService creates a new gerrit service
-func NewService(repo Repository, lfg *LFGroup) Service {
+// NewService creates a new gerrit service, the gerrit group sync is used by the instances managing their internal groups
+func NewService(repo Repository, lfg *LFGroup, gerritGroupSync *GerritGroupSync) Service {
 	return service{
-		repo:    repo,
-		lfGroup: lfg,
+		repo:            repo,
+		lfGroup:         lfg,
+		gerritGroupSync: gerritGroupSync,
 	}
 }
 
+// NewServiceFromConfig creates a new gerrit service with the LF Group and the gerrit group sync backends of the
+// configuration, the users are looked up to resolve their gerrit accounts
+func NewServiceFromConfig(repo Repository, configFile config.Config, users UserLookup, eventsService events.Service) Service {
+	return NewService(repo, &LFGroup{
+		LfBaseURL:     configFile.LFGroup.ClientURL,
+		ClientID:      configFile.LFGroup.ClientID,
+		ClientSecret:  configFile.LFGroup.ClientSecret,
+		RefreshToken:  configFile.LFGroup.RefreshToken,
+		EventsService: eventsService,
+	}, &GerritGroupSync{
+		Accounts:      configFile.GerritAccounts,
+		Users:         users,
+		EventsService: eventsService,
+	})
+}
+
 func (s service) AddGerrit(ctx context.Context, claGroupID string, projectSFID string, params *models.AddGerritInput, claGroupModel *models.ClaGroup) (*models.Gerrit, error) {
 	f := logrus.Fields{
 		"functionName":   "v1.gerrits.service.AddGerrit",
@@ -128,35 +151,57 @@ func (s service) AddGerrit(ctx context.Context, claGroupID string, projectSFID s
 		return nil, errors.New("gerrit_url required")
 	}
 
+	groupSyncBackend := params.GroupSyncBackend
+	if groupSyncBackend == "" {
+		groupSyncBackend = GroupSyncBackendLFGroup
+	}
+	gerritHost, err := extractGerritHost(*params.GerritURL, f)
+	if err != nil {
+		return nil, err
+	}
+	if groupSyncBackend == GroupSyncBackendLFGroup {
+		// the LDAP groups are only available to the LF hosted instances
+		if _, apiPathErr := getGerritAPIPath(ctx, gerritHost); apiPathErr != nil {
+			return nil, fmt.Errorf("gerrit instance %s is not LF hosted, use the %s group sync backend", gerritHost, GroupSyncBackendGerrit)
+		}
+	}
+	groupSync, err := s.groupSync(groupSyncBackend, *params.GerritURL)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warnf("unable to load the group sync backend: %s", groupSyncBackend)
+		return nil, err
+	}
+
 	var groupNameCcla, groupNameIcla string
 	if params.GroupIDIcla != "" {
-		group, err := s.lfGroup.GetGroup(ctx, params.GroupIDIcla)
+		group, err := groupSync.GetGroup(ctx, params.GroupIDIcla)
 		if err != nil {
-			message := fmt.Sprintf("unable to get LDAP ICLA Group: %s", params.GroupIDIcla)
+			message := fmt.Sprintf("unable to get ICLA Group: %s", params.GroupIDIcla)
 			log.WithFields(f).WithError(err).Warnf(message)
 			return nil, errors.New(message)
 		}
 		groupNameIcla = group.Title
 	}
 	if params.GroupIDCcla != "" {
-		group, err := s.lfGroup.GetGroup(ctx, params.GroupIDCcla)
+		group, err := groupSync.GetGroup(ctx, params.GroupIDCcla)
 		if err != nil {
-			message := fmt.Sprintf("unable to get LDAP CCLA Group: %s", params.GroupIDCcla)
+			message := fmt.Sprintf("unable to get CCLA Group: %s", params.GroupIDCcla)
 			log.WithFields(f).WithError(err).Warnf(message)
 			return nil, errors.New(message)
 		}
 		groupNameCcla = group.Title
 	}
 	input := &models.Gerrit{
-		GerritName:    utils.StringValue(params.GerritName),
-		GerritURL:     strfmt.URI(*params.GerritURL),
-		GroupIDCcla:   params.GroupIDCcla,
-		GroupIDIcla:   params.GroupIDIcla,
-		GroupNameCcla: groupNameCcla,
-		GroupNameIcla: groupNameIcla,
-		ProjectID:     claGroupID,
-		ProjectSFID:   projectSFID,
-		Version:       params.Version,
+		GerritName:          utils.StringValue(params.GerritName),
+		GerritURL:           strfmt.URI(*params.GerritURL),
+		GroupIDCcla:         params.GroupIDCcla,
+		GroupIDIcla:         params.GroupIDIcla,
+		GroupNameCcla:       groupNameCcla,
+		GroupNameIcla:       groupNameIcla,
+		GroupSyncBackend:    groupSyncBackend,
+		GroupReconcileApply: params.GroupReconcileApply,
+		ProjectID:           claGroupID,
+		ProjectSFID:         projectSFID,
+		Version:             params.Version,
 	}
 	return s.repo.AddGerrit(ctx, input)
 }
@@ -170,6 +215,11 @@ func (s service) GetGerritsByProjectSFID(ctx context.Context, projectSFID string
 	return s.repo.GetGerritsByProjectSFID(ctx, projectSFID)
 }
 
+// GetAllGerrits returns all the gerrit instances, without their repositories
+func (s service) GetAllGerrits(ctx context.Context) (*models.GerritList, error) {
+	return s.repo.GetAllGerrits(ctx)
+}
+
 func (s service) GetClaGroupGerrits(ctx context.Context, claGroupID string) (*models.GerritList, error) {
 	f := logrus.Fields{
 		"functionName":   "v1.gerrits.service.GetClaGroupGerrits",
@@ -310,8 +360,14 @@ func (s service) GetUsersOfGroup(ctx context.Context, authUser *auth.User, claGr
 			}
 		}
 
+		groupSync, groupSyncErr := s.groupSync(gerritModel.GroupSyncBackend, gerritModel.GerritURL.String())
+		if groupSyncErr != nil {
+			log.WithFields(f).WithError(groupSyncErr).Warnf("unable to load the group sync backend of gerrit: %s", gerritModel.GerritName)
+			return nil, groupSyncErr
+		}
+
 		log.WithFields(f).Debugf("querying for members of gerrit group: %s...", ldapGroupName)
-		g, gerritErr := s.lfGroup.GetUsersOfGroup(ctx, authUser, claGroupID, ldapGroupName)
+		g, gerritErr := groupSync.GetUsersOfGroup(ctx, authUser, claGroupID, ldapGroupName)
 		if gerritErr != nil {
 			log.WithFields(f).WithError(gerritErr).Warnf("unable to locate gerrits associated with CLA Group ID: %s", claGroupID)
 			return nil, gerritErr
@@ -352,8 +408,13 @@ func (s service) AddUserToGroup(ctx context.Context, authUser *auth.User, claGro
 				CLAType: claType,
 			}
 		}
+		groupSync, groupSyncErr := s.groupSync(gerritModel.GroupSyncBackend, gerritModel.GerritURL.String())
+		if groupSyncErr != nil {
+			log.WithFields(f).WithError(groupSyncErr).Warnf("unable to load the group sync backend of gerrit: %s", gerritModel.GerritName)
+			return groupSyncErr
+		}
 		log.WithFields(f).Debugf("LDAP group name: %s", ldapGroupName)
-		addErr := s.lfGroup.AddUserToGroup(ctx, authUser, claGroupID, ldapGroupName, userName)
+		addErr := groupSync.AddUserToGroup(ctx, authUser, claGroupID, ldapGroupName, userName)
 		if addErr != nil {
 			log.WithFields(f).WithError(addErr).Warnf("unable to add user %s to group: %s for CLA Group: %s", userName, ldapGroupName, claGroupID)
 			return gerritErr
@@ -424,8 +485,13 @@ func (s service) RemoveUserFromGroup(ctx context.Context, authUser *auth.User, c
 				CLAType: claType,
 			}
 		}
+		groupSync, groupSyncErr := s.groupSync(gerritModel.GroupSyncBackend, gerritModel.GerritURL.String())
+		if groupSyncErr != nil {
+			log.WithFields(f).WithError(groupSyncErr).Warnf("unable to load the group sync backend of gerrit: %s", gerritModel.GerritName)
+			return groupSyncErr
+		}
 		log.WithFields(f).Debugf("LDAP group name: %s", ldapGroupName)
-		addErr := s.lfGroup.RemoveUserFromGroup(ctx, authUser, claGroupID, ldapGroupName, userName)
+		addErr := groupSync.RemoveUserFromGroup(ctx, authUser, claGroupID, ldapGroupName, userName)
 		if addErr != nil {
 			log.WithFields(f).WithError(addErr).Warnf("unable to remove user %s from group: %s for CLA Group: %s", userName, ldapGroupName, claGroupID)
 			return gerritErr
@@ -522,7 +588,7 @@ func listGerritRepos(ctx context.Context, gerritHost string) (map[string]GerritR
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
 		"gerritHost":     gerritHost,
 	}
-	client := resty.New()
+	client := resty.New().SetTransport(telemetry.NewHTTPTransport(nil))
 
 	gerritAPIPath, gerritAPIPathErr := getGerritAPIPath(ctx, gerritHost)
 	if gerritAPIPathErr != nil {
@@ -530,6 +596,7 @@ func listGerritRepos(ctx context.Context, gerritHost string) (map[string]GerritR
 	}
 
 	resp, err := client.R().
+		SetContext(ctx).
 		EnableTrace().
 		Get(fmt.Sprintf("https://%s/%s/projects/?d&pp=0", gerritHost, gerritAPIPath))
 	if err != nil {
@@ -562,7 +629,7 @@ func getGerritConfig(ctx context.Context, gerritHost string) (*ServerInfo, error
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
 		"gerritHost":     gerritHost,
 	}
-	client := resty.New()
+	client := resty.New().SetTransport(telemetry.NewHTTPTransport(nil))
 
 	gerritAPIPath, gerritAPIPathErr := getGerritAPIPath(ctx, gerritHost)
 	if gerritAPIPathErr != nil {
@@ -570,6 +637,7 @@ func getGerritConfig(ctx context.Context, gerritHost string) (*ServerInfo, error
 	}
 
 	resp, err := client.R().
+		SetContext(ctx).
 		EnableTrace().
 		Get(fmt.Sprintf("https://%s/%s/config/server/info", gerritHost, gerritAPIPath))
 	if err != nil {
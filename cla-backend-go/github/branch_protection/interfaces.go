@@ -22,6 +22,7 @@ type V4BranchProtectionRepository interface {
 	GetRepositoryBranchProtections(ctx context.Context, repositoryOwner, repositoryName string) (*RepoBranchProtectionQueryResult, error)
 	CreateBranchProtection(ctx context.Context, input *githubv4.CreateBranchProtectionRuleInput) (*CreateRepoBranchProtectionMutation, error)
 	UpdateBranchProtection(ctx context.Context, input *githubv4.UpdateBranchProtectionRuleInput) (*UpdateRepoBranchProtectionMutation, error)
+	DeleteBranchProtection(ctx context.Context, input *githubv4.DeleteBranchProtectionRuleInput) (*DeleteRepoBranchProtectionMutation, error)
 	GetRepositoryIDFromName(ctx context.Context, repositoryOwner, repositoryName string) (string, error)
 }
 
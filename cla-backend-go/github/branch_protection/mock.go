@@ -55,6 +55,21 @@ func (mr *MockCombinedRepositoryMockRecorder) CreateBranchProtection(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBranchProtection", reflect.TypeOf((*MockCombinedRepository)(nil).CreateBranchProtection), arg0, arg1)
 }
 
+// DeleteBranchProtection mocks base method
+func (m *MockCombinedRepository) DeleteBranchProtection(arg0 context.Context, arg1 *githubv4.DeleteBranchProtectionRuleInput) (*DeleteRepoBranchProtectionMutation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBranchProtection", arg0, arg1)
+	ret0, _ := ret[0].(*DeleteRepoBranchProtectionMutation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteBranchProtection indicates an expected call of DeleteBranchProtection
+func (mr *MockCombinedRepositoryMockRecorder) DeleteBranchProtection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBranchProtection", reflect.TypeOf((*MockCombinedRepository)(nil).DeleteBranchProtection), arg0, arg1)
+}
+
 // Get mocks base method
 func (m *MockCombinedRepository) Get(arg0 context.Context, arg1, arg2 string) (*github.Repository, *github.Response, error) {
 	m.ctrl.T.Helper()
@@ -182,6 +182,16 @@ func (bp *BranchProtectionRepository) GetProtectedBranch(ctx context.Context, ow
 	return nil, ErrBranchNotProtected
 }
 
+// GetBranchProtectionRules fetches the branch protection rules of the repository, empty when no branch is protected
+func (bp *BranchProtectionRepository) GetBranchProtectionRules(ctx context.Context, owner, repoName string) ([]BranchProtectionRule, error) {
+	repoName = CleanGithubRepoName(repoName)
+	branchProtections, err := bp.combinedRepo.GetRepositoryBranchProtections(ctx, owner, repoName)
+	if err != nil {
+		return nil, fmt.Errorf("fetching repo protections for owner : %s and repoName : %s failed : %w", owner, repoName, err)
+	}
+	return branchProtections.RepositoryOwner.Repository.BranchProtectionRules.Nodes, nil
+}
+
 //EnableBranchProtection enables branch protection if not enabled and makes sure passed arguments such as enforceAdmin
 //statusChecks are applied. The operation makes sure it doesn't override the existing checks.
 func (bp *BranchProtectionRepository) EnableBranchProtection(ctx context.Context, owner, repoName, branchName string, enforceAdmin bool, enableStatusChecks, disableStatusChecks []string) error {
@@ -196,7 +206,7 @@ func (bp *BranchProtectionRepository) EnableBranchProtection(ctx context.Context
 	currentProtections := queryResult.RepositoryOwner.Repository.BranchProtectionRules.Nodes
 	repoID := queryResult.RepositoryOwner.Repository.ID
 
-	createInput, updateInput := prepareBranchProtectionMutation(repoID, currentProtections, &BranchProtectionRule{
+	return bp.applyBranchProtectionRule(ctx, owner, repoName, repoID, currentProtections, &BranchProtectionRule{
 		Pattern:                     branchName,
 		RequiredStatusCheckContexts: enableStatusChecks,
 		RequiresStatusChecks:        true,
@@ -204,6 +214,11 @@ func (bp *BranchProtectionRepository) EnableBranchProtection(ctx context.Context
 		AllowsDeletions:             false,
 		AllowsForcePushes:           false,
 	})
+}
+
+// applyBranchProtectionRule creates the rule when no current protection has its pattern, updates the current one otherwise
+func (bp *BranchProtectionRepository) applyBranchProtectionRule(ctx context.Context, owner, repoName, repoID string, currentProtections []BranchProtectionRule, rule *BranchProtectionRule) error {
+	createInput, updateInput := prepareBranchProtectionMutation(repoID, currentProtections, rule)
 	if createInput != nil {
 		_, createErr := bp.combinedRepo.CreateBranchProtection(ctx, createInput)
 		if createErr != nil {
@@ -212,7 +227,7 @@ func (bp *BranchProtectionRepository) EnableBranchProtection(ctx context.Context
 		return nil
 	}
 
-	_, err = bp.combinedRepo.UpdateBranchProtection(ctx, updateInput)
+	_, err := bp.combinedRepo.UpdateBranchProtection(ctx, updateInput)
 	if err != nil {
 		return fmt.Errorf("updating current branch rule for owner : %s and repo name : %s, failed : %v", owner, repoName, err)
 	}
@@ -57,6 +57,10 @@ func (b blockingRateLimitRepositories) UpdateBranchProtection(ctx context.Contex
 	blockingRateLimit.Take()
 	return b.CombinedRepository.UpdateBranchProtection(ctx, input)
 }
+func (b blockingRateLimitRepositories) DeleteBranchProtection(ctx context.Context, input *githubv4.DeleteBranchProtectionRuleInput) (*DeleteRepoBranchProtectionMutation, error) {
+	blockingRateLimit.Take()
+	return b.CombinedRepository.DeleteBranchProtection(ctx, input)
+}
 func (b blockingRateLimitRepositories) GetRepositoryIDFromName(ctx context.Context, repositoryOwner, repositoryName string) (string, error) {
 	blockingRateLimit.Take()
 	return b.CombinedRepository.GetRepositoryIDFromName(ctx, repositoryOwner, repositoryName)
@@ -106,6 +110,13 @@ func (nb nonBlockingRateLimitRepositories) UpdateBranchProtection(ctx context.Co
 	return nil, fmt.Errorf("too many requests : %w", github.ErrRateLimited)
 }
 
+func (nb nonBlockingRateLimitRepositories) DeleteBranchProtection(ctx context.Context, input *githubv4.DeleteBranchProtectionRuleInput) (*DeleteRepoBranchProtectionMutation, error) {
+	if nonBlockingRateLimit.Allow() {
+		return nb.CombinedRepository.DeleteBranchProtection(ctx, input)
+	}
+	return nil, fmt.Errorf("too many requests : %w", github.ErrRateLimited)
+}
+
 func (nb nonBlockingRateLimitRepositories) GetRepositoryIDFromName(ctx context.Context, repositoryOwner, repositoryName string) (string, error) {
 	if nonBlockingRateLimit.Allow() {
 		return nb.CombinedRepository.GetRepositoryIDFromName(ctx, repositoryOwner, repositoryName)
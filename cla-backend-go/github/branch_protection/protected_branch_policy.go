@@ -0,0 +1,208 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package branch_protection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// maxBranchProtectionPolicyPatterns limits the number of rules a policy creates on every repository
+const maxBranchProtectionPolicyPatterns = 20
+
+var (
+	// ErrInvalidBranchProtectionPolicy indicates the branch protection policy can't be applied
+	ErrInvalidBranchProtectionPolicy = errors.New("invalid branch protection policy")
+)
+
+// BranchProtectionPolicy describes the branches protected on the repositories of a github organization and the
+// protection applied to them. The EasyCLA status check is always required.
+type BranchProtectionPolicy struct {
+	BranchPatterns       []string
+	RequiredStatusChecks []string
+	EnforceAdmin         bool
+}
+
+// DefaultBranchProtectionPolicy returns the policy of the organizations which didn't define one, all the branches
+// require the EasyCLA check with admins enforced
+func DefaultBranchProtectionPolicy() *BranchProtectionPolicy {
+	return &BranchProtectionPolicy{
+		BranchPatterns:       []string{utils.GithubBranchProtectionPatternAll},
+		RequiredStatusChecks: []string{utils.GitHubBotName},
+		EnforceAdmin:         true,
+	}
+}
+
+// ValidateBranchProtectionPolicy checks the branch patterns and the status checks of the policy
+func ValidateBranchProtectionPolicy(policy *BranchProtectionPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	if len(policy.BranchPatterns) == 0 {
+		return fmt.Errorf("%w: at least one branch pattern is required", ErrInvalidBranchProtectionPolicy)
+	}
+	if len(policy.BranchPatterns) > maxBranchProtectionPolicyPatterns {
+		return fmt.Errorf("%w: at most %d branch patterns are allowed", ErrInvalidBranchProtectionPolicy, maxBranchProtectionPolicyPatterns)
+	}
+
+	patterns := map[string]bool{}
+	for _, pattern := range policy.BranchPatterns {
+		if strings.TrimSpace(pattern) == "" {
+			return fmt.Errorf("%w: empty branch pattern", ErrInvalidBranchProtectionPolicy)
+		}
+		if patterns[pattern] {
+			return fmt.Errorf("%w: duplicate branch pattern %s", ErrInvalidBranchProtectionPolicy, pattern)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%w: malformed branch pattern %s", ErrInvalidBranchProtectionPolicy, pattern)
+		}
+		patterns[pattern] = true
+	}
+
+	for _, check := range policy.RequiredStatusChecks {
+		if strings.TrimSpace(check) == "" {
+			return fmt.Errorf("%w: empty status check", ErrInvalidBranchProtectionPolicy)
+		}
+	}
+	return nil
+}
+
+// StatusCheckContexts returns the status checks required by the policy, the EasyCLA check included
+func (p *BranchProtectionPolicy) StatusCheckContexts() []string {
+	return mergeStatusChecks(p.RequiredStatusChecks, []string{utils.GitHubBotName}, nil)
+}
+
+// DroppedBranchPatterns returns the branch patterns of the previous policy which are not part of the current one
+func DroppedBranchPatterns(previous, current *BranchProtectionPolicy) []string {
+	if previous == nil {
+		return nil
+	}
+	if current == nil {
+		current = DefaultBranchProtectionPolicy()
+	}
+	currentPatterns := map[string]bool{}
+	for _, pattern := range current.BranchPatterns {
+		currentPatterns[pattern] = true
+	}
+	var dropped []string
+	for _, pattern := range previous.BranchPatterns {
+		if !currentPatterns[pattern] {
+			dropped = append(dropped, pattern)
+		}
+	}
+	return dropped
+}
+
+// ApplyBranchProtectionPolicy protects the branches of the repository matching the policy patterns - a rule is
+// created for the patterns not yet protected and the existing rules are updated, preserving their other checks. The
+// created patterns are the patterns whose rule a policy created on the repository, as recorded by the caller. The
+// rules of the dropped patterns are deleted when a policy created them, the EasyCLA check is only removed from the
+// other ones. It returns the patterns whose rule a policy created, to be recorded for the next changes of the policy.
+func (bp *BranchProtectionRepository) ApplyBranchProtectionPolicy(ctx context.Context, owner, repoName string, policy *BranchProtectionPolicy, droppedPatterns, createdPatterns []string) ([]string, error) {
+	if policy == nil {
+		policy = DefaultBranchProtectionPolicy()
+	}
+	if err := ValidateBranchProtectionPolicy(policy); err != nil {
+		return nil, err
+	}
+	repoName = CleanGithubRepoName(repoName)
+
+	// fetch the existing ones once, the rules are matched by pattern
+	queryResult, err := bp.combinedRepo.GetRepositoryBranchProtections(ctx, owner, repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	currentProtections := queryResult.RepositoryOwner.Repository.BranchProtectionRules.Nodes
+	repoID := queryResult.RepositoryOwner.Repository.ID
+	statusChecks := policy.StatusCheckContexts()
+
+	created := map[string]bool{}
+	for _, pattern := range createdPatterns {
+		created[pattern] = true
+	}
+
+	policyPatterns := map[string]bool{}
+	for _, pattern := range policy.BranchPatterns {
+		policyPatterns[pattern] = true
+		if branchProtectionRuleByPattern(currentProtections, pattern) == nil {
+			created[pattern] = true
+		}
+		err = bp.applyBranchProtectionRule(ctx, owner, repoName, repoID, currentProtections, &BranchProtectionRule{
+			Pattern:                     pattern,
+			RequiredStatusCheckContexts: statusChecks,
+			RequiresStatusChecks:        true,
+			IsAdminEnforced:             policy.EnforceAdmin,
+			AllowsDeletions:             false,
+			AllowsForcePushes:           false,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, pattern := range droppedPatterns {
+		if policyPatterns[pattern] {
+			continue
+		}
+		protection := branchProtectionRuleByPattern(currentProtections, pattern)
+		if protection == nil {
+			delete(created, pattern)
+			continue
+		}
+		if !created[pattern] {
+			// the rule existed before the policy, only the EasyCLA check the policy added is removed
+			_, err = bp.combinedRepo.UpdateBranchProtection(ctx, disableStatusChecksMutation(protection, []string{utils.GitHubBotName}))
+			if err != nil {
+				return nil, fmt.Errorf("removing the %s check from the branch protection rule %s for owner : %s and repo : %s failed : %v", utils.GitHubBotName, pattern, owner, repoName, err)
+			}
+			continue
+		}
+		_, err = bp.combinedRepo.DeleteBranchProtection(ctx, &githubv4.DeleteBranchProtectionRuleInput{
+			BranchProtectionRuleID: githubv4.ID(protection.ID),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("deleting branch protection rule %s for owner : %s and repo : %s failed : %v", pattern, owner, repoName, err)
+		}
+		delete(created, pattern)
+	}
+
+	createdResult := make([]string, 0, len(created))
+	for pattern := range created {
+		createdResult = append(createdResult, pattern)
+	}
+	sort.Strings(createdResult)
+	return createdResult, nil
+}
+
+// branchProtectionRuleByPattern returns the rule of the branch pattern, nil when the pattern is not protected
+func branchProtectionRuleByPattern(rules []BranchProtectionRule, pattern string) *BranchProtectionRule {
+	for i := range rules {
+		if rules[i].Pattern == pattern {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// disableStatusChecksMutation creates the mutation input removing the status checks from the rule, the other
+// settings of the rule are left as they are
+func disableStatusChecksMutation(protection *BranchProtectionRule, disableContexts []string) *githubv4.UpdateBranchProtectionRuleInput {
+	statusChecks := []githubv4.String{}
+	for _, check := range mergeStatusChecks(protection.RequiredStatusCheckContexts, nil, disableContexts) {
+		statusChecks = append(statusChecks, githubv4.String(check))
+	}
+	return &githubv4.UpdateBranchProtectionRuleInput{
+		BranchProtectionRuleID:      githubv4.ID(protection.ID),
+		RequiredStatusCheckContexts: &statusChecks,
+	}
+}
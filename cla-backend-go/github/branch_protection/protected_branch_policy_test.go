@@ -0,0 +1,210 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package branch_protection
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bmizerany/assert"
+	"github.com/golang/mock/gomock"
+	"github.com/shurcooL/githubv4"
+)
+
+func TestValidateBranchProtectionPolicy(t *testing.T) {
+	testCases := []struct {
+		Name   string
+		Policy *BranchProtectionPolicy
+		Valid  bool
+	}{
+		{
+			Name:  "nil policy",
+			Valid: true,
+		},
+		{
+			Name:   "default policy",
+			Policy: DefaultBranchProtectionPolicy(),
+			Valid:  true,
+		},
+		{
+			Name:   "release branches",
+			Policy: &BranchProtectionPolicy{BranchPatterns: []string{"main", "release/*"}, RequiredStatusChecks: []string{"ci/circleci"}},
+			Valid:  true,
+		},
+		{
+			Name:   "no branch pattern",
+			Policy: &BranchProtectionPolicy{RequiredStatusChecks: []string{"EasyCLA"}},
+		},
+		{
+			Name:   "empty branch pattern",
+			Policy: &BranchProtectionPolicy{BranchPatterns: []string{"main", " "}},
+		},
+		{
+			Name:   "duplicate branch pattern",
+			Policy: &BranchProtectionPolicy{BranchPatterns: []string{"main", "main"}},
+		},
+		{
+			Name:   "malformed branch pattern",
+			Policy: &BranchProtectionPolicy{BranchPatterns: []string{"release/["}},
+		},
+		{
+			Name:   "empty status check",
+			Policy: &BranchProtectionPolicy{BranchPatterns: []string{"main"}, RequiredStatusChecks: []string{""}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(tt *testing.T) {
+			err := ValidateBranchProtectionPolicy(tc.Policy)
+			assert.Equal(tt, tc.Valid, err == nil)
+			if !tc.Valid {
+				assert.Equal(tt, true, errors.Is(err, ErrInvalidBranchProtectionPolicy))
+			}
+		})
+	}
+}
+
+func TestApplyBranchProtectionPolicy(t *testing.T) {
+	owner := "johnpolicy"
+	repo := "johnsrepopolicy"
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := NewMockCombinedRepository(ctrl)
+	m.
+		EXPECT().
+		GetRepositoryBranchProtections(gomock.Any(), owner, repo).
+		Return(&RepoBranchProtectionQueryResult{
+			RepositoryOwner: struct {
+				Repository BranchProtectionRuleRepositoryParam `graphql:"repository(name: $name)"`
+			}{Repository: BranchProtectionRuleRepositoryParam{
+				Name: "repoNameValue",
+				ID:   "repoIDValue",
+				BranchProtectionRules: BranchProtectionRuleQueryParam{
+					TotalCount: 1,
+					Nodes: []BranchProtectionRule{
+						{
+							ID:                          "mainProtectionID",
+							Pattern:                     "main",
+							RequiredStatusCheckContexts: []string{"circle/ci"},
+							AllowsForcePushes:           true,
+						},
+					},
+				},
+			}},
+		}, nil)
+
+	// the existing rule keeps its checks, the release branches get a new rule, both require EasyCLA
+	m.
+		EXPECT().
+		UpdateBranchProtection(gomock.Any(), &githubv4.UpdateBranchProtectionRuleInput{
+			BranchProtectionRuleID:      githubv4.ID("mainProtectionID"),
+			Pattern:                     githubv4.NewString("main"),
+			AllowsForcePushes:           githubv4.NewBoolean(false),
+			AllowsDeletions:             githubv4.NewBoolean(false),
+			IsAdminEnforced:             githubv4.NewBoolean(false),
+			RequiresStatusChecks:        githubv4.NewBoolean(true),
+			RequiredStatusCheckContexts: V4StringSlice("circle/ci", "DCO", "EasyCLA"),
+		}).
+		Return(nil, nil)
+	m.
+		EXPECT().
+		CreateBranchProtection(gomock.Any(), &githubv4.CreateBranchProtectionRuleInput{
+			RepositoryID:                githubv4.ID("repoIDValue"),
+			Pattern:                     githubv4.String("release/*"),
+			AllowsForcePushes:           githubv4.NewBoolean(false),
+			AllowsDeletions:             githubv4.NewBoolean(false),
+			IsAdminEnforced:             githubv4.NewBoolean(false),
+			RequiresStatusChecks:        githubv4.NewBoolean(true),
+			RequiredStatusCheckContexts: V4StringSlice("DCO", "EasyCLA"),
+		}).
+		Return(nil, nil)
+
+	branchProtectionRepo := newBranchProtectionRepository(m)
+	created, err := branchProtectionRepo.ApplyBranchProtectionPolicy(context.Background(), owner, "org/"+repo, &BranchProtectionPolicy{
+		BranchPatterns:       []string{"main", "release/*"},
+		RequiredStatusChecks: []string{"DCO"},
+		EnforceAdmin:         false,
+	}, nil, nil)
+	if err != nil {
+		t.Errorf("apply branch protection policy failed : %v", err)
+	}
+	// only the rule of the release branches is created by the policy
+	assert.Equal(t, []string{"release/*"}, created)
+}
+
+func TestApplyBranchProtectionPolicyInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// an invalid policy doesn't reach github
+	m := NewMockCombinedRepository(ctrl)
+	branchProtectionRepo := newBranchProtectionRepository(m)
+	_, err := branchProtectionRepo.ApplyBranchProtectionPolicy(context.Background(), "johnpolicy", "johnsrepopolicy", &BranchProtectionPolicy{}, nil, nil)
+	assert.Equal(t, true, errors.Is(err, ErrInvalidBranchProtectionPolicy))
+}
+
+func TestApplyBranchProtectionPolicyDroppedPatterns(t *testing.T) {
+	owner := "johnpolicy"
+	repo := "johnsrepopolicy"
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := NewMockCombinedRepository(ctrl)
+	m.
+		EXPECT().
+		GetRepositoryBranchProtections(gomock.Any(), owner, repo).
+		Return(&RepoBranchProtectionQueryResult{
+			RepositoryOwner: struct {
+				Repository BranchProtectionRuleRepositoryParam `graphql:"repository(name: $name)"`
+			}{Repository: BranchProtectionRuleRepositoryParam{
+				Name: "repoNameValue",
+				ID:   "repoIDValue",
+				BranchProtectionRules: BranchProtectionRuleQueryParam{
+					TotalCount: 4,
+					Nodes: []BranchProtectionRule{
+						{ID: "mainProtectionID", Pattern: "main", RequiredStatusCheckContexts: []string{"EasyCLA"}, RequiresStatusChecks: true},
+						{ID: "releaseProtectionID", Pattern: "release/*", RequiredStatusCheckContexts: []string{"EasyCLA"}, RequiresStatusChecks: true},
+						{ID: "hotfixProtectionID", Pattern: "hotfix/*", RequiredStatusCheckContexts: []string{"ci", "EasyCLA"}, RequiresStatusChecks: true, IsAdminEnforced: true},
+						{ID: "docsProtectionID", Pattern: "docs/*", RequiredStatusCheckContexts: []string{"ci"}, RequiresStatusChecks: true},
+					},
+				},
+			}},
+		}, nil)
+
+	// the main rule is kept and the rule the policy created for the dropped release pattern is deleted - the hotfix
+	// rule existed before the policy, it only loses the EasyCLA check, and the rule of a pattern the policy never
+	// had is left alone
+	m.
+		EXPECT().
+		UpdateBranchProtection(gomock.Any(), gomock.Any()).
+		Return(nil, nil)
+	m.
+		EXPECT().
+		DeleteBranchProtection(gomock.Any(), &githubv4.DeleteBranchProtectionRuleInput{
+			BranchProtectionRuleID: githubv4.ID("releaseProtectionID"),
+		}).
+		Return(nil, nil)
+	m.
+		EXPECT().
+		UpdateBranchProtection(gomock.Any(), &githubv4.UpdateBranchProtectionRuleInput{
+			BranchProtectionRuleID:      githubv4.ID("hotfixProtectionID"),
+			RequiredStatusCheckContexts: V4StringSlice("ci"),
+		}).
+		Return(nil, nil)
+
+	previous := &BranchProtectionPolicy{BranchPatterns: []string{"main", "release/*", "hotfix/*"}}
+	current := &BranchProtectionPolicy{BranchPatterns: []string{"main"}}
+	assert.Equal(t, []string{"release/*", "hotfix/*"}, DroppedBranchPatterns(previous, current))
+
+	branchProtectionRepo := newBranchProtectionRepository(m)
+	created, err := branchProtectionRepo.ApplyBranchProtectionPolicy(context.Background(), owner, repo, current, DroppedBranchPatterns(previous, current), []string{"main", "release/*"})
+	if err != nil {
+		t.Errorf("apply branch protection policy failed : %v", err)
+	}
+	assert.Equal(t, []string{"main"}, created)
+}
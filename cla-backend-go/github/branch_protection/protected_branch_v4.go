@@ -76,6 +76,13 @@ type UpdateRepoBranchProtectionMutation struct {
 	} `graphql:"updateBranchProtectionRule(input: $input)"`
 }
 
+// DeleteRepoBranchProtectionMutation deletes a branch protection rule
+type DeleteRepoBranchProtectionMutation struct {
+	DeleteBranchProtectionRule struct {
+		ClientMutationID string
+	} `graphql:"deleteBranchProtectionRule(input: $input)"`
+}
+
 // BranchProtectionRepositoryV4 wraps a v4 github client
 type BranchProtectionRepositoryV4 struct {
 	client *githubv4.Client
@@ -129,6 +136,16 @@ func (r *BranchProtectionRepositoryV4) UpdateBranchProtection(ctx context.Contex
 	return &updateMutationResult, nil
 }
 
+// DeleteBranchProtection deletes the branch protection rule
+func (r *BranchProtectionRepositoryV4) DeleteBranchProtection(ctx context.Context, input *githubv4.DeleteBranchProtectionRuleInput) (*DeleteRepoBranchProtectionMutation, error) {
+	var deleteMutationResult DeleteRepoBranchProtectionMutation
+	err := r.client.Mutate(ctx, &deleteMutationResult, *input, nil)
+	if err != nil {
+		return nil, fmt.Errorf("deleting branch protection rule failed : %w", err)
+	}
+	return &deleteMutationResult, nil
+}
+
 // GetRepositoryIDFromName when provided the organization and repository name, returns the repository ID
 func (r *BranchProtectionRepositoryV4) GetRepositoryIDFromName(ctx context.Context, repositoryOwner, repositoryName string) (string, error) {
 
@@ -12,6 +12,8 @@ import (
 
 	"github.com/shurcooL/githubv4"
 
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
+
 	"github.com/bradleyfalzon/ghinstallation"
 	"github.com/google/go-github/v33/github"
 	"golang.org/x/oauth2"
@@ -74,7 +76,7 @@ func CheckAndWrapForKnownErrors(resp *github.Response, err error) (bool, error)
 
 // NewGithubAppClient creates a new github client from the supplied installationID
 func NewGithubAppClient(installationID int64) (*github.Client, error) {
-	itr, err := ghinstallation.New(http.DefaultTransport, int64(getGithubAppID()), installationID, []byte(getGithubAppPrivateKey()))
+	itr, err := ghinstallation.New(telemetry.NewGitHubTransport(telemetry.NewHTTPTransport(http.DefaultTransport)), int64(getGithubAppID()), installationID, []byte(getGithubAppPrivateKey()))
 	if err != nil {
 		return nil, err
 	}
@@ -83,7 +85,7 @@ func NewGithubAppClient(installationID int64) (*github.Client, error) {
 
 // NewGithubV4AppClient creates a new github v4 client from the supplied installationID
 func NewGithubV4AppClient(installationID int64) (*githubv4.Client, error) {
-	authTransport, err := ghinstallation.New(http.DefaultTransport, int64(getGithubAppID()), installationID, []byte(getGithubAppPrivateKey()))
+	authTransport, err := ghinstallation.New(telemetry.NewGitHubTransport(telemetry.NewHTTPTransport(http.DefaultTransport)), int64(getGithubAppID()), installationID, []byte(getGithubAppPrivateKey()))
 	if err != nil {
 		return nil, err
 	}
@@ -97,10 +99,32 @@ func NewGithubOauthClient() *github.Client {
 
 // NewGithubOauthClientWithAccessToken creates github client from specified accessToken
 func NewGithubOauthClientWithAccessToken(accessToken string) *github.Client {
-	ctx := context.TODO()
+	// the oauth2 client wraps the transport of the client set on the context
+	ctx := context.WithValue(context.TODO(), oauth2.HTTPClient, &http.Client{Transport: telemetry.NewGitHubTransport(telemetry.NewHTTPTransport(http.DefaultTransport))})
 	ts := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: accessToken},
 	)
 	tc := oauth2.NewClient(ctx, ts)
 	return github.NewClient(tc)
 }
+
+// CheckAppInstallationToken creates an installation access token of the GitHub App for one of its installations,
+// used by the readiness check to verify the App ID and private key
+func CheckAppInstallationToken(ctx context.Context) error {
+	appTransport, err := ghinstallation.NewAppsTransport(telemetry.NewGitHubTransport(telemetry.NewHTTPTransport(http.DefaultTransport)), int64(getGithubAppID()), []byte(getGithubAppPrivateKey()))
+	if err != nil {
+		return err
+	}
+	client := github.NewClient(&http.Client{Transport: appTransport})
+
+	installations, _, err := client.Apps.ListInstallations(ctx, &github.ListOptions{PerPage: 1})
+	if err != nil {
+		return err
+	}
+	if len(installations) == 0 {
+		return errors.New("the GitHub App has no installations")
+	}
+
+	_, _, err = client.Apps.CreateInstallationToken(ctx, installations[0].GetID(), nil)
+	return err
+}
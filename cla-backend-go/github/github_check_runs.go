@@ -0,0 +1,74 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package github
+
+import (
+	"context"
+
+	"github.com/google/go-github/v33/github"
+)
+
+// ChecksClient is the GitHub API behavior needed to report the CLA status as check runs
+type ChecksClient interface {
+	ListPullRequestCommits(ctx context.Context, owner, repo string, number int) ([]*github.RepositoryCommit, error)
+	CompareCommits(ctx context.Context, owner, repo, base, head string) ([]*github.RepositoryCommit, error)
+	CreateCheckRun(ctx context.Context, owner, repo string, opts github.CreateCheckRunOptions) (*github.CheckRun, error)
+}
+
+type checksClient struct {
+	client *github.Client
+}
+
+// NewChecksClient creates the checks client of the GitHub app installation
+func NewChecksClient(installationID int64) (ChecksClient, error) {
+	client, err := NewGithubAppClient(installationID)
+	if err != nil {
+		return nil, err
+	}
+	return &checksClient{client: client}, nil
+}
+
+// ListPullRequestCommits returns the commits of the pull request - GitHub lists at most 250 commits
+func (c *checksClient) ListPullRequestCommits(ctx context.Context, owner, repo string, number int) ([]*github.RepositoryCommit, error) {
+	var commits []*github.RepositoryCommit
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := c.client.PullRequests.ListCommits(ctx, owner, repo, number, opts)
+		if err != nil {
+			if ok, wErr := CheckAndWrapForKnownErrors(resp, err); ok {
+				return nil, wErr
+			}
+			return nil, err
+		}
+		commits = append(commits, page...)
+		if resp.NextPage == 0 {
+			return commits, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// CompareCommits returns the commits reachable from head and not from base
+func (c *checksClient) CompareCommits(ctx context.Context, owner, repo, base, head string) ([]*github.RepositoryCommit, error) {
+	comparison, resp, err := c.client.Repositories.CompareCommits(ctx, owner, repo, base, head)
+	if err != nil {
+		if ok, wErr := CheckAndWrapForKnownErrors(resp, err); ok {
+			return nil, wErr
+		}
+		return nil, err
+	}
+	return comparison.Commits, nil
+}
+
+// CreateCheckRun creates a check run on the commit
+func (c *checksClient) CreateCheckRun(ctx context.Context, owner, repo string, opts github.CreateCheckRunOptions) (*github.CheckRun, error) {
+	checkRun, resp, err := c.client.Checks.CreateCheckRun(ctx, owner, repo, opts)
+	if err != nil {
+		if ok, wErr := CheckAndWrapForKnownErrors(resp, err); ok {
+			return nil, wErr
+		}
+		return nil, err
+	}
+	return checkRun, nil
+}
@@ -0,0 +1,339 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package github_organizations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	"github.com/communitybridge/easycla/cla-backend-go/github/branch_protection"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// the branch protection issues reported by the audit
+const (
+	BranchProtectionIssueMissingRule             = "missing_rule"
+	BranchProtectionIssueStatusChecksNotRequired = "status_checks_not_required"
+	BranchProtectionIssueMissingStatusCheck      = "missing_status_check"
+	BranchProtectionIssueEnforceAdminMismatch    = "enforce_admin_mismatch"
+	BranchProtectionIssueAllowsForcePushes       = "allows_force_pushes"
+	BranchProtectionIssueAllowsDeletions         = "allows_deletions"
+)
+
+// ErrBranchProtectionNotEnabled is returned when auditing an organization without branch protection enabled
+var ErrBranchProtectionNotEnabled = errors.New("github organization branch protection is not enabled")
+
+// branchProtectionAuditWorkers is the number of repositories audited concurrently within an organization
+const branchProtectionAuditWorkers = 5
+
+// BranchProtectionClient is the branch protection behavior needed to audit and remediate the repositories,
+// implemented by the branch protection repository
+type BranchProtectionClient interface {
+	GetBranchProtectionRules(ctx context.Context, owner, repoName string) ([]branch_protection.BranchProtectionRule, error)
+	ApplyBranchProtectionPolicy(ctx context.Context, owner, repoName string, policy *branch_protection.BranchProtectionPolicy, droppedPatterns, createdPatterns []string) ([]string, error)
+}
+
+// BranchProtectionClientFactory creates the branch protection client of a GitHub app installation
+type BranchProtectionClientFactory func(installationID int64, opts ...branch_protection.BranchProtectionRepositoryOption) (BranchProtectionClient, error)
+
+// NewBranchProtectionClient creates the branch protection client of the GitHub app installation
+func NewBranchProtectionClient(installationID int64, opts ...branch_protection.BranchProtectionRepositoryOption) (BranchProtectionClient, error) {
+	branchProtectionRepo, err := branch_protection.NewBranchProtectionRepository(installationID, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return branchProtectionRepo, nil
+}
+
+// BranchProtectionAuditRepository is the github organization behavior needed to run and store the audits
+type BranchProtectionAuditRepository interface {
+	GetBranchProtectionEnabledOrganizations(ctx context.Context) ([]*models.GithubOrganization, error)
+	UpdateBranchProtectionAudit(ctx context.Context, githubOrganizationName string, audit *BranchProtectionAudit) error
+}
+
+// OrganizationRepositoryLister is the repository behavior needed to load the repositories of the audited organizations
+// and to record the rules the remediations create
+type OrganizationRepositoryLister interface {
+	GetRepositoriesByOrganizationName(ctx context.Context, gitHubOrgName string) ([]*models.GithubRepository, error)
+	GetBranchProtectionPatterns(ctx context.Context, repositoryID string) ([]string, error)
+	UpdateBranchProtectionPatterns(ctx context.Context, repositoryID string, patterns []string) error
+}
+
+// BranchProtectionAuditSummary summarizes a branch protection audit run
+type BranchProtectionAuditSummary struct {
+	Remediate              bool `json:"remediate"`
+	OrganizationsAudited   int  `json:"organizations_audited"`
+	RepositoriesAudited    int  `json:"repositories_audited"`
+	RepositoriesDrifted    int  `json:"repositories_drifted"`
+	RepositoriesRemediated int  `json:"repositories_remediated"`
+	Errors                 int  `json:"errors"`
+}
+
+// BranchProtectionAuditor finds the enabled repositories of the branch protection enabled organizations whose rules
+// fall short of the branch protection policy of the organization, and optionally restores the protection
+type BranchProtectionAuditor interface {
+	AuditAll(ctx context.Context, remediate bool) (*BranchProtectionAuditSummary, error)
+	AuditOrganization(ctx context.Context, githubOrg *models.GithubOrganization, remediate bool) (*BranchProtectionAudit, error)
+}
+
+type branchProtectionAuditor struct {
+	repo             BranchProtectionAuditRepository
+	repositoryLister OrganizationRepositoryLister
+	clientFactory    BranchProtectionClientFactory
+}
+
+// NewBranchProtectionAuditor creates a new branch protection auditor
+func NewBranchProtectionAuditor(repo BranchProtectionAuditRepository, repositoryLister OrganizationRepositoryLister, clientFactory BranchProtectionClientFactory) BranchProtectionAuditor {
+	return &branchProtectionAuditor{
+		repo:             repo,
+		repositoryLister: repositoryLister,
+		clientFactory:    clientFactory,
+	}
+}
+
+// AuditAll audits the organizations with branch protection enabled, a failing organization doesn't stop the run
+func (a *branchProtectionAuditor) AuditAll(ctx context.Context, remediate bool) (*BranchProtectionAuditSummary, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.github_organizations.branch_protection_audit.AuditAll",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"remediate":      remediate,
+	}
+
+	githubOrgs, err := a.repo.GetBranchProtectionEnabledOrganizations(ctx)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the branch protection enabled github organizations")
+		return nil, err
+	}
+	sort.Slice(githubOrgs, func(i, j int) bool {
+		return githubOrgs[i].OrganizationName < githubOrgs[j].OrganizationName
+	})
+
+	summary := &BranchProtectionAuditSummary{Remediate: remediate}
+	for _, githubOrg := range githubOrgs {
+		audit, auditErr := a.AuditOrganization(ctx, githubOrg, remediate)
+		if auditErr != nil {
+			log.WithFields(f).WithError(auditErr).Warnf("unable to audit the branch protection of github organization: %s", githubOrg.OrganizationName)
+			summary.Errors++
+			continue
+		}
+		summary.OrganizationsAudited++
+		summary.RepositoriesAudited += int(audit.RepositoriesAudited)
+		summary.RepositoriesDrifted += int(audit.RepositoriesDrifted)
+		summary.RepositoriesRemediated += int(audit.RepositoriesRemediated)
+		summary.Errors += int(audit.Errors)
+	}
+
+	return summary, nil
+}
+
+// AuditOrganization audits the protection of the policy branch patterns on the enabled repositories of the
+// organization and stores the report on the organization - in remediate mode the policy is applied again on the
+// drifted repositories through the blocking rate limiter
+func (a *branchProtectionAuditor) AuditOrganization(ctx context.Context, githubOrg *models.GithubOrganization, remediate bool) (*BranchProtectionAudit, error) {
+	f := logrus.Fields{
+		"functionName":     "v1.github_organizations.branch_protection_audit.AuditOrganization",
+		utils.XREQUESTID:   ctx.Value(utils.XREQUESTID),
+		"organizationName": githubOrg.OrganizationName,
+		"remediate":        remediate,
+	}
+
+	if !githubOrg.BranchProtectionEnabled {
+		return nil, ErrBranchProtectionNotEnabled
+	}
+
+	repositories, err := a.repositoryLister.GetRepositoriesByOrganizationName(ctx, githubOrg.OrganizationName)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the repositories of the github organization")
+		return nil, err
+	}
+	policy := ToBranchProtectionPolicy(githubOrg.BranchProtectionPolicy)
+	f["branchPatterns"] = policy.BranchPatterns
+
+	var enabledRepositories []*models.GithubRepository
+	for _, repository := range repositories {
+		if repository.Enabled && (repository.RepositoryType == "" || repository.RepositoryType == utils.GitHubType) {
+			enabledRepositories = append(enabledRepositories, repository)
+		}
+	}
+
+	// the audit only reads - it fails fast when rate limited, the remediation waits for the limiter
+	limiter := branch_protection.EnableNonBlockingLimiter()
+	if remediate {
+		limiter = branch_protection.EnableBlockingLimiter()
+	}
+	client, err := a.clientFactory(githubOrg.OrganizationInstallationID, limiter)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to create the branch protection client of the github organization")
+		return nil, err
+	}
+
+	log.WithFields(f).Debugf("auditing the branch protection of %d repositories...", len(enabledRepositories))
+	drifts := make([]*BranchProtectionDrift, len(enabledRepositories))
+	var wg sync.WaitGroup
+	workerTokens := make(chan struct{}, branchProtectionAuditWorkers)
+	for i, repository := range enabledRepositories {
+		i, repository := i, repository
+		workerTokens <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-workerTokens
+				wg.Done()
+			}()
+			drifts[i] = a.auditRepository(ctx, client, githubOrg.OrganizationName, repository, policy, remediate)
+		}()
+	}
+	wg.Wait()
+
+	_, currentTime := utils.CurrentTime()
+	audit := &BranchProtectionAudit{
+		OrganizationName:    githubOrg.OrganizationName,
+		ProjectSFID:         githubOrg.ProjectSFID,
+		DateAudited:         currentTime,
+		Remediate:           remediate,
+		RepositoriesAudited: int64(len(enabledRepositories)),
+		Drifts:              []*BranchProtectionDrift{},
+	}
+	for _, drift := range drifts {
+		if drift == nil {
+			continue
+		}
+		audit.Drifts = append(audit.Drifts, drift)
+		if len(drift.Rules) > 0 {
+			audit.RepositoriesDrifted++
+		}
+		if drift.Remediated {
+			audit.RepositoriesRemediated++
+		}
+		if drift.Error != "" {
+			audit.Errors++
+		}
+	}
+
+	err = a.repo.UpdateBranchProtectionAudit(ctx, githubOrg.OrganizationName, audit)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to store the branch protection audit of the github organization")
+		return nil, err
+	}
+
+	log.WithFields(f).Debugf("audited %d repositories - %d drifted, %d remediated, %d errors",
+		audit.RepositoriesAudited, audit.RepositoriesDrifted, audit.RepositoriesRemediated, audit.Errors)
+	return audit, nil
+}
+
+// auditRepository checks the rules of the policy branch patterns, nil when the repository is protected as expected.
+// The drift records the error when the repository could not be audited or remediated.
+func (a *branchProtectionAuditor) auditRepository(ctx context.Context, client BranchProtectionClient, orgName string, repository *models.GithubRepository, policy *branch_protection.BranchProtectionPolicy, remediate bool) *BranchProtectionDrift {
+	f := logrus.Fields{
+		"functionName":     "v1.github_organizations.branch_protection_audit.auditRepository",
+		utils.XREQUESTID:   ctx.Value(utils.XREQUESTID),
+		"organizationName": orgName,
+		"repositoryName":   repository.RepositoryName,
+	}
+
+	repoName := branch_protection.CleanGithubRepoName(repository.RepositoryName)
+	drift := &BranchProtectionDrift{
+		RepositoryID:   repository.RepositoryID,
+		RepositoryName: repository.RepositoryName,
+		ClaGroupID:     repository.RepositoryProjectID,
+		Rules:          []*BranchProtectionRuleDrift{},
+	}
+
+	rules, err := client.GetBranchProtectionRules(ctx, orgName, repoName)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the branch protection rules of the repository")
+		drift.Error = fmt.Sprintf("unable to load the branch protection rules: %v", err)
+		return drift
+	}
+
+	for _, pattern := range policy.BranchPatterns {
+		issues := branchProtectionIssues(branchProtectionRuleByPattern(rules, pattern), policy)
+		if len(issues) > 0 {
+			drift.Rules = append(drift.Rules, &BranchProtectionRuleDrift{
+				Pattern: pattern,
+				Issues:  issues,
+			})
+		}
+	}
+	if len(drift.Rules) == 0 {
+		return nil
+	}
+	log.WithFields(f).Debugf("branch protection drifted on %d patterns", len(drift.Rules))
+	if !remediate {
+		return drift
+	}
+
+	err = a.remediateRepository(ctx, client, orgName, repoName, repository.RepositoryID, policy)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to remediate the branch protection of the repository")
+		drift.Error = fmt.Sprintf("unable to remediate the branch protection: %v", err)
+		return drift
+	}
+	drift.Remediated = true
+	return drift
+}
+
+// remediateRepository updates the drifted rules in place and creates the missing ones, the patterns whose rule the
+// policy created are recorded on the repository
+func (a *branchProtectionAuditor) remediateRepository(ctx context.Context, client BranchProtectionClient, orgName, repoName, repositoryID string, policy *branch_protection.BranchProtectionPolicy) error {
+	createdPatterns, err := a.repositoryLister.GetBranchProtectionPatterns(ctx, repositoryID)
+	if err != nil {
+		return err
+	}
+	createdPatterns, err = client.ApplyBranchProtectionPolicy(ctx, orgName, repoName, policy, nil, createdPatterns)
+	if err != nil {
+		return err
+	}
+	return a.repositoryLister.UpdateBranchProtectionPatterns(ctx, repositoryID, createdPatterns)
+}
+
+// branchProtectionRuleByPattern returns the rule of the branch pattern, nil when the pattern is not protected
+func branchProtectionRuleByPattern(rules []branch_protection.BranchProtectionRule, pattern string) *branch_protection.BranchProtectionRule {
+	for i := range rules {
+		if rules[i].Pattern == pattern {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// branchProtectionIssues returns the ways the rule falls short of the branch protection policy, nil rule meaning the
+// pattern is not protected
+func branchProtectionIssues(rule *branch_protection.BranchProtectionRule, policy *branch_protection.BranchProtectionPolicy) []string {
+	if rule == nil {
+		return []string{BranchProtectionIssueMissingRule}
+	}
+
+	issues := []string{}
+	if !rule.RequiresStatusChecks {
+		issues = append(issues, BranchProtectionIssueStatusChecksNotRequired)
+	}
+	currentChecks := map[string]bool{}
+	for _, check := range rule.RequiredStatusCheckContexts {
+		currentChecks[check] = true
+	}
+	for _, check := range policy.StatusCheckContexts() {
+		if !currentChecks[check] {
+			issues = append(issues, BranchProtectionIssueMissingStatusCheck)
+			break
+		}
+	}
+	if rule.IsAdminEnforced != policy.EnforceAdmin {
+		issues = append(issues, BranchProtectionIssueEnforceAdminMismatch)
+	}
+	if rule.AllowsForcePushes {
+		issues = append(issues, BranchProtectionIssueAllowsForcePushes)
+	}
+	if rule.AllowsDeletions {
+		issues = append(issues, BranchProtectionIssueAllowsDeletions)
+	}
+	return issues
+}
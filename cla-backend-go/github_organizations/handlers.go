@@ -155,7 +155,7 @@ func Configure(api *operations.ClaAPI, service ServiceInterface, eventService ev
 				})
 			}
 
-			err := service.UpdateGithubOrganization(ctx, params.ProjectSFID, params.OrgName, *params.Body.AutoEnabled, params.Body.AutoEnabledClaGroupID, params.Body.BranchProtectionEnabled)
+			err := service.UpdateGithubOrganization(ctx, params.ProjectSFID, params.OrgName, *params.Body.AutoEnabled, params.Body.AutoEnabledClaGroupID, params.Body.BranchProtectionEnabled, params.Body.BranchProtectionPolicy)
 			if err != nil {
 				if errors.Is(err, projects_cla_groups.ErrCLAGroupDoesNotExist) {
 					return github_organizations.NewUpdateProjectGithubOrganizationConfigNotFound().WithPayload(errorResponse(err))
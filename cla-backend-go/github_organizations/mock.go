@@ -82,6 +82,36 @@ func (mr *MockRepositoryMockRecorder) DeleteGithubOrganizationByParent(arg0, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGithubOrganizationByParent", reflect.TypeOf((*MockRepository)(nil).DeleteGithubOrganizationByParent), arg0, arg1, arg2)
 }
 
+// GetBranchProtectionAudit mocks base method
+func (m *MockRepository) GetBranchProtectionAudit(arg0 context.Context, arg1 string) (*BranchProtectionAudit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBranchProtectionAudit", arg0, arg1)
+	ret0, _ := ret[0].(*BranchProtectionAudit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBranchProtectionAudit indicates an expected call of GetBranchProtectionAudit
+func (mr *MockRepositoryMockRecorder) GetBranchProtectionAudit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBranchProtectionAudit", reflect.TypeOf((*MockRepository)(nil).GetBranchProtectionAudit), arg0, arg1)
+}
+
+// GetBranchProtectionEnabledOrganizations mocks base method
+func (m *MockRepository) GetBranchProtectionEnabledOrganizations(arg0 context.Context) ([]*models.GithubOrganization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBranchProtectionEnabledOrganizations", arg0)
+	ret0, _ := ret[0].([]*models.GithubOrganization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBranchProtectionEnabledOrganizations indicates an expected call of GetBranchProtectionEnabledOrganizations
+func (mr *MockRepositoryMockRecorder) GetBranchProtectionEnabledOrganizations(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBranchProtectionEnabledOrganizations", reflect.TypeOf((*MockRepository)(nil).GetBranchProtectionEnabledOrganizations), arg0)
+}
+
 // GetGithubOrganization mocks base method
 func (m *MockRepository) GetGithubOrganization(arg0 context.Context, arg1 string) (*models.GithubOrganization, error) {
 	m.ctrl.T.Helper()
@@ -142,16 +172,30 @@ func (mr *MockRepositoryMockRecorder) GetGithubOrganizationsByParent(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGithubOrganizationsByParent", reflect.TypeOf((*MockRepository)(nil).GetGithubOrganizationsByParent), arg0, arg1)
 }
 
+// UpdateBranchProtectionAudit mocks base method
+func (m *MockRepository) UpdateBranchProtectionAudit(arg0 context.Context, arg1 string, arg2 *BranchProtectionAudit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBranchProtectionAudit", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBranchProtectionAudit indicates an expected call of UpdateBranchProtectionAudit
+func (mr *MockRepositoryMockRecorder) UpdateBranchProtectionAudit(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBranchProtectionAudit", reflect.TypeOf((*MockRepository)(nil).UpdateBranchProtectionAudit), arg0, arg1, arg2)
+}
+
 // UpdateGithubOrganization mocks base method
-func (m *MockRepository) UpdateGithubOrganization(arg0 context.Context, arg1, arg2 string, arg3 bool, arg4 string, arg5 bool, arg6 *bool) error {
+func (m *MockRepository) UpdateGithubOrganization(arg0 context.Context, arg1, arg2 string, arg3 bool, arg4 string, arg5 bool, arg6 *models.GithubBranchProtectionPolicy, arg7 *bool) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateGithubOrganization", arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	ret := m.ctrl.Call(m, "UpdateGithubOrganization", arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateGithubOrganization indicates an expected call of UpdateGithubOrganization
-func (mr *MockRepositoryMockRecorder) UpdateGithubOrganization(arg0, arg1, arg2, arg3, arg4, arg5, arg6 interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) UpdateGithubOrganization(arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGithubOrganization", reflect.TypeOf((*MockRepository)(nil).UpdateGithubOrganization), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGithubOrganization", reflect.TypeOf((*MockRepository)(nil).UpdateGithubOrganization), arg0, arg1, arg2, arg3, arg4, arg5, arg6, arg7)
 }
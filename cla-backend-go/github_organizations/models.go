@@ -3,7 +3,10 @@
 
 package github_organizations
 
-import "github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+import (
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	"github.com/communitybridge/easycla/cla-backend-go/github/branch_protection"
+)
 
 // GithubOrganization is data model for github organizations
 type GithubOrganization struct {
@@ -19,6 +22,15 @@ type GithubOrganization struct {
 	BranchProtectionEnabled    bool   `json:"branch_protection_enabled"`
 	AutoEnabledClaGroupID      string `json:"auto_enabled_cla_group_id,omitempty"`
 	Version                    string `json:"version,omitempty"`
+	// BranchProtectionPolicy is nil when the organization uses the default policy
+	BranchProtectionPolicy *BranchProtectionPolicy `json:"branch_protection_policy,omitempty"`
+}
+
+// BranchProtectionPolicy is the data model of the branch protection policy of a github organization
+type BranchProtectionPolicy struct {
+	BranchPatterns       []string `json:"branch_patterns"`
+	RequiredStatusChecks []string `json:"required_status_checks"`
+	EnforceAdmin         bool     `json:"enforce_admin"`
 }
 
 // ToModel converts to models.GithubOrganization
@@ -34,10 +46,41 @@ func ToModel(in *GithubOrganization) *models.GithubOrganization {
 		AutoEnabled:                in.AutoEnabled,
 		AutoEnabledClaGroupID:      in.AutoEnabledClaGroupID,
 		BranchProtectionEnabled:    in.BranchProtectionEnabled,
+		BranchProtectionPolicy:     toPolicyModel(in.BranchProtectionPolicy),
 		ProjectSFID:                in.ProjectSFID,
 	}
 }
 
+func toPolicyModel(in *BranchProtectionPolicy) *models.GithubBranchProtectionPolicy {
+	if in == nil {
+		return nil
+	}
+	return &models.GithubBranchProtectionPolicy{
+		BranchPatterns:       in.BranchPatterns,
+		RequiredStatusChecks: in.RequiredStatusChecks,
+		EnforceAdmin:         in.EnforceAdmin,
+	}
+}
+
+// IsBranchProtectionPolicyCleared returns true for the empty policy, it clears the policy of the organization which
+// returns to the default policy - a nil policy leaves the policy unchanged
+func IsBranchProtectionPolicyCleared(in *models.GithubBranchProtectionPolicy) bool {
+	return in != nil && len(in.BranchPatterns) == 0 && len(in.RequiredStatusChecks) == 0 && !in.EnforceAdmin
+}
+
+// ToBranchProtectionPolicy converts the policy of the github organization to the policy applied on its repositories,
+// the default policy when the organization didn't define one
+func ToBranchProtectionPolicy(in *models.GithubBranchProtectionPolicy) *branch_protection.BranchProtectionPolicy {
+	if in == nil {
+		return branch_protection.DefaultBranchProtectionPolicy()
+	}
+	return &branch_protection.BranchProtectionPolicy{
+		BranchPatterns:       in.BranchPatterns,
+		RequiredStatusChecks: in.RequiredStatusChecks,
+		EnforceAdmin:         in.EnforceAdmin,
+	}
+}
+
 func toModels(input []*GithubOrganization) []*models.GithubOrganization {
 	out := make([]*models.GithubOrganization, 0)
 	for _, in := range input {
@@ -45,3 +88,33 @@ func toModels(input []*GithubOrganization) []*models.GithubOrganization {
 	}
 	return out
 }
+
+// BranchProtectionAudit is the data model of the last branch protection audit of a github organization, stored along
+// with the organization record
+type BranchProtectionAudit struct {
+	OrganizationName       string                   `json:"organization_name"`
+	ProjectSFID            string                   `json:"project_sfid"`
+	DateAudited            string                   `json:"date_audited"`
+	Remediate              bool                     `json:"remediate"`
+	RepositoriesAudited    int64                    `json:"repositories_audited"`
+	RepositoriesDrifted    int64                    `json:"repositories_drifted"`
+	RepositoriesRemediated int64                    `json:"repositories_remediated"`
+	Errors                 int64                    `json:"errors"`
+	Drifts                 []*BranchProtectionDrift `json:"drifts"`
+}
+
+// BranchProtectionDrift is a repository whose branch protection rules fall short of the policy of its organization
+type BranchProtectionDrift struct {
+	RepositoryID   string                       `json:"repository_id"`
+	RepositoryName string                       `json:"repository_name"`
+	ClaGroupID     string                       `json:"cla_group_id,omitempty"`
+	Rules          []*BranchProtectionRuleDrift `json:"rules"`
+	Remediated     bool                         `json:"remediated"`
+	Error          string                       `json:"error,omitempty"`
+}
+
+// BranchProtectionRuleDrift is a branch pattern of the policy whose rule is missing or weakened
+type BranchProtectionRuleDrift struct {
+	Pattern string   `json:"pattern"`
+	Issues  []string `json:"issues"`
+}
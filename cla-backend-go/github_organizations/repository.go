@@ -21,6 +21,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
 )
 
 // indexes
@@ -32,7 +33,8 @@ const (
 
 // errors
 var (
-	ErrOrganizationDoesNotExist = errors.New("github organization does not exist in cla")
+	ErrOrganizationDoesNotExist          = errors.New("github organization does not exist in cla")
+	ErrBranchProtectionAuditDoesNotExist = errors.New("github organization branch protection was not audited")
 )
 
 // RepositoryInterface interface defines the functions for the github organizations data model
@@ -42,9 +44,12 @@ type RepositoryInterface interface {
 	GetGithubOrganizationsByParent(ctx context.Context, parentProjectSFID string) (*models.GithubOrganizations, error)
 	GetGithubOrganization(ctx context.Context, githubOrganizationName string) (*models.GithubOrganization, error)
 	GetGithubOrganizationByName(ctx context.Context, githubOrganizationName string) (*models.GithubOrganizations, error)
-	UpdateGithubOrganization(ctx context.Context, projectSFID string, organizationName string, autoEnabled bool, autoEnabledClaGroupID string, branchProtectionEnabled bool, enabled *bool) error
+	UpdateGithubOrganization(ctx context.Context, projectSFID string, organizationName string, autoEnabled bool, autoEnabledClaGroupID string, branchProtectionEnabled bool, branchProtectionPolicy *models.GithubBranchProtectionPolicy, enabled *bool) error
 	DeleteGithubOrganization(ctx context.Context, projectSFID string, githubOrgName string) error
 	DeleteGithubOrganizationByParent(ctx context.Context, parentProjectSFID string, githubOrgName string) error
+	GetBranchProtectionEnabledOrganizations(ctx context.Context) ([]*models.GithubOrganization, error)
+	GetBranchProtectionAudit(ctx context.Context, githubOrganizationName string) (*BranchProtectionAudit, error)
+	UpdateBranchProtectionAudit(ctx context.Context, githubOrganizationName string, audit *BranchProtectionAudit) error
 }
 
 // Repository object/struct
@@ -65,6 +70,9 @@ func NewRepository(awsSession *session.Session, stage string) Repository {
 
 // AddGithubOrganization add github organization logic
 func (repo Repository) AddGithubOrganization(ctx context.Context, parentProjectSFID string, projectSFID string, input *models.CreateGithubOrganization) (*models.GithubOrganization, error) {
+	ctx, span := telemetry.StartSpan(ctx, "github_organizations.repository.AddGithubOrganization")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":            "v1.github_organizations.repository.AddGitHubOrganization",
 		utils.XREQUESTID:          ctx.Value(utils.XREQUESTID),
@@ -117,6 +125,7 @@ func (repo Repository) AddGithubOrganization(ctx context.Context, parentProjectS
 			autoEnabled,
 			autoEnabledCLAGroupID,
 			branchProtectionEnabled,
+			nil,
 			&enabled,
 		)
 		if updateErr != nil {
@@ -169,7 +178,7 @@ func (repo Repository) AddGithubOrganization(ctx context.Context, parentProjectS
 	}
 
 	log.WithFields(f).Debug("Adding github organization record to the database...")
-	_, err = repo.dynamoDBClient.PutItem(&dynamodb.PutItemInput{
+	_, err = repo.dynamoDBClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
 		Item:                av,
 		TableName:           aws.String(repo.githubOrgTableName),
 		ConditionExpression: aws.String("attribute_not_exists(organization_name)"),
@@ -191,6 +200,9 @@ func (repo Repository) AddGithubOrganization(ctx context.Context, parentProjectS
 
 // GetGithubOrganizations get github organizations based on the project SFID
 func (repo Repository) GetGithubOrganizations(ctx context.Context, projectSFID string) (*models.GithubOrganizations, error) {
+	ctx, span := telemetry.StartSpan(ctx, "github_organizations.repository.GetGithubOrganizations")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.github_organizations.repository.GetGitHubOrganizations",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -221,7 +233,7 @@ func (repo Repository) GetGithubOrganizations(ctx context.Context, projectSFID s
 		IndexName:                 aws.String(ProjectSFIDOrganizationNameIndex),
 	}
 
-	results, err := repo.dynamoDBClient.Query(queryInput)
+	results, err := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).Warnf("error retrieving github_organizations using project_sfid = %s. error = %s", projectSFID, err.Error())
 		return nil, err
@@ -247,6 +259,9 @@ func (repo Repository) GetGithubOrganizations(ctx context.Context, projectSFID s
 
 // GetGithubOrganizationsByParent returns a list of github organizations by parent project SFID
 func (repo Repository) GetGithubOrganizationsByParent(ctx context.Context, parentProjectSFID string) (*models.GithubOrganizations, error) {
+	ctx, span := telemetry.StartSpan(ctx, "github_organizations.repository.GetGithubOrganizationsByParent")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":      "v1.github_organizations.repository.GetGithubOrganizationsByParent",
 		utils.XREQUESTID:    ctx.Value(utils.XREQUESTID),
@@ -273,7 +288,7 @@ func (repo Repository) GetGithubOrganizationsByParent(ctx context.Context, paren
 		IndexName:                 aws.String(GithubOrgSFIDIndex),
 	}
 
-	results, err := repo.dynamoDBClient.Query(queryInput)
+	results, err := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).Warnf("error retrieving github_organizations using organization_sfid = %s, error = %+v", parentProjectSFID, err)
 		return nil, err
@@ -299,6 +314,9 @@ func (repo Repository) GetGithubOrganizationsByParent(ctx context.Context, paren
 
 // GetGithubOrganizationByName get github organization by name
 func (repo Repository) GetGithubOrganizationByName(ctx context.Context, githubOrganizationName string) (*models.GithubOrganizations, error) {
+	ctx, span := telemetry.StartSpan(ctx, "github_organizations.repository.GetGithubOrganizationByName")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":           "v1.github_organizations.repository.GetGitHubOrganizationByName",
 		utils.XREQUESTID:         ctx.Value(utils.XREQUESTID),
@@ -324,7 +342,7 @@ func (repo Repository) GetGithubOrganizationByName(ctx context.Context, githubOr
 	}
 
 	log.WithFields(f).Debugf("querying for github organization by name using organization_name_lower=%s...", strings.ToLower(githubOrganizationName))
-	results, err := repo.dynamoDBClient.Query(queryInput)
+	results, err := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).WithError(err).Warnf("error retrieving github_organizations using githubOrganizationName = %s", githubOrganizationName)
 		return nil, err
@@ -348,6 +366,9 @@ func (repo Repository) GetGithubOrganizationByName(ctx context.Context, githubOr
 
 // GetGithubOrganization by organization name
 func (repo Repository) GetGithubOrganization(ctx context.Context, githubOrganizationName string) (*models.GithubOrganization, error) {
+	ctx, span := telemetry.StartSpan(ctx, "github_organizations.repository.GetGithubOrganization")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":           "v1.github_organizations.repository.GetGitHubOrganization",
 		utils.XREQUESTID:         ctx.Value(utils.XREQUESTID),
@@ -355,7 +376,7 @@ func (repo Repository) GetGithubOrganization(ctx context.Context, githubOrganiza
 	}
 
 	log.WithFields(f).Debug("Querying for github organization by name...")
-	result, err := repo.dynamoDBClient.GetItem(&dynamodb.GetItemInput{
+	result, err := repo.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
 		Key: map[string]*dynamodb.AttributeValue{
 			"organization_name": {
 				S: aws.String(githubOrganizationName),
@@ -381,7 +402,10 @@ func (repo Repository) GetGithubOrganization(ctx context.Context, githubOrganiza
 }
 
 // UpdateGithubOrganization updates the specified GitHub organization based on the update model provided
-func (repo Repository) UpdateGithubOrganization(ctx context.Context, projectSFID string, organizationName string, autoEnabled bool, autoEnabledClaGroupID string, branchProtectionEnabled bool, enabled *bool) error {
+func (repo Repository) UpdateGithubOrganization(ctx context.Context, projectSFID string, organizationName string, autoEnabled bool, autoEnabledClaGroupID string, branchProtectionEnabled bool, branchProtectionPolicy *models.GithubBranchProtectionPolicy, enabled *bool) error {
+	ctx, span := telemetry.StartSpan(ctx, "github_organizations.repository.UpdateGithubOrganization")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":            "v1.github_organizations.repository.UpdateGitHubOrganization",
 		utils.XREQUESTID:          ctx.Value(utils.XREQUESTID),
@@ -425,14 +449,33 @@ func (repo Repository) UpdateGithubOrganization(ctx context.Context, projectSFID
 			S: aws.String(currentTime),
 		},
 	}
-	updateExpression := "SET #A = :a, #C = :c, #B = :b, #M = :m,"
+	updateExpression := "SET #A = :a, #C = :c, #B = :b, #M = :m"
 
 	if enabled != nil {
 		expressionAttributeNames["#E"] = aws.String("enabled")
 		expressionAttributeValues[":e"] = &dynamodb.AttributeValue{
 			BOOL: aws.Bool(*enabled),
 		}
-		updateExpression = updateExpression + " #E = :e "
+		updateExpression = updateExpression + ", #E = :e"
+	}
+
+	// the policy is only replaced when provided, the empty policy removes it
+	if IsBranchProtectionPolicyCleared(branchProtectionPolicy) {
+		expressionAttributeNames["#P"] = aws.String("branch_protection_policy")
+		updateExpression = updateExpression + " REMOVE #P"
+	} else if branchProtectionPolicy != nil {
+		policyAttributeValue, marshalErr := dynamodbattribute.Marshal(BranchProtectionPolicy{
+			BranchPatterns:       branchProtectionPolicy.BranchPatterns,
+			RequiredStatusChecks: branchProtectionPolicy.RequiredStatusChecks,
+			EnforceAdmin:         branchProtectionPolicy.EnforceAdmin,
+		})
+		if marshalErr != nil {
+			log.WithFields(f).WithError(marshalErr).Warn("unable to marshal the branch protection policy")
+			return marshalErr
+		}
+		expressionAttributeNames["#P"] = aws.String("branch_protection_policy")
+		expressionAttributeValues[":p"] = policyAttributeValue
+		updateExpression = updateExpression + ", #P = :p"
 	}
 
 	input := &dynamodb.UpdateItemInput{
@@ -448,7 +491,7 @@ func (repo Repository) UpdateGithubOrganization(ctx context.Context, projectSFID
 	}
 
 	log.WithFields(f).Debug("updating github organization record...")
-	_, updateErr := repo.dynamoDBClient.UpdateItem(input)
+	_, updateErr := repo.dynamoDBClient.UpdateItemWithContext(ctx, input)
 	if updateErr != nil {
 		log.WithFields(f).Warnf("unable to update GitHub organization record, error: %+v", updateErr)
 		return updateErr
@@ -459,6 +502,9 @@ func (repo Repository) UpdateGithubOrganization(ctx context.Context, projectSFID
 
 // DeleteGithubOrganization deletes the github organization by project SFID
 func (repo Repository) DeleteGithubOrganization(ctx context.Context, projectSFID string, githubOrgName string) error {
+	ctx, span := telemetry.StartSpan(ctx, "github_organizations.repository.DeleteGithubOrganization")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.github_organizations.repository.DeleteGitHubOrganization",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -484,7 +530,7 @@ func (repo Repository) DeleteGithubOrganization(ctx context.Context, projectSFID
 	// Update enabled flag as false
 	_, currentTime := utils.CurrentTime()
 	note := fmt.Sprintf("Enabled set to false due to org deletion at %s ", currentTime)
-	_, err := repo.dynamoDBClient.UpdateItem(
+	_, err := repo.dynamoDBClient.UpdateItemWithContext(ctx,
 		&dynamodb.UpdateItemInput{
 			Key: map[string]*dynamodb.AttributeValue{
 				"organization_name": {
@@ -522,6 +568,9 @@ func (repo Repository) DeleteGithubOrganization(ctx context.Context, projectSFID
 
 // DeleteGithubOrganizationByParent deletes the github organization by parent SFID
 func (repo Repository) DeleteGithubOrganizationByParent(ctx context.Context, parentProjectSFID string, githubOrgName string) error {
+	ctx, span := telemetry.StartSpan(ctx, "github_organizations.repository.DeleteGithubOrganizationByParent")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":      "v1.github_organizations.repository.DeleteGitHubOrganization",
 		utils.XREQUESTID:    ctx.Value(utils.XREQUESTID),
@@ -544,7 +593,7 @@ func (repo Repository) DeleteGithubOrganizationByParent(ctx context.Context, par
 	}
 
 	log.WithFields(f).Debug("Deleting GitHub organization...")
-	_, err := repo.dynamoDBClient.DeleteItem(&dynamodb.DeleteItemInput{
+	_, err := repo.dynamoDBClient.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
 		Key: map[string]*dynamodb.AttributeValue{
 			"organization_name": {
 				S: aws.String(githubOrganizationName),
@@ -560,3 +609,140 @@ func (repo Repository) DeleteGithubOrganizationByParent(ctx context.Context, par
 
 	return nil
 }
+
+// GetBranchProtectionEnabledOrganizations returns the enabled github organizations with branch protection enabled
+func (repo Repository) GetBranchProtectionEnabledOrganizations(ctx context.Context) ([]*models.GithubOrganization, error) {
+	ctx, span := telemetry.StartSpan(ctx, "github_organizations.repository.GetBranchProtectionEnabledOrganizations")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.github_organizations.repository.GetBranchProtectionEnabledOrganizations",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"tableName":      repo.githubOrgTableName,
+	}
+
+	filter := expression.Name("enabled").Equal(expression.Value(true)).
+		And(expression.Name("branch_protection_enabled").Equal(expression.Value(true)))
+	expr, err := expression.NewBuilder().WithFilter(filter).Build()
+	if err != nil {
+		log.WithFields(f).Warnf("problem building scan expression, error: %+v", err)
+		return nil, err
+	}
+
+	scanInput := &dynamodb.ScanInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		FilterExpression:          expr.Filter(),
+		TableName:                 aws.String(repo.githubOrgTableName),
+	}
+	var resultList []map[string]*dynamodb.AttributeValue
+	for {
+		results, scanErr := repo.dynamoDBClient.ScanWithContext(ctx, scanInput) //nolint
+		if scanErr != nil {
+			log.WithFields(f).Warnf("error retrieving %s, error: %v", repo.githubOrgTableName, scanErr)
+			return nil, scanErr
+		}
+		resultList = append(resultList, results.Items...)
+		if len(results.LastEvaluatedKey) != 0 {
+			scanInput.ExclusiveStartKey = results.LastEvaluatedKey
+		} else {
+			break
+		}
+	}
+
+	var resultOutput []*GithubOrganization
+	err = dynamodbattribute.UnmarshalListOfMaps(resultList, &resultOutput)
+	if err != nil {
+		log.WithFields(f).Warnf("problem decoding database results, error: %+v", err)
+		return nil, err
+	}
+	return toModels(resultOutput), nil
+}
+
+// GetBranchProtectionAudit returns the last branch protection audit of the github organization
+func (repo Repository) GetBranchProtectionAudit(ctx context.Context, githubOrganizationName string) (*BranchProtectionAudit, error) {
+	ctx, span := telemetry.StartSpan(ctx, "github_organizations.repository.GetBranchProtectionAudit")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":           "v1.github_organizations.repository.GetBranchProtectionAudit",
+		utils.XREQUESTID:         ctx.Value(utils.XREQUESTID),
+		"githubOrganizationName": githubOrganizationName,
+	}
+
+	result, err := repo.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"organization_name": {
+				S: aws.String(githubOrganizationName),
+			},
+		},
+		ExpressionAttributeNames: map[string]*string{
+			"#A": aws.String("branch_protection_audit"),
+		},
+		ProjectionExpression: aws.String("#A"),
+		TableName:            aws.String(repo.githubOrgTableName),
+	})
+	if err != nil {
+		log.WithFields(f).Warnf("error loading the branch protection audit, error: %v", err)
+		return nil, err
+	}
+
+	var record struct {
+		BranchProtectionAudit *BranchProtectionAudit `json:"branch_protection_audit"`
+	}
+	err = dynamodbattribute.UnmarshalMap(result.Item, &record)
+	if err != nil {
+		log.WithFields(f).Warnf("error unmarshalling the branch protection audit, error: %v", err)
+		return nil, err
+	}
+	if record.BranchProtectionAudit == nil {
+		return nil, ErrBranchProtectionAuditDoesNotExist
+	}
+	return record.BranchProtectionAudit, nil
+}
+
+// UpdateBranchProtectionAudit stores the branch protection audit on the github organization record, replacing the
+// previous audit
+func (repo Repository) UpdateBranchProtectionAudit(ctx context.Context, githubOrganizationName string, audit *BranchProtectionAudit) error {
+	ctx, span := telemetry.StartSpan(ctx, "github_organizations.repository.UpdateBranchProtectionAudit")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":           "v1.github_organizations.repository.UpdateBranchProtectionAudit",
+		utils.XREQUESTID:         ctx.Value(utils.XREQUESTID),
+		"githubOrganizationName": githubOrganizationName,
+		"tableName":              repo.githubOrgTableName,
+	}
+
+	auditValue, err := dynamodbattribute.Marshal(audit)
+	if err != nil {
+		log.WithFields(f).Warnf("error marshalling the branch protection audit, error: %v", err)
+		return err
+	}
+
+	_, err = repo.dynamoDBClient.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"organization_name": {
+				S: aws.String(githubOrganizationName),
+			},
+		},
+		ExpressionAttributeNames: map[string]*string{
+			"#A": aws.String("branch_protection_audit"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":a": auditValue,
+		},
+		// the audit is only stored on the existing organizations
+		ConditionExpression: aws.String("attribute_exists(organization_name)"),
+		UpdateExpression:    aws.String("SET #A = :a"),
+		TableName:           aws.String(repo.githubOrgTableName),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return ErrOrganizationDoesNotExist
+		}
+		log.WithFields(f).Warnf("unable to store the branch protection audit, error: %v", err)
+		return err
+	}
+	return nil
+}
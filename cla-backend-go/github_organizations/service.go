@@ -16,6 +16,7 @@ import (
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
 
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	"github.com/communitybridge/easycla/cla-backend-go/github/branch_protection"
 	"github.com/communitybridge/easycla/cla-backend-go/repositories"
 )
 
@@ -25,7 +26,7 @@ type ServiceInterface interface {
 	GetGithubOrganizations(ctx context.Context, projectSFID string) (*models.GithubOrganizations, error)
 	GetGithubOrganizationsByParent(ctx context.Context, parentProjectSFID string) (*models.GithubOrganizations, error)
 	GetGithubOrganizationByName(ctx context.Context, githubOrgName string) (*models.GithubOrganization, error)
-	UpdateGithubOrganization(ctx context.Context, projectSFID string, organizationName string, autoEnabled bool, autoEnabledClaGroupID string, branchProtectionEnabled bool) error
+	UpdateGithubOrganization(ctx context.Context, projectSFID string, organizationName string, autoEnabled bool, autoEnabledClaGroupID string, branchProtectionEnabled bool, branchProtectionPolicy *models.GithubBranchProtectionPolicy) error
 	DeleteGithubOrganization(ctx context.Context, projectSFID string, githubOrgName string) error
 	RemoveDuplicates(input []*models.GithubOrganization) []*models.GithubOrganization
 }
@@ -164,14 +165,19 @@ func (s Service) GetGithubOrganizationByName(ctx context.Context, githubOrgName
 }
 
 // UpdateGithubOrganization updates the specified github organization based on the project SFID, organization name provided values
-func (s Service) UpdateGithubOrganization(ctx context.Context, projectSFID string, organizationName string, autoEnabled bool, autoEnabledClaGroupID string, branchProtectionEnabled bool) error {
+func (s Service) UpdateGithubOrganization(ctx context.Context, projectSFID string, organizationName string, autoEnabled bool, autoEnabledClaGroupID string, branchProtectionEnabled bool, branchProtectionPolicy *models.GithubBranchProtectionPolicy) error {
 	// check if valid cla group id is passed
 	if autoEnabledClaGroupID != "" {
 		if _, err := s.claRepository.GetCLAGroupNameByID(ctx, autoEnabledClaGroupID); err != nil {
 			return err
 		}
 	}
-	return s.repo.UpdateGithubOrganization(ctx, projectSFID, organizationName, autoEnabled, autoEnabledClaGroupID, branchProtectionEnabled, nil)
+	if branchProtectionPolicy != nil && !IsBranchProtectionPolicyCleared(branchProtectionPolicy) {
+		if err := branch_protection.ValidateBranchProtectionPolicy(ToBranchProtectionPolicy(branchProtectionPolicy)); err != nil {
+			return err
+		}
+	}
+	return s.repo.UpdateGithubOrganization(ctx, projectSFID, organizationName, autoEnabled, autoEnabledClaGroupID, branchProtectionEnabled, branchProtectionPolicy, nil)
 }
 
 // DeleteGithubOrganization removes the specified github organization under the projectSFID
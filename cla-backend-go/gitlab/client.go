@@ -0,0 +1,297 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// DefaultAPIURL is the GitLab.com REST API base URL
+const DefaultAPIURL = "https://gitlab.com/api/v4"
+
+// commit status states
+const (
+	CommitStatusPending = "pending"
+	CommitStatusRunning = "running"
+	CommitStatusSuccess = "success"
+	CommitStatusFailed  = "failed"
+)
+
+var (
+	// ErrAccessDenied is returned whenever gitlab return 403 or 401
+	ErrAccessDenied = errors.New("access denied")
+	// ErrRateLimited is returned when gitlab detects rate limit abuse
+	ErrRateLimited = errors.New("rate limit")
+	// ErrNotFound is returned when the gitlab resource does not exist
+	ErrNotFound = errors.New("not found")
+)
+
+// maxPages is the maximum number of pages loaded by the list calls
+const maxPages = 50
+
+// Client is a minimal GitLab REST API v4 client
+type Client interface {
+	GetProject(ctx context.Context, projectID int64) (*Project, error)
+	GetGroup(ctx context.Context, groupPath string) (*Group, error)
+	ListGroupProjects(ctx context.Context, groupID int64) ([]*Project, error)
+	GetMergeRequest(ctx context.Context, projectID int64, mergeRequestIID int64) (*MergeRequest, error)
+	ListMergeRequestCommits(ctx context.Context, projectID int64, mergeRequestIID int64) ([]*Commit, error)
+	SetCommitStatus(ctx context.Context, projectID int64, sha string, options *CommitStatusOptions) (*CommitStatus, error)
+	FindUserByEmail(ctx context.Context, email string) (*User, error)
+}
+
+type client struct {
+	apiURL      string
+	accessToken string
+	httpClient  *http.Client
+}
+
+// NewClient creates a new GitLab client for the specified API URL (defaults to GitLab.com) using the access token
+func NewClient(apiURL, accessToken string, httpClient *http.Client) Client {
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	if httpClient == nil {
+		httpClient = telemetry.NewHTTPClient(30 * time.Second)
+	}
+	return &client{
+		apiURL:      strings.TrimSuffix(apiURL, "/"),
+		accessToken: accessToken,
+		httpClient:  httpClient,
+	}
+}
+
+// GetProject returns the GitLab project (repository) by ID
+func (c *client) GetProject(ctx context.Context, projectID int64) (*Project, error) {
+	var project Project
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/projects/%d", projectID), nil, nil, &project); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// GetGroup returns the GitLab group by its full path, e.g. my-group/my-sub-group
+func (c *client) GetGroup(ctx context.Context, groupPath string) (*Group, error) {
+	var group Group
+	if err := c.do(ctx, http.MethodGet, "/groups/"+url.PathEscape(groupPath), nil, nil, &group); err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// ListGroupProjects returns the projects of the GitLab group, including the projects of the sub-groups
+func (c *client) ListGroupProjects(ctx context.Context, groupID int64) ([]*Project, error) {
+	var projects []*Project
+	query := url.Values{"include_subgroups": []string{"true"}, "archived": []string{"false"}}
+	err := c.list(ctx, fmt.Sprintf("/groups/%d/projects", groupID), query, func(page []byte) error {
+		var pageProjects []*Project
+		if err := json.Unmarshal(page, &pageProjects); err != nil {
+			return err
+		}
+		projects = append(projects, pageProjects...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+// GetMergeRequest returns the merge request of the project by its project level ID
+func (c *client) GetMergeRequest(ctx context.Context, projectID int64, mergeRequestIID int64) (*MergeRequest, error) {
+	var mergeRequest MergeRequest
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/projects/%d/merge_requests/%d", projectID, mergeRequestIID), nil, nil, &mergeRequest); err != nil {
+		return nil, err
+	}
+	return &mergeRequest, nil
+}
+
+// ListMergeRequestCommits returns the commits of the merge request
+func (c *client) ListMergeRequestCommits(ctx context.Context, projectID int64, mergeRequestIID int64) ([]*Commit, error) {
+	var commits []*Commit
+	err := c.list(ctx, fmt.Sprintf("/projects/%d/merge_requests/%d/commits", projectID, mergeRequestIID), url.Values{}, func(page []byte) error {
+		var pageCommits []*Commit
+		if err := json.Unmarshal(page, &pageCommits); err != nil {
+			return err
+		}
+		commits = append(commits, pageCommits...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return commits, nil
+}
+
+// SetCommitStatus creates or updates the commit status of the specified commit
+func (c *client) SetCommitStatus(ctx context.Context, projectID int64, sha string, options *CommitStatusOptions) (*CommitStatus, error) {
+	var status CommitStatus
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/projects/%d/statuses/%s", projectID, url.PathEscape(sha)), nil, options, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// FindUserByEmail returns the GitLab account owning the email, nil when no single account owns it. GitLab only matches
+// the confirmed emails of the accounts - all of them with an administrator token, the public email otherwise.
+func (c *client) FindUserByEmail(ctx context.Context, email string) (*User, error) {
+	var users []*User
+	if err := c.do(ctx, http.MethodGet, "/users", url.Values{"search": []string{email}}, nil, &users); err != nil {
+		return nil, err
+	}
+
+	// the search also matches the names and usernames, only keep the accounts with the exact email
+	var found *User
+	for _, user := range users {
+		if !strings.EqualFold(user.Email, email) && !strings.EqualFold(user.PublicEmail, email) {
+			continue
+		}
+		if found != nil {
+			return nil, nil
+		}
+		found = user
+	}
+	return found, nil
+}
+
+// list loads all the pages of the specified list endpoint, invoking the callback with the body of each page
+func (c *client) list(ctx context.Context, path string, query url.Values, pageHandler func(page []byte) error) error {
+	query.Set("per_page", "100")
+	for page := 1; page <= maxPages; page++ {
+		query.Set("page", strconv.Itoa(page))
+		var body json.RawMessage
+		resp, err := c.request(ctx, http.MethodGet, path, query, nil, &body)
+		if err != nil {
+			return err
+		}
+		if err := pageHandler(body); err != nil {
+			return err
+		}
+		if resp.Header.Get("X-Next-Page") == "" {
+			return nil
+		}
+	}
+
+	log.WithFields(logrus.Fields{"functionName": "gitlab.client.list", "path": path}).
+		Warnf("stopped loading the results after %d pages", maxPages)
+	return nil
+}
+
+// do sends the request and decodes the JSON response into the out value
+func (c *client) do(ctx context.Context, method, path string, query url.Values, in interface{}, out interface{}) error {
+	_, err := c.request(ctx, method, path, query, in, out)
+	return err
+}
+
+// request sends the request and decodes the JSON response into the out value, returns the http response
+func (c *client) request(ctx context.Context, method, path string, query url.Values, in interface{}, out interface{}) (*http.Response, error) {
+	f := logrus.Fields{
+		"functionName":   "gitlab.client.request",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"method":         method,
+		"path":           path,
+	}
+
+	endpoint := c.apiURL + path
+	if len(query) > 0 {
+		endpoint = endpoint + "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.accessToken != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.accessToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("gitlab request failed")
+		return nil, err
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			log.WithFields(f).WithError(closeErr).Warn("error closing response body")
+		}
+	}()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkResponse(resp, respBody); err != nil {
+		log.WithFields(f).WithError(err).Debugf("gitlab request returned status: %d", resp.StatusCode)
+		return resp, err
+	}
+
+	if out != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return resp, fmt.Errorf("unable to decode the gitlab response : %w", err)
+		}
+	}
+	return resp, nil
+}
+
+// checkResponse wraps the known gitlab error responses
+func checkResponse(resp *http.Response, body []byte) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	var errResponse struct {
+		Message interface{} `json:"message"`
+		Error   string      `json:"error"`
+	}
+	msg := strings.TrimSpace(string(body))
+	if json.Unmarshal(body, &errResponse) == nil {
+		if errResponse.Message != nil {
+			msg = fmt.Sprintf("%v", errResponse.Message)
+		} else if errResponse.Error != "" {
+			msg = errResponse.Error
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%s : %w", msg, ErrAccessDenied)
+	case http.StatusNotFound:
+		return fmt.Errorf("%s : %w", msg, ErrNotFound)
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%s : %w", msg, ErrRateLimited)
+	}
+	return fmt.Errorf("gitlab request failed with status %d : %s", resp.StatusCode, msg)
+}
@@ -0,0 +1,91 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package gitlab
+
+// Namespace is the GitLab namespace (group or user) of a project
+type Namespace struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Kind     string `json:"kind"`
+	FullPath string `json:"full_path"`
+	WebURL   string `json:"web_url"`
+}
+
+// Project is the GitLab project - the GitLab equivalent of a GitHub repository
+type Project struct {
+	ID                int64      `json:"id"`
+	Name              string     `json:"name"`
+	Path              string     `json:"path"`
+	PathWithNamespace string     `json:"path_with_namespace"`
+	DefaultBranch     string     `json:"default_branch"`
+	WebURL            string     `json:"web_url"`
+	Archived          bool       `json:"archived"`
+	Namespace         *Namespace `json:"namespace"`
+}
+
+// Group is the GitLab group - the GitLab equivalent of a GitHub organization
+type Group struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	FullPath string `json:"full_path"`
+	ParentID int64  `json:"parent_id"`
+	WebURL   string `json:"web_url"`
+}
+
+// User is the GitLab user
+type User struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Username    string `json:"username"`
+	Email       string `json:"email"`
+	PublicEmail string `json:"public_email"`
+}
+
+// MergeRequest is the GitLab merge request - the GitLab equivalent of a GitHub pull request
+type MergeRequest struct {
+	ID              int64  `json:"id"`
+	IID             int64  `json:"iid"`
+	ProjectID       int64  `json:"project_id"`
+	Title           string `json:"title"`
+	State           string `json:"state"`
+	SourceBranch    string `json:"source_branch"`
+	TargetBranch    string `json:"target_branch"`
+	SourceProjectID int64  `json:"source_project_id"`
+	TargetProjectID int64  `json:"target_project_id"`
+	SHA             string `json:"sha"`
+	WebURL          string `json:"web_url"`
+}
+
+// Commit is the GitLab commit
+type Commit struct {
+	ID             string `json:"id"`
+	ShortID        string `json:"short_id"`
+	Title          string `json:"title"`
+	AuthorName     string `json:"author_name"`
+	AuthorEmail    string `json:"author_email"`
+	CommitterName  string `json:"committer_name"`
+	CommitterEmail string `json:"committer_email"`
+}
+
+// CommitStatusOptions are the values of a commit status update
+type CommitStatusOptions struct {
+	State       string `json:"state"`
+	Ref         string `json:"ref,omitempty"`
+	Name        string `json:"name,omitempty"`
+	TargetURL   string `json:"target_url,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// CommitStatus is the GitLab commit status
+type CommitStatus struct {
+	ID          int64  `json:"id"`
+	SHA         string `json:"sha"`
+	Ref         string `json:"ref"`
+	Status      string `json:"status"`
+	Name        string `json:"name"`
+	TargetURL   string `json:"target_url"`
+	Description string `json:"description"`
+}
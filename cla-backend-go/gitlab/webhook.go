@@ -0,0 +1,150 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package gitlab
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// webhook headers
+const (
+	EventTypeHeader = "X-Gitlab-Event"
+	TokenHeader     = "X-Gitlab-Token"
+)
+
+// webhook event types - the X-Gitlab-Event header values
+const (
+	EventTypeSystemHook   = "System Hook"
+	EventTypeMergeRequest = "Merge Request Hook"
+)
+
+// project event names of the system hook
+const (
+	ProjectCreate   = "project_create"
+	ProjectDestroy  = "project_destroy"
+	ProjectRename   = "project_rename"
+	ProjectTransfer = "project_transfer"
+	ProjectUpdate   = "project_update"
+)
+
+// merge request actions
+const (
+	MergeRequestOpen   = "open"
+	MergeRequestReopen = "reopen"
+	MergeRequestUpdate = "update"
+)
+
+var (
+	// ErrInvalidToken is returned when the webhook secret token doesn't match
+	ErrInvalidToken = errors.New("invalid webhook token")
+	// ErrMissingSecret is returned when no webhook secret is configured, all the requests are rejected
+	ErrMissingSecret = errors.New("webhook secret not configured")
+	// ErrUnsupportedEvent is returned when the webhook event is not handled
+	ErrUnsupportedEvent = errors.New("unsupported event")
+)
+
+// ProjectEvent is the system hook project event - sent when a project is created, renamed, transferred, updated or
+// destroyed
+type ProjectEvent struct {
+	EventName            string `json:"event_name"`
+	CreatedAt            string `json:"created_at"`
+	UpdatedAt            string `json:"updated_at"`
+	Name                 string `json:"name"`
+	Path                 string `json:"path"`
+	PathWithNamespace    string `json:"path_with_namespace"`
+	OldPathWithNamespace string `json:"old_path_with_namespace"`
+	ProjectID            int64  `json:"project_id"`
+	OwnerName            string `json:"owner_name"`
+	OwnerEmail           string `json:"owner_email"`
+	ProjectVisibility    string `json:"project_visibility"`
+}
+
+// MergeRequestEvent is the merge request hook event
+type MergeRequestEvent struct {
+	ObjectKind       string                 `json:"object_kind"`
+	User             *User                  `json:"user"`
+	Project          *Project               `json:"project"`
+	ObjectAttributes *MergeRequestAttribute `json:"object_attributes"`
+}
+
+// MergeRequestAttribute is the merge request of the merge request hook event
+type MergeRequestAttribute struct {
+	ID              int64               `json:"id"`
+	IID             int64               `json:"iid"`
+	Title           string              `json:"title"`
+	State           string              `json:"state"`
+	Action          string              `json:"action"`
+	SourceBranch    string              `json:"source_branch"`
+	TargetBranch    string              `json:"target_branch"`
+	SourceProjectID int64               `json:"source_project_id"`
+	TargetProjectID int64               `json:"target_project_id"`
+	URL             string              `json:"url"`
+	LastCommit      *MergeRequestCommit `json:"last_commit"`
+}
+
+// MergeRequestCommit is the last commit of the merge request hook event
+type MergeRequestCommit struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+	Author  struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	} `json:"author"`
+}
+
+// ValidateToken checks the webhook secret token header of the request, every request is rejected when the secret is
+// empty
+func ValidateToken(r *http.Request, secret string) error {
+	if secret == "" {
+		return ErrMissingSecret
+	}
+	token := r.Header.Get(TokenHeader)
+	if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// ReadPayload reads and validates the webhook request payload
+func ReadPayload(r *http.Request, secret string) ([]byte, error) {
+	if err := ValidateToken(r, secret); err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(r.Body)
+}
+
+// ParseWebHook parses the webhook payload of the specified event type, returns a *ProjectEvent or *MergeRequestEvent
+func ParseWebHook(eventType string, payload []byte) (interface{}, error) {
+	switch eventType {
+	case EventTypeSystemHook:
+		var kind struct {
+			EventName string `json:"event_name"`
+		}
+		if err := json.Unmarshal(payload, &kind); err != nil {
+			return nil, err
+		}
+		switch kind.EventName {
+		case ProjectCreate, ProjectDestroy, ProjectRename, ProjectTransfer, ProjectUpdate:
+			var event ProjectEvent
+			if err := json.Unmarshal(payload, &event); err != nil {
+				return nil, err
+			}
+			return &event, nil
+		}
+		return nil, fmt.Errorf("system hook %s : %w", kind.EventName, ErrUnsupportedEvent)
+	case EventTypeMergeRequest:
+		var event MergeRequestEvent
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return nil, err
+		}
+		return &event, nil
+	}
+
+	return nil, fmt.Errorf("%s : %w", eventType, ErrUnsupportedEvent)
+}
@@ -0,0 +1,22 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package gitlab_organizations
+
+// GitLabGroup is data model for gitlab groups - the GitLab equivalent of the github organizations. The repositories
+// (GitLab projects) of the group, including the ones of its sub-groups, are stored in the repositories table with the
+// gitlab repository type.
+type GitLabGroup struct {
+	DateCreated           string `dynamodbav:"date_created" json:"date_created,omitempty"`
+	DateModified          string `dynamodbav:"date_modified" json:"date_modified,omitempty"`
+	GroupID               int64  `dynamodbav:"group_id" json:"group_id,omitempty"`
+	GroupFullPath         string `dynamodbav:"group_full_path" json:"group_full_path,omitempty"`
+	GroupFullPathLower    string `dynamodbav:"group_full_path_lower" json:"group_full_path_lower,omitempty"`
+	GroupURL              string `dynamodbav:"group_url" json:"group_url,omitempty"`
+	OrganizationSFID      string `dynamodbav:"organization_sfid" json:"organization_sfid,omitempty"`
+	ProjectSFID           string `dynamodbav:"project_sfid" json:"project_sfid"`
+	Enabled               bool   `dynamodbav:"enabled" json:"enabled"`
+	AutoEnabled           bool   `dynamodbav:"auto_enabled" json:"auto_enabled"`
+	AutoEnabledClaGroupID string `dynamodbav:"auto_enabled_cla_group_id" json:"auto_enabled_cla_group_id,omitempty"`
+	Version               string `dynamodbav:"version" json:"version,omitempty"`
+}
@@ -0,0 +1,247 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package gitlab_organizations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
+)
+
+// indexes
+const (
+	ProjectSFIDGroupFullPathIndex = "project-sfid-group-full-path-index"
+)
+
+// errors
+var (
+	ErrGroupDoesNotExist = errors.New("gitlab group does not exist in cla")
+	ErrGroupExists       = errors.New("gitlab group already exists")
+)
+
+// RepositoryInterface interface defines the functions for the gitlab groups data model
+type RepositoryInterface interface {
+	AddGitLabGroup(ctx context.Context, input *GitLabGroup) (*GitLabGroup, error)
+	GetGitLabGroup(ctx context.Context, groupFullPath string) (*GitLabGroup, error)
+	GetGitLabGroups(ctx context.Context, projectSFID string) ([]*GitLabGroup, error)
+	UpdateGitLabGroup(ctx context.Context, groupFullPath string, autoEnabled bool, autoEnabledClaGroupID string, enabled bool) error
+	DeleteGitLabGroup(ctx context.Context, groupFullPath string) error
+}
+
+// Repository object/struct
+type Repository struct {
+	stage              string
+	dynamoDBClient     *dynamodb.DynamoDB
+	gitlabOrgTableName string
+}
+
+// NewRepository creates a new instance of the gitlab groups repository
+func NewRepository(awsSession *session.Session, stage string) RepositoryInterface {
+	return Repository{
+		stage:              stage,
+		dynamoDBClient:     dynamodb.New(awsSession),
+		gitlabOrgTableName: fmt.Sprintf("cla-%s-gitlab-orgs", stage),
+	}
+}
+
+// newGitLabGroup returns the record of the new gitlab group
+func newGitLabGroup(input *GitLabGroup) *GitLabGroup {
+	_, currentTime := utils.CurrentTime()
+	group := *input
+	group.DateCreated = currentTime
+	group.DateModified = currentTime
+	group.GroupFullPathLower = strings.ToLower(input.GroupFullPath)
+	group.Enabled = true
+	group.Version = "v1"
+	return &group
+}
+
+// AddGitLabGroup adds the gitlab group
+func (repo Repository) AddGitLabGroup(ctx context.Context, input *GitLabGroup) (*GitLabGroup, error) {
+	ctx, span := telemetry.StartSpan(ctx, "gitlab_organizations.repository.AddGitLabGroup")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":          "v1.gitlab_organizations.repository.AddGitLabGroup",
+		utils.XREQUESTID:        ctx.Value(utils.XREQUESTID),
+		"groupFullPath":         input.GroupFullPath,
+		"projectSFID":           input.ProjectSFID,
+		"autoEnabled":           input.AutoEnabled,
+		"autoEnabledClaGroupID": input.AutoEnabledClaGroupID,
+	}
+
+	group := newGitLabGroup(input)
+	av, err := dynamodbattribute.MarshalMap(group)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to marshall request for query")
+		return nil, err
+	}
+
+	log.WithFields(f).Debug("Adding gitlab group record to the database...")
+	_, err = repo.dynamoDBClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		Item:                av,
+		TableName:           aws.String(repo.gitlabOrgTableName),
+		ConditionExpression: aws.String("attribute_not_exists(group_full_path_lower)"),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			log.WithFields(f).WithError(err).Warn("gitlab group already exists")
+			return nil, ErrGroupExists
+		}
+		log.WithFields(f).WithError(err).Warn("cannot put gitlab group in dynamodb")
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// GetGitLabGroup returns the gitlab group by its full path - the lookup is case insensitive
+func (repo Repository) GetGitLabGroup(ctx context.Context, groupFullPath string) (*GitLabGroup, error) {
+	ctx, span := telemetry.StartSpan(ctx, "gitlab_organizations.repository.GetGitLabGroup")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.gitlab_organizations.repository.GetGitLabGroup",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"groupFullPath":  groupFullPath,
+	}
+
+	result, err := repo.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"group_full_path_lower": {S: aws.String(strings.ToLower(groupFullPath))},
+		},
+		TableName: aws.String(repo.gitlabOrgTableName),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("error retrieving gitlab group")
+		return nil, err
+	}
+	if len(result.Item) == 0 {
+		return nil, ErrGroupDoesNotExist
+	}
+
+	var group GitLabGroup
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &group); err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// GetGitLabGroups returns the enabled gitlab groups of the project
+func (repo Repository) GetGitLabGroups(ctx context.Context, projectSFID string) ([]*GitLabGroup, error) {
+	ctx, span := telemetry.StartSpan(ctx, "gitlab_organizations.repository.GetGitLabGroups")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.gitlab_organizations.repository.GetGitLabGroups",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"projectSFID":    projectSFID,
+	}
+
+	condition := expression.Key("project_sfid").Equal(expression.Value(projectSFID))
+	filter := expression.Name("enabled").Equal(expression.Value(true))
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).WithFilter(filter).Build()
+	if err != nil {
+		log.WithFields(f).Warnf("problem building query expression, error: %+v", err)
+		return nil, err
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+		TableName:                 aws.String(repo.gitlabOrgTableName),
+		IndexName:                 aws.String(ProjectSFIDGroupFullPathIndex),
+	}
+
+	var groups []*GitLabGroup
+	err = repo.dynamoDBClient.QueryPagesWithContext(ctx, queryInput, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		var pageGroups []*GitLabGroup
+		if unmarshalErr := dynamodbattribute.UnmarshalListOfMaps(page.Items, &pageGroups); unmarshalErr != nil {
+			err = unmarshalErr
+			return false
+		}
+		groups = append(groups, pageGroups...)
+		return true
+	})
+	if err != nil {
+		log.WithFields(f).Warnf("error retrieving gitlab groups using project_sfid = %s. error = %s", projectSFID, err.Error())
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// UpdateGitLabGroup updates the auto enable and enabled flags of the gitlab group
+func (repo Repository) UpdateGitLabGroup(ctx context.Context, groupFullPath string, autoEnabled bool, autoEnabledClaGroupID string, enabled bool) error {
+	ctx, span := telemetry.StartSpan(ctx, "gitlab_organizations.repository.UpdateGitLabGroup")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":          "v1.gitlab_organizations.repository.UpdateGitLabGroup",
+		utils.XREQUESTID:        ctx.Value(utils.XREQUESTID),
+		"groupFullPath":         groupFullPath,
+		"autoEnabled":           autoEnabled,
+		"autoEnabledClaGroupID": autoEnabledClaGroupID,
+		"enabled":               enabled,
+	}
+
+	_, currentTime := utils.CurrentTime()
+	update := expression.Set(expression.Name("auto_enabled"), expression.Value(autoEnabled)).
+		Set(expression.Name("auto_enabled_cla_group_id"), expression.Value(autoEnabledClaGroupID)).
+		Set(expression.Name("enabled"), expression.Value(enabled)).
+		Set(expression.Name("date_modified"), expression.Value(currentTime))
+	condition := expression.AttributeExists(expression.Name("group_full_path_lower"))
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(condition).Build()
+	if err != nil {
+		return err
+	}
+
+	_, err = repo.dynamoDBClient.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"group_full_path_lower": {S: aws.String(strings.ToLower(groupFullPath))},
+		},
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		TableName:                 aws.String(repo.gitlabOrgTableName),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return ErrGroupDoesNotExist
+		}
+		log.WithFields(f).WithError(err).Warn("error updating gitlab group")
+		return err
+	}
+
+	return nil
+}
+
+// DeleteGitLabGroup disables the gitlab group
+func (repo Repository) DeleteGitLabGroup(ctx context.Context, groupFullPath string) error {
+	ctx, span := telemetry.StartSpan(ctx, "gitlab_organizations.repository.DeleteGitLabGroup")
+	defer span.End()
+
+	group, err := repo.GetGitLabGroup(ctx, groupFullPath)
+	if err != nil {
+		return err
+	}
+	return repo.UpdateGitLabGroup(ctx, group.GroupFullPath, group.AutoEnabled, group.AutoEnabledClaGroupID, false)
+}
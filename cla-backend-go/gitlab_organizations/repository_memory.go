@@ -0,0 +1,96 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package gitlab_organizations
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// memoryRepository is an embedded, in-memory implementation of the gitlab groups RepositoryInterface. It is intended
+// for local development and tests where DynamoDB is not available.
+type memoryRepository struct {
+	lock   sync.RWMutex
+	groups map[string]GitLabGroup
+}
+
+// NewMemoryRepository creates a new instance of the in-memory gitlab groups repository
+func NewMemoryRepository() RepositoryInterface {
+	return &memoryRepository{
+		groups: map[string]GitLabGroup{},
+	}
+}
+
+// AddGitLabGroup adds the gitlab group
+func (repo *memoryRepository) AddGitLabGroup(ctx context.Context, input *GitLabGroup) (*GitLabGroup, error) {
+	group := newGitLabGroup(input)
+
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	if _, exists := repo.groups[group.GroupFullPathLower]; exists {
+		return nil, ErrGroupExists
+	}
+	repo.groups[group.GroupFullPathLower] = *group
+
+	out := *group
+	return &out, nil
+}
+
+// GetGitLabGroup returns the gitlab group by its full path - the lookup is case insensitive
+func (repo *memoryRepository) GetGitLabGroup(ctx context.Context, groupFullPath string) (*GitLabGroup, error) {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	group, ok := repo.groups[strings.ToLower(groupFullPath)]
+	if !ok {
+		return nil, ErrGroupDoesNotExist
+	}
+	return &group, nil
+}
+
+// GetGitLabGroups returns the enabled gitlab groups of the project, ordered by full path
+func (repo *memoryRepository) GetGitLabGroups(ctx context.Context, projectSFID string) ([]*GitLabGroup, error) {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	var groups []*GitLabGroup
+	for _, group := range repo.groups {
+		if group.ProjectSFID == projectSFID && group.Enabled {
+			g := group
+			groups = append(groups, &g)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].GroupFullPathLower < groups[j].GroupFullPathLower
+	})
+	return groups, nil
+}
+
+// UpdateGitLabGroup updates the auto enable and enabled flags of the gitlab group
+func (repo *memoryRepository) UpdateGitLabGroup(ctx context.Context, groupFullPath string, autoEnabled bool, autoEnabledClaGroupID string, enabled bool) error {
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	key := strings.ToLower(groupFullPath)
+	group, ok := repo.groups[key]
+	if !ok {
+		return ErrGroupDoesNotExist
+	}
+	_, group.DateModified = utils.CurrentTime()
+	group.AutoEnabled = autoEnabled
+	group.AutoEnabledClaGroupID = autoEnabledClaGroupID
+	group.Enabled = enabled
+	repo.groups[key] = group
+	return nil
+}
+
+// DeleteGitLabGroup disables the gitlab group
+func (repo *memoryRepository) DeleteGitLabGroup(ctx context.Context, groupFullPath string) error {
+	group, err := repo.GetGitLabGroup(ctx, groupFullPath)
+	if err != nil {
+		return err
+	}
+	return repo.UpdateGitLabGroup(ctx, group.GroupFullPath, group.AutoEnabled, group.AutoEnabledClaGroupID, false)
+}
@@ -0,0 +1,135 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/sns"
+)
+
+// DefaultCheckTimeout is the timeout of the checks which don't set one
+const DefaultCheckTimeout = 3 * time.Second
+
+// Check is a readiness check of a dependency of the service
+type Check struct {
+	// Name identifies the dependency in the health report
+	Name string
+	// Timeout of a single run, DefaultCheckTimeout when not set
+	Timeout time.Duration
+	// CacheTTL is how long the result is reused, the TTL of the service when not set
+	CacheTTL time.Duration
+	// Run returns nil when the dependency is available
+	Run func(ctx context.Context) error
+}
+
+// DynamoDBTableChecks returns the checks of the EasyCLA tables of the stage
+func DynamoDBTableChecks(awsSession *session.Session, stage string) []Check {
+	tableNames := []string{
+		"cla-" + stage + "-ccla-whitelist-requests",
+		"cla-" + stage + "-cla-manager-requests",
+		"cla-" + stage + "-companies",
+		"cla-" + stage + "-company-invites",
+		"cla-" + stage + "-events",
+		"cla-" + stage + "-gerrit-instances",
+		"cla-" + stage + "-github-orgs",
+		"cla-" + stage + "-metrics",
+		"cla-" + stage + "-projects",
+		"cla-" + stage + "-projects-cla-groups",
+		"cla-" + stage + "-repositories",
+		"cla-" + stage + "-session-store",
+		"cla-" + stage + "-signatures",
+		"cla-" + stage + "-store",
+		"cla-" + stage + "-user-permissions",
+		"cla-" + stage + "-users",
+	}
+
+	dynamoDBClient := dynamodb.New(awsSession)
+	checks := make([]Check, 0, len(tableNames))
+	for _, tableName := range tableNames {
+		tableName := tableName
+		checks = append(checks, Check{
+			Name: "EasyCLA - Dynamodb - " + tableName,
+			Run: func(ctx context.Context) error {
+				// Don't worry about the result - just check the error response
+				_, err := dynamoDBClient.DescribeTableWithContext(ctx, &dynamodb.DescribeTableInput{
+					TableName: aws.String(tableName),
+				})
+				return err
+			},
+		})
+	}
+	return checks
+}
+
+// S3BucketCheck returns the check of the access to the bucket
+func S3BucketCheck(awsSession *session.Session, bucket string) Check {
+	s3Client := s3.New(awsSession)
+	return Check{
+		Name: "EasyCLA - S3 - " + bucket,
+		Run: func(ctx context.Context) error {
+			_, err := s3Client.HeadBucketWithContext(ctx, &s3.HeadBucketInput{
+				Bucket: aws.String(bucket),
+			})
+			return err
+		},
+	}
+}
+
+// SNSTopicCheck returns the check of the access to the topic
+func SNSTopicCheck(awsSession *session.Session, topicARN string) Check {
+	snsClient := sns.New(awsSession)
+	return Check{
+		Name: "EasyCLA - SNS - " + topicARN,
+		Run: func(ctx context.Context) error {
+			_, err := snsClient.GetTopicAttributesWithContext(ctx, &sns.GetTopicAttributesInput{
+				TopicArn: aws.String(topicARN),
+			})
+			return err
+		},
+	}
+}
+
+// Auth0JWKSCheck returns the check of the JWKS endpoint the Auth0 tokens are verified with
+func Auth0JWKSCheck(domain string) Check {
+	jwksURL := "https://" + path.Join(domain, ".well-known/jwks.json")
+	return Check{
+		Name: "EasyCLA - Auth0 - JWKS",
+		Run: func(ctx context.Context) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURL, nil)
+			if err != nil {
+				return err
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close() // nolint
+
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("unexpected JWKS response status: %s", resp.Status)
+			}
+			var keySet struct {
+				Keys []json.RawMessage `json:"keys"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&keySet); err != nil {
+				return err
+			}
+			if len(keySet.Keys) == 0 {
+				return errors.New("the JWKS has no keys")
+			}
+			return nil
+		},
+	}
+}
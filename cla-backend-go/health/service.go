@@ -5,64 +5,98 @@ package health
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
-	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/sirupsen/logrus"
+
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
-	ini "github.com/communitybridge/easycla/cla-backend-go/init"
+	platform_client "github.com/communitybridge/easycla/cla-backend-go/v2/platform-client"
+)
+
+// health status values
+const (
+	StatusHealthy    = "healthy"
+	StatusNotHealthy = "not healthy"
 )
 
+// DefaultCacheTTL is how long the check results are reused, the load balancers poll more often than that
+const DefaultCacheTTL = 30 * time.Second
+
 // Service provides an API to the health API
 type Service struct {
 	version   string
 	commit    string
 	branch    string
 	buildDate string
+	checks    []Check
+	results   *resultCache
 }
 
 // HealthService interface
 type HealthService interface { // nolint
 	HealthCheck(ctx context.Context) (*models.Health, error)
+	Liveness(ctx context.Context) (*models.Health, error)
+	Readiness(ctx context.Context) (*models.Health, error)
 }
 
-// New is a simple helper function to create a health service instance
-func New(version, commit, branch, buildDate string) Service {
+// New is a simple helper function to create a health service instance, the readiness reports the checks
+func New(version, commit, branch, buildDate string, checks ...Check) Service {
 	return Service{
 		version:   version,
 		commit:    commit,
 		branch:    branch,
 		buildDate: buildDate,
+		checks:    checks,
+		results:   newResultCache(DefaultCacheTTL, time.Now),
 	}
 }
 
-// HealthCheck API call returns the current health of the service
+// HealthCheck API call returns the current health of the service - the readiness report
 func (s Service) HealthCheck(ctx context.Context) (*models.Health, error) {
+	return s.Readiness(ctx)
+}
 
-	// General health
-	hs := models.HealthStatus{
-		TimeStamp: time.Now().UTC().Format(time.RFC3339),
-		Healthy:   true,
-		Name:      "CLA",
-		Duration:  time.Since(time.Now()).String(),
+// Liveness returns the health of the service process, the dependencies are not checked
+func (s Service) Liveness(ctx context.Context) (*models.Health, error) {
+	return s.report([]*models.HealthStatus{generalStatus()}), nil
+}
+
+// Readiness returns the health of the service and its dependencies, the check results are cached
+func (s Service) Readiness(ctx context.Context) (*models.Health, error) {
+	allStatus := make([]*models.HealthStatus, len(s.checks))
+
+	var wg sync.WaitGroup
+	wg.Add(len(s.checks))
+	for i, check := range s.checks {
+		go func(i int, check Check) {
+			defer wg.Done()
+			status := s.results.get(ctx, check)
+			allStatus[i] = &status
+		}(i, check)
 	}
+	wg.Wait()
 
-	var allStatus []*models.HealthStatus
-	allStatus = append(allStatus, &hs)
-	allStatus = append(allStatus, getDynamoTableStatus()...)
+	allStatus = append([]*models.HealthStatus{generalStatus()}, allStatus...)
+	allStatus = append(allStatus, getPlatformDependencyStatus()...)
+	return s.report(allStatus), nil
+}
 
-	var status = "healthy"
+// report returns the health report of the statuses, healthy when all the statuses are
+func (s Service) report(allStatus []*models.HealthStatus) *models.Health {
+	var status = StatusHealthy
 	for _, item := range allStatus {
-		// If any of our dynamodb tables are not healthy, then overall we are not healthy
+		// If any of our dependencies are not healthy, then overall we are not healthy
 		if !item.Healthy {
-			status = "not healthy"
+			status = StatusNotHealthy
 			break
 		}
 	}
 
-	response := models.Health{
+	return &models.Health{
 		Status:         status,
 		TimeStamp:      time.Now().UTC().Format(time.RFC3339),
 		Version:        s.version,
@@ -71,73 +105,136 @@ func (s Service) HealthCheck(ctx context.Context) (*models.Health, error) {
 		BuildTimeStamp: s.buildDate,
 		Healths:        allStatus,
 	}
+}
 
-	return &response, nil
+// generalStatus returns the status of the service process
+func generalStatus() *models.HealthStatus {
+	return &models.HealthStatus{
+		TimeStamp: time.Now().UTC().Format(time.RFC3339),
+		Healthy:   true,
+		Name:      "CLA",
+		Duration:  time.Since(time.Now()).String(),
+	}
 }
 
-// getDynamoTableStatus queries the dynamodb tables and reports if it is healthy
-func getDynamoTableStatus() []*models.HealthStatus {
+// getPlatformDependencyStatus reports the circuit breaker state of the platform services - a dependency is not
+// healthy while its breaker rejects the calls
+func getPlatformDependencyStatus() []*models.HealthStatus {
 	var allStatus []*models.HealthStatus
-
-	tableNames := []string{
-		"cla-" + ini.GetStage() + "-ccla-whitelist-requests",
-		"cla-" + ini.GetStage() + "-cla-manager-requests",
-		"cla-" + ini.GetStage() + "-companies",
-		"cla-" + ini.GetStage() + "-company-invites",
-		"cla-" + ini.GetStage() + "-events",
-		"cla-" + ini.GetStage() + "-gerrit-instances",
-		"cla-" + ini.GetStage() + "-github-orgs",
-		"cla-" + ini.GetStage() + "-metrics",
-		"cla-" + ini.GetStage() + "-projects",
-		"cla-" + ini.GetStage() + "-projects-cla-groups",
-		"cla-" + ini.GetStage() + "-repositories",
-		"cla-" + ini.GetStage() + "-session-store",
-		"cla-" + ini.GetStage() + "-signatures",
-		"cla-" + ini.GetStage() + "-store",
-		"cla-" + ini.GetStage() + "-user-permissions",
-		"cla-" + ini.GetStage() + "-users",
+	for _, dependency := range platform_client.Statuses() {
+		start := time.Now()
+		status := models.HealthStatus{
+			TimeStamp: time.Now().UTC().Format(time.RFC3339),
+			Healthy:   dependency.Healthy(),
+			Name:      "EasyCLA - Platform - " + dependency.Name,
+			Duration:  time.Since(start).String(),
+		}
+		if dependency.State != platform_client.StateClosed {
+			status.Error = fmt.Sprintf("circuit breaker %s after %d consecutive failures, last error: %s",
+				dependency.State, dependency.ConsecutiveFailures, dependency.LastError)
+		}
+		allStatus = append(allStatus, &status)
 	}
+	return allStatus
+}
 
-	var wg sync.WaitGroup
-	wg.Add(len(tableNames))
+// cachedResult is the status of a check with the time it was checked
+type cachedResult struct {
+	status    models.HealthStatus
+	checkedAt time.Time
+}
 
-	for _, tableName := range tableNames {
-		go func(tableName string) {
-			defer wg.Done()
+// resultCache keeps the check results for the TTL, a check is run once at a time - the concurrent requests wait
+// for the running check
+type resultCache struct {
+	lock     sync.Mutex
+	ttl      time.Duration
+	now      func() time.Time
+	results  map[string]cachedResult
+	inFlight map[string]chan struct{}
+}
 
-			// Do a quick check to see if we have a database connection
-			dynamoNow := time.Now()
-			dynamoAlive := isDynamoAlive(tableName)
-			dynamoDuration := time.Since(dynamoNow)
-			dy := models.HealthStatus{TimeStamp: time.Now().UTC().Format(time.RFC3339),
-				Healthy:  dynamoAlive,
-				Name:     "EasyCLA - Dynamodb - " + tableName,
-				Duration: dynamoDuration.String()}
-
-			allStatus = append(allStatus, &dy)
-		}(tableName)
+// newResultCache creates an empty cache
+func newResultCache(ttl time.Duration, now func() time.Time) *resultCache {
+	return &resultCache{
+		ttl:      ttl,
+		now:      now,
+		results:  map[string]cachedResult{},
+		inFlight: map[string]chan struct{}{},
 	}
+}
 
-	wg.Wait()
+// get returns the cached status of the check, the check is run when the status expired
+func (c *resultCache) get(ctx context.Context, check Check) models.HealthStatus {
+	ttl := check.CacheTTL
+	if ttl == 0 {
+		ttl = c.ttl
+	}
 
-	return allStatus
+	for {
+		c.lock.Lock()
+		result, ok := c.results[check.Name]
+		if ok && c.now().Sub(result.checkedAt) < ttl {
+			c.lock.Unlock()
+			return result.status
+		}
+		done, running := c.inFlight[check.Name]
+		if !running {
+			done = make(chan struct{})
+			c.inFlight[check.Name] = done
+			c.lock.Unlock()
+			break
+		}
+		c.lock.Unlock()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return models.HealthStatus{
+				TimeStamp: c.now().UTC().Format(time.RFC3339),
+				Name:      check.Name,
+				Error:     ctx.Err().Error(),
+			}
+		}
+	}
+
+	status := runCheck(check)
+
+	c.lock.Lock()
+	c.results[check.Name] = cachedResult{status: status, checkedAt: c.now()}
+	close(c.inFlight[check.Name])
+	delete(c.inFlight, check.Name)
+	c.lock.Unlock()
+
+	return status
 }
 
-// isDynamoAlive runs a check to see if we have connectivity to the database for the given table - returns true if successful, false otherwise
-func isDynamoAlive(tableName string) bool {
-	// Grab the AWS session
-	awsSession, err := ini.GetAWSSession()
-	if err != nil {
-		log.Warnf("Unable to acquire AWS session - returning failed health, error: %v", err)
-		return false
+// runCheck runs the check with its timeout - the result is shared by the requests, so the check is not cancelled
+// with the request
+func runCheck(check Check) models.HealthStatus {
+	f := logrus.Fields{
+		"functionName": "v1.health.service.runCheck",
+		"check":        check.Name,
 	}
 
-	// Create a client and make a query - don't worry about the result - just check the error response
-	dynamoDBClient := dynamodb.New(awsSession)
-	_, err = dynamoDBClient.DescribeTable(&dynamodb.DescribeTableInput{
-		TableName: &tableName,
-	})
-
-	// No error is success
-	return err == nil
+	timeout := check.Timeout
+	if timeout == 0 {
+		timeout = DefaultCheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Run(ctx)
+	status := models.HealthStatus{
+		TimeStamp: start.UTC().Format(time.RFC3339),
+		Healthy:   err == nil,
+		Name:      check.Name,
+		Duration:  time.Since(start).String(),
+	}
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("health check failed")
+		status.Error = err.Error()
+	}
+	return status
 }
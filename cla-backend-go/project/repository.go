@@ -29,6 +29,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
 )
 
 // errors
@@ -82,6 +83,9 @@ type repo struct {
 
 // CreateCLAGroup creates a new CLA Group
 func (repo *repo) CreateCLAGroup(ctx context.Context, claGroupModel *models.ClaGroup) (*models.ClaGroup, error) {
+	ctx, span := telemetry.StartSpan(ctx, "project.repository.CreateCLAGroup")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":      "project.repository.CreateCLAGroup",
 		utils.XREQUESTID:    ctx.Value(utils.XREQUESTID),
@@ -132,7 +136,7 @@ func (repo *repo) CreateCLAGroup(ctx context.Context, claGroupModel *models.ClaG
 	}
 	addStringAttribute(input.Item, "version", claGroupModel.Version)
 
-	_, err = repo.dynamoDBClient.PutItem(input)
+	_, err = repo.dynamoDBClient.PutItemWithContext(ctx, input)
 	if err != nil {
 		log.WithFields(f).Warnf("Unable to create a new CLA Group record, error: %v", err)
 		return nil, err
@@ -175,7 +179,7 @@ func (repo *repo) getCLAGroupByID(ctx context.Context, claGroupID string, loadCL
 	}
 
 	// Make the DynamoDB Query API call
-	results, queryErr := repo.dynamoDBClient.Query(queryInput)
+	results, queryErr := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if queryErr != nil {
 		log.WithFields(f).Warnf("error retrieving cla group by claGroupID: %s, error: %v", claGroupID, queryErr)
 		return nil, queryErr
@@ -192,16 +196,22 @@ func (repo *repo) getCLAGroupByID(ctx context.Context, claGroupID string, loadCL
 	}
 
 	// Convert the database model to an API response model
-	return repo.buildCLAGroupModel(ctx, dbModel, loadCLAGroupDetails), nil
+	return buildCLAGroupModel(ctx, repo.ghRepo, repo.gerritRepo, dbModel, loadCLAGroupDetails), nil
 }
 
 // GetCLAGroupByID returns the cla group model associated for the specified claGroupID
 func (repo *repo) GetCLAGroupByID(ctx context.Context, claGroupID string, loadRepoDetails bool) (*models.ClaGroup, error) {
+	ctx, span := telemetry.StartSpan(ctx, "project.repository.GetCLAGroupByID")
+	defer span.End()
+
 	return repo.getCLAGroupByID(ctx, claGroupID, loadRepoDetails)
 }
 
 // GetCLAGroupsByExternalID queries the database and returns a list of the cla groups
 func (repo *repo) GetCLAGroupsByExternalID(ctx context.Context, params *project.GetProjectsByExternalIDParams, loadRepoDetails bool) (*models.ClaGroups, error) {
+	ctx, span := telemetry.StartSpan(ctx, "project.repository.GetCLAGroupsByExternalID")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":    "project.repository.GetCLAGroupsByExternalID",
 		utils.XREQUESTID:  ctx.Value(utils.XREQUESTID),
@@ -259,7 +269,7 @@ func (repo *repo) GetCLAGroupsByExternalID(ctx context.Context, params *project.
 
 	// Loop until we have all the records
 	for ok := true; ok; ok = lastEvaluatedKey != "" {
-		results, errQuery := repo.dynamoDBClient.Query(queryInput)
+		results, errQuery := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 		if errQuery != nil {
 			log.WithFields(f).Warnf("error retrieving projects, error: %v", errQuery)
 			return nil, errQuery
@@ -302,6 +312,9 @@ func (repo *repo) GetCLAGroupsByExternalID(ctx context.Context, params *project.
 
 // GetClaGroupsByFoundationID queries the database and returns a list of all cla_groups associated with foundation
 func (repo *repo) GetClaGroupsByFoundationSFID(ctx context.Context, foundationSFID string, loadRepoDetails bool) (*models.ClaGroups, error) {
+	ctx, span := telemetry.StartSpan(ctx, "project.repository.GetClaGroupsByFoundationSFID")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":    "project.repository.GetClaGroupsByFoundationSFID",
 		utils.XREQUESTID:  ctx.Value(utils.XREQUESTID),
@@ -332,7 +345,7 @@ func (repo *repo) GetClaGroupsByFoundationSFID(ctx context.Context, foundationSF
 
 	var projects []models.ClaGroup
 	for {
-		results, errQuery := repo.dynamoDBClient.Query(queryInput)
+		results, errQuery := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 		if errQuery != nil {
 			log.WithFields(f).Warnf("error retrieving projects, error: %v", errQuery)
 			return nil, errQuery
@@ -364,6 +377,9 @@ func (repo *repo) GetClaGroupsByFoundationSFID(ctx context.Context, foundationSF
 
 // GetClaGroupByProjectSFID returns cla_group associated with project
 func (repo *repo) GetClaGroupByProjectSFID(ctx context.Context, projectSFID string, loadRepoDetails bool) (*models.ClaGroup, error) {
+	ctx, span := telemetry.StartSpan(ctx, "project.repository.GetClaGroupByProjectSFID")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":    "project.repository.GetClaGroupByProjectSFID",
 		utils.XREQUESTID:  ctx.Value(utils.XREQUESTID),
@@ -383,6 +399,9 @@ func (repo *repo) GetClaGroupByProjectSFID(ctx context.Context, projectSFID stri
 
 // GetCLAGroupByName returns the project model associated for the specified project name
 func (repo *repo) GetCLAGroupByName(ctx context.Context, projectName string) (*models.ClaGroup, error) {
+	ctx, span := telemetry.StartSpan(ctx, "project.repository.GetCLAGroupByName")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "project.repository.GetCLAGroupByName",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -412,7 +431,7 @@ func (repo *repo) GetCLAGroupByName(ctx context.Context, projectName string) (*m
 	}
 
 	// Make the DynamoDB Query API call
-	results, queryErr := repo.dynamoDBClient.Query(queryInput)
+	results, queryErr := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if queryErr != nil {
 		log.WithFields(f).Warnf("error retrieving project by projectName: %s, error: %v", projectName, queryErr)
 		return nil, queryErr
@@ -438,11 +457,14 @@ func (repo *repo) GetCLAGroupByName(ctx context.Context, projectName string) (*m
 	}
 
 	// Convert the database model to an API response model
-	return repo.buildCLAGroupModel(ctx, dbModel, LoadRepoDetails), nil
+	return buildCLAGroupModel(ctx, repo.ghRepo, repo.gerritRepo, dbModel, LoadRepoDetails), nil
 }
 
 // GetExternalCLAGroup returns the project model associated for the specified external project ID
 func (repo *repo) GetExternalCLAGroup(ctx context.Context, projectExternalID string) (*models.ClaGroup, error) {
+	ctx, span := telemetry.StartSpan(ctx, "project.repository.GetExternalCLAGroup")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":      "project.repository.GetExternalCLAGroup",
 		utils.XREQUESTID:    ctx.Value(utils.XREQUESTID),
@@ -471,7 +493,7 @@ func (repo *repo) GetExternalCLAGroup(ctx context.Context, projectExternalID str
 	}
 
 	// Make the DynamoDB Query API call
-	results, queryErr := repo.dynamoDBClient.Query(queryInput)
+	results, queryErr := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if queryErr != nil {
 		log.WithFields(f).Warnf("error retrieving project by projectExternalID: %s, error: %v", projectExternalID, queryErr)
 		return nil, queryErr
@@ -495,11 +517,14 @@ func (repo *repo) GetExternalCLAGroup(ctx context.Context, projectExternalID str
 	}
 
 	// Convert the database model to an API response model
-	return repo.buildCLAGroupModel(ctx, dbModel, LoadRepoDetails), nil
+	return buildCLAGroupModel(ctx, repo.ghRepo, repo.gerritRepo, dbModel, LoadRepoDetails), nil
 }
 
 // GetCLAGroups queries the database and returns a list of the projects
 func (repo *repo) GetCLAGroups(ctx context.Context, params *project.GetProjectsParams) (*models.ClaGroups, error) {
+	ctx, span := telemetry.StartSpan(ctx, "project.repository.GetCLAGroups")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "project.repository.GetCLAGroups",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -555,7 +580,7 @@ func (repo *repo) GetCLAGroups(ctx context.Context, params *project.GetProjectsP
 
 	// Loop until we have all the records
 	for ok := true; ok; ok = lastEvaluatedKey != "" {
-		results, errQuery := repo.dynamoDBClient.Scan(scanInput)
+		results, errQuery := repo.dynamoDBClient.ScanWithContext(ctx, scanInput)
 		if errQuery != nil {
 			log.WithFields(f).Warnf("error retrieving projects, error: %v", errQuery)
 			return nil, errQuery
@@ -597,6 +622,9 @@ func (repo *repo) GetCLAGroups(ctx context.Context, params *project.GetProjectsP
 
 // DeleteCLAGroup deletes the CLAGroup by claGroupID
 func (repo *repo) DeleteCLAGroup(ctx context.Context, claGroupID string) error {
+	ctx, span := telemetry.StartSpan(ctx, "project.repository.DeleteCLAGroup")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "project.repository.DeleteCLAGroup",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -617,7 +645,7 @@ func (repo *repo) DeleteCLAGroup(ctx context.Context, claGroupID string) error {
 
 	var deleteErr error
 	// Perform the delete
-	_, deleteErr = repo.dynamoDBClient.DeleteItem(&dynamodb.DeleteItemInput{
+	_, deleteErr = repo.dynamoDBClient.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
 		TableName: aws.String(repo.claGroupTable),
 		Key: map[string]*dynamodb.AttributeValue{
 			"project_id": {
@@ -636,6 +664,9 @@ func (repo *repo) DeleteCLAGroup(ctx context.Context, claGroupID string) error {
 
 // UpdateCLAGroup updates the project by claGroupID
 func (repo *repo) UpdateCLAGroup(ctx context.Context, claGroupModel *models.ClaGroup) (*models.ClaGroup, error) {
+	ctx, span := telemetry.StartSpan(ctx, "project.repository.UpdateCLAGroup")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":            "project.repository.UpdateCLAGroup",
 		utils.XREQUESTID:          ctx.Value(utils.XREQUESTID),
@@ -751,7 +782,7 @@ func (repo *repo) UpdateCLAGroup(ctx context.Context, claGroupModel *models.ClaG
 	//log.Debugf("Update input: %+V", updateInput.GoString())
 
 	// Make the DynamoDB Update API call
-	_, updateErr := repo.dynamoDBClient.UpdateItem(updateInput)
+	_, updateErr := repo.dynamoDBClient.UpdateItemWithContext(ctx, updateInput)
 	if updateErr != nil {
 		log.WithFields(f).Warnf("error updating CLAGroup by claGroupID: %s, error: %v", claGroupModel.ProjectID, updateErr)
 		return nil, updateErr
@@ -764,6 +795,9 @@ func (repo *repo) UpdateCLAGroup(ctx context.Context, claGroupModel *models.ClaG
 }
 
 func (repo *repo) UpdateRootCLAGroupRepositoriesCount(ctx context.Context, claGroupID string, diff int64, reset bool) error {
+	ctx, span := telemetry.StartSpan(ctx, "project.repository.UpdateRootCLAGroupRepositoriesCount")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "project.repository.UpdateRootCLAGroupRepositoriesCount",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -804,7 +838,7 @@ func (repo *repo) UpdateRootCLAGroupRepositoriesCount(ctx context.Context, claGr
 		TableName: aws.String(repo.claGroupTable),
 	}
 
-	_, err := repo.dynamoDBClient.UpdateItem(input)
+	_, err := repo.dynamoDBClient.UpdateItemWithContext(ctx, input)
 	if err != nil {
 		log.WithFields(f).WithError(err).Warn("unable to update repositories count")
 	}
@@ -832,7 +866,7 @@ func (repo *repo) buildCLAGroupModels(ctx context.Context, results []map[string]
 	for _, dbProject := range dbProjects {
 		go func(dbProject DBProjectModel) {
 			// Send the results to the output channel
-			responseChannel <- repo.buildCLAGroupModel(ctx, dbProject, loadRepoDetails)
+			responseChannel <- buildCLAGroupModel(ctx, repo.ghRepo, repo.gerritRepo, dbProject, loadRepoDetails)
 		}(dbProject)
 	}
 
@@ -845,7 +879,7 @@ func (repo *repo) buildCLAGroupModels(ctx context.Context, results []map[string]
 }
 
 // buildCLAGroupModel maps the database model to the API response model
-func (repo *repo) buildCLAGroupModel(ctx context.Context, dbModel DBProjectModel, loadRepoDetails bool) *models.ClaGroup {
+func buildCLAGroupModel(ctx context.Context, ghRepo repositories.Repository, gerritRepo gerrits.Repository, dbModel DBProjectModel, loadRepoDetails bool) *models.ClaGroup {
 
 	var ghOrgs []*models.GithubRepositoriesGroupByOrgs
 	var gerrits []*models.Gerrit
@@ -857,7 +891,7 @@ func (repo *repo) buildCLAGroupModel(ctx context.Context, dbModel DBProjectModel
 			go func() {
 				defer wg.Done()
 				var err error
-				ghOrgs, err = repo.ghRepo.GetCLAGroupRepositoriesGroupByOrgs(ctx, dbModel.ProjectID, true)
+				ghOrgs, err = ghRepo.GetCLAGroupRepositoriesGroupByOrgs(ctx, dbModel.ProjectID, true)
 				if err != nil {
 					log.Warnf("buildPCLAGroupModel - unable to load GH organizations by project ID: %s, error: %+v",
 						dbModel.ProjectID, err)
@@ -870,7 +904,7 @@ func (repo *repo) buildCLAGroupModel(ctx context.Context, dbModel DBProjectModel
 				defer wg.Done()
 				var err error
 				var gerritsList *models.GerritList
-				gerritsList, err = repo.gerritRepo.GetClaGroupGerrits(ctx, dbModel.ProjectID)
+				gerritsList, err = gerritRepo.GetClaGroupGerrits(ctx, dbModel.ProjectID)
 				if err != nil {
 					log.Warnf("buildCLAGroupModel - unable to load Gerrit repositories by project ID: %s, error: %+v",
 						dbModel.ProjectID, err)
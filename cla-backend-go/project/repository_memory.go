@@ -0,0 +1,266 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/restapi/operations/project"
+	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
+	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/gofrs/uuid"
+)
+
+// memoryRepository is an embedded, in-memory implementation of the ProjectRepository. It is intended for local
+// development and tests where DynamoDB is not available.
+type memoryRepository struct {
+	lock                sync.RWMutex
+	claGroups           map[string]DBProjectModel
+	ghRepo              repositories.Repository
+	gerritRepo          gerrits.Repository
+	projectClaGroupRepo projects_cla_groups.Repository
+}
+
+// NewMemoryRepository creates a new instance of the in-memory project repository
+func NewMemoryRepository(ghRepo repositories.Repository, gerritRepo gerrits.Repository, projectClaGroupRepo projects_cla_groups.Repository) ProjectRepository {
+	return &memoryRepository{
+		claGroups:           map[string]DBProjectModel{},
+		ghRepo:              ghRepo,
+		gerritRepo:          gerritRepo,
+		projectClaGroupRepo: projectClaGroupRepo,
+	}
+}
+
+// CreateCLAGroup creates a new CLA Group
+func (repo *memoryRepository) CreateCLAGroup(ctx context.Context, claGroupModel *models.ClaGroup) (*models.ClaGroup, error) {
+	claGroupID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+
+	_, currentTimeString := utils.CurrentTime()
+	if claGroupModel.Version == "" {
+		claGroupModel.Version = utils.V1 // default value
+	}
+
+	repo.lock.Lock()
+	repo.claGroups[claGroupID.String()] = DBProjectModel{
+		ProjectID:                        claGroupID.String(),
+		ProjectExternalID:                claGroupModel.ProjectExternalID,
+		FoundationSFID:                   claGroupModel.FoundationSFID,
+		ProjectDescription:               claGroupModel.ProjectDescription,
+		ProjectName:                      claGroupModel.ProjectName,
+		ProjectNameLower:                 strings.ToLower(claGroupModel.ProjectName),
+		ProjectTemplateID:                claGroupModel.ProjectTemplateID,
+		ProjectACL:                       append([]string{}, claGroupModel.ProjectACL...),
+		ProjectIclaEnabled:               claGroupModel.ProjectICLAEnabled,
+		ProjectCclaEnabled:               claGroupModel.ProjectCCLAEnabled,
+		ProjectCclaRequiresIclaSignature: claGroupModel.ProjectCCLARequiresICLA,
+		ProjectLive:                      claGroupModel.ProjectLive,
+		ProjectCorporateDocuments:        []DBProjectDocumentModel{},
+		ProjectIndividualDocuments:       []DBProjectDocumentModel{},
+		ProjectMemberDocuments:           []DBProjectDocumentModel{},
+		DateCreated:                      currentTimeString,
+		DateModified:                     currentTimeString,
+		Version:                          claGroupModel.Version,
+	}
+	repo.lock.Unlock()
+
+	// Re-use the provided model - just update the dynamically assigned values
+	claGroupModel.ProjectID = claGroupID.String()
+	claGroupModel.DateCreated = currentTimeString
+	claGroupModel.DateModified = currentTimeString
+	return claGroupModel, nil
+}
+
+// GetCLAGroupByID returns the cla group model associated for the specified claGroupID
+func (repo *memoryRepository) GetCLAGroupByID(ctx context.Context, claGroupID string, loadRepoDetails bool) (*models.ClaGroup, error) {
+	repo.lock.RLock()
+	dbModel, ok := repo.claGroups[claGroupID]
+	repo.lock.RUnlock()
+	if !ok {
+		return nil, &utils.CLAGroupNotFound{CLAGroupID: claGroupID}
+	}
+	return buildCLAGroupModel(ctx, repo.ghRepo, repo.gerritRepo, dbModel, loadRepoDetails), nil
+}
+
+// GetCLAGroupsByExternalID returns a page of the cla groups with the matching project SFID
+func (repo *memoryRepository) GetCLAGroupsByExternalID(ctx context.Context, params *project.GetProjectsByExternalIDParams, loadRepoDetails bool) (*models.ClaGroups, error) {
+	dbModels := repo.query(func(p *DBProjectModel) bool {
+		return p.ProjectExternalID == params.ProjectSFID
+	})
+	return repo.pageCLAGroups(ctx, dbModels, params.NextKey, params.PageSize, loadRepoDetails), nil
+}
+
+// GetCLAGroupByName returns the project model associated for the specified project name
+func (repo *memoryRepository) GetCLAGroupByName(ctx context.Context, claGroupName string) (*models.ClaGroup, error) {
+	dbModels := repo.query(func(p *DBProjectModel) bool {
+		return p.ProjectNameLower == strings.ToLower(claGroupName)
+	})
+	if len(dbModels) == 0 {
+		return nil, nil
+	}
+	return buildCLAGroupModel(ctx, repo.ghRepo, repo.gerritRepo, dbModels[0], LoadRepoDetails), nil
+}
+
+// GetExternalCLAGroup returns the project model associated for the specified external project ID
+func (repo *memoryRepository) GetExternalCLAGroup(ctx context.Context, claGroupExternalID string) (*models.ClaGroup, error) {
+	dbModels := repo.query(func(p *DBProjectModel) bool {
+		return p.ProjectExternalID == claGroupExternalID
+	})
+	if len(dbModels) == 0 {
+		return nil, nil
+	}
+	return buildCLAGroupModel(ctx, repo.ghRepo, repo.gerritRepo, dbModels[0], LoadRepoDetails), nil
+}
+
+// GetCLAGroups returns a page of all the cla groups
+func (repo *memoryRepository) GetCLAGroups(ctx context.Context, params *project.GetProjectsParams) (*models.ClaGroups, error) {
+	dbModels := repo.query(func(p *DBProjectModel) bool {
+		return true
+	})
+	return repo.pageCLAGroups(ctx, dbModels, params.NextKey, params.PageSize, LoadRepoDetails), nil
+}
+
+// DeleteCLAGroup deletes the CLAGroup by claGroupID
+func (repo *memoryRepository) DeleteCLAGroup(ctx context.Context, claGroupID string) error {
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	if _, ok := repo.claGroups[claGroupID]; !ok {
+		return &utils.CLAGroupNotFound{CLAGroupID: claGroupID}
+	}
+	delete(repo.claGroups, claGroupID)
+	return nil
+}
+
+// UpdateCLAGroup updates the project by claGroupID
+func (repo *memoryRepository) UpdateCLAGroup(ctx context.Context, claGroupModel *models.ClaGroup) (*models.ClaGroup, error) {
+	if claGroupModel.ProjectID == "" {
+		return nil, ErrProjectIDMissing
+	}
+
+	repo.lock.Lock()
+	dbModel, ok := repo.claGroups[claGroupModel.ProjectID]
+	if !ok {
+		repo.lock.Unlock()
+		return nil, &utils.CLAGroupNotFound{CLAGroupID: claGroupModel.ProjectID}
+	}
+
+	// We don't allow CLA Group templates to be changed - this requires a legal review
+	if dbModel.ProjectTemplateID != claGroupModel.ProjectTemplateID {
+		repo.lock.Unlock()
+		return nil, fmt.Errorf("problem updating CLA Group - changing CLA templates is not allowed - project: %s with ID: %s - previous template: %s updated template: %s",
+			claGroupModel.ProjectName, claGroupModel.ProjectID, dbModel.ProjectTemplateID, claGroupModel.ProjectTemplateID)
+	}
+
+	if claGroupModel.ProjectName != "" {
+		dbModel.ProjectName = claGroupModel.ProjectName
+		dbModel.ProjectNameLower = strings.ToLower(claGroupModel.ProjectName)
+	}
+	dbModel.ProjectDescription = claGroupModel.ProjectDescription
+	if len(claGroupModel.ProjectACL) > 0 {
+		dbModel.ProjectACL = append([]string{}, claGroupModel.ProjectACL...)
+	}
+	dbModel.ProjectIclaEnabled = claGroupModel.ProjectICLAEnabled
+	dbModel.ProjectCclaEnabled = claGroupModel.ProjectCCLAEnabled
+	dbModel.ProjectCclaRequiresIclaSignature = claGroupModel.ProjectCCLARequiresICLA
+	dbModel.ProjectLive = claGroupModel.ProjectLive
+	_, dbModel.DateModified = utils.CurrentTime()
+	repo.claGroups[dbModel.ProjectID] = dbModel
+	repo.lock.Unlock()
+
+	return repo.GetCLAGroupByID(ctx, claGroupModel.ProjectID, LoadRepoDetails)
+}
+
+// GetClaGroupsByFoundationSFID returns a list of all cla_groups associated with foundation
+func (repo *memoryRepository) GetClaGroupsByFoundationSFID(ctx context.Context, foundationSFID string, loadRepoDetails bool) (*models.ClaGroups, error) {
+	var projects []models.ClaGroup
+	for _, dbModel := range repo.query(func(p *DBProjectModel) bool {
+		return p.FoundationSFID == foundationSFID
+	}) {
+		projects = append(projects, *buildCLAGroupModel(ctx, repo.ghRepo, repo.gerritRepo, dbModel, loadRepoDetails))
+	}
+	return &models.ClaGroups{
+		ResultCount: int64(len(projects)),
+		Projects:    projects,
+	}, nil
+}
+
+// GetClaGroupByProjectSFID returns cla_group associated with project
+func (repo *memoryRepository) GetClaGroupByProjectSFID(ctx context.Context, projectSFID string, loadRepoDetails bool) (*models.ClaGroup, error) {
+	claGroupProject, err := repo.projectClaGroupRepo.GetClaGroupIDForProject(ctx, projectSFID)
+	if err != nil {
+		return nil, err
+	}
+	return repo.GetCLAGroupByID(ctx, claGroupProject.ClaGroupID, loadRepoDetails)
+}
+
+// UpdateRootCLAGroupRepositoriesCount adds the diff to the repositories count, or sets it to the diff if reset is true
+func (repo *memoryRepository) UpdateRootCLAGroupRepositoriesCount(ctx context.Context, claGroupID string, diff int64, reset bool) error {
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	dbModel, ok := repo.claGroups[claGroupID]
+	if !ok {
+		return &utils.CLAGroupNotFound{CLAGroupID: claGroupID}
+	}
+	if reset {
+		dbModel.RootProjectRepositoriesCount = diff
+		_, dbModel.DateModified = utils.CurrentTime()
+	} else {
+		dbModel.RootProjectRepositoriesCount += diff
+	}
+	repo.claGroups[claGroupID] = dbModel
+	return nil
+}
+
+// query returns the cla groups matching the filter, ordered by cla group ID
+func (repo *memoryRepository) query(match func(p *DBProjectModel) bool) []DBProjectModel {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	var dbModels []DBProjectModel
+	for _, dbModel := range repo.claGroups {
+		if match(&dbModel) {
+			dbModels = append(dbModels, dbModel)
+		}
+	}
+	sort.Slice(dbModels, func(i, j int) bool {
+		return dbModels[i].ProjectID < dbModels[j].ProjectID
+	})
+	return dbModels
+}
+
+// pageCLAGroups returns the page of cla groups following the next key cla group ID
+func (repo *memoryRepository) pageCLAGroups(ctx context.Context, dbModels []DBProjectModel, nextKey *string, pageSize *int64, loadRepoDetails bool) *models.ClaGroups {
+	limit := int64(50)
+	if pageSize != nil && *pageSize > 0 {
+		limit = *pageSize
+	}
+
+	var projects []models.ClaGroup
+	var lastEvaluatedKey string
+	for _, dbModel := range dbModels {
+		if nextKey != nil && *nextKey != "" && dbModel.ProjectID <= *nextKey {
+			continue
+		}
+		if int64(len(projects)) >= limit {
+			lastEvaluatedKey = projects[len(projects)-1].ProjectID
+			break
+		}
+		projects = append(projects, *buildCLAGroupModel(ctx, repo.ghRepo, repo.gerritRepo, dbModel, loadRepoDetails))
+	}
+
+	return &models.ClaGroups{
+		LastKeyScanned: lastEvaluatedKey,
+		PageSize:       limit,
+		ResultCount:    int64(len(projects)),
+		Projects:       projects,
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
 )
 
 // constants
@@ -99,7 +100,7 @@ func (repo *repo) queryClaGroupsProjects(ctx context.Context, keyCondition expre
 	var projectClaGroups []*ProjectClaGroup
 	for {
 		// log.WithFields(f).Debugf("running query using input: %+v", queryInput)
-		results, errQuery := repo.dynamoDBClient.Query(queryInput)
+		results, errQuery := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 		if errQuery != nil {
 			log.WithFields(f).Warnf("error retrieving project cla-groups, error: %v", errQuery)
 			return nil, errQuery
@@ -126,6 +127,9 @@ func (repo *repo) queryClaGroupsProjects(ctx context.Context, keyCondition expre
 
 // GetClaGroupIDForProject retrieves the CLA Group ID for the project
 func (repo *repo) GetClaGroupIDForProject(ctx context.Context, projectSFID string) (*ProjectClaGroup, error) {
+	ctx, span := telemetry.StartSpan(ctx, "projects_cla_groups.repository.GetClaGroupIDForProject")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "project_cla_groups.repository.GetClaGroupIDForProject",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -133,7 +137,7 @@ func (repo *repo) GetClaGroupIDForProject(ctx context.Context, projectSFID strin
 		"projectSFID":    projectSFID,
 	}
 
-	result, err := repo.dynamoDBClient.GetItem(&dynamodb.GetItemInput{
+	result, err := repo.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(repo.tableName),
 		Key: map[string]*dynamodb.AttributeValue{
 			"project_sfid": {
@@ -175,16 +179,25 @@ func (repo *repo) GetClaGroupIDForProject(ctx context.Context, projectSFID strin
 }
 
 func (repo *repo) GetProjectsIdsForClaGroup(ctx context.Context, claGroupID string) ([]*ProjectClaGroup, error) {
+	ctx, span := telemetry.StartSpan(ctx, "projects_cla_groups.repository.GetProjectsIdsForClaGroup")
+	defer span.End()
+
 	keyCondition := expression.Key("cla_group_id").Equal(expression.Value(claGroupID))
 	return repo.queryClaGroupsProjects(ctx, keyCondition, aws.String(CLAGroupIDIndex))
 }
 
 func (repo *repo) GetProjectsIdsForFoundation(ctx context.Context, foundationSFID string) ([]*ProjectClaGroup, error) {
+	ctx, span := telemetry.StartSpan(ctx, "projects_cla_groups.repository.GetProjectsIdsForFoundation")
+	defer span.End()
+
 	keyCondition := expression.Key("foundation_sfid").Equal(expression.Value(foundationSFID))
 	return repo.queryClaGroupsProjects(ctx, keyCondition, aws.String(FoundationSFIDIndex))
 }
 
 func (repo *repo) GetProjectsIdsForAllFoundation(ctx context.Context) ([]*ProjectClaGroup, error) {
+	ctx, span := telemetry.StartSpan(ctx, "projects_cla_groups.repository.GetProjectsIdsForAllFoundation")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "project_cla_groups.repository.GetProjectsIdsForAllFoundation",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -196,7 +209,7 @@ func (repo *repo) GetProjectsIdsForAllFoundation(ctx context.Context) ([]*Projec
 	}
 	var resultList []map[string]*dynamodb.AttributeValue
 	for {
-		results, err := repo.dynamoDBClient.Scan(scanInput) //nolint
+		results, err := repo.dynamoDBClient.ScanWithContext(ctx, scanInput) //nolint
 		if err != nil {
 			log.WithFields(f).Warnf("error retrieving %s, error: %v", repo.tableName, err)
 			return nil, err
@@ -219,6 +232,9 @@ func (repo *repo) GetProjectsIdsForAllFoundation(ctx context.Context) ([]*Projec
 
 // AssociateClaGroupWithProject creates entry in db to track cla_group association with project/foundation
 func (repo *repo) AssociateClaGroupWithProject(ctx context.Context, claGroupID string, projectSFID string, foundationSFID string) error {
+	ctx, span := telemetry.StartSpan(ctx, "projects_cla_groups.repository.AssociateClaGroupWithProject")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "project_cla_groups.repository.AssociateClaGroupWithProject",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -302,7 +318,7 @@ func (repo *repo) AssociateClaGroupWithProject(ctx context.Context, claGroupID s
 	}
 
 	log.WithFields(f).Debugf("adding entry into the %s table with: %+v", repo.tableName, item)
-	_, err := repo.dynamoDBClient.PutItem(&dynamodb.PutItemInput{
+	_, err := repo.dynamoDBClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
 		Item:                item,
 		TableName:           aws.String(repo.tableName),
 		ConditionExpression: aws.String("attribute_not_exists(project_sfid)"),
@@ -323,6 +339,9 @@ func (repo *repo) AssociateClaGroupWithProject(ctx context.Context, claGroupID s
 
 // RemoveProjectAssociatedWithClaGroup removes all associated project with cla_group
 func (repo *repo) RemoveProjectAssociatedWithClaGroup(ctx context.Context, claGroupID string, projectSFIDList []string, all bool) error {
+	ctx, span := telemetry.StartSpan(ctx, "projects_cla_groups.repository.RemoveProjectAssociatedWithClaGroup")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":    "project_cla_groups.repository.RemoveProjectAssociatedWithClaGroup",
 		utils.XREQUESTID:  ctx.Value(utils.XREQUESTID),
@@ -345,7 +364,7 @@ func (repo *repo) RemoveProjectAssociatedWithClaGroup(ctx context.Context, claGr
 			// ignore project not present in projectSFIDList
 			continue
 		}
-		_, err = repo.dynamoDBClient.DeleteItem(&dynamodb.DeleteItemInput{
+		_, err = repo.dynamoDBClient.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
 			Key: map[string]*dynamodb.AttributeValue{
 				"project_sfid": {S: aws.String(pr.ProjectSFID)},
 			},
@@ -366,8 +385,11 @@ func (repo *repo) RemoveProjectAssociatedWithClaGroup(ctx context.Context, claGr
 
 // GetCLAGroupNameByID helper function to fetch the CLA Group name
 func (repo *repo) GetCLAGroupNameByID(ctx context.Context, claGroupID string) (string, error) {
+	ctx, span := telemetry.StartSpan(ctx, "projects_cla_groups.repository.GetCLAGroupNameByID")
+	defer span.End()
+
 	tableName := fmt.Sprintf("cla-%s-projects", repo.stage)
-	result, err := repo.dynamoDBClient.GetItem(&dynamodb.GetItemInput{
+	result, err := repo.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(tableName),
 		Key: map[string]*dynamodb.AttributeValue{
 			"project_id": {
@@ -393,8 +415,11 @@ func (repo *repo) GetCLAGroupNameByID(ctx context.Context, claGroupID string) (s
 
 // GetCLAGroup helper function to fetch the CLA Group
 func (repo *repo) GetCLAGroup(ctx context.Context, claGroupID string) (*ProjectClaGroup, error) {
+	ctx, span := telemetry.StartSpan(ctx, "projects_cla_groups.repository.GetCLAGroup")
+	defer span.End()
+
 	tableName := fmt.Sprintf("cla-%s-projects", repo.stage)
-	result, err := repo.dynamoDBClient.GetItem(&dynamodb.GetItemInput{
+	result, err := repo.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(tableName),
 		Key: map[string]*dynamodb.AttributeValue{
 			"project_id": {
@@ -420,6 +445,9 @@ func (repo *repo) GetCLAGroup(ctx context.Context, claGroupID string) (*ProjectC
 
 // UpdateRepositoriesCount updates the repositories count
 func (repo *repo) UpdateRepositoriesCount(ctx context.Context, projectSFID string, diff int64, reset bool) error {
+	ctx, span := telemetry.StartSpan(ctx, "projects_cla_groups.repository.UpdateRepositoriesCount")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "project_cla_groups.repository.UpdateRepositoriesCount",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -465,7 +493,7 @@ func (repo *repo) UpdateRepositoriesCount(ctx context.Context, projectSFID strin
 		updateExpression = "ADD repositories_count :val"
 	}
 
-	_, updateErr := repo.dynamoDBClient.UpdateItem(&dynamodb.UpdateItemInput{
+	_, updateErr := repo.dynamoDBClient.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
 		UpdateExpression:          aws.String(updateExpression),
 		ExpressionAttributeNames:  expressionAttributeNames,
 		ExpressionAttributeValues: expressionAttributeValues,
@@ -484,6 +512,9 @@ func (repo *repo) UpdateRepositoriesCount(ctx context.Context, projectSFID strin
 
 // UpdateClaGroupName updates cla group name for given projectSFID
 func (repo *repo) UpdateClaGroupName(ctx context.Context, projectSFID string, claGroupName string) error {
+	ctx, span := telemetry.StartSpan(ctx, "projects_cla_groups.repository.UpdateClaGroupName")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "project_cla_groups.repository.UpdateClaGroupName",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -520,7 +551,7 @@ func (repo *repo) UpdateClaGroupName(ctx context.Context, projectSFID string, cl
 	expressionAttributeValues[":m"] = &dynamodb.AttributeValue{S: aws.String(now)}
 	updateExpression = updateExpression + ", #M = :m"
 
-	_, updateErr := repo.dynamoDBClient.UpdateItem(&dynamodb.UpdateItemInput{
+	_, updateErr := repo.dynamoDBClient.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
 		UpdateExpression:          aws.String(updateExpression),
 		ExpressionAttributeNames:  expressionAttributeNames,
 		ExpressionAttributeValues: expressionAttributeValues,
@@ -541,6 +572,9 @@ func (repo *repo) UpdateClaGroupName(ctx context.Context, projectSFID string, cl
 // specified foundation SFID has an entry in the mapping table to signify that
 // it's a foundation level CLA Group (foundationSFID == projectSFID)
 func (repo *repo) IsExistingFoundationLevelCLAGroup(ctx context.Context, foundationSFID string) (bool, error) {
+	ctx, span := telemetry.StartSpan(ctx, "projects_cla_groups.repository.IsExistingFoundationLevelCLAGroup")
+	defer span.End()
+
 	projectCLAGroupModels, err := repo.GetProjectsIdsForFoundation(ctx, foundationSFID)
 	if err != nil {
 		return false, err
@@ -556,6 +590,9 @@ func (repo *repo) IsExistingFoundationLevelCLAGroup(ctx context.Context, foundat
 }
 
 func (repo *repo) IsAssociated(ctx context.Context, projectSFID string, claGroupID string) (bool, error) {
+	ctx, span := telemetry.StartSpan(ctx, "projects_cla_groups.repository.IsAssociated")
+	defer span.End()
+
 	pmlist, err := repo.GetProjectsIdsForClaGroup(ctx, claGroupID)
 	if err != nil {
 		return false, err
@@ -198,6 +198,21 @@ func (mr *MockRepositoryMockRecorder) GetRepositoryByGithubID(ctx, externalID, e
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRepositoryByGithubID", reflect.TypeOf((*MockRepository)(nil).GetRepositoryByGithubID), ctx, externalID, enabled)
 }
 
+// GetRepositoryByExternalID mocks base method
+func (m *MockRepository) GetRepositoryByExternalID(ctx context.Context, repositoryType, externalID string, enabled bool) (*models.GithubRepository, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRepositoryByExternalID", ctx, repositoryType, externalID, enabled)
+	ret0, _ := ret[0].(*models.GithubRepository)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRepositoryByExternalID indicates an expected call of GetRepositoryByExternalID
+func (mr *MockRepositoryMockRecorder) GetRepositoryByExternalID(ctx, repositoryType, externalID, enabled interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRepositoryByExternalID", reflect.TypeOf((*MockRepository)(nil).GetRepositoryByExternalID), ctx, repositoryType, externalID, enabled)
+}
+
 // GetRepositoriesByCLAGroup mocks base method
 func (m *MockRepository) GetRepositoriesByCLAGroup(ctx context.Context, claGroup string, enabled bool) ([]*models.GithubRepository, error) {
 	m.ctrl.T.Helper()
@@ -257,3 +272,32 @@ func (mr *MockRepositoryMockRecorder) ListProjectRepositories(ctx, projectSFID,
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProjectRepositories", reflect.TypeOf((*MockRepository)(nil).ListProjectRepositories), ctx, projectSFID, enabled)
 }
+
+// GetBranchProtectionPatterns mocks base method
+func (m *MockRepository) GetBranchProtectionPatterns(ctx context.Context, repositoryID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBranchProtectionPatterns", ctx, repositoryID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBranchProtectionPatterns indicates an expected call of GetBranchProtectionPatterns
+func (mr *MockRepositoryMockRecorder) GetBranchProtectionPatterns(ctx, repositoryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBranchProtectionPatterns", reflect.TypeOf((*MockRepository)(nil).GetBranchProtectionPatterns), ctx, repositoryID)
+}
+
+// UpdateBranchProtectionPatterns mocks base method
+func (m *MockRepository) UpdateBranchProtectionPatterns(ctx context.Context, repositoryID string, patterns []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBranchProtectionPatterns", ctx, repositoryID, patterns)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBranchProtectionPatterns indicates an expected call of UpdateBranchProtectionPatterns
+func (mr *MockRepositoryMockRecorder) UpdateBranchProtectionPatterns(ctx, repositoryID, patterns interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBranchProtectionPatterns", reflect.TypeOf((*MockRepository)(nil).UpdateBranchProtectionPatterns), ctx, repositoryID, patterns)
+}
@@ -282,3 +282,32 @@ func (mr *MockGithubOrgRepoMockRecorder) GetGithubOrganizations(ctx, projectSFID
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGithubOrganizations", reflect.TypeOf((*MockGithubOrgRepo)(nil).GetGithubOrganizations), ctx, projectSFID)
 }
+
+// GetBranchProtectionPatterns mocks base method
+func (m *MockService) GetBranchProtectionPatterns(ctx context.Context, repositoryID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBranchProtectionPatterns", ctx, repositoryID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBranchProtectionPatterns indicates an expected call of GetBranchProtectionPatterns
+func (mr *MockServiceMockRecorder) GetBranchProtectionPatterns(ctx, repositoryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBranchProtectionPatterns", reflect.TypeOf((*MockService)(nil).GetBranchProtectionPatterns), ctx, repositoryID)
+}
+
+// UpdateBranchProtectionPatterns mocks base method
+func (m *MockService) UpdateBranchProtectionPatterns(ctx context.Context, repositoryID string, patterns []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBranchProtectionPatterns", ctx, repositoryID, patterns)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBranchProtectionPatterns indicates an expected call of UpdateBranchProtectionPatterns
+func (mr *MockServiceMockRecorder) UpdateBranchProtectionPatterns(ctx, repositoryID, patterns interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBranchProtectionPatterns", reflect.TypeOf((*MockService)(nil).UpdateBranchProtectionPatterns), ctx, repositoryID, patterns)
+}
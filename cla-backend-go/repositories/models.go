@@ -21,6 +21,8 @@ type RepositoryDBModel struct {
 	Enabled                    bool   `dynamodbav:"enabled" json:"enabled"`
 	Note                       string `dynamodbav:"note" json:"note,omitempty"`
 	Version                    string `dynamodbav:"version" json:"version,omitempty"`
+	// BranchProtectionPatterns are the branch patterns whose protection rule a branch protection policy created
+	BranchProtectionPatterns []string `dynamodbav:"branch_protection_patterns" json:"branch_protection_patterns,omitempty"`
 }
 
 func convertModels(dbModels []*RepositoryDBModel) []*models.GithubRepository {
@@ -25,6 +25,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
 )
 
 // index
@@ -61,10 +62,13 @@ type Repository interface {
 	GetRepository(ctx context.Context, repositoryID string) (*models.GithubRepository, error)
 	GetRepositoryByName(ctx context.Context, repositoryName string) (*models.GithubRepository, error)
 	GetRepositoryByGithubID(ctx context.Context, externalID string, enabled bool) (*models.GithubRepository, error)
+	GetRepositoryByExternalID(ctx context.Context, repositoryType, externalID string, enabled bool) (*models.GithubRepository, error)
 	GetRepositoriesByCLAGroup(ctx context.Context, claGroup string, enabled bool) ([]*models.GithubRepository, error)
 	GetRepositoriesByOrganizationName(ctx context.Context, gitHubOrgName string) ([]*models.GithubRepository, error)
 	GetCLAGroupRepositoriesGroupByOrgs(ctx context.Context, projectID string, enabled bool) ([]*models.GithubRepositoriesGroupByOrgs, error)
 	ListProjectRepositories(ctx context.Context, projectSFID string, enabled *bool) (*models.ListGithubRepositories, error)
+	GetBranchProtectionPatterns(ctx context.Context, repositoryID string) ([]string, error)
+	UpdateBranchProtectionPatterns(ctx context.Context, repositoryID string, patterns []string) error
 }
 
 // NewRepository create new Repository
@@ -84,6 +88,9 @@ type repo struct {
 
 // AddGithubRepository adds the specified repository
 func (r repo) AddGithubRepository(ctx context.Context, externalProjectID string, projectSFID string, input *models.GithubRepositoryInput) (*models.GithubRepository, error) {
+	ctx, span := telemetry.StartSpan(ctx, "repositories.repository.AddGithubRepository")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":               "v1.repositories.repository.AddGitHubRepository",
 		utils.XREQUESTID:             ctx.Value(utils.XREQUESTID),
@@ -96,14 +103,24 @@ func (r repo) AddGithubRepository(ctx context.Context, externalProjectID string,
 	}
 
 	// Check first to see if the repository already exists
-	_, err := r.GetRepositoryByGithubID(ctx, utils.StringValue(input.RepositoryExternalID), true)
-	if err != nil {
-		// Expecting Not found - no issue if not found - all other error we throw
-		if _, ok := err.(*utils.GitHubRepositoryNotFound); !ok {
+	if utils.StringValue(input.RepositoryType) == utils.GitLabType {
+		// GitLab project IDs may overlap with GitHub repository IDs - check within the repository type
+		_, err := r.GetRepositoryByExternalID(ctx, utils.GitLabType, utils.StringValue(input.RepositoryExternalID), true)
+		if err == nil {
+			return nil, errors.New("gitlab repository already exist")
+		} else if err != ErrRepositoryDoesNotExist {
 			return nil, err
 		}
 	} else {
-		return nil, errors.New("github repository already exist")
+		_, err := r.GetRepositoryByGithubID(ctx, utils.StringValue(input.RepositoryExternalID), true)
+		if err != nil {
+			// Expecting Not found - no issue if not found - all other error we throw
+			if _, ok := err.(*utils.GitHubRepositoryNotFound); !ok {
+				return nil, err
+			}
+		} else {
+			return nil, errors.New("github repository already exist")
+		}
 	}
 
 	_, currentTime := utils.CurrentTime()
@@ -135,7 +152,7 @@ func (r repo) AddGithubRepository(ctx context.Context, externalProjectID string,
 	}
 
 	log.WithFields(f).Debug("creating repository entry")
-	_, err = r.dynamoDBClient.PutItem(&dynamodb.PutItemInput{
+	_, err = r.dynamoDBClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
 		Item:      av,
 		TableName: aws.String(r.repositoryTableName),
 	})
@@ -149,6 +166,8 @@ func (r repo) AddGithubRepository(ctx context.Context, externalProjectID string,
 
 // UpdateGithubRepository updates the repository record for given ID
 func (r *repo) UpdateGithubRepository(ctx context.Context, repositoryID string, input *models.GithubRepositoryInput) (*models.GithubRepository, error) {
+	ctx, span := telemetry.StartSpan(ctx, "repositories.repository.UpdateGithubRepository")
+	defer span.End()
 
 	externalID := utils.StringValue(input.RepositoryExternalID)
 	repositoryName := utils.StringValue(input.RepositoryName)
@@ -265,7 +284,7 @@ func (r *repo) UpdateGithubRepository(ctx context.Context, repositoryID string,
 		TableName:                 aws.String(r.repositoryTableName),
 	}
 
-	_, updateErr := r.dynamoDBClient.UpdateItem(updateInput)
+	_, updateErr := r.dynamoDBClient.UpdateItemWithContext(ctx, updateInput)
 	if updateErr != nil {
 		log.WithFields(f).Warnf("error updatingRepository by repositoryID: %s, error: %v", repositoryID, updateErr)
 		return nil, updateErr
@@ -276,25 +295,40 @@ func (r *repo) UpdateGithubRepository(ctx context.Context, repositoryID string,
 
 // UpdateClaGroupID updates the claGroupID of the repository
 func (r *repo) UpdateClaGroupID(ctx context.Context, repositoryID, claGroupID string) error {
+	ctx, span := telemetry.StartSpan(ctx, "repositories.repository.UpdateClaGroupID")
+	defer span.End()
+
 	return r.setClaGroupIDGithubRepository(ctx, repositoryID, claGroupID)
 }
 
 // EnableRepository enables the repository entry
 func (r *repo) EnableRepository(ctx context.Context, repositoryID string) error {
+	ctx, span := telemetry.StartSpan(ctx, "repositories.repository.EnableRepository")
+	defer span.End()
+
 	return r.enableGithubRepository(ctx, repositoryID)
 }
 
 // EnableRepositoryWithCLAGroupID enables the repository entry with the specified CLA Group ID
 func (r *repo) EnableRepositoryWithCLAGroupID(ctx context.Context, repositoryID, claGroupID string) error {
+	ctx, span := telemetry.StartSpan(ctx, "repositories.repository.EnableRepositoryWithCLAGroupID")
+	defer span.End()
+
 	return r.enableGithubRepositoryWithCLAGroupID(ctx, repositoryID, claGroupID)
 }
 
 // DisableRepository disables the repository entry (we don't delete)
 func (r *repo) DisableRepository(ctx context.Context, repositoryID string) error {
+	ctx, span := telemetry.StartSpan(ctx, "repositories.repository.DisableRepository")
+	defer span.End()
+
 	return r.disableGithubRepository(ctx, repositoryID)
 }
 
 func (r *repo) DisableRepositoriesByProjectID(ctx context.Context, projectID string) error {
+	ctx, span := telemetry.StartSpan(ctx, "repositories.repository.DisableRepositoriesByProjectID")
+	defer span.End()
+
 	repoModels, err := r.getProjectRepositories(ctx, projectID, true)
 	if err != nil {
 		return err
@@ -313,6 +347,9 @@ func (r *repo) DisableRepositoriesByProjectID(ctx context.Context, projectID str
 
 // DisableRepositoriesOfGithubOrganization disables the repositories under the GitHub organization
 func (r repo) DisableRepositoriesOfGithubOrganization(ctx context.Context, externalProjectID, githubOrgName string) error {
+	ctx, span := telemetry.StartSpan(ctx, "repositories.repository.DisableRepositoriesOfGithubOrganization")
+	defer span.End()
+
 	repoModels, err := r.getRepositoriesByGithubOrg(ctx, githubOrgName)
 	if err != nil {
 		return err
@@ -330,12 +367,15 @@ func (r repo) DisableRepositoriesOfGithubOrganization(ctx context.Context, exter
 
 // GetRepository by repository id
 func (r *repo) GetRepository(ctx context.Context, repositoryID string) (*models.GithubRepository, error) {
+	ctx, span := telemetry.StartSpan(ctx, "repositories.repository.GetRepository")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.repositories.repository.GetRepository",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
 		"repositoryID":   repositoryID,
 	}
-	result, err := r.dynamoDBClient.GetItem(&dynamodb.GetItemInput{
+	result, err := r.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(r.repositoryTableName),
 		Key: map[string]*dynamodb.AttributeValue{
 			"repository_id": {
@@ -365,8 +405,100 @@ func (r *repo) GetRepository(ctx context.Context, repositoryID string) (*models.
 	return out.toModel(), nil
 }
 
+// GetBranchProtectionPatterns returns the branch patterns whose protection rule a branch protection policy created on
+// the repository
+func (r *repo) GetBranchProtectionPatterns(ctx context.Context, repositoryID string) ([]string, error) {
+	ctx, span := telemetry.StartSpan(ctx, "repositories.repository.GetBranchProtectionPatterns")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.repositories.repository.GetBranchProtectionPatterns",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"repositoryID":   repositoryID,
+	}
+	result, err := r.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.repositoryTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"repository_id": {
+				S: aws.String(repositoryID),
+			},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem querying using repository ID")
+		return nil, err
+	}
+	if len(result.Item) == 0 {
+		msg := fmt.Sprintf("repository with ID: %s does not exist", repositoryID)
+		log.WithFields(f).Warn(msg)
+		return nil, &utils.GitHubRepositoryNotFound{
+			Message: msg,
+		}
+	}
+
+	var out RepositoryDBModel
+	err = dynamodbattribute.UnmarshalMap(result.Item, &out)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem unmarshalling response")
+		return nil, err
+	}
+	return out.BranchProtectionPatterns, nil
+}
+
+// UpdateBranchProtectionPatterns records the branch patterns whose protection rule a branch protection policy
+// created on the repository
+func (r *repo) UpdateBranchProtectionPatterns(ctx context.Context, repositoryID string, patterns []string) error {
+	ctx, span := telemetry.StartSpan(ctx, "repositories.repository.UpdateBranchProtectionPatterns")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.repositories.repository.UpdateBranchProtectionPatterns",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"repositoryID":   repositoryID,
+		"patterns":       patterns,
+	}
+
+	_, now := utils.CurrentTime()
+	input := &dynamodb.UpdateItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"repository_id": {S: aws.String(repositoryID)},
+		},
+		ExpressionAttributeNames: map[string]*string{
+			"#repositoryID":             aws.String("repository_id"),
+			"#branchProtectionPatterns": aws.String("branch_protection_patterns"),
+			"#dateModified":             aws.String("date_modified"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":dateModifiedValue": {S: aws.String(now)},
+		},
+		ConditionExpression: aws.String("attribute_exists(#repositoryID)"),
+		TableName:           aws.String(r.repositoryTableName),
+	}
+	if len(patterns) == 0 {
+		input.UpdateExpression = aws.String("SET #dateModified = :dateModifiedValue REMOVE #branchProtectionPatterns")
+	} else {
+		patternValues := make([]*dynamodb.AttributeValue, 0, len(patterns))
+		for _, pattern := range patterns {
+			patternValues = append(patternValues, &dynamodb.AttributeValue{S: aws.String(pattern)})
+		}
+		input.ExpressionAttributeValues[":branchProtectionPatternsValue"] = &dynamodb.AttributeValue{L: patternValues}
+		input.UpdateExpression = aws.String("SET #branchProtectionPatterns = :branchProtectionPatternsValue, #dateModified = :dateModifiedValue")
+	}
+
+	_, err := r.dynamoDBClient.UpdateItemWithContext(ctx, input)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("error updating the branch protection patterns of the github repository")
+		return err
+	}
+	return nil
+}
+
 // GetRepositoryByName fetches the repository by repository name
 func (r *repo) GetRepositoryByName(ctx context.Context, repositoryName string) (*models.GithubRepository, error) {
+	ctx, span := telemetry.StartSpan(ctx, "repositories.repository.GetRepositoryByName")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.repositories.repository.GetRepositoryByName",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -392,7 +524,7 @@ func (r *repo) GetRepositoryByName(ctx context.Context, repositoryName string) (
 		IndexName:                 aws.String(RepositoryNameIndex),
 	}
 
-	results, err := r.dynamoDBClient.Query(queryInput)
+	results, err := r.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).WithError(err).Warn("unable to get repositories by name")
 		return nil, err
@@ -421,6 +553,9 @@ func (r *repo) GetRepositoryByName(ctx context.Context, repositoryName string) (
 
 // GetRepositoryByCLAGroup gets the list of repositories based on the CLA Group ID
 func (r *repo) GetRepositoriesByCLAGroup(ctx context.Context, claGroupID string, enabled bool) ([]*models.GithubRepository, error) {
+	ctx, span := telemetry.StartSpan(ctx, "repositories.repository.GetRepositoriesByCLAGroup")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.repositories.repository.GetRepositoryByCLAGroup",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -447,7 +582,7 @@ func (r *repo) GetRepositoriesByCLAGroup(ctx context.Context, claGroupID string,
 		IndexName:                 aws.String(ProjectRepositoryIndex),
 	}
 
-	results, err := r.dynamoDBClient.Query(queryInput)
+	results, err := r.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).Warnf("unable to get project github repositories. error: %+v", err)
 		return nil, err
@@ -472,6 +607,9 @@ func (r *repo) GetRepositoriesByCLAGroup(ctx context.Context, claGroupID string,
 }
 
 func (r *repo) GetRepositoriesByOrganizationName(ctx context.Context, gitHubOrgName string) ([]*models.GithubRepository, error) {
+	ctx, span := telemetry.StartSpan(ctx, "repositories.repository.GetRepositoriesByOrganizationName")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.repositories.repository.GetRepositoriesByOrganizationName",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -498,7 +636,7 @@ func (r *repo) GetRepositoriesByOrganizationName(ctx context.Context, gitHubOrgN
 	}
 
 	log.WithFields(f).Debug("querying repositories table by github organization name")
-	results, err := r.dynamoDBClient.Query(queryInput)
+	results, err := r.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).Warnf("unable to get github repositories by organization name. error: %+v", err)
 		return nil, err
@@ -524,6 +662,9 @@ func (r *repo) GetRepositoriesByOrganizationName(ctx context.Context, gitHubOrgN
 
 // GetCLAGroupRepositoriesGroupByOrgs returns a list of GH organizations by CLA Group - enabled flag indicates that we search the enabled repositories list
 func (r repo) GetCLAGroupRepositoriesGroupByOrgs(ctx context.Context, projectID string, enabled bool) ([]*models.GithubRepositoriesGroupByOrgs, error) {
+	ctx, span := telemetry.StartSpan(ctx, "repositories.repository.GetCLAGroupRepositoriesGroupByOrgs")
+	defer span.End()
+
 	out := make([]*models.GithubRepositoriesGroupByOrgs, 0)
 	outMap := make(map[string]*models.GithubRepositoriesGroupByOrgs)
 	ghrepos, err := r.getProjectRepositories(ctx, projectID, enabled)
@@ -546,6 +687,9 @@ func (r repo) GetCLAGroupRepositoriesGroupByOrgs(ctx context.Context, projectID
 
 // List github repositories of project by external/salesforce project id
 func (r repo) ListProjectRepositories(ctx context.Context, projectSFID string, enabled *bool) (*models.ListGithubRepositories, error) {
+	ctx, span := telemetry.StartSpan(ctx, "repositories.repository.ListProjectRepositories")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.repositories.repository.ListProjectRepositories",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -579,7 +723,7 @@ func (r repo) ListProjectRepositories(ctx context.Context, projectSFID string, e
 		IndexName:                 aws.String(ProjectSFIDRepositoryOrganizationNameIndex),
 	}
 
-	results, err := r.dynamoDBClient.Query(queryInput)
+	results, err := r.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).Warnf("unable to get project github repositories. error = %s", err.Error())
 		return nil, err
@@ -627,7 +771,7 @@ func (r repo) getProjectRepositories(ctx context.Context, projectID string, enab
 		IndexName:                 aws.String(ProjectRepositoryIndex),
 	}
 
-	results, err := r.dynamoDBClient.Query(queryInput)
+	results, err := r.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).Warnf("unable to get project github repositories. error = %s", err.Error())
 		return nil, err
@@ -672,7 +816,7 @@ func (r repo) getRepositoriesByGithubOrg(ctx context.Context, githubOrgName stri
 		TableName:                 aws.String(r.repositoryTableName),
 	}
 
-	results, err := r.dynamoDBClient.Scan(scanInput)
+	results, err := r.dynamoDBClient.ScanWithContext(ctx, scanInput)
 	if err != nil {
 		log.WithFields(f).Warnf("unable to get github organizations repositories. error = %s", err.Error())
 		return nil, err
@@ -693,6 +837,9 @@ func (r repo) getRepositoriesByGithubOrg(ctx context.Context, githubOrgName stri
 
 // GetRepositoryByGithubID fetches the repository model by its external github id
 func (r repo) GetRepositoryByGithubID(ctx context.Context, externalID string, enabled bool) (*models.GithubRepository, error) {
+	ctx, span := telemetry.StartSpan(ctx, "repositories.repository.GetRepositoryByGithubID")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.repositories.repository.GetRepositoryByGitHubID",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -722,7 +869,7 @@ func (r repo) GetRepositoryByGithubID(ctx context.Context, externalID string, en
 		IndexName:                 aws.String(ExternalRepositoryIndex),
 	}
 
-	results, err := r.dynamoDBClient.Query(queryInput)
+	results, err := r.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).Warnf("unable to get project github repositories. error = %s", err.Error())
 		return nil, err
@@ -742,6 +889,53 @@ func (r repo) GetRepositoryByGithubID(ctx context.Context, externalID string, en
 	return result.toModel(), nil
 }
 
+// GetRepositoryByExternalID fetches the repository model of the repository type (github, gitlab) by its external id
+func (r repo) GetRepositoryByExternalID(ctx context.Context, repositoryType, externalID string, enabled bool) (*models.GithubRepository, error) {
+	ctx, span := telemetry.StartSpan(ctx, "repositories.repository.GetRepositoryByExternalID")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.repositories.repository.GetRepositoryByExternalID",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"repositoryType": repositoryType,
+		"externalID":     externalID,
+		"enabled":        enabled,
+	}
+
+	condition := expression.Key("repository_external_id").Equal(expression.Value(externalID))
+	filter := expression.Name(repositoryEnabledColumn).Equal(expression.Value(enabled)).
+		And(expression.Name("repository_type").Equal(expression.Value(repositoryType)))
+
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).WithFilter(filter).Build()
+	if err != nil {
+		return nil, err
+	}
+	queryInput := &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+		TableName:                 aws.String(r.repositoryTableName),
+		IndexName:                 aws.String(ExternalRepositoryIndex),
+	}
+
+	results, err := r.dynamoDBClient.QueryWithContext(ctx, queryInput)
+	if err != nil {
+		log.WithFields(f).Warnf("unable to get repository by external id. error = %s", err.Error())
+		return nil, err
+	}
+	if len(results.Items) == 0 {
+		return nil, ErrRepositoryDoesNotExist
+	}
+	var result *RepositoryDBModel
+	err = dynamodbattribute.UnmarshalMap(results.Items[0], &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.toModel(), nil
+}
+
 func (r repo) enableGithubRepository(ctx context.Context, repositoryID string) error {
 	return r.setEnabledGithubRepository(ctx, repositoryID, true)
 }
@@ -787,7 +981,7 @@ func (r repo) setEnabledGithubRepository(ctx context.Context, repositoryID strin
 
 	_, now := utils.CurrentTime()
 	log.WithFields(f).Debug("updating repository record")
-	_, err := r.dynamoDBClient.UpdateItem(&dynamodb.UpdateItemInput{
+	_, err := r.dynamoDBClient.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
 		Key: map[string]*dynamodb.AttributeValue{
 			"repository_id": {S: aws.String(repositoryID)},
 		},
@@ -858,7 +1052,7 @@ func (r repo) setEnabledGithubRepositoryWithCLAGroupID(ctx context.Context, repo
 
 	_, now := utils.CurrentTime()
 	log.WithFields(f).Debug("updating repository record")
-	_, err := r.dynamoDBClient.UpdateItem(&dynamodb.UpdateItemInput{
+	_, err := r.dynamoDBClient.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
 		Key: map[string]*dynamodb.AttributeValue{
 			"repository_id": {S: aws.String(repositoryID)},
 		},
@@ -929,7 +1123,7 @@ func (r repo) setClaGroupIDGithubRepository(ctx context.Context, repositoryID, c
 
 	_, now := utils.CurrentTime()
 	log.WithFields(f).Debug("updating repository record with cla group id")
-	_, err := r.dynamoDBClient.UpdateItem(&dynamodb.UpdateItemInput{
+	_, err := r.dynamoDBClient.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
 		Key: map[string]*dynamodb.AttributeValue{
 			"repository_id": {S: aws.String(repositoryID)},
 		},
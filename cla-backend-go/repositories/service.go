@@ -34,6 +34,8 @@ type Service interface {
 	DisableRepositoriesByProjectID(ctx context.Context, projectID string) (int, error)
 	GetRepositoriesByCLAGroup(ctx context.Context, claGroupID string) ([]*models.GithubRepository, error)
 	GetRepositoriesByOrganizationName(ctx context.Context, gitHubOrgName string) ([]*models.GithubRepository, error)
+	GetBranchProtectionPatterns(ctx context.Context, repositoryID string) ([]string, error)
+	UpdateBranchProtectionPatterns(ctx context.Context, repositoryID string, patterns []string) error
 }
 
 // GithubOrgRepo provide method to get github organization by name
@@ -214,3 +216,15 @@ func (s *service) GetRepositoriesByCLAGroup(ctx context.Context, claGroupID stri
 func (s *service) GetRepositoriesByOrganizationName(ctx context.Context, gitHubOrgName string) ([]*models.GithubRepository, error) {
 	return s.repo.GetRepositoriesByOrganizationName(ctx, gitHubOrgName)
 }
+
+// GetBranchProtectionPatterns returns the branch patterns whose protection rule a branch protection policy created on
+// the repository
+func (s *service) GetBranchProtectionPatterns(ctx context.Context, repositoryID string) ([]string, error) {
+	return s.repo.GetBranchProtectionPatterns(ctx, repositoryID)
+}
+
+// UpdateBranchProtectionPatterns records the branch patterns whose protection rule a branch protection policy
+// created on the repository
+func (s *service) UpdateBranchProtectionPatterns(ctx context.Context, repositoryID string, patterns []string) error {
+	return s.repo.UpdateBranchProtectionPatterns(ctx, repositoryID, patterns)
+}
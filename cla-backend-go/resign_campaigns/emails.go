@@ -0,0 +1,96 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package resign_campaigns
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// notify sends the re-sign request (or the reminder) to the signer - the ICLA signer is emailed directly, the CLA
+// Managers of the company are emailed for a CCLA
+func (s *service) notify(ctx context.Context, claGroupModel *models.ClaGroup, campaign *Campaign, signer *Signer, latest *models.Signature, reminder bool) {
+	f := logrus.Fields{
+		"functionName":   "v1.resign_campaigns.emails.notify",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"campaignID":     campaign.CampaignID,
+		"referenceID":    signer.ReferenceID,
+	}
+
+	if campaign.ClaType == utils.ClaTypeCCLA {
+		for i := range latest.SignatureACL {
+			claManager := latest.SignatureACL[i]
+			sendResignRequestEmail(claGroupModel, campaign, signer, utils.GetBestUsername(&claManager), utils.GetBestEmail(&claManager), reminder)
+		}
+		return
+	}
+
+	userModel, err := s.usersRepo.GetUser(signer.ReferenceID)
+	if err != nil || userModel == nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the signer - skipping the email")
+		return
+	}
+	sendResignRequestEmail(claGroupModel, campaign, signer, utils.GetBestUsername(userModel), utils.GetBestEmail(userModel), reminder)
+}
+
+// sendResignRequestEmail sends the email asking to sign the new major version of the document to the recipient
+func sendResignRequestEmail(claGroupModel *models.ClaGroup, campaign *Campaign, signer *Signer, recipientName, recipientAddress string, reminder bool) {
+	f := logrus.Fields{
+		"functionName":      "sendResignRequestEmail",
+		"projectName":       claGroupModel.ProjectName,
+		"projectExternalID": claGroupModel.ProjectExternalID,
+		"campaignID":        campaign.CampaignID,
+		"recipientName":     recipientName,
+		"recipientAddress":  recipientAddress}
+
+	if recipientAddress == "" {
+		log.WithFields(f).Warn("no email address for the recipient - skipping the email")
+		return
+	}
+
+	projectName := claGroupModel.ProjectName
+	documentName := "Individual Contributor License Agreement"
+	signInstructions := "<p>You can sign the new version from the EasyCLA check of your next pull request or merge request.</p>"
+	if campaign.ClaType == utils.ClaTypeCCLA {
+		documentName = fmt.Sprintf("Corporate Contributor License Agreement of %s", signer.CompanyName)
+		signInstructions = fmt.Sprintf(`<p>As a CLA Manager of %s, please ask your CLA signatory to sign the new version from the
+<a href="%s" target="_blank">EasyCLA Corporate Console</a>.</p>`, signer.CompanyName, utils.GetCorporateURL(claGroupModel.Version == utils.V2))
+	}
+	consequence := "<p>Your current signature remains valid after this date.</p>"
+	if campaign.InvalidateOnExpiry {
+		consequence = `<p>After this date the current signature will no longer be valid and the contributions it covers
+will be blocked until the new version is signed.</p>`
+	}
+
+	subject := fmt.Sprintf("EasyCLA: Please Sign the New Version of the CLA for %s", projectName)
+	if reminder {
+		subject = fmt.Sprintf("EasyCLA: Reminder - Please Sign the New Version of the CLA for %s", projectName)
+	}
+	recipients := []string{recipientAddress}
+	body := fmt.Sprintf(`
+<p>Hello %s,</p>
+<p>This is a notification email from EasyCLA regarding the project %s.</p>
+<p>The %s for project %s was updated to version %d. The signed version is %s - please sign the new version
+before %s.</p>
+%s
+%s
+%s
+%s`,
+		recipientName, projectName, documentName, projectName, campaign.MajorVersion, signer.SignedVersion,
+		campaign.GracePeriodEnd, signInstructions, consequence,
+		utils.GetEmailHelpContent(claGroupModel.Version == utils.V2), utils.GetEmailSignOffContent())
+
+	err := utils.SendEmail(subject, body, recipients)
+	if err != nil {
+		log.WithFields(f).Warnf("problem sending email with subject: %s to recipients: %+v, error: %+v", subject, recipients, err)
+	} else {
+		log.WithFields(f).Debugf("sent email with subject: %s to recipients: %+v", subject, recipients)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package resign_campaigns
+
+// campaign statuses
+const (
+	CampaignStatusActive    = "active"
+	CampaignStatusCompleted = "completed"
+	CampaignStatusCancelled = "cancelled"
+)
+
+// signer statuses
+const (
+	SignerStatusPending  = "pending"
+	SignerStatusResigned = "resigned"
+	// SignerStatusExpired the grace period ended without a new signature, the stale signatures were kept
+	SignerStatusExpired = "expired"
+	// SignerStatusInvalidated the grace period ended without a new signature, the stale signatures were invalidated
+	SignerStatusInvalidated = "invalidated"
+)
+
+// Campaign asks the ICLA or CCLA signers of a CLA group who signed an older major version of the document to sign
+// the current major version before the end of the grace period
+type Campaign struct {
+	CampaignID   string `dynamodbav:"campaign_id"`
+	CLAGroupID   string `dynamodbav:"cla_group_id"`
+	CLAGroupName string `dynamodbav:"cla_group_name"`
+	// ClaType is icla or ccla
+	ClaType string `dynamodbav:"cla_type"`
+	// MajorVersion is the major version of the document the signers are asked to sign
+	MajorVersion       int    `dynamodbav:"major_version"`
+	GracePeriodEnd     string `dynamodbav:"grace_period_end"`
+	InvalidateOnExpiry bool   `dynamodbav:"invalidate_on_expiry"`
+	ReminderSent       bool   `dynamodbav:"reminder_sent"`
+	Status             string `dynamodbav:"status"`
+	CreatedBy          string `dynamodbav:"created_by"`
+
+	DateCreated  string `dynamodbav:"date_created"`
+	DateModified string `dynamodbav:"date_modified"`
+	Version      string `dynamodbav:"version"`
+}
+
+// Signer is a user (ICLA) or a company (CCLA) asked to re-sign by a campaign
+type Signer struct {
+	CampaignID string `dynamodbav:"campaign_id"`
+	// ReferenceID is the user ID of the ICLA signers, the company ID of the CCLA signers
+	ReferenceID   string `dynamodbav:"reference_id"`
+	ReferenceName string `dynamodbav:"reference_name"`
+	// SignatureIDs are the signatures of the older major versions, invalidated when the grace period ends
+	SignatureIDs  []string `dynamodbav:"signature_ids,stringset"`
+	SignedVersion string   `dynamodbav:"signed_version"`
+	// CompanyID is the company of the signer used to report the progress per company - the signing company of the
+	// CCLAs, the company of the user (if any) for the ICLAs
+	CompanyID   string `dynamodbav:"company_id"`
+	CompanyName string `dynamodbav:"company_name"`
+	Status      string `dynamodbav:"status"`
+	NotifiedOn  string `dynamodbav:"notified_on"`
+	RemindedOn  string `dynamodbav:"reminded_on"`
+
+	DateModified string `dynamodbav:"date_modified"`
+}
+
+// SignerCounts counts the campaign signers by status
+type SignerCounts struct {
+	Total       int `json:"total"`
+	Pending     int `json:"pending"`
+	Resigned    int `json:"resigned"`
+	Expired     int `json:"expired"`
+	Invalidated int `json:"invalidated"`
+}
+
+// add counts the signer status
+func (c *SignerCounts) add(status string) {
+	c.Total++
+	switch status {
+	case SignerStatusPending:
+		c.Pending++
+	case SignerStatusResigned:
+		c.Resigned++
+	case SignerStatusExpired:
+		c.Expired++
+	case SignerStatusInvalidated:
+		c.Invalidated++
+	}
+}
+
+// CompanyProgress is the campaign progress of the signers of a company
+type CompanyProgress struct {
+	CompanyID   string `json:"company_id"`
+	CompanyName string `json:"company_name"`
+	SignerCounts
+}
+
+// CampaignProgress is the campaign progress, in total and per company - the signers without a company are only
+// counted in the total
+type CampaignProgress struct {
+	Campaign *Campaign `json:"campaign"`
+	SignerCounts
+	Companies []*CompanyProgress `json:"companies"`
+}
+
+// ProcessResult summarizes a campaign processing run
+type ProcessResult struct {
+	CampaignsProcessed int `json:"campaigns_processed"`
+	CampaignsCompleted int `json:"campaigns_completed"`
+	Resigned           int `json:"resigned"`
+	RemindersSent      int `json:"reminders_sent"`
+	Invalidated        int `json:"invalidated"`
+	Expired            int `json:"expired"`
+	Errors             int `json:"errors"`
+}
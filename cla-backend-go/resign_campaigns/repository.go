@@ -0,0 +1,255 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package resign_campaigns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
+)
+
+// indexes
+const (
+	CLAGroupIDIndex = "cla-group-id-index"
+	StatusIndex     = "status-index"
+)
+
+// attribute names
+const (
+	campaignIDAttributeName = "campaign_id"
+	claGroupIDAttributeName = "cla_group_id"
+	statusAttributeName     = "status"
+)
+
+// ErrCampaignDoesNotExist is returned when the re-sign campaign does not exist
+var ErrCampaignDoesNotExist = errors.New("re-sign campaign does not exist")
+
+// Repository interface defines the functions for the re-sign campaigns data model
+type Repository interface {
+	PutCampaign(ctx context.Context, campaign *Campaign) error
+	GetCampaign(ctx context.Context, campaignID string) (*Campaign, error)
+	ListCampaigns(ctx context.Context, claGroupID string) ([]*Campaign, error)
+	ListCampaignsByStatus(ctx context.Context, status string) ([]*Campaign, error)
+
+	PutSigner(ctx context.Context, signer *Signer) error
+	ListSigners(ctx context.Context, campaignID string) ([]*Signer, error)
+}
+
+type repository struct {
+	stage              string
+	dynamoDBClient     *dynamodb.DynamoDB
+	campaignsTableName string
+	signersTableName   string
+}
+
+// NewRepository creates a new instance of the re-sign campaigns repository
+func NewRepository(awsSession *session.Session, stage string) Repository {
+	return &repository{
+		stage:              stage,
+		dynamoDBClient:     dynamodb.New(awsSession),
+		campaignsTableName: fmt.Sprintf("cla-%s-resign-campaigns", stage),
+		signersTableName:   fmt.Sprintf("cla-%s-resign-campaign-signers", stage),
+	}
+}
+
+// PutCampaign creates or replaces the campaign record
+func (repo *repository) PutCampaign(ctx context.Context, campaign *Campaign) error {
+	ctx, span := telemetry.StartSpan(ctx, "resign_campaigns.repository.PutCampaign")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.resign_campaigns.repository.PutCampaign",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"campaignID":     campaign.CampaignID,
+		"claGroupID":     campaign.CLAGroupID,
+		"status":         campaign.Status,
+	}
+
+	av, err := dynamodbattribute.MarshalMap(campaign)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to marshall the campaign")
+		return err
+	}
+
+	_, err = repo.dynamoDBClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		Item:      av,
+		TableName: aws.String(repo.campaignsTableName),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("cannot put the campaign in dynamodb")
+		return err
+	}
+	return nil
+}
+
+// GetCampaign returns the campaign
+func (repo *repository) GetCampaign(ctx context.Context, campaignID string) (*Campaign, error) {
+	ctx, span := telemetry.StartSpan(ctx, "resign_campaigns.repository.GetCampaign")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.resign_campaigns.repository.GetCampaign",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"campaignID":     campaignID,
+	}
+
+	result, err := repo.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			campaignIDAttributeName: {S: aws.String(campaignID)},
+		},
+		TableName: aws.String(repo.campaignsTableName),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("error retrieving the campaign")
+		return nil, err
+	}
+	if len(result.Item) == 0 {
+		return nil, ErrCampaignDoesNotExist
+	}
+
+	var campaign Campaign
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &campaign); err != nil {
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+// ListCampaigns returns the campaigns of the CLA group
+func (repo *repository) ListCampaigns(ctx context.Context, claGroupID string) ([]*Campaign, error) {
+	ctx, span := telemetry.StartSpan(ctx, "resign_campaigns.repository.ListCampaigns")
+	defer span.End()
+
+	condition := expression.Key(claGroupIDAttributeName).Equal(expression.Value(claGroupID))
+	return repo.queryCampaigns(ctx, "v1.resign_campaigns.repository.ListCampaigns", CLAGroupIDIndex, condition)
+}
+
+// ListCampaignsByStatus returns the campaigns with the status
+func (repo *repository) ListCampaignsByStatus(ctx context.Context, status string) ([]*Campaign, error) {
+	ctx, span := telemetry.StartSpan(ctx, "resign_campaigns.repository.ListCampaignsByStatus")
+	defer span.End()
+
+	condition := expression.Key(statusAttributeName).Equal(expression.Value(status))
+	return repo.queryCampaigns(ctx, "v1.resign_campaigns.repository.ListCampaignsByStatus", StatusIndex, condition)
+}
+
+// queryCampaigns returns all the pages of the campaigns index query
+func (repo *repository) queryCampaigns(ctx context.Context, functionName, indexName string, condition expression.KeyConditionBuilder) ([]*Campaign, error) {
+	f := logrus.Fields{
+		"functionName":   functionName,
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"indexName":      indexName,
+	}
+
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).Build()
+	if err != nil {
+		log.WithFields(f).Warnf("problem building query expression, error: %+v", err)
+		return nil, err
+	}
+
+	var campaigns []*Campaign
+	err = repo.dynamoDBClient.QueryPagesWithContext(ctx, &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		TableName:                 aws.String(repo.campaignsTableName),
+		IndexName:                 aws.String(indexName),
+	}, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		var pageCampaigns []*Campaign
+		if unmarshalErr := dynamodbattribute.UnmarshalListOfMaps(page.Items, &pageCampaigns); unmarshalErr != nil {
+			err = unmarshalErr
+			return false
+		}
+		campaigns = append(campaigns, pageCampaigns...)
+		return true
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("error retrieving the campaigns")
+		return nil, err
+	}
+
+	return campaigns, nil
+}
+
+// PutSigner creates or replaces the campaign signer record
+func (repo *repository) PutSigner(ctx context.Context, signer *Signer) error {
+	ctx, span := telemetry.StartSpan(ctx, "resign_campaigns.repository.PutSigner")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.resign_campaigns.repository.PutSigner",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"campaignID":     signer.CampaignID,
+		"referenceID":    signer.ReferenceID,
+		"status":         signer.Status,
+	}
+
+	av, err := dynamodbattribute.MarshalMap(signer)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to marshall the campaign signer")
+		return err
+	}
+
+	_, err = repo.dynamoDBClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		Item:      av,
+		TableName: aws.String(repo.signersTableName),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("cannot put the campaign signer in dynamodb")
+		return err
+	}
+	return nil
+}
+
+// ListSigners returns the signers of the campaign
+func (repo *repository) ListSigners(ctx context.Context, campaignID string) ([]*Signer, error) {
+	ctx, span := telemetry.StartSpan(ctx, "resign_campaigns.repository.ListSigners")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.resign_campaigns.repository.ListSigners",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"campaignID":     campaignID,
+	}
+
+	condition := expression.Key(campaignIDAttributeName).Equal(expression.Value(campaignID))
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).Build()
+	if err != nil {
+		log.WithFields(f).Warnf("problem building query expression, error: %+v", err)
+		return nil, err
+	}
+
+	var signers []*Signer
+	err = repo.dynamoDBClient.QueryPagesWithContext(ctx, &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		TableName:                 aws.String(repo.signersTableName),
+	}, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		var pageSigners []*Signer
+		if unmarshalErr := dynamodbattribute.UnmarshalListOfMaps(page.Items, &pageSigners); unmarshalErr != nil {
+			err = unmarshalErr
+			return false
+		}
+		signers = append(signers, pageSigners...)
+		return true
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("error retrieving the campaign signers")
+		return nil, err
+	}
+
+	return signers, nil
+}
@@ -0,0 +1,109 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package resign_campaigns
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// memoryRepository is an embedded, in-memory implementation of the re-sign campaigns Repository. It is intended
+// for local development and tests where DynamoDB is not available.
+type memoryRepository struct {
+	lock      sync.RWMutex
+	campaigns map[string]Campaign
+	// signers are keyed by the campaign ID then the reference ID
+	signers map[string]map[string]Signer
+}
+
+// NewMemoryRepository creates a new instance of the in-memory re-sign campaigns repository
+func NewMemoryRepository() Repository {
+	return &memoryRepository{
+		campaigns: map[string]Campaign{},
+		signers:   map[string]map[string]Signer{},
+	}
+}
+
+// PutCampaign creates or replaces the campaign record
+func (repo *memoryRepository) PutCampaign(ctx context.Context, campaign *Campaign) error {
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	repo.campaigns[campaign.CampaignID] = *campaign
+	return nil
+}
+
+// GetCampaign returns the campaign
+func (repo *memoryRepository) GetCampaign(ctx context.Context, campaignID string) (*Campaign, error) {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	campaign, ok := repo.campaigns[campaignID]
+	if !ok {
+		return nil, ErrCampaignDoesNotExist
+	}
+	return &campaign, nil
+}
+
+// ListCampaigns returns the campaigns of the CLA group, oldest first
+func (repo *memoryRepository) ListCampaigns(ctx context.Context, claGroupID string) ([]*Campaign, error) {
+	return repo.filterCampaigns(func(campaign Campaign) bool {
+		return campaign.CLAGroupID == claGroupID
+	}), nil
+}
+
+// ListCampaignsByStatus returns the campaigns with the status, oldest first
+func (repo *memoryRepository) ListCampaignsByStatus(ctx context.Context, status string) ([]*Campaign, error) {
+	return repo.filterCampaigns(func(campaign Campaign) bool {
+		return campaign.Status == status
+	}), nil
+}
+
+// filterCampaigns returns the matching campaigns, oldest first
+func (repo *memoryRepository) filterCampaigns(match func(campaign Campaign) bool) []*Campaign {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	var campaigns []*Campaign
+	for _, campaign := range repo.campaigns {
+		if match(campaign) {
+			out := campaign
+			campaigns = append(campaigns, &out)
+		}
+	}
+	sort.Slice(campaigns, func(i, j int) bool {
+		if campaigns[i].DateCreated == campaigns[j].DateCreated {
+			return campaigns[i].CampaignID < campaigns[j].CampaignID
+		}
+		return campaigns[i].DateCreated < campaigns[j].DateCreated
+	})
+	return campaigns
+}
+
+// PutSigner creates or replaces the campaign signer record
+func (repo *memoryRepository) PutSigner(ctx context.Context, signer *Signer) error {
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	if _, ok := repo.signers[signer.CampaignID]; !ok {
+		repo.signers[signer.CampaignID] = map[string]Signer{}
+	}
+	out := *signer
+	out.SignatureIDs = append([]string{}, signer.SignatureIDs...)
+	repo.signers[signer.CampaignID][signer.ReferenceID] = out
+	return nil
+}
+
+// ListSigners returns the signers of the campaign, ordered by reference ID like the table range key
+func (repo *memoryRepository) ListSigners(ctx context.Context, campaignID string) ([]*Signer, error) {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	var signers []*Signer
+	for _, signer := range repo.signers[campaignID] {
+		out := signer
+		out.SignatureIDs = append([]string{}, signer.SignatureIDs...)
+		signers = append(signers, &out)
+	}
+	sort.Slice(signers, func(i, j int) bool {
+		return signers[i].ReferenceID < signers[j].ReferenceID
+	})
+	return signers, nil
+}
@@ -0,0 +1,583 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package resign_campaigns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// grace period limits, in days
+const (
+	DefaultGracePeriodDays = 30
+	MaxGracePeriodDays     = 365
+)
+
+// resignCampaignsUser is the user recorded on the events logged by the scheduled campaign processing
+const resignCampaignsUser = "easycla-resign-campaigns"
+
+// ReminderPeriod is how long before the end of the grace period the signers who did not re-sign are reminded
+const ReminderPeriod = 7 * 24 * time.Hour
+
+// errors
+var (
+	ErrInvalidCLAType        = errors.New("invalid cla type - expecting icla or ccla")
+	ErrInvalidGracePeriod    = fmt.Errorf("invalid grace period - expecting 1 to %d days", MaxGracePeriodDays)
+	ErrCLAGroupNotFound      = errors.New("cla group does not exist")
+	ErrCLATypeNotEnabled     = errors.New("the cla type is not enabled for the cla group")
+	ErrNoCurrentDocument     = errors.New("the cla group does not have a current document for the cla type")
+	ErrCampaignAlreadyActive = errors.New("a re-sign campaign is already active for the cla group and cla type")
+	ErrCampaignNotActive     = errors.New("the re-sign campaign is not active")
+	ErrNoSignersToResign     = errors.New("all the signers already signed the current major version of the document")
+)
+
+// CampaignInput is the input of a new re-sign campaign
+type CampaignInput struct {
+	CLAGroupID string
+	// ClaType is icla or ccla
+	ClaType string
+	// GracePeriodDays is the number of days the signers have to re-sign, DefaultGracePeriodDays when zero
+	GracePeriodDays    int
+	InvalidateOnExpiry bool
+	CreatedBy          string
+}
+
+// SignatureRepository is the signatures repository behavior needed by the re-sign campaigns
+type SignatureRepository interface {
+	GetCLAGroupSignedSignatures(ctx context.Context, claGroupID, claType string) ([]*models.Signature, error)
+	InvalidateProjectRecord(ctx context.Context, signatureID, note string) error
+}
+
+// Service interface defines the re-sign campaigns service methods
+type Service interface {
+	CreateCampaign(ctx context.Context, input *CampaignInput) (*Campaign, error)
+	GetCampaign(ctx context.Context, campaignID string) (*Campaign, error)
+	ListCampaigns(ctx context.Context, claGroupID string) ([]*Campaign, error)
+	CancelCampaign(ctx context.Context, campaignID, cancelledBy string) (*Campaign, error)
+	GetCampaignProgress(ctx context.Context, campaignID string) (*CampaignProgress, error)
+	ProcessCampaigns(ctx context.Context, now time.Time) (*ProcessResult, error)
+}
+
+type service struct {
+	repo          Repository
+	signatureRepo SignatureRepository
+	claGroupRepo  signatures.CLAGroupLookup
+	usersRepo     users.UserRepository
+	companyRepo   company.IRepository
+	eventsService events.Service
+	now           func() time.Time
+}
+
+// NewService creates a new re-sign campaigns service
+func NewService(repo Repository, signatureRepo SignatureRepository, claGroupRepo signatures.CLAGroupLookup, usersRepo users.UserRepository, companyRepo company.IRepository, eventsService events.Service) Service {
+	return &service{
+		repo:          repo,
+		signatureRepo: signatureRepo,
+		claGroupRepo:  claGroupRepo,
+		usersRepo:     usersRepo,
+		companyRepo:   companyRepo,
+		eventsService: eventsService,
+		now:           time.Now,
+	}
+}
+
+// signerSignatures are the signed signatures of a user (ICLA) or a company (CCLA)
+type signerSignatures struct {
+	// latest is the signature of the highest version
+	latest       *models.Signature
+	latestMajor  int
+	latestMinor  int
+	staleVersion []*models.Signature
+}
+
+// staleSignatureIDs returns the IDs of the signatures below the major version
+func (s *signerSignatures) staleSignatureIDs() []string {
+	var signatureIDs []string
+	for _, sig := range s.staleVersion {
+		signatureIDs = append(signatureIDs, sig.SignatureID)
+	}
+	sort.Strings(signatureIDs)
+	return signatureIDs
+}
+
+// groupSignatures groups the signed signatures by user (ICLA) or company (CCLA), the signatures below the major
+// version are the stale signatures - the signatures with an invalid version are ignored
+func groupSignatures(ctx context.Context, sigs []*models.Signature, majorVersion int) map[string]*signerSignatures {
+	f := logrus.Fields{
+		"functionName":   "v1.resign_campaigns.service.groupSignatures",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+	}
+
+	signers := map[string]*signerSignatures{}
+	for _, sig := range sigs {
+		major, majorErr := strconv.Atoi(sig.SignatureMajorVersion)
+		minor, minorErr := strconv.Atoi(sig.SignatureMinorVersion)
+		if majorErr != nil || minorErr != nil || sig.SignatureReferenceID == "" {
+			log.WithFields(f).Warnf("ignoring signature: %s with version: %s.%s", sig.SignatureID, sig.SignatureMajorVersion, sig.SignatureMinorVersion)
+			continue
+		}
+
+		signer, ok := signers[sig.SignatureReferenceID]
+		if !ok {
+			signer = &signerSignatures{latestMajor: -1}
+			signers[sig.SignatureReferenceID] = signer
+		}
+		if major > signer.latestMajor || (major == signer.latestMajor && minor > signer.latestMinor) {
+			signer.latest, signer.latestMajor, signer.latestMinor = sig, major, minor
+		}
+		if major < majorVersion {
+			signer.staleVersion = append(signer.staleVersion, sig)
+		}
+	}
+	return signers
+}
+
+// currentMajorVersion returns the major version of the current document of the CLA type
+func currentMajorVersion(ctx context.Context, claGroup *models.ClaGroup, claType string) (int, error) {
+	docs := claGroup.ProjectIndividualDocuments
+	if claType == utils.ClaTypeCCLA {
+		docs = claGroup.ProjectCorporateDocuments
+	}
+	currentDoc, err := project.GetCurrentDocument(ctx, docs)
+	if err != nil {
+		return 0, err
+	}
+	majorVersion, err := strconv.Atoi(currentDoc.DocumentMajorVersion)
+	if err != nil {
+		return 0, ErrNoCurrentDocument
+	}
+	return majorVersion, nil
+}
+
+// CreateCampaign computes the signers of an older major version of the document, asks them to re-sign by email and
+// tracks them until the end of the grace period
+func (s *service) CreateCampaign(ctx context.Context, input *CampaignInput) (*Campaign, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.resign_campaigns.service.CreateCampaign",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     input.CLAGroupID,
+		"claType":        input.ClaType,
+	}
+
+	if input.ClaType != utils.ClaTypeICLA && input.ClaType != utils.ClaTypeCCLA {
+		return nil, ErrInvalidCLAType
+	}
+	gracePeriodDays := input.GracePeriodDays
+	if gracePeriodDays == 0 {
+		gracePeriodDays = DefaultGracePeriodDays
+	}
+	if gracePeriodDays < 0 || gracePeriodDays > MaxGracePeriodDays {
+		return nil, ErrInvalidGracePeriod
+	}
+
+	claGroup, err := s.claGroupRepo.GetCLAGroupByID(ctx, input.CLAGroupID, false)
+	if err != nil {
+		return nil, err
+	}
+	if claGroup == nil {
+		return nil, ErrCLAGroupNotFound
+	}
+	if (input.ClaType == utils.ClaTypeICLA && !claGroup.ProjectICLAEnabled) || (input.ClaType == utils.ClaTypeCCLA && !claGroup.ProjectCCLAEnabled) {
+		return nil, ErrCLATypeNotEnabled
+	}
+	majorVersion, err := currentMajorVersion(ctx, claGroup, input.ClaType)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.ListCampaigns(ctx, input.CLAGroupID)
+	if err != nil {
+		return nil, err
+	}
+	for _, campaign := range existing {
+		if campaign.Status == CampaignStatusActive && campaign.ClaType == input.ClaType {
+			return nil, ErrCampaignAlreadyActive
+		}
+	}
+
+	sigs, err := s.signatureRepo.GetCLAGroupSignedSignatures(ctx, input.CLAGroupID, input.ClaType)
+	if err != nil {
+		return nil, err
+	}
+	grouped := groupSignatures(ctx, sigs, majorVersion)
+	var referenceIDs []string
+	for referenceID, signer := range grouped {
+		if signer.latestMajor < majorVersion {
+			referenceIDs = append(referenceIDs, referenceID)
+		}
+	}
+	if len(referenceIDs) == 0 {
+		return nil, ErrNoSignersToResign
+	}
+	sort.Strings(referenceIDs)
+
+	campaignID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	now := s.now().UTC()
+	campaign := &Campaign{
+		CampaignID:         campaignID.String(),
+		CLAGroupID:         claGroup.ProjectID,
+		CLAGroupName:       claGroup.ProjectName,
+		ClaType:            input.ClaType,
+		MajorVersion:       majorVersion,
+		GracePeriodEnd:     now.AddDate(0, 0, gracePeriodDays).Format(time.RFC3339),
+		InvalidateOnExpiry: input.InvalidateOnExpiry,
+		Status:             CampaignStatusActive,
+		CreatedBy:          input.CreatedBy,
+		DateCreated:        utils.TimeToString(now),
+		DateModified:       utils.TimeToString(now),
+		Version:            "v1",
+	}
+	if err = s.repo.PutCampaign(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	log.WithFields(f).Debugf("asking %d signers to sign the major version %d", len(referenceIDs), majorVersion)
+	companyNames := map[string]string{}
+	for _, referenceID := range referenceIDs {
+		signer := s.newSigner(ctx, campaign, grouped[referenceID], companyNames)
+		s.notify(ctx, claGroup, campaign, signer, grouped[referenceID].latest, false)
+		signer.NotifiedOn = utils.TimeToString(now)
+		if err = s.repo.PutSigner(ctx, signer); err != nil {
+			return nil, err
+		}
+	}
+
+	s.eventsService.LogEventWithContext(ctx, &events.LogEventArgs{
+		EventType:     events.ResignCampaignStarted,
+		CLAGroupID:    claGroup.ProjectID,
+		ClaGroupModel: claGroup,
+		ProjectID:     claGroup.ProjectExternalID,
+		LfUsername:    input.CreatedBy,
+		EventData: &events.ResignCampaignStartedEventData{
+			CampaignID:     campaign.CampaignID,
+			ClaType:        campaign.ClaType,
+			MajorVersion:   campaign.MajorVersion,
+			GracePeriodEnd: campaign.GracePeriodEnd,
+			SignerCount:    len(referenceIDs),
+		},
+	})
+	return campaign, nil
+}
+
+// newSigner returns the pending signer of the campaign with the company used to report the progress
+func (s *service) newSigner(ctx context.Context, campaign *Campaign, signer *signerSignatures, companyNames map[string]string) *Signer {
+	f := logrus.Fields{
+		"functionName":   "v1.resign_campaigns.service.newSigner",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"campaignID":     campaign.CampaignID,
+		"referenceID":    signer.latest.SignatureReferenceID,
+	}
+
+	out := &Signer{
+		CampaignID:    campaign.CampaignID,
+		ReferenceID:   signer.latest.SignatureReferenceID,
+		ReferenceName: signer.latest.SignatureReferenceName,
+		SignatureIDs:  signer.staleSignatureIDs(),
+		SignedVersion: fmt.Sprintf("%d.%d", signer.latestMajor, signer.latestMinor),
+		Status:        SignerStatusPending,
+		DateModified:  utils.TimeToString(s.now()),
+	}
+	if campaign.ClaType == utils.ClaTypeCCLA {
+		out.CompanyID = out.ReferenceID
+		out.CompanyName = out.ReferenceName
+		return out
+	}
+
+	userModel, err := s.usersRepo.GetUser(out.ReferenceID)
+	if err != nil || userModel == nil || userModel.CompanyID == "" {
+		return out
+	}
+	out.CompanyID = userModel.CompanyID
+	companyName, ok := companyNames[userModel.CompanyID]
+	if !ok {
+		companyModel, companyErr := s.companyRepo.GetCompany(ctx, userModel.CompanyID)
+		if companyErr != nil {
+			log.WithFields(f).WithError(companyErr).Warnf("unable to load the company: %s of the user", userModel.CompanyID)
+		} else if companyModel != nil {
+			companyName = companyModel.CompanyName
+		}
+		companyNames[userModel.CompanyID] = companyName
+	}
+	out.CompanyName = companyName
+	return out
+}
+
+// GetCampaign returns the campaign
+func (s *service) GetCampaign(ctx context.Context, campaignID string) (*Campaign, error) {
+	return s.repo.GetCampaign(ctx, campaignID)
+}
+
+// ListCampaigns returns the campaigns of the CLA group
+func (s *service) ListCampaigns(ctx context.Context, claGroupID string) ([]*Campaign, error) {
+	return s.repo.ListCampaigns(ctx, claGroupID)
+}
+
+// CancelCampaign stops the active campaign, the pending signers are not reminded and their signatures are kept
+func (s *service) CancelCampaign(ctx context.Context, campaignID, cancelledBy string) (*Campaign, error) {
+	campaign, err := s.repo.GetCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status != CampaignStatusActive {
+		return nil, ErrCampaignNotActive
+	}
+
+	campaign.Status = CampaignStatusCancelled
+	campaign.DateModified = utils.TimeToString(s.now())
+	if err = s.repo.PutCampaign(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	s.eventsService.LogEventWithContext(ctx, &events.LogEventArgs{
+		EventType:  events.ResignCampaignCancelled,
+		CLAGroupID: campaign.CLAGroupID,
+		LfUsername: cancelledBy,
+		EventData: &events.ResignCampaignCancelledEventData{
+			CampaignID:   campaign.CampaignID,
+			ClaType:      campaign.ClaType,
+			MajorVersion: campaign.MajorVersion,
+		},
+	})
+	return campaign, nil
+}
+
+// GetCampaignProgress returns the campaign signer counts in total and per company, the signers of an active
+// campaign who signed the new version since the last run are marked as re-signed first
+func (s *service) GetCampaignProgress(ctx context.Context, campaignID string) (*CampaignProgress, error) {
+	campaign, err := s.repo.GetCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	signers, err := s.repo.ListSigners(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	if campaign.Status == CampaignStatusActive {
+		sigs, sigErr := s.signatureRepo.GetCLAGroupSignedSignatures(ctx, campaign.CLAGroupID, campaign.ClaType)
+		if sigErr != nil {
+			return nil, sigErr
+		}
+		if _, err = s.refreshSigners(ctx, campaign, signers, groupSignatures(ctx, sigs, campaign.MajorVersion)); err != nil {
+			return nil, err
+		}
+	}
+
+	progress := &CampaignProgress{Campaign: campaign, Companies: []*CompanyProgress{}}
+	companies := map[string]*CompanyProgress{}
+	for _, signer := range signers {
+		progress.add(signer.Status)
+		if signer.CompanyID == "" {
+			continue
+		}
+		companyProgress, ok := companies[signer.CompanyID]
+		if !ok {
+			companyProgress = &CompanyProgress{CompanyID: signer.CompanyID, CompanyName: signer.CompanyName}
+			companies[signer.CompanyID] = companyProgress
+			progress.Companies = append(progress.Companies, companyProgress)
+		}
+		companyProgress.add(signer.Status)
+	}
+	sort.Slice(progress.Companies, func(i, j int) bool {
+		if progress.Companies[i].CompanyName == progress.Companies[j].CompanyName {
+			return progress.Companies[i].CompanyID < progress.Companies[j].CompanyID
+		}
+		return progress.Companies[i].CompanyName < progress.Companies[j].CompanyName
+	})
+	return progress, nil
+}
+
+// refreshSigners marks the pending signers who signed the campaign major version as re-signed, returns the number
+// of re-signed signers
+func (s *service) refreshSigners(ctx context.Context, campaign *Campaign, signers []*Signer, grouped map[string]*signerSignatures) (int, error) {
+	resigned := 0
+	for _, signer := range signers {
+		if signer.Status != SignerStatusPending {
+			continue
+		}
+		current, ok := grouped[signer.ReferenceID]
+		if !ok || current.latestMajor < campaign.MajorVersion {
+			continue
+		}
+		signer.Status = SignerStatusResigned
+		signer.SignedVersion = fmt.Sprintf("%d.%d", current.latestMajor, current.latestMinor)
+		signer.DateModified = utils.TimeToString(s.now())
+		if err := s.repo.PutSigner(ctx, signer); err != nil {
+			return resigned, err
+		}
+		resigned++
+	}
+	return resigned, nil
+}
+
+// ProcessCampaigns updates the active campaigns at the specified time - the signers who re-signed are recorded, the
+// pending signers are reminded a week before the end of the grace period and the stale signatures are invalidated
+// (when requested) once the grace period ends
+func (s *service) ProcessCampaigns(ctx context.Context, now time.Time) (*ProcessResult, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.resign_campaigns.service.ProcessCampaigns",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"now":            now.Format(time.RFC3339),
+	}
+
+	campaigns, err := s.repo.ListCampaignsByStatus(ctx, CampaignStatusActive)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the active re-sign campaigns")
+		return nil, err
+	}
+
+	result := &ProcessResult{}
+	for _, campaign := range campaigns {
+		result.CampaignsProcessed++
+		if processErr := s.processCampaign(ctx, campaign, now, result); processErr != nil {
+			log.WithFields(f).WithError(processErr).Warnf("unable to process the re-sign campaign: %s", campaign.CampaignID)
+			result.Errors++
+		}
+	}
+
+	log.WithFields(f).Infof("re-sign campaign results: %+v", result)
+	return result, nil
+}
+
+// processCampaign updates the campaign signers and closes the campaign at the end of the grace period
+func (s *service) processCampaign(ctx context.Context, campaign *Campaign, now time.Time, result *ProcessResult) error {
+	f := logrus.Fields{
+		"functionName":   "v1.resign_campaigns.service.processCampaign",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"campaignID":     campaign.CampaignID,
+		"claGroupID":     campaign.CLAGroupID,
+	}
+
+	gracePeriodEnd, err := time.Parse(time.RFC3339, campaign.GracePeriodEnd)
+	if err != nil {
+		return err
+	}
+	claGroup, err := s.claGroupRepo.GetCLAGroupByID(ctx, campaign.CLAGroupID, false)
+	if err != nil {
+		return err
+	}
+	if claGroup == nil {
+		return ErrCLAGroupNotFound
+	}
+	signers, err := s.repo.ListSigners(ctx, campaign.CampaignID)
+	if err != nil {
+		return err
+	}
+	sigs, err := s.signatureRepo.GetCLAGroupSignedSignatures(ctx, campaign.CLAGroupID, campaign.ClaType)
+	if err != nil {
+		return err
+	}
+	grouped := groupSignatures(ctx, sigs, campaign.MajorVersion)
+
+	resigned, err := s.refreshSigners(ctx, campaign, signers, grouped)
+	result.Resigned += resigned
+	if err != nil {
+		return err
+	}
+
+	if now.Before(gracePeriodEnd) {
+		if campaign.ReminderSent || now.Before(gracePeriodEnd.Add(-ReminderPeriod)) {
+			return nil
+		}
+		log.WithFields(f).Debug("reminding the pending signers")
+		for _, signer := range signers {
+			current, ok := grouped[signer.ReferenceID]
+			if signer.Status != SignerStatusPending || !ok {
+				continue
+			}
+			s.notify(ctx, claGroup, campaign, signer, current.latest, true)
+			signer.RemindedOn = utils.TimeToString(now)
+			signer.DateModified = utils.TimeToString(now)
+			if err = s.repo.PutSigner(ctx, signer); err != nil {
+				return err
+			}
+			result.RemindersSent++
+		}
+		campaign.ReminderSent = true
+		campaign.DateModified = utils.TimeToString(now)
+		return s.repo.PutCampaign(ctx, campaign)
+	}
+
+	log.WithFields(f).Debug("the grace period ended - closing the campaign")
+	counts := SignerCounts{}
+	for _, signer := range signers {
+		if signer.Status == SignerStatusPending {
+			if err = s.expireSigner(ctx, campaign, signer, grouped[signer.ReferenceID], now); err != nil {
+				return err
+			}
+		}
+		counts.add(signer.Status)
+	}
+	result.Expired += counts.Expired
+	result.Invalidated += counts.Invalidated
+
+	campaign.Status = CampaignStatusCompleted
+	campaign.DateModified = utils.TimeToString(now)
+	if err = s.repo.PutCampaign(ctx, campaign); err != nil {
+		return err
+	}
+	result.CampaignsCompleted++
+
+	s.eventsService.LogEventWithContext(ctx, &events.LogEventArgs{
+		EventType:     events.ResignCampaignCompleted,
+		CLAGroupID:    claGroup.ProjectID,
+		ClaGroupModel: claGroup,
+		ProjectID:     claGroup.ProjectExternalID,
+		LfUsername:    resignCampaignsUser,
+		EventData: &events.ResignCampaignCompletedEventData{
+			CampaignID:       campaign.CampaignID,
+			ClaType:          campaign.ClaType,
+			MajorVersion:     campaign.MajorVersion,
+			ResignedCount:    counts.Resigned,
+			ExpiredCount:     counts.Expired,
+			InvalidatedCount: counts.Invalidated,
+		},
+	})
+	return nil
+}
+
+// expireSigner closes the pending signer at the end of the grace period, the stale signatures are invalidated when
+// the campaign requests it
+func (s *service) expireSigner(ctx context.Context, campaign *Campaign, signer *Signer, current *signerSignatures, now time.Time) error {
+	signer.Status = SignerStatusExpired
+	signer.DateModified = utils.TimeToString(now)
+	if !campaign.InvalidateOnExpiry {
+		return s.repo.PutSigner(ctx, signer)
+	}
+
+	// the signatures may have changed since the campaign started, the current stale signatures are invalidated
+	signatureIDs := signer.SignatureIDs
+	if current != nil {
+		signatureIDs = current.staleSignatureIDs()
+	}
+	note := fmt.Sprintf("invalidated by the re-sign campaign %s - the version %d of the %s document was not signed by %s",
+		campaign.CampaignID, campaign.MajorVersion, campaign.ClaType, campaign.GracePeriodEnd)
+	for _, signatureID := range signatureIDs {
+		if err := s.signatureRepo.InvalidateProjectRecord(ctx, signatureID, note); err != nil {
+			return err
+		}
+	}
+	signer.SignatureIDs = signatureIDs
+	signer.Status = SignerStatusInvalidated
+	return s.repo.PutSigner(ctx, signer)
+}
@@ -0,0 +1,400 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// ApprovalListExpiryWarningPeriod is how long before a time-bounded approval list entry expires that the CLA Managers
+// are warned
+const ApprovalListExpiryWarningPeriod = 7 * 24 * time.Hour
+
+// approvalListExpiryUser is the user recorded as the CLA Manager when the expired approval list entries are removed
+var approvalListExpiryUser = &models.User{
+	LfUsername: "easycla-approval-list-expiry",
+	Username:   "EasyCLA Approval List Expiry",
+}
+
+// approvalListColumns is the list of approval list columns, in display order
+var approvalListColumns = []string{
+	EmailApprovalListColumn,
+	DomainApprovalListColumn,
+	GitHubUsernameApprovalListColumn,
+	GitHubOrgApprovalListColumn,
+}
+
+// approvalListExpiryPartition is the partition key value of the sparse approval list expiry index - only the
+// signatures with time-bounded approval list entries carry the index attributes
+const approvalListExpiryPartition = "approval_list_expiry"
+
+// ApprovalListExpirationKey returns the key of the approval list entry in the expirations map, the key includes the
+// column as the same value may be an entry of several approval lists
+func ApprovalListExpirationKey(columnName, entry string) string {
+	return columnName + ":" + entry
+}
+
+// parseApprovalListExpirationKey returns the approval list column and entry of the expirations map key
+func parseApprovalListExpirationKey(key string) (string, string, bool) {
+	parts := strings.SplitN(key, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// approvalListAdditions returns the entries added by the approval list update, keyed by the column name
+func approvalListAdditions(params *models.ApprovalList) map[string][]string {
+	return map[string][]string{
+		EmailApprovalListColumn:          params.AddEmailApprovalList,
+		DomainApprovalListColumn:         params.AddDomainApprovalList,
+		GitHubUsernameApprovalListColumn: params.AddGithubUsernameApprovalList,
+		GitHubOrgApprovalListColumn:      params.AddGithubOrgApprovalList,
+	}
+}
+
+// validateApprovalListExpirations checks that each expiry refers to an added entry and is a future RFC3339 timestamp
+func validateApprovalListExpirations(params *models.ApprovalList, now time.Time) error {
+	additions := approvalListAdditions(params)
+	for key, expiry := range params.ApprovalListExpirations {
+		columnName, entry, ok := parseApprovalListExpirationKey(key)
+		if !ok {
+			return fmt.Errorf("invalid approval list expiry key %s - expecting <column>:<entry>, e.g. %s", key, ApprovalListExpirationKey(EmailApprovalListColumn, "contractor@example.com"))
+		}
+		if !utils.StringInSlice(entry, additions[columnName]) {
+			return fmt.Errorf("approval list expiry set for %s which is not being added to the approval list", key)
+		}
+		expiresAt, err := time.Parse(time.RFC3339, expiry)
+		if err != nil {
+			return fmt.Errorf("invalid approval list expiry %s for %s - expecting an RFC3339 timestamp", expiry, key)
+		}
+		if !expiresAt.After(now) {
+			return fmt.Errorf("approval list expiry %s for %s is in the past", expiry, key)
+		}
+	}
+
+	return nil
+}
+
+// buildApprovalListExpirations returns the expirations and expiry warnings once the approval list update is applied.
+// Adding an entry sets its expiry (or makes it permanent when no expiry is given) and resets its warning, removed
+// entries lose their expiry.
+func buildApprovalListExpirations(cclaSignature *models.Signature, updatedLists map[string][]string, params *models.ApprovalList) (map[string]string, []string) {
+	additions := approvalListAdditions(params)
+	expirations := map[string]string{}
+	var warningsSent []string
+
+	for columnName, entries := range updatedLists {
+		for _, entry := range entries {
+			key := ApprovalListExpirationKey(columnName, entry)
+			if utils.StringInSlice(entry, additions[columnName]) {
+				if expiry, ok := params.ApprovalListExpirations[key]; ok {
+					expiresAt, err := time.Parse(time.RFC3339, expiry)
+					if err == nil {
+						expirations[key] = expiresAt.UTC().Format(time.RFC3339)
+					}
+				}
+				continue
+			}
+			if expiry, ok := cclaSignature.ApprovalListExpirations[key]; ok {
+				expirations[key] = expiry
+				if utils.StringInSlice(key, cclaSignature.ApprovalListExpiryWarningsSent) {
+					warningsSent = append(warningsSent, key)
+				}
+			}
+		}
+	}
+
+	sort.Strings(warningsSent)
+	return expirations, warningsSent
+}
+
+// nextApprovalListExpiry returns the earliest expiry of the expirations as an RFC3339 UTC timestamp, empty if none
+func nextApprovalListExpiry(expirations map[string]string) string {
+	var next time.Time
+	for _, expiry := range expirations {
+		expiresAt, err := time.Parse(time.RFC3339, expiry)
+		if err != nil {
+			continue
+		}
+		if next.IsZero() || expiresAt.Before(next) {
+			next = expiresAt
+		}
+	}
+	if next.IsZero() {
+		return ""
+	}
+	return next.UTC().Format(time.RFC3339)
+}
+
+// expiredApprovalListEntries returns the approval list update removing the entries expired at the specified time
+func expiredApprovalListEntries(cclaSignature *models.Signature, now time.Time) *models.ApprovalList {
+	expired := &models.ApprovalList{}
+	for key, expiry := range cclaSignature.ApprovalListExpirations {
+		columnName, entry, ok := parseApprovalListExpirationKey(key)
+		if !ok {
+			continue
+		}
+		expiresAt, err := time.Parse(time.RFC3339, expiry)
+		if err != nil || expiresAt.After(now) {
+			continue
+		}
+		switch columnName {
+		case EmailApprovalListColumn:
+			expired.RemoveEmailApprovalList = append(expired.RemoveEmailApprovalList, entry)
+		case DomainApprovalListColumn:
+			expired.RemoveDomainApprovalList = append(expired.RemoveDomainApprovalList, entry)
+		case GitHubUsernameApprovalListColumn:
+			expired.RemoveGithubUsernameApprovalList = append(expired.RemoveGithubUsernameApprovalList, entry)
+		case GitHubOrgApprovalListColumn:
+			expired.RemoveGithubOrgApprovalList = append(expired.RemoveGithubOrgApprovalList, entry)
+		}
+	}
+
+	sort.Strings(expired.RemoveEmailApprovalList)
+	sort.Strings(expired.RemoveDomainApprovalList)
+	sort.Strings(expired.RemoveGithubUsernameApprovalList)
+	sort.Strings(expired.RemoveGithubOrgApprovalList)
+	return expired
+}
+
+// expiringApprovalListEntries returns the keys of the entries expiring within the warning period for which the CLA
+// Managers have not been warned yet
+func expiringApprovalListEntries(cclaSignature *models.Signature, now time.Time) []string {
+	var expiring []string
+	for key, expiry := range cclaSignature.ApprovalListExpirations {
+		expiresAt, err := time.Parse(time.RFC3339, expiry)
+		if err != nil || !expiresAt.After(now) || expiresAt.After(now.Add(ApprovalListExpiryWarningPeriod)) {
+			continue
+		}
+		if !utils.StringInSlice(key, cclaSignature.ApprovalListExpiryWarningsSent) {
+			expiring = append(expiring, key)
+		}
+	}
+
+	sort.Strings(expiring)
+	return expiring
+}
+
+// hasApprovalListRemovals returns true if the approval list update removes at least one entry
+func hasApprovalListRemovals(params *models.ApprovalList) bool {
+	return len(params.RemoveEmailApprovalList) > 0 || len(params.RemoveDomainApprovalList) > 0 ||
+		len(params.RemoveGithubUsernameApprovalList) > 0 || len(params.RemoveGithubOrgApprovalList) > 0
+}
+
+// CLAGroupLookup is the CLA Group repository behavior needed to process the approval list expirations
+type CLAGroupLookup interface {
+	GetCLAGroupByID(ctx context.Context, claGroupID string, loadRepoDetails bool) (*models.ClaGroup, error)
+}
+
+// ApprovalListExpiryResult summarizes an approval list expiry run
+type ApprovalListExpiryResult struct {
+	SignaturesProcessed int `json:"signatures_processed"`
+	EntriesRemoved      int `json:"entries_removed"`
+	WarningsSent        int `json:"warnings_sent"`
+	Errors              int `json:"errors"`
+}
+
+// ApprovalListExpiryService removes the expired approval list entries and warns the CLA Managers before entries expire
+type ApprovalListExpiryService interface {
+	ProcessApprovalListExpirations(ctx context.Context, now time.Time) (*ApprovalListExpiryResult, error)
+}
+
+type approvalListExpiryService struct {
+	repo          SignatureRepository
+	claGroupRepo  CLAGroupLookup
+	companyRepo   company.IRepository
+	eventsService events.Service
+}
+
+// NewApprovalListExpiryService creates a new approval list expiry service
+func NewApprovalListExpiryService(repo SignatureRepository, claGroupRepo CLAGroupLookup, companyRepo company.IRepository, eventsService events.Service) ApprovalListExpiryService {
+	return &approvalListExpiryService{
+		repo:          repo,
+		claGroupRepo:  claGroupRepo,
+		companyRepo:   companyRepo,
+		eventsService: eventsService,
+	}
+}
+
+// ProcessApprovalListExpirations removes the approval list entries expired at the specified time - invalidating the
+// signatures that are no longer approved - and warns the CLA Managers of the entries expiring within a week
+func (s *approvalListExpiryService) ProcessApprovalListExpirations(ctx context.Context, now time.Time) (*ApprovalListExpiryResult, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.signatures.approval_list_expiry.ProcessApprovalListExpirations",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"now":            now.Format(time.RFC3339),
+	}
+
+	// Only the signatures with an entry expired or expiring within the warning period need processing
+	cclaSignatures, err := s.repo.GetSignaturesWithApprovalListExpirations(ctx, now.Add(ApprovalListExpiryWarningPeriod))
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the signatures with approval list expirations")
+		return nil, err
+	}
+	log.WithFields(f).Debugf("processing %d signatures with approval list expirations", len(cclaSignatures))
+
+	result := &ApprovalListExpiryResult{}
+	for _, cclaSignature := range cclaSignatures {
+		result.SignaturesProcessed++
+		removed, warned, processErr := s.processSignature(ctx, cclaSignature, now)
+		result.EntriesRemoved += removed
+		result.WarningsSent += warned
+		if processErr != nil {
+			log.WithFields(f).WithError(processErr).Warnf("unable to process the approval list expirations for signature: %s", cclaSignature.SignatureID)
+			result.Errors++
+		}
+	}
+
+	log.WithFields(f).Infof("approval list expiry results: %+v", result)
+	return result, nil
+}
+
+// processSignature removes the expired entries of the CCLA signature and sends the expiry warnings, returns the number
+// of removed entries and the number of warned entries
+func (s *approvalListExpiryService) processSignature(ctx context.Context, cclaSignature *models.Signature, now time.Time) (int, int, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.signatures.approval_list_expiry.processSignature",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"signatureID":    cclaSignature.SignatureID,
+		"claGroupID":     cclaSignature.ProjectID,
+		"companyID":      cclaSignature.SignatureReferenceID,
+	}
+
+	claGroupModel, err := s.claGroupRepo.GetCLAGroupByID(ctx, cclaSignature.ProjectID, false)
+	if err != nil {
+		return 0, 0, err
+	}
+	if claGroupModel == nil {
+		return 0, 0, &utils.CLAGroupNotFound{CLAGroupID: cclaSignature.ProjectID}
+	}
+	companyModel, err := s.companyRepo.GetCompany(ctx, cclaSignature.SignatureReferenceID)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	removed := 0
+	expired := expiredApprovalListEntries(cclaSignature, now)
+	if hasApprovalListRemovals(expired) {
+		log.WithFields(f).Debugf("removing expired approval list entries: %+v", expired)
+		eventArgs := &events.LogEventArgs{
+			EventType:     events.InvalidatedSignature,
+			ProjectID:     claGroupModel.ProjectExternalID,
+			ClaGroupModel: claGroupModel,
+			CompanyID:     companyModel.CompanyID,
+			CompanyModel:  companyModel,
+			UserModel:     approvalListExpiryUser,
+			ProjectSFID:   claGroupModel.ProjectExternalID,
+		}
+		updatedSignature, updateErr := s.repo.UpdateApprovalList(ctx, approvalListExpiryUser, claGroupModel, companyModel.CompanyID, expired, eventArgs)
+		if updateErr != nil {
+			return 0, 0, updateErr
+		}
+		removed = len(expired.RemoveEmailApprovalList) + len(expired.RemoveDomainApprovalList) +
+			len(expired.RemoveGithubUsernameApprovalList) + len(expired.RemoveGithubOrgApprovalList)
+
+		createEventLogEntries(ctx, s.eventsService, companyModel, claGroupModel, approvalListExpiryUser, expired)
+		for i := range cclaSignature.SignatureACL {
+			claManager := cclaSignature.SignatureACL[i]
+			sendApprovalListUpdateEmailToCLAManagers(companyModel, claGroupModel, utils.GetBestUsername(&claManager), getBestEmail(&claManager), expired)
+		}
+
+		if updatedSignature != nil {
+			cclaSignature = updatedSignature
+		}
+	}
+
+	expiring := expiringApprovalListEntries(cclaSignature, now)
+	if len(expiring) == 0 {
+		return removed, 0, nil
+	}
+
+	log.WithFields(f).Debugf("warning the CLA Managers of the expiring approval list entries: %+v", expiring)
+	for i := range cclaSignature.SignatureACL {
+		claManager := cclaSignature.SignatureACL[i]
+		sendApprovalListExpiryWarningEmail(companyModel, claGroupModel, utils.GetBestUsername(&claManager), getBestEmail(&claManager), cclaSignature.ApprovalListExpirations, expiring)
+	}
+
+	warningsSent := append(append([]string{}, cclaSignature.ApprovalListExpiryWarningsSent...), expiring...)
+	sort.Strings(warningsSent)
+	err = s.repo.UpdateApprovalListExpirations(ctx, cclaSignature.SignatureID, cclaSignature.ApprovalListExpirations, warningsSent)
+	if err != nil {
+		return removed, 0, err
+	}
+
+	return removed, len(expiring), nil
+}
+
+// buildApprovalListExpirySummary is a helper function to generate the email content of the expiring entries
+func buildApprovalListExpirySummary(expirations map[string]string, expiring []string) string {
+	labels := map[string]string{
+		EmailApprovalListColumn:          "Email",
+		DomainApprovalListColumn:         "Domain",
+		GitHubUsernameApprovalListColumn: "GitHub User",
+		GitHubOrgApprovalListColumn:      "GitHub Organization",
+	}
+
+	summary := "<ul>"
+	for _, columnName := range approvalListColumns {
+		for _, key := range expiring {
+			keyColumn, entry, ok := parseApprovalListExpirationKey(key)
+			if !ok || keyColumn != columnName {
+				continue
+			}
+			summary += fmt.Sprintf("<li>%s: %s expires on %s</li>", labels[columnName], entry, expirations[key])
+		}
+	}
+	summary += "</ul>"
+	return summary
+}
+
+// sendApprovalListExpiryWarningEmail sends the upcoming approval list expiry email to the specified CLA Manager
+func sendApprovalListExpiryWarningEmail(companyModel *models.Company, claGroupModel *models.ClaGroup, recipientName, recipientAddress string, expirations map[string]string, expiring []string) {
+	f := logrus.Fields{
+		"functionName":      "sendApprovalListExpiryWarningEmail",
+		"projectName":       claGroupModel.ProjectName,
+		"projectExternalID": claGroupModel.ProjectExternalID,
+		"foundationSFID":    claGroupModel.FoundationSFID,
+		"companyName":       companyModel.CompanyName,
+		"companyExternalID": companyModel.CompanyExternalID,
+		"recipientName":     recipientName,
+		"recipientAddress":  recipientAddress}
+
+	companyName := companyModel.CompanyName
+	projectName := claGroupModel.ProjectName
+
+	subject := fmt.Sprintf("EasyCLA: Approval List Entries Expiring for %s on %s", companyName, projectName)
+	recipients := []string{recipientAddress}
+	body := fmt.Sprintf(`
+<p>Hello %s,</p>
+<p>This is a notification email from EasyCLA regarding the project %s.</p>
+<p>The following EasyCLA approval list entries for %s for project %s will expire within the next week:</p>
+%s
+<p>Once expired, the entries are removed from the approval list and the contributors they approve will no longer be
+authorized to contribute on behalf of %s. To keep them authorized, add the entries again with a new expiry date or
+without an expiry date.</p>
+%s
+%s`,
+		recipientName, projectName, companyName, projectName, buildApprovalListExpirySummary(expirations, expiring), companyName,
+		utils.GetEmailHelpContent(claGroupModel.Version == utils.V2), utils.GetEmailSignOffContent())
+
+	err := utils.SendEmail(subject, body, recipients)
+	if err != nil {
+		log.WithFields(f).Warnf("problem sending email with subject: %s to recipients: %+v, error: %+v", subject, recipients, err)
+	} else {
+		log.WithFields(f).Debugf("sent email with subject: %s to recipients: %+v", subject, recipients)
+	}
+}
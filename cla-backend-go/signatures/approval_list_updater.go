@@ -0,0 +1,833 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
+	"github.com/communitybridge/easycla/cla-backend-go/github"
+	"github.com/communitybridge/easycla/cla-backend-go/github_organizations"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/restapi/operations/signatures"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+)
+
+// approvalListColumnStore writes the approval list columns of the signature record. Every storage backend implements
+// it, it is kept out of SignatureRepository as only the approval list updater writes the columns directly.
+type approvalListColumnStore interface {
+	removeColumn(ctx context.Context, signatureID, columnName string) (*models.Signature, error)
+	updateApprovalListColumns(ctx context.Context, signatureID string, columns map[string][]string) error
+}
+
+// approvalListUpdater holds the approval list update logic (gerrit updates, signature invalidation, emails and
+// events). It only talks to the signature storage through the SignatureRepository and the approvalListColumnStore
+// interfaces, so every storage backend shares the same behavior.
+type approvalListUpdater struct {
+	sigRepo          SignatureRepository
+	columnStore      approvalListColumnStore
+	companyRepo      company.IRepository
+	usersRepo        users.UserRepository
+	eventsService    events.Service
+	repositoriesRepo repositories.Repository
+	ghOrgRepo        github_organizations.RepositoryInterface
+	gerritService    gerrits.Service
+}
+
+// UpdateApprovalList updates the specified project/company signature with the updated approval list information
+func (u approvalListUpdater) UpdateApprovalList(ctx context.Context, claManager *models.User, claGroupModel *models.ClaGroup, companyID string, params *models.ApprovalList, eventArgs *events.LogEventArgs) (*models.Signature, error) { // nolint
+
+	projectID := claGroupModel.ProjectID
+	f := logrus.Fields{
+		"functionName": "v1.signatures.repository.UpdateApprovalList",
+		"projectID":    projectID,
+		"companyID":    companyID,
+	}
+	log.WithFields(f).Debug("querying database for approval list details")
+
+	// Reject invalid entries, including malformed patterns, before touching any records
+	if validationErr := validateApprovalListEntries(params); validationErr != nil {
+		log.WithFields(f).WithError(validationErr).Warn("invalid approval list update")
+		return nil, validationErr
+	}
+	if validationErr := validateApprovalListExpirations(params, time.Now()); validationErr != nil {
+		log.WithFields(f).WithError(validationErr).Warn("invalid approval list expirations")
+		return nil, validationErr
+	}
+
+	approved, signed := true, true
+	pageSize := int64(10)
+
+	// Get CCLA signature - For Approval List info
+	cclaSignature, err := u.sigRepo.GetCorporateSignature(ctx, projectID, companyID, &approved, &signed)
+	if err != nil || cclaSignature == nil {
+		msg := fmt.Sprintf("unable to get corporate signature for CLA Group: %s and company: %s", projectID, companyID)
+		log.WithFields(f).Warn(msg)
+		return nil, errors.New(msg)
+	}
+
+	// Get CLA Manager
+	var cclaManagers []ClaManagerInfoParams
+	for i := range cclaSignature.SignatureACL {
+		cclaManagers = append(cclaManagers, ClaManagerInfoParams{
+			Username: utils.GetBestUsername(&cclaSignature.SignatureACL[i]),
+			Email:    getBestEmail(&cclaSignature.SignatureACL[i]),
+		})
+	}
+
+	// The approval lists once this update is applied - column name => updated list
+	updatedLists := map[string][]string{
+		EmailApprovalListColumn:          buildApprovalList(ctx, cclaSignature.EmailApprovalList, params.AddEmailApprovalList, params.RemoveEmailApprovalList),
+		DomainApprovalListColumn:         buildApprovalList(ctx, cclaSignature.DomainApprovalList, params.AddDomainApprovalList, params.RemoveDomainApprovalList),
+		GitHubUsernameApprovalListColumn: buildApprovalList(ctx, cclaSignature.GithubUsernameApprovalList, params.AddGithubUsernameApprovalList, params.RemoveGithubUsernameApprovalList),
+		GitHubOrgApprovalListColumn:      buildApprovalList(ctx, cclaSignature.GithubOrgApprovalList, params.AddGithubOrgApprovalList, params.RemoveGithubOrgApprovalList),
+	}
+
+	// Keep track of existing company approvals
+	approvalList := ApprovalList{
+		DomainApprovals:         cclaSignature.DomainApprovalList,
+		GHOrgApprovals:          cclaSignature.GithubOrgApprovalList,
+		GitHubUsernameApprovals: cclaSignature.GithubUsernameApprovalList,
+		EmailApprovals:          cclaSignature.EmailApprovalList,
+		CLAManager:              claManager,
+		ICLAs:                   make([]*models.IclaSignature, 0),
+		ECLAs:                   make([]*models.Signature, 0),
+		ManagersInfo:            cclaManagers,
+		CCLASignature:           cclaSignature,
+		// The approval rules once this update is applied - used to check if a user is still approved
+		Rules: NewApprovalRules(ctx, updatedLists[EmailApprovalListColumn], updatedLists[DomainApprovalListColumn],
+			updatedLists[GitHubUsernameApprovalListColumn], updatedLists[GitHubOrgApprovalListColumn]),
+	}
+
+	// Just grab and use the first one - need to figure out conflict resolution if more than one
+	// Keep track of the approval list columns we need to update - column name => updated list
+	columnUpdates := map[string][]string{}
+
+	employeeSignatureParams := signatures.GetProjectCompanyEmployeeSignaturesParams{
+		ProjectID: projectID,
+		CompanyID: companyID,
+	}
+
+	authUser := auth.User{
+		Email:    claManager.LfEmail,
+		UserName: claManager.LfUsername,
+	}
+
+	// Keep track of gerrit users under a give CLA Group
+	var gerritICLAECLAs []string
+
+	// Only load the gerrit user information, which is costly, if we have updates to remove email or email domains
+	if (params.RemoveEmailApprovalList != nil && len(params.RemoveEmailApprovalList) > 0) || (params.RemoveDomainApprovalList != nil && len(params.RemoveDomainApprovalList) > 0) {
+
+		goRoutines := 2
+		gerritResultChannel := make(chan *GerritUserResponse, goRoutines)
+		gerritQueryStartTime, _ := utils.CurrentTime()
+		go u.getGerritUsers(ctx, &authUser, projectID, utils.ClaTypeICLA, gerritResultChannel)
+		go u.getGerritUsers(ctx, &authUser, projectID, utils.ClaTypeECLA, gerritResultChannel)
+
+		log.WithFields(f).Debug("waiting on gerrit user query results from 2 go routines...")
+		for i := 0; i < goRoutines; i++ {
+			results := <-gerritResultChannel
+			log.WithFields(f).Debugf("received gerrit user query results response for %s - took: %+v", results.queryType, time.Since(gerritQueryStartTime))
+			if results.Error != nil {
+				log.WithFields(f).WithError(results.Error).Warnf("problem retrieving gerrit users for %s, error: %+v", results.queryType, results.Error)
+			} else {
+				for _, member := range results.gerritGroupResponse.Members {
+					gerritICLAECLAs = append(gerritICLAECLAs, member.Username)
+				}
+				log.WithFields(f).Debugf("updated gerrit user query results response for %s - list size is %d...", results.queryType, len(gerritICLAECLAs))
+			}
+		}
+		log.WithFields(f).Debugf("received the gerrit user query results from %d go routines...", goRoutines)
+	}
+
+	// If we have an add or remove email list...we need to run an update for this column
+	if (params.AddEmailApprovalList != nil && len(params.AddEmailApprovalList) > 0) || (params.RemoveEmailApprovalList != nil && len(params.RemoveEmailApprovalList) > 0) {
+		columnName := EmailApprovalListColumn
+		updatedList := updatedLists[columnName]
+		// If no entries after consolidating all the updates, we need to remove the column
+		if len(updatedList) == 0 {
+			var rmColErr error
+			cclaSignature, rmColErr = u.columnStore.removeColumn(ctx, cclaSignature.SignatureID, columnName)
+			if rmColErr != nil {
+				msg := fmt.Sprintf("unable to remove column %s for signature for company ID: %s project ID: %s, type: ccla, signed: %t, approved: %t",
+					columnName, companyID, projectID, true, true)
+				log.WithFields(f).Warn(msg)
+				return nil, errors.New(msg)
+			}
+		} else {
+			columnUpdates[columnName] = updatedList
+		}
+
+		// if email removal update signature approvals
+		if params.RemoveEmailApprovalList != nil {
+			log.WithFields(f).Debugf("removing email: %+v the approval list", params.RemoveDomainApprovalList)
+			var wg sync.WaitGroup
+			wg.Add(len(params.RemoveEmailApprovalList))
+			approvalList.Criteria = utils.EmailCriteria
+			approvalList.ApprovalList = params.RemoveEmailApprovalList
+			approvalList.Action = utils.RemoveApprovals
+			approvalList.Version = claGroupModel.Version
+			for _, email := range params.RemoveEmailApprovalList {
+				go func(email string) {
+					defer wg.Done()
+					var iclas []*models.IclaSignature
+					var eclas []*models.Signature
+					log.WithFields(f).Debugf("getting cla user record for email: %s ", email)
+					userSearch, userErr := u.usersRepo.SearchUsers("user_emails", email, false)
+					if userErr != nil || userSearch == nil {
+						log.WithFields(f).Debugf("error getting user by email: %s ", email)
+						return
+					}
+					criteria := &ApprovalCriteria{
+						UserEmail: email,
+					}
+					log.WithFields(f).Debugf("Updating signature records for emailApprovalList: %+v ", params.RemoveEmailApprovalList)
+					signs, appErr := u.sigRepo.GetProjectCompanyEmployeeSignatures(ctx, employeeSignatureParams, criteria, pageSize)
+					if appErr != nil {
+						log.WithFields(f).Debugf("unable to get Company Employee signatures : %+v ", appErr)
+						return
+					}
+
+					if len(signs.Signatures) == 0 {
+						log.WithFields(f).Debugf("company employee signatures do not exist for company:%s and project: %s ", companyID, projectID)
+					}
+
+					if len(signs.Signatures) > 0 {
+						approvalList.ECLAs = signs.Signatures
+						eclas = signs.Signatures
+					}
+
+					if len(userSearch.Users) > 0 {
+						// Try and grab iclaSignature records for users
+						results := make(chan *ICLAUserResponse, len(userSearch.Users))
+						go func() {
+							defer close(results)
+							for _, user := range userSearch.Users {
+								icla, iclaErr := u.sigRepo.GetIndividualSignature(ctx, projectID, user.UserID, &approved, &signed)
+								if iclaErr != nil || icla == nil {
+									results <- &ICLAUserResponse{
+										Error: fmt.Errorf("unable to get icla for user: %s ", user.UserID),
+									}
+								} else {
+
+									// Update gerrit user
+									if utils.StringInSlice(user.LfUsername, gerritICLAECLAs) {
+										gerritIclaErr := u.gerritService.RemoveUserFromGroup(ctx, &authUser, approvalList.ClaGroupID, user.LfUsername, utils.ClaTypeICLA)
+										if gerritIclaErr != nil {
+											msg := fmt.Sprintf("unable to remove gerrit user:%s from group:%s", user.LfUsername, approvalList.ClaGroupID)
+											log.WithFields(f).WithError(gerritIclaErr).Warn(msg)
+										}
+										eclaErr := u.gerritService.RemoveUserFromGroup(ctx, &authUser, approvalList.ClaGroupID, user.LfUsername, utils.ClaTypeECLA)
+										if eclaErr != nil {
+											msg := fmt.Sprintf("unable to remove gerrit user:%s from group:%s", user.LfUsername, approvalList.ClaGroupID)
+											log.WithFields(f).WithError(eclaErr).Warn(msg)
+										}
+									}
+									results <- &ICLAUserResponse{
+										ICLASignature: &models.IclaSignature{
+											GithubUsername: icla.UserGHUsername,
+											LfUsername:     user.LfUsername,
+											SignatureID:    icla.SignatureID,
+										},
+									}
+								}
+							}
+						}()
+
+						for result := range results {
+							if result.Error == nil {
+								log.WithFields(f).Debug("processing icla...")
+								approvalList.ICLAs = append(approvalList.ICLAs, result.ICLASignature)
+								iclas = append(iclas, result.ICLASignature)
+							}
+						}
+
+					}
+
+					// Invalidate signatures
+					u.invalidateSignatures(ctx, &approvalList, claManager, eventArgs)
+
+					// Send email
+					u.sendEmail(ctx, email, &approvalList, iclas, eclas)
+
+				}(email)
+			}
+			wg.Wait()
+		}
+	}
+
+	if (params.AddDomainApprovalList != nil && len(params.AddDomainApprovalList) > 0) || (params.RemoveDomainApprovalList != nil && len(params.RemoveDomainApprovalList) > 0) {
+
+		columnName := DomainApprovalListColumn
+		updatedList := updatedLists[columnName]
+		// If no entries after consolidating all the updates, we need to remove the column
+		if len(updatedList) == 0 {
+			var rmColErr error
+			cclaSignature, rmColErr = u.columnStore.removeColumn(ctx, cclaSignature.SignatureID, columnName)
+			if rmColErr != nil {
+				msg := fmt.Sprintf("unable to remove column %s for signature for company ID: %s project ID: %s, type: ccla, signed: %t, approved: %t",
+					columnName, companyID, projectID, true, true)
+				log.WithFields(f).Warn(msg)
+				return nil, errors.New(msg)
+			}
+		} else {
+			columnUpdates[columnName] = updatedList
+		}
+		if params.RemoveDomainApprovalList != nil {
+			// Get ICLAs
+			log.WithFields(f).Debug("getting icla records... ")
+			iclas, iclaErr := u.sigRepo.GetClaGroupICLASignatures(ctx, approvalList.ClaGroupID, nil, &approved, &signed, 0, "")
+			if iclaErr != nil {
+				log.WithFields(f).Warn("unable to get iclas")
+			}
+			// Get ECLAs
+			log.WithFields(f).Debug("getting ecla records... ")
+			companyProjectParams := signatures.GetProjectCompanyEmployeeSignaturesParams{
+				CompanyID: approvalList.CompanyID,
+				ProjectID: approvalList.ClaGroupID,
+			}
+
+			criteria := ApprovalCriteria{}
+			eclas, eclaErr := u.sigRepo.GetProjectCompanyEmployeeSignatures(ctx, companyProjectParams, &criteria, int64(10))
+			if eclaErr != nil {
+				log.WithFields(f).Warnf("unable to get cclas for company: %s and project: %s ", approvalList.CompanyID, approvalList.ClaGroupID)
+			}
+
+			approvalList.Criteria = utils.EmailDomainCriteria
+			approvalList.ApprovalList = params.RemoveDomainApprovalList
+			approvalList.Action = utils.RemoveApprovals
+			approvalList.GerritICLAECLAs = gerritICLAECLAs
+			approvalList.ClaGroupID = projectID
+			approvalList.ClaGroupName = claGroupModel.ProjectName
+			approvalList.CompanyID = companyID
+			approvalList.Version = claGroupModel.Version
+			if iclas != nil {
+				approvalList.ICLAs = iclas.List
+			}
+			if eclas != nil {
+				approvalList.ECLAs = eclas.Signatures
+			}
+
+			u.invalidateSignatures(ctx, &approvalList, claManager, eventArgs)
+		}
+	}
+
+	if (params.AddGithubUsernameApprovalList != nil && len(params.AddGithubUsernameApprovalList) > 0) || (params.RemoveGithubUsernameApprovalList != nil && len(params.RemoveGithubUsernameApprovalList) > 0) {
+		columnName := GitHubUsernameApprovalListColumn
+		updatedList := updatedLists[columnName]
+		// If no entries after consolidating all the updates, we need to remove the column
+		if len(updatedList) == 0 {
+			var rmColErr error
+			cclaSignature, rmColErr = u.columnStore.removeColumn(ctx, cclaSignature.SignatureID, columnName)
+			if rmColErr != nil {
+				msg := fmt.Sprintf("unable to remove column %s for signature for company ID: %s project ID: %s, type: ccla, signed: %t, approved: %t",
+					columnName, companyID, projectID, true, true)
+				log.WithFields(f).Warn(msg)
+				return nil, errors.New(msg)
+			}
+		} else {
+			columnUpdates[columnName] = updatedList
+		}
+		if params.RemoveGithubUsernameApprovalList != nil {
+			// if email removal update signature approvals
+			if params.RemoveGithubUsernameApprovalList != nil {
+				var wg sync.WaitGroup
+				approvalList.Criteria = utils.GitHubUsernameCriteria
+				approvalList.ApprovalList = params.RemoveGithubUsernameApprovalList
+				approvalList.Action = utils.RemoveApprovals
+				approvalList.ClaGroupID = projectID
+				approvalList.ClaGroupName = claGroupModel.ProjectName
+				approvalList.CompanyID = companyID
+				approvalList.Version = claGroupModel.Version
+				wg.Add(len(params.RemoveGithubUsernameApprovalList))
+				for _, ghUsername := range params.RemoveGithubUsernameApprovalList {
+					go func(ghUsername string) {
+						defer wg.Done()
+						var iclas []*models.IclaSignature
+						var eclas []*models.Signature
+
+						criteria := &ApprovalCriteria{
+							GitHubUsername: ghUsername,
+						}
+						log.WithFields(f).Debugf("Updating signature records for ghUsernameApporvalList: %+v ", params.RemoveGithubUsernameApprovalList)
+						signs, ghUserErr := u.sigRepo.GetProjectCompanyEmployeeSignatures(ctx, employeeSignatureParams, criteria, pageSize)
+						if ghUserErr != nil {
+							log.WithFields(f).Debugf("unable to get Company Employee signatures : %+v ", ghUserErr)
+							return
+						}
+						if signs.Signatures != nil {
+							approvalList.ECLAs = signs.Signatures
+							eclas = signs.Signatures
+						}
+						// Get ICLAs
+						claUser, claErr := u.usersRepo.GetUserByGitHubUsername(ghUsername)
+						if claErr != nil {
+							log.WithFields(f).Debugf("unable to get User by GH Username: %s ", ghUsername)
+							return
+						}
+						if claUser != nil {
+							icla, iclaErr := u.sigRepo.GetIndividualSignature(ctx, projectID, claUser.UserID, &approved, &signed)
+							if iclaErr != nil || icla == nil {
+								log.WithFields(f).Debugf("unable to get icla signature for user with ghUsername: %s ", ghUsername)
+							}
+							if icla != nil {
+								// Convert to IclSignature instance to leverage invalidateSignatures helper function
+								approvalList.ICLAs = []*models.IclaSignature{{
+									GithubUsername: icla.UserGHUsername,
+									LfUsername:     icla.UserLFID,
+									SignatureID:    icla.SignatureID,
+								}}
+							}
+						}
+
+						u.invalidateSignatures(ctx, &approvalList, claManager, eventArgs)
+
+						// Send Email
+						u.sendEmail(ctx, getBestEmail(claUser), &approvalList, iclas, eclas)
+
+					}(ghUsername)
+				}
+				wg.Wait()
+			}
+		}
+	}
+
+	if (params.AddGithubOrgApprovalList != nil && len(params.AddGithubOrgApprovalList) > 0) || (params.RemoveGithubOrgApprovalList != nil && len(params.RemoveGithubOrgApprovalList) > 0) {
+		columnName := GitHubOrgApprovalListColumn
+		updatedList := updatedLists[columnName]
+		// If no entries after consolidating all the updates, we need to remove the column
+		if len(updatedList) == 0 {
+			var rmColErr error
+			cclaSignature, rmColErr = u.columnStore.removeColumn(ctx, cclaSignature.SignatureID, columnName)
+			if rmColErr != nil {
+				msg := fmt.Sprintf("unable to remove column %s for signature for company ID: %s project ID: %s, type: ccla, signed: %t, approved: %t",
+					columnName, companyID, projectID, true, true)
+				log.WithFields(f).Warn(msg)
+				return nil, errors.New(msg)
+			}
+		} else {
+			columnUpdates[columnName] = updatedList
+		}
+
+		if params.RemoveGithubOrgApprovalList != nil {
+			approvalList.Criteria = utils.GitHubOrgCriteria
+			approvalList.ApprovalList = params.RemoveGithubOrgApprovalList
+			approvalList.Action = utils.RemoveApprovals
+			approvalList.Version = claGroupModel.Version
+			// Get repositories by CLAGroup
+			repositories, getRepoByCLAGroupErr := u.repositoriesRepo.GetRepositoriesByCLAGroup(ctx, projectID, true)
+			if getRepoByCLAGroupErr != nil {
+				msg := fmt.Sprintf("unable to fetch repositories for claGroupID: %s ", projectID)
+				log.WithFields(f).WithError(getRepoByCLAGroupErr).Warn(msg)
+				return nil, errors.New(msg)
+			}
+			var ghOrgRepositories []*models.GithubRepository
+			var ghOrgs []*models.GithubOrganization
+			for _, repository := range repositories {
+				// Check for matching organization name in repositories table against approvalList removal GH Orgs
+				if utils.StringInSlice(repository.RepositoryOrganizationName, approvalList.ApprovalList) {
+					ghOrgRepositories = append(ghOrgRepositories, repository)
+				}
+			}
+
+			for _, ghOrgRepo := range ghOrgRepositories {
+				ghOrg, getGHOrgErr := u.ghOrgRepo.GetGithubOrganization(ctx, ghOrgRepo.RepositoryOrganizationName)
+				if getGHOrgErr != nil {
+					msg := fmt.Sprintf("unable to get gh org by name: %s ", ghOrgRepo.RepositoryOrganizationName)
+					log.WithFields(f).WithError(getGHOrgErr).Warn(msg)
+					return nil, errors.New(msg)
+				}
+				ghOrgs = append(ghOrgs, ghOrg)
+			}
+
+			var ghUsernames []string
+			for _, ghOrg := range ghOrgs {
+				ghOrgUsers, getOrgMembersErr := github.GetOrganizationMembers(ctx, ghOrg.OrganizationName, ghOrg.OrganizationInstallationID)
+				if getOrgMembersErr != nil {
+					msg := fmt.Sprintf("unable to fetch ghOrgUsers for org: %s ", ghOrg.OrganizationName)
+					log.WithFields(f).WithError(getOrgMembersErr).Warnf(msg)
+					return nil, errors.New(msg)
+				}
+				ghUsernames = append(ghUsernames, ghOrgUsers...)
+			}
+			approvalList.GHUsernames = utils.RemoveDuplicates(ghUsernames)
+
+			u.invalidateSignatures(ctx, &approvalList, claManager, eventArgs)
+		}
+	}
+
+	// Deny entries override any allow entry - invalidate the employee acknowledgements of the users they now match
+	if addsDenyEntries(params) {
+		log.WithFields(f).Debug("getting ecla records matching the deny entries... ")
+		companyProjectParams := signatures.GetProjectCompanyEmployeeSignaturesParams{
+			CompanyID: companyID,
+			ProjectID: projectID,
+		}
+		eclas, eclaErr := u.sigRepo.GetProjectCompanyEmployeeSignatures(ctx, companyProjectParams, &ApprovalCriteria{Rules: approvalList.Rules.denyOnly()}, pageSize)
+		if eclaErr != nil {
+			log.WithFields(f).WithError(eclaErr).Warnf("unable to get eclas for company: %s and project: %s ", companyID, projectID)
+		} else if len(eclas.Signatures) > 0 {
+			approvalList.Criteria = ApprovalDenyCriteria
+			approvalList.ApprovalList = nil
+			approvalList.Action = utils.RemoveApprovals
+			approvalList.ClaGroupID = projectID
+			approvalList.ClaGroupName = claGroupModel.ProjectName
+			approvalList.CompanyID = companyID
+			approvalList.Version = claGroupModel.Version
+			approvalList.ICLAs = nil
+			approvalList.ECLAs = eclas.Signatures
+			u.invalidateSignatures(ctx, &approvalList, claManager, eventArgs)
+		}
+	}
+
+	// Keep the expiry timestamps of the time-bounded entries in sync with the updated approval lists
+	expirations, warningsSent := buildApprovalListExpirations(approvalList.CCLASignature, updatedLists, params)
+	if len(expirations) > 0 || len(approvalList.CCLASignature.ApprovalListExpirations) > 0 {
+		expiryErr := u.sigRepo.UpdateApprovalListExpirations(ctx, approvalList.CCLASignature.SignatureID, expirations, warningsSent)
+		if expiryErr != nil {
+			log.WithFields(f).WithError(expiryErr).Warnf("unable to update the approval list expirations for company ID: %s project ID: %s", companyID, projectID)
+			return nil, expiryErr
+		}
+		if len(columnUpdates) == 0 {
+			return u.sigRepo.GetSignature(ctx, approvalList.CCLASignature.SignatureID)
+		}
+	}
+
+	// Ensure at least one value is set for us to update
+	if len(columnUpdates) == 0 {
+		log.WithFields(f).Debugf("no updates required to any of the approved list values company ID: %s project ID: %s, type: ccla, signed: %t, approved: %t - expecting at least something to update",
+			companyID, projectID, true, true)
+		return cclaSignature, nil
+	}
+
+	log.WithFields(f).Debugf("updating approval list for company ID: %s project ID: %s, type: ccla, signed: %t, approved: %t",
+		companyID, projectID, signed, approved)
+
+	updateErr := u.columnStore.updateApprovalListColumns(ctx, cclaSignature.SignatureID, columnUpdates)
+	if updateErr != nil {
+		log.WithFields(f).Warnf("error updating approval lists for company ID: %s project ID: %s, type: ccla, signed: %t, approved: %t, error: %v",
+			companyID, projectID, signed, approved, updateErr)
+		return nil, updateErr
+	}
+
+	// Query the CCLA signature once again to load the most recent updates which include approval list updates from above
+	updatedSig, err := u.sigRepo.GetCorporateSignature(ctx, projectID, companyID, &approved, &signed)
+	if err != nil || cclaSignature == nil {
+		msg := fmt.Sprintf("unable to get corporate signature for CLA Group: %s and company: %s", projectID, companyID)
+		log.WithFields(f).Warn(msg)
+		return nil, errors.New(msg)
+	}
+
+	// Just grab and use the first one - need to figure out conflict resolution if more than one
+	return updatedSig, nil
+}
+
+// sendEmail is a helper function used to render email for (CCLA, ICLA, ECLA cases)
+func (u approvalListUpdater) sendEmail(ctx context.Context, email string, approvalList *ApprovalList, iclas []*models.IclaSignature, eclas []*models.Signature) {
+	f := logrus.Fields{
+		"functionName":   "v1.signatures.repository.sendEmail",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+	}
+	companyName := ""
+	company, companyErr := u.companyRepo.GetCompany(ctx, approvalList.CompanyID)
+	if companyErr != nil {
+		log.WithFields(f).Debugf("unable to get company")
+	}
+	if company != nil {
+		companyName = company.CompanyName
+	}
+
+	params := InvalidateSignatureTemplateParams{
+		Company:       companyName,
+		RecipientName: email,
+		ClaManager:    utils.GetBestUsername(approvalList.CLAManager),
+		CLAManagers:   approvalList.ManagersInfo,
+		CLAGroupName:  approvalList.ClaGroupName,
+	}
+
+	// check for signature type (CCLA, ICLA, ECLA)
+	var removalType = ""
+
+	// case 1 CCLA
+	if len(iclas) == 0 && len(eclas) == 0 {
+		removalType = CCLA
+	} else if len(iclas) > 0 && len(eclas) == 0 {
+		// case 2 ccla + icla
+		removalType = CCLAICLA
+	} else if len(iclas) > 0 && len(eclas) > 0 {
+		// case 3 ccla + icla + ecla
+		removalType = CCLAICLAECLA
+	}
+
+	// Send CCLA Email
+	if removalType == CCLA {
+		subject := fmt.Sprintf("EasyCLA: CCLA invalidated  for :%s ", approvalList.ClaGroupName)
+		log.WithFields(f).Debugf("sending ccla invalidation email to :%s ", email)
+		body, renderErr := utils.RenderTemplate(approvalList.Version, InvalidateCCLASignatureTemplateName, InvalidateCCLASignatureTemplate, params)
+		if renderErr != nil {
+			log.WithFields(f).Debugf("unable to render email approval template for user: %s ", email)
+		} else {
+			err := utils.SendEmail(subject, body, []string{email})
+			if err != nil {
+				log.WithFields(f).Debugf("unable to send approval list update email to : %s ", email)
+			}
+		}
+	} else if removalType == ICLA {
+		subject := fmt.Sprintf("EasyCLA: ICLA invalidated  for :%s ", approvalList.ClaGroupName)
+		log.WithFields(f).Debugf("sending icla invalidation email to :%s ", email)
+		body, renderErr := utils.RenderTemplate(approvalList.Version, InvalidateICLASignatureTemplateName, InvalidateICLASignatureTemplate, params)
+		if renderErr != nil {
+			log.WithFields(f).Debugf("unable to render email approval template for user: %s ", email)
+		} else {
+			err := utils.SendEmail(subject, body, []string{email})
+			if err != nil {
+				log.WithFields(f).Debugf("unable to send approval list update email to : %s ", email)
+			}
+		}
+	} else if removalType == CCLAICLA {
+		subject := fmt.Sprintf("EasyCLA: ICLA invalidated  for :%s ", approvalList.ClaGroupName)
+		log.WithFields(f).Debugf("sending icla invalidation email to :%s ", email)
+		body, renderErr := utils.RenderTemplate(approvalList.Version, InvalidateCCLAICLASignatureTemplateName, InvalidateCCLASignatureTemplate, params)
+		if renderErr != nil {
+			log.WithFields(f).Debugf("unable to render email approval template for user: %s ", email)
+		} else {
+			err := utils.SendEmail(subject, body, []string{email})
+			if err != nil {
+				log.WithFields(f).Debugf("unable to send approval list update email to : %s ", email)
+			}
+		}
+	} else if removalType == CCLAICLAECLA {
+		subject := fmt.Sprintf("EasyCLA: Employee Acknowledgement invalidated  for :%s ", approvalList.ClaGroupName)
+		log.WithFields(f).Debugf("sending employee acknowledgement invalidation email to :%s ", email)
+		body, renderErr := utils.RenderTemplate(approvalList.Version, InvalidateCCLAICLAECLASignatureTemplateName, InvalidateCCLAICLAECLASignatureTemplate, params)
+		if renderErr != nil {
+			log.WithFields(f).Debugf("unable to render email approval template for user: %s ", email)
+		} else {
+			err := utils.SendEmail(subject, body, []string{email})
+			if err != nil {
+				log.WithFields(f).Debugf("unable to send approval list update email to : %s ", email)
+			}
+		}
+	}
+}
+
+// invalidateSignatures is a helper function that invalidates signature records based on approval list
+func (u approvalListUpdater) invalidateSignatures(ctx context.Context, approvalList *ApprovalList, claManager *models.User, eventArgs *events.LogEventArgs) {
+	f := logrus.Fields{
+		"functionName":   "v1.signatures.repository.invalidateSignatures",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     &approvalList,
+	}
+
+	if approvalList.ICLAs != nil {
+		var iclaWg sync.WaitGroup
+		//Iterate iclas
+		iclaWg.Add(len(approvalList.ICLAs))
+		log.WithFields(f).Debug("invalidating signature icla records... ")
+		for _, icla := range approvalList.ICLAs {
+			go func(icla *models.IclaSignature) {
+				defer iclaWg.Done()
+				signature, sigErr := u.sigRepo.GetSignature(ctx, icla.SignatureID)
+				if sigErr != nil {
+					log.WithFields(f).Warnf("unable to fetch signature for ID: %s ", icla.SignatureID)
+					return
+				}
+				// Grab user record
+				if signature.SignatureReferenceID == "" {
+					log.WithFields(f).Warnf("no signatureReferenceID for signature: %+v ", signature)
+					return
+				}
+
+				user, verifyErr := u.verifyUserApprovals(ctx, signature.SignatureReferenceID, signature.SignatureID, claManager, approvalList)
+				if verifyErr != nil {
+					log.WithFields(f).Warnf("unable to verify user: %s ", signature.SignatureReferenceID)
+					return
+				}
+				// Map representing CLA types against email ....
+				email := getBestEmail(user)
+				// Log Event
+				eventArgs.EventData = &events.SignatureInvalidatedApprovalRejectionEventData{
+					SignatureID: icla.SignatureID,
+					CLAManager:  claManager,
+					CLAGroupID:  signature.ProjectID,
+					Email:       email,
+				}
+				u.eventsService.LogEventWithContext(ctx, eventArgs)
+			}(icla)
+		}
+		iclaWg.Wait()
+	}
+
+	if approvalList.ECLAs != nil {
+		var eclaWg sync.WaitGroup
+		log.WithFields(f).Debug("invalidating signature ecla records... ")
+		// Iterate eclas
+		eclaWg.Add(len(approvalList.ECLAs))
+		for _, ecla := range approvalList.ECLAs {
+			go func(ecla *models.Signature) {
+				defer eclaWg.Done()
+				// Grab user record
+				if ecla.SignatureReferenceID == "" {
+					log.WithFields(f).Warnf("no signatureReferenceID for signature: %+v ", ecla)
+					return
+				}
+				user, verifyErr := u.verifyUserApprovals(ctx, ecla.SignatureReferenceID, ecla.SignatureID, claManager, approvalList)
+				if verifyErr != nil {
+					log.WithFields(f).Warnf("unable to verify user: %s ", ecla.SignatureReferenceID)
+					return
+				}
+				email := getBestEmail(user)
+				// Log Event
+				eventArgs.EventData = &events.SignatureInvalidatedApprovalRejectionEventData{
+					SignatureID: ecla.SignatureID,
+					CLAManager:  claManager,
+					CLAGroupID:  ecla.ProjectID,
+					Email:       email,
+				}
+				u.eventsService.LogEventWithContext(ctx, eventArgs)
+			}(ecla)
+		}
+		eclaWg.Wait()
+	}
+}
+
+// verify UserApprovals checks user
+func (u approvalListUpdater) verifyUserApprovals(ctx context.Context, userID, signatureID string, claManager *models.User, approvalList *ApprovalList) (*models.User, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.signatures.repository.verifyUserApprovals",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"userID":         userID,
+	}
+
+	user, err := u.usersRepo.GetUser(userID)
+	if err != nil {
+		log.WithFields(f).Warnf("unable to get user record for ID: %s ", userID)
+		return nil, err
+	}
+	email := getBestEmail(user)
+
+	authUser := auth.User{
+		Email:    claManager.LfEmail,
+		UserName: claManager.LfUsername,
+	}
+
+	if approvalList.Criteria == utils.EmailDomainCriteria {
+		// Handle Domains
+		log.WithFields(f).Debugf("Handling domain for user email: %s  with approval list: %+v ", email, approvalList.ApprovalList)
+		removed := NewApprovalRules(ctx, nil, approvalList.ApprovalList, nil, nil)
+		if removed.Explain(userApprovalCandidate(user)).Approved {
+			// Gerrit users are only approved by email, ignore any GitHub username approval for them
+			candidate := userApprovalCandidate(user)
+			if utils.StringInSlice(user.LfUsername, approvalList.GerritICLAECLAs) {
+				candidate.GitHubUsername = ""
+			}
+			if match := approvalList.stillApproved(candidate); !match.Approved {
+				//Invalidate record
+				note := fmt.Sprintf("Signature invalidated (approved set to false) by %s due to %s  removal", utils.GetBestUsername(claManager), utils.EmailDomainCriteria)
+				err := u.sigRepo.InvalidateProjectRecord(ctx, signatureID, note)
+				if err != nil {
+					log.WithFields(f).Warnf("unable to invalidate record for signatureID: %s ", signatureID)
+					return user, err
+				}
+
+				// Update Gerrit group users
+				if utils.StringInSlice(user.LfUsername, approvalList.GerritICLAECLAs) {
+					log.WithFields(f).Debugf("removing gerrit user:%s  from claGroup: %s ...", user.LfUsername, approvalList.ClaGroupID)
+					iclaErr := u.gerritService.RemoveUserFromGroup(ctx, &authUser, approvalList.ClaGroupID, user.LfUsername, utils.ClaTypeICLA)
+					if iclaErr != nil {
+						msg := fmt.Sprintf("unable to remove gerrit user:%s from group:%s", user.LfUsername, approvalList.ClaGroupID)
+						log.WithFields(f).Warn(msg)
+					}
+					eclaErr := u.gerritService.RemoveUserFromGroup(ctx, &authUser, approvalList.ClaGroupID, user.LfUsername, utils.ClaTypeECLA)
+					if eclaErr != nil {
+						msg := fmt.Sprintf("unable to remove gerrit user:%s from group:%s", user.LfUsername, approvalList.ClaGroupID)
+						log.WithFields(f).Warn(msg)
+					}
+				}
+			}
+		}
+	} else if approvalList.Criteria == utils.GitHubOrgCriteria {
+		// Handle GH Org Approvals
+		if utils.StringInSlice(user.GithubUsername, approvalList.GHUsernames) {
+			if match := approvalList.stillApproved(userApprovalCandidate(user)); !match.Approved {
+				//Invalidate record
+
+				note := fmt.Sprintf("Signature invalidated (approved set to false) by %s due to %s  removal", utils.GetBestUsername(claManager), utils.GitHubOrgCriteria)
+				err := u.sigRepo.InvalidateProjectRecord(ctx, signatureID, note)
+				if err != nil {
+					log.WithFields(f).Warnf("unable to invalidate record for signatureID: %s ", signatureID)
+					return user, err
+				}
+			}
+		}
+	} else if approvalList.Criteria == utils.GitHubUsernameCriteria || approvalList.Criteria == utils.EmailCriteria {
+		// Entries may be patterns - the user may still be approved by another entry
+		if match := approvalList.stillApproved(userApprovalCandidate(user)); match.Approved {
+			log.WithFields(f).Debugf("user is still approved, skipping invalidation: %s", match.Reason)
+			return user, nil
+		}
+		note := fmt.Sprintf("Signature invalidated (approved set to false) by %s due to %s  removal", utils.GetBestUsername(claManager), approvalList.Criteria)
+		err := u.sigRepo.InvalidateProjectRecord(ctx, signatureID, note)
+		if err != nil {
+			log.WithFields(f).Warnf("unable to invalidate record for signatureID: %s ", signatureID)
+			return user, err
+		}
+
+	} else if approvalList.Criteria == ApprovalDenyCriteria {
+		match := approvalList.stillApproved(userApprovalCandidate(user))
+		if !match.Denied {
+			return user, nil
+		}
+		note := fmt.Sprintf("Signature invalidated (approved set to false) by %s due to %s: %s", utils.GetBestUsername(claManager), ApprovalDenyCriteria, match.Reason)
+		err := u.sigRepo.InvalidateProjectRecord(ctx, signatureID, note)
+		if err != nil {
+			log.WithFields(f).Warnf("unable to invalidate record for signatureID: %s ", signatureID)
+			return user, err
+		}
+	}
+
+	return user, nil
+}
+
+// getGerritUsers is a helper function to fetch the list of gerrit users for the specified type - results are returned through the specified results channel
+func (u approvalListUpdater) getGerritUsers(ctx context.Context, authUser *auth.User, projectSFID string, claType string, gerritResultChannel chan *GerritUserResponse) {
+	f := logrus.Fields{
+		"functionName":   "v1.signatures.repository.getGerritUsers",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"projectSFID":    projectSFID,
+	}
+	log.WithFields(f).Debugf("querying gerrit for %s gerrit users...", claType)
+	gerritIclaUsers, getGerritQueryErr := u.gerritService.GetUsersOfGroup(ctx, authUser, projectSFID, claType)
+	if getGerritQueryErr != nil || gerritIclaUsers == nil {
+		msg := fmt.Sprintf("unable to fetch gerrit users for claGroup: %s , claType: %s ", projectSFID, claType)
+		log.WithFields(f).WithError(getGerritQueryErr).Warn(msg)
+		gerritResultChannel <- &GerritUserResponse{
+			gerritGroupResponse: nil,
+			queryType:           claType,
+			Error:               errors.New(msg),
+		}
+		return
+	}
+
+	log.WithFields(f).Debugf("retrieved %d gerrit users for CLA type: %s...", len(gerritIclaUsers.Members), claType)
+	gerritResultChannel <- &GerritUserResponse{
+		gerritGroupResponse: gerritIclaUsers,
+		queryType:           claType,
+		Error:               nil,
+	}
+}
@@ -0,0 +1,598 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Approval list entries are stored as plain strings in the CCLA approval list columns. Besides literal values, an
+// entry may be:
+//
+//   - a glob pattern using * and ?, e.g. *@example.com, jane.*@example.com or *.corp.example.com
+//   - for the domains, a *. or . prefix, e.g. *.example.com or .example.com, matching the domain and its subdomains
+//   - a regular expression with the regex: prefix, e.g. regex:^[a-z]+@(eng|ops)\.example\.com$
+//   - a deny entry with the ! prefix, e.g. !contractor@example.com or !*.contractors.example.com
+//
+// Matching is case insensitive. Deny entries override allow entries in any of the lists.
+//
+// The Python backend evaluates the same entries with a backtracking engine, the regular expressions are limited to
+// the syntax both backends evaluate the same way (see checkPortableRegex) and can't repeat a repetition or an
+// alternation.
+const (
+	ApprovalDenyPrefix  = "!"
+	ApprovalRegexPrefix = "regex:"
+
+	// maxApprovalRegexLength is the maximum length of the regular expression of an approval list entry
+	maxApprovalRegexLength = 256
+	// maxApprovalRegexRepeats is the maximum number of repetition operators of an approval list regular expression
+	maxApprovalRegexRepeats = 3
+	// maxApprovalRegexCount is the maximum count of a {n,m} repetition, the limit of the Go regular expressions
+	maxApprovalRegexCount = 1000
+
+	// ApprovalDenyCriteria is the approval list criteria used when signatures are invalidated by a deny entry
+	ApprovalDenyCriteria = "Deny Criteria"
+)
+
+// ApprovalCandidate holds the user attributes evaluated against the approval rules
+type ApprovalCandidate struct {
+	Emails         []string `json:"emails"`
+	GitHubUsername string   `json:"github_username"`
+	GitHubOrgs     []string `json:"github_orgs"`
+}
+
+// ApprovalMatch is the result of evaluating a candidate against the approval rules, explaining why the user is or
+// is not approved
+type ApprovalMatch struct {
+	Approved bool   `json:"approved"`
+	Denied   bool   `json:"denied"`
+	Criteria string `json:"criteria,omitempty"`
+	Entry    string `json:"entry,omitempty"`
+	Value    string `json:"value,omitempty"`
+	Reason   string `json:"reason"`
+}
+
+// Matched returns true if an allow or deny entry matched the candidate
+func (m *ApprovalMatch) Matched() bool {
+	return m.Approved || m.Denied
+}
+
+// approvalRule is a single compiled approval list entry
+type approvalRule struct {
+	criteria string
+	entry    string
+	deny     bool
+	literal  string
+	re       *regexp.Regexp
+}
+
+// matches returns true if the value matches the rule
+func (r approvalRule) matches(value string) bool {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return false
+	}
+	if r.re != nil {
+		return r.re.MatchString(value)
+	}
+	return strings.EqualFold(r.literal, value)
+}
+
+// ApprovalRules is the compiled set of approval list entries for a CCLA signature
+type ApprovalRules struct {
+	rules []approvalRule
+}
+
+// NewApprovalRules compiles the approval list entries into a rule set - invalid entries are logged and skipped
+func NewApprovalRules(ctx context.Context, emails, domains, githubUsernames, githubOrgs []string) *ApprovalRules {
+	f := logrus.Fields{
+		"functionName":   "v1.signatures.approval_rules.NewApprovalRules",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+	}
+
+	rules := &ApprovalRules{}
+	for _, list := range []struct {
+		criteria string
+		entries  []string
+	}{
+		{utils.EmailCriteria, emails},
+		{utils.EmailDomainCriteria, domains},
+		{utils.GitHubUsernameCriteria, githubUsernames},
+		{utils.GitHubOrgCriteria, githubOrgs},
+	} {
+		for _, entry := range list.entries {
+			rule, err := compileApprovalRule(list.criteria, entry)
+			if err != nil {
+				log.WithFields(f).WithError(err).Warnf("skipping invalid %s approval list entry: %s", list.criteria, entry)
+				continue
+			}
+			rules.rules = append(rules.rules, rule)
+		}
+	}
+
+	return rules
+}
+
+// NewApprovalRulesFromSignature compiles the approval list entries of the CCLA signature into a rule set
+func NewApprovalRulesFromSignature(ctx context.Context, cclaSignature *models.Signature) *ApprovalRules {
+	return NewApprovalRules(ctx, cclaSignature.EmailApprovalList, cclaSignature.DomainApprovalList,
+		cclaSignature.GithubUsernameApprovalList, cclaSignature.GithubOrgApprovalList)
+}
+
+// Explain evaluates the candidate against the rules - deny entries are checked first and override any allow entry
+func (r *ApprovalRules) Explain(candidate ApprovalCandidate) *ApprovalMatch {
+	for _, deny := range []bool{true, false} {
+		for _, rule := range r.rules {
+			if rule.deny != deny {
+				continue
+			}
+			for _, value := range candidateValues(rule.criteria, candidate) {
+				if !rule.matches(value) {
+					continue
+				}
+				if rule.deny {
+					return &ApprovalMatch{
+						Denied:   true,
+						Criteria: rule.criteria,
+						Entry:    rule.entry,
+						Value:    value,
+						Reason:   fmt.Sprintf("%s is denied by the %s entry %s", value, rule.criteria, rule.entry),
+					}
+				}
+				return &ApprovalMatch{
+					Approved: true,
+					Criteria: rule.criteria,
+					Entry:    rule.entry,
+					Value:    value,
+					Reason:   fmt.Sprintf("%s is approved by the %s entry %s", value, rule.criteria, rule.entry),
+				}
+			}
+		}
+	}
+
+	return &ApprovalMatch{
+		Reason: "no approval list entry matches the user",
+	}
+}
+
+// HasDenyEntries returns true if the rule set contains at least one deny entry
+func (r *ApprovalRules) HasDenyEntries() bool {
+	for _, rule := range r.rules {
+		if rule.deny {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateApprovalEntry checks the approval list entry for the criteria, returns a message and false if invalid
+func ValidateApprovalEntry(criteria, entry string) (string, bool) {
+	if _, err := compileApprovalRule(criteria, entry); err != nil {
+		return err.Error(), false
+	}
+	return "", true
+}
+
+// IsDenyApprovalEntry returns true if the approval list entry is a deny entry
+func IsDenyApprovalEntry(entry string) bool {
+	return strings.HasPrefix(strings.TrimSpace(entry), ApprovalDenyPrefix)
+}
+
+// isLiteralApprovalEntry returns true if the approval list entry matches a single value, i.e. it is not a pattern
+// or a deny entry
+func isLiteralApprovalEntry(entry string) bool {
+	entry = strings.TrimSpace(entry)
+	return !IsDenyApprovalEntry(entry) && !strings.HasPrefix(entry, ApprovalRegexPrefix) && !strings.ContainsAny(entry, "*?")
+}
+
+// compileApprovalRule parses and validates a single approval list entry
+func compileApprovalRule(criteria, entry string) (approvalRule, error) {
+	rule := approvalRule{
+		criteria: criteria,
+		entry:    entry,
+	}
+
+	pattern := strings.TrimSpace(entry)
+	if strings.HasPrefix(pattern, ApprovalDenyPrefix) {
+		rule.deny = true
+		pattern = strings.TrimSpace(strings.TrimPrefix(pattern, ApprovalDenyPrefix))
+	}
+	if pattern == "" {
+		return rule, fmt.Errorf("empty %s approval list entry", criteria)
+	}
+
+	// Regular expressions must match the whole value
+	if strings.HasPrefix(pattern, ApprovalRegexPrefix) {
+		expr := strings.TrimPrefix(pattern, ApprovalRegexPrefix)
+		if err := checkPortableRegex(expr); err != nil {
+			return rule, fmt.Errorf("invalid %s approval list regular expression %s - %v", criteria, entry, err)
+		}
+		re, err := regexp.Compile("(?i)^(?:" + expr + ")$")
+		if err != nil {
+			return rule, fmt.Errorf("invalid %s approval list regular expression %s - %v", criteria, entry, err)
+		}
+		rule.re = re
+		return rule, nil
+	}
+
+	// A domain with the *. or . prefix matches the domain itself and any of its subdomains
+	subdomains := false
+	if criteria == utils.EmailDomainCriteria {
+		for _, prefix := range []string{"*.", "."} {
+			if strings.HasPrefix(pattern, prefix) {
+				subdomains = true
+				pattern = strings.TrimPrefix(pattern, prefix)
+				break
+			}
+		}
+	}
+
+	// Validate the value with the wildcards replaced by a valid character
+	if msg, valid := validApprovalValue(criteria, strings.NewReplacer("*", "a", "?", "a").Replace(pattern)); !valid {
+		return rule, fmt.Errorf("invalid %s approval list entry %s - %s", criteria, entry, msg)
+	}
+
+	if !subdomains && !strings.ContainsAny(pattern, "*?") {
+		rule.literal = pattern
+		return rule, nil
+	}
+
+	var expr strings.Builder
+	expr.WriteString("(?i)^")
+	if subdomains {
+		expr.WriteString(`(?:.*\.)?`)
+	}
+	for _, c := range pattern {
+		switch c {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	expr.WriteString("$")
+	rule.re = regexp.MustCompile(expr.String())
+	return rule, nil
+}
+
+// regexGroup tracks the operators of a group of an approval list regular expression
+type regexGroup struct {
+	repeats    bool
+	alternates bool
+}
+
+// regexAtom is the kind of the last element of an approval list regular expression
+type regexAtom int
+
+const (
+	regexAtomNone regexAtom = iota
+	regexAtomSimple
+	regexAtomRepeatedGroup
+	regexAtomQuantified
+)
+
+// checkPortableRegex checks the approval list regular expression uses the syntax the Go and the Python backends
+// evaluate the same way - literals, ., character classes without the POSIX classes, the \d \w \s escapes, ^ and $,
+// (...) and (?:...) groups, | and the * + ? {n,m} repetitions. As the Python backend evaluates it with a
+// backtracking engine, a repetition can't apply to a group which repeats or alternates and the number of repetitions
+// is limited. The Python backend applies the same checks (cla/approval_rules.py).
+func checkPortableRegex(expr string) error {
+	if len(expr) > maxApprovalRegexLength {
+		return fmt.Errorf("longer than %d characters", maxApprovalRegexLength)
+	}
+
+	runes := []rune(expr)
+	groups := []*regexGroup{{}}
+	last := regexAtomNone
+	repeats := 0
+	for i := 0; i < len(runes); i++ {
+		switch c := runes[i]; c {
+		case '\\':
+			if i+1 == len(runes) {
+				return errors.New("trailing backslash")
+			}
+			i++
+			if !portableRegexEscape(runes[i]) {
+				return fmt.Errorf("unsupported escape \\%c", runes[i])
+			}
+			last = regexAtomSimple
+		case '[':
+			end, err := scanRegexCharClass(runes, i)
+			if err != nil {
+				return err
+			}
+			i = end
+			last = regexAtomSimple
+		case '(':
+			if i+1 < len(runes) && runes[i+1] == '?' {
+				if i+2 == len(runes) || runes[i+2] != ':' {
+					return errors.New("unsupported group - only the (...) and (?:...) groups are supported")
+				}
+				i += 2
+			}
+			groups = append(groups, &regexGroup{})
+			last = regexAtomNone
+		case ')':
+			if len(groups) == 1 {
+				return errors.New("unexpected )")
+			}
+			group := groups[len(groups)-1]
+			groups = groups[:len(groups)-1]
+			parent := groups[len(groups)-1]
+			parent.repeats = parent.repeats || group.repeats
+			parent.alternates = parent.alternates || group.alternates
+			last = regexAtomSimple
+			if group.repeats || group.alternates {
+				last = regexAtomRepeatedGroup
+			}
+		case '|':
+			groups[len(groups)-1].alternates = true
+			last = regexAtomNone
+		case '^', '$':
+			last = regexAtomNone
+		case '*', '+', '?', '{':
+			loop := c != '?'
+			if c == '{' {
+				end, upper, err := scanRegexRepeat(runes, i)
+				if err != nil {
+					return err
+				}
+				i = end
+				loop = upper != 1
+			}
+			switch last {
+			case regexAtomNone:
+				return fmt.Errorf("missing argument to repetition operator %c", c)
+			case regexAtomQuantified:
+				return errors.New("nested repetition operator")
+			case regexAtomRepeatedGroup:
+				if loop {
+					return errors.New("repetition of a group which repeats or alternates")
+				}
+			}
+			if loop {
+				repeats++
+				if repeats > maxApprovalRegexRepeats {
+					return fmt.Errorf("more than %d repetitions", maxApprovalRegexRepeats)
+				}
+				groups[len(groups)-1].repeats = true
+			}
+			// the non-greedy modifier
+			if i+1 < len(runes) && runes[i+1] == '?' {
+				i++
+			}
+			last = regexAtomQuantified
+		default:
+			last = regexAtomSimple
+		}
+	}
+	if len(groups) != 1 {
+		return errors.New("missing )")
+	}
+	return nil
+}
+
+// portableRegexEscape returns true if the escaped character is supported - the ASCII punctuation and the \d \w \s
+// classes and their negation
+func portableRegexEscape(c rune) bool {
+	return strings.ContainsRune(`!"#$%&'()*+,-./:;<=>?@[\]^_{|}~`+"`", c) || strings.ContainsRune("dDwWsS", c)
+}
+
+// scanRegexCharClass returns the index of the ] closing the character class starting at the index
+func scanRegexCharClass(runes []rune, start int) (int, error) {
+	i := start + 1
+	if i < len(runes) && runes[i] == '^' {
+		i++
+	}
+	// a leading ] is a literal
+	if i < len(runes) && runes[i] == ']' {
+		i++
+	}
+	for ; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			if i+1 == len(runes) || !portableRegexEscape(runes[i+1]) {
+				return 0, errors.New("unsupported escape in character class")
+			}
+			i++
+		case '[':
+			return 0, errors.New("unsupported [ in character class - POSIX classes aren't supported, escape the literal [")
+		case ']':
+			return i, nil
+		}
+	}
+	return 0, errors.New("missing ]")
+}
+
+// scanRegexRepeat parses the {n}, {n,} or {n,m} repetition starting at the index, returns the index of the closing }
+// and the maximum count, -1 when unbounded
+func scanRegexRepeat(runes []rune, start int) (int, int, error) {
+	invalid := errors.New("invalid repetition - escape the literal {")
+	end := start + 1
+	for end < len(runes) && runes[end] != '}' {
+		end++
+	}
+	if end == len(runes) {
+		return 0, 0, invalid
+	}
+	bounds := strings.SplitN(string(runes[start+1:end]), ",", 2)
+	lower, ok := parseRegexCount(bounds[0])
+	if !ok {
+		return 0, 0, invalid
+	}
+	if len(bounds) == 1 {
+		return end, lower, nil
+	}
+	if bounds[1] == "" {
+		return end, -1, nil
+	}
+	upper, ok := parseRegexCount(bounds[1])
+	if !ok || upper < lower {
+		return 0, 0, invalid
+	}
+	return end, upper, nil
+}
+
+// parseRegexCount parses the count of a repetition, only digits are allowed
+func parseRegexCount(value string) (int, bool) {
+	if value == "" || strings.Trim(value, "0123456789") != "" {
+		return 0, false
+	}
+	count, err := strconv.Atoi(value)
+	if err != nil || count > maxApprovalRegexCount {
+		return 0, false
+	}
+	return count, true
+}
+
+// validApprovalValue validates a literal approval list value for the criteria
+func validApprovalValue(criteria, value string) (string, bool) {
+	switch criteria {
+	case utils.EmailCriteria:
+		if !utils.ValidEmail(value) {
+			return "invalid email address", false
+		}
+		return "", true
+	case utils.EmailDomainCriteria:
+		return utils.ValidDomain(value, false)
+	case utils.GitHubUsernameCriteria:
+		return utils.ValidGitHubUsername(value)
+	case utils.GitHubOrgCriteria:
+		return utils.ValidGitHubOrg(value)
+	}
+	return fmt.Sprintf("unsupported approval criteria %s", criteria), false
+}
+
+// candidateValues returns the candidate values evaluated for the criteria
+func candidateValues(criteria string, candidate ApprovalCandidate) []string {
+	switch criteria {
+	case utils.EmailCriteria:
+		return candidate.Emails
+	case utils.EmailDomainCriteria:
+		var domains []string
+		for _, email := range candidate.Emails {
+			if idx := strings.LastIndex(email, "@"); idx >= 0 {
+				domains = append(domains, email[idx+1:])
+			}
+		}
+		return domains
+	case utils.GitHubUsernameCriteria:
+		return []string{candidate.GitHubUsername}
+	case utils.GitHubOrgCriteria:
+		return candidate.GitHubOrgs
+	}
+	return nil
+}
+
+// matches returns true if the employee signature record satisfies the criteria - the email and GitHub username
+// criteria may be approval list patterns
+func (c *ApprovalCriteria) matches(item *ItemSignature) bool {
+	if c == nil {
+		return true
+	}
+	if c.GitHubUsername != "" && !criteriaValueMatches(utils.GitHubUsernameCriteria, c.GitHubUsername, item.UserGithubUsername) {
+		return false
+	}
+	if c.UserEmail != "" && !criteriaValueMatches(utils.EmailCriteria, c.UserEmail, item.UserEmail) {
+		return false
+	}
+	if c.Rules != nil {
+		return c.Rules.Explain(ApprovalCandidate{
+			Emails:         []string{item.UserEmail},
+			GitHubUsername: item.UserGithubUsername,
+		}).Matched()
+	}
+	return true
+}
+
+// criteriaValueMatches returns true if the value matches the criteria entry, falling back to a case insensitive
+// comparison when the entry can't be compiled - like the approval rules of both backends
+func criteriaValueMatches(criteria, entry, value string) bool {
+	if isLiteralApprovalEntry(entry) {
+		return strings.EqualFold(strings.TrimSpace(entry), strings.TrimSpace(value))
+	}
+	rule, err := compileApprovalRule(criteria, entry)
+	if err != nil {
+		return strings.EqualFold(strings.TrimSpace(entry), strings.TrimSpace(value))
+	}
+	return rule.matches(value)
+}
+
+// userApprovalCandidate returns the approval candidate for the user record
+func userApprovalCandidate(user *models.User) ApprovalCandidate {
+	if user == nil {
+		return ApprovalCandidate{}
+	}
+	emails := []string{user.LfEmail}
+	emails = append(emails, user.Emails...)
+	return ApprovalCandidate{
+		Emails:         utils.RemoveDuplicates(emails),
+		GitHubUsername: user.GithubUsername,
+	}
+}
+
+// denyOnly returns the deny rules of the rule set
+func (r *ApprovalRules) denyOnly() *ApprovalRules {
+	deny := &ApprovalRules{}
+	for _, rule := range r.rules {
+		if rule.deny {
+			deny.rules = append(deny.rules, rule)
+		}
+	}
+	return deny
+}
+
+// stillApproved evaluates the candidate against the approval rules once the update is applied
+func (l *ApprovalList) stillApproved(candidate ApprovalCandidate) *ApprovalMatch {
+	if l.Rules == nil {
+		return &ApprovalMatch{
+			Reason: "no approval rules loaded",
+		}
+	}
+	return l.Rules.Explain(candidate)
+}
+
+// addsDenyEntries returns true if the approval list update adds at least one deny entry
+func addsDenyEntries(params *models.ApprovalList) bool {
+	for _, list := range [][]string{params.AddEmailApprovalList, params.AddDomainApprovalList, params.AddGithubUsernameApprovalList, params.AddGithubOrgApprovalList} {
+		for _, entry := range list {
+			if IsDenyApprovalEntry(entry) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validateApprovalListEntries validates the entries added by the approval list update
+func validateApprovalListEntries(params *models.ApprovalList) error {
+	for _, list := range []struct {
+		criteria string
+		entries  []string
+	}{
+		{utils.EmailCriteria, params.AddEmailApprovalList},
+		{utils.EmailDomainCriteria, params.AddDomainApprovalList},
+		{utils.GitHubUsernameCriteria, params.AddGithubUsernameApprovalList},
+		{utils.GitHubOrgCriteria, params.AddGithubOrgApprovalList},
+	} {
+		for _, entry := range list.entries {
+			if msg, valid := ValidateApprovalEntry(list.criteria, entry); !valid {
+				return errors.New(msg)
+			}
+		}
+	}
+	return nil
+}
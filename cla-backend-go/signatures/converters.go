@@ -8,11 +8,12 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/communitybridge/easycla/cla-backend-go/company"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
 	"github.com/sirupsen/logrus"
 )
@@ -24,7 +25,6 @@ func (repo repository) buildProjectSignatureModels(ctx context.Context, results
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
 		"claGroupID":     claGroupID,
 	}
-	var sigs []*models.Signature
 
 	// The DB signature model
 	var dbSignatures []ItemSignature
@@ -36,6 +36,17 @@ func (repo repository) buildProjectSignatureModels(ctx context.Context, results
 		return nil, err
 	}
 
+	return buildSignatureModels(ctx, repo.usersRepo, repo.companyRepo, dbSignatures, loadACLDetails), nil
+}
+
+// buildSignatureModels converts the signature database models into response models, loading the user, company and ACL details
+func buildSignatureModels(ctx context.Context, usersRepo users.UserRepository, companyRepo company.IRepository, dbSignatures []ItemSignature, loadACLDetails bool) []*models.Signature {
+	f := logrus.Fields{
+		"functionName":   "v1.signatures.converters.buildSignatureModels",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+	}
+	var sigs []*models.Signature
+
 	var wg sync.WaitGroup
 	wg.Add(len(dbSignatures))
 	for _, dbSignature := range dbSignatures {
@@ -92,6 +103,13 @@ func (repo repository) buildProjectSignatureModels(ctx context.Context, results
 			SignatoryName:               dbSignature.SignatoryName,
 			UserDocusignName:            dbSignature.UserDocusignName,
 			UserDocusignDateSigned:      dbSignature.UserDocusignDateSigned,
+			// Time-bounded approval list entries
+			ApprovalListExpirations:        dbSignature.ApprovalListExpirations,
+			ApprovalListExpiryWarningsSent: dbSignature.ApprovalListExpiryWarningsSent,
+			// Signed document integrity
+			SignatureDocumentSha256:          dbSignature.SignatureDocumentSHA256,
+			SignatureDocumentIntegrityStatus: dbSignature.SignatureDocumentIntegrityStatus,
+			SignatureDocumentVerifiedOn:      dbSignature.SignatureDocumentVerifiedOn,
 		}
 		sigs = append(sigs, sig)
 		go func(sigModel *models.Signature, signatureUserCompanyID string, sigACL []string) {
@@ -108,7 +126,7 @@ func (repo repository) buildProjectSignatureModels(ctx context.Context, results
 			go func() {
 				defer swg.Done()
 				if sigModel.SignatureReferenceType == utils.SignatureReferenceTypeUser {
-					userModel, userErr := repo.usersRepo.GetUser(sigModel.SignatureReferenceID)
+					userModel, userErr := usersRepo.GetUser(sigModel.SignatureReferenceID)
 					if userErr != nil || userModel == nil {
 						log.WithFields(f).WithError(userErr).Warnf("unable to lookup user for signature: %s with reference type: %s using signature reference id: %s",
 							sigModel.SignatureID, sigModel.SignatureReferenceType, sigModel.SignatureReferenceID)
@@ -120,7 +138,7 @@ func (repo repository) buildProjectSignatureModels(ctx context.Context, results
 					}
 
 					if signatureUserCompanyID != "" {
-						dbCompanyModel, companyErr := repo.companyRepo.GetCompany(ctx, signatureUserCompanyID)
+						dbCompanyModel, companyErr := companyRepo.GetCompany(ctx, signatureUserCompanyID)
 						if companyErr != nil {
 							log.WithFields(f).WithError(companyErr).Warnf("unable to lookup company record for signature: %s with reference type: %s using signature user company id: %s",
 								sigModel.SignatureID, sigModel.SignatureReferenceType, signatureUserCompanyID)
@@ -130,7 +148,7 @@ func (repo repository) buildProjectSignatureModels(ctx context.Context, results
 						}
 					}
 				} else if sigModel.SignatureReferenceType == utils.SignatureReferenceTypeCompany {
-					dbCompanyModel, companyErr := repo.companyRepo.GetCompany(ctx, sigModel.SignatureReferenceID)
+					dbCompanyModel, companyErr := companyRepo.GetCompany(ctx, sigModel.SignatureReferenceID)
 					if companyErr != nil {
 						log.WithFields(f).WithError(companyErr).Warnf("unable to lookup company record for signature: %s with reference type: %s using signature reference id: %s",
 							sigModel.SignatureID, sigModel.SignatureReferenceType, sigModel.SignatureReferenceID)
@@ -146,7 +164,7 @@ func (repo repository) buildProjectSignatureModels(ctx context.Context, results
 				defer swg.Done()
 				for _, userName := range sigACL {
 					if loadACLDetails {
-						userModel, userErr := repo.usersRepo.GetUserByUserName(userName, true)
+						userModel, userErr := usersRepo.GetUserByUserName(userName, true)
 						if userErr != nil {
 							log.WithFields(f).WithError(userErr).Warnf("unable to lookup user by userNmae: %s in ACL for signature: %s", userName, sigModel.SignatureID)
 						} else {
@@ -172,7 +190,7 @@ func (repo repository) buildProjectSignatureModels(ctx context.Context, results
 		}(sig, dbSignature.SignatureUserCompanyID, dbSignature.SignatureACL)
 	}
 	wg.Wait()
-	return sigs, nil
+	return sigs
 }
 
 // buildProjectSignatureSummaryModels converts the response model into a signature summary model
@@ -182,7 +200,6 @@ func (repo repository) buildProjectSignatureSummaryModels(ctx context.Context, r
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
 		"projectID":      projectID,
 	}
-	var sigs []*models.SignatureSummary
 
 	// The DB signature model
 	var dbSignatures []ItemSignature
@@ -194,6 +211,17 @@ func (repo repository) buildProjectSignatureSummaryModels(ctx context.Context, r
 		return nil, err
 	}
 
+	return buildSignatureSummaryModels(ctx, repo.companyRepo, dbSignatures), nil
+}
+
+// buildSignatureSummaryModels converts the signature database models into signature summary models, loading the company details
+func buildSignatureSummaryModels(ctx context.Context, companyRepo company.IRepository, dbSignatures []ItemSignature) []*models.SignatureSummary {
+	f := logrus.Fields{
+		"functionName":   "v1.signatures.converters.buildSignatureSummaryModels",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+	}
+	var sigs []*models.SignatureSummary
+
 	var wg sync.WaitGroup
 	wg.Add(len(dbSignatures))
 	for _, dbSignature := range dbSignatures {
@@ -243,7 +271,7 @@ func (repo repository) buildProjectSignatureSummaryModels(ctx context.Context, r
 				defer swg.Done()
 				if sigModel.SignatureReferenceType == "user" {
 					if signatureUserCompanyID != "" {
-						dbCompanyModel, companyErr := repo.companyRepo.GetCompany(ctx, signatureUserCompanyID)
+						dbCompanyModel, companyErr := companyRepo.GetCompany(ctx, signatureUserCompanyID)
 						if companyErr != nil {
 							log.WithFields(f).WithError(companyErr).Warnf("unable to lookup company record for signature: %s with reference type: %s using signature user company id: %s",
 								sigModel.SignatureID, sigModel.SignatureReferenceType, signatureUserCompanyID)
@@ -253,7 +281,7 @@ func (repo repository) buildProjectSignatureSummaryModels(ctx context.Context, r
 						}
 					}
 				} else if sigModel.SignatureReferenceType == "company" {
-					dbCompanyModel, companyErr := repo.companyRepo.GetCompany(ctx, sigModel.SignatureReferenceID)
+					dbCompanyModel, companyErr := companyRepo.GetCompany(ctx, sigModel.SignatureReferenceID)
 					if companyErr != nil {
 						log.WithFields(f).WithError(companyErr).Warnf("unable to lookup company record for signature: %s with reference type: %s using signature reference id: %s",
 							sigModel.SignatureID, sigModel.SignatureReferenceType, sigModel.SignatureReferenceID)
@@ -271,7 +299,7 @@ func (repo repository) buildProjectSignatureSummaryModels(ctx context.Context, r
 	}
 
 	wg.Wait()
-	return sigs, nil
+	return sigs
 }
 
 // buildResponse is a helper function which converts a database model to a GitHub organization response model
@@ -289,24 +317,24 @@ func buildResponse(items []*dynamodb.AttributeValue) []models.GithubOrg {
 	return orgs
 }
 
-// buildApprovalAttributeList builds the updated approval list based on the added and removed values
-func buildApprovalAttributeList(ctx context.Context, existingList, addEntries, removeEntries []string) *dynamodb.AttributeValue {
+// buildApprovalList builds the updated approval list based on the added and removed values
+func buildApprovalList(ctx context.Context, existingList, addEntries, removeEntries []string) []string {
 	f := logrus.Fields{
-		"functionName":   "buildApprovalAttributeList",
+		"functionName":   "buildApprovalList",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
 	}
 	var updatedList []string
-	log.WithFields(f).Debugf("buildApprovalAttributeList - existing: %+v, add entries: %+v, remove entries: %+v",
+	log.WithFields(f).Debugf("buildApprovalList - existing: %+v, add entries: %+v, remove entries: %+v",
 		existingList, addEntries, removeEntries)
 
 	// Add the existing entries to our response
 	for _, value := range existingList {
 		// No duplicates allowed
 		if !utils.StringInSlice(value, updatedList) {
-			log.WithFields(f).Debugf("buildApprovalAttributeList - adding existing entry: %s", value)
+			log.WithFields(f).Debugf("buildApprovalList - adding existing entry: %s", value)
 			updatedList = append(updatedList, strings.TrimSpace(value))
 		} else {
-			log.WithFields(f).Debugf("buildApprovalAttributeList - skipping existing entry: %s", value)
+			log.WithFields(f).Debugf("buildApprovalList - skipping existing entry: %s", value)
 		}
 	}
 
@@ -314,30 +342,24 @@ func buildApprovalAttributeList(ctx context.Context, existingList, addEntries, r
 	for _, value := range addEntries {
 		// No duplicates allowed
 		if !utils.StringInSlice(value, updatedList) {
-			log.WithFields(f).Debugf("buildApprovalAttributeList - adding new entry: %s", value)
+			log.WithFields(f).Debugf("buildApprovalList - adding new entry: %s", value)
 			updatedList = append(updatedList, strings.TrimSpace(value))
 		} else {
-			log.WithFields(f).Debugf("buildApprovalAttributeList - skipping new entry: %s", value)
+			log.WithFields(f).Debugf("buildApprovalList - skipping new entry: %s", value)
 		}
 	}
 
 	// Remove the items
-	log.WithFields(f).Debugf("buildApprovalAttributeList - before: %+v - removing entries: %+v", updatedList, removeEntries)
+	log.WithFields(f).Debugf("buildApprovalList - before: %+v - removing entries: %+v", updatedList, removeEntries)
 	updatedList = utils.RemoveItemsFromList(updatedList, removeEntries)
-	log.WithFields(f).Debugf("buildApprovalAttributeList - after: %+v - removing entries: %+v", updatedList, removeEntries)
+	log.WithFields(f).Debugf("buildApprovalList - after: %+v - removing entries: %+v", updatedList, removeEntries)
 
 	// Remove any duplicates - shouldn't have any if checked before adding
-	log.WithFields(f).Debugf("buildApprovalAttributeList - before: %+v - removing duplicates", updatedList)
+	log.WithFields(f).Debugf("buildApprovalList - before: %+v - removing duplicates", updatedList)
 	updatedList = utils.RemoveDuplicates(updatedList)
-	log.WithFields(f).Debugf("buildApprovalAttributeList - after: %+v - removing duplicates", updatedList)
+	log.WithFields(f).Debugf("buildApprovalList - after: %+v - removing duplicates", updatedList)
 
-	// Convert to the response type
-	var responseList []*dynamodb.AttributeValue
-	for _, value := range updatedList {
-		responseList = append(responseList, &dynamodb.AttributeValue{S: aws.String(value)})
-	}
-
-	return &dynamodb.AttributeValue{L: responseList}
+	return updatedList
 }
 
 // buildCompanyIDList is a helper function to convert the DB response models into a simple list of company IDs
@@ -346,7 +368,6 @@ func (repo repository) buildCompanyIDList(ctx context.Context, results *dynamodb
 		"functionName":   "buildCompanyIDList",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
 	}
-	var response []SignatureCompanyID
 
 	// The DB signature model
 	var dbSignatures []ItemSignature
@@ -356,10 +377,21 @@ func (repo repository) buildCompanyIDList(ctx context.Context, results *dynamodb
 		return nil, err
 	}
 
+	return buildCompanyIDs(ctx, repo.companyRepo, dbSignatures), nil
+}
+
+// buildCompanyIDs is a helper function to convert the signature database models into a simple list of company IDs
+func buildCompanyIDs(ctx context.Context, companyRepo company.IRepository, dbSignatures []ItemSignature) []SignatureCompanyID {
+	f := logrus.Fields{
+		"functionName":   "buildCompanyIDs",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+	}
+	var response []SignatureCompanyID
+
 	// Loop and extract the company ID (signature_reference_id) value
 	for _, item := range dbSignatures {
 		// Lookup the company by ID - try to get more information like the external ID and name
-		companyModel, companyLookupErr := repo.companyRepo.GetCompany(ctx, item.SignatureReferenceID)
+		companyModel, companyLookupErr := companyRepo.GetCompany(ctx, item.SignatureReferenceID)
 		// Start building a model for this entry in the list
 		signatureCompanyID := SignatureCompanyID{
 			SignatureID: item.SignatureID,
@@ -381,5 +413,5 @@ func (repo repository) buildCompanyIDList(ctx context.Context, results *dynamodb
 		}
 	}
 
-	return response, nil
+	return response
 }
@@ -33,6 +33,17 @@ type ItemSignature struct {
 	SignatoryName                 string   `json:"signatory_name"`
 	UserDocusignName              string   `json:"user_docusign_name"`
 	UserDocusignDateSigned        string   `json:"user_docusign_date_signed"`
+	Note                          string   `json:"note"`
+	// Expiry timestamps of the time-bounded approval list entries, keyed by <column>:<entry>
+	ApprovalListExpirations        map[string]string `json:"approval_list_expirations"`
+	ApprovalListExpiryWarningsSent []string          `json:"approval_list_expiry_warnings_sent"`
+	// Attributes of the sparse approval list expiry index, only set when the signature has time-bounded entries
+	ApprovalListExpiryPartition string `json:"approval_list_expiry_partition,omitempty"`
+	ApprovalListNextExpiry      string `json:"approval_list_next_expiry,omitempty"`
+	// SHA-256 of the signed document recorded when it is stored, and the outcome of the last integrity check
+	SignatureDocumentSHA256          string `json:"signature_document_sha256"`
+	SignatureDocumentIntegrityStatus string `json:"signature_document_integrity_status"`
+	SignatureDocumentVerifiedOn      string `json:"signature_document_verified_on"`
 }
 
 // DBManagersModel is a database model for only the ACL/Manager column
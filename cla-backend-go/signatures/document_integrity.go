@@ -0,0 +1,258 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// signed document integrity check statuses
+const (
+	// DocumentIntegrityVerified the stored document matches the hash recorded when it was stored
+	DocumentIntegrityVerified = "verified"
+	// DocumentIntegrityBaselined no hash was recorded when the document was stored (older signatures), the hash of the
+	// stored document is recorded and verified by the next checks
+	DocumentIntegrityBaselined = "baselined"
+	// DocumentIntegrityMissing the document is not stored anymore
+	DocumentIntegrityMissing = "missing"
+	// DocumentIntegrityModified the stored document does not match the recorded hash
+	DocumentIntegrityModified = "modified"
+)
+
+// DocumentIntegrityReverifyPeriod is how often the batch job verifies each signed document
+const DocumentIntegrityReverifyPeriod = 30 * 24 * time.Hour
+
+// MaxDocumentIntegrityChecksPerRun is the number of documents downloaded and verified by a batch job run
+const MaxDocumentIntegrityChecksPerRun = 2000
+
+// ErrSignatureNotFound is returned when the verified signature does not exist
+var ErrSignatureNotFound = errors.New("signature not found")
+
+// ErrNoSignedDocument is returned when the signature has no signed document, e.g. the employee acknowledgements
+var ErrNoSignedDocument = errors.New("the signature does not have a signed document")
+
+// SignatureDocument identifies the signed document of a signature and its last integrity check
+type SignatureDocument struct {
+	SignatureID string
+	ClaGroupID  string
+	// ClaType is icla or ccla, the signature files bucket folder
+	ClaType string
+	// ReferenceID is the user ID of the ICLAs, the company ID of the CCLAs
+	ReferenceID     string
+	DocumentSHA256  string
+	IntegrityStatus string
+	VerifiedOn      string
+}
+
+// DocumentIntegrityResult is the outcome of the integrity check of a signed document
+type DocumentIntegrityResult struct {
+	SignatureID    string `json:"signature_id"`
+	Status         string `json:"status"`
+	ExpectedSHA256 string `json:"expected_sha256"`
+	ActualSHA256   string `json:"actual_sha256"`
+	VerifiedOn     string `json:"verified_on"`
+}
+
+// Failed returns true if the stored document is missing or changed
+func (r *DocumentIntegrityResult) Failed() bool {
+	return r.Status == DocumentIntegrityMissing || r.Status == DocumentIntegrityModified
+}
+
+// DocumentIntegrityReport summarizes a document integrity batch job run
+type DocumentIntegrityReport struct {
+	Checked   int `json:"checked"`
+	Verified  int `json:"verified"`
+	Baselined int `json:"baselined"`
+	Failed    int `json:"failed"`
+	Errors    int `json:"errors"`
+}
+
+// DocumentIntegrityService verifies the stored signed documents against the hashes recorded on the signatures
+type DocumentIntegrityService interface {
+	VerifySignatureDocument(ctx context.Context, signatureID string) (*DocumentIntegrityResult, error)
+	VerifySignatureDocuments(ctx context.Context, now time.Time) (*DocumentIntegrityReport, error)
+}
+
+type documentIntegrityService struct {
+	repo          SignatureRepository
+	storage       utils.S3Storage
+	eventsService events.Service
+}
+
+// NewDocumentIntegrityService creates a new document integrity service
+func NewDocumentIntegrityService(repo SignatureRepository, storage utils.S3Storage, eventsService events.Service) DocumentIntegrityService {
+	return &documentIntegrityService{
+		repo:          repo,
+		storage:       storage,
+		eventsService: eventsService,
+	}
+}
+
+// signatureDocumentFromModel returns the signed document of the signature, nil for the employee acknowledgements
+func signatureDocumentFromModel(sig *models.Signature) *SignatureDocument {
+	doc := &SignatureDocument{
+		SignatureID:     sig.SignatureID,
+		ClaGroupID:      sig.ProjectID,
+		ReferenceID:     sig.SignatureReferenceID,
+		DocumentSHA256:  sig.SignatureDocumentSha256,
+		IntegrityStatus: sig.SignatureDocumentIntegrityStatus,
+		VerifiedOn:      sig.SignatureDocumentVerifiedOn,
+	}
+	switch {
+	case sig.SignatureType == utils.SignatureTypeCCLA:
+		doc.ClaType = utils.ClaTypeCCLA
+	case sig.SignatureType == utils.SignatureTypeCLA && sig.CompanyName == "":
+		doc.ClaType = utils.ClaTypeICLA
+	default:
+		return nil
+	}
+	return doc
+}
+
+// signatureDocumentFromItem returns the signed document of the ICLA or CCLA signature record
+func signatureDocumentFromItem(item *ItemSignature) *SignatureDocument {
+	claType := utils.ClaTypeICLA
+	if item.SignatureType == utils.SignatureTypeCCLA {
+		claType = utils.ClaTypeCCLA
+	}
+	return &SignatureDocument{
+		SignatureID:     item.SignatureID,
+		ClaGroupID:      item.SignatureProjectID,
+		ClaType:         claType,
+		ReferenceID:     item.SignatureReferenceID,
+		DocumentSHA256:  item.SignatureDocumentSHA256,
+		IntegrityStatus: item.SignatureDocumentIntegrityStatus,
+		VerifiedOn:      item.SignatureDocumentVerifiedOn,
+	}
+}
+
+// VerifySignatureDocument downloads the signed document of the signature and verifies it against the recorded hash
+func (s *documentIntegrityService) VerifySignatureDocument(ctx context.Context, signatureID string) (*DocumentIntegrityResult, error) {
+	sig, err := s.repo.GetSignature(ctx, signatureID)
+	if err != nil {
+		return nil, err
+	}
+	if sig == nil {
+		return nil, ErrSignatureNotFound
+	}
+	doc := signatureDocumentFromModel(sig)
+	if doc == nil || !sig.SignatureSigned {
+		return nil, ErrNoSignedDocument
+	}
+	return s.verify(ctx, doc, time.Now().UTC())
+}
+
+// VerifySignatureDocuments verifies the signed documents which were not verified for a month, the documents left
+// over by a run are verified by the next runs
+func (s *documentIntegrityService) VerifySignatureDocuments(ctx context.Context, now time.Time) (*DocumentIntegrityReport, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.signatures.document_integrity.VerifySignatureDocuments",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"now":            now.Format(time.RFC3339),
+	}
+
+	verifiedBefore := now.Add(-DocumentIntegrityReverifyPeriod).Format(time.RFC3339)
+	docs, err := s.repo.GetSignatureDocumentsForVerification(ctx, verifiedBefore, MaxDocumentIntegrityChecksPerRun)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the signed documents to verify")
+		return nil, err
+	}
+	log.WithFields(f).Debugf("verifying %d signed documents", len(docs))
+
+	report := &DocumentIntegrityReport{}
+	for _, doc := range docs {
+		result, verifyErr := s.verify(ctx, doc, now)
+		if verifyErr != nil {
+			log.WithFields(f).WithError(verifyErr).Warnf("unable to verify the signed document of signature: %s", doc.SignatureID)
+			report.Errors++
+			continue
+		}
+		report.Checked++
+		switch {
+		case result.Failed():
+			report.Failed++
+		case result.Status == DocumentIntegrityBaselined:
+			report.Baselined++
+		default:
+			report.Verified++
+		}
+	}
+
+	log.WithFields(f).Infof("document integrity results: %+v", report)
+	return report, nil
+}
+
+// verify compares the stored document with the recorded hash, records the outcome on the signature and logs an
+// integrity failure event when the document went missing or changed since the previous check
+func (s *documentIntegrityService) verify(ctx context.Context, doc *SignatureDocument, now time.Time) (*DocumentIntegrityResult, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.signatures.document_integrity.verify",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"signatureID":    doc.SignatureID,
+		"claGroupID":     doc.ClaGroupID,
+	}
+
+	result := &DocumentIntegrityResult{
+		SignatureID:    doc.SignatureID,
+		ExpectedSHA256: doc.DocumentSHA256,
+		VerifiedOn:     now.Format(time.RFC3339),
+	}
+	content, err := s.storage.Download(utils.SignedCLAFilename(doc.ClaGroupID, doc.ClaType, doc.ReferenceID, doc.SignatureID))
+	switch {
+	case err == utils.ErrS3ObjectNotFound:
+		result.Status = DocumentIntegrityMissing
+	case err != nil:
+		return nil, err
+	default:
+		result.ActualSHA256 = utils.DocumentSHA256(content)
+		switch {
+		case doc.DocumentSHA256 == "":
+			result.Status = DocumentIntegrityBaselined
+			result.ExpectedSHA256 = result.ActualSHA256
+		case doc.DocumentSHA256 == result.ActualSHA256:
+			result.Status = DocumentIntegrityVerified
+		default:
+			result.Status = DocumentIntegrityModified
+		}
+	}
+
+	// the baseline hash is recorded once, the recorded hash is never replaced by the hash of a changed document
+	var documentSHA256 string
+	if result.Status == DocumentIntegrityBaselined {
+		documentSHA256 = result.ActualSHA256
+	}
+	if err = s.repo.UpdateSignatureDocumentIntegrity(ctx, doc.SignatureID, documentSHA256, result.Status, result.VerifiedOn); err != nil {
+		return nil, err
+	}
+
+	if result.Failed() && doc.IntegrityStatus != result.Status {
+		log.WithFields(f).Warnf("signed document integrity check failed: %s", result.Status)
+		eventArgs := &events.LogEventArgs{
+			EventType:  events.SignatureIntegrityFailed,
+			CLAGroupID: doc.ClaGroupID,
+			EventData: &events.SignatureIntegrityFailedEventData{
+				SignatureID:    doc.SignatureID,
+				Status:         result.Status,
+				ExpectedSHA256: result.ExpectedSHA256,
+				ActualSHA256:   result.ActualSHA256,
+			},
+		}
+		if doc.ClaType == utils.ClaTypeCCLA {
+			eventArgs.CompanyID = doc.ReferenceID
+		} else {
+			eventArgs.UserID = doc.ReferenceID
+		}
+		s.eventsService.LogEventWithContext(ctx, eventArgs)
+	}
+	return result, nil
+}
@@ -0,0 +1,242 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// gerritReconcilerUser is the user recorded on the group membership change events of the scheduled reconciliation
+var gerritReconcilerUser = &auth.User{
+	UserName: "easycla-gerrit-reconciler",
+}
+
+// GerritReconcileSummary summarizes a gerrit group reconciliation run
+type GerritReconcileSummary struct {
+	DryRun             bool `json:"dry_run"`
+	CLAGroupsProcessed int  `json:"cla_groups_processed"`
+	GroupsReconciled   int  `json:"groups_reconciled"`
+	UsersAdded         int  `json:"users_added"`
+	UsersRemoved       int  `json:"users_removed"`
+	Failures           int  `json:"failures"`
+	Errors             int  `json:"errors"`
+}
+
+// GerritGroupReconciler keeps the ICLA/CCLA groups of the gerrit instances in line with the signatures and the
+// approval lists - users missed by the request flows are added and the revoked members are removed
+type GerritGroupReconciler interface {
+	ReconcileCLAGroup(ctx context.Context, authUser *auth.User, claGroupID, claType string, dryRun bool) ([]*gerrits.GroupReconcileResult, error)
+	ReconcileAll(ctx context.Context, dryRun bool) (*GerritReconcileSummary, error)
+}
+
+type gerritGroupReconciler struct {
+	repo          SignatureRepository
+	gerritService gerrits.Service
+}
+
+// NewGerritGroupReconciler creates a new gerrit group reconciler
+func NewGerritGroupReconciler(repo SignatureRepository, gerritService gerrits.Service) GerritGroupReconciler {
+	return &gerritGroupReconciler{
+		repo:          repo,
+		gerritService: gerritService,
+	}
+}
+
+// ReconcileAll reconciles the ICLA/CCLA groups of all the gerrit instances, a failing CLA Group doesn't stop the run
+func (r *gerritGroupReconciler) ReconcileAll(ctx context.Context, dryRun bool) (*GerritReconcileSummary, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.signatures.gerrit_reconciler.ReconcileAll",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"dryRun":         dryRun,
+	}
+
+	gerritList, err := r.gerritService.GetAllGerrits(ctx)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the gerrit instances")
+		return nil, err
+	}
+
+	// the CLA types to reconcile, keyed by the CLA Group of the gerrit instances
+	claTypes := map[string]map[string]bool{}
+	for _, gerritModel := range gerritList.List {
+		if gerritModel.ProjectID == "" {
+			continue
+		}
+		if _, ok := claTypes[gerritModel.ProjectID]; !ok {
+			claTypes[gerritModel.ProjectID] = map[string]bool{}
+		}
+		if gerritModel.GroupNameIcla != "" {
+			claTypes[gerritModel.ProjectID][utils.ClaTypeICLA] = true
+		}
+		if gerritModel.GroupNameCcla != "" {
+			claTypes[gerritModel.ProjectID][utils.ClaTypeECLA] = true
+		}
+	}
+
+	claGroupIDs := make([]string, 0, len(claTypes))
+	for claGroupID := range claTypes {
+		claGroupIDs = append(claGroupIDs, claGroupID)
+	}
+	sort.Strings(claGroupIDs)
+
+	summary := &GerritReconcileSummary{DryRun: dryRun}
+	for _, claGroupID := range claGroupIDs {
+		summary.CLAGroupsProcessed++
+		for _, claType := range []string{utils.ClaTypeICLA, utils.ClaTypeECLA} {
+			if !claTypes[claGroupID][claType] {
+				continue
+			}
+			results, reconcileErr := r.ReconcileCLAGroup(ctx, gerritReconcilerUser, claGroupID, claType, dryRun)
+			if reconcileErr != nil {
+				log.WithFields(f).WithError(reconcileErr).Warnf("unable to reconcile the %s groups of CLA Group: %s", claType, claGroupID)
+				summary.Errors++
+				continue
+			}
+			for _, result := range results {
+				summary.GroupsReconciled++
+				summary.UsersAdded += len(result.Added)
+				summary.UsersRemoved += len(result.Removed)
+				summary.Failures += len(result.Failed)
+			}
+		}
+	}
+
+	return summary, nil
+}
+
+// ReconcileCLAGroup reconciles the ICLA or ECLA groups of the CLA Group gerrit instances with the signatures
+func (r *gerritGroupReconciler) ReconcileCLAGroup(ctx context.Context, authUser *auth.User, claGroupID, claType string, dryRun bool) ([]*gerrits.GroupReconcileResult, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.signatures.gerrit_reconciler.ReconcileCLAGroup",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+		"claType":        claType,
+		"dryRun":         dryRun,
+	}
+
+	var expected *gerrits.ExpectedMembers
+	var err error
+	switch claType {
+	case utils.ClaTypeICLA:
+		expected, err = r.expectedICLAMembers(ctx, claGroupID)
+	case utils.ClaTypeECLA:
+		expected, err = r.expectedECLAMembers(ctx, claGroupID)
+	default:
+		return nil, &utils.InvalidCLAType{
+			CLAType: claType,
+		}
+	}
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to compute the expected group members")
+		return nil, err
+	}
+
+	log.WithFields(f).Debugf("reconciling the %s groups with %d expected members", claType, len(expected.Members))
+	return r.gerritService.ReconcileGroup(ctx, authUser, claGroupID, claType, expected, dryRun)
+}
+
+// expectedICLAMembers returns the LF usernames of the users with a signed and approved ICLA - the users whose ICLA
+// was invalidated are recorded with the reason of their removal
+func (r *gerritGroupReconciler) expectedICLAMembers(ctx context.Context, claGroupID string) (*gerrits.ExpectedMembers, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.signatures.gerrit_reconciler.expectedICLAMembers",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+	}
+
+	iclaSignatures, err := r.repo.GetCLAGroupSignedSignatures(ctx, claGroupID, utils.ClaTypeICLA)
+	if err != nil {
+		return nil, err
+	}
+
+	expected := gerrits.NewExpectedMembers()
+	for _, iclaSignature := range iclaSignatures {
+		expected.Expect(iclaSignature.UserLFID, "signed and approved ICLA")
+	}
+
+	// the ICLAs invalidated by the project managers
+	approved := false
+	nextKey := ""
+	for {
+		invalidated, invalidatedErr := r.repo.GetClaGroupICLASignatures(ctx, claGroupID, nil, &approved, nil, BigPageSize, nextKey)
+		if invalidatedErr != nil {
+			log.WithFields(f).WithError(invalidatedErr).Warn("unable to load the invalidated ICLA signatures")
+			return nil, invalidatedErr
+		}
+		for _, iclaSignature := range invalidated.List {
+			expected.Revoke(iclaSignature.LfUsername, "ICLA invalidated")
+		}
+		if invalidated.LastKeyScanned == "" {
+			break
+		}
+		nextKey = invalidated.LastKeyScanned
+	}
+	return expected, nil
+}
+
+// expectedECLAMembers returns the employees with a signed ECLA still approved by the CCLA approval list of their
+// company - the employees denied or no longer on the approval list are recorded with the reason of their removal
+func (r *gerritGroupReconciler) expectedECLAMembers(ctx context.Context, claGroupID string) (*gerrits.ExpectedMembers, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.signatures.gerrit_reconciler.expectedECLAMembers",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+	}
+
+	cclaSignatures, err := r.repo.GetCLAGroupSignedSignatures(ctx, claGroupID, utils.ClaTypeCCLA)
+	if err != nil {
+		return nil, err
+	}
+
+	expected := gerrits.NewExpectedMembers()
+	for _, cclaSignature := range cclaSignatures {
+		companyID := cclaSignature.SignatureReferenceID
+		companyName := cclaSignature.CompanyName
+		if companyName == "" {
+			companyName = companyID
+		}
+
+		contributors, contributorsErr := r.repo.GetClaGroupCorporateContributors(ctx, claGroupID, &companyID, nil)
+		if contributorsErr != nil {
+			log.WithFields(f).WithError(contributorsErr).Warnf("unable to load the corporate contributors of company: %s", companyID)
+			return nil, contributorsErr
+		}
+
+		rules := NewApprovalRulesFromSignature(ctx, cclaSignature)
+		for _, contributor := range contributors.List {
+			// the group sync backends resolve the group members from the LF usernames
+			if contributor.LinuxFoundationID == "" {
+				continue
+			}
+			candidate := ApprovalCandidate{GitHubUsername: contributor.GithubID}
+			if contributor.Email != "" {
+				candidate.Emails = []string{contributor.Email}
+			}
+
+			match := rules.Explain(candidate)
+			switch {
+			case match.Denied:
+				expected.Revoke(contributor.LinuxFoundationID, fmt.Sprintf("denied by the approval list of %s: %s", companyName, match.Reason))
+			case match.Approved:
+				expected.Expect(contributor.LinuxFoundationID, fmt.Sprintf("signed ECLA approved by %s: %s", companyName, match.Reason))
+			case len(cclaSignature.GithubOrgApprovalList) > 0 && contributor.GithubID != "":
+				// the GitHub organization membership is checked by the GitHub flows, keep the contributor
+				expected.Expect(contributor.LinuxFoundationID, fmt.Sprintf("signed ECLA with %s, GitHub organization approval not evaluated", companyName))
+			default:
+				expected.Revoke(contributor.LinuxFoundationID, fmt.Sprintf("no longer on the approval list of %s", companyName))
+			}
+		}
+	}
+
+	return expected, nil
+}
@@ -20,6 +20,8 @@ type SignatureCompanyID struct {
 type ApprovalCriteria struct {
 	UserEmail      string
 	GitHubUsername string
+	// Rules, when set, limits the results to the signatures matched by an allow or deny approval rule
+	Rules *ApprovalRules
 }
 
 //ApprovalList ...
@@ -42,6 +44,8 @@ type ApprovalList struct {
 	CLAManager              *models.User
 	ManagersInfo            []ClaManagerInfoParams
 	CCLASignature           *models.Signature
+	// Rules holds the approval rules after the update has been applied
+	Rules *ApprovalRules
 }
 
 // GerritUserResponse is a data structure to hold the gerrit user query response
@@ -28,6 +28,8 @@ func buildProjection() expression.ProjectionBuilder {
 		expression.Name("domain_whitelist"),
 		expression.Name("github_whitelist"),
 		expression.Name("github_org_whitelist"),
+		expression.Name("approval_list_expirations"),
+		expression.Name("approval_list_expiry_warnings_sent"),
 		expression.Name("user_github_username"),
 		expression.Name("user_lf_username"),
 		expression.Name("user_name"),
@@ -36,6 +38,9 @@ func buildProjection() expression.ProjectionBuilder {
 		expression.Name("signatory_name"),
 		expression.Name("user_docusign_date_signed"),
 		expression.Name("user_docusign_name"),
+		expression.Name("signature_document_sha256"),
+		expression.Name("signature_document_integrity_status"),
+		expression.Name("signature_document_verified_on"),
 	)
 }
 
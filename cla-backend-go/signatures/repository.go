@@ -11,12 +11,10 @@ import (
 	"fmt"
 	"sort"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/communitybridge/easycla/cla-backend-go/config"
 
-	"github.com/LF-Engineering/lfx-kit/auth"
 	"github.com/sirupsen/logrus"
 
 	"github.com/communitybridge/easycla/cla-backend-go/users"
@@ -28,7 +26,6 @@ import (
 	"github.com/communitybridge/easycla/cla-backend-go/company"
 	"github.com/communitybridge/easycla/cla-backend-go/events"
 	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
-	"github.com/communitybridge/easycla/cla-backend-go/github"
 	"github.com/communitybridge/easycla/cla-backend-go/github_organizations"
 	"github.com/communitybridge/easycla/cla-backend-go/repositories"
 
@@ -36,6 +33,7 @@ import (
 
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -54,6 +52,7 @@ const (
 	SignatureProjectIDTypeIndex                    = "signature-project-id-type-index"
 	SignatureReferenceIndex                        = "reference-signature-index"
 	SignatureReferenceSearchIndex                  = "reference-signature-search-index"
+	SignatureApprovalListExpiryIndex               = "signature-approval-list-expiry-index"
 
 	HugePageSize    = 10000
 	DefaultPageSize = 100
@@ -70,6 +69,7 @@ type SignatureRepository interface {
 	GetSignature(ctx context.Context, signatureID string) (*models.Signature, error)
 	GetIndividualSignature(ctx context.Context, claGroupID, userID string, approved, signed *bool) (*models.Signature, error)
 	GetCorporateSignature(ctx context.Context, claGroupID, companyID string, approved, signed *bool) (*models.Signature, error)
+	GetEmployeeSignature(ctx context.Context, claGroupID, companyID, userID string) (*models.Signature, error)
 	GetSignatureACL(ctx context.Context, signatureID string) ([]string, error)
 	GetProjectSignatures(ctx context.Context, params signatures.GetProjectSignaturesParams) (*models.Signatures, error)
 	CreateProjectSummaryReport(ctx context.Context, params signatures.CreateProjectSummaryReportParams) (*models.SignatureReport, error)
@@ -85,14 +85,20 @@ type SignatureRepository interface {
 	AddCLAManager(ctx context.Context, signatureID, claManagerID string) (*models.Signature, error)
 	RemoveCLAManager(ctx context.Context, signatureID, claManagerID string) (*models.Signature, error)
 
-	removeColumn(ctx context.Context, signatureID, columnName string) (*models.Signature, error)
-
 	AddSigTypeSignedApprovedID(ctx context.Context, signatureID string, val string) error
 	AddUsersDetails(ctx context.Context, signatureID string, userID string) error
 	AddSignedOn(ctx context.Context, signatureID string) error
 
 	GetClaGroupICLASignatures(ctx context.Context, claGroupID string, searchTerm *string, approved, signed *bool, pageSize int64, nextKey string) (*models.IclaSignatures, error)
 	GetClaGroupCorporateContributors(ctx context.Context, claGroupID string, companyID *string, searchTerm *string) (*models.CorporateContributorList, error)
+
+	GetSignaturesWithApprovalListExpirations(ctx context.Context, expiringBefore time.Time) ([]*models.Signature, error)
+	UpdateApprovalListExpirations(ctx context.Context, signatureID string, expirations map[string]string, warningsSent []string) error
+
+	GetSignatureDocumentsForVerification(ctx context.Context, verifiedBefore string, limit int) ([]*SignatureDocument, error)
+	UpdateSignatureDocumentIntegrity(ctx context.Context, signatureID, documentSHA256, status, verifiedOn string) error
+
+	GetCLAGroupSignedSignatures(ctx context.Context, claGroupID, claType string) ([]*models.Signature, error)
 }
 
 type iclaSignatureWithDetails struct {
@@ -128,15 +134,32 @@ func NewRepository(awsSession *session.Session, stage string, companyRepo compan
 	}
 }
 
+// approvalListUpdater returns the approval list update helper backed by this repository
+func (repo repository) approvalListUpdater() approvalListUpdater {
+	return approvalListUpdater{
+		sigRepo:          repo,
+		columnStore:      repo,
+		companyRepo:      repo.companyRepo,
+		usersRepo:        repo.usersRepo,
+		eventsService:    repo.eventsService,
+		repositoriesRepo: repo.repositoriesRepo,
+		ghOrgRepo:        repo.ghOrgRepo,
+		gerritService:    repo.gerritService,
+	}
+}
+
 // GetGithubOrganizationsFromWhitelist returns a list of GH organizations stored in the whitelist
 func (repo repository) GetGithubOrganizationsFromWhitelist(ctx context.Context, signatureID string) ([]models.GithubOrg, error) {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.GetGithubOrganizationsFromWhitelist")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.signatures.repository.GetGitHubOrganizationsFromWhitelist",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
 		"signatureID":    signatureID,
 	}
 	// get item from dynamoDB table
-	result, err := repo.dynamoDBClient.GetItem(&dynamodb.GetItemInput{
+	result, err := repo.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(repo.signatureTableName),
 		Key: map[string]*dynamodb.AttributeValue{
 			"signature_id": {
@@ -174,6 +197,9 @@ func (repo repository) GetGithubOrganizationsFromWhitelist(ctx context.Context,
 
 // AddGithubOrganizationToWhitelist adds the specified GH organization to the whitelist
 func (repo repository) AddGithubOrganizationToWhitelist(ctx context.Context, signatureID, GitHubOrganizationID string) ([]models.GithubOrg, error) {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.AddGithubOrganizationToWhitelist")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":         "v1.signatures.repository.AddGitHubOrganizationToWhitelist",
 		utils.XREQUESTID:       ctx.Value(utils.XREQUESTID),
@@ -183,7 +209,7 @@ func (repo repository) AddGithubOrganizationToWhitelist(ctx context.Context, sig
 	// get item from dynamoDB table
 	log.WithFields(f).Debugf("querying database for GitHub organization whitelist using signatureID: %s", signatureID)
 
-	result, err := repo.dynamoDBClient.GetItem(&dynamodb.GetItemInput{
+	result, err := repo.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(repo.signatureTableName),
 		Key: map[string]*dynamodb.AttributeValue{
 			"signature_id": {
@@ -247,7 +273,7 @@ func (repo repository) AddGithubOrganizationToWhitelist(ctx context.Context, sig
 	}
 
 	log.WithFields(f).Warnf("updating database record using signatureID: %s with values: %v", signatureID, newList)
-	updatedValues, err := repo.dynamoDBClient.UpdateItem(input)
+	updatedValues, err := repo.dynamoDBClient.UpdateItemWithContext(ctx, input)
 	if err != nil {
 		log.WithFields(f).Warnf("Error updating white list, error: %v", err)
 		return nil, err
@@ -267,6 +293,9 @@ func (repo repository) AddGithubOrganizationToWhitelist(ctx context.Context, sig
 
 // DeleteGithubOrganizationFromWhitelist removes the specified GH organization from the whitelist
 func (repo repository) DeleteGithubOrganizationFromWhitelist(ctx context.Context, signatureID, GitHubOrganizationID string) ([]models.GithubOrg, error) {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.DeleteGithubOrganizationFromWhitelist")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":         "v1.signatures.repository.DeleteGitHubOrganizationFromWhitelist",
 		utils.XREQUESTID:       ctx.Value(utils.XREQUESTID),
@@ -274,7 +303,7 @@ func (repo repository) DeleteGithubOrganizationFromWhitelist(ctx context.Context
 		"GitHubOrganizationID": GitHubOrganizationID,
 	}
 	// get item from dynamoDB table
-	result, err := repo.dynamoDBClient.GetItem(&dynamodb.GetItemInput{
+	result, err := repo.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(repo.signatureTableName),
 		Key: map[string]*dynamodb.AttributeValue{
 			"signature_id": {
@@ -334,7 +363,7 @@ func (repo repository) DeleteGithubOrganizationFromWhitelist(ctx context.Context
 			UpdateExpression: aws.String("SET #L = :l"),
 		}
 
-		_, err = repo.dynamoDBClient.UpdateItem(input)
+		_, err = repo.dynamoDBClient.UpdateItemWithContext(ctx, input)
 		if err != nil {
 			log.WithFields(f).Warnf("error updating github org whitelist to NULL value, error: %v", err)
 			return nil, err
@@ -367,7 +396,7 @@ func (repo repository) DeleteGithubOrganizationFromWhitelist(ctx context.Context
 		ReturnValues:     &updatedReturnValues,
 	}
 
-	updatedValues, err := repo.dynamoDBClient.UpdateItem(input)
+	updatedValues, err := repo.dynamoDBClient.UpdateItemWithContext(ctx, input)
 	if err != nil {
 		log.WithFields(f).Warnf("Error updating github org whitelist, error: %v", err)
 		return nil, err
@@ -388,6 +417,9 @@ func (repo repository) DeleteGithubOrganizationFromWhitelist(ctx context.Context
 
 // GetSignature returns the signature for the specified signature id
 func (repo repository) GetSignature(ctx context.Context, signatureID string) (*models.Signature, error) {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.GetSignature")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.signatures.repository.GetSignature",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -414,7 +446,7 @@ func (repo repository) GetSignature(ctx context.Context, signatureID string) (*m
 	}
 
 	// Make the DynamoDB Query API call
-	results, queryErr := repo.dynamoDBClient.Query(queryInput)
+	results, queryErr := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if queryErr != nil {
 		log.WithFields(f).Warnf("error retrieving signature ID: %s, error: %v", signatureID, queryErr)
 		return nil, queryErr
@@ -442,6 +474,9 @@ func (repo repository) GetSignature(ctx context.Context, signatureID string) (*m
 
 // GetIndividualSignature returns the signature record for the specified CLA Group and User
 func (repo repository) GetIndividualSignature(ctx context.Context, claGroupID, userID string, approved, signed *bool) (*models.Signature, error) {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.GetIndividualSignature")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":           "v1.signatures.repository.GetIndividualSignature",
 		utils.XREQUESTID:         ctx.Value(utils.XREQUESTID),
@@ -516,7 +551,7 @@ func (repo repository) GetIndividualSignature(ctx context.Context, claGroupID, u
 	// Loop until we have all the records
 	for ok := true; ok; ok = lastEvaluatedKey != "" {
 		// Make the DynamoDB Query API call
-		results, errQuery := repo.dynamoDBClient.Query(queryInput)
+		results, errQuery := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 		//log.WithFields(f).Debugf("Ran signature project query, results: %+v, error: %+v", results, errQuery)
 		if errQuery != nil {
 			log.WithFields(f).Warnf("error retrieving project ICLA signature ID, error: %v", errQuery)
@@ -556,8 +591,76 @@ func (repo repository) GetIndividualSignature(ctx context.Context, claGroupID, u
 	return sigs[0], nil
 }
 
+// GetEmployeeSignature returns the signed and approved employee acknowledgement (ECLA) of the user for the CCLA of the
+// specified CLA Group and Company ID, nil if the user didn't acknowledge it
+func (repo repository) GetEmployeeSignature(ctx context.Context, claGroupID, companyID, userID string) (*models.Signature, error) {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.GetEmployeeSignature")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.signatures.repository.GetEmployeeSignature",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"tableName":      repo.signatureTableName,
+		"claGroupID":     claGroupID,
+		"companyID":      companyID,
+		"userID":         userID,
+	}
+
+	var filterAdded bool
+	// These are the keys we want to match for an ECLA Signature with a given CLA Group and User ID
+	condition := expression.Key("signature_project_id").Equal(expression.Value(claGroupID)).
+		And(expression.Key("signature_reference_id").Equal(expression.Value(userID)))
+	var filter expression.ConditionBuilder
+	filter = addAndCondition(filter, expression.Name("signature_type").Equal(expression.Value(utils.SignatureTypeCLA)), &filterAdded)
+	filter = addAndCondition(filter, expression.Name("signature_reference_type").Equal(expression.Value(utils.SignatureReferenceTypeUser)), &filterAdded)
+	filter = addAndCondition(filter, expression.Name("signature_user_ccla_company_id").Equal(expression.Value(companyID)), &filterAdded)
+	filter = addAndCondition(filter, expression.Name("signature_approved").Equal(expression.Value(true)), &filterAdded)
+	filter = addAndCondition(filter, expression.Name("signature_signed").Equal(expression.Value(true)), &filterAdded)
+
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).WithFilter(filter).WithProjection(buildProjection()).Build()
+	if err != nil {
+		log.WithFields(f).Warnf("error building expression for project ECLA signature query, error: %v", err)
+		return nil, err
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ProjectionExpression:      expr.Projection(),
+		FilterExpression:          expr.Filter(),
+		TableName:                 aws.String(repo.signatureTableName),
+		IndexName:                 aws.String(SignatureProjectReferenceIndex),
+	}
+
+	for {
+		results, errQuery := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
+		if errQuery != nil {
+			log.WithFields(f).Warnf("error retrieving project ECLA signature, error: %v", errQuery)
+			return nil, errQuery
+		}
+
+		signatureList, modelErr := repo.buildProjectSignatureModels(ctx, results, claGroupID, LoadACLDetails)
+		if modelErr != nil {
+			log.WithFields(f).Warnf("error converting DB model to response model for signatures, error: %v", modelErr)
+			return nil, modelErr
+		}
+		if len(signatureList) > 0 {
+			return signatureList[0], nil
+		}
+
+		if results.LastEvaluatedKey["signature_id"] == nil {
+			return nil, nil
+		}
+		queryInput.ExclusiveStartKey = results.LastEvaluatedKey
+	}
+}
+
 // GetCorporateSignature returns the signature record for the specified CLA Group and Company ID
 func (repo repository) GetCorporateSignature(ctx context.Context, claGroupID, companyID string, approved, signed *bool) (*models.Signature, error) {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.GetCorporateSignature")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":           "v1.signatures.repository.GetCorporateSignature",
 		utils.XREQUESTID:         ctx.Value(utils.XREQUESTID),
@@ -628,7 +731,7 @@ func (repo repository) GetCorporateSignature(ctx context.Context, claGroupID, co
 	// Loop until we have all the records
 	for ok := true; ok; ok = lastEvaluatedKey != "" {
 		// Make the DynamoDB Query API call
-		results, errQuery := repo.dynamoDBClient.Query(queryInput)
+		results, errQuery := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 		if errQuery != nil {
 			log.WithFields(f).Warnf("error retrieving project CCLA signature, error: %v", errQuery)
 			return nil, errQuery
@@ -669,6 +772,9 @@ func (repo repository) GetCorporateSignature(ctx context.Context, claGroupID, co
 
 // GetSignatureACL returns the signature ACL for the specified signature id
 func (repo repository) GetSignatureACL(ctx context.Context, signatureID string) ([]string, error) {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.GetSignatureACL")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.signatures.repository.GetSignatureACL",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -695,7 +801,7 @@ func (repo repository) GetSignatureACL(ctx context.Context, signatureID string)
 	}
 
 	// Make the DynamoDB Query API call
-	result, queryErr := repo.dynamoDBClient.GetItem(itemInput)
+	result, queryErr := repo.dynamoDBClient.GetItemWithContext(ctx, itemInput)
 	if queryErr != nil {
 		log.WithFields(f).Warnf("error retrieving signature ID: %s, error: %v", signatureID, queryErr)
 		return nil, queryErr
@@ -852,7 +958,7 @@ func (repo repository) GetProjectSignatures(ctx context.Context, params signatur
 	// Loop until we have all the records
 	for ok := true; ok; ok = lastEvaluatedKey != "" {
 		// Make the DynamoDB Query API call
-		results, errQuery := repo.dynamoDBClient.Query(queryInput)
+		results, errQuery := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 		if errQuery != nil {
 			log.WithFields(f).Warnf("error retrieving project signature ID for project: %s, error: %v",
 				params.ProjectID, errQuery)
@@ -1112,7 +1218,7 @@ func (repo repository) CreateProjectSummaryReport(ctx context.Context, params si
 	// Loop until we have all the records
 	for ok := true; ok; ok = lastEvaluatedKey != "" {
 		// Make the DynamoDB Query API call
-		results, errQuery := repo.dynamoDBClient.Query(queryInput)
+		results, errQuery := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 		if errQuery != nil {
 			log.WithFields(f).Warnf("error retrieving project signature ID for project: %s, error: %v",
 				params.ProjectID, errQuery)
@@ -1171,6 +1277,9 @@ func (repo repository) CreateProjectSummaryReport(ctx context.Context, params si
 
 // GetProjectCompanySignature returns a the signature for the specified project and specified company with the other query flags
 func (repo repository) GetProjectCompanySignature(ctx context.Context, companyID, projectID string, approved, signed *bool, nextKey *string, pageSize *int64) (*models.Signature, error) {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.GetProjectCompanySignature")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.signatures.repository.GetProjectCompanySignature",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -1205,6 +1314,9 @@ func (repo repository) GetProjectCompanySignature(ctx context.Context, companyID
 
 // GetProjectCompanySignatures returns a list of signatures for the specified project and specified company
 func (repo repository) GetProjectCompanySignatures(ctx context.Context, companyID, projectID string, approved, signed *bool, nextKey *string, sortOrder *string, pageSize *int64) (*models.Signatures, error) {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.GetProjectCompanySignatures")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.signatures.repository.GetProjectCompanySignatures",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -1292,7 +1404,7 @@ func (repo repository) GetProjectCompanySignatures(ctx context.Context, companyI
 	for ok := true; ok; ok = lastEvaluatedKey != "" {
 		// Make the DynamoDB Query API call
 		log.WithFields(f).Debugf("executing query for input: %+v", queryInput)
-		results, errQuery := repo.dynamoDBClient.Query(queryInput)
+		results, errQuery := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 		if errQuery != nil {
 			log.WithFields(f).WithError(errQuery).Warnf("error retrieving project signature ID for project: %s with company: %s, error: %v",
 				projectID, companyID, errQuery)
@@ -1376,6 +1488,9 @@ func (repo repository) GetProjectCompanySignatures(ctx context.Context, companyI
 
 // ProjectSignatures - get project signatures with no pagination
 func (repo repository) ProjectSignatures(ctx context.Context, projectID string) (*models.Signatures, error) {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.ProjectSignatures")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.signatures.repository.ProjectSignatures",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -1422,7 +1537,7 @@ func (repo repository) ProjectSignatures(ctx context.Context, projectID string)
 		IndexName:                 aws.String(indexName), // Name of a secondary index to scan
 	}
 
-	results, errQuery := repo.dynamoDBClient.Query(queryInput)
+	results, errQuery := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 
 	if errQuery != nil {
 		log.WithFields(f).Warnf("error retrieving project signature ID for project: %s, error: %v",
@@ -1446,6 +1561,9 @@ func (repo repository) ProjectSignatures(ctx context.Context, projectID string)
 
 // InvalidateProjectRecord invalidates the specified project record by setting the signature_approved flag to false
 func (repo repository) InvalidateProjectRecord(ctx context.Context, signatureID, note string) error {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.InvalidateProjectRecord")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.signatures.repository.InvalidateProjectRecord",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -1479,7 +1597,7 @@ func (repo repository) InvalidateProjectRecord(ctx context.Context, signatureID,
 		TableName:                 aws.String(signatureTableName),
 	}
 
-	_, updateErr := repo.dynamoDBClient.UpdateItem(input)
+	_, updateErr := repo.dynamoDBClient.UpdateItemWithContext(ctx, input)
 	if updateErr != nil {
 		log.WithFields(f).Warnf("error updating signature_approved for signature_id : %s error : %v ", signatureID, updateErr)
 		return updateErr
@@ -1490,6 +1608,9 @@ func (repo repository) InvalidateProjectRecord(ctx context.Context, signatureID,
 
 // GetProjectCompanyEmployeeSignatures returns a list of employee signatures for the specified project and specified company
 func (repo repository) GetProjectCompanyEmployeeSignatures(ctx context.Context, params signatures.GetProjectCompanyEmployeeSignaturesParams, criteria *ApprovalCriteria, pageSize int64) (*models.Signatures, error) {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.GetProjectCompanyEmployeeSignatures")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.signatures.repository.GetProjectCompanyEmployeeSignatures",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -1511,15 +1632,8 @@ func (repo repository) GetProjectCompanyEmployeeSignatures(ctx context.Context,
 	filter = addAndCondition(filter, expression.Name("signature_approved").Equal(expression.Value(true)), &filterAdded)
 	filter = addAndCondition(filter, expression.Name("signature_signed").Equal(expression.Value(true)), &filterAdded)
 
-	if criteria != nil && criteria.GitHubUsername != "" {
-		log.WithFields(f).Debugf("adding Githubusername criteria filter for :%s ", criteria.GitHubUsername)
-		filter = addAndCondition(filter, expression.Name("user_github_username").Equal(expression.Value(criteria.GitHubUsername)), &filterAdded)
-	}
-
-	if criteria != nil && criteria.UserEmail != "" {
-		log.WithFields(f).Debugf("adding useremail criteria filter for : %s ", criteria.UserEmail)
-		filter = addAndCondition(filter, expression.Name("user_email").Equal(expression.Value(criteria.UserEmail)), &filterAdded)
-	}
+	// The criteria are matched case insensitively, like the approval list entries - they are applied to the results
+	// as the query can only compare the exact values
 
 	// Use the nice builder to create the expression
 	expr, err := expression.NewBuilder().WithKeyCondition(condition).WithFilter(filter).WithProjection(buildProjection()).Build()
@@ -1565,23 +1679,30 @@ func (repo repository) GetProjectCompanyEmployeeSignatures(ctx context.Context,
 	for ok := true; ok; ok = lastEvaluatedKey != "" {
 		// Make the DynamoDB Query API call
 		//log.WithFields(f).Debugf("Running signature project company query using queryInput: %+v", queryInput)
-		results, errQuery := repo.dynamoDBClient.Query(queryInput)
+		results, errQuery := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 		if errQuery != nil {
 			log.WithFields(f).Warnf("error retrieving project company employee signature ID for project: %s with company: %s, error: %v",
 				params.ProjectID, params.CompanyID, errQuery)
 			return nil, errQuery
 		}
 
-		// Convert the list of DB models to a list of response models
-		signatureList, modelErr := repo.buildProjectSignatureModels(ctx, results, params.ProjectID, LoadACLDetails)
+		var dbSignatures []ItemSignature
+		modelErr := dynamodbattribute.UnmarshalListOfMaps(results.Items, &dbSignatures)
 		if modelErr != nil {
 			log.WithFields(f).Warnf("error converting DB model to response model for employee signatures with project %s with company: %s, error: %v",
 				params.ProjectID, params.CompanyID, modelErr)
 			return nil, modelErr
 		}
 
-		// Add to the signatures response model to the list
-		sigs = append(sigs, signatureList...)
+		var matched []ItemSignature
+		for i := range dbSignatures {
+			if criteria.matches(&dbSignatures[i]) {
+				matched = append(matched, dbSignatures[i])
+			}
+		}
+
+		// Convert the list of DB models to a list of response models and add them to the list
+		sigs = append(sigs, buildSignatureModels(ctx, repo.usersRepo, repo.companyRepo, matched, LoadACLDetails)...)
 
 		// log.WithFields(f).Debugf("LastEvaluatedKey: %+v", results.LastEvaluatedKey["signature_id"])
 		if results.LastEvaluatedKey["signature_id"] != nil {
@@ -1624,6 +1745,9 @@ func (repo repository) GetProjectCompanyEmployeeSignatures(ctx context.Context,
 
 // GetCompanySignatures returns a list of company signatures for the specified company
 func (repo repository) GetCompanySignatures(ctx context.Context, params signatures.GetCompanySignaturesParams, pageSize int64, loadACL bool) (*models.Signatures, error) {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.GetCompanySignatures")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.signatures.repository.GetCompanySignatures",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -1683,7 +1807,7 @@ func (repo repository) GetCompanySignatures(ctx context.Context, params signatur
 	for ok := true; ok; ok = lastEvaluatedKey != "" {
 		// Make the DynamoDB Query API call
 		//log.WithFields(f).Debugf("Running signature project company query using queryInput: %+v", queryInput)
-		results, errQuery := repo.dynamoDBClient.Query(queryInput)
+		results, errQuery := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 		if errQuery != nil {
 			log.WithFields(f).Warnf("error retrieving company signature ID for company: %s with company: %s, error: %v",
 				params.CompanyID, params.CompanyID, errQuery)
@@ -1743,6 +1867,9 @@ func (repo repository) GetCompanySignatures(ctx context.Context, params signatur
 
 // GetCompanyIDsWithSignedCorporateSignatures returns a list of company IDs that have signed a CLA agreement
 func (repo repository) GetCompanyIDsWithSignedCorporateSignatures(ctx context.Context, claGroupID string) ([]SignatureCompanyID, error) {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.GetCompanyIDsWithSignedCorporateSignatures")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":             "v1.signatures.repository.GetCompanyIDsWithSignedCorporateSignatures",
 		"claGroupID":               claGroupID,
@@ -1796,7 +1923,7 @@ func (repo repository) GetCompanyIDsWithSignedCorporateSignatures(ctx context.Co
 	// Loop until we have all the records
 	for ok := true; ok; ok = lastEvaluatedKey != "" {
 		// Make the DynamoDB Query API call
-		results, errQuery := repo.dynamoDBClient.Query(queryInput)
+		results, errQuery := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 		if errQuery != nil {
 			log.WithFields(f).Warnf("error retrieving signature record, error: %v", errQuery)
 			return nil, errQuery
@@ -1831,6 +1958,9 @@ func (repo repository) GetCompanyIDsWithSignedCorporateSignatures(ctx context.Co
 
 // GetUserSignatures returns a list of user signatures for the specified user
 func (repo repository) GetUserSignatures(ctx context.Context, params signatures.GetUserSignaturesParams, pageSize int64) (*models.Signatures, error) {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.GetUserSignatures")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.signatures.repository.GetUserSignatures",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -1879,7 +2009,7 @@ func (repo repository) GetUserSignatures(ctx context.Context, params signatures.
 	// Loop until we have all the records
 	for ok := true; ok; ok = lastEvaluatedKey != "" {
 		// Make the DynamoDB Query API call
-		results, errQuery := repo.dynamoDBClient.Query(queryInput)
+		results, errQuery := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 		if errQuery != nil {
 			log.WithFields(f).Warnf("error retrieving user signatures for user: %s/%s, error: %v",
 				params.UserID, *params.UserName, errQuery)
@@ -1934,6 +2064,9 @@ func (repo repository) GetUserSignatures(ctx context.Context, params signatures.
 }
 
 func (repo repository) AddCLAManager(ctx context.Context, signatureID, claManagerID string) (*models.Signature, error) {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.AddCLAManager")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.signatures.repository.AddCLAManager",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -1984,7 +2117,7 @@ func (repo repository) AddCLAManager(ctx context.Context, signatureID, claManage
 		TableName:        aws.String(fmt.Sprintf("cla-%s-signatures", repo.stage)),
 	}
 
-	_, updateErr := repo.dynamoDBClient.UpdateItem(input)
+	_, updateErr := repo.dynamoDBClient.UpdateItemWithContext(ctx, input)
 	if updateErr != nil {
 		log.WithFields(f).Warnf("add CLA manager - unable to update request with new ACL entry of '%s' for signature ID: %s, error: %v",
 			claManagerID, signatureID, updateErr)
@@ -2002,6 +2135,9 @@ func (repo repository) AddCLAManager(ctx context.Context, signatureID, claManage
 }
 
 func (repo repository) RemoveCLAManager(ctx context.Context, signatureID, claManagerID string) (*models.Signature, error) {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.RemoveCLAManager")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.signatures.repository.RemoveCLAManager",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -2058,7 +2194,7 @@ func (repo repository) RemoveCLAManager(ctx context.Context, signatureID, claMan
 		TableName:        aws.String(fmt.Sprintf("cla-%s-signatures", repo.stage)),
 	}
 
-	_, updateErr := repo.dynamoDBClient.UpdateItem(input)
+	_, updateErr := repo.dynamoDBClient.UpdateItemWithContext(ctx, input)
 	if updateErr != nil {
 		log.WithFields(f).Warnf("remove CLA manager - unable to remove ACL entry of '%s' for signature ID: %s, error: %v",
 			claManagerID, signatureID, updateErr)
@@ -2076,710 +2212,294 @@ func (repo repository) RemoveCLAManager(ctx context.Context, signatureID, claMan
 }
 
 // UpdateApprovalList updates the specified project/company signature with the updated approval list information
-func (repo repository) UpdateApprovalList(ctx context.Context, claManager *models.User, claGroupModel *models.ClaGroup, companyID string, params *models.ApprovalList, eventArgs *events.LogEventArgs) (*models.Signature, error) { // nolint
-
-	projectID := claGroupModel.ProjectID
-	f := logrus.Fields{
-		"functionName": "v1.signatures.repository.UpdateApprovalList",
-		"projectID":    projectID,
-		"companyID":    companyID,
-	}
-	log.WithFields(f).Debug("querying database for approval list details")
-
-	approved, signed := true, true
-	pageSize := int64(10)
-
-	// Get CCLA signature - For Approval List info
-	cclaSignature, err := repo.GetCorporateSignature(ctx, projectID, companyID, &approved, &signed)
-	if err != nil || cclaSignature == nil {
-		msg := fmt.Sprintf("unable to get corporate signature for CLA Group: %s and company: %s", projectID, companyID)
-		log.WithFields(f).Warn(msg)
-		return nil, errors.New(msg)
-	}
+func (repo repository) UpdateApprovalList(ctx context.Context, claManager *models.User, claGroupModel *models.ClaGroup, companyID string, params *models.ApprovalList, eventArgs *events.LogEventArgs) (*models.Signature, error) {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.UpdateApprovalList")
+	defer span.End()
 
-	// Get CLA Manager
-	var cclaManagers []ClaManagerInfoParams
-	for i := range cclaSignature.SignatureACL {
-		cclaManagers = append(cclaManagers, ClaManagerInfoParams{
-			Username: utils.GetBestUsername(&cclaSignature.SignatureACL[i]),
-			Email:    getBestEmail(&cclaSignature.SignatureACL[i]),
-		})
-	}
+	return repo.approvalListUpdater().UpdateApprovalList(ctx, claManager, claGroupModel, companyID, params, eventArgs)
+}
 
-	// Keep track of existing company approvals
-	approvalList := ApprovalList{
-		DomainApprovals:         cclaSignature.DomainApprovalList,
-		GHOrgApprovals:          cclaSignature.GithubOrgApprovalList,
-		GitHubUsernameApprovals: cclaSignature.GithubUsernameApprovalList,
-		EmailApprovals:          cclaSignature.EmailApprovalList,
-		CLAManager:              claManager,
-		ICLAs:                   make([]*models.IclaSignature, 0),
-		ECLAs:                   make([]*models.Signature, 0),
-		ManagersInfo:            cclaManagers,
-		CCLASignature:           cclaSignature,
+// updateApprovalListColumns sets the specified approval list columns on the signature record
+func (repo repository) updateApprovalListColumns(ctx context.Context, signatureID string, columns map[string][]string) error {
+	f := logrus.Fields{
+		"functionName":   "v1.signatures.repository.updateApprovalListColumns",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"signatureID":    signatureID,
 	}
 
-	// Just grab and use the first one - need to figure out conflict resolution if more than one
 	expressionAttributeNames := map[string]*string{}
 	expressionAttributeValues := map[string]*dynamodb.AttributeValue{}
-	haveAdditions := false
 	updateExpression := ""
 
-	employeeSignatureParams := signatures.GetProjectCompanyEmployeeSignaturesParams{
-		ProjectID: projectID,
-		CompanyID: companyID,
+	// Sort the column names so that the generated expression is stable
+	var columnNames []string
+	for columnName := range columns {
+		columnNames = append(columnNames, columnName)
 	}
+	sort.Strings(columnNames)
 
-	authUser := auth.User{
-		Email:    claManager.LfEmail,
-		UserName: claManager.LfUsername,
+	for i, columnName := range columnNames {
+		var responseList []*dynamodb.AttributeValue
+		for _, value := range columns[columnName] {
+			responseList = append(responseList, &dynamodb.AttributeValue{S: aws.String(value)})
+		}
+		expressionAttributeNames[fmt.Sprintf("#C%d", i)] = aws.String(columnName)
+		expressionAttributeValues[fmt.Sprintf(":c%d", i)] = &dynamodb.AttributeValue{L: responseList}
+		updateExpression = updateExpression + fmt.Sprintf(" #C%d = :c%d, ", i, i)
 	}
 
-	// Keep track of gerrit users under a give CLA Group
-	var gerritICLAECLAs []string
-
-	// Only load the gerrit user information, which is costly, if we have updates to remove email or email domains
-	if (params.RemoveEmailApprovalList != nil && len(params.RemoveEmailApprovalList) > 0) || (params.RemoveDomainApprovalList != nil && len(params.RemoveDomainApprovalList) > 0) {
-
-		goRoutines := 2
-		gerritResultChannel := make(chan *GerritUserResponse, goRoutines)
-		gerritQueryStartTime, _ := utils.CurrentTime()
-		go repo.getGerritUsers(ctx, &authUser, projectID, utils.ClaTypeICLA, gerritResultChannel)
-		go repo.getGerritUsers(ctx, &authUser, projectID, utils.ClaTypeECLA, gerritResultChannel)
+	// Remove trailing comma from the expression, if present
+	updateExpression = utils.TrimRemoveTrailingComma("SET " + updateExpression)
 
-		log.WithFields(f).Debug("waiting on gerrit user query results from 2 go routines...")
-		for i := 0; i < goRoutines; i++ {
-			results := <-gerritResultChannel
-			log.WithFields(f).Debugf("received gerrit user query results response for %s - took: %+v", results.queryType, time.Since(gerritQueryStartTime))
-			if results.Error != nil {
-				log.WithFields(f).WithError(results.Error).Warnf("problem retrieving gerrit users for %s, error: %+v", results.queryType, results.Error)
-			} else {
-				for _, member := range results.gerritGroupResponse.Members {
-					gerritICLAECLAs = append(gerritICLAECLAs, member.Username)
-				}
-				log.WithFields(f).Debugf("updated gerrit user query results response for %s - list size is %d...", results.queryType, len(gerritICLAECLAs))
-			}
-		}
-		log.WithFields(f).Debugf("received the gerrit user query results from %d go routines...", goRoutines)
-	}
-
-	// If we have an add or remove email list...we need to run an update for this column
-	if (params.AddEmailApprovalList != nil && len(params.AddEmailApprovalList) > 0) || (params.RemoveEmailApprovalList != nil && len(params.RemoveEmailApprovalList) > 0) {
-		columnName := "email_whitelist"
-		attrList := buildApprovalAttributeList(ctx, cclaSignature.EmailApprovalList, params.AddEmailApprovalList, params.RemoveEmailApprovalList)
-		// If no entries after consolidating all the updates, we need to remove the column
-		if attrList == nil || attrList.L == nil {
-			var rmColErr error
-			cclaSignature, rmColErr = repo.removeColumn(ctx, cclaSignature.SignatureID, columnName)
-			if rmColErr != nil {
-				msg := fmt.Sprintf("unable to remove column %s for signature for company ID: %s project ID: %s, type: ccla, signed: %t, approved: %t",
-					columnName, companyID, projectID, true, true)
-				log.WithFields(f).Warn(msg)
-				return nil, errors.New(msg)
-			}
-		} else {
-			haveAdditions = true
-			expressionAttributeNames["#E"] = aws.String("email_whitelist")
-			expressionAttributeValues[":e"] = attrList
-			updateExpression = updateExpression + " #E = :e, "
-		}
-
-		// if email removal update signature approvals
-		if params.RemoveEmailApprovalList != nil {
-			log.WithFields(f).Debugf("removing email: %+v the approval list", params.RemoveDomainApprovalList)
-			var wg sync.WaitGroup
-			wg.Add(len(params.RemoveEmailApprovalList))
-			approvalList.Criteria = utils.EmailCriteria
-			approvalList.ApprovalList = params.RemoveEmailApprovalList
-			approvalList.Action = utils.RemoveApprovals
-			approvalList.Version = claGroupModel.Version
-			for _, email := range params.RemoveEmailApprovalList {
-				go func(email string) {
-					defer wg.Done()
-					var iclas []*models.IclaSignature
-					var eclas []*models.Signature
-					log.WithFields(f).Debugf("getting cla user record for email: %s ", email)
-					userSearch, userErr := repo.usersRepo.SearchUsers("user_emails", email, false)
-					if userErr != nil || userSearch == nil {
-						log.WithFields(f).Debugf("error getting user by email: %s ", email)
-						return
-					}
-					criteria := &ApprovalCriteria{
-						UserEmail: email,
-					}
-					log.WithFields(f).Debugf("Updating signature records for emailApprovalList: %+v ", params.RemoveEmailApprovalList)
-					signs, appErr := repo.GetProjectCompanyEmployeeSignatures(ctx, employeeSignatureParams, criteria, pageSize)
-					if appErr != nil {
-						log.WithFields(f).Debugf("unable to get Company Employee signatures : %+v ", appErr)
-						return
-					}
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(repo.signatureTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"signature_id": {
+				S: aws.String(signatureID),
+			},
+		},
+		ExpressionAttributeNames:  expressionAttributeNames,
+		ExpressionAttributeValues: expressionAttributeValues,
+		UpdateExpression:          aws.String(updateExpression),
+	}
 
-					if len(signs.Signatures) == 0 {
-						log.WithFields(f).Debugf("company employee signatures do not exist for company:%s and project: %s ", companyID, projectID)
-					}
+	_, updateErr := repo.dynamoDBClient.UpdateItemWithContext(ctx, input)
+	if updateErr != nil {
+		log.WithFields(f).Warnf("error updating approval list columns %+v for signature ID: %s, error: %v", columnNames, signatureID, updateErr)
+		return updateErr
+	}
 
-					if len(signs.Signatures) > 0 {
-						approvalList.ECLAs = signs.Signatures
-						eclas = signs.Signatures
-					}
+	return nil
+}
 
-					if len(userSearch.Users) > 0 {
-						// Try and grab iclaSignature records for users
-						results := make(chan *ICLAUserResponse, len(userSearch.Users))
-						go func() {
-							defer close(results)
-							for _, user := range userSearch.Users {
-								icla, iclaErr := repo.GetIndividualSignature(ctx, projectID, user.UserID, &approved, &signed)
-								if iclaErr != nil || icla == nil {
-									results <- &ICLAUserResponse{
-										Error: fmt.Errorf("unable to get icla for user: %s ", user.UserID),
-									}
-								} else {
-
-									// Update gerrit user
-									if utils.StringInSlice(user.LfUsername, gerritICLAECLAs) {
-										gerritIclaErr := repo.gerritService.RemoveUserFromGroup(ctx, &authUser, approvalList.ClaGroupID, user.LfUsername, utils.ClaTypeICLA)
-										if gerritIclaErr != nil {
-											msg := fmt.Sprintf("unable to remove gerrit user:%s from group:%s", user.LfUsername, approvalList.ClaGroupID)
-											log.WithFields(f).WithError(gerritIclaErr).Warn(msg)
-										}
-										eclaErr := repo.gerritService.RemoveUserFromGroup(ctx, &authUser, approvalList.ClaGroupID, user.LfUsername, utils.ClaTypeECLA)
-										if eclaErr != nil {
-											msg := fmt.Sprintf("unable to remove gerrit user:%s from group:%s", user.LfUsername, approvalList.ClaGroupID)
-											log.WithFields(f).WithError(eclaErr).Warn(msg)
-										}
-									}
-									results <- &ICLAUserResponse{
-										ICLASignature: &models.IclaSignature{
-											GithubUsername: icla.UserGHUsername,
-											LfUsername:     user.LfUsername,
-											SignatureID:    icla.SignatureID,
-										},
-									}
-								}
-							}
-						}()
-
-						for result := range results {
-							if result.Error == nil {
-								log.WithFields(f).Debug("processing icla...")
-								approvalList.ICLAs = append(approvalList.ICLAs, result.ICLASignature)
-								iclas = append(iclas, result.ICLASignature)
-							}
-						}
+// GetSignaturesWithApprovalListExpirations returns the signed and approved CCLA signatures with approval list entries
+// expiring before the specified time. The sparse approval list expiry index only holds the signatures with
+// time-bounded entries, sorted by their next expiry.
+func (repo repository) GetSignaturesWithApprovalListExpirations(ctx context.Context, expiringBefore time.Time) ([]*models.Signature, error) {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.GetSignaturesWithApprovalListExpirations")
+	defer span.End()
 
-					}
+	f := logrus.Fields{
+		"functionName":   "v1.signatures.repository.GetSignaturesWithApprovalListExpirations",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"expiringBefore": expiringBefore.Format(time.RFC3339),
+	}
 
-					// Invalidate signatures
-					repo.invalidateSignatures(ctx, &approvalList, claManager, eventArgs)
+	condition := expression.Key("approval_list_expiry_partition").Equal(expression.Value(approvalListExpiryPartition)).
+		And(expression.Key("approval_list_next_expiry").LessThanEqual(expression.Value(expiringBefore.UTC().Format(time.RFC3339))))
 
-					// Send email
-					repo.sendEmail(ctx, email, &approvalList, iclas, eclas)
+	var filterAdded bool
+	var filter expression.ConditionBuilder
+	filter = addAndCondition(filter, expression.Name("signature_type").Equal(expression.Value(utils.SignatureTypeCCLA)), &filterAdded)
+	filter = addAndCondition(filter, expression.Name("signature_approved").Equal(expression.Value(true)), &filterAdded)
+	filter = addAndCondition(filter, expression.Name("signature_signed").Equal(expression.Value(true)), &filterAdded)
 
-				}(email)
-			}
-			wg.Wait()
-		}
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).WithFilter(filter).WithProjection(buildProjection()).Build()
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("error building expression for approval list expirations query")
+		return nil, err
 	}
 
-	if (params.AddDomainApprovalList != nil && len(params.AddDomainApprovalList) > 0) || (params.RemoveDomainApprovalList != nil && len(params.RemoveDomainApprovalList) > 0) {
-
-		columnName := "domain_whitelist"
-		attrList := buildApprovalAttributeList(ctx, cclaSignature.DomainApprovalList, params.AddDomainApprovalList, params.RemoveDomainApprovalList)
-		// If no entries after consolidating all the updates, we need to remove the column
-		if attrList == nil || attrList.L == nil {
-			var rmColErr error
-			cclaSignature, rmColErr = repo.removeColumn(ctx, cclaSignature.SignatureID, columnName)
-			if rmColErr != nil {
-				msg := fmt.Sprintf("unable to remove column %s for signature for company ID: %s project ID: %s, type: ccla, signed: %t, approved: %t",
-					columnName, companyID, projectID, true, true)
-				log.WithFields(f).Warn(msg)
-				return nil, errors.New(msg)
-			}
-		} else {
-			haveAdditions = true
-			expressionAttributeNames["#D"] = aws.String(columnName)
-			expressionAttributeValues[":d"] = attrList
-			updateExpression = updateExpression + " #D = :d, "
-		}
-		if params.RemoveDomainApprovalList != nil {
-			// Get ICLAs
-			log.WithFields(f).Debug("getting icla records... ")
-			iclas, iclaErr := repo.GetClaGroupICLASignatures(ctx, approvalList.ClaGroupID, nil, &approved, &signed, 0, "")
-			if iclaErr != nil {
-				log.WithFields(f).Warn("unable to get iclas")
-			}
-			// Get ECLAs
-			log.WithFields(f).Debug("getting ecla records... ")
-			companyProjectParams := signatures.GetProjectCompanyEmployeeSignaturesParams{
-				CompanyID: approvalList.CompanyID,
-				ProjectID: approvalList.ClaGroupID,
-			}
-
-			criteria := ApprovalCriteria{}
-			eclas, eclaErr := repo.GetProjectCompanyEmployeeSignatures(ctx, companyProjectParams, &criteria, int64(10))
-			if eclaErr != nil {
-				log.WithFields(f).Warnf("unable to get cclas for company: %s and project: %s ", approvalList.CompanyID, approvalList.ClaGroupID)
-			}
+	queryInput := &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+		ProjectionExpression:      expr.Projection(),
+		TableName:                 aws.String(repo.signatureTableName),
+		IndexName:                 aws.String(SignatureApprovalListExpiryIndex),
+	}
 
-			approvalList.Criteria = utils.EmailDomainCriteria
-			approvalList.ApprovalList = params.RemoveDomainApprovalList
-			approvalList.Action = utils.RemoveApprovals
-			approvalList.GerritICLAECLAs = gerritICLAECLAs
-			approvalList.ClaGroupID = projectID
-			approvalList.ClaGroupName = claGroupModel.ProjectName
-			approvalList.CompanyID = companyID
-			approvalList.Version = claGroupModel.Version
-			if iclas != nil {
-				approvalList.ICLAs = iclas.List
-			}
-			if eclas != nil {
-				approvalList.ECLAs = eclas.Signatures
-			}
+	var dbSignatures []ItemSignature
+	for {
+		results, queryErr := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
+		if queryErr != nil {
+			log.WithFields(f).WithError(queryErr).Warn("error querying signatures with approval list expirations")
+			return nil, queryErr
+		}
 
-			repo.invalidateSignatures(ctx, &approvalList, claManager, eventArgs)
+		var items []ItemSignature
+		unmarshalErr := dynamodbattribute.UnmarshalListOfMaps(results.Items, &items)
+		if unmarshalErr != nil {
+			log.WithFields(f).WithError(unmarshalErr).Warn("error unmarshalling signatures with approval list expirations")
+			return nil, unmarshalErr
 		}
-	}
+		dbSignatures = append(dbSignatures, items...)
 
-	if (params.AddGithubUsernameApprovalList != nil && len(params.AddGithubUsernameApprovalList) > 0) || (params.RemoveGithubUsernameApprovalList != nil && len(params.RemoveGithubUsernameApprovalList) > 0) {
-		columnName := "github_whitelist"
-		attrList := buildApprovalAttributeList(ctx, cclaSignature.GithubUsernameApprovalList, params.AddGithubUsernameApprovalList, params.RemoveGithubUsernameApprovalList)
-		// If no entries after consolidating all the updates, we need to remove the column
-		if attrList == nil || attrList.L == nil {
-			var rmColErr error
-			cclaSignature, rmColErr = repo.removeColumn(ctx, cclaSignature.SignatureID, columnName)
-			if rmColErr != nil {
-				msg := fmt.Sprintf("unable to remove column %s for signature for company ID: %s project ID: %s, type: ccla, signed: %t, approved: %t",
-					columnName, companyID, projectID, true, true)
-				log.WithFields(f).Warn(msg)
-				return nil, errors.New(msg)
-			}
-		} else {
-			haveAdditions = true
-			expressionAttributeNames["#G"] = aws.String(columnName)
-			expressionAttributeValues[":g"] = attrList
-			updateExpression = updateExpression + " #G = :g, "
-		}
-		if params.RemoveGithubUsernameApprovalList != nil {
-			// if email removal update signature approvals
-			if params.RemoveGithubUsernameApprovalList != nil {
-				var wg sync.WaitGroup
-				approvalList.Criteria = utils.GitHubUsernameCriteria
-				approvalList.ApprovalList = params.RemoveGithubUsernameApprovalList
-				approvalList.Action = utils.RemoveApprovals
-				approvalList.ClaGroupID = projectID
-				approvalList.ClaGroupName = claGroupModel.ProjectName
-				approvalList.CompanyID = companyID
-				approvalList.Version = claGroupModel.Version
-				wg.Add(len(params.RemoveGithubUsernameApprovalList))
-				for _, ghUsername := range params.RemoveGithubUsernameApprovalList {
-					go func(ghUsername string) {
-						defer wg.Done()
-						var iclas []*models.IclaSignature
-						var eclas []*models.Signature
-
-						criteria := &ApprovalCriteria{
-							GitHubUsername: ghUsername,
-						}
-						log.WithFields(f).Debugf("Updating signature records for ghUsernameApporvalList: %+v ", params.RemoveGithubUsernameApprovalList)
-						signs, ghUserErr := repo.GetProjectCompanyEmployeeSignatures(ctx, employeeSignatureParams, criteria, pageSize)
-						if ghUserErr != nil {
-							log.WithFields(f).Debugf("unable to get Company Employee signatures : %+v ", ghUserErr)
-							return
-						}
-						if signs.Signatures != nil {
-							approvalList.ECLAs = signs.Signatures
-							eclas = signs.Signatures
-						}
-						// Get ICLAs
-						claUser, claErr := repo.usersRepo.GetUserByGitHubUsername(ghUsername)
-						if claErr != nil {
-							log.WithFields(f).Debugf("unable to get User by GH Username: %s ", ghUsername)
-							return
-						}
-						if claUser != nil {
-							icla, iclaErr := repo.GetIndividualSignature(ctx, projectID, claUser.UserID, &approved, &signed)
-							if iclaErr != nil || icla == nil {
-								log.WithFields(f).Debugf("unable to get icla signature for user with ghUsername: %s ", ghUsername)
-							}
-							if icla != nil {
-								// Convert to IclSignature instance to leverage invalidateSignatures helper function
-								approvalList.ICLAs = []*models.IclaSignature{{
-									GithubUsername: icla.UserGHUsername,
-									LfUsername:     icla.UserLFID,
-									SignatureID:    icla.SignatureID,
-								}}
-							}
-						}
-
-						repo.invalidateSignatures(ctx, &approvalList, claManager, eventArgs)
-
-						// Send Email
-						repo.sendEmail(ctx, getBestEmail(claUser), &approvalList, iclas, eclas)
-
-					}(ghUsername)
-				}
-				wg.Wait()
-			}
+		if len(results.LastEvaluatedKey) == 0 {
+			break
 		}
+		queryInput.ExclusiveStartKey = results.LastEvaluatedKey
 	}
 
-	if (params.AddGithubOrgApprovalList != nil && len(params.AddGithubOrgApprovalList) > 0) || (params.RemoveGithubOrgApprovalList != nil && len(params.RemoveGithubOrgApprovalList) > 0) {
-		columnName := "github_org_whitelist"
-		attrList := buildApprovalAttributeList(ctx, cclaSignature.GithubOrgApprovalList, params.AddGithubOrgApprovalList, params.RemoveGithubOrgApprovalList)
-		// If no entries after consolidating all the updates, we need to remove the column
-		if attrList == nil || attrList.L == nil {
-			var rmColErr error
-			cclaSignature, rmColErr = repo.removeColumn(ctx, cclaSignature.SignatureID, columnName)
-			if rmColErr != nil {
-				msg := fmt.Sprintf("unable to remove column %s for signature for company ID: %s project ID: %s, type: ccla, signed: %t, approved: %t",
-					columnName, companyID, projectID, true, true)
-				log.WithFields(f).Warn(msg)
-				return nil, errors.New(msg)
-			}
-		} else {
-			haveAdditions = true
-			expressionAttributeNames["#GO"] = aws.String("github_org_whitelist")
-			expressionAttributeValues[":go"] = attrList
-			updateExpression = updateExpression + " #GO = :go, "
-		}
-
-		if params.RemoveGithubOrgApprovalList != nil {
-			approvalList.Criteria = utils.GitHubOrgCriteria
-			approvalList.ApprovalList = params.RemoveGithubOrgApprovalList
-			approvalList.Action = utils.RemoveApprovals
-			approvalList.Version = claGroupModel.Version
-			// Get repositories by CLAGroup
-			repositories, getRepoByCLAGroupErr := repo.repositoriesRepo.GetRepositoriesByCLAGroup(ctx, projectID, true)
-			if getRepoByCLAGroupErr != nil {
-				msg := fmt.Sprintf("unable to fetch repositories for claGroupID: %s ", projectID)
-				log.WithFields(f).WithError(getRepoByCLAGroupErr).Warn(msg)
-				return nil, errors.New(msg)
-			}
-			var ghOrgRepositories []*models.GithubRepository
-			var ghOrgs []*models.GithubOrganization
-			for _, repository := range repositories {
-				// Check for matching organization name in repositories table against approvalList removal GH Orgs
-				if utils.StringInSlice(repository.RepositoryOrganizationName, approvalList.ApprovalList) {
-					ghOrgRepositories = append(ghOrgRepositories, repository)
-				}
-			}
-
-			for _, ghOrgRepo := range ghOrgRepositories {
-				ghOrg, getGHOrgErr := repo.ghOrgRepo.GetGithubOrganization(ctx, ghOrgRepo.RepositoryOrganizationName)
-				if getGHOrgErr != nil {
-					msg := fmt.Sprintf("unable to get gh org by name: %s ", ghOrgRepo.RepositoryOrganizationName)
-					log.WithFields(f).WithError(getGHOrgErr).Warn(msg)
-					return nil, errors.New(msg)
-				}
-				ghOrgs = append(ghOrgs, ghOrg)
-			}
-
-			var ghUsernames []string
-			for _, ghOrg := range ghOrgs {
-				ghOrgUsers, getOrgMembersErr := github.GetOrganizationMembers(ctx, ghOrg.OrganizationName, ghOrg.OrganizationInstallationID)
-				if getOrgMembersErr != nil {
-					msg := fmt.Sprintf("unable to fetch ghOrgUsers for org: %s ", ghOrg.OrganizationName)
-					log.WithFields(f).WithError(getOrgMembersErr).Warnf(msg)
-					return nil, errors.New(msg)
-				}
-				ghUsernames = append(ghUsernames, ghOrgUsers...)
-			}
-			approvalList.GHUsernames = utils.RemoveDuplicates(ghUsernames)
+	return buildSignatureModels(ctx, repo.usersRepo, repo.companyRepo, dbSignatures, LoadACLDetails), nil
+}
 
-			repo.invalidateSignatures(ctx, &approvalList, claManager, eventArgs)
-		}
+// UpdateApprovalListExpirations sets the approval list expiry columns on the signature record, the columns are
+// removed when empty
+func (repo repository) UpdateApprovalListExpirations(ctx context.Context, signatureID string, expirations map[string]string, warningsSent []string) error {
+	f := logrus.Fields{
+		"functionName":   "v1.signatures.repository.UpdateApprovalListExpirations",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"signatureID":    signatureID,
 	}
 
-	// Ensure at least one value is set for us to update
-	if !haveAdditions {
-		log.WithFields(f).Debugf("no updates required to any of the approved list values company ID: %s project ID: %s, type: ccla, signed: %t, approved: %t - expecting at least something to update",
-			companyID, projectID, true, true)
-		return cclaSignature, nil
+	// The index attributes are removed along with the expirations to keep the signature out of the sparse index
+	var update expression.UpdateBuilder
+	if next := nextApprovalListExpiry(expirations); next != "" {
+		update = update.Set(expression.Name("approval_list_expirations"), expression.Value(expirations))
+		update = update.Set(expression.Name("approval_list_expiry_partition"), expression.Value(approvalListExpiryPartition))
+		update = update.Set(expression.Name("approval_list_next_expiry"), expression.Value(next))
+	} else {
+		update = update.Remove(expression.Name("approval_list_expirations"))
+		update = update.Remove(expression.Name("approval_list_expiry_partition"))
+		update = update.Remove(expression.Name("approval_list_next_expiry"))
+	}
+	if len(warningsSent) > 0 {
+		update = update.Set(expression.Name("approval_list_expiry_warnings_sent"), expression.Value(warningsSent))
+	} else {
+		update = update.Remove(expression.Name("approval_list_expiry_warnings_sent"))
 	}
 
-	// Remove trailing comma from the expression, if present
-	updateExpression = utils.TrimRemoveTrailingComma("SET " + updateExpression)
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("error building expression for approval list expirations update")
+		return err
+	}
 
-	// Update dynamoDB table
-	input := &dynamodb.UpdateItemInput{
+	_, updateErr := repo.dynamoDBClient.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
 		TableName: aws.String(repo.signatureTableName),
 		Key: map[string]*dynamodb.AttributeValue{
 			"signature_id": {
-				S: aws.String(cclaSignature.SignatureID),
+				S: aws.String(signatureID),
 			},
 		},
-		ExpressionAttributeNames:  expressionAttributeNames,
-		ExpressionAttributeValues: expressionAttributeValues,
-		UpdateExpression:          aws.String(updateExpression), //aws.String("SET #L = :l"),
-	}
-
-	log.WithFields(f).Debugf("updating approval list for company ID: %s project ID: %s, type: ccla, signed: %t, approved: %t",
-		companyID, projectID, signed, approved)
-
-	_, updateErr := repo.dynamoDBClient.UpdateItem(input)
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		UpdateExpression:          expr.Update(),
+	})
 	if updateErr != nil {
-		log.WithFields(f).Warnf("error updating approval lists for company ID: %s project ID: %s, type: ccla, signed: %t, approved: %t, error: %v",
-			companyID, projectID, signed, approved, updateErr)
-		return nil, updateErr
-	}
-
-	// Query the CCLA signature once again to load the most recent updates which include approval list updates from above
-	updatedSig, err := repo.GetCorporateSignature(ctx, projectID, companyID, &approved, &signed)
-	if err != nil || cclaSignature == nil {
-		msg := fmt.Sprintf("unable to get corporate signature for CLA Group: %s and company: %s", projectID, companyID)
-		log.WithFields(f).Warn(msg)
-		return nil, errors.New(msg)
+		log.WithFields(f).WithError(updateErr).Warn("error updating approval list expirations")
+		return updateErr
 	}
 
-	// Just grab and use the first one - need to figure out conflict resolution if more than one
-	return updatedSig, nil
+	return nil
 }
 
-// sendEmail is a helper function used to render email for (CCLA, ICLA, ECLA cases)
-func (repo repository) sendEmail(ctx context.Context, email string, approvalList *ApprovalList, iclas []*models.IclaSignature, eclas []*models.Signature) {
+// GetSignatureDocumentsForVerification returns up to limit signed ICLA and CCLA documents which were never verified
+// or verified before the specified RFC3339 time
+func (repo repository) GetSignatureDocumentsForVerification(ctx context.Context, verifiedBefore string, limit int) ([]*SignatureDocument, error) {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.GetSignatureDocumentsForVerification")
+	defer span.End()
+
 	f := logrus.Fields{
-		"functionName":   "v1.signatures.repository.sendEmail",
+		"functionName":   "v1.signatures.repository.GetSignatureDocumentsForVerification",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
-	}
-	companyName := ""
-	company, companyErr := repo.companyRepo.GetCompany(ctx, approvalList.CompanyID)
-	if companyErr != nil {
-		log.WithFields(f).Debugf("unable to get company")
-	}
-	if company != nil {
-		companyName = company.CompanyName
-	}
-
-	params := InvalidateSignatureTemplateParams{
-		Company:       companyName,
-		RecipientName: email,
-		ClaManager:    utils.GetBestUsername(approvalList.CLAManager),
-		CLAManagers:   approvalList.ManagersInfo,
-		CLAGroupName:  approvalList.ClaGroupName,
+		"verifiedBefore": verifiedBefore,
+	}
+
+	// employee acknowledgements reference the company CCLA and have no signed document
+	individualOrCorporate := expression.Name("signature_type").Equal(expression.Value(utils.SignatureTypeCCLA)).Or(
+		expression.Name("signature_type").Equal(expression.Value(utils.SignatureTypeCLA)).And(
+			expression.Name("signature_user_ccla_company_id").AttributeNotExists()))
+	notVerifiedSince := expression.Name("signature_document_verified_on").AttributeNotExists().Or(
+		expression.Name("signature_document_verified_on").LessThan(expression.Value(verifiedBefore)))
+	filter := expression.Name("signature_signed").Equal(expression.Value(true)).And(individualOrCorporate, notVerifiedSince)
+
+	projection := expression.NamesList(
+		expression.Name("signature_id"),
+		expression.Name("signature_project_id"),
+		expression.Name("signature_type"),
+		expression.Name("signature_reference_id"),
+		expression.Name("signature_document_sha256"),
+		expression.Name("signature_document_integrity_status"),
+		expression.Name("signature_document_verified_on"),
+	)
+	expr, err := expression.NewBuilder().WithFilter(filter).WithProjection(projection).Build()
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("error building expression for signed documents scan")
+		return nil, err
 	}
 
-	// check for signature type (CCLA, ICLA, ECLA)
-	var removalType = ""
-
-	// case 1 CCLA
-	if len(iclas) == 0 && len(eclas) == 0 {
-		removalType = CCLA
-	} else if len(iclas) > 0 && len(eclas) == 0 {
-		// case 2 ccla + icla
-		removalType = CCLAICLA
-	} else if len(iclas) > 0 && len(eclas) > 0 {
-		// case 3 ccla + icla + ecla
-		removalType = CCLAICLAECLA
+	scanInput := &dynamodb.ScanInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		FilterExpression:          expr.Filter(),
+		ProjectionExpression:      expr.Projection(),
+		TableName:                 aws.String(repo.signatureTableName),
 	}
 
-	// Send CCLA Email
-	if removalType == CCLA {
-		subject := fmt.Sprintf("EasyCLA: CCLA invalidated  for :%s ", approvalList.ClaGroupName)
-		log.WithFields(f).Debugf("sending ccla invalidation email to :%s ", email)
-		body, renderErr := utils.RenderTemplate(approvalList.Version, InvalidateCCLASignatureTemplateName, InvalidateCCLASignatureTemplate, params)
-		if renderErr != nil {
-			log.WithFields(f).Debugf("unable to render email approval template for user: %s ", email)
-		} else {
-			err := utils.SendEmail(subject, body, []string{email})
-			if err != nil {
-				log.WithFields(f).Debugf("unable to send approval list update email to : %s ", email)
-			}
+	var docs []*SignatureDocument
+	for len(docs) < limit {
+		results, scanErr := repo.dynamoDBClient.ScanWithContext(ctx, scanInput)
+		if scanErr != nil {
+			log.WithFields(f).WithError(scanErr).Warn("error scanning signed documents")
+			return nil, scanErr
 		}
-	} else if removalType == ICLA {
-		subject := fmt.Sprintf("EasyCLA: ICLA invalidated  for :%s ", approvalList.ClaGroupName)
-		log.WithFields(f).Debugf("sending icla invalidation email to :%s ", email)
-		body, renderErr := utils.RenderTemplate(approvalList.Version, InvalidateICLASignatureTemplateName, InvalidateICLASignatureTemplate, params)
-		if renderErr != nil {
-			log.WithFields(f).Debugf("unable to render email approval template for user: %s ", email)
-		} else {
-			err := utils.SendEmail(subject, body, []string{email})
-			if err != nil {
-				log.WithFields(f).Debugf("unable to send approval list update email to : %s ", email)
-			}
-		}
-	} else if removalType == CCLAICLA {
-		subject := fmt.Sprintf("EasyCLA: ICLA invalidated  for :%s ", approvalList.ClaGroupName)
-		log.WithFields(f).Debugf("sending icla invalidation email to :%s ", email)
-		body, renderErr := utils.RenderTemplate(approvalList.Version, InvalidateCCLAICLASignatureTemplateName, InvalidateCCLASignatureTemplate, params)
-		if renderErr != nil {
-			log.WithFields(f).Debugf("unable to render email approval template for user: %s ", email)
-		} else {
-			err := utils.SendEmail(subject, body, []string{email})
-			if err != nil {
-				log.WithFields(f).Debugf("unable to send approval list update email to : %s ", email)
-			}
+
+		var items []ItemSignature
+		unmarshalErr := dynamodbattribute.UnmarshalListOfMaps(results.Items, &items)
+		if unmarshalErr != nil {
+			log.WithFields(f).WithError(unmarshalErr).Warn("error unmarshalling signed documents")
+			return nil, unmarshalErr
 		}
-	} else if removalType == CCLAICLAECLA {
-		subject := fmt.Sprintf("EasyCLA: Employee Acknowledgement invalidated  for :%s ", approvalList.ClaGroupName)
-		log.WithFields(f).Debugf("sending employee acknowledgement invalidation email to :%s ", email)
-		body, renderErr := utils.RenderTemplate(approvalList.Version, InvalidateCCLAICLAECLASignatureTemplateName, InvalidateCCLAICLAECLASignatureTemplate, params)
-		if renderErr != nil {
-			log.WithFields(f).Debugf("unable to render email approval template for user: %s ", email)
-		} else {
-			err := utils.SendEmail(subject, body, []string{email})
-			if err != nil {
-				log.WithFields(f).Debugf("unable to send approval list update email to : %s ", email)
+		for i := range items {
+			if len(docs) == limit {
+				break
 			}
+			docs = append(docs, signatureDocumentFromItem(&items[i]))
 		}
-	}
-}
 
-// invalidateSignatures is a helper function that invalidates signature records based on approval list
-func (repo repository) invalidateSignatures(ctx context.Context, approvalList *ApprovalList, claManager *models.User, eventArgs *events.LogEventArgs) {
-	f := logrus.Fields{
-		"functionName":   "v1.signatures.repository.invalidateSignatures",
-		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
-		"claGroupID":     &approvalList,
-	}
-
-	if approvalList.ICLAs != nil {
-		var iclaWg sync.WaitGroup
-		//Iterate iclas
-		iclaWg.Add(len(approvalList.ICLAs))
-		log.WithFields(f).Debug("invalidating signature icla records... ")
-		for _, icla := range approvalList.ICLAs {
-			go func(icla *models.IclaSignature) {
-				defer iclaWg.Done()
-				signature, sigErr := repo.GetSignature(ctx, icla.SignatureID)
-				if sigErr != nil {
-					log.WithFields(f).Warnf("unable to fetch signature for ID: %s ", icla.SignatureID)
-					return
-				}
-				// Grab user record
-				if signature.SignatureReferenceID == "" {
-					log.WithFields(f).Warnf("no signatureReferenceID for signature: %+v ", signature)
-					return
-				}
-
-				user, verifyErr := repo.verifyUserApprovals(ctx, signature.SignatureReferenceID, signature.SignatureID, claManager, approvalList)
-				if verifyErr != nil {
-					log.WithFields(f).Warnf("unable to verify user: %s ", signature.SignatureReferenceID)
-					return
-				}
-				// Map representing CLA types against email ....
-				email := getBestEmail(user)
-				// Log Event
-				eventArgs.EventData = &events.SignatureInvalidatedApprovalRejectionEventData{
-					SignatureID: icla.SignatureID,
-					CLAManager:  claManager,
-					CLAGroupID:  signature.ProjectID,
-					Email:       email,
-				}
-				repo.eventsService.LogEventWithContext(ctx, eventArgs)
-			}(icla)
-		}
-		iclaWg.Wait()
-	}
-
-	if approvalList.ECLAs != nil {
-		var eclaWg sync.WaitGroup
-		log.WithFields(f).Debug("invalidating signature ecla records... ")
-		// Iterate eclas
-		eclaWg.Add(len(approvalList.ECLAs))
-		for _, ecla := range approvalList.ECLAs {
-			go func(ecla *models.Signature) {
-				defer eclaWg.Done()
-				// Grab user record
-				if ecla.SignatureReferenceID == "" {
-					log.WithFields(f).Warnf("no signatureReferenceID for signature: %+v ", ecla)
-					return
-				}
-				user, verifyErr := repo.verifyUserApprovals(ctx, ecla.SignatureReferenceID, ecla.SignatureID, claManager, approvalList)
-				if verifyErr != nil {
-					log.WithFields(f).Warnf("unable to verify user: %s ", ecla.SignatureReferenceID)
-					return
-				}
-				email := getBestEmail(user)
-				// Log Event
-				eventArgs.EventData = &events.SignatureInvalidatedApprovalRejectionEventData{
-					SignatureID: ecla.SignatureID,
-					CLAManager:  claManager,
-					CLAGroupID:  ecla.ProjectID,
-					Email:       email,
-				}
-				repo.eventsService.LogEventWithContext(ctx, eventArgs)
-			}(ecla)
+		if len(results.LastEvaluatedKey) == 0 {
+			break
 		}
-		eclaWg.Wait()
+		scanInput.ExclusiveStartKey = results.LastEvaluatedKey
 	}
+
+	return docs, nil
 }
 
-// verify UserApprovals checks user
-func (repo repository) verifyUserApprovals(ctx context.Context, userID, signatureID string, claManager *models.User, approvalList *ApprovalList) (*models.User, error) {
+// UpdateSignatureDocumentIntegrity records the outcome of the signed document integrity check, the document hash is
+// only set when not empty
+func (repo repository) UpdateSignatureDocumentIntegrity(ctx context.Context, signatureID, documentSHA256, status, verifiedOn string) error {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.UpdateSignatureDocumentIntegrity")
+	defer span.End()
+
 	f := logrus.Fields{
-		"functionName":   "v1.signatures.repository.verifyUserApprovals",
+		"functionName":   "v1.signatures.repository.UpdateSignatureDocumentIntegrity",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
-		"userID":         userID,
+		"signatureID":    signatureID,
+		"status":         status,
 	}
 
-	user, err := repo.usersRepo.GetUser(userID)
+	update := expression.Set(expression.Name("signature_document_integrity_status"), expression.Value(status)).
+		Set(expression.Name("signature_document_verified_on"), expression.Value(verifiedOn))
+	if documentSHA256 != "" {
+		update = update.Set(expression.Name("signature_document_sha256"), expression.Value(documentSHA256))
+	}
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
 	if err != nil {
-		log.WithFields(f).Warnf("unable to get user record for ID: %s ", userID)
-		return nil, err
+		log.WithFields(f).WithError(err).Warn("error building expression for document integrity update")
+		return err
 	}
-	email := getBestEmail(user)
-
-	authUser := auth.User{
-		Email:    claManager.LfEmail,
-		UserName: claManager.LfUsername,
-	}
-
-	if approvalList.Criteria == utils.EmailDomainCriteria {
-		// Handle Domains
-		log.WithFields(f).Debugf("Handling domain for user email: %s  with approval list: %+v ", email, approvalList.ApprovalList)
-		domain := strings.Split(email, "@")[1]
-		if utils.StringInSlice(domain, approvalList.ApprovalList) {
-			if (!utils.StringInSlice(user.GithubUsername, approvalList.GitHubUsernameApprovals) || utils.StringInSlice(user.LfUsername, approvalList.GerritICLAECLAs)) && !utils.StringInSlice(email, approvalList.EmailApprovals) {
-				//Invalidate record
-				note := fmt.Sprintf("Signature invalidated (approved set to false) by %s due to %s  removal", utils.GetBestUsername(claManager), utils.EmailDomainCriteria)
-				err := repo.InvalidateProjectRecord(ctx, signatureID, note)
-				if err != nil {
-					log.WithFields(f).Warnf("unable to invalidate record for signatureID: %s ", signatureID)
-					return user, err
-				}
-
-				// Update Gerrit group users
-				if utils.StringInSlice(user.LfUsername, approvalList.GerritICLAECLAs) {
-					log.WithFields(f).Debugf("removing gerrit user:%s  from claGroup: %s ...", user.LfUsername, approvalList.ClaGroupID)
-					iclaErr := repo.gerritService.RemoveUserFromGroup(ctx, &authUser, approvalList.ClaGroupID, user.LfUsername, utils.ClaTypeICLA)
-					if iclaErr != nil {
-						msg := fmt.Sprintf("unable to remove gerrit user:%s from group:%s", user.LfUsername, approvalList.ClaGroupID)
-						log.WithFields(f).Warn(msg)
-					}
-					eclaErr := repo.gerritService.RemoveUserFromGroup(ctx, &authUser, approvalList.ClaGroupID, user.LfUsername, utils.ClaTypeECLA)
-					if eclaErr != nil {
-						msg := fmt.Sprintf("unable to remove gerrit user:%s from group:%s", user.LfUsername, approvalList.ClaGroupID)
-						log.WithFields(f).Warn(msg)
-					}
-				}
-			}
-		}
-	} else if approvalList.Criteria == utils.GitHubOrgCriteria {
-		// Handle GH Org Approvals
-		if utils.StringInSlice(user.GithubUsername, approvalList.GHUsernames) {
-			if !utils.StringInSlice(getBestEmail(user), approvalList.EmailApprovals) && !utils.StringInSlice(user.GithubUsername, approvalList.GitHubUsernameApprovals) {
-				//Invalidate record
-
-				note := fmt.Sprintf("Signature invalidated (approved set to false) by %s due to %s  removal", utils.GetBestUsername(claManager), utils.GitHubOrgCriteria)
-				err := repo.InvalidateProjectRecord(ctx, signatureID, note)
-				if err != nil {
-					log.WithFields(f).Warnf("unable to invalidate record for signatureID: %s ", signatureID)
-					return user, err
-				}
-			}
-		}
-	} else if approvalList.Criteria == utils.GitHubUsernameCriteria || approvalList.Criteria == utils.EmailCriteria {
-		note := fmt.Sprintf("Signature invalidated (approved set to false) by %s due to %s  removal", utils.GetBestUsername(claManager), approvalList.Criteria)
-		err := repo.InvalidateProjectRecord(ctx, signatureID, note)
-		if err != nil {
-			log.WithFields(f).Warnf("unable to invalidate record for signatureID: %s ", signatureID)
-			return user, err
-		}
 
+	_, updateErr := repo.dynamoDBClient.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(repo.signatureTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"signature_id": {
+				S: aws.String(signatureID),
+			},
+		},
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		UpdateExpression:          expr.Update(),
+	})
+	if updateErr != nil {
+		log.WithFields(f).WithError(updateErr).Warn("error updating document integrity")
+		return updateErr
 	}
 
-	return user, nil
+	return nil
 }
 
 // removeColumn is a helper function to remove a given column when we need to zero out the column value - typically the approval list
@@ -2811,7 +2531,7 @@ func (repo repository) removeColumn(ctx context.Context, signatureID, columnName
 		ReturnValues:     aws.String(dynamodb.ReturnValueNone),
 	}
 
-	_, updateErr := repo.dynamoDBClient.UpdateItem(input)
+	_, updateErr := repo.dynamoDBClient.UpdateItemWithContext(ctx, input)
 	if updateErr != nil {
 		log.WithFields(f).Warnf("error removing approval lists column %s for signature ID: %s, error: %v", columnName, signatureID, updateErr)
 		return nil, updateErr
@@ -2826,6 +2546,9 @@ func (repo repository) removeColumn(ctx context.Context, signatureID, columnName
 }
 
 func (repo repository) AddSigTypeSignedApprovedID(ctx context.Context, signatureID string, val string) error {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.AddSigTypeSignedApprovedID")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":            "v1.signatures.repository.AddSigTypeSignedApprovedID",
 		utils.XREQUESTID:          ctx.Value(utils.XREQUESTID),
@@ -2849,7 +2572,7 @@ func (repo repository) AddSigTypeSignedApprovedID(ctx context.Context, signature
 		},
 		UpdateExpression: aws.String("SET #signature_project_id_skey = :val"),
 	}
-	_, updateErr := repo.dynamoDBClient.UpdateItem(input)
+	_, updateErr := repo.dynamoDBClient.UpdateItemWithContext(ctx, input)
 	if updateErr != nil {
 		log.WithFields(f).Warnf("unable to update sigtype_signed_approved_id for signature_id: %s with input: %+v, error: %+v",
 			signatureID, input, updateErr)
@@ -2858,6 +2581,9 @@ func (repo repository) AddSigTypeSignedApprovedID(ctx context.Context, signature
 	return nil
 }
 func (repo repository) AddUsersDetails(ctx context.Context, signatureID string, userID string) error {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.AddUsersDetails")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.signatures.repository.AddUserDetails",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -2912,7 +2638,7 @@ func (repo repository) AddUsersDetails(ctx context.Context, signatureID string,
 	input.UpdateExpression = aws.String(ue.Expression)
 	input.ExpressionAttributeNames = ue.ExpressionAttributeNames
 	input.ExpressionAttributeValues = ue.ExpressionAttributeValues
-	_, updateErr := repo.dynamoDBClient.UpdateItem(input)
+	_, updateErr := repo.dynamoDBClient.UpdateItemWithContext(ctx, input)
 	if updateErr != nil {
 		log.WithFields(f).Warnf("unable to add users details to signature ID: %s with input: %+v, error = %s",
 			signatureID, input, updateErr.Error())
@@ -2923,6 +2649,9 @@ func (repo repository) AddUsersDetails(ctx context.Context, signatureID string,
 }
 
 func (repo repository) AddSignedOn(ctx context.Context, signatureID string) error {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.AddSignedOn")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.signatures.repository.AddSignedOn",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -2948,7 +2677,7 @@ func (repo repository) AddSignedOn(ctx context.Context, signatureID string) erro
 	}
 
 	log.WithFields(f).Debug("updating signed on date...")
-	_, updateErr := repo.dynamoDBClient.UpdateItem(input)
+	_, updateErr := repo.dynamoDBClient.UpdateItemWithContext(ctx, input)
 	if updateErr != nil {
 		log.WithFields(f).Warnf("unable to signed_on for signature ID: %s using update input: %+v, error = %s",
 			signatureID, input, updateErr.Error())
@@ -2960,6 +2689,9 @@ func (repo repository) AddSignedOn(ctx context.Context, signatureID string) erro
 }
 
 func (repo repository) GetClaGroupICLASignatures(ctx context.Context, claGroupID string, searchTerm *string, approved, signed *bool, pageSize int64, nextKey string) (*models.IclaSignatures, error) {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.GetClaGroupICLASignatures")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.signatures.repository.GetClaGroupICLASignatures",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -3061,7 +2793,7 @@ func (repo repository) GetClaGroupICLASignatures(ctx context.Context, claGroupID
 	// Loop until we have all the records
 	for ok := true; ok; ok = lastEvaluatedKey != "" {
 		// Make the DynamoDB Query API call
-		results, errQuery := repo.dynamoDBClient.Query(queryInput)
+		results, errQuery := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 		if errQuery != nil {
 			log.WithFields(f).Warnf("error retrieving icla signatures for project: %s , error: %v",
 				claGroupID, errQuery)
@@ -3077,7 +2809,7 @@ func (repo repository) GetClaGroupICLASignatures(ctx context.Context, claGroupID
 			return nil, unmarshallError
 		}
 
-		intermediateResponse = append(intermediateResponse, repo.getIntermediateICLAResponse(f, dbSignatures)...)
+		intermediateResponse = append(intermediateResponse, getIntermediateICLAResponse(f, dbSignatures)...)
 
 		log.WithFields(f).Debugf("LastEvaluatedKey: %+v", results.LastEvaluatedKey["signature_id"])
 		if results.LastEvaluatedKey["signature_id"] != nil {
@@ -3104,7 +2836,7 @@ func (repo repository) GetClaGroupICLASignatures(ctx context.Context, claGroupID
 		ResultCount:    int64(len(intermediateResponse)),
 	}
 
-	iclaSignatures, err := repo.addAdditionalICLAMetaData(f, intermediateResponse)
+	iclaSignatures, err := addAdditionalICLAMetaData(f, repo.usersRepo, intermediateResponse)
 	if err != nil {
 		return nil, err
 	}
@@ -3113,7 +2845,8 @@ func (repo repository) GetClaGroupICLASignatures(ctx context.Context, claGroupID
 	return out, nil
 }
 
-func (repo repository) getIntermediateICLAResponse(f logrus.Fields, dbSignatures []ItemSignature) []*iclaSignatureWithDetails {
+// getIntermediateICLAResponse converts the signature database models into ICLA response models with the reference ID
+func getIntermediateICLAResponse(f logrus.Fields, dbSignatures []ItemSignature) []*iclaSignatureWithDetails {
 	var intermediateResponse []*iclaSignatureWithDetails
 
 	for _, sig := range dbSignatures {
@@ -3160,7 +2893,8 @@ func (repo repository) getIntermediateICLAResponse(f logrus.Fields, dbSignatures
 	return intermediateResponse
 }
 
-func (repo repository) addAdditionalICLAMetaData(f logrus.Fields, intermediateResponse []*iclaSignatureWithDetails) ([]*models.IclaSignature, error) {
+// addAdditionalICLAMetaData fills in the missing user details on the ICLA response models
+func addAdditionalICLAMetaData(f logrus.Fields, usersRepo users.UserRepository, intermediateResponse []*iclaSignatureWithDetails) ([]*models.IclaSignature, error) {
 	log.WithFields(f).Debugf("Adding additional meta-data for %d records...", len(intermediateResponse))
 	// For some older ICLA signatures, we are missing the user's info, but we have their internal ID - let's look up those values before returning
 	responseChannel := make(chan *models.IclaSignature)
@@ -3169,7 +2903,7 @@ func (repo repository) addAdditionalICLAMetaData(f logrus.Fields, intermediateRe
 
 	for _, iclaDetails := range intermediateResponse {
 		go func(iclaSignatureWithDetails *iclaSignatureWithDetails) {
-			userModel, userLookupErr := repo.usersRepo.GetUser(iclaSignatureWithDetails.SignatureReferenceID)
+			userModel, userLookupErr := usersRepo.GetUser(iclaSignatureWithDetails.SignatureReferenceID)
 			if userLookupErr != nil || userModel == nil {
 				log.WithFields(f).WithError(userLookupErr).Warnf("unable to lookup user with id: %s", iclaSignatureWithDetails.SignatureReferenceID)
 			} else {
@@ -3213,6 +2947,9 @@ func (repo repository) addAdditionalICLAMetaData(f logrus.Fields, intermediateRe
 }
 
 func (repo repository) GetClaGroupCorporateContributors(ctx context.Context, claGroupID string, companyID *string, searchTerm *string) (*models.CorporateContributorList, error) {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.GetClaGroupCorporateContributors")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.signatures.repository.GetClaGroupCorporateContributors",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -3267,7 +3004,7 @@ func (repo repository) GetClaGroupCorporateContributors(ctx context.Context, cla
 	for {
 		// Make the DynamoDB Query API call
 		log.WithFields(f).Debug("querying signatures...")
-		results, queryErr := repo.dynamoDBClient.Query(queryInput)
+		results, queryErr := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 		if queryErr != nil {
 			log.WithFields(f).Warnf("error retrieving icla signatures for project: %s, error: %v", claGroupID, queryErr)
 			return nil, queryErr
@@ -3284,48 +3021,7 @@ func (repo repository) GetClaGroupCorporateContributors(ctx context.Context, cla
 
 		log.WithFields(f).Debugf("located %d signatures...", len(dbSignatures))
 		for _, sig := range dbSignatures {
-			var sigCreatedTime = sig.DateCreated
-			t, err := utils.ParseDateTime(sig.DateCreated)
-			if err != nil {
-				log.WithFields(f).WithError(err).Warn("unable to parse signature date created time")
-			} else {
-				sigCreatedTime = utils.TimeToString(t)
-			}
-
-			// Set the signed date/time
-			var sigSignedTime string
-			// Use the user docusign date signed value if it is present - older signatures do not have this
-			if sig.UserDocusignDateSigned != "" {
-				// Put the date into a standard format
-				t, err = utils.ParseDateTime(sig.UserDocusignDateSigned)
-				if err != nil {
-					log.WithFields(f).WithError(err).Warn("unable to parse signature docusign date signed time")
-				} else {
-					sigSignedTime = utils.TimeToString(t)
-				}
-			} else {
-				// Put the date into a standard format
-				t, err = utils.ParseDateTime(sig.DateCreated)
-				if err != nil {
-					log.WithFields(f).WithError(err).Warn("unable to parse signature date created time")
-				} else {
-					sigSignedTime = utils.TimeToString(t)
-				}
-			}
-
-			signatureVersion := fmt.Sprintf("v%s.%s", sig.SignatureDocumentMajorVersion, sig.SignatureDocumentMinorVersion)
-			out.List = append(out.List, &models.CorporateContributor{
-				SignatureID:            sig.SignatureID,
-				GithubID:               sig.UserGithubUsername,
-				LinuxFoundationID:      sig.UserLFUsername,
-				Name:                   sig.UserName,
-				SignatureVersion:       signatureVersion,
-				Email:                  sig.UserEmail,
-				Timestamp:              sigCreatedTime,
-				UserDocusignName:       sig.UserDocusignName,
-				UserDocusignDateSigned: sigSignedTime,
-				SignatureModified:      sig.DateModified,
-			})
+			out.List = append(out.List, buildCorporateContributor(f, sig))
 		}
 
 		if len(results.LastEvaluatedKey) == 0 {
@@ -3341,31 +3037,49 @@ func (repo repository) GetClaGroupCorporateContributors(ctx context.Context, cla
 	return out, nil
 }
 
-// getGerritUsers is a helper function to fetch the list of gerrit users for the specified type - results are returned through the specified results channel
-func (repo repository) getGerritUsers(ctx context.Context, authUser *auth.User, projectSFID string, claType string, gerritResultChannel chan *GerritUserResponse) {
-	f := logrus.Fields{
-		"functionName":   "v1.signatures.repository.getGerritUsers",
-		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
-		"projectSFID":    projectSFID,
+// buildCorporateContributor converts an employee signature database model into a corporate contributor response model
+func buildCorporateContributor(f logrus.Fields, sig ItemSignature) *models.CorporateContributor {
+	var sigCreatedTime = sig.DateCreated
+	t, err := utils.ParseDateTime(sig.DateCreated)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to parse signature date created time")
+	} else {
+		sigCreatedTime = utils.TimeToString(t)
 	}
-	log.WithFields(f).Debugf("querying gerrit for %s gerrit users...", claType)
-	gerritIclaUsers, getGerritQueryErr := repo.gerritService.GetUsersOfGroup(ctx, authUser, projectSFID, claType)
-	if getGerritQueryErr != nil || gerritIclaUsers == nil {
-		msg := fmt.Sprintf("unable to fetch gerrit users for claGroup: %s , claType: %s ", projectSFID, claType)
-		log.WithFields(f).WithError(getGerritQueryErr).Warn(msg)
-		gerritResultChannel <- &GerritUserResponse{
-			gerritGroupResponse: nil,
-			queryType:           claType,
-			Error:               errors.New(msg),
+
+	// Set the signed date/time
+	var sigSignedTime string
+	// Use the user docusign date signed value if it is present - older signatures do not have this
+	if sig.UserDocusignDateSigned != "" {
+		// Put the date into a standard format
+		t, err = utils.ParseDateTime(sig.UserDocusignDateSigned)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("unable to parse signature docusign date signed time")
+		} else {
+			sigSignedTime = utils.TimeToString(t)
+		}
+	} else {
+		// Put the date into a standard format
+		t, err = utils.ParseDateTime(sig.DateCreated)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("unable to parse signature date created time")
+		} else {
+			sigSignedTime = utils.TimeToString(t)
 		}
-		return
 	}
 
-	log.WithFields(f).Debugf("retrieved %d gerrit users for CLA type: %s...", len(gerritIclaUsers.Members), claType)
-	gerritResultChannel <- &GerritUserResponse{
-		gerritGroupResponse: gerritIclaUsers,
-		queryType:           claType,
-		Error:               nil,
+	signatureVersion := fmt.Sprintf("v%s.%s", sig.SignatureDocumentMajorVersion, sig.SignatureDocumentMinorVersion)
+	return &models.CorporateContributor{
+		SignatureID:            sig.SignatureID,
+		GithubID:               sig.UserGithubUsername,
+		LinuxFoundationID:      sig.UserLFUsername,
+		Name:                   sig.UserName,
+		SignatureVersion:       signatureVersion,
+		Email:                  sig.UserEmail,
+		Timestamp:              sigCreatedTime,
+		UserDocusignName:       sig.UserDocusignName,
+		UserDocusignDateSigned: sigSignedTime,
+		SignatureModified:      sig.DateModified,
 	}
 }
 
@@ -3431,3 +3145,72 @@ func decodeNextKey(str string) (map[string]*dynamodb.AttributeValue, error) {
 
 	return m, nil
 }
+
+// GetCLAGroupSignedSignatures returns all the signed and approved ICLA or CCLA signatures of the CLA Group, the
+// employee acknowledgements are not included
+func (repo repository) GetCLAGroupSignedSignatures(ctx context.Context, claGroupID, claType string) ([]*models.Signature, error) {
+	ctx, span := telemetry.StartSpan(ctx, "signatures.repository.GetCLAGroupSignedSignatures")
+	defer span.End()
+
+	f := logrus.Fields{
+		"functionName":   "v1.signatures.repository.GetCLAGroupSignedSignatures",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+		"claType":        claType,
+	}
+
+	var filterAdded bool
+	var filter expression.ConditionBuilder
+	switch claType {
+	case utils.ClaTypeICLA:
+		filter = addAndCondition(filter, expression.Name("signature_type").Equal(expression.Value(utils.SignatureTypeCLA)), &filterAdded)
+		filter = addAndCondition(filter, expression.Name("signature_user_ccla_company_id").AttributeNotExists(), &filterAdded)
+	case utils.ClaTypeCCLA:
+		filter = addAndCondition(filter, expression.Name("signature_type").Equal(expression.Value(utils.SignatureTypeCCLA)), &filterAdded)
+	default:
+		return nil, fmt.Errorf("not supported cla type: %s", claType)
+	}
+	filter = addAndCondition(filter, expression.Name("signature_approved").Equal(expression.Value(true)), &filterAdded)
+	filter = addAndCondition(filter, expression.Name("signature_signed").Equal(expression.Value(true)), &filterAdded)
+
+	condition := expression.Key("signature_project_id").Equal(expression.Value(claGroupID))
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).WithFilter(filter).WithProjection(buildProjection()).Build()
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("error building expression for the CLA Group signatures query")
+		return nil, err
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+		ProjectionExpression:      expr.Projection(),
+		TableName:                 aws.String(repo.signatureTableName),
+		IndexName:                 aws.String(SignatureProjectIDIndex),
+	}
+
+	var dbSignatures []ItemSignature
+	for {
+		results, queryErr := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
+		if queryErr != nil {
+			log.WithFields(f).WithError(queryErr).Warn("error querying the CLA Group signatures")
+			return nil, queryErr
+		}
+
+		var items []ItemSignature
+		unmarshalErr := dynamodbattribute.UnmarshalListOfMaps(results.Items, &items)
+		if unmarshalErr != nil {
+			log.WithFields(f).WithError(unmarshalErr).Warn("error unmarshalling the CLA Group signatures")
+			return nil, unmarshalErr
+		}
+		dbSignatures = append(dbSignatures, items...)
+
+		if len(results.LastEvaluatedKey) == 0 {
+			break
+		}
+		queryInput.ExclusiveStartKey = results.LastEvaluatedKey
+	}
+
+	return buildSignatureModels(ctx, repo.usersRepo, repo.companyRepo, dbSignatures, LoadACLDetails), nil
+}
@@ -0,0 +1,889 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/config"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
+	"github.com/communitybridge/easycla/cla-backend-go/github_organizations"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/restapi/operations/signatures"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+)
+
+// approval list column names
+const (
+	EmailApprovalListColumn          = "email_whitelist"
+	DomainApprovalListColumn         = "domain_whitelist"
+	GitHubUsernameApprovalListColumn = "github_whitelist"
+	GitHubOrgApprovalListColumn      = "github_org_whitelist"
+)
+
+// MemoryRepository is an embedded, in-memory implementation of the SignatureRepository. It is intended for local
+// development and tests where DynamoDB is not available.
+type MemoryRepository struct {
+	lock          sync.RWMutex
+	signatures    map[string]ItemSignature
+	companyRepo   company.IRepository
+	usersRepo     users.UserRepository
+	eventsService events.Service
+
+	repositoriesRepo repositories.Repository
+	ghOrgRepo        github_organizations.RepositoryInterface
+	gerritService    gerrits.Service
+}
+
+// NewMemoryRepository creates a new instance of the in-memory signature repository
+func NewMemoryRepository(companyRepo company.IRepository, usersRepo users.UserRepository, eventsService events.Service, repositoriesRepo repositories.Repository, ghOrgRepo github_organizations.RepositoryInterface, gerritService gerrits.Service) *MemoryRepository {
+	return &MemoryRepository{
+		signatures:       make(map[string]ItemSignature),
+		companyRepo:      companyRepo,
+		usersRepo:        usersRepo,
+		eventsService:    eventsService,
+		repositoriesRepo: repositoriesRepo,
+		ghOrgRepo:        ghOrgRepo,
+		gerritService:    gerritService,
+	}
+}
+
+// PutSignature adds or replaces the signature record - signatures are created by the python backend, this is used to seed the store
+func (repo *MemoryRepository) PutSignature(ctx context.Context, signature ItemSignature) error {
+	if signature.SignatureID == "" {
+		return errors.New("signature ID is required")
+	}
+	if signature.DateCreated == "" || signature.DateModified == "" {
+		_, now := utils.CurrentTime()
+		if signature.DateCreated == "" {
+			signature.DateCreated = now
+		}
+		if signature.DateModified == "" {
+			signature.DateModified = now
+		}
+	}
+	signature.SignatureReferenceNameLower = strings.ToLower(signature.SignatureReferenceName)
+
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	repo.signatures[signature.SignatureID] = copyItemSignature(signature)
+	return nil
+}
+
+// approvalListUpdater returns the approval list update helper backed by this repository
+func (repo *MemoryRepository) approvalListUpdater() approvalListUpdater {
+	return approvalListUpdater{
+		sigRepo:          repo,
+		columnStore:      repo,
+		companyRepo:      repo.companyRepo,
+		usersRepo:        repo.usersRepo,
+		eventsService:    repo.eventsService,
+		repositoriesRepo: repo.repositoriesRepo,
+		ghOrgRepo:        repo.ghOrgRepo,
+		gerritService:    repo.gerritService,
+	}
+}
+
+// GetGithubOrganizationsFromWhitelist returns a list of GH organizations stored in the whitelist
+func (repo *MemoryRepository) GetGithubOrganizationsFromWhitelist(ctx context.Context, signatureID string) ([]models.GithubOrg, error) {
+	repo.lock.RLock()
+	item, ok := repo.signatures[signatureID]
+	repo.lock.RUnlock()
+	if !ok || item.GitHubOrgWhitelist == nil {
+		return nil, nil
+	}
+
+	orgs := buildGithubOrgs(item.GitHubOrgWhitelist)
+	sort.Slice(orgs, func(i, j int) bool {
+		return *orgs[i].ID < *orgs[j].ID
+	})
+	return orgs, nil
+}
+
+// AddGithubOrganizationToWhitelist adds the specified GH organization to the whitelist
+func (repo *MemoryRepository) AddGithubOrganizationToWhitelist(ctx context.Context, signatureID, githubOrganizationID string) ([]models.GithubOrg, error) {
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	item, ok := repo.signatures[signatureID]
+	if !ok {
+		return nil, fmt.Errorf("signature ID: %s not found", signatureID)
+	}
+
+	if !utils.StringInSlice(githubOrganizationID, item.GitHubOrgWhitelist) {
+		item.GitHubOrgWhitelist = append(item.GitHubOrgWhitelist, githubOrganizationID)
+		repo.signatures[signatureID] = item
+	}
+
+	return buildGithubOrgs(item.GitHubOrgWhitelist), nil
+}
+
+// DeleteGithubOrganizationFromWhitelist removes the specified GH organization from the whitelist
+func (repo *MemoryRepository) DeleteGithubOrganizationFromWhitelist(ctx context.Context, signatureID, githubOrganizationID string) ([]models.GithubOrg, error) {
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	item, ok := repo.signatures[signatureID]
+	if !ok || item.GitHubOrgWhitelist == nil {
+		return nil, errors.New("no github_org_whitelist column")
+	}
+
+	item.GitHubOrgWhitelist = utils.RemoveItemsFromList(item.GitHubOrgWhitelist, []string{githubOrganizationID})
+	repo.signatures[signatureID] = item
+
+	if len(item.GitHubOrgWhitelist) == 0 {
+		return []models.GithubOrg{}, nil
+	}
+	return buildGithubOrgs(item.GitHubOrgWhitelist), nil
+}
+
+// InvalidateProjectRecord invalidates the specified project record by setting the signature_approved flag to false
+func (repo *MemoryRepository) InvalidateProjectRecord(ctx context.Context, signatureID, note string) error {
+	return repo.update(signatureID, func(item *ItemSignature) {
+		item.SignatureApproved = false
+		item.Note = note
+	})
+}
+
+// GetSignature returns the signature for the specified signature id
+func (repo *MemoryRepository) GetSignature(ctx context.Context, signatureID string) (*models.Signature, error) {
+	repo.lock.RLock()
+	item, ok := repo.signatures[signatureID]
+	repo.lock.RUnlock()
+	if !ok {
+		return nil, nil
+	}
+
+	return buildSignatureModels(ctx, repo.usersRepo, repo.companyRepo, []ItemSignature{item}, LoadACLDetails)[0], nil
+}
+
+// GetIndividualSignature returns the signature record for the specified CLA Group and User
+func (repo *MemoryRepository) GetIndividualSignature(ctx context.Context, claGroupID, userID string, approved, signed *bool) (*models.Signature, error) {
+	items := repo.query(func(item *ItemSignature) bool {
+		return item.SignatureProjectID == claGroupID && item.SignatureReferenceID == userID &&
+			isIndividualSignature(item) && matchesApprovedSigned(item, approved, signed)
+	}, byDateCreated)
+	if len(items) == 0 {
+		return nil, nil
+	}
+	if len(items) > 1 {
+		log.WithFields(logrus.Fields{
+			"functionName": "v1.signatures.repository_memory.GetIndividualSignature",
+			"claGroupID":   claGroupID,
+			"userID":       userID,
+		}).Warnf("found multiple matching ICLA signatures - found %d total", len(items))
+	}
+
+	return buildSignatureModels(ctx, repo.usersRepo, repo.companyRepo, items[:1], LoadACLDetails)[0], nil
+}
+
+// GetCorporateSignature returns the signature record for the specified CLA Group and Company ID
+func (repo *MemoryRepository) GetCorporateSignature(ctx context.Context, claGroupID, companyID string, approved, signed *bool) (*models.Signature, error) {
+	items := repo.query(func(item *ItemSignature) bool {
+		return item.SignatureProjectID == claGroupID && item.SignatureReferenceID == companyID &&
+			isCorporateSignature(item) && matchesApprovedSigned(item, approved, signed)
+	}, byDateCreated)
+	if len(items) == 0 {
+		return nil, nil
+	}
+	if len(items) > 1 {
+		log.WithFields(logrus.Fields{
+			"functionName": "v1.signatures.repository_memory.GetCorporateSignature",
+			"claGroupID":   claGroupID,
+			"companyID":    companyID,
+		}).Warnf("found multiple matching CCLA signatures - found %d total", len(items))
+	}
+
+	return buildSignatureModels(ctx, repo.usersRepo, repo.companyRepo, items[:1], LoadACLDetails)[0], nil
+}
+
+// GetEmployeeSignature returns the signed and approved employee acknowledgement (ECLA) of the user for the CCLA of the
+// specified CLA Group and Company ID
+func (repo *MemoryRepository) GetEmployeeSignature(ctx context.Context, claGroupID, companyID, userID string) (*models.Signature, error) {
+	items := repo.query(func(item *ItemSignature) bool {
+		return item.SignatureProjectID == claGroupID && item.SignatureReferenceID == userID && isEmployeeSignature(item) &&
+			item.SignatureUserCompanyID == companyID && item.SignatureApproved && item.SignatureSigned
+	}, byDateCreated)
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	return buildSignatureModels(ctx, repo.usersRepo, repo.companyRepo, items[:1], LoadACLDetails)[0], nil
+}
+
+// GetSignatureACL returns the signature ACL for the specified signature id
+func (repo *MemoryRepository) GetSignatureACL(ctx context.Context, signatureID string) ([]string, error) {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	item, ok := repo.signatures[signatureID]
+	if !ok {
+		return nil, nil
+	}
+	return append([]string(nil), item.SignatureACL...), nil
+}
+
+// GetProjectSignatures returns a list of signatures for the specified project
+func (repo *MemoryRepository) GetProjectSignatures(ctx context.Context, params signatures.GetProjectSignaturesParams) (*models.Signatures, error) {
+	claType := ""
+	if params.ClaType != nil || params.SignatureType != nil {
+		claType = getCLATypeFromParams(params)
+	}
+	searchTerm := strings.ToLower(utils.StringValue(params.SearchTerm))
+
+	items := repo.query(func(item *ItemSignature) bool {
+		if item.SignatureProjectID != params.ProjectID || !matchesCLAType(item, claType) {
+			return false
+		}
+		if searchTerm != "" && !strings.Contains(item.SignatureReferenceNameLower, searchTerm) && !strings.Contains(item.UserEmail, searchTerm) {
+			return false
+		}
+		return matchesApprovedSigned(item, params.Approved, params.Signed)
+	}, byDateModified)
+
+	pageSize := int64(1000)
+	if params.PageSize != nil && *params.PageSize > 0 {
+		pageSize = *params.PageSize
+	}
+	page, lastKey := pageItems(items, utils.StringValue(params.NextKey), pageSize)
+
+	return &models.Signatures{
+		ProjectID:      params.ProjectID,
+		ResultCount:    int64(len(page)),
+		TotalCount:     repo.count(),
+		LastKeyScanned: lastKey,
+		Signatures:     buildSignatureModels(ctx, repo.usersRepo, repo.companyRepo, page, LoadACLDetails),
+	}, nil
+}
+
+// CreateProjectSummaryReport generates a project summary report based on the specified input
+func (repo *MemoryRepository) CreateProjectSummaryReport(ctx context.Context, params signatures.CreateProjectSummaryReportParams) (*models.SignatureReport, error) {
+	claType := strings.ToLower(utils.StringValue(params.ClaType))
+	searchTerm := strings.ToLower(utils.StringValue(params.SearchTerm))
+
+	items := repo.query(func(item *ItemSignature) bool {
+		if item.SignatureProjectID != params.ProjectID {
+			return false
+		}
+		if params.ClaType != nil {
+			if !matchesCLAType(item, claType) {
+				return false
+			}
+		} else {
+			if params.SearchField != nil && item.SignatureReferenceType != *params.SearchField {
+				return false
+			}
+			if params.SignatureType != nil {
+				if item.SignatureType != strings.ToLower(*params.SignatureType) {
+					return false
+				}
+				if *params.SignatureType == utils.ClaTypeCCLA && (item.SignatureReferenceID == "" || item.SignatureUserCompanyID != "") {
+					return false
+				}
+			}
+			if searchTerm != "" {
+				if utils.BoolValue(params.FullMatch) {
+					if item.SignatureReferenceNameLower != searchTerm {
+						return false
+					}
+				} else if !strings.Contains(item.SignatureReferenceNameLower, searchTerm) && !strings.Contains(item.UserEmail, searchTerm) {
+					return false
+				}
+			}
+		}
+		if len(params.Body) > 0 && !utils.StringInSlice(item.SignatureReferenceID, params.Body) {
+			return false
+		}
+		return matchesApprovedSigned(item, params.Approved, params.Signed)
+	}, byDateModified)
+
+	pageSize := int64(100)
+	if params.PageSize != nil && *params.PageSize > 0 {
+		pageSize = *params.PageSize
+	}
+	page, lastKey := pageItems(items, utils.StringValue(params.NextKey), pageSize)
+
+	return &models.SignatureReport{
+		ProjectID:      params.ProjectID,
+		ResultCount:    int64(len(page)),
+		TotalCount:     repo.count(),
+		LastKeyScanned: lastKey,
+		Signatures:     buildSignatureSummaryModels(ctx, repo.companyRepo, page),
+	}, nil
+}
+
+// GetProjectCompanySignature returns a the signature for the specified project and specified company with the other query flags
+func (repo *MemoryRepository) GetProjectCompanySignature(ctx context.Context, companyID, projectID string, approved, signed *bool, nextKey *string, pageSize *int64) (*models.Signature, error) {
+	sortOrder := utils.SortOrderAscending
+	sigs, err := repo.GetProjectCompanySignatures(ctx, companyID, projectID, approved, signed, nextKey, &sortOrder, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	if sigs == nil || len(sigs.Signatures) == 0 {
+		return nil, nil
+	}
+
+	return sigs.Signatures[0], nil
+}
+
+// GetProjectCompanySignatures returns a list of signatures for the specified project and specified company
+func (repo *MemoryRepository) GetProjectCompanySignatures(ctx context.Context, companyID, projectID string, approved, signed *bool, nextKey *string, sortOrder *string, pageSize *int64) (*models.Signatures, error) {
+	items := repo.query(func(item *ItemSignature) bool {
+		return item.SignatureProjectID == projectID && item.SignatureReferenceID == companyID &&
+			isCorporateSignature(item) && matchesApprovedSigned(item, approved, signed)
+	}, byDateCreated)
+	if utils.StringValue(sortOrder) == utils.SortOrderDescending {
+		reverseItems(items)
+	}
+
+	limit := int64(10)
+	if pageSize != nil {
+		limit = *pageSize
+	}
+	page, lastKey := pageItems(items, utils.StringValue(nextKey), limit)
+
+	return &models.Signatures{
+		ProjectID:      projectID,
+		ResultCount:    int64(len(page)),
+		TotalCount:     repo.count(),
+		LastKeyScanned: lastKey,
+		Signatures:     buildSignatureModels(ctx, repo.usersRepo, repo.companyRepo, page, LoadACLDetails),
+	}, nil
+}
+
+// GetProjectCompanyEmployeeSignatures returns a list of employee signatures for the specified project and specified company
+func (repo *MemoryRepository) GetProjectCompanyEmployeeSignatures(ctx context.Context, params signatures.GetProjectCompanyEmployeeSignaturesParams, criteria *ApprovalCriteria, pageSize int64) (*models.Signatures, error) {
+	items := repo.query(func(item *ItemSignature) bool {
+		if item.SignatureUserCompanyID != params.CompanyID || item.SignatureProjectID != params.ProjectID {
+			return false
+		}
+		if !item.SignatureApproved || !item.SignatureSigned {
+			return false
+		}
+		return criteria.matches(item)
+	}, byDateCreated)
+	page, lastKey := pageItems(items, utils.StringValue(params.NextKey), pageSize)
+
+	return &models.Signatures{
+		ProjectID:      params.ProjectID,
+		ResultCount:    int64(len(page)),
+		TotalCount:     repo.count(),
+		LastKeyScanned: lastKey,
+		Signatures:     buildSignatureModels(ctx, repo.usersRepo, repo.companyRepo, page, LoadACLDetails),
+	}, nil
+}
+
+// GetCompanySignatures returns a list of company signatures for the specified company
+func (repo *MemoryRepository) GetCompanySignatures(ctx context.Context, params signatures.GetCompanySignaturesParams, pageSize int64, loadACL bool) (*models.Signatures, error) {
+	items := repo.query(func(item *ItemSignature) bool {
+		if item.SignatureReferenceID != params.CompanyID || !item.SignatureApproved || !item.SignatureSigned {
+			return false
+		}
+		return params.SignatureType == nil || item.SignatureType == *params.SignatureType
+	}, byDateCreated)
+	page, lastKey := pageItems(items, utils.StringValue(params.NextKey), pageSize)
+
+	return &models.Signatures{
+		ResultCount:    int64(len(page)),
+		TotalCount:     repo.count(),
+		LastKeyScanned: lastKey,
+		Signatures:     buildSignatureModels(ctx, repo.usersRepo, repo.companyRepo, page, loadACL),
+	}, nil
+}
+
+// GetCompanyIDsWithSignedCorporateSignatures returns a list of company IDs that have signed a CLA agreement
+func (repo *MemoryRepository) GetCompanyIDsWithSignedCorporateSignatures(ctx context.Context, claGroupID string) ([]SignatureCompanyID, error) {
+	items := repo.query(func(item *ItemSignature) bool {
+		return item.SignatureProjectID == claGroupID && isCorporateSignature(item) && item.SignatureApproved && item.SignatureSigned
+	}, byDateCreated)
+
+	return buildCompanyIDs(ctx, repo.companyRepo, items), nil
+}
+
+// GetUserSignatures returns a list of user signatures for the specified user
+func (repo *MemoryRepository) GetUserSignatures(ctx context.Context, params signatures.GetUserSignaturesParams, pageSize int64) (*models.Signatures, error) {
+	items := repo.query(func(item *ItemSignature) bool {
+		return item.SignatureReferenceID == params.UserID
+	}, byDateCreated)
+	page, lastKey := pageItems(items, utils.StringValue(params.NextKey), pageSize)
+
+	return &models.Signatures{
+		ResultCount:    int64(len(page)),
+		TotalCount:     repo.count(),
+		LastKeyScanned: lastKey,
+		Signatures:     buildSignatureModels(ctx, repo.usersRepo, repo.companyRepo, page, LoadACLDetails),
+	}, nil
+}
+
+// ProjectSignatures - get project signatures with no pagination
+func (repo *MemoryRepository) ProjectSignatures(ctx context.Context, projectID string) (*models.Signatures, error) {
+	items := repo.query(func(item *ItemSignature) bool {
+		return item.SignatureProjectID == projectID && item.SignatureApproved && item.SignatureSigned
+	}, byDateCreated)
+
+	return &models.Signatures{
+		ProjectID:  projectID,
+		Signatures: buildSignatureModels(ctx, repo.usersRepo, repo.companyRepo, items, LoadACLDetails),
+	}, nil
+}
+
+// UpdateApprovalList updates the specified project/company signature with the updated approval list information
+func (repo *MemoryRepository) UpdateApprovalList(ctx context.Context, claManager *models.User, claGroupModel *models.ClaGroup, companyID string, params *models.ApprovalList, eventArgs *events.LogEventArgs) (*models.Signature, error) {
+	return repo.approvalListUpdater().UpdateApprovalList(ctx, claManager, claGroupModel, companyID, params, eventArgs)
+}
+
+// AddCLAManager adds the specified manager to the signature ACL
+func (repo *MemoryRepository) AddCLAManager(ctx context.Context, signatureID, claManagerID string) (*models.Signature, error) {
+	repo.lock.Lock()
+	item, ok := repo.signatures[signatureID]
+	if !ok || item.SignatureACL == nil {
+		repo.lock.Unlock()
+		return nil, nil
+	}
+	if utils.StringInSlice(claManagerID, item.SignatureACL) {
+		repo.lock.Unlock()
+		return nil, errors.New("manager already in signature ACL")
+	}
+	item.SignatureACL = append(item.SignatureACL, claManagerID)
+	_, item.DateModified = utils.CurrentTime()
+	repo.signatures[signatureID] = item
+	repo.lock.Unlock()
+
+	return repo.GetSignature(ctx, signatureID)
+}
+
+// RemoveCLAManager removes the specified manager from the signature ACL
+func (repo *MemoryRepository) RemoveCLAManager(ctx context.Context, signatureID, claManagerID string) (*models.Signature, error) {
+	repo.lock.Lock()
+	item, ok := repo.signatures[signatureID]
+	if !ok || item.SignatureACL == nil {
+		repo.lock.Unlock()
+		return nil, nil
+	}
+	if !utils.StringInSlice(claManagerID, item.SignatureACL) {
+		repo.lock.Unlock()
+		return nil, fmt.Errorf("manager ID: %s not found in signature ACL", claManagerID)
+	}
+	item.SignatureACL = utils.RemoveItemsFromList(item.SignatureACL, []string{claManagerID})
+	_, item.DateModified = utils.CurrentTime()
+	repo.signatures[signatureID] = item
+	repo.lock.Unlock()
+
+	return repo.GetSignature(ctx, signatureID)
+}
+
+// removeColumn is a helper function to remove a given approval list column
+func (repo *MemoryRepository) removeColumn(ctx context.Context, signatureID, columnName string) (*models.Signature, error) {
+	var columnErr error
+	err := repo.update(signatureID, func(item *ItemSignature) {
+		columnErr = setApprovalListColumn(item, columnName, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if columnErr != nil {
+		return nil, columnErr
+	}
+
+	return repo.GetSignature(ctx, signatureID)
+}
+
+// updateApprovalListColumns sets the specified approval list columns on the signature record
+func (repo *MemoryRepository) updateApprovalListColumns(ctx context.Context, signatureID string, columns map[string][]string) error {
+	var columnErr error
+	err := repo.update(signatureID, func(item *ItemSignature) {
+		for columnName, values := range columns {
+			if setErr := setApprovalListColumn(item, columnName, values); setErr != nil {
+				columnErr = setErr
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	return columnErr
+}
+
+// GetSignaturesWithApprovalListExpirations returns the signed and approved CCLA signatures with approval list entries
+// expiring before the specified time
+func (repo *MemoryRepository) GetSignaturesWithApprovalListExpirations(ctx context.Context, expiringBefore time.Time) ([]*models.Signature, error) {
+	before := expiringBefore.UTC().Format(time.RFC3339)
+	items := repo.query(func(item *ItemSignature) bool {
+		next := nextApprovalListExpiry(item.ApprovalListExpirations)
+		return isCorporateSignature(item) && item.SignatureApproved && item.SignatureSigned && next != "" && next <= before
+	}, byDateCreated)
+
+	return buildSignatureModels(ctx, repo.usersRepo, repo.companyRepo, items, LoadACLDetails), nil
+}
+
+// UpdateApprovalListExpirations sets the approval list expiry columns on the signature record
+func (repo *MemoryRepository) UpdateApprovalListExpirations(ctx context.Context, signatureID string, expirations map[string]string, warningsSent []string) error {
+	return repo.update(signatureID, func(item *ItemSignature) {
+		item.ApprovalListExpirations = nil
+		if len(expirations) > 0 {
+			item.ApprovalListExpirations = map[string]string{}
+			for key, value := range expirations {
+				item.ApprovalListExpirations[key] = value
+			}
+		}
+		item.ApprovalListExpiryWarningsSent = nil
+		if len(warningsSent) > 0 {
+			item.ApprovalListExpiryWarningsSent = copyStrings(warningsSent)
+		}
+	})
+}
+
+// GetSignatureDocumentsForVerification returns up to limit signed ICLA and CCLA documents which were never verified
+// or verified before the specified RFC3339 time, the least recently verified first
+func (repo *MemoryRepository) GetSignatureDocumentsForVerification(ctx context.Context, verifiedBefore string, limit int) ([]*SignatureDocument, error) {
+	items := repo.query(func(item *ItemSignature) bool {
+		return item.SignatureSigned && (isCorporateSignature(item) || isIndividualSignature(item)) &&
+			(item.SignatureDocumentVerifiedOn == "" || item.SignatureDocumentVerifiedOn < verifiedBefore)
+	}, func(a, b *ItemSignature) bool {
+		if a.SignatureDocumentVerifiedOn == b.SignatureDocumentVerifiedOn {
+			return a.SignatureID < b.SignatureID
+		}
+		return a.SignatureDocumentVerifiedOn < b.SignatureDocumentVerifiedOn
+	})
+
+	var docs []*SignatureDocument
+	for i := 0; i < len(items) && i < limit; i++ {
+		docs = append(docs, signatureDocumentFromItem(&items[i]))
+	}
+	return docs, nil
+}
+
+// UpdateSignatureDocumentIntegrity records the outcome of the signed document integrity check
+func (repo *MemoryRepository) UpdateSignatureDocumentIntegrity(ctx context.Context, signatureID, documentSHA256, status, verifiedOn string) error {
+	return repo.update(signatureID, func(item *ItemSignature) {
+		if documentSHA256 != "" {
+			item.SignatureDocumentSHA256 = documentSHA256
+		}
+		item.SignatureDocumentIntegrityStatus = status
+		item.SignatureDocumentVerifiedOn = verifiedOn
+	})
+}
+
+// GetCLAGroupSignedSignatures returns all the signed and approved ICLA or CCLA signatures of the CLA Group
+func (repo *MemoryRepository) GetCLAGroupSignedSignatures(ctx context.Context, claGroupID, claType string) ([]*models.Signature, error) {
+	if claType != utils.ClaTypeICLA && claType != utils.ClaTypeCCLA {
+		return nil, fmt.Errorf("not supported cla type: %s", claType)
+	}
+	items := repo.query(func(item *ItemSignature) bool {
+		return item.SignatureProjectID == claGroupID && matchesCLAType(item, claType) && item.SignatureApproved && item.SignatureSigned
+	}, byDateCreated)
+
+	return buildSignatureModels(ctx, repo.usersRepo, repo.companyRepo, items, LoadACLDetails), nil
+}
+
+// AddSigTypeSignedApprovedID sets the sigtype_signed_approved_id value on the signature
+func (repo *MemoryRepository) AddSigTypeSignedApprovedID(ctx context.Context, signatureID string, val string) error {
+	return repo.update(signatureID, func(item *ItemSignature) {
+		item.SigtypeSignedApprovedID = val
+	})
+}
+
+// AddUsersDetails copies the user's details onto the signature
+func (repo *MemoryRepository) AddUsersDetails(ctx context.Context, signatureID string, userID string) error {
+	userModel, err := repo.usersRepo.GetUser(userID)
+	if err != nil {
+		return err
+	}
+	if userModel == nil {
+		return fmt.Errorf("invalid user id : %s for signature : %s", userID, signatureID)
+	}
+
+	email := userModel.LfEmail
+	if email == "" && len(userModel.Emails) > 0 {
+		email = userModel.Emails[0]
+	}
+
+	return repo.update(signatureID, func(item *ItemSignature) {
+		if userModel.GithubUsername != "" {
+			item.UserGithubUsername = userModel.GithubUsername
+		}
+		if userModel.LfUsername != "" {
+			item.UserLFUsername = userModel.LfUsername
+		}
+		if userModel.Username != "" {
+			item.UserName = userModel.Username
+		}
+		if email != "" {
+			item.UserEmail = email
+		}
+	})
+}
+
+// AddSignedOn sets the signed on date to the current time
+func (repo *MemoryRepository) AddSignedOn(ctx context.Context, signatureID string) error {
+	return repo.update(signatureID, func(item *ItemSignature) {
+		_, item.SignedOn = utils.CurrentTime()
+	})
+}
+
+// GetClaGroupICLASignatures returns the list of ICLA signatures for the specified CLA Group
+func (repo *MemoryRepository) GetClaGroupICLASignatures(ctx context.Context, claGroupID string, searchTerm *string, approved, signed *bool, pageSize int64, nextKey string) (*models.IclaSignatures, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.signatures.repository_memory.GetClaGroupICLASignatures",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+		"searchTerm":     utils.StringValue(searchTerm),
+	}
+
+	searchTermValue := strings.ToLower(utils.StringValue(searchTerm))
+	items := repo.query(func(item *ItemSignature) bool {
+		if item.SignatureProjectID != claGroupID || !isIndividualSignature(item) || !matchesApprovedSigned(item, approved, signed) {
+			return false
+		}
+		return searchTermValue == "" || matchesContributorSearchTerm(item, searchTermValue)
+	}, bySignatureID)
+
+	if pageSize == 0 {
+		pageSize = DefaultPageSize
+	}
+	if pageSize > BigPageSize {
+		pageSize = BigPageSize
+	}
+	page, lastKey := pageItems(items, nextKey, pageSize)
+
+	iclaSignatures, err := addAdditionalICLAMetaData(f, repo.usersRepo, getIntermediateICLAResponse(f, page))
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.IclaSignatures{
+		LastKeyScanned: lastKey,
+		PageSize:       pageSize,
+		ResultCount:    int64(len(iclaSignatures)),
+		List:           iclaSignatures,
+	}, nil
+}
+
+// GetClaGroupCorporateContributors returns the list of employee contributors for the specified CLA Group and optional company
+func (repo *MemoryRepository) GetClaGroupCorporateContributors(ctx context.Context, claGroupID string, companyID *string, searchTerm *string) (*models.CorporateContributorList, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.signatures.repository_memory.GetClaGroupCorporateContributors",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+		"companyID":      utils.StringValue(companyID),
+	}
+
+	searchTermValue := strings.ToLower(utils.StringValue(searchTerm))
+	items := repo.query(func(item *ItemSignature) bool {
+		if item.SignatureProjectID != claGroupID || !isEmployeeSignature(item) || !item.SignatureSigned || !item.SignatureApproved {
+			return false
+		}
+		if companyID != nil && item.SignatureUserCompanyID != *companyID {
+			return false
+		}
+		return searchTermValue == "" || matchesContributorSearchTerm(item, searchTermValue)
+	}, bySignatureID)
+
+	out := &models.CorporateContributorList{List: make([]*models.CorporateContributor, 0, len(items))}
+	for _, item := range items {
+		out.List = append(out.List, buildCorporateContributor(f, item))
+	}
+	sort.Slice(out.List, func(i, j int) bool {
+		return out.List[i].Name < out.List[j].Name
+	})
+
+	return out, nil
+}
+
+// update applies the specified change to the signature record and bumps the modified date
+func (repo *MemoryRepository) update(signatureID string, change func(item *ItemSignature)) error {
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	item, ok := repo.signatures[signatureID]
+	if !ok {
+		return fmt.Errorf("signature ID: %s not found", signatureID)
+	}
+	change(&item)
+	_, item.DateModified = utils.CurrentTime()
+	repo.signatures[signatureID] = item
+	return nil
+}
+
+// query returns copies of the signature records which match the specified filter, sorted with the specified function
+func (repo *MemoryRepository) query(match func(item *ItemSignature) bool, less func(a, b *ItemSignature) bool) []ItemSignature {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	var items []ItemSignature
+	for _, item := range repo.signatures {
+		if match(&item) {
+			items = append(items, copyItemSignature(item))
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return less(&items[i], &items[j])
+	})
+	return items
+}
+
+// count returns the total number of signature records
+func (repo *MemoryRepository) count() int64 {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	return int64(len(repo.signatures))
+}
+
+// pageItems returns the page of items following the item with the next key signature ID, along with the last key of the page
+func pageItems(items []ItemSignature, nextKey string, pageSize int64) ([]ItemSignature, string) {
+	start := 0
+	if nextKey != "" {
+		for i := range items {
+			if items[i].SignatureID == nextKey {
+				start = i + 1
+				break
+			}
+		}
+	}
+	items = items[start:]
+	if pageSize <= 0 || int64(len(items)) <= pageSize {
+		return items, ""
+	}
+
+	return items[:pageSize], items[pageSize-1].SignatureID
+}
+
+func bySignatureID(a, b *ItemSignature) bool {
+	return a.SignatureID < b.SignatureID
+}
+
+func byDateCreated(a, b *ItemSignature) bool {
+	if a.DateCreated == b.DateCreated {
+		return a.SignatureID < b.SignatureID
+	}
+	return a.DateCreated < b.DateCreated
+}
+
+func byDateModified(a, b *ItemSignature) bool {
+	if a.DateModified == b.DateModified {
+		return a.SignatureID < b.SignatureID
+	}
+	return a.DateModified < b.DateModified
+}
+
+func reverseItems(items []ItemSignature) {
+	for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+		items[i], items[j] = items[j], items[i]
+	}
+}
+
+// isCorporateSignature returns true if the signature is a company CCLA signature
+func isCorporateSignature(item *ItemSignature) bool {
+	return item.SignatureType == utils.SignatureTypeCCLA && item.SignatureReferenceType == utils.SignatureReferenceTypeCompany && item.SignatureUserCompanyID == ""
+}
+
+// isIndividualSignature returns true if the signature is an ICLA signature
+func isIndividualSignature(item *ItemSignature) bool {
+	return item.SignatureType == utils.SignatureTypeCLA && item.SignatureReferenceType == utils.SignatureReferenceTypeUser && item.SignatureUserCompanyID == ""
+}
+
+// isEmployeeSignature returns true if the signature is an employee acknowledgement (ECLA) signature
+func isEmployeeSignature(item *ItemSignature) bool {
+	return item.SignatureType == utils.SignatureTypeCLA && item.SignatureReferenceType == utils.SignatureReferenceTypeUser && item.SignatureUserCompanyID != ""
+}
+
+// matchesCLAType returns true if the signature matches the specified CLA type - an empty CLA type matches everything
+func matchesCLAType(item *ItemSignature, claType string) bool {
+	switch claType {
+	case utils.ClaTypeICLA:
+		return isIndividualSignature(item)
+	case utils.ClaTypeECLA:
+		return isEmployeeSignature(item)
+	case utils.ClaTypeCCLA:
+		return isCorporateSignature(item)
+	}
+	return true
+}
+
+// matchesApprovedSigned applies the approved and signed query flags, falling back to the configured signature query default
+func matchesApprovedSigned(item *ItemSignature, approved, signed *bool) bool {
+	if approved != nil && item.SignatureApproved != *approved {
+		return false
+	}
+	if signed != nil && item.SignatureSigned != *signed {
+		return false
+	}
+	if approved == nil && signed == nil && config.GetConfig().SignatureQueryDefault == utils.SignatureQueryDefaultActive {
+		return item.SignatureApproved && item.SignatureSigned
+	}
+	return true
+}
+
+// matchesContributorSearchTerm returns true if any of the contributor name, email, github username or docusign name contains the search term
+func matchesContributorSearchTerm(item *ItemSignature, searchTerm string) bool {
+	return strings.Contains(item.SignatureReferenceNameLower, searchTerm) ||
+		strings.Contains(strings.ToLower(item.UserEmail), searchTerm) ||
+		strings.Contains(strings.ToLower(item.UserGithubUsername), searchTerm) ||
+		strings.Contains(strings.ToLower(item.UserDocusignName), searchTerm)
+}
+
+// setApprovalListColumn sets the approval list column value on the signature record
+func setApprovalListColumn(item *ItemSignature, columnName string, values []string) error {
+	switch columnName {
+	case EmailApprovalListColumn:
+		item.EmailWhitelist = values
+	case DomainApprovalListColumn:
+		item.DomainWhitelist = values
+	case GitHubUsernameApprovalListColumn:
+		item.GitHubWhitelist = values
+	case GitHubOrgApprovalListColumn:
+		item.GitHubOrgWhitelist = values
+	default:
+		return fmt.Errorf("unsupported approval list column: %s", columnName)
+	}
+	return nil
+}
+
+// buildGithubOrgs converts the list of GitHub organization IDs into the response model
+func buildGithubOrgs(orgIDs []string) []models.GithubOrg {
+	var orgs []models.GithubOrg
+	for _, orgID := range orgIDs {
+		id := orgID
+		selected := true
+		orgs = append(orgs, models.GithubOrg{
+			ID:       &id,
+			Selected: &selected,
+		})
+	}
+	return orgs
+}
+
+// copyItemSignature returns a copy of the signature record which does not share any slices with the original
+func copyItemSignature(item ItemSignature) ItemSignature {
+	item.EmailWhitelist = copyStrings(item.EmailWhitelist)
+	item.DomainWhitelist = copyStrings(item.DomainWhitelist)
+	item.GitHubWhitelist = copyStrings(item.GitHubWhitelist)
+	item.GitHubOrgWhitelist = copyStrings(item.GitHubOrgWhitelist)
+	item.SignatureACL = copyStrings(item.SignatureACL)
+	item.ApprovalListExpiryWarningsSent = copyStrings(item.ApprovalListExpiryWarningsSent)
+	if item.ApprovalListExpirations != nil {
+		expirations := make(map[string]string, len(item.ApprovalListExpirations))
+		for key, value := range item.ApprovalListExpirations {
+			expirations[key] = value
+		}
+		item.ApprovalListExpirations = expirations
+	}
+	return item
+}
+
+func copyStrings(in []string) []string {
+	if in == nil {
+		return nil
+	}
+	return append([]string(nil), in...)
+}
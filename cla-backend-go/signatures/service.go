@@ -49,6 +49,8 @@ type SignatureService interface {
 	AddGithubOrganizationToWhitelist(ctx context.Context, signatureID string, whiteListParams models.GhOrgWhitelist, githubAccessToken string) ([]models.GithubOrg, error)
 	DeleteGithubOrganizationFromWhitelist(ctx context.Context, signatureID string, whiteListParams models.GhOrgWhitelist, githubAccessToken string) ([]models.GithubOrg, error)
 	UpdateApprovalList(ctx context.Context, authUser *auth.User, claGroupModel *models.ClaGroup, companyModel *models.Company, claGroupID string, params *models.ApprovalList) (*models.Signature, error)
+	ExplainApproval(ctx context.Context, claGroupID, companyID string, candidate ApprovalCandidate) (*ApprovalMatch, error)
+	CheckUserCLA(ctx context.Context, claGroupID string, userModel *models.User, candidate ApprovalCandidate) (*ApprovalMatch, error)
 
 	AddCLAManager(ctx context.Context, signatureID, claManagerID string) (*models.Signature, error)
 	RemoveCLAManager(ctx context.Context, ignatureID, claManagerID string) (*models.Signature, error)
@@ -92,6 +94,71 @@ func (s service) GetCorporateSignature(ctx context.Context, claGroupID, companyI
 	return s.repo.GetCorporateSignature(ctx, claGroupID, companyID, approved, signed)
 }
 
+// ExplainApproval evaluates the user against the approval list of the company CCLA signature and explains why the
+// user is approved, denied or not matched
+func (s service) ExplainApproval(ctx context.Context, claGroupID, companyID string, candidate ApprovalCandidate) (*ApprovalMatch, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.signatures.service.ExplainApproval",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+		"companyID":      companyID,
+	}
+
+	approved, signed := true, true
+	cclaSignature, err := s.repo.GetCorporateSignature(ctx, claGroupID, companyID, &approved, &signed)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the corporate signature")
+		return nil, err
+	}
+	if cclaSignature == nil {
+		return &ApprovalMatch{
+			Reason: fmt.Sprintf("no signed and approved corporate signature for CLA Group: %s and company: %s", claGroupID, companyID),
+		}, nil
+	}
+
+	return NewApprovalRulesFromSignature(ctx, cclaSignature).Explain(candidate), nil
+}
+
+// CheckUserCLA determines whether the user is covered by a CLA of the CLA Group - the user signed the ICLA, or
+// acknowledged the CCLA of their company (ECLA) and is approved by its approval list. The approval list alone doesn't
+// cover a user who never acknowledged the CCLA.
+func (s service) CheckUserCLA(ctx context.Context, claGroupID string, userModel *models.User, candidate ApprovalCandidate) (*ApprovalMatch, error) {
+	f := logrus.Fields{
+		"functionName":   "v1.signatures.service.CheckUserCLA",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+		"userID":         userModel.UserID,
+		"companyID":      userModel.CompanyID,
+	}
+
+	approved, signed := true, true
+	iclaSignature, err := s.repo.GetIndividualSignature(ctx, claGroupID, userModel.UserID, &approved, &signed)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the individual signature")
+		return nil, err
+	}
+	if iclaSignature != nil {
+		return &ApprovalMatch{Approved: true, Reason: "signed and approved ICLA"}, nil
+	}
+
+	if userModel.CompanyID == "" {
+		return &ApprovalMatch{Reason: "no ICLA and no company"}, nil
+	}
+
+	eclaSignature, err := s.repo.GetEmployeeSignature(ctx, claGroupID, userModel.CompanyID, userModel.UserID)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the employee signature")
+		return nil, err
+	}
+	if eclaSignature == nil {
+		return &ApprovalMatch{
+			Reason: fmt.Sprintf("no signed and approved employee acknowledgement for CLA Group: %s and company: %s", claGroupID, userModel.CompanyID),
+		}, nil
+	}
+
+	return s.ExplainApproval(ctx, claGroupID, userModel.CompanyID, candidate)
+}
+
 // GetProjectSignatures returns the list of signatures associated with the specified project
 func (s service) GetProjectSignatures(ctx context.Context, params signatures.GetProjectSignaturesParams) (*models.Signatures, error) {
 
@@ -442,12 +509,12 @@ func (s service) UpdateApprovalList(ctx context.Context, authUser *auth.User, cl
 	}
 
 	// Log Events
-	s.createEventLogEntries(ctx, companyModel, claGroupModel, userModel, params)
+	createEventLogEntries(ctx, s.eventsService, companyModel, claGroupModel, userModel, params)
 
 	// Send an email to the CLA Managers
 	for _, claManager := range claManagers {
 		claManagerEmail := getBestEmail(&claManager) // nolint
-		s.sendApprovalListUpdateEmailToCLAManagers(companyModel, claGroupModel, claManager.Username, claManagerEmail, params)
+		sendApprovalListUpdateEmailToCLAManagers(companyModel, claGroupModel, claManager.Username, claManagerEmail, params)
 	}
 
 	// Send emails to contributors if email or GH username as added/removed
@@ -532,7 +599,7 @@ func buildApprovalListSummary(approvalListChanges *models.ApprovalList) string {
 }
 
 // sendRequestAccessEmailToCLAManagers sends the request access email to the specified CLA Managers
-func (s service) sendApprovalListUpdateEmailToCLAManagers(companyModel *models.Company, claGroupModel *models.ClaGroup, recipientName, recipientAddress string, approvalListChanges *models.ApprovalList) {
+func sendApprovalListUpdateEmailToCLAManagers(companyModel *models.Company, claGroupModel *models.ClaGroup, recipientName, recipientAddress string, approvalListChanges *models.ApprovalList) {
 	f := logrus.Fields{
 		"functionName":      "sendApprovalListUpdateEmailToCLAManagers",
 		"projectName":       claGroupModel.ProjectName,
@@ -650,10 +717,11 @@ func (s service) sendRequestAccessEmailToContributors(authUser *auth.User, compa
 	}
 }
 
-func (s service) createEventLogEntries(ctx context.Context, companyModel *models.Company, claGroupModel *models.ClaGroup, userModel *models.User, approvalList *models.ApprovalList) {
+// createEventLogEntries logs a ClaApprovalListUpdated event for each of the approval list changes
+func createEventLogEntries(ctx context.Context, eventsService events.Service, companyModel *models.Company, claGroupModel *models.ClaGroup, userModel *models.User, approvalList *models.ApprovalList) {
 	for _, value := range approvalList.AddEmailApprovalList {
 		// Send an event
-		s.eventsService.LogEventWithContext(ctx, &events.LogEventArgs{
+		eventsService.LogEventWithContext(ctx, &events.LogEventArgs{
 			EventType:     events.ClaApprovalListUpdated,
 			ProjectID:     claGroupModel.ProjectExternalID,
 			ClaGroupModel: claGroupModel,
@@ -670,7 +738,7 @@ func (s service) createEventLogEntries(ctx context.Context, companyModel *models
 	}
 	for _, value := range approvalList.RemoveEmailApprovalList {
 		// Send an event
-		s.eventsService.LogEventWithContext(ctx, &events.LogEventArgs{
+		eventsService.LogEventWithContext(ctx, &events.LogEventArgs{
 			EventType:     events.ClaApprovalListUpdated,
 			ProjectID:     claGroupModel.ProjectExternalID,
 			ClaGroupModel: claGroupModel,
@@ -687,7 +755,7 @@ func (s service) createEventLogEntries(ctx context.Context, companyModel *models
 	}
 	for _, value := range approvalList.AddDomainApprovalList {
 		// Send an event
-		s.eventsService.LogEventWithContext(ctx, &events.LogEventArgs{
+		eventsService.LogEventWithContext(ctx, &events.LogEventArgs{
 			EventType:     events.ClaApprovalListUpdated,
 			ProjectID:     claGroupModel.ProjectExternalID,
 			ClaGroupModel: claGroupModel,
@@ -704,7 +772,7 @@ func (s service) createEventLogEntries(ctx context.Context, companyModel *models
 	}
 	for _, value := range approvalList.RemoveDomainApprovalList {
 		// Send an event
-		s.eventsService.LogEventWithContext(ctx, &events.LogEventArgs{
+		eventsService.LogEventWithContext(ctx, &events.LogEventArgs{
 			EventType:     events.ClaApprovalListUpdated,
 			ProjectID:     claGroupModel.ProjectExternalID,
 			ClaGroupModel: claGroupModel,
@@ -721,7 +789,7 @@ func (s service) createEventLogEntries(ctx context.Context, companyModel *models
 	}
 	for _, value := range approvalList.AddGithubUsernameApprovalList {
 		// Send an event
-		s.eventsService.LogEventWithContext(ctx, &events.LogEventArgs{
+		eventsService.LogEventWithContext(ctx, &events.LogEventArgs{
 			EventType:     events.ClaApprovalListUpdated,
 			ProjectID:     claGroupModel.ProjectExternalID,
 			ClaGroupModel: claGroupModel,
@@ -738,7 +806,7 @@ func (s service) createEventLogEntries(ctx context.Context, companyModel *models
 	}
 	for _, value := range approvalList.RemoveGithubUsernameApprovalList {
 		// Send an event
-		s.eventsService.LogEventWithContext(ctx, &events.LogEventArgs{
+		eventsService.LogEventWithContext(ctx, &events.LogEventArgs{
 			EventType:     events.ClaApprovalListUpdated,
 			ProjectID:     claGroupModel.ProjectExternalID,
 			ClaGroupModel: claGroupModel,
@@ -755,7 +823,7 @@ func (s service) createEventLogEntries(ctx context.Context, companyModel *models
 	}
 	for _, value := range approvalList.AddGithubOrgApprovalList {
 		// Send an event
-		s.eventsService.LogEventWithContext(ctx, &events.LogEventArgs{
+		eventsService.LogEventWithContext(ctx, &events.LogEventArgs{
 			EventType:     events.ClaApprovalListUpdated,
 			ProjectID:     claGroupModel.ProjectExternalID,
 			ClaGroupModel: claGroupModel,
@@ -772,7 +840,7 @@ func (s service) createEventLogEntries(ctx context.Context, companyModel *models
 	}
 	for _, value := range approvalList.RemoveGithubOrgApprovalList {
 		// Send an event
-		s.eventsService.LogEventWithContext(ctx, &events.LogEventArgs{
+		eventsService.LogEventWithContext(ctx, &events.LogEventArgs{
 			EventType:     events.ClaApprovalListUpdated,
 			CLAGroupID:    claGroupModel.ProjectID,
 			ProjectID:     claGroupModel.ProjectExternalID,
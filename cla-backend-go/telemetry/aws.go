@@ -0,0 +1,112 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package telemetry
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// multipleTables is the table label of the batch and transaction calls
+const multipleTables = "multiple"
+
+// names of the AWS request handlers
+const (
+	dynamoDBMetricsHandler = "easycla.telemetry.DynamoDBMetrics"
+	startSpanHandler       = "easycla.telemetry.StartSpan"
+	endSpanHandler         = "easycla.telemetry.EndSpan"
+)
+
+// awsSpanKey is the context key of the span started for an AWS request
+type awsSpanKey struct{}
+
+// InstrumentAWSSession records the DynamoDB calls made by the clients created from the session and traces all the
+// AWS calls - the spans are children of the span of the request context (the *WithContext calls)
+func InstrumentAWSSession(awsSession *session.Session) {
+	awsSession.Handlers.Validate.RemoveByName(startSpanHandler)
+	awsSession.Handlers.Validate.PushFrontNamed(request.NamedHandler{
+		Name: startSpanHandler,
+		Fn:   startAWSSpan,
+	})
+	awsSession.Handlers.Complete.RemoveByName(dynamoDBMetricsHandler)
+	awsSession.Handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: dynamoDBMetricsHandler,
+		Fn:   observeAWSRequest,
+	})
+	awsSession.Handlers.Complete.RemoveByName(endSpanHandler)
+	awsSession.Handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: endSpanHandler,
+		Fn:   endAWSSpan,
+	})
+}
+
+// startAWSSpan starts the client span of the AWS request
+func startAWSSpan(r *request.Request) {
+	if r.Operation == nil {
+		return
+	}
+	attributes := []attribute.KeyValue{
+		attribute.String("rpc.system", "aws-api"),
+		attribute.String("rpc.service", r.ClientInfo.ServiceName),
+		attribute.String("rpc.method", r.Operation.Name),
+	}
+	if r.ClientInfo.ServiceName == dynamodb.ServiceName {
+		attributes = append(attributes,
+			attribute.String("db.system", "dynamodb"),
+			attribute.String("aws.dynamodb.table_names", tableName(r.Params)))
+	}
+	ctx, span := otel.Tracer(tracerName).Start(r.Context(), r.ClientInfo.ServiceName+"."+r.Operation.Name,
+		trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attributes...))
+	r.SetContext(context.WithValue(ctx, awsSpanKey{}, span))
+}
+
+// endAWSSpan ends the span of the AWS request with its outcome
+func endAWSSpan(r *request.Request) {
+	span, ok := r.Context().Value(awsSpanKey{}).(trace.Span)
+	if !ok {
+		return
+	}
+	if r.HTTPResponse != nil {
+		span.SetAttributes(attribute.Int("http.status_code", r.HTTPResponse.StatusCode))
+	}
+	span.SetAttributes(attribute.Int("aws.retry_count", r.RetryCount))
+	RecordError(span, r.Error)
+	span.End()
+}
+
+// observeAWSRequest records the completed DynamoDB request - the latency includes the retries
+func observeAWSRequest(r *request.Request) {
+	if r.ClientInfo.ServiceName != dynamodb.ServiceName || r.Operation == nil {
+		return
+	}
+	ObserveDynamoDBRequest(tableName(r.Params), r.Operation.Name, time.Since(r.Time), r.Error)
+}
+
+// tableName returns the TableName of the DynamoDB input, the batch and transaction inputs span multiple tables
+func tableName(params interface{}) string {
+	value := reflect.ValueOf(params)
+	if value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return multipleTables
+	}
+	field := value.FieldByName("TableName")
+	if !field.IsValid() || field.Kind() != reflect.Ptr || field.IsNil() {
+		return multipleTables
+	}
+	name, ok := field.Interface().(*string)
+	if !ok {
+		return multipleTables
+	}
+	return *name
+}
@@ -0,0 +1,50 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package telemetry
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// GitHub rate limit response headers
+const (
+	gitHubRateLimitRemainingHeader = "X-RateLimit-Remaining"
+	gitHubRateLimitResourceHeader  = "X-RateLimit-Resource"
+	defaultGitHubRateLimitResource = "core"
+)
+
+// gitHubTransport records the GitHub API calls and the rate limit remaining reported by the responses
+type gitHubTransport struct {
+	base http.RoundTripper
+}
+
+// NewGitHubTransport returns a round tripper recording the GitHub API calls made with the base round tripper
+func NewGitHubTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &gitHubTransport{base: base}
+}
+
+// RoundTrip executes the request with the base round tripper
+func (t *gitHubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp == nil {
+		ObserveGitHubRequest(req.Method, 0, time.Since(start))
+		return resp, err
+	}
+
+	ObserveGitHubRequest(req.Method, resp.StatusCode, time.Since(start))
+	if remaining, convErr := strconv.Atoi(resp.Header.Get(gitHubRateLimitRemainingHeader)); convErr == nil {
+		resource := resp.Header.Get(gitHubRateLimitResourceHeader)
+		if resource == "" {
+			resource = defaultGitHubRateLimitResource
+		}
+		SetGitHubRateLimitRemaining(resource, remaining)
+	}
+	return resp, nil
+}
@@ -0,0 +1,155 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package telemetry
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metricsNamespace is the prefix of the metric names
+const metricsNamespace = "easycla"
+
+// outcome label values
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// UnknownOperation is the operation label of the requests which did not match a swagger operation
+const UnknownOperation = "unknown"
+
+var (
+	registry = prometheus.NewRegistry()
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the API requests by swagger operation, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation", "method", "code"})
+
+	dynamoDBRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "dynamodb",
+		Name:      "requests_total",
+		Help:      "Number of DynamoDB calls by table, operation and outcome.",
+	}, []string{"table", "operation", "outcome"})
+
+	dynamoDBRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "dynamodb",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the DynamoDB calls by table and operation, including the retries.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"table", "operation"})
+
+	githubRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "github",
+		Name:      "requests_total",
+		Help:      "Number of GitHub API calls by method and status code.",
+	}, []string{"method", "code"})
+
+	githubRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "github",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the GitHub API calls by method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	githubRateLimitRemaining = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "github",
+		Name:      "rate_limit_remaining",
+		Help:      "Remaining GitHub API calls in the current rate limit window, as reported by the last response.",
+	}, []string{"resource"})
+
+	emailsSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "email",
+		Name:      "sent_total",
+		Help:      "Number of outbound emails by outcome.",
+	}, []string{"outcome"})
+
+	eventsLogged = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "events",
+		Name:      "logged_total",
+		Help:      "Number of events logged by event type and outcome.",
+	}, []string{"event_type", "outcome"})
+)
+
+func init() {
+	registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		httpRequestDuration,
+		dynamoDBRequests,
+		dynamoDBRequestDuration,
+		githubRequests,
+		githubRequestDuration,
+		githubRateLimitRemaining,
+		emailsSent,
+		eventsLogged,
+	)
+}
+
+// Handler returns the HTTP handler exposing the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// outcome returns the outcome label value of the error
+func outcome(err error) string {
+	if err != nil {
+		return OutcomeError
+	}
+	return OutcomeSuccess
+}
+
+// ObserveHTTPRequest records the latency of an API request
+func ObserveHTTPRequest(operation, method string, statusCode int, elapsed time.Duration) {
+	if operation == "" {
+		operation = UnknownOperation
+	}
+	httpRequestDuration.WithLabelValues(operation, method, strconv.Itoa(statusCode)).Observe(elapsed.Seconds())
+}
+
+// ObserveDynamoDBRequest records a DynamoDB call
+func ObserveDynamoDBRequest(table, operation string, elapsed time.Duration, err error) {
+	dynamoDBRequests.WithLabelValues(table, operation, outcome(err)).Inc()
+	dynamoDBRequestDuration.WithLabelValues(table, operation).Observe(elapsed.Seconds())
+}
+
+// ObserveGitHubRequest records a GitHub API call, statusCode is zero when no response was received
+func ObserveGitHubRequest(method string, statusCode int, elapsed time.Duration) {
+	code := OutcomeError
+	if statusCode != 0 {
+		code = strconv.Itoa(statusCode)
+	}
+	githubRequests.WithLabelValues(method, code).Inc()
+	githubRequestDuration.WithLabelValues(method).Observe(elapsed.Seconds())
+}
+
+// SetGitHubRateLimitRemaining records the remaining GitHub API calls of the rate limit resource
+func SetGitHubRateLimitRemaining(resource string, remaining int) {
+	githubRateLimitRemaining.WithLabelValues(resource).Set(float64(remaining))
+}
+
+// ObserveEmail records an outbound email
+func ObserveEmail(err error) {
+	emailsSent.WithLabelValues(outcome(err)).Inc()
+}
+
+// ObserveEvent records a logged event
+func ObserveEvent(eventType string, err error) {
+	eventsLogged.WithLabelValues(eventType, outcome(err)).Inc()
+}
@@ -0,0 +1,112 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package telemetry
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/sirupsen/logrus"
+
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+)
+
+// DefaultSnapshotInterval is the minimum time between two metrics snapshots in lambda mode
+const DefaultSnapshotInterval = time.Minute
+
+// Snapshot returns the current value of the EasyCLA metrics keyed by the metric name and labels, the histograms
+// are reported as their _count and _sum series
+func Snapshot() (map[string]float64, error) {
+	families, err := registry.Gather()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := map[string]float64{}
+	for _, family := range families {
+		name := family.GetName()
+		if !strings.HasPrefix(name, metricsNamespace+"_") {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			key := name + labelsString(metric.GetLabel())
+			switch family.GetType() {
+			case dto.MetricType_COUNTER:
+				snapshot[key] = metric.GetCounter().GetValue()
+			case dto.MetricType_GAUGE:
+				snapshot[key] = metric.GetGauge().GetValue()
+			case dto.MetricType_HISTOGRAM:
+				labels := labelsString(metric.GetLabel())
+				snapshot[name+"_count"+labels] = float64(metric.GetHistogram().GetSampleCount())
+				snapshot[name+"_sum"+labels] = metric.GetHistogram().GetSampleSum()
+			}
+		}
+	}
+	return snapshot, nil
+}
+
+// labelsString returns the labels in the Prometheus series format, e.g. {method="GET",operation="getUser"}
+func labelsString(labels []*dto.LabelPair) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(labels))
+	for _, label := range labels {
+		pairs = append(pairs, label.GetName()+"=\""+label.GetValue()+"\"")
+	}
+	sort.Strings(pairs)
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// LogSnapshot logs the current metrics in a single entry, used where the /metrics endpoint can't be scraped
+func LogSnapshot() {
+	f := logrus.Fields{
+		"functionName": "telemetry.snapshot.LogSnapshot",
+	}
+
+	snapshot, err := Snapshot()
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to gather the metrics")
+		return
+	}
+	f["metrics"] = snapshot
+	log.WithFields(f).Info("metrics snapshot")
+}
+
+// snapshotLogger logs a snapshot at most once per interval
+type snapshotLogger struct {
+	lock     sync.Mutex
+	interval time.Duration
+	last     time.Time
+	now      func() time.Time
+}
+
+// logIfDue logs a snapshot when the interval has elapsed since the last one
+func (s *snapshotLogger) logIfDue() {
+	s.lock.Lock()
+	now := s.now()
+	due := now.Sub(s.last) >= s.interval
+	if due {
+		s.last = now
+	}
+	s.lock.Unlock()
+
+	if due {
+		LogSnapshot()
+	}
+}
+
+// SnapshotMiddleware logs a metrics snapshot after the requests, at most once per interval - the lambda functions
+// are frozen between the invocations, so the snapshots are driven by the requests rather than by a timer
+func SnapshotMiddleware(next http.Handler, interval time.Duration) http.Handler {
+	logger := &snapshotLogger{interval: interval, last: time.Now(), now: time.Now}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+		logger.logIfDue()
+	})
+}
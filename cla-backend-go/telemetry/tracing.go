@@ -0,0 +1,156 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlphttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/semconv"
+	"go.opentelemetry.io/otel/trace"
+
+	runtimeClient "github.com/go-openapi/runtime/client"
+
+	"github.com/communitybridge/easycla/cla-backend-go/config"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+)
+
+// tracerName is the instrumentation name of the EasyCLA spans
+const tracerName = "github.com/communitybridge/easycla/cla-backend-go"
+
+// otlpEndpointEnv is the standard OpenTelemetry environment variable of the OTLP endpoint, used when the
+// configuration does not set one - e.g. OTEL_EXPORTER_OTLP_ENDPOINT=localhost:4318 for a local collector
+const otlpEndpointEnv = "OTEL_EXPORTER_OTLP_ENDPOINT"
+
+// tracerProvider is the SDK provider, nil when the traces are not exported
+var tracerProvider *sdktrace.TracerProvider
+
+// InitTracing installs the W3C trace context propagator and, when an OTLP endpoint is configured, the OTLP/HTTP
+// span exporter - returns the function flushing the pending spans on shutdown
+func InitTracing(ctx context.Context, serviceName string, tracingConfig config.Tracing) (func(context.Context) error, error) {
+	f := logrus.Fields{
+		"functionName": "telemetry.tracing.InitTracing",
+		"serviceName":  serviceName,
+	}
+
+	// The trace context is propagated to the outbound calls even when the traces are not exported here
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	endpoint := tracingConfig.OTLPEndpoint
+	if endpoint == "" {
+		endpoint = os.Getenv(otlpEndpointEnv)
+	}
+	if endpoint == "" {
+		log.WithFields(f).Debug("no OTLP endpoint configured - the traces are not exported")
+		return func(context.Context) error { return nil }, nil
+	}
+
+	driverOptions := []otlphttp.Option{otlphttp.WithEndpoint(endpoint)}
+	if tracingConfig.Insecure {
+		driverOptions = append(driverOptions, otlphttp.WithInsecure())
+	}
+	exporter, err := otlp.NewExporter(ctx, otlphttp.NewDriver(driverOptions...))
+	if err != nil {
+		return nil, err
+	}
+
+	sampleRatio := tracingConfig.SampleRatio
+	if sampleRatio <= 0 || sampleRatio > 1 {
+		sampleRatio = 1
+	}
+	tracerProvider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.ServiceNameKey.String(serviceName))),
+	)
+	otel.SetTracerProvider(tracerProvider)
+
+	log.WithFields(f).Infof("exporting the traces to: %s with sample ratio: %.2f", endpoint, sampleRatio)
+	return tracerProvider.Shutdown, nil
+}
+
+// FlushTraces exports the pending spans, the lambda functions are frozen after the invocations and would
+// otherwise hold the spans until the next one
+func FlushTraces(ctx context.Context) {
+	if tracerProvider == nil {
+		return
+	}
+	if err := tracerProvider.ForceFlush(ctx); err != nil {
+		log.WithFields(logrus.Fields{"functionName": "telemetry.tracing.FlushTraces"}).WithError(err).Warn("unable to flush the traces")
+	}
+}
+
+// StartSpan starts a span as a child of the span of the context, the returned context carries the new span to the
+// outbound calls
+func StartSpan(ctx context.Context, spanName string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, spanName, trace.WithAttributes(attributes...))
+}
+
+// RecordError marks the span as failed with the error
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// requestIDKey is the attribute correlating the spans with the request ID of the logs
+const requestIDKey = attribute.Key("easycla.request_id")
+
+// AnnotateServerSpan names the server span of the request after the routed operation and records the request ID
+func AnnotateServerSpan(r *http.Request, operation, requestID string) {
+	span := trace.SpanFromContext(r.Context())
+	span.SetName(operation)
+	if requestID != "" {
+		span.SetAttributes(requestIDKey.String(requestID))
+	}
+}
+
+// TracingMiddleware starts the server span of the requests from the W3C trace context headers, the spans are
+// flushed after each request when flush is set
+func TracingMiddleware(next http.Handler, flush bool) http.Handler {
+	handler := otelhttp.NewHandler(next, "easycla-api")
+	if !flush {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r)
+		FlushTraces(r.Context())
+	})
+}
+
+// NewHTTPTransport returns a round tripper recording the outbound calls in client spans and propagating the trace
+// context of the request context in the W3C traceparent header
+func NewHTTPTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base)
+}
+
+// NewHTTPClient returns an HTTP client with the tracing transport
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{Transport: NewHTTPTransport(nil), Timeout: timeout}
+}
+
+// NewSwaggerTransport returns the go-swagger client transport of the platform service with the tracing transport,
+// the trace context is taken from the Context of the operation parameters
+func NewSwaggerTransport(host, basePath string, schemes []string) *runtimeClient.Runtime {
+	transport := runtimeClient.New(host, basePath, schemes)
+	transport.Transport = NewHTTPTransport(transport.Transport)
+	return transport
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -110,6 +111,9 @@ func NewRepository(awsSession *session.Session, stage string) Repository {
 
 // GetTemplates returns a list containing all the template models
 func (r Repository) GetTemplates(ctx context.Context) ([]models.Template, error) {
+	ctx, span := telemetry.StartSpan(ctx, "template.repository.GetTemplates")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "GetTemplates",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -132,6 +136,9 @@ func (r Repository) GetTemplates(ctx context.Context) ([]models.Template, error)
 
 // GetTemplateName returns the template name when provided the template ID
 func (r Repository) GetTemplateName(ctx context.Context, templateID string) (string, error) {
+	ctx, span := telemetry.StartSpan(ctx, "template.repository.GetTemplateName")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "v1.template.repository.GetTemplateName",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -162,6 +169,9 @@ func (r Repository) GetTemplate(templateID string) (models.Template, error) {
 
 // CLAGroupTemplateExists return true if the specified template ID exists, false otherwise
 func (r Repository) CLAGroupTemplateExists(ctx context.Context, templateID string) bool {
+	_, span := telemetry.StartSpan(ctx, "template.repository.CLAGroupTemplateExists")
+	defer span.End()
+
 	_, ok := templateMap[templateID]
 	return ok
 }
@@ -269,6 +279,9 @@ func (r Repository) buildProjectModel(dbModel DBProjectModel) *models.ClaGroup {
 
 // UpdateDynamoContractGroupTemplates updates the templates in the data store
 func (r Repository) UpdateDynamoContractGroupTemplates(ctx context.Context, claGroupID string, template models.Template, pdfUrls models.TemplatePdfs, projectCCLAEnabled, projectICLAEnabled bool) error {
+	ctx, span := telemetry.StartSpan(ctx, "template.repository.UpdateDynamoContractGroupTemplates")
+	defer span.End()
+
 	f := logrus.Fields{
 		"functionName":   "UpdateDynamoContractGroupTemplates",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
@@ -364,7 +377,7 @@ func (r Repository) UpdateDynamoContractGroupTemplates(ctx context.Context, claG
 			UpdateExpression: aws.String("set #date_modified = :date_modified, #project_corporate_documents =  list_append(#project_corporate_documents, :project_corporate_documents)"),
 		}
 
-		_, err = r.dynamoDBClient.UpdateItem(input)
+		_, err = r.dynamoDBClient.UpdateItemWithContext(ctx, input)
 		if err != nil {
 			log.WithFields(f).Warnf("Error updating the CLA Group corporate document with template from: %s, error: %+v", template.Name, err)
 			return err
@@ -448,7 +461,7 @@ func (r Repository) UpdateDynamoContractGroupTemplates(ctx context.Context, claG
 		}
 
 		log.WithFields(f).Debugf("Updating table %s with individual template details - CLA Group id: %s.", tableName, claGroupID)
-		_, err = r.dynamoDBClient.UpdateItem(input)
+		_, err = r.dynamoDBClient.UpdateItemWithContext(ctx, input)
 		if err != nil {
 			log.WithFields(f).Warnf("Error updating the CLA Group individual document with template from: %s, error: %+v", template.Name, err)
 			return err
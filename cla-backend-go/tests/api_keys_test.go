@@ -0,0 +1,314 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/communitybridge/easycla/cla-backend-go/api_keys"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+const apiKeysTestCompanySFID = "company-sfid-1234"
+
+func newAPIKeysTestService() (api_keys.Service, events.Repository) {
+	eventsRepo := events.NewMemoryRepository()
+	eventsService := events.NewService(eventsRepo, events.NewMockRepository())
+	service := api_keys.NewService(api_keys.NewMemoryRepository(), eventsService,
+		[]string{"getCompanyProjectClaManagers", "listCompanyEvents", "createAPIKey", "revokeAPIKey"})
+	return service, eventsRepo
+}
+
+func apiKeysTestEventTypes(t *testing.T, eventsRepo events.Repository) []string {
+	recent, err := eventsRepo.GetRecentEvents(100)
+	assert.NoError(t, err)
+	var eventTypes []string
+	for _, event := range recent.Events {
+		eventTypes = append(eventTypes, event.EventType)
+	}
+	return eventTypes
+}
+
+func TestAPIKeyLifecycle(t *testing.T) {
+	service, eventsRepo := newAPIKeysTestService()
+	ctx := context.Background()
+
+	// the keys can't be granted the API key operations or the unknown ones
+	for _, operations := range [][]string{nil, {"createAPIKey"}, {"deleteEverything"}} {
+		_, _, err := service.CreateKey(ctx, &api_keys.KeyInput{Name: "ci", ScopeType: api_keys.ScopeTypeOrganization, ScopeID: apiKeysTestCompanySFID, Operations: operations})
+		assert.Equal(t, api_keys.ErrInvalidOperations, err)
+	}
+	_, _, err := service.CreateKey(ctx, &api_keys.KeyInput{Name: "ci", ScopeType: "foundation", ScopeID: apiKeysTestCompanySFID, Operations: []string{"listCompanyEvents"}})
+	assert.Equal(t, api_keys.ErrInvalidScope, err)
+	_, _, err = service.CreateKey(ctx, &api_keys.KeyInput{Name: "ci", ScopeType: api_keys.ScopeTypeOrganization, ScopeID: apiKeysTestCompanySFID, Operations: []string{"listCompanyEvents"}, ExpiresInDays: 400})
+	assert.Equal(t, api_keys.ErrInvalidExpiry, err)
+
+	key, apiKey, err := service.CreateKey(ctx, &api_keys.KeyInput{
+		Name:       "release bot",
+		ScopeType:  api_keys.ScopeTypeOrganization,
+		ScopeID:    apiKeysTestCompanySFID,
+		Operations: []string{"listCompanyEvents", "getCompanyProjectClaManagers", "listCompanyEvents"},
+		CreatedBy:  "jdoe",
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []string{"getCompanyProjectClaManagers", "listCompanyEvents"}, key.Operations)
+	assert.Equal(t, api_keys.StatusActive, key.Status(time.Now()))
+	assert.Equal(t, api_keys.StatusExpired, key.Status(time.Now().AddDate(0, 0, api_keys.DefaultExpiryDays+1)))
+
+	// only the hash of the secret is stored
+	stored, err := service.GetKey(ctx, key.KeyID)
+	assert.NoError(t, err)
+	secret := apiKey[strings.LastIndex(apiKey, "_")+1:]
+	assert.NotEmpty(t, stored.SecretHash)
+	assert.NotContains(t, stored.SecretHash, secret)
+
+	authenticated, err := service.Authenticate(ctx, apiKey, "listCompanyEvents")
+	if assert.NoError(t, err) {
+		assert.Equal(t, key.KeyID, authenticated.KeyID)
+	}
+	_, err = service.Authenticate(ctx, apiKey, "createCompany")
+	assert.Equal(t, api_keys.ErrOperationNotAllowed, err)
+	_, err = service.Authenticate(ctx, apiKey+"x", "listCompanyEvents")
+	assert.Equal(t, api_keys.ErrInvalidKey, err)
+	_, err = service.Authenticate(ctx, "not a key", "listCompanyEvents")
+	assert.Equal(t, api_keys.ErrInvalidKey, err)
+
+	stored, err = service.GetKey(ctx, key.KeyID)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, stored.DateLastUsed)
+
+	// the rotated key remains valid for the grace period
+	rotated, rotatedAPIKey, err := service.RotateKey(ctx, key.KeyID, time.Hour, "jdoe")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, key.KeyID, rotated.RotatedFrom)
+	assert.Equal(t, key.Operations, rotated.Operations)
+	assert.Equal(t, key.DateExpires, rotated.DateExpires)
+	_, err = service.Authenticate(ctx, rotatedAPIKey, "listCompanyEvents")
+	assert.NoError(t, err)
+	_, err = service.Authenticate(ctx, apiKey, "listCompanyEvents")
+	assert.NoError(t, err)
+	stored, err = service.GetKey(ctx, key.KeyID)
+	assert.NoError(t, err)
+	assert.Equal(t, rotated.KeyID, stored.RotatedTo)
+	assert.Equal(t, api_keys.StatusExpired, stored.Status(time.Now().Add(2*time.Hour)))
+	_, _, err = service.RotateKey(ctx, key.KeyID, time.Hour, "jdoe")
+	assert.Equal(t, api_keys.ErrKeyAlreadyRotated, err)
+	_, _, err = service.RotateKey(ctx, rotated.KeyID, 365*24*time.Hour, "jdoe")
+	assert.Equal(t, api_keys.ErrInvalidGracePeriod, err)
+
+	// the revoked keys are rejected immediately
+	revoked, err := service.RevokeKey(ctx, rotated.KeyID, "jdoe")
+	assert.NoError(t, err)
+	assert.Equal(t, api_keys.StatusRevoked, revoked.Status(time.Now()))
+	_, err = service.Authenticate(ctx, rotatedAPIKey, "listCompanyEvents")
+	assert.Equal(t, api_keys.ErrKeyRevoked, err)
+
+	keys, err := service.ListKeys(ctx, api_keys.ScopeTypeOrganization, apiKeysTestCompanySFID)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+
+	eventTypes := apiKeysTestEventTypes(t, eventsRepo)
+	for _, eventType := range []string{events.APIKeyCreated, events.APIKeyUsed, events.APIKeyRejected, events.APIKeyRotated, events.APIKeyRevoked} {
+		assert.Contains(t, eventTypes, eventType)
+	}
+}
+
+func TestAPIKeyUsesAreThrottled(t *testing.T) {
+	eventsRepo := events.NewMemoryRepository()
+	keysRepo := api_keys.NewMemoryRepository()
+	service := api_keys.NewService(keysRepo, events.NewService(eventsRepo, events.NewMockRepository()), []string{"listCompanyEvents"})
+	ctx := context.Background()
+	key, apiKey, err := service.CreateKey(ctx, &api_keys.KeyInput{
+		Name:       "ci",
+		ScopeType:  api_keys.ScopeTypeOrganization,
+		ScopeID:    apiKeysTestCompanySFID,
+		Operations: []string{"listCompanyEvents"},
+		CreatedBy:  "jdoe",
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	usedEvents := func() int {
+		count := 0
+		for _, eventType := range apiKeysTestEventTypes(t, eventsRepo) {
+			if eventType == events.APIKeyUsed {
+				count++
+			}
+		}
+		return count
+	}
+
+	// the uses within the interval are neither recorded nor logged again
+	for i := 0; i < 3; i++ {
+		_, err = service.Authenticate(ctx, apiKey, "listCompanyEvents")
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 1, usedEvents())
+	stored, err := service.GetKey(ctx, key.KeyID)
+	assert.NoError(t, err)
+	firstUse := stored.DateLastUsed
+	assert.NotEmpty(t, firstUse)
+
+	// the rejected uses within the interval are rejected but not logged again
+	rejectedEvents := func() int {
+		count := 0
+		for _, eventType := range apiKeysTestEventTypes(t, eventsRepo) {
+			if eventType == events.APIKeyRejected {
+				count++
+			}
+		}
+		return count
+	}
+	_, err = service.Authenticate(ctx, apiKey, "createCompany")
+	assert.Equal(t, api_keys.ErrOperationNotAllowed, err)
+	_, err = service.Authenticate(ctx, apiKey, "createCompany")
+	assert.Equal(t, api_keys.ErrOperationNotAllowed, err)
+	_, err = service.Authenticate(ctx, apiKey+"x", "listCompanyEvents")
+	assert.Equal(t, api_keys.ErrInvalidKey, err)
+	assert.Equal(t, 1, rejectedEvents())
+	assert.Len(t, apiKeysTestEventTypes(t, eventsRepo), 3)
+	stored, err = service.GetKey(ctx, key.KeyID)
+	assert.NoError(t, err)
+	firstRejection := stored.DateLastRejected
+	assert.NotEmpty(t, firstRejection)
+
+	// the first rejected use after the interval is logged
+	earlierRejection := time.Now().Add(-2 * api_keys.LastRejectedInterval).UTC().Format(time.RFC3339)
+	recorded, err := keysRepo.UpdateLastRejected(ctx, key.KeyID, earlierRejection, earlierRejection)
+	assert.NoError(t, err)
+	assert.False(t, recorded)
+	recorded, err = keysRepo.UpdateLastRejected(ctx, key.KeyID, earlierRejection, firstRejection)
+	assert.NoError(t, err)
+	assert.True(t, recorded)
+	_, err = service.Authenticate(ctx, apiKey, "createCompany")
+	assert.Equal(t, api_keys.ErrOperationNotAllowed, err)
+	assert.Equal(t, 2, rejectedEvents())
+
+	// a later use is not overwritten by an earlier one
+	earlier := time.Now().Add(-2 * api_keys.LastUsedInterval).UTC().Format(time.RFC3339)
+	recorded, err = keysRepo.UpdateLastUsed(ctx, key.KeyID, earlier, earlier)
+	assert.NoError(t, err)
+	assert.False(t, recorded)
+
+	// the first use after the interval is recorded and logged
+	recorded, err = keysRepo.UpdateLastUsed(ctx, key.KeyID, earlier, firstUse)
+	assert.NoError(t, err)
+	assert.True(t, recorded)
+	_, err = service.Authenticate(ctx, apiKey, "listCompanyEvents")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, usedEvents())
+	stored, err = service.GetKey(ctx, key.KeyID)
+	assert.NoError(t, err)
+	assert.True(t, stored.DateLastUsed > earlier)
+}
+
+func TestAPIKeyRotationIsAtomic(t *testing.T) {
+	keysRepo := api_keys.NewMemoryRepository()
+	service := api_keys.NewService(keysRepo, events.NewService(events.NewMemoryRepository(), events.NewMockRepository()), []string{"listCompanyEvents"})
+	ctx := context.Background()
+	key, _, err := service.CreateKey(ctx, &api_keys.KeyInput{
+		Name:       "ci",
+		ScopeType:  api_keys.ScopeTypeOrganization,
+		ScopeID:    apiKeysTestCompanySFID,
+		Operations: []string{"listCompanyEvents"},
+		CreatedBy:  "jdoe",
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	// the key as loaded by a concurrent rotation, before this rotation is stored
+	concurrent, err := keysRepo.GetKey(ctx, key.KeyID)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	rotated, _, err := service.RotateKey(ctx, key.KeyID, time.Hour, "jdoe")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// the concurrent rotation stores neither key
+	concurrent.RotatedTo = "concurrent-key-id"
+	err = keysRepo.RotateKey(ctx, concurrent, &api_keys.APIKey{KeyID: "concurrent-key-id", Scope: concurrent.Scope})
+	assert.Equal(t, api_keys.ErrKeyAlreadyRotated, err)
+	_, err = keysRepo.GetKey(ctx, "concurrent-key-id")
+	assert.Equal(t, api_keys.ErrKeyDoesNotExist, err)
+	stored, err := keysRepo.GetKey(ctx, key.KeyID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, rotated.KeyID, stored.RotatedTo)
+	}
+	keys, err := service.ListKeys(ctx, api_keys.ScopeTypeOrganization, apiKeysTestCompanySFID)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+}
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	service, _ := newAPIKeysTestService()
+	ctx := context.Background()
+	key, apiKey, err := service.CreateKey(ctx, &api_keys.KeyInput{
+		Name:       "ci",
+		ScopeType:  api_keys.ScopeTypeOrganization,
+		ScopeID:    apiKeysTestCompanySFID,
+		Operations: []string{"listCompanyEvents"},
+		CreatedBy:  "jdoe",
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var forwarded *http.Request
+	handler := api_keys.Middleware(service, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = r
+	}))
+	send := func(headers map[string]string) int {
+		forwarded = nil
+		r := httptest.NewRequest(http.MethodGet, "/v4/company/"+apiKeysTestCompanySFID+"/events", nil)
+		for name, value := range headers {
+			r.Header.Set(name, value)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	// the requests without a key are passed through
+	assert.Equal(t, http.StatusOK, send(map[string]string{"X-ACL": "user-acl"}))
+	if assert.NotNil(t, forwarded) {
+		assert.Equal(t, "user-acl", forwarded.Header.Get("X-ACL"))
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, send(map[string]string{api_keys.HeaderAPIKey: "ecla_unknown_secret"}))
+	assert.Nil(t, forwarded)
+
+	// the request isn't routed to an operation the key allows
+	assert.Equal(t, http.StatusForbidden, send(map[string]string{api_keys.HeaderAPIKey: apiKey}))
+	assert.Nil(t, forwarded)
+
+	// the key maps to the ACL of a user with the organization scope
+	encoded, err := key.EncodedACL()
+	assert.NoError(t, err)
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	assert.NoError(t, err)
+	var acl auth.ACL
+	assert.NoError(t, json.Unmarshal(data, &acl))
+	assert.False(t, acl.Admin)
+	if assert.Len(t, acl.Scopes, 1) {
+		assert.Equal(t, "organization", acl.Scopes[0].Type)
+		assert.Equal(t, apiKeysTestCompanySFID, acl.Scopes[0].ID)
+	}
+	assert.Equal(t, "api-key-"+key.KeyID, key.PrincipalName())
+}
@@ -0,0 +1,90 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	signatureService "github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubCLAGroupLookup struct {
+	claGroup *models.ClaGroup
+}
+
+func (s *stubCLAGroupLookup) GetCLAGroupByID(ctx context.Context, claGroupID string, loadRepoDetails bool) (*models.ClaGroup, error) {
+	return s.claGroup, nil
+}
+
+func TestApprovalListExpiryRemovesExpiredEntries(t *testing.T) {
+	ctx := utils.NewContext()
+	utils.SetEmailSender(&utils.MockEmailSender{})
+	eventsService := events.NewService(events.NewMemoryRepository(), events.NewMockRepository())
+	companyRepo := company.NewMemoryRepository()
+	repo := signatureService.NewMemoryRepository(companyRepo, users.NewMemoryRepository(), eventsService, nil, nil, nil)
+
+	companyModel, err := companyRepo.CreateCompany(ctx, &models.Company{CompanyName: "Expiry Co " + uniqueTestID(t)})
+	assert.NoError(t, err)
+	claGroup := &models.ClaGroup{ProjectID: "cla-group-" + uniqueTestID(t), ProjectName: "Expiry Project"}
+
+	now := time.Now().UTC()
+	signatureID := uniqueTestID(t)
+	assert.NoError(t, repo.PutSignature(ctx, signatureService.ItemSignature{
+		SignatureID:            signatureID,
+		SignatureProjectID:     claGroup.ProjectID,
+		SignatureReferenceID:   companyModel.CompanyID,
+		SignatureReferenceType: utils.SignatureReferenceTypeCompany,
+		SignatureType:          utils.SignatureTypeCCLA,
+		SignatureApproved:      true,
+		SignatureSigned:        true,
+		GitHubWhitelist:        []string{"expired-user", "expiring-user", "permanent-user"},
+		ApprovalListExpirations: map[string]string{
+			signatureService.GitHubUsernameApprovalListColumn + ":expired-user":  now.Add(-time.Hour).Format(time.RFC3339),
+			signatureService.GitHubUsernameApprovalListColumn + ":expiring-user": now.Add(48 * time.Hour).Format(time.RFC3339),
+		},
+	}))
+
+	// The signatures with no entry expiring within the warning period are not loaded
+	assert.NoError(t, repo.PutSignature(ctx, signatureService.ItemSignature{
+		SignatureID:            uniqueTestID(t) + "-later",
+		SignatureProjectID:     claGroup.ProjectID,
+		SignatureReferenceID:   companyModel.CompanyID,
+		SignatureReferenceType: utils.SignatureReferenceTypeCompany,
+		SignatureType:          utils.SignatureTypeCCLA,
+		SignatureApproved:      true,
+		SignatureSigned:        true,
+		GitHubWhitelist:        []string{"later-user"},
+		ApprovalListExpirations: map[string]string{
+			signatureService.GitHubUsernameApprovalListColumn + ":later-user": now.Add(30 * 24 * time.Hour).Format(time.RFC3339),
+		},
+	}))
+
+	expiryService := signatureService.NewApprovalListExpiryService(repo, &stubCLAGroupLookup{claGroup: claGroup}, companyRepo, eventsService)
+	result, err := expiryService.ProcessApprovalListExpirations(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.SignaturesProcessed)
+	assert.Equal(t, 1, result.EntriesRemoved)
+	assert.Equal(t, 1, result.WarningsSent)
+	assert.Equal(t, 0, result.Errors)
+
+	sig, err := repo.GetSignature(ctx, signatureID)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"expiring-user", "permanent-user"}, sig.GithubUsernameApprovalList)
+	assert.Len(t, sig.ApprovalListExpirations, 1)
+	assert.Equal(t, []string{signatureService.GitHubUsernameApprovalListColumn + ":expiring-user"}, sig.ApprovalListExpiryWarningsSent)
+
+	// A second run does not warn the CLA Managers again
+	result, err = expiryService.ProcessApprovalListExpirations(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.EntriesRemoved)
+	assert.Equal(t, 0, result.WarningsSent)
+}
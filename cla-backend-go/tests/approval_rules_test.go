@@ -0,0 +1,172 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/restapi/operations/signatures"
+	signatureService "github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApprovalRulesWildcardDomain(t *testing.T) {
+	rules := signatureService.NewApprovalRules(utils.NewContext(), nil, []string{"*.corp.example.com", "example.org"}, nil, nil)
+
+	match := rules.Explain(signatureService.ApprovalCandidate{Emails: []string{"jane@eng.CORP.example.com"}})
+	assert.True(t, match.Approved)
+	assert.Equal(t, utils.EmailDomainCriteria, match.Criteria)
+	assert.Equal(t, "*.corp.example.com", match.Entry)
+	assert.Equal(t, "eng.CORP.example.com", match.Value)
+
+	assert.True(t, rules.Explain(signatureService.ApprovalCandidate{Emails: []string{"jane@a.b.corp.example.com"}}).Approved)
+	assert.True(t, rules.Explain(signatureService.ApprovalCandidate{Emails: []string{"jane@corp.example.com"}}).Approved)
+	assert.False(t, rules.Explain(signatureService.ApprovalCandidate{Emails: []string{"jane@othercorp.example.com"}}).Approved)
+	assert.False(t, rules.Explain(signatureService.ApprovalCandidate{Emails: []string{"jane@sub.example.org"}}).Approved)
+	assert.True(t, rules.Explain(signatureService.ApprovalCandidate{Emails: []string{"jane@example.org"}}).Approved)
+}
+
+func TestApprovalRulesDomainPrefixes(t *testing.T) {
+	// the domain patterns match like the Python backend, *. and . include the domain itself
+	for _, entry := range []string{"*.example.net", ".example.net", "*example.net"} {
+		rules := signatureService.NewApprovalRules(utils.NewContext(), nil, []string{entry}, nil, nil)
+		assert.True(t, rules.Explain(signatureService.ApprovalCandidate{Emails: []string{"jane@example.net"}}).Approved, entry)
+		assert.True(t, rules.Explain(signatureService.ApprovalCandidate{Emails: []string{"jane@eng.example.net"}}).Approved, entry)
+		assert.False(t, rules.Explain(signatureService.ApprovalCandidate{Emails: []string{"jane@example.org"}}).Approved, entry)
+	}
+	rules := signatureService.NewApprovalRules(utils.NewContext(), nil, []string{"example.net"}, nil, nil)
+	assert.False(t, rules.Explain(signatureService.ApprovalCandidate{Emails: []string{"jane@eng.example.net"}}).Approved)
+}
+
+func TestApprovalRulesEmailGlobAndRegex(t *testing.T) {
+	rules := signatureService.NewApprovalRules(utils.NewContext(),
+		[]string{"jane.*@example.com", "regex:[a-z]+@(eng|ops)\\.example\\.net"}, nil, []string{"bot-?"}, nil)
+
+	assert.True(t, rules.Explain(signatureService.ApprovalCandidate{Emails: []string{"Jane.Doe@example.com"}}).Approved)
+	assert.False(t, rules.Explain(signatureService.ApprovalCandidate{Emails: []string{"john@example.com"}}).Approved)
+	assert.True(t, rules.Explain(signatureService.ApprovalCandidate{Emails: []string{"john@ops.example.net"}}).Approved)
+	assert.False(t, rules.Explain(signatureService.ApprovalCandidate{Emails: []string{"john1@ops.example.net"}}).Approved)
+	assert.True(t, rules.Explain(signatureService.ApprovalCandidate{GitHubUsername: "bot-1"}).Approved)
+	assert.False(t, rules.Explain(signatureService.ApprovalCandidate{GitHubUsername: "bot-12"}).Approved)
+}
+
+func TestApprovalRulesDenyOverridesAllow(t *testing.T) {
+	rules := signatureService.NewApprovalRules(utils.NewContext(),
+		[]string{"!*@contractors.example.com"}, []string{"*.example.com", "example.com"}, []string{"jane"}, nil)
+
+	match := rules.Explain(signatureService.ApprovalCandidate{
+		Emails:         []string{"jane@contractors.example.com"},
+		GitHubUsername: "jane",
+	})
+	assert.False(t, match.Approved)
+	assert.True(t, match.Denied)
+	assert.Equal(t, "!*@contractors.example.com", match.Entry)
+	assert.Contains(t, match.Reason, "denied")
+
+	match = rules.Explain(signatureService.ApprovalCandidate{Emails: []string{"john@eng.example.com"}})
+	assert.True(t, match.Approved)
+	assert.False(t, match.Denied)
+
+	match = rules.Explain(signatureService.ApprovalCandidate{Emails: []string{"john@other.org"}})
+	assert.False(t, match.Matched())
+	assert.NotEmpty(t, match.Reason)
+}
+
+func TestValidateApprovalEntry(t *testing.T) {
+	valid := []struct {
+		criteria string
+		entry    string
+	}{
+		{utils.EmailCriteria, "jane@example.com"},
+		{utils.EmailCriteria, "*@example.com"},
+		{utils.EmailCriteria, "!contractor@example.com"},
+		{utils.EmailDomainCriteria, "*.corp.example.com"},
+		{utils.EmailDomainCriteria, ".corp.example.com"},
+		{utils.EmailDomainCriteria, "regex:(eng|ops)\\.example\\.com"},
+		{utils.EmailCriteria, "regex:^[a-z]+\\.[a-z]+@(?:eng|ops)\\.example\\.com$"},
+		{utils.GitHubUsernameCriteria, "regex:bot-\\d{1,3}"},
+		{utils.GitHubUsernameCriteria, "bot-*"},
+		{utils.GitHubOrgCriteria, "!my-org"},
+	}
+	for _, tc := range valid {
+		msg, ok := signatureService.ValidateApprovalEntry(tc.criteria, tc.entry)
+		assert.True(t, ok, "%s %s - %s", tc.criteria, tc.entry, msg)
+	}
+
+	invalid := []struct {
+		criteria string
+		entry    string
+	}{
+		{utils.EmailCriteria, "not-an-email"},
+		{utils.EmailCriteria, "!"},
+		{utils.EmailDomainCriteria, "-bad.example.com"},
+		{utils.EmailDomainCriteria, "regex:(unclosed"},
+		// the expressions which backtrack catastrophically in the Python backend
+		{utils.EmailCriteria, "regex:(a+)+$"},
+		{utils.EmailCriteria, "regex:(a|aa)*@example\\.com"},
+		{utils.EmailCriteria, "regex:[a-z]*[a-z]*[a-z]*[a-z]*@example\\.com"},
+		// the syntax the Python backend evaluates differently or not at all
+		{utils.EmailCriteria, "regex:\\p{L}+@example\\.com"},
+		{utils.EmailCriteria, "regex:(?P<name>[a-z]+)@example\\.com"},
+		{utils.EmailCriteria, "regex:[[:alpha:]]+@example\\.com"},
+		{utils.EmailCriteria, "regex:(?i)jane@example\\.com"},
+		{utils.EmailCriteria, "regex:jane@example\\.com\\z"},
+	}
+	for _, tc := range invalid {
+		_, ok := signatureService.ValidateApprovalEntry(tc.criteria, tc.entry)
+		assert.False(t, ok, "%s %s", tc.criteria, tc.entry)
+	}
+}
+
+func TestEmployeeSignatureLookupWithPatterns(t *testing.T) {
+	ctx := utils.NewContext()
+	eventsService := events.NewService(events.NewMemoryRepository(), events.NewMockRepository())
+	repo := signatureService.NewMemoryRepository(company.NewMemoryRepository(), users.NewMemoryRepository(), eventsService, nil, nil, nil)
+
+	claGroupID := "cla-group-" + uniqueTestID(t)
+	companyID := "company-" + uniqueTestID(t)
+	botSignatureID := uniqueTestID(t) + "-3"
+	for _, ecla := range []signatureService.ItemSignature{
+		{SignatureID: uniqueTestID(t) + "-1", UserEmail: "jane@eng.example.com", UserGithubUsername: "jane"},
+		{SignatureID: uniqueTestID(t) + "-2", UserEmail: "john@example.org", UserGithubUsername: "john"},
+		{SignatureID: botSignatureID, UserEmail: "bot@eng.example.com", UserGithubUsername: "build-bot"},
+	} {
+		ecla.SignatureApproved = true
+		ecla.SignatureSigned = true
+		ecla.SignatureProjectID = claGroupID
+		ecla.SignatureUserCompanyID = companyID
+		ecla.SignatureReferenceType = utils.SignatureReferenceTypeUser
+		ecla.SignatureType = utils.SignatureTypeCLA
+		assert.NoError(t, repo.PutSignature(ctx, ecla))
+	}
+
+	params := signatures.GetProjectCompanyEmployeeSignaturesParams{
+		ProjectID: claGroupID,
+		CompanyID: companyID,
+	}
+
+	sigs, err := repo.GetProjectCompanyEmployeeSignatures(ctx, params, &signatureService.ApprovalCriteria{UserEmail: "*@eng.example.com"}, 10)
+	assert.NoError(t, err)
+	assert.Len(t, sigs.Signatures, 2)
+
+	sigs, err = repo.GetProjectCompanyEmployeeSignatures(ctx, params, &signatureService.ApprovalCriteria{UserEmail: "john@example.org"}, 10)
+	assert.NoError(t, err)
+	assert.Len(t, sigs.Signatures, 1)
+
+	// the exact values are compared case insensitively, like the approval list entries
+	sigs, err = repo.GetProjectCompanyEmployeeSignatures(ctx, params, &signatureService.ApprovalCriteria{UserEmail: "John@Example.org", GitHubUsername: "JOHN"}, 10)
+	assert.NoError(t, err)
+	assert.Len(t, sigs.Signatures, 1)
+
+	denyRules := signatureService.NewApprovalRules(ctx, nil, nil, []string{"!*-bot"}, nil)
+	sigs, err = repo.GetProjectCompanyEmployeeSignatures(ctx, params, &signatureService.ApprovalCriteria{Rules: denyRules}, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, sigs.Signatures, 1) {
+		assert.Equal(t, botSignatureID, sigs.Signatures[0].SignatureID)
+	}
+}
@@ -0,0 +1,255 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	"github.com/communitybridge/easycla/cla-backend-go/github/branch_protection"
+	"github.com/communitybridge/easycla/cla-backend-go/github_organizations"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// auditOrgRepo stores the audits of the branch protection enabled organizations in memory
+type auditOrgRepo struct {
+	orgs   []*models.GithubOrganization
+	audits map[string]*github_organizations.BranchProtectionAudit
+}
+
+func (r *auditOrgRepo) GetBranchProtectionEnabledOrganizations(ctx context.Context) ([]*models.GithubOrganization, error) {
+	return r.orgs, nil
+}
+
+func (r *auditOrgRepo) UpdateBranchProtectionAudit(ctx context.Context, githubOrganizationName string, audit *github_organizations.BranchProtectionAudit) error {
+	r.audits[githubOrganizationName] = audit
+	return nil
+}
+
+// auditRepositoryLister returns the repositories of the organization and records the patterns whose rule the
+// remediations created
+type auditRepositoryLister struct {
+	mu           sync.Mutex
+	repositories map[string][]*models.GithubRepository
+	patterns     map[string][]string
+}
+
+func (l *auditRepositoryLister) GetRepositoriesByOrganizationName(ctx context.Context, gitHubOrgName string) ([]*models.GithubRepository, error) {
+	return l.repositories[gitHubOrgName], nil
+}
+
+func (l *auditRepositoryLister) GetBranchProtectionPatterns(ctx context.Context, repositoryID string) ([]string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.patterns[repositoryID], nil
+}
+
+func (l *auditRepositoryLister) UpdateBranchProtectionPatterns(ctx context.Context, repositoryID string, patterns []string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.patterns[repositoryID] = patterns
+	return nil
+}
+
+// auditClient returns the protection rules of the repositories and records the policies applied by the remediations
+type auditClient struct {
+	mu         sync.Mutex
+	rules      map[string][]branch_protection.BranchProtectionRule
+	failing    map[string]bool
+	remediated map[string]*branch_protection.BranchProtectionPolicy
+}
+
+func (c *auditClient) GetBranchProtectionRules(ctx context.Context, owner, repoName string) ([]branch_protection.BranchProtectionRule, error) {
+	if c.failing[repoName] {
+		return nil, errors.New("rate limited")
+	}
+	return c.rules[repoName], nil
+}
+
+func (c *auditClient) ApplyBranchProtectionPolicy(ctx context.Context, owner, repoName string, policy *branch_protection.BranchProtectionPolicy, droppedPatterns, createdPatterns []string) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.remediated[repoName] = policy
+	// the rules of the patterns which are not protected are created
+	for _, pattern := range policy.BranchPatterns {
+		protected := false
+		for _, rule := range c.rules[repoName] {
+			protected = protected || rule.Pattern == pattern
+		}
+		if !protected {
+			createdPatterns = append(createdPatterns, pattern)
+		}
+	}
+	return createdPatterns, nil
+}
+
+func newBranchProtectionAuditFixture() (*auditOrgRepo, *auditRepositoryLister, *auditClient, github_organizations.BranchProtectionAuditor) {
+	org := &models.GithubOrganization{
+		OrganizationName:           "acme",
+		ProjectSFID:                "project-sfid",
+		OrganizationInstallationID: 42,
+		Enabled:                    true,
+		BranchProtectionEnabled:    true,
+		BranchProtectionPolicy: &models.GithubBranchProtectionPolicy{
+			BranchPatterns:       []string{"main", "release/*"},
+			RequiredStatusChecks: []string{"ci"},
+			EnforceAdmin:         true,
+		},
+	}
+	repo := &auditOrgRepo{
+		orgs:   []*models.GithubOrganization{org},
+		audits: map[string]*github_organizations.BranchProtectionAudit{},
+	}
+	newRepository := func(name string, enabled bool) *models.GithubRepository {
+		return &models.GithubRepository{
+			RepositoryID:               name + "-id",
+			RepositoryName:             "acme/" + name,
+			RepositoryOrganizationName: "acme",
+			RepositoryProjectID:        "cla-group-id",
+			RepositoryType:             utils.GitHubType,
+			Enabled:                    enabled,
+		}
+	}
+	lister := &auditRepositoryLister{
+		repositories: map[string][]*models.GithubRepository{
+			"acme": {
+				newRepository("compliant", true),
+				newRepository("unprotected", true),
+				newRepository("weakened", true),
+				newRepository("failing", true),
+				newRepository("disabled", false),
+			},
+		},
+		patterns: map[string][]string{
+			// the policy created the rule of the main branch of the weakened repository
+			"weakened-id": {"main"},
+		},
+	}
+	compliantRule := func(pattern string) branch_protection.BranchProtectionRule {
+		return branch_protection.BranchProtectionRule{
+			Pattern:                     pattern,
+			RequiresStatusChecks:        true,
+			RequiredStatusCheckContexts: []string{"ci", utils.GitHubBotName},
+			IsAdminEnforced:             true,
+		}
+	}
+	client := &auditClient{
+		rules: map[string][]branch_protection.BranchProtectionRule{
+			"compliant": {compliantRule("main"), compliantRule("release/*")},
+			"weakened": {
+				{
+					Pattern:                     "main",
+					RequiresStatusChecks:        true,
+					RequiredStatusCheckContexts: []string{"ci"},
+					AllowsForcePushes:           true,
+				},
+				compliantRule("release/*"),
+			},
+			// the release branches are not protected, the unrelated rule is not part of the policy
+			"unprotected": {compliantRule("main"), {Pattern: "docs/*"}},
+		},
+		failing:    map[string]bool{"failing": true},
+		remediated: map[string]*branch_protection.BranchProtectionPolicy{},
+	}
+	factory := func(installationID int64, opts ...branch_protection.BranchProtectionRepositoryOption) (github_organizations.BranchProtectionClient, error) {
+		return client, nil
+	}
+	return repo, lister, client, github_organizations.NewBranchProtectionAuditor(repo, lister, factory)
+}
+
+func driftByRepository(audit *github_organizations.BranchProtectionAudit) map[string]*github_organizations.BranchProtectionDrift {
+	drifts := map[string]*github_organizations.BranchProtectionDrift{}
+	for _, drift := range audit.Drifts {
+		drifts[drift.RepositoryName] = drift
+	}
+	return drifts
+}
+
+func TestBranchProtectionAuditReportsDrift(t *testing.T) {
+	repo, _, client, auditor := newBranchProtectionAuditFixture()
+
+	summary, err := auditor.AuditAll(context.Background(), false)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, summary.OrganizationsAudited)
+	assert.Equal(t, 4, summary.RepositoriesAudited)
+	assert.Equal(t, 2, summary.RepositoriesDrifted)
+	assert.Equal(t, 0, summary.RepositoriesRemediated)
+	assert.Equal(t, 1, summary.Errors)
+	assert.Empty(t, client.remediated)
+
+	audit := repo.audits["acme"]
+	if assert.NotNil(t, audit) {
+		assert.Equal(t, "project-sfid", audit.ProjectSFID)
+		assert.False(t, audit.Remediate)
+		drifts := driftByRepository(audit)
+		assert.Len(t, drifts, 3)
+		assert.NotContains(t, drifts, "acme/compliant")
+		assert.Equal(t, []*github_organizations.BranchProtectionRuleDrift{
+			{Pattern: "release/*", Issues: []string{github_organizations.BranchProtectionIssueMissingRule}},
+		}, drifts["acme/unprotected"].Rules)
+		assert.Equal(t, []*github_organizations.BranchProtectionRuleDrift{
+			{Pattern: "main", Issues: []string{
+				github_organizations.BranchProtectionIssueMissingStatusCheck,
+				github_organizations.BranchProtectionIssueEnforceAdminMismatch,
+				github_organizations.BranchProtectionIssueAllowsForcePushes,
+			}},
+		}, drifts["acme/weakened"].Rules)
+		assert.Equal(t, "cla-group-id", drifts["acme/weakened"].ClaGroupID)
+		assert.NotEmpty(t, drifts["acme/failing"].Error)
+	}
+}
+
+func TestBranchProtectionAuditRemediates(t *testing.T) {
+	repo, lister, client, auditor := newBranchProtectionAuditFixture()
+
+	summary, err := auditor.AuditAll(context.Background(), true)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, summary.RepositoriesRemediated)
+
+	// the policy of the organization is applied again on the drifted repositories
+	assert.Len(t, client.remediated, 2)
+	for _, repoName := range []string{"unprotected", "weakened"} {
+		if assert.NotNil(t, client.remediated[repoName]) {
+			assert.Equal(t, []string{"main", "release/*"}, client.remediated[repoName].BranchPatterns)
+			assert.Equal(t, []string{"ci"}, client.remediated[repoName].RequiredStatusChecks)
+			assert.True(t, client.remediated[repoName].EnforceAdmin)
+		}
+	}
+
+	// the rules the remediation created are recorded along with the ones the policy created before
+	assert.Equal(t, []string{"release/*"}, lister.patterns["unprotected-id"])
+	assert.Equal(t, []string{"main"}, lister.patterns["weakened-id"])
+	assert.Empty(t, lister.patterns["compliant-id"])
+
+	drifts := driftByRepository(repo.audits["acme"])
+	assert.True(t, drifts["acme/unprotected"].Remediated)
+	assert.True(t, drifts["acme/weakened"].Remediated)
+	assert.False(t, drifts["acme/failing"].Remediated)
+}
+
+func TestBranchProtectionAuditDefaultPolicy(t *testing.T) {
+	repo, _, _, auditor := newBranchProtectionAuditFixture()
+
+	// without a policy all the branches must be protected
+	org := repo.orgs[0]
+	org.BranchProtectionPolicy = nil
+	audit, err := auditor.AuditOrganization(context.Background(), org, false)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), audit.RepositoriesDrifted)
+	drifts := driftByRepository(audit)
+	assert.Equal(t, []*github_organizations.BranchProtectionRuleDrift{
+		{Pattern: utils.GithubBranchProtectionPatternAll, Issues: []string{github_organizations.BranchProtectionIssueMissingRule}},
+	}, drifts["acme/compliant"].Rules)
+}
+
+func TestBranchProtectionAuditRequiresBranchProtection(t *testing.T) {
+	_, _, _, auditor := newBranchProtectionAuditFixture()
+
+	_, err := auditor.AuditOrganization(context.Background(), &models.GithubOrganization{OrganizationName: "acme", Enabled: true}, false)
+	assert.True(t, errors.Is(err, github_organizations.ErrBranchProtectionNotEnabled))
+}
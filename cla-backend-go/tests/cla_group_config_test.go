@@ -0,0 +1,475 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/communitybridge/easycla/cla-backend-go/cla_group_config"
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
+	"github.com/communitybridge/easycla/cla-backend-go/template"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/cla_groups"
+	"github.com/go-openapi/strfmt"
+	"github.com/stretchr/testify/assert"
+)
+
+// configBackendRepository is a repository of the fake backend
+type configBackendRepository struct {
+	id          string
+	githubID    int64
+	name        string
+	orgName     string
+	claGroupID  string
+	projectSFID string
+	enabled     bool
+	protection  *models.GithubRepositoryBranchProtection
+}
+
+// configBackend keeps the state of the CLA Groups and everything attached to them in memory, it implements the
+// dependencies of the CLA Group configuration service
+type configBackend struct {
+	claGroups map[string]*v1Models.ClaGroup
+	projects  map[string][]string
+	// orgs maps the organization names to the project they are added to
+	orgs         map[string]*models.ProjectGithubOrganization
+	orgProjects  map[string]string
+	repositories []*configBackendRepository
+	gerrits      []*v1Models.Gerrit
+	templateIDs  map[string]string
+	calls        []string
+}
+
+func newConfigBackend() *configBackend {
+	return &configBackend{
+		claGroups:   map[string]*v1Models.ClaGroup{},
+		projects:    map[string][]string{},
+		orgs:        map[string]*models.ProjectGithubOrganization{},
+		orgProjects: map[string]string{},
+		templateIDs: map[string]string{},
+	}
+}
+
+func (b *configBackend) GetCLAGroupByID(ctx context.Context, claGroupID string) (*v1Models.ClaGroup, error) {
+	claGroup, ok := b.claGroups[claGroupID]
+	if !ok {
+		return nil, nil
+	}
+	out := *claGroup
+	return &out, nil
+}
+
+func (b *configBackend) GetCLAGroupByName(ctx context.Context, claGroupName string) (*v1Models.ClaGroup, error) {
+	for _, claGroup := range b.claGroups {
+		if claGroup.ProjectName == claGroupName {
+			out := *claGroup
+			return &out, nil
+		}
+	}
+	return nil, nil
+}
+
+func (b *configBackend) UpdateCLAGroup(ctx context.Context, claGroupModel *v1Models.ClaGroup) (*v1Models.ClaGroup, error) {
+	b.calls = append(b.calls, "UpdateCLAGroup")
+	updated := *claGroupModel
+	b.claGroups[claGroupModel.ProjectID] = &updated
+	return claGroupModel, nil
+}
+
+func (b *configBackend) CreateCLAGroup(ctx context.Context, authUser *auth.User, input *models.CreateClaGroupInput, projectManagerLFID string) (*models.ClaGroupSummary, error) {
+	b.calls = append(b.calls, "CreateCLAGroup")
+	for _, metaField := range input.TemplateFields.MetaFields {
+		if metaField.Value == "" || metaField.TemplateVariable == "" {
+			return nil, errors.New("bad request: required fields for template were not found")
+		}
+	}
+	claGroupID := fmt.Sprintf("cla-group-%d", len(b.claGroups)+1)
+	b.claGroups[claGroupID] = &v1Models.ClaGroup{
+		ProjectID:               claGroupID,
+		ProjectName:             utils.StringValue(input.ClaGroupName),
+		ProjectDescription:      input.ClaGroupDescription,
+		FoundationSFID:          utils.StringValue(input.FoundationSfid),
+		ProjectExternalID:       utils.StringValue(input.FoundationSfid),
+		ProjectICLAEnabled:      utils.BoolValue(input.IclaEnabled),
+		ProjectCCLAEnabled:      utils.BoolValue(input.CclaEnabled),
+		ProjectCCLARequiresICLA: utils.BoolValue(input.CclaRequiresIcla),
+		ProjectTemplateID:       input.TemplateFields.TemplateID,
+	}
+	b.projects[claGroupID] = append([]string{}, input.ProjectSfidList...)
+	return &models.ClaGroupSummary{ClaGroupID: claGroupID}, nil
+}
+
+func (b *configBackend) EnrollProjectsInClaGroup(ctx context.Context, request *cla_groups.EnrollProjectsModel) error {
+	b.calls = append(b.calls, "EnrollProjectsInClaGroup")
+	b.projects[request.CLAGroupID] = append(b.projects[request.CLAGroupID], request.ProjectSFIDList...)
+	return nil
+}
+
+func (b *configBackend) UnenrollProjectsInClaGroup(ctx context.Context, request *cla_groups.UnenrollProjectsModel) error {
+	b.calls = append(b.calls, "UnenrollProjectsInClaGroup")
+	var projects []string
+	for _, projectSFID := range b.projects[request.CLAGroupID] {
+		if projectSFID != request.ProjectSFIDList[0] {
+			projects = append(projects, projectSFID)
+		}
+	}
+	b.projects[request.CLAGroupID] = projects
+	return nil
+}
+
+func (b *configBackend) GetProjectsIdsForClaGroup(ctx context.Context, claGroupID string) ([]*projects_cla_groups.ProjectClaGroup, error) {
+	var out []*projects_cla_groups.ProjectClaGroup
+	for _, projectSFID := range b.projects[claGroupID] {
+		out = append(out, &projects_cla_groups.ProjectClaGroup{ProjectSFID: projectSFID, ClaGroupID: claGroupID})
+	}
+	return out, nil
+}
+
+func (b *configBackend) GetGithubOrganizations(ctx context.Context, projectSFID string) (*models.ProjectGithubOrganizations, error) {
+	out := &models.ProjectGithubOrganizations{}
+	for name, org := range b.orgs {
+		if b.orgProjects[name] != projectSFID {
+			continue
+		}
+		result := *org
+		result.Repositories = nil
+		for _, repository := range b.repositories {
+			if repository.orgName != name {
+				continue
+			}
+			result.Repositories = append(result.Repositories, &models.ProjectGithubRepository{
+				RepositoryID:       repository.id,
+				RepositoryGithubID: repository.githubID,
+				RepositoryName:     repository.name,
+				ClaGroupID:         repository.claGroupID,
+				ProjectID:          repository.projectSFID,
+				Enabled:            repository.enabled,
+			})
+		}
+		out.List = append(out.List, &result)
+	}
+	return out, nil
+}
+
+func (b *configBackend) AddGithubOrganization(ctx context.Context, projectSFID string, input *models.CreateGithubOrganization) (*models.GithubOrganization, error) {
+	b.calls = append(b.calls, "AddGithubOrganization")
+	name := utils.StringValue(input.OrganizationName)
+	b.orgs[name] = &models.ProjectGithubOrganization{
+		GithubOrganizationName:  name,
+		AutoEnabled:             utils.BoolValue(input.AutoEnabled),
+		AutoEnableCLAGroupID:    input.AutoEnabledClaGroupID,
+		BranchProtectionEnabled: utils.BoolValue(input.BranchProtectionEnabled),
+	}
+	b.orgProjects[name] = projectSFID
+	return &models.GithubOrganization{}, nil
+}
+
+func (b *configBackend) DeleteGithubOrganization(ctx context.Context, projectSFID string, githubOrgName string) error {
+	b.calls = append(b.calls, "DeleteGithubOrganization")
+	delete(b.orgs, githubOrgName)
+	return nil
+}
+
+func (b *configBackend) UpdateGithubOrganization(ctx context.Context, projectSFID string, organizationName string, autoEnabled bool, autoEnabledClaGroupID string, branchProtectionEnabled bool, branchProtectionPolicy *models.GithubBranchProtectionPolicy) error {
+	b.calls = append(b.calls, "UpdateGithubOrganization")
+	org := b.orgs[organizationName]
+	org.AutoEnabled = autoEnabled
+	org.AutoEnableCLAGroupID = autoEnabledClaGroupID
+	org.BranchProtectionEnabled = branchProtectionEnabled
+	return nil
+}
+
+func (b *configBackend) AddGithubRepositories(ctx context.Context, projectSFID string, input *models.GithubRepositoryInput) ([]*v1Models.GithubRepository, error) {
+	b.calls = append(b.calls, "AddGithubRepositories")
+	var out []*v1Models.GithubRepository
+	for _, githubID := range input.RepositoryGithubIds {
+		for _, repository := range b.repositories {
+			if strconv.FormatInt(repository.githubID, 10) != githubID {
+				continue
+			}
+			repository.id = "repository-" + githubID
+			repository.claGroupID = utils.StringValue(input.ClaGroupID)
+			repository.projectSFID = projectSFID
+			repository.enabled = true
+			out = append(out, &v1Models.GithubRepository{
+				RepositoryID:   repository.id,
+				RepositoryName: repository.name,
+				ProjectSFID:    projectSFID,
+			})
+		}
+	}
+	return out, nil
+}
+
+func (b *configBackend) DisableRepository(ctx context.Context, repositoryID string) error {
+	b.calls = append(b.calls, "DisableRepository")
+	for _, repository := range b.repositories {
+		if repository.id == repositoryID {
+			repository.enabled = false
+		}
+	}
+	return nil
+}
+
+func (b *configBackend) repository(projectSFID, repositoryID string) (*configBackendRepository, error) {
+	for _, repository := range b.repositories {
+		if repository.id == repositoryID && repository.projectSFID == projectSFID {
+			return repository, nil
+		}
+	}
+	return nil, fmt.Errorf("github repository %s doesn't belong to project : %s", repositoryID, projectSFID)
+}
+
+func (b *configBackend) GetProtectedBranch(ctx context.Context, projectSFID, repositoryID, branchName string) (*models.GithubRepositoryBranchProtection, error) {
+	repository, err := b.repository(projectSFID, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	if repository.protection == nil {
+		return &models.GithubRepositoryBranchProtection{
+			BranchName: &branchName,
+			StatusChecks: []*models.GithubRepositoryBranchProtectionStatusChecks{
+				{Name: utils.StringRef("EasyCLA"), Enabled: utils.Bool(false)},
+			},
+		}, nil
+	}
+	return repository.protection, nil
+}
+
+func (b *configBackend) UpdateProtectedBranch(ctx context.Context, projectSFID, repositoryID string, input *models.GithubRepositoryBranchProtectionInput) (*models.GithubRepositoryBranchProtection, error) {
+	b.calls = append(b.calls, "UpdateProtectedBranch")
+	repository, err := b.repository(projectSFID, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	repository.protection = &models.GithubRepositoryBranchProtection{
+		BranchName:        utils.StringRef(input.BranchName),
+		ProtectionEnabled: true,
+		EnforceAdmin:      utils.BoolValue(input.EnforceAdmin),
+		StatusChecks:      input.StatusChecks,
+	}
+	return repository.protection, nil
+}
+
+func (b *configBackend) GetClaGroupGerrits(ctx context.Context, claGroupID string) (*v1Models.GerritList, error) {
+	out := &v1Models.GerritList{}
+	for _, gerritModel := range b.gerrits {
+		if gerritModel.ProjectID == claGroupID {
+			out.List = append(out.List, gerritModel)
+		}
+	}
+	return out, nil
+}
+
+func (b *configBackend) AddGerrit(ctx context.Context, claGroupID string, projectSFID string, input *v1Models.AddGerritInput, claGroupModel *v1Models.ClaGroup) (*v1Models.Gerrit, error) {
+	b.calls = append(b.calls, "AddGerrit")
+	gerritModel := &v1Models.Gerrit{
+		GerritID:         strfmt.UUID4(fmt.Sprintf("gerrit-%d", len(b.gerrits)+1)),
+		GerritName:       utils.StringValue(input.GerritName),
+		GerritURL:        strfmt.URI(utils.StringValue(input.GerritURL)),
+		GroupIDIcla:      input.GroupIDIcla,
+		GroupIDCcla:      input.GroupIDCcla,
+		GroupSyncBackend: input.GroupSyncBackend,
+		ProjectSFID:      projectSFID,
+		ProjectID:        claGroupID,
+	}
+	b.gerrits = append(b.gerrits, gerritModel)
+	return gerritModel, nil
+}
+
+func (b *configBackend) DeleteGerrit(ctx context.Context, gerritID string) error {
+	b.calls = append(b.calls, "DeleteGerrit")
+	var gerritList []*v1Models.Gerrit
+	for _, gerritModel := range b.gerrits {
+		if gerritModel.GerritID.String() != gerritID {
+			gerritList = append(gerritList, gerritModel)
+		}
+	}
+	b.gerrits = gerritList
+	return nil
+}
+
+func (b *configBackend) GetTemplates(ctx context.Context) ([]v1Models.Template, error) {
+	return []v1Models.Template{
+		{
+			ID:   template.ApacheStyleTemplateID,
+			Name: "Apache Style",
+			MetaFields: []*v1Models.MetaField{
+				{Name: "Project Name", TemplateVariable: "PROJECT_NAME"},
+			},
+		},
+	}, nil
+}
+
+func (b *configBackend) CreateCLAGroupTemplate(ctx context.Context, claGroupID string, claGroupFields *v1Models.CreateClaGroupTemplate) (v1Models.TemplatePdfs, error) {
+	b.calls = append(b.calls, "CreateCLAGroupTemplate")
+	b.claGroups[claGroupID].ProjectTemplateID = claGroupFields.TemplateID
+	return v1Models.TemplatePdfs{}, nil
+}
+
+// changeSummary returns the changes as "action resource name" entries
+func changeSummary(plan *models.ClaGroupConfigPlan) []string {
+	var out []string
+	for _, change := range plan.Changes {
+		out = append(out, fmt.Sprintf("%s %s %s", change.Action, change.Resource, change.Name))
+	}
+	sort.Strings(out)
+	return out
+}
+
+const testCLAGroupManifest = `
+version: easycla/v1
+cla_group:
+  name: Example CLA Group
+  description: the CLA Group of the example projects
+  foundation_sfid: foundation-1
+  icla_enabled: true
+  ccla_enabled: true
+  project_sfid_list:
+    - project-1
+  template:
+    fields:
+      Project Name: Example
+github_organizations:
+  - name: example-org
+    project_sfid: foundation-1
+    auto_enabled: true
+    branch_protection_enabled: true
+    repositories:
+      - name: example-org/repo-1
+        github_id: "101"
+        branch_protection:
+          status_checks:
+            - EasyCLA
+gerrits:
+  - name: Example Gerrit
+    url: https://review.example.org/r
+    group_id_icla: example-icla
+    group_id_ccla: example-ccla
+    group_sync_backend: gerrit
+`
+
+func TestCLAGroupConfig(t *testing.T) {
+	ctx := utils.NewContext()
+	authUser := &auth.User{UserName: "project-manager", Email: "pm@example.org"}
+	backend := newConfigBackend()
+	// the repositories the GitHub app can see
+	backend.repositories = []*configBackendRepository{
+		{githubID: 101, name: "example-org/repo-1", orgName: "example-org"},
+		{githubID: 102, name: "example-org/repo-2", orgName: "example-org"},
+	}
+	service := cla_group_config.NewService(backend, backend, backend, backend, backend, backend, backend, backend)
+
+	manifest, err := cla_group_config.ParseManifest([]byte(testCLAGroupManifest))
+	assert.NoError(t, err)
+
+	// the plan of a new CLA Group changes nothing
+	plan, err := service.Plan(ctx, authUser, manifest)
+	assert.NoError(t, err)
+	assert.False(t, plan.Applied)
+	assert.Empty(t, plan.ClaGroupID)
+	assert.Equal(t, []string{
+		"create branch-protection example-org/repo-1:main",
+		"create cla-group Example CLA Group",
+		"create gerrit Example Gerrit",
+		"create github-organization example-org",
+		"create github-repository example-org/repo-1",
+	}, changeSummary(plan))
+	for _, change := range plan.Changes {
+		assert.Equal(t, cla_group_config.StatusPlanned, change.Status)
+	}
+	assert.Empty(t, backend.calls)
+
+	// apply creates the CLA Group and attaches everything to it
+	plan, err = service.Apply(ctx, authUser, manifest)
+	assert.NoError(t, err)
+	assert.True(t, plan.Applied)
+	assert.Equal(t, "cla-group-1", plan.ClaGroupID)
+	for _, change := range plan.Changes {
+		assert.Equal(t, cla_group_config.StatusApplied, change.Status, "%s %s: %s", change.Resource, change.Name, change.Error)
+	}
+	assert.Equal(t, []string{"project-1"}, backend.projects["cla-group-1"])
+	assert.Equal(t, "cla-group-1", backend.orgs["example-org"].AutoEnableCLAGroupID)
+	assert.True(t, backend.repositories[0].enabled)
+	assert.False(t, backend.repositories[1].enabled)
+	if assert.NotNil(t, backend.repositories[0].protection) {
+		assert.Equal(t, []string{"EasyCLA"}, []string{utils.StringValue(backend.repositories[0].protection.StatusChecks[0].Name)})
+	}
+	assert.Len(t, backend.gerrits, 1)
+
+	// applying the same manifest again makes no change
+	calls := len(backend.calls)
+	plan, err = service.Apply(ctx, authUser, manifest)
+	assert.NoError(t, err)
+	assert.Empty(t, plan.Changes)
+	assert.Len(t, backend.calls, calls)
+
+	// the exported manifest describes the current state and plans no change
+	exported, err := service.Export(ctx, "cla-group-1")
+	assert.NoError(t, err)
+	assert.Equal(t, cla_group_config.ManifestVersion, exported.Version)
+	assert.Equal(t, "Example CLA Group", exported.ClaGroup.Name)
+	assert.Equal(t, []string{"project-1"}, exported.ClaGroup.ProjectSfidList)
+	assert.Equal(t, template.ApacheStyleTemplateID, exported.ClaGroup.Template.TemplateID)
+	if assert.Len(t, exported.GithubOrganizations, 1) && assert.Len(t, exported.GithubOrganizations[0].Repositories, 1) {
+		repository := exported.GithubOrganizations[0].Repositories[0]
+		assert.Equal(t, "101", repository.GithubID)
+		if assert.NotNil(t, repository.BranchProtection) {
+			assert.Equal(t, []string{"EasyCLA"}, repository.BranchProtection.StatusChecks)
+		}
+	}
+	data, err := cla_group_config.FormatManifest(exported, cla_group_config.FormatYAML)
+	assert.NoError(t, err)
+	roundTrip, err := cla_group_config.ParseManifest(data)
+	assert.NoError(t, err)
+	assert.Equal(t, exported, roundTrip)
+	plan, err = service.Plan(ctx, authUser, roundTrip)
+	assert.NoError(t, err)
+	assert.Empty(t, plan.Changes)
+
+	// the resources missing from the manifest are only removed when the manifest prunes
+	manifest.ClaGroup.Description = "the updated description"
+	manifest.ClaGroup.ProjectSfidList = []string{"project-2"}
+	manifest.Gerrits = nil
+	plan, err = service.Plan(ctx, authUser, manifest)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"create project project-2",
+		"unmanaged gerrit Example Gerrit",
+		"unmanaged project project-1",
+		"update cla-group Example CLA Group",
+	}, changeSummary(plan))
+
+	manifest.Prune = true
+	plan, err = service.Apply(ctx, authUser, manifest)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"create project project-2",
+		"delete gerrit Example Gerrit",
+		"delete project project-1",
+		"update cla-group Example CLA Group",
+	}, changeSummary(plan))
+	assert.Equal(t, "the updated description", backend.claGroups["cla-group-1"].ProjectDescription)
+	assert.Equal(t, []string{"project-2"}, backend.projects["cla-group-1"])
+	assert.Empty(t, backend.gerrits)
+
+	// the manifest of another foundation is rejected
+	manifest.ClaGroup.FoundationSfid = "foundation-2"
+	_, err = service.Plan(ctx, authUser, manifest)
+	assert.True(t, errors.Is(err, cla_group_config.ErrInvalidManifest))
+
+	_, err = cla_group_config.ParseManifest([]byte("cla_group:\n  name: Example CLA Group\n"))
+	assert.True(t, errors.Is(err, cla_group_config.ErrInvalidManifest))
+
+	_, err = service.Export(ctx, "unknown")
+	assert.True(t, errors.Is(err, cla_group_config.ErrCLAGroupNotFound))
+}
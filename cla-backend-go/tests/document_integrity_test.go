@@ -0,0 +1,108 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	signatureService "github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryDocumentStorage keeps the signed documents by file name
+type memoryDocumentStorage struct {
+	files map[string][]byte
+}
+
+func (s *memoryDocumentStorage) Upload(fileContent []byte, projectID string, claType string, identifier string, signatureID string) (string, error) {
+	s.files[utils.SignedCLAFilename(projectID, claType, identifier, signatureID)] = fileContent
+	return utils.DocumentSHA256(fileContent), nil
+}
+
+func (s *memoryDocumentStorage) Download(filename string) ([]byte, error) {
+	content, ok := s.files[filename]
+	if !ok {
+		return nil, utils.ErrS3ObjectNotFound
+	}
+	return content, nil
+}
+
+func (s *memoryDocumentStorage) Delete(filename string) error {
+	delete(s.files, filename)
+	return nil
+}
+
+func (s *memoryDocumentStorage) GetPresignedURL(filename string) (string, error) {
+	return filename, nil
+}
+
+func TestDocumentIntegrityVerification(t *testing.T) {
+	ctx := utils.NewContext()
+	eventsService := events.NewService(events.NewMemoryRepository(), events.NewMockRepository())
+	repo := signatureService.NewMemoryRepository(company.NewMemoryRepository(), users.NewMemoryRepository(), eventsService, nil, nil, nil)
+	storage := &memoryDocumentStorage{files: map[string][]byte{}}
+	integrityService := signatureService.NewDocumentIntegrityService(repo, storage, eventsService)
+
+	claGroupID := "cla-group-" + uniqueTestID(t)
+	hashedSignatureID := uniqueTestID(t) + "-hashed"
+	legacySignatureID := uniqueTestID(t) + "-legacy"
+	hash, err := storage.Upload([]byte("signed ccla"), claGroupID, utils.ClaTypeCCLA, "company-1", hashedSignatureID)
+	assert.NoError(t, err)
+	_, err = storage.Upload([]byte("signed icla"), claGroupID, utils.ClaTypeICLA, "user-1", legacySignatureID)
+	assert.NoError(t, err)
+
+	assert.NoError(t, repo.PutSignature(ctx, signatureService.ItemSignature{
+		SignatureID:             hashedSignatureID,
+		SignatureProjectID:      claGroupID,
+		SignatureReferenceID:    "company-1",
+		SignatureReferenceType:  utils.SignatureReferenceTypeCompany,
+		SignatureType:           utils.SignatureTypeCCLA,
+		SignatureSigned:         true,
+		SignatureApproved:       true,
+		SignatureDocumentSHA256: hash,
+	}))
+	// signed before the hashes were recorded
+	assert.NoError(t, repo.PutSignature(ctx, signatureService.ItemSignature{
+		SignatureID:            legacySignatureID,
+		SignatureProjectID:     claGroupID,
+		SignatureReferenceID:   "user-1",
+		SignatureReferenceType: utils.SignatureReferenceTypeUser,
+		SignatureType:          utils.SignatureTypeCLA,
+		SignatureSigned:        true,
+		SignatureApproved:      true,
+	}))
+
+	now := time.Now().UTC()
+	report, err := integrityService.VerifySignatureDocuments(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, &signatureService.DocumentIntegrityReport{Checked: 2, Verified: 1, Baselined: 1}, report)
+
+	// the documents were verified recently
+	report, err = integrityService.VerifySignatureDocuments(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, report.Checked)
+
+	// the baseline hash detects the changes of the legacy document
+	_, err = storage.Upload([]byte("tampered icla"), claGroupID, utils.ClaTypeICLA, "user-1", legacySignatureID)
+	assert.NoError(t, err)
+	result, err := integrityService.VerifySignatureDocument(ctx, legacySignatureID)
+	assert.NoError(t, err)
+	assert.Equal(t, signatureService.DocumentIntegrityModified, result.Status)
+	assert.NotEqual(t, result.ExpectedSHA256, result.ActualSHA256)
+
+	assert.NoError(t, storage.Delete(utils.SignedCLAFilename(claGroupID, utils.ClaTypeCCLA, "company-1", hashedSignatureID)))
+	report, err = integrityService.VerifySignatureDocuments(ctx, now.Add(signatureService.DocumentIntegrityReverifyPeriod+time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, report.Failed)
+
+	sig, err := repo.GetSignature(ctx, hashedSignatureID)
+	assert.NoError(t, err)
+	assert.Equal(t, signatureService.DocumentIntegrityMissing, sig.SignatureDocumentIntegrityStatus)
+	assert.Equal(t, hash, sig.SignatureDocumentSha256)
+}
@@ -0,0 +1,253 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/config"
+	"github.com/communitybridge/easycla/cla-backend-go/emails"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// parseEmailMessage returns the headers and the decoded parts of the multipart/alternative message keyed by content type
+func parseEmailMessage(t *testing.T, raw []byte) (mail.Header, map[string]string) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if !assert.NoError(t, err) {
+		return nil, nil
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	assert.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	parts := map[string]string{}
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, partErr := reader.NextPart()
+		if partErr == io.EOF {
+			break
+		}
+		if !assert.NoError(t, partErr) {
+			break
+		}
+		content, readErr := ioutil.ReadAll(part)
+		assert.NoError(t, readErr)
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		// quoted-printable keeps the CRLF line breaks of the message
+		parts[contentType] = strings.ReplaceAll(string(content), "\r\n", "\n")
+	}
+	return msg.Header, parts
+}
+
+func TestFileEmailSenderCapturesTemplates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "easycla-emails")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	sender, err := utils.NewFileEmailSender(dir, "EasyCLA <admin@easycla.example.org>")
+	assert.NoError(t, err)
+	utils.SetEmailSender(sender)
+	defer utils.SetEmailSender(&utils.MockEmailSender{})
+
+	approvalBody, err := emails.RenderTemplate(utils.V2, emails.ApprovalListApprovedTemplateName, emails.ApprovalListApprovedTemplate,
+		emails.ApprovalListApprovedTemplateParams{
+			CommonEmailParams: emails.CommonEmailParams{RecipientName: "Recipient", CompanyName: "CompanyFoo"},
+			CLAGroupTemplateParams: emails.CLAGroupTemplateParams{
+				CLAGroupName: "CLAGroupFoo",
+				Projects:     []emails.CLAProjectParams{{ExternalProjectName: "Project1"}},
+			},
+			Approver: "LFUsername",
+		})
+	assert.NoError(t, err)
+	assert.NoError(t, utils.SendEmail("EasyCLA: Approval List Update for CLAGroupFoo", approvalBody, []string{"contributor@example.org"}))
+
+	managerBody, err := emails.RenderTemplate(utils.V1, emails.RequestAccessToCLAManagersTemplateName, emails.RequestAccessToCLAManagersTemplate,
+		emails.RequestAccessToCLAManagersTemplateParams{
+			CommonEmailParams: emails.CommonEmailParams{RecipientName: "Manager", CompanyName: "CompanyFoo"},
+			CLAGroupTemplateParams: emails.CLAGroupTemplateParams{
+				Projects:         []emails.CLAProjectParams{{ExternalProjectName: "Project1"}},
+				CorporateConsole: "https://corporate.example.org",
+			},
+			RequesterName:  "Jane",
+			RequesterEmail: "jane@example.org",
+		})
+	assert.NoError(t, err)
+	assert.NoError(t, utils.SendEmail("EasyCLA: New CLA Manager Access Request", managerBody, []string{"manager1@example.org", "manager2@example.org"}))
+
+	messages := sender.Messages()
+	if !assert.Len(t, messages, 2) {
+		return
+	}
+	assert.Equal(t, []string{"contributor@example.org"}, messages[0].Recipients)
+	assert.Regexp(t, `^\d{8}T\d{6}\.\d{9}Z-easycla-approval-list-update-for-clagroupfoo\.eml$`, filepath.Base(messages[0].Path))
+
+	raw, err := ioutil.ReadFile(messages[0].Path)
+	assert.NoError(t, err)
+	header, parts := parseEmailMessage(t, raw)
+	assert.Equal(t, "EasyCLA <admin@easycla.example.org>", header.Get("From"))
+	assert.Equal(t, "contributor@example.org", header.Get("To"))
+	assert.Contains(t, parts["text/html"], "You have been added to the Approval list of CompanyFoo for CLAGroupFoo by CLA Manager LFUsername.")
+	assert.Contains(t, parts["text/plain"], "You have been added to the Approval list of CompanyFoo for CLAGroupFoo by CLA Manager LFUsername.")
+	assert.NotContains(t, parts["text/plain"], "<p>")
+
+	raw, err = ioutil.ReadFile(messages[1].Path)
+	assert.NoError(t, err)
+	header, parts = parseEmailMessage(t, raw)
+	assert.Equal(t, "manager1@example.org, manager2@example.org", header.Get("To"))
+	assert.Contains(t, parts["text/html"], "Jane (jane@example.org) has requested to be added as another CLA Manager from CompanyFoo for Project1.")
+	assert.Contains(t, parts["text/html"], `<a href="https://corporate.example.org" target="_blank">`)
+}
+
+func TestFileEmailSenderKeepsTheEmailsOfEarlierRuns(t *testing.T) {
+	dir, err := ioutil.TempDir("", "easycla-emails")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// a new sender on the same capture directory, as after a restart, doesn't overwrite the captured emails
+	var paths []string
+	for i := 0; i < 3; i++ {
+		sender, senderErr := utils.NewFileEmailSender(dir, "EasyCLA <admin@easycla.example.org>")
+		if !assert.NoError(t, senderErr) {
+			return
+		}
+		assert.NoError(t, sender.SendEmail("EasyCLA: Signature Reminder", fmt.Sprintf("<p>reminder %d</p>", i), []string{"contributor@example.org"}))
+		messages := sender.Messages()
+		if assert.Len(t, messages, 1) {
+			paths = append(paths, messages[0].Path)
+		}
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 3)
+	for i, path := range paths {
+		raw, readErr := ioutil.ReadFile(path)
+		if assert.NoError(t, readErr) {
+			_, parts := parseEmailMessage(t, raw)
+			assert.Contains(t, parts["text/html"], fmt.Sprintf("reminder %d", i))
+		}
+	}
+}
+
+// fakeSMTPServer is a minimal SMTP server accepting a single message without TLS
+type fakeSMTPServer struct {
+	listener   net.Listener
+	auth       string
+	from       string
+	recipients []string
+	data       []byte
+	done       chan struct{}
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	server := &fakeSMTPServer{listener: listener, done: make(chan struct{})}
+	go server.serve()
+	return server
+}
+
+func (s *fakeSMTPServer) serve() {
+	defer close(s.done)
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) {
+		_, _ = conn.Write([]byte(line + "\r\n"))
+	}
+	reply("220 localhost ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch command {
+		case "EHLO", "HELO":
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			s.auth = line
+			reply("235 2.7.0 Authentication successful")
+		case "MAIL":
+			s.from = line
+			reply("250 OK")
+		case "RCPT":
+			s.recipients = append(s.recipients, line)
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data bytes.Buffer
+			for {
+				dataLine, dataErr := reader.ReadString('\n')
+				if dataErr != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(dataLine, "."))
+			}
+			s.data = data.Bytes()
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func TestSMTPEmailSender(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	defer server.listener.Close()
+
+	_, port, err := net.SplitHostPort(server.listener.Addr().String())
+	assert.NoError(t, err)
+	portNumber, err := strconv.Atoi(port)
+	assert.NoError(t, err)
+
+	sender := utils.NewSMTPEmailSender(config.SMTP{
+		Host:     "127.0.0.1",
+		Port:     portNumber,
+		Username: "easycla",
+		Password: "secret",
+		TLSMode:  config.SMTPTLSModeNone,
+	}, "EasyCLA <admin@easycla.example.org>")
+
+	body := "<p>Hello Recipient,</p><p>Welcome &amp; thanks.</p>"
+	assert.NoError(t, sender.SendEmail("EasyCLA: Test", body, []string{"user1@example.org", "user2@example.org"}))
+	<-server.done
+
+	assert.True(t, strings.HasPrefix(server.auth, "AUTH PLAIN "))
+	assert.Equal(t, "MAIL FROM:<admin@easycla.example.org>", server.from)
+	assert.Equal(t, []string{"RCPT TO:<user1@example.org>", "RCPT TO:<user2@example.org>"}, server.recipients)
+
+	header, parts := parseEmailMessage(t, server.data)
+	assert.Equal(t, "EasyCLA: Test", header.Get("Subject"))
+	assert.Equal(t, body, parts["text/html"])
+	assert.Equal(t, "Hello Recipient,\nWelcome & thanks.\n", parts["text/plain"])
+}
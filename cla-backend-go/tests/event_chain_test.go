@@ -0,0 +1,199 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	chainTestCLAGroupID = "d5412fa7-0a29-4a6b-a0d1-6a8d4e6ebf4c"
+	chainTestCompanyID  = "0b1e0a4c-8d3b-4b55-9a3e-1d3c2a4f5e6d"
+)
+
+func createChainTestEvents(t *testing.T, repo events.Repository) {
+	for i := 1; i <= 4; i++ {
+		assert.NoError(t, repo.CreateEvent(&models.Event{
+			EventType:       events.ClaManagerCreated,
+			UserID:          "user-1234",
+			EventCLAGroupID: chainTestCLAGroupID,
+			EventCompanyID:  chainTestCompanyID,
+			EventData:       fmt.Sprintf("cla manager added %d", i),
+		}))
+	}
+	assert.NoError(t, repo.CreateEvent(&models.Event{
+		EventType:      events.CompanyACLUserAdded,
+		UserID:         "user-1234",
+		EventCompanyID: chainTestCompanyID,
+		EventData:      "company manager added",
+	}))
+	assert.NoError(t, repo.CreateEvent(&models.Event{
+		EventType: events.UserCreated,
+		UserID:    "user-1234",
+		EventData: "user created",
+	}))
+}
+
+func chainTestEvents(t *testing.T, repo events.Repository, stream string) ([]*models.Event, *events.ChainHead) {
+	chained, err := repo.GetChainEvents(stream, 0, events.HugePageSize)
+	assert.NoError(t, err)
+	head, err := repo.GetChainHead(stream)
+	assert.NoError(t, err)
+	return chained, head
+}
+
+func TestEventChainLinksTheStreams(t *testing.T) {
+	repo := events.NewMemoryRepository()
+	createChainTestEvents(t, repo)
+
+	claGroupStream, err := events.ChainStreamKey(events.ChainStreamTypeCLAGroup, chainTestCLAGroupID)
+	assert.NoError(t, err)
+	chained, head := chainTestEvents(t, repo, claGroupStream)
+	if !assert.Len(t, chained, 4) {
+		return
+	}
+	prevHash := events.ChainGenesisHash
+	for i, event := range chained {
+		assert.Equal(t, claGroupStream, event.EventChainStream)
+		assert.Equal(t, int64(i+1), event.EventChainSequence)
+		assert.Equal(t, prevHash, event.EventPrevHash)
+		assert.Equal(t, events.ChainEventHash(event), event.EventHash)
+		prevHash = event.EventHash
+	}
+	assert.Equal(t, int64(4), head.Sequence)
+	assert.Equal(t, prevHash, head.HeadHash)
+
+	// the company events of a CLA group are chained in the CLA group stream
+	companyStream, err := events.ChainStreamKey(events.ChainStreamTypeCompany, chainTestCompanyID)
+	assert.NoError(t, err)
+	companyEvents, _ := chainTestEvents(t, repo, companyStream)
+	if assert.Len(t, companyEvents, 1) {
+		assert.Equal(t, "company manager added", companyEvents[0].EventData)
+		assert.Equal(t, events.ChainGenesisHash, companyEvents[0].EventPrevHash)
+	}
+
+	heads, err := repo.GetChainHeads()
+	assert.NoError(t, err)
+	assert.Len(t, heads, 2)
+
+	_, err = events.ChainStreamKey("user", "user-1234")
+	assert.Equal(t, events.ErrInvalidChainStreamType, err)
+}
+
+func TestEventChainVerification(t *testing.T) {
+	repo := events.NewMemoryRepository()
+	createChainTestEvents(t, repo)
+	service := events.NewService(repo, nil)
+
+	result, err := service.VerifyEventChain(utils.NewContext(), events.ChainStreamTypeCLAGroup, chainTestCLAGroupID)
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, int64(4), result.EventsVerified)
+	assert.Equal(t, int64(4), result.HeadSequence)
+	assert.Empty(t, result.Breaks)
+
+	// a stream without events is valid
+	result, err = service.VerifyEventChain(utils.NewContext(), events.ChainStreamTypeCompany, "no-events")
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, int64(0), result.EventsVerified)
+
+	stream, _ := events.ChainStreamKey(events.ChainStreamTypeCLAGroup, chainTestCLAGroupID)
+	chained, head := chainTestEvents(t, repo, stream)
+
+	t.Run("altered event", func(t *testing.T) {
+		altered := copyChainEvents(chained)
+		altered[1].EventData = "cla manager removed"
+		result := events.VerifyChainEvents(stream, altered, nil, head, "")
+		assert.False(t, result.Valid)
+		if assert.Len(t, result.Breaks, 1) {
+			assert.Equal(t, int64(2), result.Breaks[0].Sequence)
+			assert.Equal(t, altered[1].EventID, result.Breaks[0].EventID)
+			assert.Equal(t, "event content does not match its hash", result.Breaks[0].Reason)
+		}
+	})
+
+	t.Run("rehashed event", func(t *testing.T) {
+		// recomputing the hash of the altered event breaks the link of the next event
+		altered := copyChainEvents(chained)
+		altered[1].EventData = "cla manager removed"
+		altered[1].EventHash = events.ChainEventHash(altered[1])
+		result := events.VerifyChainEvents(stream, altered, nil, head, "")
+		assert.False(t, result.Valid)
+		if assert.Len(t, result.Breaks, 1) {
+			assert.Equal(t, int64(3), result.Breaks[0].Sequence)
+			assert.Equal(t, "previous hash does not match the previous event", result.Breaks[0].Reason)
+		}
+	})
+
+	t.Run("removed event", func(t *testing.T) {
+		removed := append(copyChainEvents(chained[:1]), copyChainEvents(chained[2:])...)
+		result := events.VerifyChainEvents(stream, removed, nil, head, "")
+		assert.False(t, result.Valid)
+		if assert.Len(t, result.Breaks, 2) {
+			assert.Equal(t, "events 2 to 2 are missing", result.Breaks[0].Reason)
+			assert.Equal(t, "previous hash does not match the previous event", result.Breaks[1].Reason)
+		}
+	})
+
+	t.Run("truncated stream", func(t *testing.T) {
+		result := events.VerifyChainEvents(stream, copyChainEvents(chained[:3]), nil, head, "")
+		assert.False(t, result.Valid)
+		if assert.Len(t, result.Breaks, 1) {
+			assert.Equal(t, int64(4), result.Breaks[0].Sequence)
+			assert.Equal(t, "events 4 to 4 are missing from the end of the stream", result.Breaks[0].Reason)
+		}
+	})
+}
+
+func TestEventChainCheckpoints(t *testing.T) {
+	repo := events.NewMemoryRepository()
+	createChainTestEvents(t, repo)
+	service := events.NewService(repo, nil)
+
+	seed := make([]byte, ed25519.SeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	signingKey, err := events.ParseChainSigningKey(base64.StdEncoding.EncodeToString(seed))
+	assert.NoError(t, err)
+	publicKey := signingKey.Public().(ed25519.PublicKey)
+
+	export, err := service.CreateChainCheckpoints(utils.NewContext(), signingKey)
+	assert.NoError(t, err)
+	if !assert.Len(t, export.Checkpoints, 2) {
+		return
+	}
+	assert.Equal(t, events.ChainCheckpointAlgorithm, export.Algorithm)
+	assert.Equal(t, "cla-group#"+chainTestCLAGroupID, export.Checkpoints[0].Stream)
+	assert.Equal(t, int64(4), export.Checkpoints[0].Sequence)
+	assert.True(t, events.VerifyChainCheckpoints(publicKey, export))
+
+	// a rewritten stream doesn't match the signed checkpoint
+	export.Checkpoints[0].HeadHash = events.ChainGenesisHash
+	assert.False(t, events.VerifyChainCheckpoints(publicKey, export))
+
+	otherKey := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	assert.False(t, events.VerifyChainCheckpoints(otherKey.Public().(ed25519.PublicKey), export))
+
+	_, err = events.ParseChainSigningKey("not a key")
+	assert.Equal(t, events.ErrInvalidChainSigningKey, err)
+}
+
+func copyChainEvents(in []*models.Event) []*models.Event {
+	out := make([]*models.Event, 0, len(in))
+	for _, event := range in {
+		copied := *event
+		out = append(out, &copied)
+	}
+	return out
+}
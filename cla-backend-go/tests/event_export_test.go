@@ -0,0 +1,184 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	exportTestCLAGroupID = "d5412fa7-0a29-4a6b-a0d1-6a8d4e6ebf4c"
+	exportTestCompanyID  = "0b1e0a4c-8d3b-4b55-9a3e-1d3c2a4f5e6d"
+)
+
+func createExportTestEvents(t *testing.T, repo events.Repository) {
+	for i := 1; i <= 7; i++ {
+		companyID := exportTestCompanyID
+		if i%2 == 0 {
+			companyID = "7c3f9a1e-2b4d-4e6f-8a0b-1c2d3e4f5a6b"
+		}
+		assert.NoError(t, repo.CreateEvent(&models.Event{
+			EventType:       events.ClaManagerCreated,
+			UserID:          "user-1234",
+			EventCLAGroupID: exportTestCLAGroupID,
+			EventCompanyID:  companyID,
+			EventData:       fmt.Sprintf("cla manager added %d", i),
+		}))
+	}
+	assert.NoError(t, repo.CreateEvent(&models.Event{
+		EventType:       events.ClaManagerCreated,
+		UserID:          "user-1234",
+		EventCLAGroupID: "another-cla-group",
+		EventData:       "cla manager added to another CLA Group",
+	}))
+}
+
+func TestExportEventsResumesAfterTheCursor(t *testing.T) {
+	repo := events.NewMemoryRepository()
+	createExportTestEvents(t, repo)
+	service := events.NewService(repo, nil)
+	ctx := context.Background()
+	params := &events.ExportParams{CLAGroupID: exportTestCLAGroupID}
+
+	var out bytes.Buffer
+	var cursor *events.ExportCursor
+	var total int64
+	for requests := 0; ; requests++ {
+		if !assert.True(t, requests < 5, "the export doesn't complete") {
+			return
+		}
+		writer, err := events.NewExportWriter(&out, events.ExportFormatJSONLines, cursor == nil)
+		assert.NoError(t, err)
+
+		// the text form of the cursor is what the clients resume with
+		var after *events.ExportCursor
+		if cursor != nil {
+			after, err = events.ParseExportCursor(cursor.String())
+			assert.NoError(t, err)
+			assert.Equal(t, cursor, after)
+		}
+		next, count, err := service.ExportEvents(ctx, params, after, 3, writer)
+		assert.NoError(t, err)
+		total += count
+		if next == nil {
+			break
+		}
+		cursor = next
+	}
+	assert.Equal(t, int64(7), total)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if assert.Len(t, lines, 7) {
+		seen := map[string]bool{}
+		previous := ""
+		for _, line := range lines {
+			var record map[string]interface{}
+			assert.NoError(t, json.Unmarshal([]byte(line), &record))
+			assert.Equal(t, exportTestCLAGroupID, record["cla_group_id"])
+			eventID := record["event_id"].(string)
+			assert.False(t, seen[eventID], eventID)
+			seen[eventID] = true
+			// the events created in the same second are exported by event ID
+			assert.True(t, previous < eventID)
+			previous = eventID
+		}
+	}
+
+	// the company filter and the time range
+	var filtered bytes.Buffer
+	writer, err := events.NewExportWriter(&filtered, events.ExportFormatCSV, true)
+	assert.NoError(t, err)
+	next, count, err := service.ExportEvents(ctx, &events.ExportParams{CLAGroupID: exportTestCLAGroupID, CompanyID: exportTestCompanyID}, nil, 0, writer)
+	assert.NoError(t, err)
+	assert.Nil(t, next)
+	assert.Equal(t, int64(4), count)
+	records, err := csv.NewReader(&filtered).ReadAll()
+	assert.NoError(t, err)
+	if assert.Len(t, records, 5) {
+		assert.Equal(t, "event_id", records[0][0])
+	}
+
+	_, count, err = service.ExportEvents(ctx, &events.ExportParams{CLAGroupID: exportTestCLAGroupID, After: 1 << 40}, nil, 0, writer)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	_, _, err = service.ExportEvents(ctx, &events.ExportParams{}, nil, 0, writer)
+	assert.Equal(t, events.ErrInvalidExportScope, err)
+	_, _, err = service.ExportEvents(ctx, &events.ExportParams{FoundationSFID: "a0941000002wBz4AAE", After: 20, Before: 10}, nil, 0, writer)
+	assert.Equal(t, events.ErrInvalidExportRange, err)
+	_, err = events.ParseExportCursor("not a cursor")
+	assert.Equal(t, events.ErrInvalidExportCursor, err)
+}
+
+func TestExportEventsMatchEitherCompanyID(t *testing.T) {
+	repo := events.NewMemoryRepository()
+	service := events.NewService(repo, nil)
+	ctx := context.Background()
+	companySFID := "0014100000Te0yqAAB"
+
+	// the events only record the company IDs known when they were logged
+	for _, event := range []*models.Event{
+		{EventCompanyID: exportTestCompanyID},
+		{EventCompanySFID: companySFID},
+		{EventCompanyID: exportTestCompanyID, EventCompanySFID: companySFID},
+		{EventCompanyID: "7c3f9a1e-2b4d-4e6f-8a0b-1c2d3e4f5a6b", EventCompanySFID: "0014100000Te1AbAAJ"},
+	} {
+		event.EventType = events.ClaManagerCreated
+		event.UserID = "user-1234"
+		event.EventCLAGroupID = exportTestCLAGroupID
+		assert.NoError(t, repo.CreateEvent(event))
+	}
+
+	var out bytes.Buffer
+	writer, err := events.NewExportWriter(&out, events.ExportFormatJSONLines, true)
+	assert.NoError(t, err)
+	_, count, err := service.ExportEvents(ctx, &events.ExportParams{CLAGroupID: exportTestCLAGroupID, CompanyID: exportTestCompanyID, CompanySFID: companySFID}, nil, 0, writer)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	_, count, err = service.ExportEvents(ctx, &events.ExportParams{CLAGroupID: exportTestCLAGroupID, CompanySFID: companySFID}, nil, 0, writer)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestExportEventsSIEMFormats(t *testing.T) {
+	event := &models.Event{
+		EventID:         "event-1",
+		EventType:       "cla_manager.removed",
+		EventTimeEpoch:  1600000000,
+		UserID:          "user-1234",
+		LfUsername:      "jdoe",
+		EventCLAGroupID: exportTestCLAGroupID,
+		EventSummary:    "CLA Manager removed | by admin",
+		EventData:       "removed a=b\nfrom the CLA Group",
+	}
+
+	var cef bytes.Buffer
+	writer, err := events.NewExportWriter(&cef, events.ExportFormatCEF, false)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.WriteEvent(event))
+	assert.NoError(t, writer.Flush())
+	assert.Equal(t, "CEF:0|Linux Foundation|EasyCLA|2|cla_manager.removed|CLA Manager removed \\| by admin|6|"+
+		"rt=1600000000000 externalId=event-1 suid=user-1234 suser=jdoe msg=removed a\\=b\\nfrom the CLA Group "+
+		"cs1Label=claGroupID cs1="+exportTestCLAGroupID+"\n", cef.String())
+
+	var syslog bytes.Buffer
+	writer, err = events.NewExportWriter(&syslog, events.ExportFormatSyslog, false)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.WriteEvent(event))
+	assert.True(t, strings.HasPrefix(syslog.String(), "<108>1 2020-09-13T12:26:40Z easycla easycla - cla_manager.removed - CEF:0|"), syslog.String())
+
+	_, err = events.NewExportWriter(&syslog, "xml", false)
+	assert.Equal(t, events.ErrInvalidExportFormat, err)
+}
@@ -0,0 +1,302 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/config"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/go-openapi/strfmt"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubGerritRepository returns the gerrit instances
+type stubGerritRepository struct {
+	gerrits.Repository
+	gerrits []*models.Gerrit
+}
+
+func (r *stubGerritRepository) GetClaGroupGerrits(ctx context.Context, claGroupID string) (*models.GerritList, error) {
+	var list []*models.Gerrit
+	for _, gerritModel := range r.gerrits {
+		if gerritModel.ProjectID == claGroupID {
+			list = append(list, gerritModel)
+		}
+	}
+	return &models.GerritList{List: list}, nil
+}
+
+func (r *stubGerritRepository) GetAllGerrits(ctx context.Context) (*models.GerritList, error) {
+	return &models.GerritList{List: r.gerrits}, nil
+}
+
+// fakeGerritServer serves the account query and the group members endpoints of the gerrit REST API, the group
+// members are the account IDs
+type fakeGerritServer struct {
+	lock     sync.Mutex
+	accounts []gerrits.AccountInfo
+	groups   map[string]map[int]bool
+}
+
+func (s *fakeGerritServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if username, _, ok := r.BasicAuth(); !ok || username != "easycla" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	path, _ := url.PathUnescape(r.URL.EscapedPath())
+	if path == "/r/a/accounts/" {
+		var matches []gerrits.AccountInfo
+		for _, account := range s.accounts {
+			if r.URL.Query().Get("q") == "email:"+account.Email {
+				matches = append(matches, account)
+			}
+		}
+		body, _ := json.Marshal(matches)
+		fmt.Fprintf(w, ")]}'\n%s", body)
+		return
+	}
+
+	parts := strings.Split(strings.TrimPrefix(path, "/r/a/groups/"), "/members/")
+	members, ok := s.groups[parts[0]]
+	if len(parts) != 2 || !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	accountID, _ := strconv.Atoi(parts[1])
+
+	switch r.Method {
+	case http.MethodGet:
+		var accounts []gerrits.AccountInfo
+		for _, account := range s.accounts {
+			if members[account.AccountID] {
+				accounts = append(accounts, account)
+			}
+		}
+		body, _ := json.Marshal(accounts)
+		fmt.Fprintf(w, ")]}'\n%s", body)
+	case http.MethodPut:
+		members[accountID] = true
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		delete(members, accountID)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// memberList returns the gerrit usernames of the group members
+func (s *fakeGerritServer) memberList(groupName string) []string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var list []string
+	for _, account := range s.accounts {
+		if s.groups[groupName][account.AccountID] {
+			list = append(list, account.Username)
+		}
+	}
+	sort.Strings(list)
+	return list
+}
+
+func TestGerritGroupReconciler(t *testing.T) {
+	ctx := utils.NewContext()
+	// the gerrit usernames don't match the LF usernames, the accounts are found by email - mallory was added by a
+	// gerrit administrator and has no EasyCLA user
+	fakeGerrit := &fakeGerritServer{
+		accounts: []gerrits.AccountInfo{
+			{AccountID: 1001, Username: "alice.a", Email: "alice@acme.org"},
+			{AccountID: 1002, Username: "bob.b", Email: "bob@acme.org"},
+			{AccountID: 1003, Username: "carol.c", Email: "carol@acme.org"},
+			{AccountID: 1004, Username: "dsmith", Email: "dave@example.org"},
+			{AccountID: 1005, Username: "mallory", Email: "mallory@example.org"},
+			{AccountID: 1006, Username: "erin.e", Email: "erin@example.org"},
+		},
+		groups: map[string]map[int]bool{
+			"Example ICLA": {1005: true, 1006: true},
+			"Example CCLA": {1001: true, 1003: true},
+		},
+	}
+	server := httptest.NewServer(fakeGerrit)
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+
+	eventsRepo := events.NewMemoryRepository()
+	eventsService := events.NewService(eventsRepo, events.NewMockRepository())
+	usersRepo := users.NewMemoryRepository()
+	claGroupID := "cla-group-" + uniqueTestID(t)
+	repo := &stubGerritRepository{
+		gerrits: []*models.Gerrit{
+			{
+				GerritID:         strfmt.UUID4(uniqueTestID(t)),
+				GerritName:       "Example",
+				GerritURL:        strfmt.URI(server.URL + "/r"),
+				GroupNameIcla:    "Example ICLA",
+				GroupNameCcla:    "Example CCLA",
+				GroupSyncBackend: gerrits.GroupSyncBackendGerrit,
+				ProjectID:        claGroupID,
+			},
+		},
+	}
+	gerritService := gerrits.NewService(repo, nil, &gerrits.GerritGroupSync{
+		Accounts: map[string]config.GerritAccount{
+			serverURL.Host: {Username: "easycla", Password: "secret"},
+		},
+		Users:         usersRepo,
+		EventsService: eventsService,
+	})
+
+	companyRepo := company.NewMemoryRepository()
+	signaturesRepo := signatures.NewMemoryRepository(companyRepo, usersRepo, eventsService, nil, nil, gerritService)
+	individual, err := usersRepo.CreateUser(&models.User{LfUsername: "dave", LfEmail: "dave@example.org", Username: "Dave"})
+	assert.NoError(t, err)
+	invalidated, err := usersRepo.CreateUser(&models.User{LfUsername: "erin", LfEmail: "erin@example.org"})
+	assert.NoError(t, err)
+	for _, lfUsername := range []string{"alice", "bob", "carol"} {
+		_, err = usersRepo.CreateUser(&models.User{LfUsername: lfUsername, LfEmail: lfUsername + "@acme.org"})
+		assert.NoError(t, err)
+	}
+	companyModel, err := companyRepo.CreateCompany(ctx, &models.Company{CompanyName: "Acme " + uniqueTestID(t)})
+	assert.NoError(t, err)
+
+	seed := []signatures.ItemSignature{
+		{
+			SignatureReferenceID:   individual.UserID,
+			SignatureReferenceType: utils.SignatureReferenceTypeUser,
+			SignatureType:          utils.SignatureTypeCLA,
+		},
+		{
+			SignatureReferenceID:   companyModel.CompanyID,
+			SignatureReferenceType: utils.SignatureReferenceTypeCompany,
+			SignatureType:          utils.SignatureTypeCCLA,
+			EmailWhitelist:         []string{"alice@acme.org", "bob@acme.org"},
+		},
+	}
+	// the employees of the company, carol is no longer on the approval list
+	for _, lfUsername := range []string{"alice", "bob", "carol"} {
+		seed = append(seed, signatures.ItemSignature{
+			SignatureReferenceID:   "user-" + lfUsername,
+			SignatureReferenceType: utils.SignatureReferenceTypeUser,
+			SignatureType:          utils.SignatureTypeCLA,
+			SignatureUserCompanyID: companyModel.CompanyID,
+			UserLFUsername:         lfUsername,
+			UserEmail:              lfUsername + "@acme.org",
+		})
+	}
+	for _, item := range seed {
+		item.SignatureID = uniqueTestID(t)
+		item.SignatureProjectID = claGroupID
+		item.SignatureApproved = true
+		item.SignatureSigned = true
+		assert.NoError(t, signaturesRepo.PutSignature(ctx, item))
+	}
+	// the ICLA of erin was invalidated
+	assert.NoError(t, signaturesRepo.PutSignature(ctx, signatures.ItemSignature{
+		SignatureID:            uniqueTestID(t),
+		SignatureProjectID:     claGroupID,
+		SignatureReferenceID:   invalidated.UserID,
+		SignatureReferenceType: utils.SignatureReferenceTypeUser,
+		SignatureType:          utils.SignatureTypeCLA,
+		SignatureSigned:        true,
+		UserLFUsername:         "erin",
+	}))
+
+	reconciler := signatures.NewGerritGroupReconciler(signaturesRepo, gerritService)
+
+	// the dry-run only reports the changes
+	summary, err := reconciler.ReconcileAll(ctx, true)
+	assert.NoError(t, err)
+	assert.True(t, summary.DryRun)
+	assert.Equal(t, 1, summary.CLAGroupsProcessed)
+	assert.Equal(t, 2, summary.GroupsReconciled)
+	assert.Equal(t, 2, summary.UsersAdded)
+	assert.Equal(t, 2, summary.UsersRemoved)
+	assert.Equal(t, []string{"erin.e", "mallory"}, fakeGerrit.memberList("Example ICLA"))
+	assert.Equal(t, []string{"alice.a", "carol.c"}, fakeGerrit.memberList("Example CCLA"))
+
+	results, err := reconciler.ReconcileCLAGroup(ctx, &auth.User{UserName: "easycla-system"}, claGroupID, utils.ClaTypeECLA, true)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.True(t, results[0].DryRun)
+		assert.Equal(t, []string{"bob"}, results[0].Added)
+		assert.Equal(t, []string{"carol"}, results[0].Removed)
+		assert.Contains(t, results[0].Reasons["carol"], "no longer on the approval list")
+	}
+
+	// the changes are only reported until the instance opts in
+	results, err = reconciler.ReconcileCLAGroup(ctx, &auth.User{UserName: "easycla-system"}, claGroupID, utils.ClaTypeICLA, false)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.True(t, results[0].DryRun)
+		assert.Equal(t, []string{"dave"}, results[0].Added)
+		assert.Equal(t, []string{"erin"}, results[0].Removed)
+		assert.Equal(t, "ICLA invalidated", results[0].Reasons["erin"])
+	}
+	assert.Equal(t, []string{"erin.e", "mallory"}, fakeGerrit.memberList("Example ICLA"))
+
+	repo.gerrits[0].GroupReconcileApply = true
+	summary, err = reconciler.ReconcileAll(ctx, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, summary.UsersAdded)
+	assert.Equal(t, 2, summary.UsersRemoved)
+	assert.Equal(t, 0, summary.Failures)
+	assert.Equal(t, 0, summary.Errors)
+	// the member added by the gerrit administrator is not managed by EasyCLA
+	assert.Equal(t, []string{"dsmith", "mallory"}, fakeGerrit.memberList("Example ICLA"))
+	assert.Equal(t, []string{"alice.a", "bob.b"}, fakeGerrit.memberList("Example CCLA"))
+
+	// the group membership events record the reason of the change
+	recentEvents, err := eventsRepo.GetRecentEvents(100)
+	assert.NoError(t, err)
+	var eventData []string
+	for _, event := range recentEvents.Events {
+		if event.EventType == events.GerritUserAdded || event.EventType == events.GerritUserRemoved {
+			eventData = append(eventData, event.EventData)
+		}
+	}
+	assert.Len(t, eventData, 4)
+	for _, data := range eventData {
+		assert.Contains(t, data, "reason:")
+	}
+
+	// no drift left
+	summary, err = reconciler.ReconcileAll(ctx, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, summary.UsersAdded)
+	assert.Equal(t, 0, summary.UsersRemoved)
+
+	// a user without a gerrit account can't be added
+	fakeGerrit.accounts[3].Email = "dave@other.example.org"
+	fakeGerrit.groups["Example ICLA"] = map[int]bool{}
+	summary, err = reconciler.ReconcileAll(ctx, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, summary.UsersAdded)
+	assert.Equal(t, 1, summary.Failures)
+
+	// an instance without an account can't be managed
+	repo.gerrits[0].GerritURL = "https://review.unknown.org/r"
+	summary, err = reconciler.ReconcileAll(ctx, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, summary.Errors)
+}
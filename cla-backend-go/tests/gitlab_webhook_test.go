@@ -0,0 +1,24 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gitlab"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitLabWebhookValidateToken(t *testing.T) {
+	request := httptest.NewRequest("POST", "/v4/gitlab/activity", nil)
+	request.Header.Set(gitlab.TokenHeader, "secret")
+	assert.NoError(t, gitlab.ValidateToken(request, "secret"))
+	assert.Equal(t, gitlab.ErrInvalidToken, gitlab.ValidateToken(request, "other"))
+
+	// the requests are rejected when no secret is configured
+	assert.Equal(t, gitlab.ErrMissingSecret, gitlab.ValidateToken(request, ""))
+	request.Header.Del(gitlab.TokenHeader)
+	assert.Equal(t, gitlab.ErrMissingSecret, gitlab.ValidateToken(request, ""))
+}
@@ -0,0 +1,73 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/health"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthReadinessChecks(t *testing.T) {
+	var okRuns, failingRuns int32
+	checks := []health.Check{
+		{
+			Name: "ok-dependency",
+			Run: func(ctx context.Context) error {
+				atomic.AddInt32(&okRuns, 1)
+				return nil
+			},
+		},
+		{
+			Name: "failing-dependency",
+			Run: func(ctx context.Context) error {
+				atomic.AddInt32(&failingRuns, 1)
+				return errors.New("connection refused")
+			},
+		},
+		{
+			Name:    "slow-dependency",
+			Timeout: 10 * time.Millisecond,
+			Run: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		},
+	}
+	service := health.New("v1.0.0", "abc123", "main", "2021-01-01T00:00:00Z", checks...)
+
+	// liveness doesn't check the dependencies
+	liveness, err := service.Liveness(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, health.StatusHealthy, liveness.Status)
+	assert.Len(t, liveness.Healths, 1)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&okRuns))
+
+	readiness, err := service.Readiness(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, health.StatusNotHealthy, readiness.Status)
+	statuses := map[string]bool{}
+	errorMessages := map[string]string{}
+	for _, status := range readiness.Healths {
+		statuses[status.Name] = status.Healthy
+		errorMessages[status.Name] = status.Error
+	}
+	assert.True(t, statuses["CLA"])
+	assert.True(t, statuses["ok-dependency"])
+	assert.False(t, statuses["failing-dependency"])
+	assert.Equal(t, "connection refused", errorMessages["failing-dependency"])
+	assert.False(t, statuses["slow-dependency"])
+	assert.Equal(t, context.DeadlineExceeded.Error(), errorMessages["slow-dependency"])
+
+	// the results are cached - the checks are not run again
+	_, err = service.HealthCheck(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&okRuns))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&failingRuns))
+}
@@ -0,0 +1,280 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/auth"
+	"github.com/communitybridge/easycla/cla-backend-go/auth/oidctest"
+	"github.com/communitybridge/easycla/cla-backend-go/user"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// oidcTestPermissioner doesn't know any user, the authorizer returns the user of the token claims
+type oidcTestPermissioner struct{}
+
+func (oidcTestPermissioner) GetUserAndProfilesByLFID(lfidUsername string) (user.CLAUser, error) {
+	return user.CLAUser{}, errors.New("user not found")
+}
+
+func (oidcTestPermissioner) GetUserProjectIDs(userID string) ([]string, error) {
+	return nil, nil
+}
+
+func (oidcTestPermissioner) GetClaManagerCorporateClaIDs(userID string) ([]string, error) {
+	return nil, nil
+}
+
+func (oidcTestPermissioner) GetUserCompanyIDs(userID string) ([]string, error) {
+	return nil, nil
+}
+
+func newOIDCTestProvider(t *testing.T) *oidctest.Provider {
+	provider, err := oidctest.NewProvider()
+	if err != nil {
+		t.Fatalf("unable to start the provider: %+v", err)
+	}
+	return provider
+}
+
+func signOIDCTestToken(t *testing.T, provider *oidctest.Provider, claims map[string]interface{}) string {
+	token, err := provider.Sign(claims)
+	if err != nil {
+		t.Fatalf("unable to sign the token: %+v", err)
+	}
+	return token
+}
+
+func TestOIDCValidatorIssuers(t *testing.T) {
+	corporate := newOIDCTestProvider(t)
+	defer corporate.Close()
+	community := newOIDCTestProvider(t)
+	defer community.Close()
+	untrusted := newOIDCTestProvider(t)
+	defer untrusted.Close()
+
+	_, err := auth.NewOIDCValidator([]auth.IssuerConfig{{Issuer: corporate.Issuer, Algorithms: []string{"HS256"}}}, nil)
+	assert.Error(t, err)
+	_, err = auth.NewOIDCValidator([]auth.IssuerConfig{
+		{Issuer: corporate.Issuer, Audiences: []string{"easycla"}},
+		{Issuer: corporate.Issuer, Audiences: []string{"easycla"}},
+	}, nil)
+	assert.Error(t, err)
+	// the tokens an issuer grants to its other applications must not be accepted, an audience is required
+	_, err = auth.NewOIDCValidator([]auth.IssuerConfig{{Issuer: corporate.Issuer}}, nil)
+	assert.EqualError(t, err, "missing Audiences for the issuer: "+corporate.Issuer)
+
+	validator, err := auth.NewOIDCValidator([]auth.IssuerConfig{
+		{Issuer: corporate.Issuer, Audiences: []string{"easycla"}},
+		{
+			Issuer:    community.Issuer,
+			Audiences: []string{"community"},
+			ClaimMapping: auth.ClaimMapping{
+				UsernameClaim: "https://sso.example.org/claims/username",
+				NameClaim:     "nickname",
+				EmailClaim:    "https://sso.example.org/claims/email",
+			},
+			UsernameNamespace: "community",
+		},
+	}, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	authorizer := auth.NewAuthorizer(validator, oidcTestPermissioner{})
+
+	// the claims of each issuer are mapped with its claim mapping
+	claUser, err := authorizer.SecurityAuth(signOIDCTestToken(t, corporate, map[string]interface{}{
+		"aud":                []string{"portal", "easycla"},
+		"preferred_username": "jdoe",
+		"name":               "Jane Doe",
+		"email":              "jdoe@example.org",
+		"email_verified":     true,
+	}), nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, strings.TrimPrefix(strings.TrimSuffix(corporate.Issuer, "/"), "http://")+"/jdoe", claUser.LFUsername)
+		assert.Equal(t, "Jane Doe", claUser.Name)
+		assert.Equal(t, "jdoe@example.org", claUser.LFEmail)
+	}
+	claUser, err = authorizer.SecurityAuth(signOIDCTestToken(t, community, map[string]interface{}{
+		"aud": "community",
+		"https://sso.example.org/claims/username": "jroe",
+		"nickname":                             "Jim Roe",
+		"https://sso.example.org/claims/email": "jroe@example.org",
+		"email_verified":                       true,
+	}), nil)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "community/jroe", claUser.LFUsername)
+		assert.Equal(t, "Jim Roe", claUser.Name)
+		assert.Equal(t, "jroe@example.org", claUser.LFEmail)
+	}
+	_, err = authorizer.SecurityAuth(signOIDCTestToken(t, community, map[string]interface{}{
+		"aud":                "community",
+		"preferred_username": "jroe",
+		"name":               "Jim Roe",
+		"email":              "jroe@example.org",
+	}), nil)
+	assert.EqualError(t, err, "username not found")
+
+	_, err = validator.VerifyToken(signOIDCTestToken(t, corporate, map[string]interface{}{"aud": "portal"}))
+	assert.Equal(t, auth.ErrInvalidAudience, err)
+	_, err = validator.VerifyToken(signOIDCTestToken(t, corporate, nil))
+	assert.Equal(t, auth.ErrInvalidAudience, err)
+	_, err = validator.VerifyToken(signOIDCTestToken(t, community, map[string]interface{}{"aud": "easycla"}))
+	assert.Equal(t, auth.ErrInvalidAudience, err)
+	_, err = validator.VerifyToken(signOIDCTestToken(t, untrusted, nil))
+	assert.EqualError(t, err, auth.ErrUntrustedIssuer.Error())
+	// a trusted issuer claim doesn't make the keys of another issuer trusted
+	_, err = validator.VerifyToken(signOIDCTestToken(t, untrusted, map[string]interface{}{"iss": community.Issuer}))
+	assert.Error(t, err)
+	_, err = validator.VerifyToken(signOIDCTestToken(t, corporate, map[string]interface{}{
+		"aud": "easycla",
+		"exp": time.Now().Add(-time.Minute).Unix(),
+	}))
+	assert.Contains(t, err.Error(), "expired")
+
+	// the symmetric algorithms are rejected, the public key can't be used as the secret
+	symmetric, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iss": corporate.Issuer,
+		"aud": "easycla",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("secret"))
+	assert.NoError(t, err)
+	_, err = validator.VerifyToken(symmetric)
+	assert.EqualError(t, err, "unexpected signing algorithm: HS256")
+}
+
+func TestOIDCValidatorUserClaims(t *testing.T) {
+	corporate := newOIDCTestProvider(t)
+	defer corporate.Close()
+	community := newOIDCTestProvider(t)
+	defer community.Close()
+	lf := newOIDCTestProvider(t)
+	defer lf.Close()
+
+	// the user names of two issuers can't share a namespace
+	_, err := auth.NewOIDCValidator([]auth.IssuerConfig{
+		{Issuer: corporate.Issuer, Audiences: []string{"easycla"}, UsernameNamespace: "example"},
+		{Issuer: community.Issuer, Audiences: []string{"easycla"}, UsernameNamespace: "example"},
+	}, nil)
+	assert.EqualError(t, err, "duplicate username namespace: example for the issuer: "+community.Issuer)
+
+	lfConfig, err := auth.NewAuth0IssuerConfig("example.auth0.com", "client", "nickname", "RS256")
+	if !assert.NoError(t, err) {
+		return
+	}
+	lfConfig.Issuer = lf.Issuer
+	lfConfig.JWKSURL = lf.Issuer + "jwks"
+	validator, err := auth.NewOIDCValidator([]auth.IssuerConfig{
+		lfConfig,
+		{Issuer: corporate.Issuer, Audiences: []string{"easycla"}, UsernameNamespace: "corporate"},
+		{
+			Issuer:            community.Issuer,
+			Audiences:         []string{"easycla"},
+			UsernameNamespace: "community",
+			ClaimMapping:      auth.ClaimMapping{EmailVerifiedClaim: "https://sso.example.org/claims/email_verified"},
+		},
+	}, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	authorizer := auth.NewAuthorizer(validator, oidcTestPermissioner{})
+
+	// the same user name of each issuer is a different user, only the LF identity provider issues the LF user names
+	for _, tc := range []struct {
+		provider *oidctest.Provider
+		claims   map[string]interface{}
+		username string
+	}{
+		{lf, map[string]interface{}{"nickname": "jdoe"}, "jdoe"},
+		{corporate, map[string]interface{}{"preferred_username": "jdoe", "email_verified": true}, "corporate/jdoe"},
+		{community, map[string]interface{}{"preferred_username": "jdoe", "https://sso.example.org/claims/email_verified": true}, "community/jdoe"},
+	} {
+		tc.claims["aud"] = "easycla"
+		tc.claims["name"] = "Jane Doe"
+		tc.claims["email"] = "jdoe@example.org"
+		claUser, authErr := authorizer.SecurityAuth(signOIDCTestToken(t, tc.provider, tc.claims), nil)
+		if assert.NoError(t, authErr) {
+			assert.Equal(t, tc.username, claUser.LFUsername)
+			assert.Equal(t, "jdoe@example.org", claUser.LFEmail)
+		}
+	}
+
+	// the email of the other issuers is used once the issuer has verified it
+	for _, tc := range []struct {
+		provider *oidctest.Provider
+		claims   map[string]interface{}
+	}{
+		{corporate, map[string]interface{}{}},
+		{corporate, map[string]interface{}{"email_verified": false}},
+		{corporate, map[string]interface{}{"email_verified": "true"}},
+		{community, map[string]interface{}{"email_verified": true}},
+	} {
+		tc.claims["aud"] = "easycla"
+		tc.claims["preferred_username"] = "jdoe"
+		tc.claims["name"] = "Jane Doe"
+		tc.claims["email"] = "jdoe@example.org"
+		_, err = authorizer.SecurityAuth(signOIDCTestToken(t, tc.provider, tc.claims), nil)
+		assert.Equal(t, auth.ErrEmailNotVerified, err)
+	}
+}
+
+func TestOIDCValidatorKeyRotation(t *testing.T) {
+	provider := newOIDCTestProvider(t)
+	defer provider.Close()
+
+	validator, err := auth.NewOIDCValidator([]auth.IssuerConfig{{Issuer: provider.Issuer, Audiences: []string{"easycla"}}}, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	claims := map[string]interface{}{"sub": "jdoe", "aud": "easycla"}
+
+	// the signing keys are fetched once and cached
+	before := signOIDCTestToken(t, provider, claims)
+	for i := 0; i < 3; i++ {
+		tokenClaims, verifyErr := validator.VerifyToken(before)
+		if assert.NoError(t, verifyErr) {
+			assert.Equal(t, "jdoe", tokenClaims["sub"])
+		}
+	}
+	assert.Equal(t, 1, provider.KeyRequests())
+
+	// a token signed with the new key makes the validator fetch the keys again
+	assert.NoError(t, provider.RotateKey())
+	after := signOIDCTestToken(t, provider, claims)
+	_, err = validator.VerifyToken(after)
+	assert.NoError(t, err)
+	_, err = validator.VerifyToken(before)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, provider.KeyRequests())
+
+	// the keys aren't fetched again for each token signed with an unknown key
+	assert.NoError(t, provider.RotateKey())
+	_, err = validator.VerifyToken(signOIDCTestToken(t, provider, claims))
+	assert.EqualError(t, err, auth.ErrKeyNotFound.Error())
+	assert.Equal(t, 2, provider.KeyRequests())
+}
+
+func TestOIDCValidatorAuth0Audience(t *testing.T) {
+	provider := newOIDCTestProvider(t)
+	defer provider.Close()
+
+	// the audience of the auth0 tokens isn't checked
+	config, err := auth.NewAuth0IssuerConfig("example.auth0.com", "client", "nickname", "RS256")
+	if !assert.NoError(t, err) {
+		return
+	}
+	config.Issuer = provider.Issuer
+	config.JWKSURL = provider.Issuer + "jwks"
+	validator, err := auth.NewOIDCValidator([]auth.IssuerConfig{config}, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = validator.VerifyToken(signOIDCTestToken(t, provider, map[string]interface{}{"aud": "portal"}))
+	assert.NoError(t, err)
+}
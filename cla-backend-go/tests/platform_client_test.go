@@ -0,0 +1,130 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	platform_client "github.com/communitybridge/easycla/cla-backend-go/v2/platform-client"
+	"github.com/stretchr/testify/assert"
+)
+
+func testPlatformConfig(name string) platform_client.Config {
+	return platform_client.Config{
+		Name:             name,
+		Timeout:          time.Second,
+		MaxRetries:       2,
+		InitialBackoff:   time.Millisecond,
+		MaxBackoff:       5 * time.Millisecond,
+		FailureThreshold: 3,
+		CoolDown:         time.Hour,
+	}
+}
+
+func TestPlatformClientRetriesIdempotentCalls(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dependency := platform_client.NewDependency(testPlatformConfig("retry-test-service" + uniqueTestID(t)))
+	client := dependency.HTTPClient()
+
+	resp, err := client.Get(server.URL)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	assert.Equal(t, platform_client.StateClosed, dependency.Status().State)
+
+	// a POST is not idempotent - a single attempt
+	atomic.StoreInt32(&calls, 0)
+	resp, err = client.Post(server.URL, "application/json", strings.NewReader("{}"))
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestPlatformClientCircuitBreaker(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	name := "breaker-test-service" + uniqueTestID(t)
+	dependency := platform_client.NewDependency(testPlatformConfig(name))
+	client := dependency.HTTPClient()
+
+	// 500 is not retried but counts as a failure of the dependency
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(server.URL)
+		assert.NoError(t, err)
+		assert.NoError(t, resp.Body.Close())
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	// the open breaker rejects the calls without reaching the service
+	_, err := client.Get(server.URL)
+	assert.True(t, errors.Is(err, platform_client.ErrCircuitOpen))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	var found bool
+	for _, status := range platform_client.Statuses() {
+		if status.Name == name {
+			found = true
+			assert.False(t, status.Healthy())
+			assert.Equal(t, 3, status.ConsecutiveFailures)
+			assert.Contains(t, status.LastError, "500")
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestPlatformClientCache(t *testing.T) {
+	cache := platform_client.NewCache(50*time.Millisecond, 2)
+	loads := 0
+	load := func() (interface{}, error) {
+		loads++
+		return "role-id", nil
+	}
+
+	for i := 0; i < 3; i++ {
+		value, err := cache.GetOrLoad("cla-manager", load)
+		assert.NoError(t, err)
+		assert.Equal(t, "role-id", value)
+	}
+	assert.Equal(t, 1, loads)
+
+	// the errors are not cached
+	_, err := cache.GetOrLoad("missing", func() (interface{}, error) { return nil, errors.New("role not found") })
+	assert.Error(t, err)
+	_, ok := cache.Get("missing")
+	assert.False(t, ok)
+
+	// bounded size
+	cache.Set("a", 1)
+	cache.Set("b", 2)
+	assert.Equal(t, 2, cache.Len())
+
+	time.Sleep(60 * time.Millisecond)
+	_, ok = cache.Get("a")
+	assert.False(t, ok)
+	_, err = cache.GetOrLoad("cla-manager", load)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, loads)
+}
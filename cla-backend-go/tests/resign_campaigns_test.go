@@ -0,0 +1,119 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	"github.com/communitybridge/easycla/cla-backend-go/resign_campaigns"
+	signatureService "github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResignCampaignLifecycle(t *testing.T) {
+	ctx := utils.NewContext()
+	utils.SetEmailSender(&utils.MockEmailSender{})
+	eventsService := events.NewService(events.NewMemoryRepository(), events.NewMockRepository())
+	companyRepo := company.NewMemoryRepository()
+	usersRepo := users.NewMemoryRepository()
+	signaturesRepo := signatureService.NewMemoryRepository(companyRepo, usersRepo, eventsService, nil, nil, nil)
+
+	claGroup := &models.ClaGroup{
+		ProjectID:          "cla-group-" + uniqueTestID(t),
+		ProjectName:        "Resign Project",
+		ProjectICLAEnabled: true,
+		ProjectIndividualDocuments: []models.ClaGroupDocument{
+			{DocumentMajorVersion: "1", DocumentMinorVersion: "0", DocumentCreationDate: "2020-01-01T00:00:00Z"},
+			{DocumentMajorVersion: "2", DocumentMinorVersion: "0", DocumentCreationDate: "2021-01-01T00:00:00Z"},
+		},
+	}
+	putICLA := func(signatureID, userID, majorVersion string) {
+		assert.NoError(t, signaturesRepo.PutSignature(ctx, signatureService.ItemSignature{
+			SignatureID:                   signatureID,
+			SignatureProjectID:            claGroup.ProjectID,
+			SignatureReferenceID:          userID,
+			SignatureReferenceType:        utils.SignatureReferenceTypeUser,
+			SignatureType:                 utils.SignatureTypeCLA,
+			SignatureDocumentMajorVersion: majorVersion,
+			SignatureDocumentMinorVersion: "0",
+			SignatureApproved:             true,
+			SignatureSigned:               true,
+		}))
+	}
+
+	var userIDs []string
+	for _, name := range []string{"stale", "current", "resigner"} {
+		userModel, err := usersRepo.CreateUser(&models.User{Username: name, LfEmail: name + "@example.org"})
+		assert.NoError(t, err)
+		userIDs = append(userIDs, userModel.UserID)
+	}
+	staleSignatureID := uniqueTestID(t) + "-stale"
+	putICLA(staleSignatureID, userIDs[0], "1")
+	putICLA(uniqueTestID(t)+"-current", userIDs[1], "2")
+	putICLA(uniqueTestID(t)+"-resigner", userIDs[2], "1")
+
+	service := resign_campaigns.NewService(resign_campaigns.NewMemoryRepository(), signaturesRepo, &stubCLAGroupLookup{claGroup: claGroup}, usersRepo, companyRepo, eventsService)
+	_, err := service.CreateCampaign(ctx, &resign_campaigns.CampaignInput{CLAGroupID: claGroup.ProjectID, ClaType: utils.ClaTypeICLA, GracePeriodDays: 400})
+	assert.Equal(t, resign_campaigns.ErrInvalidGracePeriod, err)
+	_, err = service.CreateCampaign(ctx, &resign_campaigns.CampaignInput{CLAGroupID: claGroup.ProjectID, ClaType: utils.ClaTypeCCLA})
+	assert.Equal(t, resign_campaigns.ErrCLATypeNotEnabled, err)
+
+	campaign, err := service.CreateCampaign(ctx, &resign_campaigns.CampaignInput{
+		CLAGroupID:         claGroup.ProjectID,
+		ClaType:            utils.ClaTypeICLA,
+		GracePeriodDays:    10,
+		InvalidateOnExpiry: true,
+		CreatedBy:          "admin",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, campaign.MajorVersion)
+	assert.Equal(t, resign_campaigns.CampaignStatusActive, campaign.Status)
+
+	_, err = service.CreateCampaign(ctx, &resign_campaigns.CampaignInput{CLAGroupID: claGroup.ProjectID, ClaType: utils.ClaTypeICLA})
+	assert.Equal(t, resign_campaigns.ErrCampaignAlreadyActive, err)
+
+	// The third user signs the new version during the grace period
+	putICLA(uniqueTestID(t)+"-resigned", userIDs[2], "2")
+	progress, err := service.GetCampaignProgress(ctx, campaign.CampaignID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, progress.Total)
+	assert.Equal(t, 1, progress.Resigned)
+	assert.Equal(t, 1, progress.Pending)
+
+	now := time.Now().UTC()
+	result, err := service.ProcessCampaigns(ctx, now.Add(24*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.RemindersSent)
+
+	result, err = service.ProcessCampaigns(ctx, now.Add(4*24*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.RemindersSent)
+
+	// The reminder is sent once
+	result, err = service.ProcessCampaigns(ctx, now.Add(5*24*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.RemindersSent)
+
+	result, err = service.ProcessCampaigns(ctx, now.Add(11*24*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.CampaignsCompleted)
+	assert.Equal(t, 1, result.Invalidated)
+	assert.Equal(t, 0, result.Errors)
+
+	sig, err := signaturesRepo.GetSignature(ctx, staleSignatureID)
+	assert.NoError(t, err)
+	assert.False(t, sig.SignatureApproved)
+
+	progress, err = service.GetCampaignProgress(ctx, campaign.CampaignID)
+	assert.NoError(t, err)
+	assert.Equal(t, resign_campaigns.CampaignStatusCompleted, progress.Campaign.Status)
+	assert.Equal(t, 1, progress.Invalidated)
+	assert.Equal(t, 1, progress.Resigned)
+}
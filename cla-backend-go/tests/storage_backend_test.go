@@ -0,0 +1,409 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	eventOps "github.com/communitybridge/easycla/cla-backend-go/gen/v1/restapi/operations/events"
+	projectOps "github.com/communitybridge/easycla/cla-backend-go/gen/v1/restapi/operations/project"
+	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
+	repoMock "github.com/communitybridge/easycla/cla-backend-go/repositories/mock"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/user"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/gofrs/uuid"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// dynamoDBTestStageEnv names the environment variable with the stage of the DynamoDB tables the storage backend
+// suite runs against - the DynamoDB variants are skipped when it is not set
+const dynamoDBTestStageEnv = "EASYCLA_TEST_DYNAMODB_STAGE"
+
+// signatureSeeder stores a signature record directly in the backend - signatures are created by the python backend
+type signatureSeeder func(t *testing.T, sig signatures.ItemSignature)
+
+func dynamoDBTestSession(t *testing.T) (*session.Session, string) {
+	stage := os.Getenv(dynamoDBTestStageEnv)
+	if stage == "" {
+		t.Skipf("%s not set - skipping DynamoDB storage backend tests", dynamoDBTestStageEnv)
+	}
+	awsSession, err := session.NewSession(&aws.Config{Region: aws.String(os.Getenv("AWS_REGION"))})
+	if err != nil {
+		t.Fatalf("unable to create AWS session: %v", err)
+	}
+	return awsSession, stage
+}
+
+func uniqueTestID(t *testing.T) string {
+	id, err := uuid.NewV4()
+	if err != nil {
+		t.Fatalf("unable to generate test ID: %v", err)
+	}
+	return id.String()
+}
+
+func TestMemoryUsersRepository(t *testing.T) {
+	runUsersRepositorySuite(t, users.NewMemoryRepository())
+}
+
+func TestDynamoDBUsersRepository(t *testing.T) {
+	awsSession, stage := dynamoDBTestSession(t)
+	runUsersRepositorySuite(t, users.NewRepository(awsSession, stage))
+}
+
+func runUsersRepositorySuite(t *testing.T, repo users.UserRepository) {
+	lfUsername := "lfuser-" + uniqueTestID(t)
+	created, err := repo.CreateUser(&models.User{
+		LfUsername:     lfUsername,
+		LfEmail:        lfUsername + "@example.org",
+		GithubUsername: "gh-" + lfUsername,
+		Username:       "Test User",
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, created.UserID)
+	defer func() {
+		assert.NoError(t, repo.Delete(created.UserID))
+	}()
+
+	fetched, err := repo.GetUser(created.UserID)
+	assert.NoError(t, err)
+	assert.Equal(t, lfUsername, fetched.LfUsername)
+
+	byName, err := repo.GetUserByLFUserName(lfUsername)
+	assert.NoError(t, err)
+	assert.Equal(t, created.UserID, byName.UserID)
+
+	byGitHub, err := repo.GetUserByGitHubUsername("gh-" + lfUsername)
+	assert.NoError(t, err)
+	assert.Equal(t, created.UserID, byGitHub.UserID)
+
+	_, err = repo.GetUserByEmail("missing-" + lfUsername + "@example.org")
+	assert.Error(t, err, "missing email returns a not found error")
+
+	saved, err := repo.Save(&models.UserUpdate{LfUsername: lfUsername, CompanyID: "company-1234"})
+	assert.NoError(t, err)
+	assert.Equal(t, "company-1234", saved.CompanyID)
+
+	search, err := repo.SearchUsers("lf_username", lfUsername, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(search.Users))
+}
+
+func TestMemoryCompanyRepository(t *testing.T) {
+	runCompanyRepositorySuite(t, company.NewMemoryRepository())
+}
+
+func TestDynamoDBCompanyRepository(t *testing.T) {
+	awsSession, stage := dynamoDBTestSession(t)
+	runCompanyRepositorySuite(t, company.NewRepository(awsSession, stage))
+}
+
+func runCompanyRepositorySuite(t *testing.T, repo company.IRepository) {
+	ctx := utils.NewContext()
+	companyName := "Test Company " + uniqueTestID(t)
+	created, err := repo.CreateCompany(ctx, &models.Company{
+		CompanyName:       companyName,
+		CompanyExternalID: "sfid-" + companyName,
+		CompanyACL:        []string{"manager-one"},
+	})
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, repo.DeleteCompanyByID(ctx, created.CompanyID))
+	}()
+	assert.Equal(t, companyName, created.SigningEntityName, "signing entity name defaults to the company name")
+
+	duplicate, err := repo.CreateCompany(ctx, &models.Company{CompanyName: companyName})
+	assert.NoError(t, err)
+	assert.Equal(t, created.CompanyID, duplicate.CompanyID, "duplicate company names are not re-created")
+
+	byExternalID, err := repo.GetCompanyByExternalID(ctx, "sfid-"+companyName)
+	assert.NoError(t, err)
+	assert.Equal(t, created.CompanyID, byExternalID.CompanyID)
+
+	_, err = repo.GetCompany(ctx, "missing-"+created.CompanyID)
+	assert.Error(t, err)
+
+	assert.NoError(t, repo.UpdateCompanyAccessList(ctx, created.CompanyID, []string{"manager-one", "manager-two"}))
+	managed, err := repo.GetCompaniesByUserManager(ctx, "user-1234", user.User{LFUsername: "manager-two"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(managed.Companies))
+
+	invite, err := repo.AddPendingCompanyInviteRequest(ctx, created.CompanyID, user.User{UserID: "user-" + companyName})
+	assert.NoError(t, err)
+	assert.Equal(t, "pending", invite.Status)
+	assert.NoError(t, repo.ApproveCompanyAccessRequest(ctx, invite.CompanyInviteID))
+	approved, err := repo.GetCompanyInviteRequests(ctx, created.CompanyID, aws.String("approved"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(approved))
+}
+
+func TestMemoryEventsRepository(t *testing.T) {
+	runEventsRepositorySuite(t, events.NewMemoryRepository())
+}
+
+func TestDynamoDBEventsRepository(t *testing.T) {
+	awsSession, stage := dynamoDBTestSession(t)
+	runEventsRepositorySuite(t, events.NewRepository(awsSession, stage))
+}
+
+func runEventsRepositorySuite(t *testing.T, repo events.Repository) {
+	projectID := "project-" + uniqueTestID(t)
+	assert.Equal(t, events.ErrUserIDRequired, repo.CreateEvent(&models.Event{EventType: events.CLATemplateCreated}))
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, repo.CreateEvent(&models.Event{
+			EventType:      events.CLATemplateCreated,
+			EventProjectID: projectID,
+			EventCompanyID: "company-1234",
+			UserID:         "user-1234",
+			EventData:      fmt.Sprintf("event number %d", i),
+		}))
+	}
+
+	all, err := repo.SearchEvents(&eventOps.SearchEventsParams{ProjectID: aws.String(projectID)}, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(all.Events))
+
+	firstPage, err := repo.SearchEvents(&eventOps.SearchEventsParams{ProjectID: aws.String(projectID)}, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(firstPage.Events))
+	assert.NotEmpty(t, firstPage.NextKey)
+
+	searched, err := repo.SearchEvents(&eventOps.SearchEventsParams{
+		ProjectID:  aws.String(projectID),
+		SearchTerm: aws.String("number 1"),
+	}, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(searched.Events))
+}
+
+// projectTestGerritRepo returns no gerrit instances for the CLA groups, the other gerrit repository methods aren't
+// used by the project repository
+type projectTestGerritRepo struct {
+	gerrits.Repository
+}
+
+func (projectTestGerritRepo) GetClaGroupGerrits(ctx context.Context, claGroupID string) (*models.GerritList, error) {
+	return &models.GerritList{}, nil
+}
+
+// projectRepositoryDependencies returns the GitHub repositories and the project CLA group mappings the project
+// repository loads the CLA group details with
+func projectRepositoryDependencies(t *testing.T, projectSFID string) (*repoMock.MockRepository, *projects_cla_groups.MockRepository, func(claGroupID string)) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	ghRepo := repoMock.NewMockRepository(ctrl)
+	ghRepo.EXPECT().GetCLAGroupRepositoriesGroupByOrgs(gomock.Any(), gomock.Any(), true).Return(nil, nil).AnyTimes()
+	pcgRepo := projects_cla_groups.NewMockRepository(ctrl)
+	mapProject := func(claGroupID string) {
+		pcgRepo.EXPECT().GetClaGroupIDForProject(gomock.Any(), projectSFID).
+			Return(&projects_cla_groups.ProjectClaGroup{ProjectSFID: projectSFID, ClaGroupID: claGroupID}, nil).AnyTimes()
+	}
+	return ghRepo, pcgRepo, mapProject
+}
+
+func TestMemoryProjectRepository(t *testing.T) {
+	projectSFID := "project-sfid-" + uniqueTestID(t)
+	ghRepo, pcgRepo, mapProject := projectRepositoryDependencies(t, projectSFID)
+	runProjectRepositorySuite(t, project.NewMemoryRepository(ghRepo, projectTestGerritRepo{}, pcgRepo), projectSFID, mapProject)
+}
+
+func TestDynamoDBProjectRepository(t *testing.T) {
+	awsSession, stage := dynamoDBTestSession(t)
+	projectSFID := "project-sfid-" + uniqueTestID(t)
+	ghRepo, pcgRepo, mapProject := projectRepositoryDependencies(t, projectSFID)
+	runProjectRepositorySuite(t, project.NewRepository(awsSession, stage, ghRepo, projectTestGerritRepo{}, pcgRepo), projectSFID, mapProject)
+}
+
+func runProjectRepositorySuite(t *testing.T, repo project.ProjectRepository, projectSFID string, mapProject func(claGroupID string)) {
+	ctx := utils.NewContext()
+	claGroupName := "Test CLA Group " + uniqueTestID(t)
+	foundationSFID := "foundation-sfid-" + uniqueTestID(t)
+	created, err := repo.CreateCLAGroup(ctx, &models.ClaGroup{
+		ProjectName:        claGroupName,
+		ProjectExternalID:  projectSFID,
+		FoundationSFID:     foundationSFID,
+		ProjectTemplateID:  "template-1234",
+		ProjectACL:         []string{"manager-one"},
+		ProjectICLAEnabled: true,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotEmpty(t, created.ProjectID)
+	assert.Equal(t, utils.V1, created.Version, "the version defaults to v1")
+	deleted := false
+	defer func() {
+		if !deleted {
+			assert.NoError(t, repo.DeleteCLAGroup(ctx, created.ProjectID))
+		}
+	}()
+	mapProject(created.ProjectID)
+
+	fetched, err := repo.GetCLAGroupByID(ctx, created.ProjectID, project.DontLoadRepoDetails)
+	assert.NoError(t, err)
+	assert.Equal(t, claGroupName, fetched.ProjectName)
+	assert.True(t, fetched.ProjectICLAEnabled)
+
+	_, err = repo.GetCLAGroupByID(ctx, "missing-"+created.ProjectID, project.DontLoadRepoDetails)
+	assert.Error(t, err)
+
+	byName, err := repo.GetCLAGroupByName(ctx, claGroupName)
+	assert.NoError(t, err)
+	if assert.NotNil(t, byName) {
+		assert.Equal(t, created.ProjectID, byName.ProjectID)
+	}
+
+	byExternalID, err := repo.GetCLAGroupsByExternalID(ctx, &projectOps.GetProjectsByExternalIDParams{ProjectSFID: projectSFID}, project.DontLoadRepoDetails)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(byExternalID.Projects))
+
+	byFoundation, err := repo.GetClaGroupsByFoundationSFID(ctx, foundationSFID, project.DontLoadRepoDetails)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(byFoundation.Projects))
+
+	byProjectSFID, err := repo.GetClaGroupByProjectSFID(ctx, projectSFID, project.DontLoadRepoDetails)
+	assert.NoError(t, err)
+	assert.Equal(t, created.ProjectID, byProjectSFID.ProjectID)
+
+	updated, err := repo.UpdateCLAGroup(ctx, &models.ClaGroup{
+		ProjectID:          created.ProjectID,
+		ProjectName:        claGroupName + " updated",
+		ProjectTemplateID:  "template-1234",
+		ProjectCCLAEnabled: true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, claGroupName+" updated", updated.ProjectName)
+	assert.True(t, updated.ProjectCCLAEnabled)
+	assert.ElementsMatch(t, []string{"manager-one"}, updated.ProjectACL, "the ACL is kept when not updated")
+
+	_, err = repo.UpdateCLAGroup(ctx, &models.ClaGroup{ProjectID: created.ProjectID, ProjectTemplateID: "template-5678"})
+	assert.Error(t, err, "the CLA group template can't be changed")
+
+	assert.NoError(t, repo.UpdateRootCLAGroupRepositoriesCount(ctx, created.ProjectID, 3, false))
+	assert.NoError(t, repo.UpdateRootCLAGroupRepositoriesCount(ctx, created.ProjectID, -1, false))
+	counted, err := repo.GetCLAGroupByID(ctx, created.ProjectID, project.DontLoadRepoDetails)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), counted.RootProjectRepositoriesCount)
+	assert.NoError(t, repo.UpdateRootCLAGroupRepositoriesCount(ctx, created.ProjectID, 7, true))
+	counted, err = repo.GetCLAGroupByID(ctx, created.ProjectID, project.DontLoadRepoDetails)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), counted.RootProjectRepositoriesCount)
+
+	assert.NoError(t, repo.DeleteCLAGroup(ctx, created.ProjectID))
+	deleted = true
+	_, err = repo.GetCLAGroupByID(ctx, created.ProjectID, project.DontLoadRepoDetails)
+	assert.Error(t, err)
+}
+
+func TestMemorySignatureRepository(t *testing.T) {
+	usersRepo := users.NewMemoryRepository()
+	companyRepo := company.NewMemoryRepository()
+	eventsService := events.NewService(events.NewMemoryRepository(), events.NewMockRepository())
+	repo := signatures.NewMemoryRepository(companyRepo, usersRepo, eventsService, nil, nil, nil)
+	runSignatureRepositorySuite(t, repo, func(t *testing.T, sig signatures.ItemSignature) {
+		assert.NoError(t, repo.PutSignature(utils.NewContext(), sig))
+	})
+}
+
+func TestDynamoDBSignatureRepository(t *testing.T) {
+	awsSession, stage := dynamoDBTestSession(t)
+	usersRepo := users.NewRepository(awsSession, stage)
+	companyRepo := company.NewRepository(awsSession, stage)
+	eventsService := events.NewService(events.NewRepository(awsSession, stage), events.NewMockRepository())
+	repo := signatures.NewRepository(awsSession, stage, companyRepo, usersRepo, eventsService, nil, nil, nil)
+	dynamoDBClient := dynamodb.New(awsSession)
+	runSignatureRepositorySuite(t, repo, func(t *testing.T, sig signatures.ItemSignature) {
+		_, now := utils.CurrentTime()
+		sig.DateCreated, sig.DateModified = now, now
+		item, err := dynamodbattribute.MarshalMap(sig)
+		assert.NoError(t, err)
+		_, err = dynamoDBClient.PutItem(&dynamodb.PutItemInput{
+			Item:      item,
+			TableName: aws.String(fmt.Sprintf("cla-%s-signatures", stage)),
+		})
+		assert.NoError(t, err)
+	})
+}
+
+func runSignatureRepositorySuite(t *testing.T, repo signatures.SignatureRepository, seed signatureSeeder) {
+	ctx := utils.NewContext()
+	claGroupID := "cla-group-" + uniqueTestID(t)
+	companyID := "company-" + uniqueTestID(t)
+	cclaID := uniqueTestID(t)
+	iclaID := uniqueTestID(t)
+	approved, signed := true, true
+
+	seed(t, signatures.ItemSignature{
+		SignatureID:            cclaID,
+		SignatureApproved:      true,
+		SignatureSigned:        true,
+		SignatureProjectID:     claGroupID,
+		SignatureReferenceID:   companyID,
+		SignatureReferenceType: utils.SignatureReferenceTypeCompany,
+		SignatureType:          utils.SignatureTypeCCLA,
+		SignatureACL:           []string{"manager-one"},
+		EmailWhitelist:         []string{"existing@example.org"},
+	})
+	seed(t, signatures.ItemSignature{
+		SignatureID:            iclaID,
+		SignatureApproved:      true,
+		SignatureSigned:        true,
+		SignatureProjectID:     claGroupID,
+		SignatureReferenceID:   "user-1234",
+		SignatureReferenceName: "Test User",
+		SignatureReferenceType: utils.SignatureReferenceTypeUser,
+		SignatureType:          utils.SignatureTypeCLA,
+	})
+
+	ccla, err := repo.GetCorporateSignature(ctx, claGroupID, companyID, &approved, &signed)
+	assert.NoError(t, err)
+	assert.Equal(t, cclaID, ccla.SignatureID)
+	assert.Equal(t, utils.ClaTypeCCLA, ccla.ClaType)
+
+	icla, err := repo.GetIndividualSignature(ctx, claGroupID, "user-1234", &approved, &signed)
+	assert.NoError(t, err)
+	assert.Equal(t, iclaID, icla.SignatureID)
+	assert.Equal(t, utils.ClaTypeICLA, icla.ClaType)
+
+	updated, err := repo.UpdateApprovalList(ctx, &models.User{LfUsername: "manager-one"}, &models.ClaGroup{ProjectID: claGroupID}, companyID, &models.ApprovalList{
+		AddEmailApprovalList:  []string{"new@example.org"},
+		AddDomainApprovalList: []string{"example.org"},
+	}, &events.LogEventArgs{})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"existing@example.org", "new@example.org"}, updated.EmailApprovalList)
+	assert.ElementsMatch(t, []string{"example.org"}, updated.DomainApprovalList)
+
+	_, err = repo.AddCLAManager(ctx, cclaID, "manager-two")
+	assert.NoError(t, err)
+	acl, err := repo.GetSignatureACL(ctx, cclaID)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"manager-one", "manager-two"}, acl)
+
+	_, err = repo.RemoveCLAManager(ctx, cclaID, "manager-two")
+	assert.NoError(t, err)
+	acl, err = repo.GetSignatureACL(ctx, cclaID)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"manager-one"}, acl)
+
+	assert.NoError(t, repo.InvalidateProjectRecord(ctx, iclaID, "invalidated by test"))
+	invalidated, err := repo.GetIndividualSignature(ctx, claGroupID, "user-1234", &approved, &signed)
+	assert.NoError(t, err)
+	assert.Nil(t, invalidated, "invalidated signatures are no longer approved")
+}
@@ -0,0 +1,90 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/config"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestTelemetryMetrics(t *testing.T) {
+	operation := "getTelemetryTest" + uniqueTestID(t)
+	telemetry.ObserveHTTPRequest(operation, http.MethodGet, http.StatusOK, 25*time.Millisecond)
+	telemetry.ObserveHTTPRequest(operation, http.MethodGet, http.StatusOK, 75*time.Millisecond)
+	telemetry.ObserveDynamoDBRequest("cla-test-telemetry", "Query", 10*time.Millisecond, nil)
+	telemetry.ObserveDynamoDBRequest("cla-test-telemetry", "Query", 10*time.Millisecond, errors.New("throttled"))
+	telemetry.ObserveEvent("telemetry.test", nil)
+
+	snapshot, err := telemetry.Snapshot()
+	assert.NoError(t, err)
+	assert.Equal(t, float64(2), snapshot[`easycla_http_request_duration_seconds_count{code="200",method="GET",operation="`+operation+`"}`])
+	assert.InDelta(t, 0.1, snapshot[`easycla_http_request_duration_seconds_sum{code="200",method="GET",operation="`+operation+`"}`], 0.0001)
+	assert.Equal(t, float64(1), snapshot[`easycla_dynamodb_requests_total{operation="Query",outcome="error",table="cla-test-telemetry"}`])
+	assert.Equal(t, float64(1), snapshot[`easycla_events_logged_total{event_type="telemetry.test",outcome="success"}`])
+
+	recorder := httptest.NewRecorder()
+	telemetry.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "easycla_dynamodb_request_duration_seconds_bucket")
+}
+
+func TestTelemetryGitHubTransport(t *testing.T) {
+	githubServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "4321")
+		w.Header().Set("X-RateLimit-Resource", "graphql")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer githubServer.Close()
+
+	client := &http.Client{Transport: telemetry.NewGitHubTransport(nil)}
+	resp, err := client.Get(githubServer.URL)
+	assert.NoError(t, err)
+	_, _ = ioutil.ReadAll(resp.Body)
+	assert.NoError(t, resp.Body.Close())
+
+	snapshot, err := telemetry.Snapshot()
+	assert.NoError(t, err)
+	assert.Equal(t, float64(4321), snapshot[`easycla_github_rate_limit_remaining{resource="graphql"}`])
+	assert.GreaterOrEqual(t, snapshot[`easycla_github_requests_total{code="200",method="GET"}`], float64(1))
+}
+
+func TestTelemetryTraceContextPropagation(t *testing.T) {
+	// no endpoint - only the propagator is installed
+	shutdown, err := telemetry.InitTracing(context.Background(), "easycla-test", config.Tracing{})
+	assert.NoError(t, err)
+	defer func() { assert.NoError(t, shutdown(context.Background())) }()
+
+	previousProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	defer otel.SetTracerProvider(previousProvider)
+
+	var traceParent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceParent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	ctx, span := telemetry.StartSpan(context.Background(), "tests.TestTelemetryTraceContextPropagation")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	assert.NoError(t, err)
+	resp, err := telemetry.NewHTTPClient(5 * time.Second).Do(req)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	span.End()
+
+	// version-traceid-parentid-flags, the outbound call is a child of the span
+	assert.Regexp(t, "^00-"+span.SpanContext().TraceID().String()+"-[0-9a-f]{16}-01$", traceParent)
+}
@@ -18,5 +18,7 @@ type DBUser struct {
 	UserGithubID       string   `json:"user_github_id"`
 	UserCompanyID      string   `json:"user_company_id"`
 	UserGithubUsername string   `json:"user_github_username"`
+	UserGitlabID       string   `json:"user_gitlab_id"`
+	UserGitlabUsername string   `json:"user_gitlab_username"`
 	Note               string   `json:"note"`
 }
@@ -40,6 +40,7 @@ type UserRepository interface {
 	GetUserByUserName(userName string, fullMatch bool) (*models.User, error)
 	GetUserByEmail(userEmail string) (*models.User, error)
 	GetUserByGitHubUsername(gitHubUsername string) (*models.User, error)
+	GetUserByGitLabID(gitLabID string) (*models.User, error)
 	SearchUsers(searchField string, searchTerm string, fullMatch bool) (*models.Users, error)
 }
 
@@ -100,6 +101,18 @@ func (repo repository) CreateUser(user *models.User) (*models.User, error) {
 		}
 	}
 
+	if user.GitlabID != "" {
+		attributes["user_gitlab_id"] = &dynamodb.AttributeValue{
+			S: aws.String(user.GitlabID),
+		}
+	}
+
+	if user.GitlabUsername != "" {
+		attributes["user_gitlab_username"] = &dynamodb.AttributeValue{
+			S: aws.String(user.GitlabUsername),
+		}
+	}
+
 	if user.LfEmail != "" {
 		attributes["lf_email"] = &dynamodb.AttributeValue{
 			S: aws.String(user.LfEmail),
@@ -748,6 +761,54 @@ func (repo repository) GetUserByGitHubUsername(gitHubUsername string) (*models.U
 	return convertDBUserModel(dbUserModels[0]), nil
 }
 
+// GetUserByGitLabID fetches the user record by the GitLab user ID
+func (repo repository) GetUserByGitLabID(gitLabID string) (*models.User, error) {
+	f := logrus.Fields{
+		"functionName": "users.repository.GetUserByGitLabID",
+		"gitLabID":     gitLabID,
+	}
+	// This is the key we want to match
+	condition := expression.Key("user_gitlab_id").Equal(expression.Value(gitLabID))
+
+	// Use the nice builder to create the expression
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).WithProjection(buildUserProjection()).Build()
+	if err != nil {
+		log.WithFields(f).WithError(err).Warnf("error building expression for user_gitlab_id : %s, error: %v", gitLabID, err)
+		return nil, err
+	}
+
+	// Assemble the query input parameters
+	queryInput := &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ProjectionExpression:      expr.Projection(),
+		TableName:                 aws.String(repo.tableName),
+		IndexName:                 aws.String("gitlab-user-index"),
+	}
+
+	result, err := repo.dynamoDBClient.Query(queryInput)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warnf("error retrieving user by user_gitlab_id: %s, error: %+v", gitLabID, err)
+		return nil, err
+	}
+
+	var dbUserModels []DBUser
+	err = dynamodbattribute.UnmarshalListOfMaps(result.Items, &dbUserModels)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warnf("error unmarshalling user record from database for user_gitlab_id: %s, error: %+v", gitLabID, err)
+		return nil, err
+	}
+
+	if len(dbUserModels) == 0 {
+		return nil, errors.NotFound("user not found when searching by user_gitlab_id: %s", gitLabID)
+	} else if len(dbUserModels) > 1 {
+		log.WithFields(f).Warnf("retrieved %d results for the user_gitlab_id query when we should return 0 or 1", len(dbUserModels))
+	}
+
+	return convertDBUserModel(dbUserModels[0]), nil
+}
+
 func (repo repository) SearchUsers(searchField string, searchTerm string, fullMatch bool) (*models.Users, error) {
 	f := logrus.Fields{
 		"functionName": "users.repository.SearchUsers",
@@ -868,6 +929,8 @@ func convertDBUserModel(user DBUser) *models.User {
 		GithubID:       user.UserGithubID,
 		CompanyID:      user.UserCompanyID,
 		GithubUsername: user.UserGithubUsername,
+		GitlabID:       user.UserGitlabID,
+		GitlabUsername: user.UserGitlabUsername,
 		Note:           user.Note,
 	}
 }
@@ -885,6 +948,8 @@ func buildUserProjection() expression.ProjectionBuilder {
 		expression.Name("user_emails"),
 		expression.Name("user_github_username"),
 		expression.Name("user_github_id"),
+		expression.Name("user_gitlab_id"),
+		expression.Name("user_gitlab_username"),
 		expression.Name("date_created"),
 		expression.Name("date_modified"),
 		expression.Name("version"),
@@ -0,0 +1,279 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package users
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/go-openapi/errors"
+	"github.com/google/uuid"
+)
+
+// memoryRepository is an embedded, in-memory implementation of the UserRepository. It is intended for local
+// development and tests where DynamoDB is not available.
+type memoryRepository struct {
+	lock  sync.RWMutex
+	users map[string]DBUser
+}
+
+// NewMemoryRepository creates a new instance of the in-memory user repository
+func NewMemoryRepository() UserRepository {
+	return &memoryRepository{
+		users: map[string]DBUser{},
+	}
+}
+
+// CreateUser creates a new user
+func (repo *memoryRepository) CreateUser(user *models.User) (*models.User, error) {
+	theUUID, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	user.UserID = theUUID.String()
+	user.DateCreated = now
+	user.DateModified = now
+	user.Version = "v1"
+
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	repo.users[user.UserID] = DBUser{
+		UserID:             user.UserID,
+		UserExternalID:     user.UserExternalID,
+		LFEmail:            user.LfEmail,
+		Admin:              user.Admin,
+		LFUsername:         user.LfUsername,
+		DateCreated:        user.DateCreated,
+		DateModified:       user.DateModified,
+		UserName:           user.Username,
+		Version:            user.Version,
+		UserGithubID:       user.GithubID,
+		UserGithubUsername: user.GithubUsername,
+		UserGitlabID:       user.GitlabID,
+		UserGitlabUsername: user.GitlabUsername,
+	}
+
+	return user, nil
+}
+
+// Save saves the user model to the data store
+func (repo *memoryRepository) Save(user *models.UserUpdate) (*models.User, error) {
+	existing, err := repo.getUserByUpdateModel(user)
+	if err != nil || existing == nil {
+		return nil, err
+	}
+
+	repo.lock.Lock()
+	dbUser := repo.users[existing.UserID]
+	if user.LfEmail != "" {
+		dbUser.LFEmail = user.LfEmail
+	}
+	if user.UserExternalID != "" {
+		dbUser.UserExternalID = user.UserExternalID
+	}
+	if user.Emails != nil {
+		dbUser.UserEmails = append([]string{}, user.Emails...)
+	}
+	if user.LfUsername != "" {
+		dbUser.LFUsername = user.LfUsername
+	}
+	if user.Username != "" {
+		dbUser.UserName = user.Username
+	}
+	if user.CompanyID != "" {
+		dbUser.UserCompanyID = user.CompanyID
+	}
+	if user.GithubUsername != "" {
+		dbUser.UserGithubUsername = user.GithubUsername
+	}
+	if user.GithubID != "" {
+		dbUser.UserGithubID = user.GithubID
+	}
+	dbUser.DateModified = time.Now().UTC().Format(time.RFC3339)
+	repo.users[dbUser.UserID] = dbUser
+	repo.lock.Unlock()
+
+	return repo.getUserByUpdateModel(user)
+}
+
+// Delete deletes the specified user
+func (repo *memoryRepository) Delete(userID string) error {
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+	delete(repo.users, userID)
+	return nil
+}
+
+// GetUser retrieves the specified user using the user id
+func (repo *memoryRepository) GetUser(userID string) (*models.User, error) {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	dbUser, ok := repo.users[userID]
+	if !ok {
+		return nil, nil
+	}
+	return convertDBUserModel(dbUser), nil
+}
+
+// GetUserByLFUserName returns the user record associated with the LF Username value
+func (repo *memoryRepository) GetUserByLFUserName(lfUserName string) (*models.User, error) {
+	return repo.findFirst(func(u *DBUser) bool {
+		return u.LFUsername == lfUserName
+	}), nil
+}
+
+// GetUserByExternalID returns the user record associated with the UserExternalID value
+func (repo *memoryRepository) GetUserByExternalID(userExternalID string) (*models.User, error) {
+	return repo.findFirst(func(u *DBUser) bool {
+		return u.UserExternalID == userExternalID
+	}), nil
+}
+
+// GetUserByUserName fetches the user record by LF username, or by GitHub ID if the name has the github: prefix
+func (repo *memoryRepository) GetUserByUserName(userName string, fullMatch bool) (*models.User, error) {
+	if strings.Contains(userName, "github:") {
+		githubID := strings.Replace(userName, "github:", "", 1)
+		return repo.findFirst(func(u *DBUser) bool {
+			return u.UserGithubID == githubID
+		}), nil
+	}
+	return repo.findFirst(func(u *DBUser) bool {
+		return u.LFUsername == userName
+	}), nil
+}
+
+// GetUserByEmail fetches the user record by email
+func (repo *memoryRepository) GetUserByEmail(userEmail string) (*models.User, error) {
+	user := repo.findFirst(func(u *DBUser) bool {
+		return u.LFEmail == userEmail
+	})
+	if user == nil {
+		return nil, &utils.UserNotFound{
+			Message:   fmt.Sprintf("user not found when searching by lf email: %s", userEmail),
+			UserEmail: userEmail,
+		}
+	}
+	return user, nil
+}
+
+// GetUserByGitHubUsername fetches the user record by github username
+func (repo *memoryRepository) GetUserByGitHubUsername(gitHubUsername string) (*models.User, error) {
+	user := repo.findFirst(func(u *DBUser) bool {
+		return u.UserGithubUsername == gitHubUsername
+	})
+	if user == nil {
+		return nil, errors.NotFound("user not found when searching by user_github_username: %s", gitHubUsername)
+	}
+	return user, nil
+}
+
+// GetUserByGitLabID fetches the user record by the GitLab user ID
+func (repo *memoryRepository) GetUserByGitLabID(gitLabID string) (*models.User, error) {
+	user := repo.findFirst(func(u *DBUser) bool {
+		return u.UserGitlabID == gitLabID
+	})
+	if user == nil {
+		return nil, errors.NotFound("user not found when searching by user_gitlab_id: %s", gitLabID)
+	}
+	return user, nil
+}
+
+// SearchUsers returns the users whose search field column matches the search term
+func (repo *memoryRepository) SearchUsers(searchField string, searchTerm string, fullMatch bool) (*models.Users, error) {
+	if strings.TrimSpace(searchTerm) == "" || strings.TrimSpace(searchField) == "" {
+		return &models.Users{
+			Users:      []models.User{},
+			SearchTerm: searchTerm,
+		}, nil
+	}
+
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	var users []models.User
+	for _, dbUser := range repo.sortedUsers() {
+		value, ok := dbUserColumnValue(dbUser, searchField)
+		if !ok {
+			continue
+		}
+		if (fullMatch && value == searchTerm) || (!fullMatch && strings.Contains(value, searchTerm)) {
+			users = append(users, *convertDBUserModel(dbUser))
+		}
+	}
+
+	return &models.Users{
+		ResultCount: int64(len(users)),
+		TotalCount:  int64(len(repo.users)),
+		Users:       users,
+	}, nil
+}
+
+// getUserByUpdateModel looks up the existing user by LF username, falling back to the GitHub username
+func (repo *memoryRepository) getUserByUpdateModel(user *models.UserUpdate) (*models.User, error) {
+	if user.LfUsername != "" {
+		existing, err := repo.GetUserByUserName(user.LfUsername, true)
+		if err != nil || existing != nil {
+			return existing, err
+		}
+	}
+	if user.GithubUsername != "" {
+		return repo.GetUserByGitHubUsername(user.GithubUsername)
+	}
+	return nil, nil
+}
+
+// findFirst returns the first user, ordered by user ID, matching the filter or nil if none match
+func (repo *memoryRepository) findFirst(match func(u *DBUser) bool) *models.User {
+	repo.lock.RLock()
+	defer repo.lock.RUnlock()
+	for _, dbUser := range repo.sortedUsers() {
+		if match(&dbUser) {
+			return convertDBUserModel(dbUser)
+		}
+	}
+	return nil
+}
+
+// sortedUsers returns the stored users in a stable order - caller must hold the lock
+func (repo *memoryRepository) sortedUsers() []DBUser {
+	users := make([]DBUser, 0, len(repo.users))
+	for _, dbUser := range repo.users {
+		users = append(users, dbUser)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].UserID < users[j].UserID
+	})
+	return users
+}
+
+// dbUserColumnValue returns the value of the named string column of the user record
+func dbUserColumnValue(user DBUser, column string) (string, bool) {
+	switch column {
+	case "user_id":
+		return user.UserID, true
+	case "user_external_id":
+		return user.UserExternalID, true
+	case "user_company_id":
+		return user.UserCompanyID, true
+	case "lf_email":
+		return user.LFEmail, true
+	case "lf_username":
+		return user.LFUsername, true
+	case "user_name":
+		return user.UserName, true
+	case "user_github_username":
+		return user.UserGithubUsername, true
+	case "user_github_id":
+		return user.UserGithubID, true
+	case "note":
+		return user.Note, true
+	}
+	return "", false
+}
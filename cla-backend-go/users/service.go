@@ -21,6 +21,7 @@ type Service interface {
 	GetUserByUserName(userName string, fullMatch bool) (*models.User, error)
 	GetUserByEmail(userEmail string) (*models.User, error)
 	GetUserByGitHubUsername(gitHubUsername string) (*models.User, error)
+	GetUserByGitLabID(gitLabID string) (*models.User, error)
 	SearchUsers(field string, searchTerm string, fullMatch bool) (*models.Users, error)
 }
 
@@ -141,6 +142,14 @@ func (s service) GetUserByGitHubUsername(gitHubUsername string) (*models.User, e
 	return s.repo.GetUserByGitHubUsername(gitHubUsername)
 }
 
+// GetUserByGitLabID fetches the user by the GitLab user ID
+func (s service) GetUserByGitLabID(gitLabID string) (*models.User, error) {
+	if gitLabID == "" {
+		return nil, errors.New("gitLabID is empty")
+	}
+	return s.repo.GetUserByGitLabID(gitLabID)
+}
+
 // SearchUsers attempts to locate the user by the searchField and searchTerm fields
 func (s service) SearchUsers(searchField string, searchTerm string, fullMatch bool) (*models.Users, error) {
 	return s.repo.SearchUsers(searchField, searchTerm, fullMatch)
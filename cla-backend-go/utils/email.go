@@ -12,6 +12,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	"github.com/communitybridge/easycla/cla-backend-go/config"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -96,7 +97,9 @@ func SendEmail(subject string, body string, recipients []string) error {
 	if emailSender == nil {
 		return errors.New("email sender not set")
 	}
-	return emailSender.SendEmail(subject, body, recipients)
+	err := emailSender.SendEmail(subject, body, recipients)
+	telemetry.ObserveEmail(err)
+	return err
 }
 
 // GetCorporateURL returns the corporate URL based on the specified flag
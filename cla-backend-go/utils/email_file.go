@@ -0,0 +1,132 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+)
+
+// CapturedEmail is an email written by the file email sender
+type CapturedEmail struct {
+	Subject    string
+	Body       string
+	Recipients []string
+	// Path is the location of the .eml file holding the MIME message
+	Path string
+}
+
+// FileEmailSender writes every email as a MIME .eml file to the capture directory, useful for local development and
+// for asserting on the rendered emails in tests
+type FileEmailSender struct {
+	lock               sync.Mutex
+	dir                string
+	senderEmailAddress string
+	messages           []CapturedEmail
+}
+
+var fileNameRegex = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// fileNameTimeFormat starts the file names, the files of the capture directory sort by the time they were written
+const fileNameTimeFormat = "20060102T150405.000000000Z"
+
+// maxFileNameAttempts is the number of suffixed names tried when the name of an email is already taken
+const maxFileNameAttempts = 100
+
+// NewFileEmailSender creates a new file email sender, the capture directory is created if it doesn't exist
+func NewFileEmailSender(dir string, senderEmailAddress string) (*FileEmailSender, error) {
+	if dir == "" {
+		return nil, errors.New("file email sender requires the capture directory")
+	}
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return nil, err
+	}
+	return &FileEmailSender{
+		dir:                dir,
+		senderEmailAddress: senderEmailAddress,
+	}, nil
+}
+
+// SendEmail writes the email to the capture directory
+func (s *FileEmailSender) SendEmail(subject string, body string, recipients []string) error {
+	f := logrus.Fields{
+		"functionName": "utils.FileEmailSender.SendEmail",
+		"subject":      subject,
+		"recipients":   strings.Join(recipients, ","),
+	}
+
+	message, err := BuildEmailMessage(s.senderEmailAddress, recipients, subject, body)
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	name := strings.Trim(fileNameRegex.ReplaceAllString(strings.ToLower(subject), "-"), "-")
+	if len(name) > 60 {
+		name = name[:60]
+	}
+	path, err := s.writeFile(fmt.Sprintf("%s-%s", time.Now().UTC().Format(fileNameTimeFormat), name), message)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warnf("unable to write the email to the directory: %s", s.dir)
+		return err
+	}
+
+	s.messages = append(s.messages, CapturedEmail{
+		Subject:    subject,
+		Body:       body,
+		Recipients: append([]string(nil), recipients...),
+		Path:       path,
+	})
+	log.WithFields(f).Debugf("email written to: %s", path)
+	return nil
+}
+
+// writeFile writes the message to a new file of the capture directory and returns its path. The capture directory may
+// hold the emails of an earlier run or of another process - an existing file is never overwritten, the name is
+// suffixed with a counter instead.
+func (s *FileEmailSender) writeFile(name string, message []byte) (string, error) {
+	for attempt := 1; attempt <= maxFileNameAttempts; attempt++ {
+		path := filepath.Join(s.dir, name+".eml")
+		if attempt > 1 {
+			path = filepath.Join(s.dir, fmt.Sprintf("%s-%d.eml", name, attempt))
+		}
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0640)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if _, err = file.Write(message); err != nil {
+			file.Close() // nolint
+			return "", err
+		}
+		return path, file.Close()
+	}
+	return "", fmt.Errorf("unable to find a free file name for: %s", name)
+}
+
+// Messages returns the emails written so far, oldest first
+func (s *FileEmailSender) Messages() []CapturedEmail {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([]CapturedEmail(nil), s.messages...)
+}
+
+// Dir returns the capture directory
+func (s *FileEmailSender) Dir() string {
+	return s.dir
+}
@@ -0,0 +1,269 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package utils
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/config"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+)
+
+// smtpDialTimeout is the timeout of the connection to the SMTP server
+const smtpDialTimeout = 30 * time.Second
+
+type smtpEmail struct {
+	smtpConfig         config.SMTP
+	senderEmailAddress string
+}
+
+// NewSMTPEmailSender creates an email sender which delivers the emails through the SMTP server
+func NewSMTPEmailSender(smtpConfig config.SMTP, senderEmailAddress string) EmailSender {
+	if smtpConfig.TLSMode == "" {
+		smtpConfig.TLSMode = config.SMTPTLSModeStartTLS
+	}
+	if smtpConfig.Port == 0 {
+		switch smtpConfig.TLSMode {
+		case config.SMTPTLSModeTLS:
+			smtpConfig.Port = 465
+		case config.SMTPTLSModeNone:
+			smtpConfig.Port = 25
+		default:
+			smtpConfig.Port = 587
+		}
+	}
+	return &smtpEmail{
+		smtpConfig:         smtpConfig,
+		senderEmailAddress: senderEmailAddress,
+	}
+}
+
+// SetSMTPEmailSender set smtp as mechanism to send email
+func SetSMTPEmailSender(smtpConfig config.SMTP, senderEmailAddress string) {
+	emailSender = NewSMTPEmailSender(smtpConfig, senderEmailAddress)
+}
+
+// SetEmailSenderFromConfig sets up the email sender selected by the configuration, defaults to sns
+func SetEmailSenderFromConfig(awsSession *session.Session, configFile config.Config) error {
+	switch configFile.EmailSender {
+	case "", config.EmailSenderSNS:
+		SetSnsEmailSender(awsSession, configFile.SNSEventTopicARN, configFile.SenderEmailAddress)
+	case config.EmailSenderSMTP:
+		if configFile.SMTP.Host == "" {
+			return errors.New("smtp email sender requires the smtp host")
+		}
+		SetSMTPEmailSender(configFile.SMTP, configFile.SenderEmailAddress)
+	case config.EmailSenderFile:
+		fileSender, err := NewFileEmailSender(configFile.EmailCaptureDir, configFile.SenderEmailAddress)
+		if err != nil {
+			return err
+		}
+		SetEmailSender(fileSender)
+	default:
+		return fmt.Errorf("unsupported email sender: %s", configFile.EmailSender)
+	}
+	return nil
+}
+
+// SendEmail sends an email to the specified recipients
+func (s *smtpEmail) SendEmail(subject string, body string, recipients []string) error {
+	f := logrus.Fields{
+		"functionName": "utils.smtpEmail.SendEmail",
+		"subject":      subject,
+		"recipients":   strings.Join(recipients, ","),
+		"smtpHost":     s.smtpConfig.Host,
+	}
+	if len(recipients) == 0 {
+		return errors.New("no recipients")
+	}
+
+	message, err := BuildEmailMessage(s.senderEmailAddress, recipients, subject, body)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to build the email message")
+		return err
+	}
+
+	if err := s.send(recipients, message); err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to send the email")
+		return err
+	}
+
+	log.WithFields(f).Debug("Successfully sent the email")
+	return nil
+}
+
+// send delivers the message using the smtp protocol
+func (s *smtpEmail) send(recipients []string, message []byte) error {
+	address := net.JoinHostPort(s.smtpConfig.Host, strconv.Itoa(s.smtpConfig.Port))
+	tlsConfig := &tls.Config{
+		ServerName:         s.smtpConfig.Host,
+		InsecureSkipVerify: s.smtpConfig.InsecureSkipVerify, // nolint
+	}
+
+	var conn net.Conn
+	var err error
+	if s.smtpConfig.TLSMode == config.SMTPTLSModeTLS {
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: smtpDialTimeout}, "tcp", address, tlsConfig)
+	} else {
+		conn, err = net.DialTimeout("tcp", address, smtpDialTimeout)
+	}
+	if err != nil {
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, s.smtpConfig.Host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+
+	if s.smtpConfig.TLSMode == config.SMTPTLSModeStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("smtp server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+
+	if s.smtpConfig.Username != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("smtp server does not support AUTH")
+		}
+		if err := client.Auth(smtp.PlainAuth("", s.smtpConfig.Username, s.smtpConfig.Password, s.smtpConfig.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(emailAddress(s.senderEmailAddress)); err != nil {
+		return err
+	}
+	for _, recipient := range recipients {
+		if err := client.Rcpt(emailAddress(recipient)); err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(message); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+var (
+	htmlBreakRegex = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</li>|</h[1-6]>|</div>`)
+	htmlTagRegex   = regexp.MustCompile(`<[^>]*>`)
+	blankLineRegex = regexp.MustCompile(`\n{3,}`)
+)
+
+// EmailPlainText returns the plain text alternative of the html email body
+func EmailPlainText(body string) string {
+	text := htmlBreakRegex.ReplaceAllString(body, "$0\n")
+	text = htmlTagRegex.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	text = blankLineRegex.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return strings.TrimSpace(text) + "\n"
+}
+
+// BuildEmailMessage builds the multipart/alternative MIME message with the html body and its plain text alternative
+func BuildEmailMessage(sender string, recipients []string, subject string, body string) ([]byte, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	headers := []struct{ name, value string }{
+		{"From", sender},
+		{"To", strings.Join(recipients, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", subject)},
+		{"Date", time.Now().UTC().Format(time.RFC1123Z)},
+		{"Message-ID", messageID(sender)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", mw.Boundary())},
+	}
+	var headerBuf bytes.Buffer
+	for _, header := range headers {
+		fmt.Fprintf(&headerBuf, "%s: %s\r\n", header.name, header.value)
+	}
+	headerBuf.WriteString("\r\n")
+
+	parts := []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", EmailPlainText(body)},
+		{"text/html; charset=utf-8", body},
+	}
+	for _, part := range parts {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(pw)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	return append(headerBuf.Bytes(), buf.Bytes()...), nil
+}
+
+// messageID returns a unique Message-ID header value in the domain of the sender
+func messageID(sender string) string {
+	domain := "easycla.lfx.linuxfoundation.org"
+	if at := strings.LastIndex(emailAddress(sender), "@"); at >= 0 {
+		domain = emailAddress(sender)[at+1:]
+	}
+	random := make([]byte, 12)
+	if _, err := rand.Read(random); err != nil {
+		return fmt.Sprintf("<%d@%s>", time.Now().UnixNano(), domain)
+	}
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), hex.EncodeToString(random), domain)
+}
+
+// emailAddress returns the address part of the email, e.g. the address of "EasyCLA <admin@example.org>"
+func emailAddress(value string) string {
+	start, end := strings.LastIndex(value, "<"), strings.LastIndex(value, ">")
+	if start >= 0 && end > start {
+		return strings.TrimSpace(value[start+1 : end])
+	}
+	return strings.TrimSpace(value)
+}
@@ -5,6 +5,8 @@ package utils
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"io/ioutil"
 	"strings"
@@ -13,6 +15,7 @@ import (
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 )
@@ -20,6 +23,9 @@ import (
 // PresignedURLValidity is time for which s3 url will remain valid
 const PresignedURLValidity = 15 * time.Minute
 
+// ErrS3ObjectNotFound is returned when the downloaded file does not exist
+var ErrS3ObjectNotFound = errors.New("file does not exist on s3")
+
 // S3Storage provides methods to handle s3 storage
 type S3Storage interface {
 	Upload(fileContent []byte, projectID string, claType string, identifier string, signatureID string) error
@@ -36,19 +42,31 @@ type S3Client struct {
 	BucketName string
 }
 
-// SetS3Storage set default S3Storage
-func SetS3Storage(awsSession *session.Session, bucketName string) {
-	s3Storage = &S3Client{
+// NewS3Storage returns the S3Storage of the bucket
+func NewS3Storage(awsSession *session.Session, bucketName string) S3Storage {
+	return &S3Client{
 		s3:         s3.New(awsSession),
 		BucketName: bucketName,
 	}
 }
 
+// SetS3Storage set default S3Storage
+func SetS3Storage(awsSession *session.Session, bucketName string) {
+	s3Storage = NewS3Storage(awsSession, bucketName)
+}
+
+// DocumentSHA256 returns the hex encoded SHA-256 of the document, the hash the signed documents are recorded with on
+// their signature when they are stored - the integrity checks compare it with the stored document
+func DocumentSHA256(fileContent []byte) string {
+	sum := sha256.Sum256(fileContent)
+	return hex.EncodeToString(sum[:])
+}
+
 // Upload file to s3 storage at path contract-group/<project-ID>/<claType>/<identifier>/<signatureID>.pdf
 // claType should be cla or ccla
 // identifier can be user-id or company-id
 func (s3c *S3Client) Upload(fileContent []byte, projectID string, claType string, identifier string, signatureID string) error {
-	filename := strings.Join([]string{"contract-group", projectID, claType, identifier, signatureID}, "/") + ".pdf"
+	filename := SignedCLAFilename(projectID, claType, identifier, signatureID)
 	_, err := s3c.s3.PutObject(&s3.PutObjectInput{
 		Bucket: aws.String(s3c.BucketName),
 		Key:    aws.String(filename),
@@ -64,6 +82,9 @@ func (s3c *S3Client) Download(filename string) ([]byte, error) {
 		Key:    aws.String(filename),
 	})
 	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+			return nil, ErrS3ObjectNotFound
+		}
 		log.Warnf("problem downloading from s3 bucket: %s resource: %s, error: %+v",
 			s3c.BucketName, filename, err)
 		return nil, err
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/communitybridge/easycla/cla-backend-go/v2/acs-service/client/role"
 
@@ -20,6 +21,7 @@ import (
 
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 	"github.com/communitybridge/easycla/cla-backend-go/token"
+	platform_client "github.com/communitybridge/easycla/cla-backend-go/v2/platform-client"
 
 	"github.com/communitybridge/easycla/cla-backend-go/v2/acs-service/client"
 	"github.com/communitybridge/easycla/cla-backend-go/v2/acs-service/client/invite"
@@ -33,11 +35,16 @@ import (
 
 // Client is client for acs_service
 type Client struct {
-	apiKey   string
-	apiGwURL string
-	cl       *client.CentralAuthorizationLayerForTheLFXPlatform
+	apiKey            string
+	apiGwURL          string
+	cl                *client.CentralAuthorizationLayerForTheLFXPlatform
+	roleIDCache       platform_client.Cache
+	objectTypeIDCache platform_client.Cache
 }
 
+// lookupCacheTTL is how long the role and object type IDs are cached - they rarely change
+const lookupCacheTTL = time.Hour
+
 var (
 	acsServiceClient *Client
 )
@@ -51,14 +58,13 @@ var (
 // InitClient initializes the acs_service client
 func InitClient(APIGwURL string, apiKey string) {
 	url := strings.ReplaceAll(APIGwURL, "https://", "")
+	dependency := platform_client.NewDependency(platform_client.DefaultConfig("acs-service", 10*time.Second))
 	acsServiceClient = &Client{
-		apiKey:   apiKey,
-		apiGwURL: APIGwURL,
-		cl: client.NewHTTPClientWithConfig(strfmt.Default, &client.TransportConfig{
-			Host:     url,
-			BasePath: "acs/v1/api",
-			Schemes:  []string{"https"},
-		}),
+		apiKey:            apiKey,
+		apiGwURL:          APIGwURL,
+		cl:                client.New(dependency.SwaggerTransport(url, "acs/v1/api"), strfmt.Default),
+		roleIDCache:       platform_client.NewCache(lookupCacheTTL, 100),
+		objectTypeIDCache: platform_client.NewCache(lookupCacheTTL, 100),
 	}
 }
 
@@ -147,8 +153,19 @@ func (ac *Client) SendUserInvite(ctx context.Context, input *SendUserInviteInput
 	return nil
 }
 
-// GetRoleID will return roleID for the provided role name
+// GetRoleID will return roleID for the provided role name, the role IDs are cached for lookupCacheTTL
 func (ac *Client) GetRoleID(roleName string) (string, error) {
+	roleID, err := ac.roleIDCache.GetOrLoad(roleName, func() (interface{}, error) {
+		return ac.getRoleID(roleName)
+	})
+	if err != nil {
+		return "", err
+	}
+	return roleID.(string), nil
+}
+
+// getRoleID looks up the roleID of the role name in the service
+func (ac *Client) getRoleID(roleName string) (string, error) {
 	f := logrus.Fields{
 		"functionName": "GetRoleID",
 		"roleName":     roleName,
@@ -180,8 +197,19 @@ func (ac *Client) GetRoleID(roleName string) (string, error) {
 	return "", ErrRoleNotFound
 }
 
-// GetObjectTypeIDByName will return object type ID for the provided role name
+// GetObjectTypeIDByName will return object type ID for the provided role name, the IDs are cached for lookupCacheTTL
 func (ac *Client) GetObjectTypeIDByName(objectType string) (int, error) {
+	objectTypeID, err := ac.objectTypeIDCache.GetOrLoad(objectType, func() (interface{}, error) {
+		return ac.getObjectTypeIDByName(objectType)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return objectTypeID.(int), nil
+}
+
+// getObjectTypeIDByName looks up the object type ID of the name in the service
+func (ac *Client) getObjectTypeIDByName(objectType string) (int, error) {
 	f := logrus.Fields{
 		"functionName": "GetObjectTypeID",
 		"objectType":   objectType,
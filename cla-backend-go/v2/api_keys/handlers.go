@@ -0,0 +1,272 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package api_keys
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/sirupsen/logrus"
+
+	keys "github.com/communitybridge/easycla/cla-backend-go/api_keys"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations/api_keys"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// Configure setups handlers on api with service
+func Configure(api *operations.EasyclaAPI, service keys.Service) {
+	// isAuthorized checks the user is an admin of the project tree or of the organization of the keys
+	isAuthorized := func(ctx context.Context, authUser *auth.User, scopeType, scopeID string) bool {
+		switch scopeType {
+		case keys.ScopeTypeProject:
+			return utils.IsUserAuthorizedForProjectTree(ctx, authUser, scopeID, utils.ALLOW_ADMIN_SCOPE)
+		case keys.ScopeTypeOrganization:
+			return utils.IsUserAuthorizedForOrganization(ctx, authUser, scopeID, utils.ALLOW_ADMIN_SCOPE)
+		}
+		return false
+	}
+
+	api.APIKeysListAPIKeysHandler = api_keys.ListAPIKeysHandlerFunc(
+		func(params api_keys.ListAPIKeysParams, authUser *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+			f := logrus.Fields{
+				"functionName":   "v2.api_keys.handlers.APIKeysListAPIKeysHandler",
+				utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+				"authUser":       authUser.UserName,
+				"scopeType":      params.ScopeType,
+				"scopeID":        params.ScopeID,
+			}
+
+			if !isAuthorized(ctx, authUser, params.ScopeType, params.ScopeID) {
+				msg := fmt.Sprintf("user %s does not have access to List the API Keys of the %s %s", authUser.UserName, params.ScopeType, params.ScopeID)
+				log.WithFields(f).Debug(msg)
+				return api_keys.NewListAPIKeysForbidden().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseForbidden(reqID, msg))
+			}
+
+			results, err := service.ListKeys(ctx, params.ScopeType, params.ScopeID)
+			if err != nil {
+				msg := fmt.Sprintf("unable to list the API keys of the %s %s", params.ScopeType, params.ScopeID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				if errors.Is(err, keys.ErrInvalidScope) {
+					return api_keys.NewListAPIKeysBadRequest().WithXRequestID(reqID).WithPayload(
+						utils.ErrorResponseBadRequestWithError(reqID, msg, err))
+				}
+				return api_keys.NewListAPIKeysInternalServerError().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+			}
+			return api_keys.NewListAPIKeysOK().WithXRequestID(reqID).WithPayload(toAPIKeyList(results))
+		})
+
+	api.APIKeysCreateAPIKeyHandler = api_keys.CreateAPIKeyHandlerFunc(
+		func(params api_keys.CreateAPIKeyParams, authUser *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+			scopeType, scopeID := utils.StringValue(params.Body.ScopeType), utils.StringValue(params.Body.ScopeID)
+			f := logrus.Fields{
+				"functionName":   "v2.api_keys.handlers.APIKeysCreateAPIKeyHandler",
+				utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+				"authUser":       authUser.UserName,
+				"scopeType":      scopeType,
+				"scopeID":        scopeID,
+			}
+
+			if !isAuthorized(ctx, authUser, scopeType, scopeID) {
+				msg := fmt.Sprintf("user %s does not have access to Create API Keys for the %s %s", authUser.UserName, scopeType, scopeID)
+				log.WithFields(f).Debug(msg)
+				return api_keys.NewCreateAPIKeyForbidden().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseForbidden(reqID, msg))
+			}
+
+			key, apiKey, err := service.CreateKey(ctx, &keys.KeyInput{
+				Name:          utils.StringValue(params.Body.Name),
+				ScopeType:     scopeType,
+				ScopeID:       scopeID,
+				Operations:    params.Body.Operations,
+				ExpiresInDays: int(params.Body.ExpiresInDays),
+				CreatedBy:     authUser.UserName,
+			})
+			if err != nil {
+				msg := fmt.Sprintf("unable to create the API key for the %s %s", scopeType, scopeID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				if isValidationError(err) {
+					return api_keys.NewCreateAPIKeyBadRequest().WithXRequestID(reqID).WithPayload(
+						utils.ErrorResponseBadRequestWithError(reqID, msg, err))
+				}
+				return api_keys.NewCreateAPIKeyInternalServerError().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+			}
+			return api_keys.NewCreateAPIKeyOK().WithXRequestID(reqID).WithPayload(&models.APIKeySecret{
+				Key:    toAPIKey(key),
+				APIKey: apiKey,
+			})
+		})
+
+	// loadKey returns the key when the user is authorized for its scope, or the error response
+	loadKey := func(ctx context.Context, reqID string, authUser *auth.User, keyID, operation string, f logrus.Fields) (*keys.APIKey, *models.ErrorResponse, int) {
+		key, err := service.GetKey(ctx, keyID)
+		if err != nil {
+			msg := fmt.Sprintf("unable to load the API key: %s", keyID)
+			log.WithFields(f).WithError(err).Warn(msg)
+			if errors.Is(err, keys.ErrKeyDoesNotExist) {
+				return nil, utils.ErrorResponseNotFoundWithError(reqID, msg, err), http.StatusNotFound
+			}
+			return nil, utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err), http.StatusInternalServerError
+		}
+		if !isAuthorized(ctx, authUser, key.ScopeType, key.ScopeID) {
+			msg := fmt.Sprintf("user %s does not have access to %s the API Key %s", authUser.UserName, operation, keyID)
+			log.WithFields(f).Debug(msg)
+			return nil, utils.ErrorResponseForbidden(reqID, msg), http.StatusForbidden
+		}
+		return key, nil, 0
+	}
+
+	api.APIKeysGetAPIKeyHandler = api_keys.GetAPIKeyHandlerFunc(
+		func(params api_keys.GetAPIKeyParams, authUser *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+			f := logrus.Fields{
+				"functionName":   "v2.api_keys.handlers.APIKeysGetAPIKeyHandler",
+				utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+				"authUser":       authUser.UserName,
+				"keyID":          params.KeyID,
+			}
+
+			key, errResponse, status := loadKey(ctx, reqID, authUser, params.KeyID, "Get", f)
+			switch status {
+			case http.StatusForbidden:
+				return api_keys.NewGetAPIKeyForbidden().WithXRequestID(reqID).WithPayload(errResponse)
+			case http.StatusNotFound:
+				return api_keys.NewGetAPIKeyNotFound().WithXRequestID(reqID).WithPayload(errResponse)
+			case http.StatusInternalServerError:
+				return api_keys.NewGetAPIKeyInternalServerError().WithXRequestID(reqID).WithPayload(errResponse)
+			}
+			return api_keys.NewGetAPIKeyOK().WithXRequestID(reqID).WithPayload(toAPIKey(key))
+		})
+
+	api.APIKeysRotateAPIKeyHandler = api_keys.RotateAPIKeyHandlerFunc(
+		func(params api_keys.RotateAPIKeyParams, authUser *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+			f := logrus.Fields{
+				"functionName":   "v2.api_keys.handlers.APIKeysRotateAPIKeyHandler",
+				utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+				"authUser":       authUser.UserName,
+				"keyID":          params.KeyID,
+			}
+
+			_, errResponse, status := loadKey(ctx, reqID, authUser, params.KeyID, "Rotate", f)
+			switch status {
+			case http.StatusForbidden:
+				return api_keys.NewRotateAPIKeyForbidden().WithXRequestID(reqID).WithPayload(errResponse)
+			case http.StatusNotFound:
+				return api_keys.NewRotateAPIKeyNotFound().WithXRequestID(reqID).WithPayload(errResponse)
+			case http.StatusInternalServerError:
+				return api_keys.NewRotateAPIKeyInternalServerError().WithXRequestID(reqID).WithPayload(errResponse)
+			}
+
+			gracePeriod := keys.DefaultRotationGracePeriod
+			if params.Body != nil && params.Body.GracePeriodHours != nil {
+				gracePeriod = time.Duration(*params.Body.GracePeriodHours) * time.Hour
+			}
+			key, apiKey, err := service.RotateKey(ctx, params.KeyID, gracePeriod, authUser.UserName)
+			if err != nil {
+				msg := fmt.Sprintf("unable to rotate the API key: %s", params.KeyID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				if errors.Is(err, keys.ErrInvalidGracePeriod) {
+					return api_keys.NewRotateAPIKeyBadRequest().WithXRequestID(reqID).WithPayload(
+						utils.ErrorResponseBadRequestWithError(reqID, msg, err))
+				}
+				if errors.Is(err, keys.ErrKeyNotActive) || errors.Is(err, keys.ErrKeyAlreadyRotated) {
+					return api_keys.NewRotateAPIKeyConflict().WithXRequestID(reqID).WithPayload(
+						utils.ErrorResponseConflictWithError(reqID, msg, err))
+				}
+				return api_keys.NewRotateAPIKeyInternalServerError().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+			}
+			return api_keys.NewRotateAPIKeyOK().WithXRequestID(reqID).WithPayload(&models.APIKeySecret{
+				Key:    toAPIKey(key),
+				APIKey: apiKey,
+			})
+		})
+
+	api.APIKeysRevokeAPIKeyHandler = api_keys.RevokeAPIKeyHandlerFunc(
+		func(params api_keys.RevokeAPIKeyParams, authUser *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+			f := logrus.Fields{
+				"functionName":   "v2.api_keys.handlers.APIKeysRevokeAPIKeyHandler",
+				utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+				"authUser":       authUser.UserName,
+				"keyID":          params.KeyID,
+			}
+
+			_, errResponse, status := loadKey(ctx, reqID, authUser, params.KeyID, "Revoke", f)
+			switch status {
+			case http.StatusForbidden:
+				return api_keys.NewRevokeAPIKeyForbidden().WithXRequestID(reqID).WithPayload(errResponse)
+			case http.StatusNotFound:
+				return api_keys.NewRevokeAPIKeyNotFound().WithXRequestID(reqID).WithPayload(errResponse)
+			case http.StatusInternalServerError:
+				return api_keys.NewRevokeAPIKeyInternalServerError().WithXRequestID(reqID).WithPayload(errResponse)
+			}
+
+			key, err := service.RevokeKey(ctx, params.KeyID, authUser.UserName)
+			if err != nil {
+				msg := fmt.Sprintf("unable to revoke the API key: %s", params.KeyID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				return api_keys.NewRevokeAPIKeyInternalServerError().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+			}
+			return api_keys.NewRevokeAPIKeyOK().WithXRequestID(reqID).WithPayload(toAPIKey(key))
+		})
+}
+
+// isValidationError returns true when the key input is not valid
+func isValidationError(err error) bool {
+	return errors.Is(err, keys.ErrInvalidName) || errors.Is(err, keys.ErrInvalidScope) ||
+		errors.Is(err, keys.ErrInvalidOperations) || errors.Is(err, keys.ErrInvalidExpiry)
+}
+
+func toAPIKey(key *keys.APIKey) *models.APIKey {
+	return &models.APIKey{
+		KeyID:        key.KeyID,
+		Name:         key.Name,
+		ScopeType:    key.ScopeType,
+		ScopeID:      key.ScopeID,
+		Operations:   key.Operations,
+		Status:       key.Status(time.Now()),
+		DateExpires:  key.DateExpires,
+		DateRevoked:  key.DateRevoked,
+		DateLastUsed: key.DateLastUsed,
+		RotatedFrom:  key.RotatedFrom,
+		RotatedTo:    key.RotatedTo,
+		CreatedBy:    key.CreatedBy,
+		RevokedBy:    key.RevokedBy,
+		DateCreated:  key.DateCreated,
+		DateModified: key.DateModified,
+	}
+}
+
+func toAPIKeyList(results []*keys.APIKey) *models.APIKeyList {
+	list := make([]*models.APIKey, 0, len(results))
+	for _, key := range results {
+		list = append(list, toAPIKey(key))
+	}
+	return &models.APIKeyList{List: list}
+}
@@ -0,0 +1,142 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package cla_group_config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/sirupsen/logrus"
+
+	claGroupConfig "github.com/communitybridge/easycla/cla-backend-go/cla_group_config"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations/cla_group_config"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// Configure setups handlers on api with service
+func Configure(api *operations.EasyclaAPI, service claGroupConfig.Service, claGroupService project.Service) {
+	api.ClaGroupConfigExportClaGroupConfigHandler = cla_group_config.ExportClaGroupConfigHandlerFunc(
+		func(params cla_group_config.ExportClaGroupConfigParams, authUser *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+			f := logrus.Fields{
+				"functionName":   "v2.cla_group_config.handlers.ClaGroupConfigExportClaGroupConfigHandler",
+				utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+				"authUser":       authUser.UserName,
+				"claGroupID":     params.ClaGroupID,
+			}
+
+			claGroupModel, err := claGroupService.GetCLAGroupByID(ctx, params.ClaGroupID)
+			if err != nil || claGroupModel == nil {
+				msg := fmt.Sprintf("unable to load the CLA group: %s", params.ClaGroupID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				return cla_group_config.NewExportClaGroupConfigNotFound().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseNotFound(reqID, msg))
+			}
+			if !utils.IsUserAuthorizedForProjectTree(ctx, authUser, claGroupModel.ProjectExternalID, utils.ALLOW_ADMIN_SCOPE) {
+				msg := fmt.Sprintf("user %s does not have access to Export the Configuration of the CLA Group %s", authUser.UserName, params.ClaGroupID)
+				log.WithFields(f).Debug(msg)
+				return cla_group_config.NewExportClaGroupConfigForbidden().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseForbidden(reqID, msg))
+			}
+
+			manifest, err := service.Export(ctx, params.ClaGroupID)
+			if err != nil {
+				msg := fmt.Sprintf("unable to export the configuration of the CLA group: %s", params.ClaGroupID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				if errors.Is(err, claGroupConfig.ErrCLAGroupNotFound) {
+					return cla_group_config.NewExportClaGroupConfigNotFound().WithXRequestID(reqID).WithPayload(
+						utils.ErrorResponseNotFoundWithError(reqID, msg, err))
+				}
+				return cla_group_config.NewExportClaGroupConfigInternalServerError().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+			}
+			return cla_group_config.NewExportClaGroupConfigOK().WithXRequestID(reqID).WithPayload(manifest)
+		})
+
+	api.ClaGroupConfigPlanClaGroupConfigHandler = cla_group_config.PlanClaGroupConfigHandlerFunc(
+		func(params cla_group_config.PlanClaGroupConfigParams, authUser *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+			f := logrus.Fields{
+				"functionName":   "v2.cla_group_config.handlers.ClaGroupConfigPlanClaGroupConfigHandler",
+				utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+				"authUser":       authUser.UserName,
+			}
+
+			if err := claGroupConfig.ValidateManifest(params.Body); err != nil {
+				msg := "invalid CLA Group manifest"
+				log.WithFields(f).WithError(err).Warn(msg)
+				return cla_group_config.NewPlanClaGroupConfigBadRequest().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseBadRequestWithError(reqID, msg, err))
+			}
+			foundationSFID := params.Body.ClaGroup.FoundationSfid
+			if !utils.IsUserAuthorizedForProjectTree(ctx, authUser, foundationSFID, utils.ALLOW_ADMIN_SCOPE) {
+				msg := fmt.Sprintf("user %s does not have access to Plan the CLA Group Configuration of the foundation %s", authUser.UserName, foundationSFID)
+				log.WithFields(f).Debug(msg)
+				return cla_group_config.NewPlanClaGroupConfigForbidden().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseForbidden(reqID, msg))
+			}
+
+			result, err := service.Plan(ctx, authUser, params.Body)
+			if err != nil {
+				msg := fmt.Sprintf("unable to plan the configuration of the CLA group: %s", params.Body.ClaGroup.Name)
+				log.WithFields(f).WithError(err).Warn(msg)
+				if errors.Is(err, claGroupConfig.ErrInvalidManifest) {
+					return cla_group_config.NewPlanClaGroupConfigBadRequest().WithXRequestID(reqID).WithPayload(
+						utils.ErrorResponseBadRequestWithError(reqID, msg, err))
+				}
+				return cla_group_config.NewPlanClaGroupConfigInternalServerError().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+			}
+			return cla_group_config.NewPlanClaGroupConfigOK().WithXRequestID(reqID).WithPayload(result)
+		})
+
+	api.ClaGroupConfigApplyClaGroupConfigHandler = cla_group_config.ApplyClaGroupConfigHandlerFunc(
+		func(params cla_group_config.ApplyClaGroupConfigParams, authUser *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+			f := logrus.Fields{
+				"functionName":   "v2.cla_group_config.handlers.ClaGroupConfigApplyClaGroupConfigHandler",
+				utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+				"authUser":       authUser.UserName,
+			}
+
+			if err := claGroupConfig.ValidateManifest(params.Body); err != nil {
+				msg := "invalid CLA Group manifest"
+				log.WithFields(f).WithError(err).Warn(msg)
+				return cla_group_config.NewApplyClaGroupConfigBadRequest().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseBadRequestWithError(reqID, msg, err))
+			}
+			foundationSFID := params.Body.ClaGroup.FoundationSfid
+			if !utils.IsUserAuthorizedForProjectTree(ctx, authUser, foundationSFID, utils.ALLOW_ADMIN_SCOPE) {
+				msg := fmt.Sprintf("user %s does not have access to Apply the CLA Group Configuration of the foundation %s", authUser.UserName, foundationSFID)
+				log.WithFields(f).Debug(msg)
+				return cla_group_config.NewApplyClaGroupConfigForbidden().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseForbidden(reqID, msg))
+			}
+
+			result, err := service.Apply(ctx, authUser, params.Body)
+			if err != nil {
+				msg := fmt.Sprintf("unable to apply the configuration of the CLA group: %s", params.Body.ClaGroup.Name)
+				log.WithFields(f).WithError(err).Warn(msg)
+				if errors.Is(err, claGroupConfig.ErrInvalidManifest) {
+					return cla_group_config.NewApplyClaGroupConfigBadRequest().WithXRequestID(reqID).WithPayload(
+						utils.ErrorResponseBadRequestWithError(reqID, msg, err))
+				}
+				return cla_group_config.NewApplyClaGroupConfigInternalServerError().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+			}
+			return cla_group_config.NewApplyClaGroupConfigOK().WithXRequestID(reqID).WithPayload(result)
+		})
+}
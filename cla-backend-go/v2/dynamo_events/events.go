@@ -5,6 +5,7 @@ package dynamo_events
 
 import (
 	"github.com/aws/aws-lambda-go/events"
+	claevent "github.com/communitybridge/easycla/cla-backend-go/events"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
 	v2ProjectService "github.com/communitybridge/easycla/cla-backend-go/v2/project-service"
@@ -60,3 +61,81 @@ func (s *service) EventAddedEvent(event events.DynamoDBEventRecord) error {
 	}
 	return nil
 }
+
+// EventWebhooksHandler queues the new event for the event webhook subscriptions of its CLA group and company
+func (s *service) EventWebhooksHandler(event events.DynamoDBEventRecord) error {
+	ctx := utils.NewContext()
+	var newEvent claevent.Event
+	err := unmarshalStreamImage(event.Change.NewImage, &newEvent)
+	if err != nil {
+		return err
+	}
+	f := logrus.Fields{
+		"functionName": "dynamo_events.EventWebhooksHandler",
+		"eventID":      newEvent.EventID,
+		"eventType":    newEvent.EventType,
+	}
+
+	// the company SFID is not always set when the event is created
+	if newEvent.EventCompanySFID == "" && newEvent.EventCompanyID != "" {
+		companyModel, companyErr := s.companyRepo.GetCompany(ctx, newEvent.EventCompanyID)
+		if companyErr != nil {
+			log.WithFields(f).WithError(companyErr).Warn("unable to get company detail")
+		} else {
+			newEvent.EventCompanySFID = companyModel.CompanyExternalID
+		}
+	}
+
+	// a webhook failure must not fail the stream batch and replay the other handlers - the deliveries are queued
+	// and sent by the event webhooks lambda
+	if err := s.eventWebhooksService.PublishEvent(ctx, &newEvent); err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to queue the event webhook deliveries")
+	}
+	return nil
+}
+
+// EventSearchIndexHandler adds the new or updated event to the event search index - the event is indexed again
+// when the SFIDs and names are added to it
+func (s *service) EventSearchIndexHandler(event events.DynamoDBEventRecord) error {
+	ctx := utils.NewContext()
+	var newEvent claevent.Event
+	err := unmarshalStreamImage(event.Change.NewImage, &newEvent)
+	if err != nil {
+		return err
+	}
+	f := logrus.Fields{
+		"functionName":   "dynamo_events.EventSearchIndexHandler",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"eventID":        newEvent.EventID,
+		"eventType":      newEvent.EventType,
+	}
+
+	err = s.eventSearchService.IndexEvent(ctx, newEvent.ToEvent())
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to index the event")
+		return err
+	}
+	return nil
+}
+
+// EventSearchRemoveHandler removes the deleted event from the event search index
+func (s *service) EventSearchRemoveHandler(event events.DynamoDBEventRecord) error {
+	ctx := utils.NewContext()
+	var oldEvent claevent.Event
+	err := unmarshalStreamImage(event.Change.OldImage, &oldEvent)
+	if err != nil {
+		return err
+	}
+	f := logrus.Fields{
+		"functionName":   "dynamo_events.EventSearchRemoveHandler",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"eventID":        oldEvent.EventID,
+	}
+
+	err = s.eventSearchService.RemoveEvent(ctx, oldEvent.EventID)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to remove the event from the search index")
+		return err
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ package dynamo_events
 
 import (
 	"context"
+	"reflect"
 
 	"github.com/communitybridge/easycla/cla-backend-go/github/branch_protection"
 
@@ -38,7 +39,7 @@ func (s *service) GitHubOrgAddedEvent(event events.DynamoDBEventRecord) error {
 	// If the branch protection value was updated from false to true....
 	if newGitHubOrg.BranchProtectionEnabled {
 		log.WithFields(f).Debug("branchProtectionEnabled - processing...")
-		return s.enableBranchProtectionForGithubOrg(ctx, newGitHubOrg)
+		return s.enableBranchProtectionForGithubOrg(ctx, newGitHubOrg, nil)
 	}
 
 	if newGitHubOrg.AutoEnabled {
@@ -77,7 +78,17 @@ func (s *service) GitHubOrgUpdatedEvent(event events.DynamoDBEventRecord) error
 	// If the branch protection value was updated from false to true....
 	if !oldGitHubOrg.BranchProtectionEnabled && newGitHubOrg.BranchProtectionEnabled {
 		log.WithFields(f).Debug("transition of branchProtectionEnabled false => true - processing...")
-		return s.enableBranchProtectionForGithubOrg(ctx, newGitHubOrg)
+		return s.enableBranchProtectionForGithubOrg(ctx, newGitHubOrg, nil)
+	}
+
+	// If the branch protection policy changed, the new policy is applied on the repositories and the rules of the
+	// patterns dropped from the policy are removed
+	if newGitHubOrg.BranchProtectionEnabled && !reflect.DeepEqual(oldGitHubOrg.BranchProtectionPolicy, newGitHubOrg.BranchProtectionPolicy) {
+		log.WithFields(f).Debug("branchProtectionPolicy updated - processing...")
+		droppedPatterns := branch_protection.DroppedBranchPatterns(
+			github_organizations.ToBranchProtectionPolicy(github_organizations.ToModel(&oldGitHubOrg).BranchProtectionPolicy),
+			github_organizations.ToBranchProtectionPolicy(github_organizations.ToModel(&newGitHubOrg).BranchProtectionPolicy))
+		return s.enableBranchProtectionForGithubOrg(ctx, newGitHubOrg, droppedPatterns)
 	}
 
 	if !oldGitHubOrg.AutoEnabled && newGitHubOrg.AutoEnabled {
@@ -132,7 +143,10 @@ func (s *service) GitHubOrgDeletedEvent(event events.DynamoDBEventRecord) error
 	return nil
 }
 
-func (s *service) enableBranchProtectionForGithubOrg(ctx context.Context, newGitHubOrg github_organizations.GithubOrganization) error {
+// enableBranchProtectionForGithubOrg applies the branch protection policy of the organization on its repositories, the
+// dropped patterns are no longer protected by the policy - the patterns whose rule the policy created are recorded on
+// every repository
+func (s *service) enableBranchProtectionForGithubOrg(ctx context.Context, newGitHubOrg github_organizations.GithubOrganization, droppedPatterns []string) error {
 	f := logrus.Fields{
 		"functionName":               "dynamo_events.github_organization.enableBranchProtectionForGithubOrg",
 		utils.XREQUESTID:             ctx.Value(utils.XREQUESTID),
@@ -153,6 +167,10 @@ func (s *service) enableBranchProtectionForGithubOrg(ctx context.Context, newGit
 		return err
 	}
 
+	policy := github_organizations.ToBranchProtectionPolicy(github_organizations.ToModel(&newGitHubOrg).BranchProtectionPolicy)
+	f["branchPatterns"] = policy.BranchPatterns
+	f["droppedPatterns"] = droppedPatterns
+
 	log.WithFields(f).Debugf("creating a new GitHub client object for org: %s...", newGitHubOrg.OrganizationName)
 	branchProtectionRepo, err := branch_protection.NewBranchProtectionRepository(newGitHubOrg.OrganizationInstallationID, branch_protection.EnableBlockingLimiter())
 	if err != nil {
@@ -175,10 +193,19 @@ func (s *service) enableBranchProtectionForGithubOrg(ctx context.Context, newGit
 			}()
 			log.WithFields(f).Debugf("enabling branch protection for repository: %s", repo.RepositoryName)
 
-			log.WithFields(f).Debugf("enabling branch protection on the default branch %s for the GitHub repository: %s...",
-				utils.GithubBranchProtectionPatternAll, repo.RepositoryName)
-			return branchProtectionRepo.EnableBranchProtection(ctx, newGitHubOrg.OrganizationName, repo.RepositoryName,
-				utils.GithubBranchProtectionPatternAll, true, []string{utils.GitHubBotName}, []string{})
+			createdPatterns, err := s.repositoryService.GetBranchProtectionPatterns(ctx, repo.RepositoryID)
+			if err != nil {
+				log.WithFields(f).WithError(err).Warnf("unable to load the branch patterns the policy protected on the repository: %s", repo.RepositoryName)
+				return err
+			}
+
+			log.WithFields(f).Debugf("applying the branch protection policy %v for the GitHub repository: %s...",
+				policy.BranchPatterns, repo.RepositoryName)
+			createdPatterns, err = branchProtectionRepo.ApplyBranchProtectionPolicy(ctx, newGitHubOrg.OrganizationName, repo.RepositoryName, policy, droppedPatterns, createdPatterns)
+			if err != nil {
+				return err
+			}
+			return s.repositoryService.UpdateBranchProtectionPatterns(ctx, repo.RepositoryID, createdPatterns)
 		})
 	}
 
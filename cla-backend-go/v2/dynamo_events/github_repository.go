@@ -7,6 +7,7 @@ import (
 	"context"
 
 	"github.com/communitybridge/easycla/cla-backend-go/github/branch_protection"
+	"github.com/communitybridge/easycla/cla-backend-go/github_organizations"
 
 	"github.com/communitybridge/easycla/cla-backend-go/repositories"
 	"github.com/sirupsen/logrus"
@@ -117,11 +118,15 @@ func (s *service) EnableBranchProtectionServiceHandler(event events.DynamoDBEven
 				return err
 			}
 
-			log.WithFields(f).Debugf("enabling branch protection on th default branch %s for the GitHub repository: %s...",
-				utils.GithubBranchProtectionPatternAll, newRepoModel.RepositoryName)
-			return branchProtectionRepository.EnableBranchProtection(ctx,
-				parentOrgName, newRepoModel.RepositoryName,
-				utils.GithubBranchProtectionPatternAll, true, []string{utils.GitHubBotName}, []string{})
+			policy := github_organizations.ToBranchProtectionPolicy(gitHubOrg.BranchProtectionPolicy)
+			log.WithFields(f).Debugf("applying the branch protection policy %v for the GitHub repository: %s...",
+				policy.BranchPatterns, newRepoModel.RepositoryName)
+			createdPatterns, err := branchProtectionRepository.ApplyBranchProtectionPolicy(ctx,
+				parentOrgName, newRepoModel.RepositoryName, policy, nil, newRepoModel.BranchProtectionPatterns)
+			if err != nil {
+				return err
+			}
+			return s.repositoryService.UpdateBranchProtectionPatterns(ctx, newRepoModel.RepositoryID, createdPatterns)
 		}
 
 		log.WithFields(f).Debug("github organization branch protection is not enabled - no action required")
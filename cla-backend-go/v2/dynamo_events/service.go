@@ -11,6 +11,8 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/communitybridge/easycla/cla-backend-go/event_search"
+	"github.com/communitybridge/easycla/cla-backend-go/event_webhooks"
 	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
 
 	"github.com/communitybridge/easycla/cla-backend-go/repositories"
@@ -64,6 +66,8 @@ type service struct {
 	autoEnableService        *autoEnableServiceProvider
 	claManagerRequestsRepo   cla_manager.IRepository
 	approvalListRequestsRepo approval_list.IRepository
+	eventWebhooksService     event_webhooks.Service
+	eventSearchService       event_search.Service
 }
 
 // Service implements DynamoDB stream event handler service
@@ -84,7 +88,9 @@ func NewService(stage string,
 	repositoryService repositories.Service,
 	gerritService gerrits.Service,
 	claManagerRequestsRepo cla_manager.IRepository,
-	approvalListRequestsRepo approval_list.IRepository) Service {
+	approvalListRequestsRepo approval_list.IRepository,
+	eventWebhooksService event_webhooks.Service,
+	eventSearchService event_search.Service) Service {
 
 	signaturesTable := fmt.Sprintf("cla-%s-signatures", stage)
 	eventsTable := fmt.Sprintf("cla-%s-events", stage)
@@ -109,6 +115,8 @@ func NewService(stage string,
 		autoEnableService:        &autoEnableServiceProvider{repositoryService: repositoryService},
 		claManagerRequestsRepo:   claManagerRequestsRepo,
 		approvalListRequestsRepo: approvalListRequestsRepo,
+		eventWebhooksService:     eventWebhooksService,
+		eventSearchService:       eventSearchService,
 	}
 
 	s.registerCallback(signaturesTable, Modify, s.SignatureSignedEvent)
@@ -120,6 +128,12 @@ func NewService(stage string,
 	s.registerCallback(signaturesTable, Modify, s.UpdateCLAPermissions)
 
 	s.registerCallback(eventsTable, Insert, s.EventAddedEvent)
+	// Deliver the new events to the event webhook subscriptions
+	s.registerCallback(eventsTable, Insert, s.EventWebhooksHandler)
+	// Keep the event search index up to date
+	s.registerCallback(eventsTable, Insert, s.EventSearchIndexHandler)
+	s.registerCallback(eventsTable, Modify, s.EventSearchIndexHandler)
+	s.registerCallback(eventsTable, Remove, s.EventSearchRemoveHandler)
 
 	// Enable or Disable the CLA Service Enabled/Disabled flag/attribute in the platform Project Service
 	// These are called by the API via the service layer - includes the user who did it
@@ -0,0 +1,350 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package event_webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/sirupsen/logrus"
+
+	webhooks "github.com/communitybridge/easycla/cla-backend-go/event_webhooks"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations/event_webhooks"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// Configure setups handlers on api with service
+func Configure(api *operations.EasyclaAPI, service webhooks.Service, claGroupService project.Service) {
+	// isAuthorized checks the user has access to the CLA group or the company owning the subscriptions
+	isAuthorized := func(ctx context.Context, authUser *auth.User, ownerType, ownerID string) (bool, error) {
+		if ownerType == webhooks.OwnerTypeCompany {
+			return utils.IsUserAuthorizedForOrganization(ctx, authUser, ownerID, utils.ALLOW_ADMIN_SCOPE), nil
+		}
+		claGroupModel, err := claGroupService.GetCLAGroupByID(ctx, ownerID)
+		if err != nil {
+			return false, err
+		}
+		if claGroupModel == nil {
+			return false, fmt.Errorf("cla group %s does not exist", ownerID)
+		}
+		return utils.IsUserAuthorizedForProjectTree(ctx, authUser, claGroupModel.ProjectExternalID, utils.ALLOW_ADMIN_SCOPE), nil
+	}
+
+	api.EventWebhooksListClaGroupEventWebhooksHandler = event_webhooks.ListClaGroupEventWebhooksHandlerFunc(
+		func(params event_webhooks.ListClaGroupEventWebhooksParams, authUser *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+			f := logrus.Fields{
+				"functionName":   "v2.event_webhooks.handlers.EventWebhooksListClaGroupEventWebhooksHandler",
+				utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+				"authUser":       authUser.UserName,
+				"claGroupID":     params.ClaGroupID,
+			}
+
+			authorized, err := isAuthorized(ctx, authUser, webhooks.OwnerTypeCLAGroup, params.ClaGroupID)
+			if err != nil {
+				msg := fmt.Sprintf("unable to load the CLA group: %s", params.ClaGroupID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				return event_webhooks.NewListClaGroupEventWebhooksNotFound().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseNotFoundWithError(reqID, msg, err))
+			}
+			if !authorized {
+				msg := fmt.Sprintf("user %s does not have access to List Event Webhooks of the CLA Group %s", authUser.UserName, params.ClaGroupID)
+				log.WithFields(f).Debug(msg)
+				return event_webhooks.NewListClaGroupEventWebhooksForbidden().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseForbidden(reqID, msg))
+			}
+
+			subscriptions, err := service.ListSubscriptions(ctx, webhooks.OwnerTypeCLAGroup, params.ClaGroupID)
+			if err != nil {
+				msg := fmt.Sprintf("unable to list the event webhooks of the CLA group: %s", params.ClaGroupID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				return event_webhooks.NewListClaGroupEventWebhooksInternalServerError().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+			}
+			return event_webhooks.NewListClaGroupEventWebhooksOK().WithXRequestID(reqID).WithPayload(toSubscriptionList(subscriptions))
+		})
+
+	api.EventWebhooksCreateClaGroupEventWebhookHandler = event_webhooks.CreateClaGroupEventWebhookHandlerFunc(
+		func(params event_webhooks.CreateClaGroupEventWebhookParams, authUser *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+			f := logrus.Fields{
+				"functionName":   "v2.event_webhooks.handlers.EventWebhooksCreateClaGroupEventWebhookHandler",
+				utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+				"authUser":       authUser.UserName,
+				"claGroupID":     params.ClaGroupID,
+			}
+
+			authorized, err := isAuthorized(ctx, authUser, webhooks.OwnerTypeCLAGroup, params.ClaGroupID)
+			if err != nil {
+				msg := fmt.Sprintf("unable to load the CLA group: %s", params.ClaGroupID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				return event_webhooks.NewCreateClaGroupEventWebhookNotFound().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseNotFoundWithError(reqID, msg, err))
+			}
+			if !authorized {
+				msg := fmt.Sprintf("user %s does not have access to Create Event Webhooks of the CLA Group %s", authUser.UserName, params.ClaGroupID)
+				log.WithFields(f).Debug(msg)
+				return event_webhooks.NewCreateClaGroupEventWebhookForbidden().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseForbidden(reqID, msg))
+			}
+
+			subscription, err := service.CreateSubscription(ctx, toSubscriptionInput(webhooks.OwnerTypeCLAGroup, params.ClaGroupID, params.Body, authUser))
+			if err != nil {
+				msg := fmt.Sprintf("unable to create the event webhook of the CLA group: %s", params.ClaGroupID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				return event_webhooks.NewCreateClaGroupEventWebhookBadRequest().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseBadRequestWithError(reqID, msg, err))
+			}
+			return event_webhooks.NewCreateClaGroupEventWebhookOK().WithXRequestID(reqID).WithPayload(toSubscription(subscription, true))
+		})
+
+	api.EventWebhooksListCompanyEventWebhooksHandler = event_webhooks.ListCompanyEventWebhooksHandlerFunc(
+		func(params event_webhooks.ListCompanyEventWebhooksParams, authUser *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+			f := logrus.Fields{
+				"functionName":   "v2.event_webhooks.handlers.EventWebhooksListCompanyEventWebhooksHandler",
+				utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+				"authUser":       authUser.UserName,
+				"companySFID":    params.CompanySFID,
+			}
+
+			if authorized, _ := isAuthorized(ctx, authUser, webhooks.OwnerTypeCompany, params.CompanySFID); !authorized {
+				msg := fmt.Sprintf("user %s does not have access to List Event Webhooks of the company %s", authUser.UserName, params.CompanySFID)
+				log.WithFields(f).Debug(msg)
+				return event_webhooks.NewListCompanyEventWebhooksForbidden().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseForbidden(reqID, msg))
+			}
+
+			subscriptions, err := service.ListSubscriptions(ctx, webhooks.OwnerTypeCompany, params.CompanySFID)
+			if err != nil {
+				msg := fmt.Sprintf("unable to list the event webhooks of the company: %s", params.CompanySFID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				return event_webhooks.NewListCompanyEventWebhooksInternalServerError().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+			}
+			return event_webhooks.NewListCompanyEventWebhooksOK().WithXRequestID(reqID).WithPayload(toSubscriptionList(subscriptions))
+		})
+
+	api.EventWebhooksCreateCompanyEventWebhookHandler = event_webhooks.CreateCompanyEventWebhookHandlerFunc(
+		func(params event_webhooks.CreateCompanyEventWebhookParams, authUser *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+			f := logrus.Fields{
+				"functionName":   "v2.event_webhooks.handlers.EventWebhooksCreateCompanyEventWebhookHandler",
+				utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+				"authUser":       authUser.UserName,
+				"companySFID":    params.CompanySFID,
+			}
+
+			if authorized, _ := isAuthorized(ctx, authUser, webhooks.OwnerTypeCompany, params.CompanySFID); !authorized {
+				msg := fmt.Sprintf("user %s does not have access to Create Event Webhooks of the company %s", authUser.UserName, params.CompanySFID)
+				log.WithFields(f).Debug(msg)
+				return event_webhooks.NewCreateCompanyEventWebhookForbidden().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseForbidden(reqID, msg))
+			}
+
+			subscription, err := service.CreateSubscription(ctx, toSubscriptionInput(webhooks.OwnerTypeCompany, params.CompanySFID, params.Body, authUser))
+			if err != nil {
+				msg := fmt.Sprintf("unable to create the event webhook of the company: %s", params.CompanySFID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				return event_webhooks.NewCreateCompanyEventWebhookBadRequest().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseBadRequestWithError(reqID, msg, err))
+			}
+			return event_webhooks.NewCreateCompanyEventWebhookOK().WithXRequestID(reqID).WithPayload(toSubscription(subscription, true))
+		})
+
+	api.EventWebhooksDeleteEventWebhookHandler = event_webhooks.DeleteEventWebhookHandlerFunc(
+		func(params event_webhooks.DeleteEventWebhookParams, authUser *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+			f := logrus.Fields{
+				"functionName":   "v2.event_webhooks.handlers.EventWebhooksDeleteEventWebhookHandler",
+				utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+				"authUser":       authUser.UserName,
+				"subscriptionID": params.SubscriptionID,
+			}
+
+			subscription, err := service.GetSubscription(ctx, params.SubscriptionID)
+			if err != nil {
+				msg := fmt.Sprintf("unable to load the event webhook: %s", params.SubscriptionID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				return event_webhooks.NewDeleteEventWebhookNotFound().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseNotFoundWithError(reqID, msg, err))
+			}
+			if authorized, _ := isAuthorized(ctx, authUser, subscription.OwnerType, subscription.OwnerID); !authorized {
+				msg := fmt.Sprintf("user %s does not have access to Delete the Event Webhook %s", authUser.UserName, params.SubscriptionID)
+				log.WithFields(f).Debug(msg)
+				return event_webhooks.NewDeleteEventWebhookForbidden().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseForbidden(reqID, msg))
+			}
+
+			if err := service.DeleteSubscription(ctx, params.SubscriptionID); err != nil {
+				msg := fmt.Sprintf("unable to delete the event webhook: %s", params.SubscriptionID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				return event_webhooks.NewDeleteEventWebhookInternalServerError().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+			}
+			return event_webhooks.NewDeleteEventWebhookNoContent().WithXRequestID(reqID)
+		})
+
+	api.EventWebhooksListEventWebhookDeadLettersHandler = event_webhooks.ListEventWebhookDeadLettersHandlerFunc(
+		func(params event_webhooks.ListEventWebhookDeadLettersParams, authUser *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+			f := logrus.Fields{
+				"functionName":   "v2.event_webhooks.handlers.EventWebhooksListEventWebhookDeadLettersHandler",
+				utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+				"authUser":       authUser.UserName,
+				"subscriptionID": params.SubscriptionID,
+			}
+
+			subscription, err := service.GetSubscription(ctx, params.SubscriptionID)
+			if err != nil {
+				msg := fmt.Sprintf("unable to load the event webhook: %s", params.SubscriptionID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				return event_webhooks.NewListEventWebhookDeadLettersNotFound().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseNotFoundWithError(reqID, msg, err))
+			}
+			if authorized, _ := isAuthorized(ctx, authUser, subscription.OwnerType, subscription.OwnerID); !authorized {
+				msg := fmt.Sprintf("user %s does not have access to List the Dead Letters of the Event Webhook %s", authUser.UserName, params.SubscriptionID)
+				log.WithFields(f).Debug(msg)
+				return event_webhooks.NewListEventWebhookDeadLettersForbidden().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseForbidden(reqID, msg))
+			}
+
+			deliveries, err := service.ListDeadLetters(ctx, params.SubscriptionID)
+			if err != nil {
+				msg := fmt.Sprintf("unable to list the dead letters of the event webhook: %s", params.SubscriptionID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				return event_webhooks.NewListEventWebhookDeadLettersInternalServerError().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+			}
+			return event_webhooks.NewListEventWebhookDeadLettersOK().WithXRequestID(reqID).WithPayload(toDeliveryList(deliveries))
+		})
+
+	api.EventWebhooksRedeliverEventWebhookDeliveryHandler = event_webhooks.RedeliverEventWebhookDeliveryHandlerFunc(
+		func(params event_webhooks.RedeliverEventWebhookDeliveryParams, authUser *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+			f := logrus.Fields{
+				"functionName":   "v2.event_webhooks.handlers.EventWebhooksRedeliverEventWebhookDeliveryHandler",
+				utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+				"authUser":       authUser.UserName,
+				"subscriptionID": params.SubscriptionID,
+				"deliveryID":     params.DeliveryID,
+			}
+
+			subscription, err := service.GetSubscription(ctx, params.SubscriptionID)
+			if err != nil {
+				msg := fmt.Sprintf("unable to load the event webhook: %s", params.SubscriptionID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				return event_webhooks.NewRedeliverEventWebhookDeliveryNotFound().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseNotFoundWithError(reqID, msg, err))
+			}
+			if authorized, _ := isAuthorized(ctx, authUser, subscription.OwnerType, subscription.OwnerID); !authorized {
+				msg := fmt.Sprintf("user %s does not have access to Redeliver the Event Webhook %s deliveries", authUser.UserName, params.SubscriptionID)
+				log.WithFields(f).Debug(msg)
+				return event_webhooks.NewRedeliverEventWebhookDeliveryForbidden().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseForbidden(reqID, msg))
+			}
+
+			delivery, err := service.Redeliver(ctx, params.SubscriptionID, params.DeliveryID)
+			if err != nil {
+				msg := fmt.Sprintf("unable to redeliver the event webhook delivery: %s", params.DeliveryID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				switch err {
+				case webhooks.ErrDeliveryDoesNotExist:
+					return event_webhooks.NewRedeliverEventWebhookDeliveryNotFound().WithXRequestID(reqID).WithPayload(
+						utils.ErrorResponseNotFoundWithError(reqID, msg, err))
+				case webhooks.ErrDeliveryNotDeadLettered, webhooks.ErrSubscriptionDisabled:
+					return event_webhooks.NewRedeliverEventWebhookDeliveryConflict().WithXRequestID(reqID).WithPayload(
+						utils.ErrorResponseConflictWithError(reqID, msg, err))
+				}
+				return event_webhooks.NewRedeliverEventWebhookDeliveryInternalServerError().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+			}
+			return event_webhooks.NewRedeliverEventWebhookDeliveryOK().WithXRequestID(reqID).WithPayload(toDelivery(delivery))
+		})
+}
+
+// toSubscriptionInput converts the request body to the service input
+func toSubscriptionInput(ownerType, ownerID string, body *models.EventWebhookSubscriptionInput, authUser *auth.User) *webhooks.SubscriptionInput {
+	return &webhooks.SubscriptionInput{
+		OwnerType:   ownerType,
+		OwnerID:     ownerID,
+		URL:         utils.StringValue(body.URL),
+		Description: body.Description,
+		EventTypes:  body.EventTypes,
+		Secret:      body.Secret,
+		CreatedBy:   authUser.UserName,
+	}
+}
+
+// toSubscription converts the subscription to the response model, the secret is only returned on creation
+func toSubscription(subscription *webhooks.Subscription, withSecret bool) *models.EventWebhookSubscription {
+	out := &models.EventWebhookSubscription{
+		SubscriptionID: subscription.SubscriptionID,
+		OwnerType:      subscription.OwnerType,
+		OwnerID:        subscription.OwnerID,
+		URL:            subscription.URL,
+		EventTypes:     subscription.EventTypes,
+		Description:    subscription.Description,
+		Enabled:        subscription.Enabled,
+		CreatedBy:      subscription.CreatedBy,
+		DateCreated:    subscription.DateCreated,
+		DateModified:   subscription.DateModified,
+	}
+	if withSecret {
+		out.Secret = subscription.Secret
+	}
+	return out
+}
+
+func toSubscriptionList(subscriptions []*webhooks.Subscription) *models.EventWebhookSubscriptionList {
+	list := make([]*models.EventWebhookSubscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		list = append(list, toSubscription(subscription, false))
+	}
+	return &models.EventWebhookSubscriptionList{List: list}
+}
+
+func toDelivery(delivery *webhooks.Delivery) *models.EventWebhookDelivery {
+	return &models.EventWebhookDelivery{
+		DeliveryID:      delivery.DeliveryID,
+		SubscriptionID:  delivery.SubscriptionID,
+		EventID:         delivery.EventID,
+		EventType:       delivery.EventType,
+		Status:          delivery.Status,
+		Attempts:        int64(delivery.Attempts),
+		LastAttemptTime: delivery.LastAttemptTime,
+		LastStatusCode:  int64(delivery.LastStatusCode),
+		LastError:       delivery.LastError,
+		DateCreated:     delivery.DateCreated,
+		DateModified:    delivery.DateModified,
+	}
+}
+
+func toDeliveryList(deliveries []*webhooks.Delivery) *models.EventWebhookDeliveryList {
+	list := make([]*models.EventWebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		list = append(list, toDelivery(delivery))
+	}
+	return &models.EventWebhookDeliveryList{List: list}
+}
@@ -4,6 +4,8 @@
 package events
 
 import (
+	"github.com/communitybridge/easycla/cla-backend-go/event_search"
+	v1Events "github.com/communitybridge/easycla/cla-backend-go/events"
 	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
 	"github.com/jinzhu/copier"
@@ -36,3 +38,59 @@ func errorResponse(reqID string, err error) *models.ErrorResponse {
 
 	return &e
 }
+
+func v2EventChainVerification(in *v1Events.ChainVerification) *models.EventChainVerification {
+	breaks := make([]*models.EventChainBreak, 0, len(in.Breaks))
+	for _, chainBreak := range in.Breaks {
+		breaks = append(breaks, &models.EventChainBreak{
+			Sequence: chainBreak.Sequence,
+			EventID:  chainBreak.EventID,
+			Reason:   chainBreak.Reason,
+		})
+	}
+	return &models.EventChainVerification{
+		Stream:         in.Stream,
+		EventsVerified: in.EventsVerified,
+		HeadSequence:   in.HeadSequence,
+		HeadHash:       in.HeadHash,
+		Valid:          in.Valid,
+		Breaks:         breaks,
+		DateVerified:   in.DateVerified,
+	}
+}
+
+func v2EventSearchResult(in *event_search.Result) (*models.EventSearchResult, error) {
+	hits := make([]*models.EventSearchHit, 0, len(in.Hits))
+	for _, hit := range in.Hits {
+		var event models.Event
+		if err := copier.Copy(&event, hit.Event); err != nil {
+			return nil, err
+		}
+		hits = append(hits, &models.EventSearchHit{
+			Event: &event,
+			Score: hit.Score,
+		})
+	}
+	return &models.EventSearchResult{
+		Hits: hits,
+		Facets: &models.EventSearchFacets{
+			EventTypes: v2EventSearchFacetValues(in.Facets[event_search.FacetEventType]),
+			Companies:  v2EventSearchFacetValues(in.Facets[event_search.FacetCompany]),
+			ClaGroups:  v2EventSearchFacetValues(in.Facets[event_search.FacetCLAGroup]),
+		},
+		Total:      in.Total,
+		NextCursor: in.NextCursor,
+	}, nil
+}
+
+func v2EventSearchFacetValues(in []*event_search.FacetValue) []*models.EventSearchFacetValue {
+	values := make([]*models.EventSearchFacetValue, 0, len(in))
+	for _, value := range in {
+		values = append(values, &models.EventSearchFacetValue{
+			Value: value.Value,
+			Name:  value.Name,
+			Count: value.Count,
+		})
+	}
+	return values
+}
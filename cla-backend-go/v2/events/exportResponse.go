@@ -0,0 +1,142 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package events
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/sirupsen/logrus"
+
+	v1Events "github.com/communitybridge/easycla/cla-backend-go/events"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// export response headers - sent as trailers when the export is streamed
+const (
+	ExportNextCursorHeader = "X-Export-Next-Cursor"
+	ExportCountHeader      = "X-Export-Count"
+	ExportErrorHeader      = "X-Export-Error"
+)
+
+// ExportEventsResponse creates a new response handler for the event exports
+func ExportEventsResponse(ctx context.Context, reqID string, service v1Events.Service, params *v1Events.ExportParams, cursor *v1Events.ExportCursor, limit int64, format string) middleware.Responder {
+	return &ExportEventsResponder{
+		ctx:     ctx,
+		reqID:   reqID,
+		service: service,
+		params:  params,
+		cursor:  cursor,
+		limit:   limit,
+		format:  format,
+	}
+}
+
+// ExportEventsResponder writes the exported events to the response. The events are streamed page by page when the
+// response can be flushed - the cursor and the count are then sent as trailers. Otherwise the export is buffered and
+// they are sent as headers.
+type ExportEventsResponder struct {
+	ctx     context.Context
+	reqID   string
+	service v1Events.Service
+	params  *v1Events.ExportParams
+	cursor  *v1Events.ExportCursor
+	limit   int64
+	format  string
+}
+
+// flushingExportWriter flushes the response after every page of the export
+type flushingExportWriter struct {
+	v1Events.ExportWriter
+	flusher http.Flusher
+}
+
+func (w *flushingExportWriter) Flush() error {
+	if err := w.ExportWriter.Flush(); err != nil {
+		return err
+	}
+	w.flusher.Flush()
+	return nil
+}
+
+// WriteResponse writes to the response
+func (r *ExportEventsResponder) WriteResponse(rw http.ResponseWriter, pr runtime.Producer) {
+	f := logrus.Fields{
+		"functionName":   "v2.events.exportResponse.WriteResponse",
+		utils.XREQUESTID: r.ctx.Value(utils.XREQUESTID),
+		"format":         r.format,
+		"limit":          r.limit,
+	}
+
+	flusher, streamed := rw.(http.Flusher)
+	var out io.Writer = rw
+	var buffer bytes.Buffer
+	if !streamed {
+		out = &buffer
+	}
+
+	// a resumed CSV export appends to the records of the previous one
+	writer, err := v1Events.NewExportWriter(out, r.format, r.cursor == nil)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to create the export writer")
+		WriteResponse(http.StatusBadRequest, runtime.JSONMime, runtime.JSONProducer(), utils.ErrorResponseBadRequestWithError(r.reqID, "invalid export format", err)).WriteResponse(rw, pr)
+		return
+	}
+	if streamed {
+		writer = &flushingExportWriter{ExportWriter: writer, flusher: flusher}
+		rw.Header().Set("Trailer", fmt.Sprintf("%s, %s, %s", ExportNextCursorHeader, ExportCountHeader, ExportErrorHeader))
+		r.writeHeaders(rw)
+		rw.WriteHeader(http.StatusOK)
+	}
+
+	next, count, err := r.service.ExportEvents(r.ctx, r.params, r.cursor, r.limit, writer)
+	nextCursor := ""
+	if next != nil {
+		nextCursor = next.String()
+	}
+	if err != nil {
+		log.WithFields(f).WithError(err).Warnf("export stopped after %d events, resume cursor: %s", count, nextCursor)
+		if !streamed {
+			// nothing was sent, the export is retried from the same cursor
+			WriteResponse(http.StatusInternalServerError, runtime.JSONMime, runtime.JSONProducer(), utils.ErrorResponseInternalServerErrorWithError(r.reqID, "problem exporting the events", err)).WriteResponse(rw, pr)
+			return
+		}
+		rw.Header().Set(ExportErrorHeader, err.Error())
+	}
+	rw.Header().Set(ExportNextCursorHeader, nextCursor)
+	rw.Header().Set(ExportCountHeader, strconv.FormatInt(count, 10))
+	log.WithFields(f).Debugf("exported %d events, next cursor: %s", count, nextCursor)
+
+	if !streamed {
+		r.writeHeaders(rw)
+		rw.WriteHeader(http.StatusOK)
+		if _, err := buffer.WriteTo(rw); err != nil {
+			log.WithFields(f).WithError(err).Warn("unable to write the export")
+		}
+	}
+}
+
+func (r *ExportEventsResponder) writeHeaders(rw http.ResponseWriter) {
+	rw.Header().Set(runtime.HeaderContentType, v1Events.ExportContentType(r.format))
+	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment;filename=%s", r.filename()))
+}
+
+func (r *ExportEventsResponder) filename() string {
+	scope := fmt.Sprintf("cla-group-%s", r.params.CLAGroupID)
+	if r.params.FoundationSFID != "" {
+		scope = fmt.Sprintf("foundation-%s", r.params.FoundationSFID)
+	}
+	extension := r.format
+	if r.format == v1Events.ExportFormatSyslog {
+		extension = "log"
+	}
+	return fmt.Sprintf("events-%s.%s", scope, extension)
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/LF-Engineering/lfx-kit/auth"
 	v1Company "github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/event_search"
 	v1Events "github.com/communitybridge/easycla/cla-backend-go/events"
 	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
@@ -31,7 +32,7 @@ import (
 )
 
 // Configure setups handlers on api with service
-func Configure(api *operations.EasyclaAPI, service v1Events.Service, v1CompanyRepo v1Company.IRepository, projectsClaGroupsRepo projects_cla_groups.Repository) { // nolint
+func Configure(api *operations.EasyclaAPI, service v1Events.Service, searchService event_search.Service, v1CompanyRepo v1Company.IRepository, projectsClaGroupsRepo projects_cla_groups.Repository) { // nolint
 	api.EventsGetRecentEventsHandler = events.GetRecentEventsHandlerFunc(
 		func(params events.GetRecentEventsParams, authUser *auth.User) middleware.Responder {
 			reqID := utils.GetRequestID(params.XREQUESTID)
@@ -346,6 +347,276 @@ func Configure(api *operations.EasyclaAPI, service v1Events.Service, v1CompanyRe
 			}
 			return events.NewGetCompanyProjectEventsOK().WithPayload(resp)
 		})
+
+	api.EventsVerifyClaGroupEventChainHandler = events.VerifyClaGroupEventChainHandlerFunc(
+		func(params events.VerifyClaGroupEventChainParams, authUser *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			ctx := context.WithValue(context.Background(), utils.XREQUESTID, reqID) // nolint
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			f := logrus.Fields{
+				"functionName":   "EventsVerifyClaGroupEventChainHandler",
+				utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+				"authUserName":   authUser.UserName,
+				"authUserEmail":  authUser.Email,
+				"claGroupID":     params.ClaGroupID,
+			}
+
+			projectCLAGroups, err := projectsClaGroupsRepo.GetProjectsIdsForClaGroup(ctx, params.ClaGroupID)
+			if err != nil {
+				msg := fmt.Sprintf("problem loading the projects of the CLA Group: %s", params.ClaGroupID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				return events.NewVerifyClaGroupEventChainInternalServerError().WithPayload(utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+			}
+			if len(projectCLAGroups) == 0 {
+				msg := fmt.Sprintf("no projects associated with the CLA Group: %s", params.ClaGroupID)
+				log.WithFields(f).Warn(msg)
+				return events.NewVerifyClaGroupEventChainNotFound().WithPayload(utils.ErrorResponseNotFound(reqID, msg))
+			}
+
+			log.WithFields(f).Debug("checking permission...")
+			if !utils.IsUserAuthorizedForProjectTree(ctx, authUser, projectCLAGroups[0].FoundationSFID, utils.ALLOW_ADMIN_SCOPE) {
+				msg := fmt.Sprintf("user %s does not have access to Verify the Events of the CLA Group %s.", authUser.UserName, params.ClaGroupID)
+				log.WithFields(f).Warn(msg)
+				return events.NewVerifyClaGroupEventChainForbidden().WithPayload(utils.ErrorResponseForbidden(reqID, msg))
+			}
+
+			result, err := service.VerifyEventChain(ctx, v1Events.ChainStreamTypeCLAGroup, params.ClaGroupID)
+			if err != nil {
+				msg := fmt.Sprintf("problem verifying the events of the CLA Group: %s", params.ClaGroupID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				return events.NewVerifyClaGroupEventChainInternalServerError().WithPayload(utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+			}
+			if !result.Valid {
+				log.WithFields(f).Warnf("the event stream %s has %d breaks", result.Stream, len(result.Breaks))
+			}
+
+			return events.NewVerifyClaGroupEventChainOK().WithPayload(v2EventChainVerification(result))
+		})
+
+	api.EventsVerifyCompanyEventChainHandler = events.VerifyCompanyEventChainHandlerFunc(
+		func(params events.VerifyCompanyEventChainParams, authUser *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			ctx := context.WithValue(context.Background(), utils.XREQUESTID, reqID) // nolint
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			f := logrus.Fields{
+				"functionName":   "EventsVerifyCompanyEventChainHandler",
+				utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+				"authUserName":   authUser.UserName,
+				"authUserEmail":  authUser.Email,
+				"companyID":      params.CompanyID,
+			}
+
+			v1Company, compErr := v1CompanyRepo.GetCompany(ctx, params.CompanyID)
+			if compErr != nil {
+				msg := fmt.Sprintf("unable to fetch company by ID: %s", params.CompanyID)
+				log.WithFields(f).WithError(compErr).Warn(msg)
+				return events.NewVerifyCompanyEventChainNotFound().WithPayload(utils.ErrorResponseNotFoundWithError(reqID, msg, compErr))
+			}
+
+			log.WithFields(f).Debug("checking permission...")
+			if !utils.IsUserAuthorizedForOrganization(ctx, authUser, v1Company.CompanyExternalID, utils.ALLOW_ADMIN_SCOPE) {
+				msg := fmt.Sprintf("user %s does not have access to Verify the Events of the company with Organization scope of %s.", authUser.UserName, v1Company.CompanyExternalID)
+				log.WithFields(f).Warn(msg)
+				return events.NewVerifyCompanyEventChainForbidden().WithPayload(utils.ErrorResponseForbidden(reqID, msg))
+			}
+
+			result, err := service.VerifyEventChain(ctx, v1Events.ChainStreamTypeCompany, params.CompanyID)
+			if err != nil {
+				msg := fmt.Sprintf("problem verifying the events of the company: %s", params.CompanyID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				return events.NewVerifyCompanyEventChainInternalServerError().WithPayload(utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+			}
+			if !result.Valid {
+				log.WithFields(f).Warnf("the event stream %s has %d breaks", result.Stream, len(result.Breaks))
+			}
+
+			return events.NewVerifyCompanyEventChainOK().WithPayload(v2EventChainVerification(result))
+		})
+
+	api.EventsSearchEventsHandler = events.SearchEventsHandlerFunc(
+		func(params events.SearchEventsParams, authUser *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			ctx := context.WithValue(context.Background(), utils.XREQUESTID, reqID) // nolint
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			f := logrus.Fields{
+				"functionName":   "EventsSearchEventsHandler",
+				utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+				"authUserName":   authUser.UserName,
+				"authUserEmail":  authUser.Email,
+				"searchTerm":     aws.StringValue(params.SearchTerm),
+				"claGroupID":     aws.StringValue(params.ClaGroupID),
+				"companyID":      aws.StringValue(params.CompanyID),
+			}
+
+			if searchService == nil {
+				msg := "the event search index is not available with the memory storage backend"
+				log.WithFields(f).Warn(msg)
+				return events.NewSearchEventsBadRequest().WithPayload(utils.ErrorResponseBadRequest(reqID, msg))
+			}
+			ready, readyErr := searchService.Ready(ctx)
+			if readyErr != nil {
+				msg := "problem loading the state of the event search index"
+				log.WithFields(f).WithError(readyErr).Warn(msg)
+				return events.NewSearchEventsInternalServerError().WithPayload(utils.ErrorResponseInternalServerErrorWithError(reqID, msg, readyErr))
+			}
+			if !ready {
+				msg := "the event search index is not available until the events are indexed"
+				log.WithFields(f).Warn(msg)
+				return events.NewSearchEventsBadRequest().WithPayload(utils.ErrorResponseBadRequest(reqID, msg))
+			}
+
+			// Admins search all the events, the other users search the events of a CLA Group or a company
+			log.WithFields(f).Debug("checking permission...")
+			if !utils.IsUserAdmin(authUser) {
+				switch {
+				case params.ClaGroupID != nil:
+					projectCLAGroups, err := projectsClaGroupsRepo.GetProjectsIdsForClaGroup(ctx, *params.ClaGroupID)
+					if err != nil {
+						msg := fmt.Sprintf("problem loading the projects of the CLA Group: %s", *params.ClaGroupID)
+						log.WithFields(f).WithError(err).Warn(msg)
+						return events.NewSearchEventsInternalServerError().WithPayload(utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+					}
+					if len(projectCLAGroups) == 0 {
+						msg := fmt.Sprintf("no projects associated with the CLA Group: %s", *params.ClaGroupID)
+						log.WithFields(f).Warn(msg)
+						return events.NewSearchEventsNotFound().WithPayload(utils.ErrorResponseNotFound(reqID, msg))
+					}
+					if !utils.IsUserAuthorizedForProjectTree(ctx, authUser, projectCLAGroups[0].FoundationSFID, utils.ALLOW_ADMIN_SCOPE) {
+						msg := fmt.Sprintf("user %s does not have access to Search the Events of the CLA Group %s.", authUser.UserName, *params.ClaGroupID)
+						log.WithFields(f).Warn(msg)
+						return events.NewSearchEventsForbidden().WithPayload(utils.ErrorResponseForbidden(reqID, msg))
+					}
+				case params.CompanyID != nil:
+					v1Company, compErr := v1CompanyRepo.GetCompany(ctx, *params.CompanyID)
+					if compErr != nil {
+						msg := fmt.Sprintf("unable to fetch company by ID: %s", *params.CompanyID)
+						log.WithFields(f).WithError(compErr).Warn(msg)
+						return events.NewSearchEventsNotFound().WithPayload(utils.ErrorResponseNotFoundWithError(reqID, msg, compErr))
+					}
+					if !utils.IsUserAuthorizedForOrganization(ctx, authUser, v1Company.CompanyExternalID, utils.ALLOW_ADMIN_SCOPE) {
+						msg := fmt.Sprintf("user %s does not have access to Search the Events of the company with Organization scope of %s.", authUser.UserName, v1Company.CompanyExternalID)
+						log.WithFields(f).Warn(msg)
+						return events.NewSearchEventsForbidden().WithPayload(utils.ErrorResponseForbidden(reqID, msg))
+					}
+				default:
+					msg := fmt.Sprintf("user %s does not have access to Search all the Events - only Admins are allowed to search without a CLA Group or a company filter.", authUser.UserName)
+					log.WithFields(f).Warn(msg)
+					return events.NewSearchEventsForbidden().WithPayload(utils.ErrorResponseForbidden(reqID, msg))
+				}
+			}
+
+			result, err := searchService.Search(ctx, &event_search.Query{
+				Text:       aws.StringValue(params.SearchTerm),
+				EventType:  aws.StringValue(params.EventType),
+				CompanyID:  aws.StringValue(params.CompanyID),
+				CLAGroupID: aws.StringValue(params.ClaGroupID),
+				After:      params.After,
+				Before:     params.Before,
+				Sort:       aws.StringValue(params.Sort),
+				PageSize:   aws.Int64Value(params.PageSize),
+				Cursor:     aws.StringValue(params.Cursor),
+			})
+			if err != nil {
+				if err == event_search.ErrEmptyQuery || err == event_search.ErrInvalidCursor || err == event_search.ErrInvalidSort || err == event_search.ErrQueryTooBroad {
+					log.WithFields(f).WithError(err).Warn("invalid event search")
+					return events.NewSearchEventsBadRequest().WithPayload(utils.ErrorResponseBadRequestWithError(reqID, "invalid event search", err))
+				}
+				msg := "problem searching the events"
+				log.WithFields(f).WithError(err).Warn(msg)
+				return events.NewSearchEventsInternalServerError().WithPayload(utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+			}
+
+			resp, err := v2EventSearchResult(result)
+			if err != nil {
+				msg := "problem converting the search result to a v2 object"
+				log.WithFields(f).WithError(err).Warn(msg)
+				return events.NewSearchEventsInternalServerError().WithPayload(utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+			}
+			return events.NewSearchEventsOK().WithPayload(resp)
+		})
+
+	api.EventsExportEventsHandler = events.ExportEventsHandlerFunc(
+		func(params events.ExportEventsParams, authUser *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			ctx := context.WithValue(context.Background(), utils.XREQUESTID, reqID) // nolint
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			f := logrus.Fields{
+				"functionName":   "EventsExportEventsHandler",
+				utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+				"authUserName":   authUser.UserName,
+				"authUserEmail":  authUser.Email,
+				"claGroupID":     aws.StringValue(params.ClaGroupID),
+				"foundationSFID": aws.StringValue(params.FoundationSFID),
+				"companyID":      aws.StringValue(params.CompanyID),
+				"format":         aws.StringValue(params.Format),
+				"cursor":         aws.StringValue(params.Cursor),
+			}
+
+			exportParams := &v1Events.ExportParams{
+				CLAGroupID:     aws.StringValue(params.ClaGroupID),
+				FoundationSFID: aws.StringValue(params.FoundationSFID),
+				CompanyID:      aws.StringValue(params.CompanyID),
+				EventType:      aws.StringValue(params.EventType),
+				After:          aws.Int64Value(params.After),
+				Before:         aws.Int64Value(params.Before),
+			}
+			if err := exportParams.Validate(); err != nil {
+				log.WithFields(f).WithError(err).Warn("invalid event export")
+				return events.NewExportEventsBadRequest().WithPayload(utils.ErrorResponseBadRequestWithError(reqID, "invalid event export", err))
+			}
+			var cursor *v1Events.ExportCursor
+			if params.Cursor != nil && *params.Cursor != "" {
+				var err error
+				cursor, err = v1Events.ParseExportCursor(*params.Cursor)
+				if err != nil {
+					log.WithFields(f).WithError(err).Warn("invalid event export cursor")
+					return events.NewExportEventsBadRequest().WithPayload(utils.ErrorResponseBadRequestWithError(reqID, "invalid event export cursor", err))
+				}
+			}
+
+			// The company events are exported with the company SFID, the company managers may export them
+			var companySFID string
+			if params.CompanyID != nil && *params.CompanyID != "" {
+				v1Company, compErr := v1CompanyRepo.GetCompany(ctx, *params.CompanyID)
+				if compErr != nil {
+					msg := fmt.Sprintf("unable to fetch company by ID: %s", *params.CompanyID)
+					log.WithFields(f).WithError(compErr).Warn(msg)
+					return events.NewExportEventsNotFound().WithPayload(utils.ErrorResponseNotFoundWithError(reqID, msg, compErr))
+				}
+				companySFID = v1Company.CompanyExternalID
+				exportParams.CompanySFID = companySFID
+			}
+
+			log.WithFields(f).Debug("checking permission...")
+			foundationSFID := exportParams.FoundationSFID
+			if exportParams.CLAGroupID != "" {
+				projectCLAGroups, err := projectsClaGroupsRepo.GetProjectsIdsForClaGroup(ctx, exportParams.CLAGroupID)
+				if err != nil {
+					msg := fmt.Sprintf("problem loading the projects of the CLA Group: %s", exportParams.CLAGroupID)
+					log.WithFields(f).WithError(err).Warn(msg)
+					return events.NewExportEventsInternalServerError().WithPayload(utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+				}
+				if len(projectCLAGroups) == 0 {
+					msg := fmt.Sprintf("no projects associated with the CLA Group: %s", exportParams.CLAGroupID)
+					log.WithFields(f).Warn(msg)
+					return events.NewExportEventsNotFound().WithPayload(utils.ErrorResponseNotFound(reqID, msg))
+				}
+				foundationSFID = projectCLAGroups[0].FoundationSFID
+			}
+			if !utils.IsUserAdmin(authUser) && !utils.IsUserAuthorizedForProjectTree(ctx, authUser, foundationSFID, utils.ALLOW_ADMIN_SCOPE) &&
+				(companySFID == "" || !utils.IsUserAuthorizedForOrganization(ctx, authUser, companySFID, utils.ALLOW_ADMIN_SCOPE)) {
+				msg := fmt.Sprintf("user %s does not have access to Export the Events of the foundation %s.", authUser.UserName, foundationSFID)
+				log.WithFields(f).Warn(msg)
+				return events.NewExportEventsForbidden().WithPayload(utils.ErrorResponseForbidden(reqID, msg))
+			}
+
+			format := aws.StringValue(params.Format)
+			if format == "" {
+				format = v1Events.ExportFormatCSV
+			}
+			log.WithFields(f).Debug("exporting events...")
+			return ExportEventsResponse(ctx, reqID, service, exportParams, cursor, aws.Int64Value(params.Limit), format)
+		})
 }
 
 // WriteResponse function writes http response.
@@ -32,8 +32,13 @@ type ProjectService interface { //nolint
 	GetCLAGroupByID(ctx context.Context, claGroupID string) (*v1Models.ClaGroup, error)
 }
 
+// GroupReconciler reconciles the CLA type groups of the CLA Group gerrit instances with the signatures
+type GroupReconciler interface {
+	ReconcileCLAGroup(ctx context.Context, authUser *auth.User, claGroupID, claType string, dryRun bool) ([]*v1Gerrits.GroupReconcileResult, error)
+}
+
 // Configure the Gerrit api
-func Configure(api *operations.EasyclaAPI, v1Service v1Gerrits.Service, projectService ProjectService, eventService events.Service, projectsClaGroupsRepo projects_cla_groups.Repository) { // nolint
+func Configure(api *operations.EasyclaAPI, v1Service v1Gerrits.Service, projectService ProjectService, eventService events.Service, projectsClaGroupsRepo projects_cla_groups.Repository, groupReconciler GroupReconciler) { // nolint
 	api.GerritsDeleteGerritHandler = gerrits.DeleteGerritHandlerFunc(
 		func(params gerrits.DeleteGerritParams, authUser *auth.User) middleware.Responder {
 			reqID := utils.GetRequestID(params.XREQUESTID)
@@ -129,11 +134,13 @@ func Configure(api *operations.EasyclaAPI, v1Service v1Gerrits.Service, projectS
 
 			// add the gerrit
 			addGerritInput := &v1Models.AddGerritInput{
-				GerritName:  params.AddGerritInput.GerritName,
-				GerritURL:   params.AddGerritInput.GerritURL,
-				GroupIDCcla: params.AddGerritInput.GroupIDCcla,
-				GroupIDIcla: params.AddGerritInput.GroupIDIcla,
-				Version:     "v2",
+				GerritName:          params.AddGerritInput.GerritName,
+				GerritURL:           params.AddGerritInput.GerritURL,
+				GroupIDCcla:         params.AddGerritInput.GroupIDCcla,
+				GroupIDIcla:         params.AddGerritInput.GroupIDIcla,
+				GroupSyncBackend:    params.AddGerritInput.GroupSyncBackend,
+				GroupReconcileApply: params.AddGerritInput.GroupReconcileApply,
+				Version:             "v2",
 			}
 			result, err := v1Service.AddGerrit(ctx, params.ClaGroupID, params.ProjectSFID, addGerritInput, projectModel)
 			if err != nil {
@@ -451,6 +458,68 @@ func Configure(api *operations.EasyclaAPI, v1Service v1Gerrits.Service, projectS
 		return gerrits.NewRemoveGerritECLAUserOK().WithXRequestID(reqID)
 	})
 
+	api.GerritsReconcileGerritECLAUsersHandler = gerrits.ReconcileGerritECLAUsersHandlerFunc(func(params gerrits.ReconcileGerritECLAUsersParams, authUser *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(context.Background(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+		// the changes are only reported unless the dry-run mode is explicitly disabled
+		dryRun := params.DryRun == nil || *params.DryRun
+		f := logrus.Fields{
+			"functionName":   "v2.gerrits.handlers.GerritsReconcileGerritECLAUsersHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"authUserName":   authUser.UserName,
+			"authUserEmail":  authUser.Email,
+			"claGroupID":     params.ClaGroupID,
+			"projectSFID":    params.ProjectSFID,
+			"dryRun":         dryRun,
+		}
+
+		// verify user have access to the project
+		if !utils.IsUserAuthorizedForProjectTree(ctx, authUser, params.ProjectSFID, utils.ALLOW_ADMIN_SCOPE) {
+			msg := fmt.Sprintf("user %s does not have access to reconcile gerrit users with Project scope of %s", authUser.UserName, params.ProjectSFID)
+			log.WithFields(f).Warn(msg)
+			return gerrits.NewReconcileGerritECLAUsersForbidden().WithXRequestID(reqID).WithPayload(utils.ErrorResponseForbidden(reqID, msg))
+		}
+
+		ok, err := projectsClaGroupsRepo.IsAssociated(ctx, params.ProjectSFID, params.ClaGroupID)
+		if err != nil {
+			msg := fmt.Sprintf("unable to determine project CLA group association for project: %s and CLA Group: %s", params.ProjectSFID, params.ClaGroupID)
+			log.WithFields(f).WithError(err).Warn(msg)
+			return gerrits.NewReconcileGerritECLAUsersBadRequest().WithXRequestID(reqID).WithPayload(utils.ErrorResponseBadRequestWithError(reqID, msg, err))
+		}
+		if !ok {
+			msg := fmt.Sprintf("provided CLA Group %s and project %s are not associated with each other", params.ClaGroupID, params.ProjectSFID)
+			log.WithFields(f).Warn(msg)
+			return gerrits.NewReconcileGerritECLAUsersBadRequest().WithXRequestID(reqID).WithPayload(utils.ErrorResponseBadRequest(reqID, msg))
+		}
+
+		log.WithFields(f).Debug("reconciling the gerrit CCLA groups with the approved corporate contributors...")
+		results, err := groupReconciler.ReconcileCLAGroup(ctx, authUser, params.ClaGroupID, utils.ClaTypeECLA, dryRun)
+		if err != nil {
+			msg := fmt.Sprintf("problem reconciling the gerrit users of CLA Group %s", params.ClaGroupID)
+			log.WithFields(f).WithError(err).Warn(msg)
+			return gerrits.NewReconcileGerritECLAUsersInternalServerError().WithXRequestID(reqID).WithPayload(utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+		}
+
+		response := &models.GerritGroupReconcileResultList{
+			List: make([]*models.GerritGroupReconcileResult, 0, len(results)),
+		}
+		for _, result := range results {
+			response.List = append(response.List, &models.GerritGroupReconcileResult{
+				GerritID:   result.GerritID,
+				GerritName: result.GerritName,
+				GroupName:  result.GroupName,
+				DryRun:     result.DryRun,
+				Added:      result.Added,
+				Removed:    result.Removed,
+				Failed:     result.Failed,
+				Reasons:    result.Reasons,
+			})
+		}
+
+		return gerrits.NewReconcileGerritECLAUsersOK().WithXRequestID(reqID).WithPayload(response)
+	})
+
 }
 
 type codedResponse interface {
@@ -0,0 +1,443 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package github_activity
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-github/v33/github"
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	claGithub "github.com/communitybridge/easycla/cla-backend-go/github"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// the check run values we send to github
+const (
+	// CheckRunName is the name of the check run, the name of the status check required by the branch protection
+	CheckRunName = utils.GitHubBotName
+
+	checkRunStatusCompleted          = "completed"
+	checkRunConclusionSuccess        = "success"
+	checkRunConclusionFailure        = "failure"
+	checkRunConclusionActionRequired = "action_required"
+	checkRunAnnotationLevelFailure   = "failure"
+
+	// checkRunAnnotationPath is the path github requires on the annotations - the missing authorizations aren't
+	// about a file, the annotations are listed on the check run
+	checkRunAnnotationPath = ".github"
+
+	// maxCheckRunAnnotations is the maximum number of annotations github accepts per request
+	maxCheckRunAnnotations = 50
+)
+
+// the merge group and check suite actions we report the CLA status for
+const (
+	MergeGroupActionChecksRequested = "checks_requested"
+	CheckSuiteActionRequested       = "requested"
+	CheckSuiteActionRerequested     = "rerequested"
+)
+
+// mergeGroupPullRequestRegex extracts the pull request number from the merge group head ref,
+// e.g. refs/heads/gh-readonly-queue/main/pr-123-0123456789abcdef
+var mergeGroupPullRequestRegex = regexp.MustCompile(`/pr-(\d+)-[0-9a-f]+$`)
+
+// ChecksClientFactory creates the checks client of a GitHub app installation
+type ChecksClientFactory func(installationID int64) (claGithub.ChecksClient, error)
+
+// MergeGroupEvent is the merge_group webhook payload, sent when a merge queue needs the checks of a merge group
+type MergeGroupEvent struct {
+	Action       *string              `json:"action,omitempty"`
+	MergeGroup   *MergeGroup          `json:"merge_group,omitempty"`
+	Repo         *github.Repository   `json:"repository,omitempty"`
+	Org          *github.Organization `json:"organization,omitempty"`
+	Sender       *github.User         `json:"sender,omitempty"`
+	Installation *github.Installation `json:"installation,omitempty"`
+}
+
+// MergeGroup is the group of pull requests merged together by a merge queue
+type MergeGroup struct {
+	HeadSHA    *string            `json:"head_sha,omitempty"`
+	HeadRef    *string            `json:"head_ref,omitempty"`
+	BaseSHA    *string            `json:"base_sha,omitempty"`
+	BaseRef    *string            `json:"base_ref,omitempty"`
+	HeadCommit *github.HeadCommit `json:"head_commit,omitempty"`
+}
+
+// unsignedAuthor is a commit author without CLA authorization and the commits they authored
+type unsignedAuthor struct {
+	author *CommitAuthor
+	shas   []string
+}
+
+// checkRunTarget is the commit we report the CLA status on
+type checkRunTarget struct {
+	owner             string
+	repoName          string
+	headSHA           string
+	installationID    int64
+	githubRepoID      int64
+	pullRequestNumber int
+}
+
+// ProcessMergeGroupEvent reports the CLA status of the commits of the merge group as a check run
+func (s *eventHandlerService) ProcessMergeGroupEvent(event *MergeGroupEvent) error {
+	ctx := utils.NewContext()
+	f := logrus.Fields{
+		"functionName":   "v2.github_activity.service.ProcessMergeGroupEvent",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+	}
+	if event.Action == nil {
+		return fmt.Errorf("no action found in event payload")
+	}
+	if *event.Action != MergeGroupActionChecksRequested {
+		log.WithFields(f).Debugf("no handler for merge group action : %s", *event.Action)
+		return nil
+	}
+	if event.MergeGroup == nil || event.MergeGroup.GetHeadSHA() == "" || event.MergeGroup.GetBaseSHA() == "" {
+		return fmt.Errorf("missing merge group object in event payload")
+	}
+
+	target, err := newCheckRunTarget(event.Repo, event.Installation, event.MergeGroup.GetHeadSHA())
+	if err != nil {
+		return err
+	}
+	target.pullRequestNumber = mergeGroupPullRequestNumber(event.MergeGroup.GetHeadRef())
+	f["repositoryName"] = target.repoName
+	f["headSHA"] = target.headSHA
+
+	repoModel, err := s.checkedRepository(ctx, target)
+	if err != nil || repoModel == nil {
+		return err
+	}
+
+	client, err := s.checksClientFactory(target.installationID)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to create the github checks client")
+		return err
+	}
+	commits, err := client.CompareCommits(ctx, target.owner, target.repoName, event.MergeGroup.GetBaseSHA(), target.headSHA)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to list the commits of the merge group")
+		return err
+	}
+
+	return s.createCLACheckRun(ctx, client, target, repoModel, commits)
+}
+
+// ProcessCheckSuiteEvent reports the CLA status of the pull request commits as a check run of the check suite head commit
+func (s *eventHandlerService) ProcessCheckSuiteEvent(event *github.CheckSuiteEvent) error {
+	ctx := utils.NewContext()
+	f := logrus.Fields{
+		"functionName":   "v2.github_activity.service.ProcessCheckSuiteEvent",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+	}
+	if event.Action == nil {
+		return fmt.Errorf("no action found in event payload")
+	}
+	switch *event.Action {
+	case CheckSuiteActionRequested, CheckSuiteActionRerequested:
+	default:
+		log.WithFields(f).Debugf("no handler for check suite action : %s", *event.Action)
+		return nil
+	}
+	if event.CheckSuite == nil || event.CheckSuite.GetHeadSHA() == "" {
+		return fmt.Errorf("missing check suite object in event payload")
+	}
+
+	// a check suite of a push to a branch doesn't belong to a pull request - nothing to report
+	if len(event.CheckSuite.PullRequests) == 0 || event.CheckSuite.PullRequests[0].GetNumber() == 0 {
+		log.WithFields(f).Debug("check suite without pull request, nothing to do")
+		return nil
+	}
+
+	target, err := newCheckRunTarget(event.Repo, event.Installation, event.CheckSuite.GetHeadSHA())
+	if err != nil {
+		return err
+	}
+	target.pullRequestNumber = event.CheckSuite.PullRequests[0].GetNumber()
+	f["repositoryName"] = target.repoName
+	f["headSHA"] = target.headSHA
+	f["pullRequestNumber"] = target.pullRequestNumber
+
+	repoModel, err := s.checkedRepository(ctx, target)
+	if err != nil || repoModel == nil {
+		return err
+	}
+
+	client, err := s.checksClientFactory(target.installationID)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to create the github checks client")
+		return err
+	}
+	commits, err := client.ListPullRequestCommits(ctx, target.owner, target.repoName, target.pullRequestNumber)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to list the commits of the pull request")
+		return err
+	}
+
+	return s.createCLACheckRun(ctx, client, target, repoModel, commits)
+}
+
+// newCheckRunTarget validates the repository and installation of the event payload
+func newCheckRunTarget(repo *github.Repository, installation *github.Installation, headSHA string) (*checkRunTarget, error) {
+	if repo == nil || repo.GetID() == 0 || repo.GetName() == "" || repo.GetOwner().GetLogin() == "" {
+		return nil, fmt.Errorf("missing repository object in event payload")
+	}
+	if installation == nil || installation.GetID() == 0 {
+		return nil, fmt.Errorf("missing installation object in event payload")
+	}
+	return &checkRunTarget{
+		owner:          repo.GetOwner().GetLogin(),
+		repoName:       repo.GetName(),
+		headSHA:        headSHA,
+		installationID: installation.GetID(),
+		githubRepoID:   repo.GetID(),
+	}, nil
+}
+
+// checkedRepository returns the enabled repository of the event, nil when the repository isn't part of a CLA Group
+func (s *eventHandlerService) checkedRepository(ctx context.Context, target *checkRunTarget) (*models.GithubRepository, error) {
+	f := logrus.Fields{
+		"functionName":   "v2.github_activity.service.checkedRepository",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"repositoryName": target.repoName,
+	}
+	repositoryExternalID := strconv.FormatInt(target.githubRepoID, 10)
+	repoModel, err := s.githubRepo.GetRepositoryByGithubID(ctx, repositoryExternalID, true)
+	if err != nil {
+		if _, ok := err.(*utils.GitHubRepositoryNotFound); ok {
+			log.WithFields(f).Debug("event for a repository which is not part of a CLA Group, nothing to do")
+			return nil, nil
+		}
+		return nil, fmt.Errorf("fetching the repo : %s by external id : %s failed : %v", target.repoName, repositoryExternalID, err)
+	}
+	return repoModel, nil
+}
+
+// createCLACheckRun creates the completed check run with an annotation per commit author missing a CLA authorization
+func (s *eventHandlerService) createCLACheckRun(ctx context.Context, client claGithub.ChecksClient, target *checkRunTarget, repoModel *models.GithubRepository, commits []*github.RepositoryCommit) error {
+	f := logrus.Fields{
+		"functionName":   "v2.github_activity.service.createCLACheckRun",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"repositoryName": target.repoName,
+		"headSHA":        target.headSHA,
+		"claGroupID":     repoModel.RepositoryProjectID,
+	}
+
+	unsigned, err := s.unsignedAuthors(ctx, repoModel.RepositoryProjectID, commits)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to check the CLA of the commit authors")
+		return err
+	}
+
+	opts := checkRunOptions(target, unsigned, s.signURL(target))
+	log.WithFields(f).Debugf("creating check run with conclusion : %s", *opts.Conclusion)
+	if _, err := client.CreateCheckRun(ctx, target.owner, target.repoName, opts); err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to create the check run")
+		return err
+	}
+	return nil
+}
+
+// unsignedAuthors returns the commit authors without CLA authorization, sorted by name
+func (s *eventHandlerService) unsignedAuthors(ctx context.Context, claGroupID string, commits []*github.RepositoryCommit) ([]*unsignedAuthor, error) {
+	checked := map[string]*unsignedAuthor{}
+	signedAuthors := map[string]bool{}
+	var unsigned []*unsignedAuthor
+	for _, commit := range commits {
+		author := commitAuthor(commit)
+		key := strings.ToLower(author.Login)
+		if key == "" {
+			key = strings.ToLower(author.Email)
+		}
+		if signedAuthors[key] {
+			continue
+		}
+		if entry, ok := checked[key]; ok {
+			entry.shas = append(entry.shas, commit.GetSHA())
+			continue
+		}
+
+		// without a github account nobody can sign for the commit
+		signed := false
+		if author.Login != "" {
+			var err error
+			signed, err = s.claChecker.HasSignedCLA(ctx, claGroupID, author)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if signed {
+			signedAuthors[key] = true
+			continue
+		}
+		entry := &unsignedAuthor{author: author, shas: []string{commit.GetSHA()}}
+		if key != "" {
+			checked[key] = entry
+		}
+		unsigned = append(unsigned, entry)
+	}
+
+	sort.SliceStable(unsigned, func(i, j int) bool {
+		return unsigned[i].displayName() < unsigned[j].displayName()
+	})
+	return unsigned, nil
+}
+
+// signURL returns the link to the CLA signing flow of the pull request, empty when it can't be determined
+func (s *eventHandlerService) signURL(target *checkRunTarget) string {
+	if s.claAPIURL == "" || target.pullRequestNumber == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s/v2/repository-provider/github/sign/%d/%d/%d",
+		strings.TrimSuffix(s.claAPIURL, "/"), target.installationID, target.githubRepoID, target.pullRequestNumber)
+}
+
+// checkRunOptions builds the completed check run - the authors missing a CLA authorization are listed in the
+// summary and annotated with the link to sign
+func checkRunOptions(target *checkRunTarget, unsigned []*unsignedAuthor, signURL string) github.CreateCheckRunOptions {
+	opts := github.CreateCheckRunOptions{
+		Name:       CheckRunName,
+		HeadSHA:    target.headSHA,
+		Status:     github.String(checkRunStatusCompleted),
+		Conclusion: github.String(checkRunConclusionSuccess),
+		Output: &github.CheckRunOutput{
+			Title:   github.String("All committers have signed the CLA."),
+			Summary: github.String("The commit authors are covered by a signed CLA."),
+		},
+	}
+	if signURL != "" {
+		opts.DetailsURL = github.String(signURL)
+	}
+	if len(unsigned) == 0 {
+		return opts
+	}
+
+	// action required needs the details link to act on
+	opts.Conclusion = github.String(checkRunConclusionFailure)
+	if signURL != "" {
+		opts.Conclusion = github.String(checkRunConclusionActionRequired)
+	}
+
+	var summary strings.Builder
+	summary.WriteString("The following commit authors are not covered by a signed CLA:\n\n")
+	var annotations []*github.CheckRunAnnotation
+	for _, entry := range unsigned {
+		summary.WriteString(fmt.Sprintf("- %s (%s)\n", entry.displayName(), shortSHAs(entry.shas)))
+		if len(annotations) == maxCheckRunAnnotations {
+			continue
+		}
+		message := fmt.Sprintf("%s authored %s without a signed CLA.", entry.displayName(), shortSHAs(entry.shas))
+		if entry.author.Login == "" && entry.author.Email == "" {
+			message = fmt.Sprintf("The author of %s can't be identified, the commit email must be linked to a GitHub account.", shortSHAs(entry.shas))
+		}
+		if signURL != "" {
+			message = message + " Sign the CLA at " + signURL
+		}
+		annotation := &github.CheckRunAnnotation{
+			Path:            github.String(checkRunAnnotationPath),
+			StartLine:       github.Int(1),
+			EndLine:         github.Int(1),
+			AnnotationLevel: github.String(checkRunAnnotationLevelFailure),
+			Title:           github.String("Missing CLA authorization"),
+			Message:         github.String(message),
+		}
+		if signURL != "" {
+			annotation.RawDetails = github.String(signURL)
+		}
+		annotations = append(annotations, annotation)
+	}
+	if signURL != "" {
+		summary.WriteString(fmt.Sprintf("\n[Sign the CLA](%s)\n", signURL))
+	}
+
+	opts.Output = &github.CheckRunOutput{
+		Title:       github.String(fmt.Sprintf("Missing CLA authorization for %d commit author(s).", len(unsigned))),
+		Summary:     github.String(summary.String()),
+		Annotations: annotations,
+	}
+	return opts
+}
+
+// commitAuthor returns the author of the commit, the github login when the commit email is linked to an account
+func commitAuthor(commit *github.RepositoryCommit) *CommitAuthor {
+	author := &CommitAuthor{
+		Login: commit.GetAuthor().GetLogin(),
+	}
+	if commit.Commit != nil && commit.Commit.Author != nil {
+		author.Email = commit.Commit.Author.GetEmail()
+		author.Name = commit.Commit.Author.GetName()
+	}
+	return author
+}
+
+// displayName returns the way the author is shown in the check run
+func (u *unsignedAuthor) displayName() string {
+	switch {
+	case u.author.Login != "":
+		return "@" + u.author.Login
+	case u.author.Name != "" && u.author.Email != "":
+		return fmt.Sprintf("%s <%s>", u.author.Name, u.author.Email)
+	case u.author.Email != "":
+		return u.author.Email
+	default:
+		return "unknown author"
+	}
+}
+
+// mergeGroupPullRequestNumber returns the number of the pull request at the head of the merge group, 0 when unknown
+func mergeGroupPullRequestNumber(headRef string) int {
+	match := mergeGroupPullRequestRegex.FindStringSubmatch(headRef)
+	if match == nil {
+		return 0
+	}
+	number, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0
+	}
+	return number
+}
+
+func shortSHAs(shas []string) string {
+	short := make([]string, 0, len(shas))
+	for _, sha := range shas {
+		if len(sha) > 7 {
+			sha = sha[:7]
+		}
+		short = append(short, sha)
+	}
+	return strings.Join(short, ", ")
+}
+
+// GetHeadSHA returns the HeadSHA field if it's non-nil, zero value otherwise
+func (m *MergeGroup) GetHeadSHA() string {
+	if m == nil || m.HeadSHA == nil {
+		return ""
+	}
+	return *m.HeadSHA
+}
+
+// GetHeadRef returns the HeadRef field if it's non-nil, zero value otherwise
+func (m *MergeGroup) GetHeadRef() string {
+	if m == nil || m.HeadRef == nil {
+		return ""
+	}
+	return *m.HeadRef
+}
+
+// GetBaseSHA returns the BaseSHA field if it's non-nil, zero value otherwise
+func (m *MergeGroup) GetBaseSHA() string {
+	if m == nil || m.BaseSHA == nil {
+		return ""
+	}
+	return *m.BaseSHA
+}
@@ -0,0 +1,196 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package github_activity
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	claGithub "github.com/communitybridge/easycla/cla-backend-go/github"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/google/go-github/v33/github"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testClaAPIURL      = "https://api.dev.lfcla.com"
+	testClaGroupID     = "c057ed9a-4235-4acf-80bd-c7b4c235eff9"
+	testGithubRepoID   = "198682561"
+	testInstallationID = int64(1973025)
+)
+
+// fakeChecksClient returns the recorded commits and records the created check runs
+type fakeChecksClient struct {
+	commits     []*github.RepositoryCommit
+	compared    []string
+	pullRequest int
+	checkRuns   []github.CreateCheckRunOptions
+}
+
+func (c *fakeChecksClient) ListPullRequestCommits(ctx context.Context, owner, repo string, number int) ([]*github.RepositoryCommit, error) {
+	c.pullRequest = number
+	return c.commits, nil
+}
+
+func (c *fakeChecksClient) CompareCommits(ctx context.Context, owner, repo, base, head string) ([]*github.RepositoryCommit, error) {
+	c.compared = []string{owner, repo, base, head}
+	return c.commits, nil
+}
+
+func (c *fakeChecksClient) CreateCheckRun(ctx context.Context, owner, repo string, opts github.CreateCheckRunOptions) (*github.CheckRun, error) {
+	c.checkRuns = append(c.checkRuns, opts)
+	return &github.CheckRun{ID: github.Int64(1)}, nil
+}
+
+// fakeCLAChecker authorizes the authors by github login or email
+type fakeCLAChecker map[string]bool
+
+func (c fakeCLAChecker) HasSignedCLA(ctx context.Context, claGroupID string, author *CommitAuthor) (bool, error) {
+	return c[author.Login] || c[author.Email], nil
+}
+
+func testCommit(sha, login, name, email string) *github.RepositoryCommit {
+	commit := &github.RepositoryCommit{
+		SHA: github.String(sha),
+		Commit: &github.Commit{
+			Author: &github.CommitAuthor{Name: github.String(name), Email: github.String(email)},
+		},
+	}
+	if login != "" {
+		commit.Author = &github.User{Login: github.String(login)}
+	}
+	return commit
+}
+
+func loadTestEvent(t *testing.T, githubEvent, file string) interface{} {
+	payload, err := ioutil.ReadFile("testdata/" + file)
+	if err != nil {
+		t.Fatalf("unable to read the recorded payload : %v", err)
+	}
+	event, err := parseWebHook(githubEvent, payload)
+	if err != nil {
+		t.Fatalf("unable to parse the recorded payload : %v", err)
+	}
+	return event
+}
+
+func newCheckRunsService(ctrl *gomock.Controller, client *fakeChecksClient, checker fakeCLAChecker) Service {
+	githubRepo := mock.NewMockRepository(ctrl)
+	githubRepo.EXPECT().
+		GetRepositoryByGithubID(gomock.Any(), testGithubRepoID, true).
+		Return(&models.GithubRepository{
+			Enabled:             true,
+			RepositoryID:        "1f15f478-0659-43f3-bcf1-383052de7616",
+			RepositoryName:      "octo-org/hello-world",
+			RepositoryProjectID: testClaGroupID,
+		}, nil)
+
+	factory := func(installationID int64) (claGithub.ChecksClient, error) {
+		if installationID != testInstallationID {
+			return nil, assert.AnError
+		}
+		return client, nil
+	}
+	return newService(githubRepo, nil, nil, nil, nil, checker, factory, testClaAPIURL, false)
+}
+
+func TestEventHandlerService_ProcessMergeGroupEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := &fakeChecksClient{
+		commits: []*github.RepositoryCommit{
+			testCommit("1111111aaaaaaa", "signed-user", "Signed User", "signed@example.com"),
+			testCommit("2222222bbbbbbb", "unsigned-user", "Unsigned User", "unsigned@example.com"),
+			testCommit("3333333ccccccc", "unsigned-user", "Unsigned User", "unsigned@example.com"),
+			testCommit("4444444ddddddd", "", "No Account", "no-account@example.com"),
+		},
+	}
+	// the commit without a linked github account is not covered, even with the email of a signer
+	service := newCheckRunsService(ctrl, client, fakeCLAChecker{"signed-user": true, "no-account@example.com": true})
+
+	event, ok := loadTestEvent(t, "merge_group", "merge_group_checks_requested.json").(*MergeGroupEvent)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.NoError(t, service.ProcessMergeGroupEvent(event))
+
+	// the merge group commits are the commits between the base and the head of the group
+	assert.Equal(t, []string{"octo-org", "hello-world", "4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2e3f", "c6a4d5c3e2f1b0a9c8d7e6f5a4b3c2d1e0f9a8b7"}, client.compared)
+	if !assert.Len(t, client.checkRuns, 1) {
+		return
+	}
+	checkRun := client.checkRuns[0]
+	signURL := "https://api.dev.lfcla.com/v2/repository-provider/github/sign/1973025/198682561/42"
+	assert.Equal(t, "EasyCLA", checkRun.Name)
+	assert.Equal(t, "c6a4d5c3e2f1b0a9c8d7e6f5a4b3c2d1e0f9a8b7", checkRun.HeadSHA)
+	assert.Equal(t, "completed", *checkRun.Status)
+	assert.Equal(t, "action_required", *checkRun.Conclusion)
+	assert.Equal(t, signURL, *checkRun.DetailsURL)
+
+	annotations := checkRun.Output.Annotations
+	if assert.Len(t, annotations, 2) {
+		assert.Equal(t, "@unsigned-user authored 2222222, 3333333 without a signed CLA. Sign the CLA at "+signURL, annotations[0].GetMessage())
+		assert.Equal(t, "No Account <no-account@example.com> authored 4444444 without a signed CLA. Sign the CLA at "+signURL, annotations[1].GetMessage())
+		assert.Equal(t, "failure", annotations[1].GetAnnotationLevel())
+		assert.Equal(t, signURL, annotations[1].GetRawDetails())
+	}
+	assert.Equal(t, "Missing CLA authorization for 2 commit author(s).", checkRun.Output.GetTitle())
+	assert.True(t, strings.Contains(checkRun.Output.GetSummary(), "[Sign the CLA]("+signURL+")"))
+	assert.False(t, strings.Contains(checkRun.Output.GetSummary(), "signed-user"))
+}
+
+func TestEventHandlerService_ProcessCheckSuiteEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := &fakeChecksClient{
+		commits: []*github.RepositoryCommit{
+			testCommit("ec26c3e57ca3a959", "monalisa", "Mona Lisa", "mona@example.com"),
+			testCommit("146e867f55c26428", "monalisa", "Mona Lisa", "mona-work@example.com"),
+		},
+	}
+	service := newCheckRunsService(ctrl, client, fakeCLAChecker{"monalisa": true})
+
+	event, ok := loadTestEvent(t, "check_suite", "check_suite_requested.json").(*github.CheckSuiteEvent)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.NoError(t, service.ProcessCheckSuiteEvent(event))
+
+	assert.Equal(t, 35, client.pullRequest)
+	if !assert.Len(t, client.checkRuns, 1) {
+		return
+	}
+	checkRun := client.checkRuns[0]
+	assert.Equal(t, "ec26c3e57ca3a959ca5aad62de7213c562f8c821", checkRun.HeadSHA)
+	assert.Equal(t, "success", *checkRun.Conclusion)
+	assert.Equal(t, "https://api.dev.lfcla.com/v2/repository-provider/github/sign/1973025/198682561/35", *checkRun.DetailsURL)
+	assert.Empty(t, checkRun.Output.Annotations)
+}
+
+func TestEventHandlerService_ProcessCheckSuiteEvent_IgnoredAction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	event, ok := loadTestEvent(t, "check_suite", "check_suite_requested.json").(*github.CheckSuiteEvent)
+	if !assert.True(t, ok) {
+		return
+	}
+	event.Action = github.String("completed")
+
+	// the repository isn't even looked up for the other actions
+	activityService := newService(mock.NewMockRepository(ctrl), nil, nil, nil, nil, fakeCLAChecker{}, nil, testClaAPIURL, false)
+	assert.NoError(t, activityService.ProcessCheckSuiteEvent(event))
+}
+
+func TestMergeGroupPullRequestNumber(t *testing.T) {
+	assert.Equal(t, 42, mergeGroupPullRequestNumber("refs/heads/gh-readonly-queue/main/pr-42-4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2e3f"))
+	assert.Equal(t, 7, mergeGroupPullRequestNumber("refs/heads/gh-readonly-queue/release/v1/pr-7-abc123"))
+	assert.Equal(t, 0, mergeGroupPullRequestNumber("refs/heads/main"))
+}
@@ -0,0 +1,79 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package github_activity
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// CommitAuthor identifies the author of a commit, the GitHub login is empty when the commit email isn't linked to a
+// GitHub account - the email and the name are only shown to the contributors
+type CommitAuthor struct {
+	Login string
+	Email string
+	Name  string
+}
+
+// CLAChecker determines whether the commit author is covered by a CLA of the CLA Group
+type CLAChecker interface {
+	HasSignedCLA(ctx context.Context, claGroupID string, author *CommitAuthor) (bool, error)
+}
+
+type claChecker struct {
+	usersService     users.Service
+	signatureService signatures.SignatureService
+}
+
+// NewCLAChecker creates a new CLA checker which checks the ICLA of the user, then the employee acknowledgement and
+// the approval list of the CCLA of the user's company - the checks are shared with the GitLab checker
+func NewCLAChecker(usersService users.Service, signatureService signatures.SignatureService) CLAChecker {
+	return &claChecker{
+		usersService:     usersService,
+		signatureService: signatureService,
+	}
+}
+
+// HasSignedCLA returns true if the GitHub account of the commit author has signed the ICLA or has acknowledged the CCLA
+// of their company and is on its approval list. The commit email is set by the committer and not verified, a commit
+// without a linked GitHub account is not covered and only the emails of the user record are checked against the
+// approval list.
+func (c *claChecker) HasSignedCLA(ctx context.Context, claGroupID string, author *CommitAuthor) (bool, error) {
+	f := logrus.Fields{
+		"functionName":   "v2.github_activity.cla_checker.HasSignedCLA",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+		"login":          author.Login,
+	}
+	if author.Login == "" {
+		log.WithFields(f).Debug("the commit author has no GitHub account")
+		return false, nil
+	}
+
+	userModel, err := c.usersService.GetUserByGitHubUsername(author.Login)
+	if err != nil || userModel == nil {
+		log.WithFields(f).Debugf("no user record found for the GitHub account of the commit author: %v", err)
+		return false, nil
+	}
+
+	gitHubUsername := userModel.GithubUsername
+	if gitHubUsername == "" {
+		gitHubUsername = author.Login
+	}
+	match, err := c.signatureService.CheckUserCLA(ctx, claGroupID, userModel, signatures.ApprovalCandidate{
+		Emails:         utils.RemoveDuplicates(append([]string{userModel.LfEmail}, userModel.Emails...)),
+		GitHubUsername: gitHubUsername,
+	})
+	if err != nil {
+		return false, err
+	}
+	log.WithFields(f).Debugf("CLA status of the commit author : %s", match.Reason)
+	return match.Approved, nil
+}
@@ -0,0 +1,70 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package github_activity
+
+import (
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubUsersService returns the user of the GitHub username
+type stubUsersService struct {
+	users.Service
+	users map[string]*models.User
+}
+
+func (s stubUsersService) GetUserByGitHubUsername(gitHubUsername string) (*models.User, error) {
+	return s.users[gitHubUsername], nil
+}
+
+func TestCLACheckerRequiresTheEmployeeAcknowledgement(t *testing.T) {
+	ctx := utils.NewContext()
+	signaturesRepo := signatures.NewMemoryRepository(company.NewMemoryRepository(), users.NewMemoryRepository(), nil, nil, nil, nil)
+	usersService := stubUsersService{users: map[string]*models.User{
+		"jdoe": {UserID: "user-jdoe", CompanyID: "company-acme", LfEmail: "jdoe@example.org"},
+	}}
+	checker := NewCLAChecker(usersService, signatures.NewService(signaturesRepo, nil, nil, nil, false))
+
+	// the GitHub username of the user is on the approval list of the CCLA of the company
+	assert.NoError(t, signaturesRepo.PutSignature(ctx, signatures.ItemSignature{
+		SignatureID:            "ccla-acme",
+		SignatureProjectID:     testClaGroupID,
+		SignatureReferenceID:   "company-acme",
+		SignatureReferenceType: utils.SignatureReferenceTypeCompany,
+		SignatureType:          utils.SignatureTypeCCLA,
+		SignatureApproved:      true,
+		SignatureSigned:        true,
+		GitHubWhitelist:        []string{"jdoe"},
+	}))
+
+	// the commit status of the python backend rejects the user until the CCLA is acknowledged, so does the check run
+	signed, err := checker.HasSignedCLA(ctx, testClaGroupID, &CommitAuthor{Login: "jdoe"})
+	assert.NoError(t, err)
+	assert.False(t, signed)
+
+	assert.NoError(t, signaturesRepo.PutSignature(ctx, signatures.ItemSignature{
+		SignatureID:            "ecla-acme",
+		SignatureProjectID:     testClaGroupID,
+		SignatureReferenceID:   "user-jdoe",
+		SignatureReferenceType: utils.SignatureReferenceTypeUser,
+		SignatureType:          utils.SignatureTypeCLA,
+		SignatureUserCompanyID: "company-acme",
+		SignatureApproved:      true,
+		SignatureSigned:        true,
+	}))
+	signed, err = checker.HasSignedCLA(ctx, testClaGroupID, &CommitAuthor{Login: "jdoe"})
+	assert.NoError(t, err)
+	assert.True(t, signed)
+
+	// a commit without a user record isn't covered
+	signed, err = checker.HasSignedCLA(ctx, testClaGroupID, &CommitAuthor{Login: "jroe"})
+	assert.NoError(t, err)
+	assert.False(t, signed)
+}
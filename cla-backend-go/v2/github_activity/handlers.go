@@ -5,6 +5,7 @@ package github_activity
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -58,7 +59,7 @@ func Configure(api *operations.EasyclaAPI, service Service) {
 				})
 			}
 
-			event, err := github.ParseWebHook(githubEvent, payload)
+			event, err := parseWebHook(githubEvent, payload)
 			if err != nil {
 				return github_activity.NewGithubActivityBadRequest().WithPayload(&models.ErrorResponse{
 					Code:    "400",
@@ -72,6 +73,10 @@ func Configure(api *operations.EasyclaAPI, service Service) {
 				processError = service.ProcessInstallationRepositoriesEvent(event)
 			case *github.RepositoryEvent:
 				processError = service.ProcessRepositoryEvent(event)
+			case *MergeGroupEvent:
+				processError = service.ProcessMergeGroupEvent(event)
+			case *github.CheckSuiteEvent:
+				processError = service.ProcessCheckSuiteEvent(event)
 			default:
 				log.Warnf("unsupported event sent : %s", githubEvent)
 			}
@@ -85,6 +90,18 @@ func Configure(api *operations.EasyclaAPI, service Service) {
 	api.AddMiddlewareFor("POST", "/github/activity", signatureCheckMiddleware)
 }
 
+// parseWebHook parses the event payload - the merge_group event is parsed here, the github library doesn't know it
+func parseWebHook(githubEvent string, payload []byte) (interface{}, error) {
+	if githubEvent == "merge_group" {
+		event := &MergeGroupEvent{}
+		if err := json.Unmarshal(payload, event); err != nil {
+			return nil, err
+		}
+		return event, nil
+	}
+	return github.ParseWebHook(githubEvent, payload)
+}
+
 type codedResponse interface {
 	Code() string
 }
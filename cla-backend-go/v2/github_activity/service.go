@@ -33,25 +33,34 @@ import (
 type Service interface {
 	ProcessInstallationRepositoriesEvent(event *github.InstallationRepositoriesEvent) error
 	ProcessRepositoryEvent(*github.RepositoryEvent) error
+	ProcessMergeGroupEvent(event *MergeGroupEvent) error
+	ProcessCheckSuiteEvent(event *github.CheckSuiteEvent) error
 }
 
 type eventHandlerService struct {
-	githubRepo        repositories.Repository
-	githubOrgRepo     v1GithubOrg.RepositoryInterface
-	eventService      events.Service
-	autoEnableService dynamo_events.AutoEnableService
-	emailService      emails.Service
-	sendEmail         bool
+	githubRepo          repositories.Repository
+	githubOrgRepo       v1GithubOrg.RepositoryInterface
+	eventService        events.Service
+	autoEnableService   dynamo_events.AutoEnableService
+	emailService        emails.Service
+	claChecker          CLAChecker
+	checksClientFactory ChecksClientFactory
+	claAPIURL           string
+	sendEmail           bool
 }
 
-// NewService creates a new instance of the Event Handler Service
+// NewService creates a new instance of the Event Handler Service, the CLA status check runs link to the signing flow
+// of the CLA API
 func NewService(githubRepo repositories.Repository,
 	githubOrgRepo v1GithubOrg.RepositoryInterface,
 	eventService events.Service,
 	autoEnableService dynamo_events.AutoEnableService,
-	emailService emails.Service) Service {
+	emailService emails.Service,
+	claChecker CLAChecker,
+	checksClientFactory ChecksClientFactory,
+	claAPIURL string) Service {
 
-	return newService(githubRepo, githubOrgRepo, eventService, autoEnableService, emailService, true)
+	return newService(githubRepo, githubOrgRepo, eventService, autoEnableService, emailService, claChecker, checksClientFactory, claAPIURL, true)
 }
 
 func newService(githubRepo repositories.Repository,
@@ -59,14 +68,20 @@ func newService(githubRepo repositories.Repository,
 	eventService events.Service,
 	autoEnableService dynamo_events.AutoEnableService,
 	emailService emails.Service,
+	claChecker CLAChecker,
+	checksClientFactory ChecksClientFactory,
+	claAPIURL string,
 	sendEmail bool) Service {
 	return &eventHandlerService{
-		githubRepo:        githubRepo,
-		githubOrgRepo:     githubOrgRepo,
-		eventService:      eventService,
-		autoEnableService: autoEnableService,
-		emailService:      emailService,
-		sendEmail:         sendEmail,
+		githubRepo:          githubRepo,
+		githubOrgRepo:       githubOrgRepo,
+		eventService:        eventService,
+		autoEnableService:   autoEnableService,
+		emailService:        emailService,
+		claChecker:          claChecker,
+		checksClientFactory: checksClientFactory,
+		claAPIURL:           claAPIURL,
+		sendEmail:           sendEmail,
 	}
 }
 
@@ -54,7 +54,7 @@ func TestEventHandlerService_ProcessRepositoryEvent_HandleRepositoryRenamedActio
 			},
 		}).Return()
 
-	activityService := newService(githubRepo, githubOrganizationRepo, eventsService, nil, nil, false)
+	activityService := newService(githubRepo, githubOrganizationRepo, eventsService, nil, nil, nil, nil, "", false)
 	err := activityService.ProcessRepositoryEvent(&github.RepositoryEvent{
 		Action: aws.String("renamed"),
 		Repo: &github.Repository{
@@ -161,7 +161,7 @@ func TestEventHandlerService_ProcessRepositoryEvent_HandleRepositoryTransferredA
 					}).Return()
 			}
 
-			activityService := newService(githubRepo, githubOrganizationRepo, eventsService, nil, nil, false)
+			activityService := newService(githubRepo, githubOrganizationRepo, eventsService, nil, nil, nil, nil, "", false)
 			err := activityService.ProcessRepositoryEvent(&github.RepositoryEvent{
 				Action: aws.String("transferred"),
 				Repo: &github.Repository{
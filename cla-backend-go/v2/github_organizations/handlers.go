@@ -5,6 +5,7 @@ package github_organizations
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations/github_organizations"
 	"github.com/communitybridge/easycla/cla-backend-go/github"
+	v1GithubOrg "github.com/communitybridge/easycla/cla-backend-go/github_organizations"
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
 	"github.com/go-openapi/runtime/middleware"
 )
@@ -219,7 +221,7 @@ func Configure(api *operations.EasyclaAPI, service Service, eventService events.
 				return github_organizations.NewUpdateProjectGithubOrganizationConfigBadRequest().WithPayload(utils.ErrorResponseBadRequest(reqID, msg))
 			}
 
-			err := service.UpdateGithubOrganization(ctx, params.ProjectSFID, params.OrgName, *params.Body.AutoEnabled, params.Body.AutoEnabledClaGroupID, params.Body.BranchProtectionEnabled)
+			err := service.UpdateGithubOrganization(ctx, params.ProjectSFID, params.OrgName, *params.Body.AutoEnabled, params.Body.AutoEnabledClaGroupID, params.Body.BranchProtectionEnabled, params.Body.BranchProtectionPolicy)
 			if err != nil {
 				msg := fmt.Sprintf("problem updating GitHub Organization for project SFID: %s for organization: %s", params.ProjectSFID, params.OrgName)
 				log.WithFields(f).Debug(msg)
@@ -239,4 +241,101 @@ func Configure(api *operations.EasyclaAPI, service Service, eventService events.
 
 			return github_organizations.NewUpdateProjectGithubOrganizationConfigOK()
 		})
+
+	api.GithubOrganizationsGetProjectGithubOrganizationBranchProtectionAuditHandler = github_organizations.GetProjectGithubOrganizationBranchProtectionAuditHandlerFunc(
+		func(params github_organizations.GetProjectGithubOrganizationBranchProtectionAuditParams, authUser *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			ctx := context.WithValue(context.Background(), utils.XREQUESTID, reqID) // nolint
+
+			f := logrus.Fields{
+				"functionName":   "github_organization.handlers.GithubOrganizationsGetProjectGithubOrganizationBranchProtectionAuditHandler",
+				utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+				"projectSFID":    params.ProjectSFID,
+				"orgName":        params.OrgName,
+				"authUser":       authUser.UserName,
+				"authEmail":      authUser.Email,
+			}
+
+			if !utils.IsUserAuthorizedForProjectTree(ctx, authUser, params.ProjectSFID, utils.ALLOW_ADMIN_SCOPE) {
+				msg := fmt.Sprintf("user %s does not have access to Get Project GitHub Organization Branch Protection Audit with Project scope of %s",
+					authUser.UserName, params.ProjectSFID)
+				log.WithFields(f).Debug(msg)
+				return github_organizations.NewGetProjectGithubOrganizationBranchProtectionAuditForbidden().WithPayload(utils.ErrorResponseForbidden(reqID, msg))
+			}
+
+			result, err := service.GetBranchProtectionAudit(ctx, params.ProjectSFID, params.OrgName)
+			if err != nil {
+				if errors.Is(err, v1GithubOrg.ErrOrganizationDoesNotExist) || errors.Is(err, v1GithubOrg.ErrBranchProtectionAuditDoesNotExist) {
+					msg := fmt.Sprintf("branch protection audit not found for project SFID: %s for organization: %s", params.ProjectSFID, params.OrgName)
+					log.WithFields(f).Debug(msg)
+					return github_organizations.NewGetProjectGithubOrganizationBranchProtectionAuditNotFound().WithPayload(utils.ErrorResponseNotFoundWithError(reqID, msg, err))
+				}
+				msg := fmt.Sprintf("problem loading the branch protection audit for project SFID: %s for organization: %s", params.ProjectSFID, params.OrgName)
+				log.WithFields(f).WithError(err).Warn(msg)
+				return github_organizations.NewGetProjectGithubOrganizationBranchProtectionAuditInternalServerError().WithPayload(utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+			}
+
+			return github_organizations.NewGetProjectGithubOrganizationBranchProtectionAuditOK().WithPayload(result)
+		})
+
+	api.GithubOrganizationsAuditProjectGithubOrganizationBranchProtectionHandler = github_organizations.AuditProjectGithubOrganizationBranchProtectionHandlerFunc(
+		func(params github_organizations.AuditProjectGithubOrganizationBranchProtectionParams, authUser *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			ctx := context.WithValue(context.Background(), utils.XREQUESTID, reqID) // nolint
+
+			remediate := utils.BoolValue(params.Remediate)
+			f := logrus.Fields{
+				"functionName":   "github_organization.handlers.GithubOrganizationsAuditProjectGithubOrganizationBranchProtectionHandler",
+				utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+				"projectSFID":    params.ProjectSFID,
+				"orgName":        params.OrgName,
+				"remediate":      remediate,
+				"authUser":       authUser.UserName,
+				"authEmail":      authUser.Email,
+			}
+
+			if !utils.IsUserAuthorizedForProjectTree(ctx, authUser, params.ProjectSFID, utils.ALLOW_ADMIN_SCOPE) {
+				msg := fmt.Sprintf("user %s does not have access to Audit Project GitHub Organization Branch Protection with Project scope of %s",
+					authUser.UserName, params.ProjectSFID)
+				log.WithFields(f).Debug(msg)
+				return github_organizations.NewAuditProjectGithubOrganizationBranchProtectionForbidden().WithPayload(utils.ErrorResponseForbidden(reqID, msg))
+			}
+
+			result, err := service.AuditBranchProtection(ctx, params.ProjectSFID, params.OrgName, remediate)
+			if err != nil {
+				if errors.Is(err, v1GithubOrg.ErrOrganizationDoesNotExist) {
+					msg := fmt.Sprintf("GitHub Organization not found for project SFID: %s for organization: %s", params.ProjectSFID, params.OrgName)
+					log.WithFields(f).Debug(msg)
+					return github_organizations.NewAuditProjectGithubOrganizationBranchProtectionNotFound().WithPayload(utils.ErrorResponseNotFoundWithError(reqID, msg, err))
+				}
+				if errors.Is(err, v1GithubOrg.ErrBranchProtectionNotEnabled) {
+					msg := fmt.Sprintf("branch protection is not enabled for project SFID: %s for organization: %s", params.ProjectSFID, params.OrgName)
+					log.WithFields(f).Debug(msg)
+					return github_organizations.NewAuditProjectGithubOrganizationBranchProtectionBadRequest().WithPayload(utils.ErrorResponseBadRequestWithError(reqID, msg, err))
+				}
+				msg := fmt.Sprintf("problem auditing the branch protection for project SFID: %s for organization: %s", params.ProjectSFID, params.OrgName)
+				log.WithFields(f).WithError(err).Warn(msg)
+				return github_organizations.NewAuditProjectGithubOrganizationBranchProtectionInternalServerError().WithPayload(utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+			}
+
+			// Log an event for each repository brought back in line
+			for _, drift := range result.Drifts {
+				if !drift.Remediated {
+					continue
+				}
+				eventService.LogEventWithContext(ctx, &events.LogEventArgs{
+					EventType:   events.RepositoryBranchProtectionUpdated,
+					ProjectSFID: params.ProjectSFID,
+					ProjectID:   drift.ClaGroupID,
+					LfUsername:  authUser.UserName,
+					EventData: &events.RepositoryBranchProtectionUpdatedEventData{
+						RepositoryName: drift.RepositoryName,
+					},
+				})
+			}
+
+			return github_organizations.NewAuditProjectGithubOrganizationBranchProtectionOK().WithPayload(result)
+		})
 }
@@ -22,6 +22,7 @@ import (
 
 	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	"github.com/communitybridge/easycla/cla-backend-go/github/branch_protection"
 	v1GithubOrg "github.com/communitybridge/easycla/cla-backend-go/github_organizations"
 	v1Repositories "github.com/communitybridge/easycla/cla-backend-go/repositories"
 	v2ProjectService "github.com/communitybridge/easycla/cla-backend-go/v2/project-service"
@@ -37,12 +38,34 @@ func v2GithubOrganizationModel(in *v1Models.GithubOrganization) (*models.GithubO
 	return &response, nil
 }
 
+func v2BranchProtectionPolicyModel(in *v1Models.GithubBranchProtectionPolicy) *models.GithubBranchProtectionPolicy {
+	if in == nil {
+		return nil
+	}
+	return &models.GithubBranchProtectionPolicy{
+		BranchPatterns:       in.BranchPatterns,
+		RequiredStatusChecks: in.RequiredStatusChecks,
+		EnforceAdmin:         in.EnforceAdmin,
+	}
+}
+
+func v2BranchProtectionAuditModel(in *v1GithubOrg.BranchProtectionAudit) (*models.GithubOrganizationBranchProtectionAudit, error) {
+	var response models.GithubOrganizationBranchProtectionAudit
+	err := copier.Copy(&response, in)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
 // Service contains functions of GithubOrganizations service
 type Service interface {
 	GetGithubOrganizations(ctx context.Context, projectSFID string) (*models.ProjectGithubOrganizations, error)
 	AddGithubOrganization(ctx context.Context, projectSFID string, input *models.CreateGithubOrganization) (*models.GithubOrganization, error)
 	DeleteGithubOrganization(ctx context.Context, projectSFID string, githubOrgName string) error
-	UpdateGithubOrganization(ctx context.Context, projectSFID string, organizationName string, autoEnabled bool, autoEnabledClaGroupID string, branchProtectionEnabled bool) error
+	UpdateGithubOrganization(ctx context.Context, projectSFID string, organizationName string, autoEnabled bool, autoEnabledClaGroupID string, branchProtectionEnabled bool, branchProtectionPolicy *models.GithubBranchProtectionPolicy) error
+	GetBranchProtectionAudit(ctx context.Context, projectSFID string, organizationName string) (*models.GithubOrganizationBranchProtectionAudit, error)
+	AuditBranchProtection(ctx context.Context, projectSFID string, organizationName string, remediate bool) (*models.GithubOrganizationBranchProtectionAudit, error)
 }
 
 type service struct {
@@ -50,15 +73,17 @@ type service struct {
 	ghRepository            v1Repositories.Repository
 	ghService               v1GithubOrg.ServiceInterface
 	projectsCLAGroupService projects_cla_groups.Repository
+	branchProtectionAuditor v1GithubOrg.BranchProtectionAuditor
 }
 
 // NewService creates a new githubOrganizations service
-func NewService(repo v1GithubOrg.RepositoryInterface, ghRepository v1Repositories.Repository, projectsCLAGroupService projects_cla_groups.Repository, ghService v1GithubOrg.ServiceInterface) Service {
+func NewService(repo v1GithubOrg.RepositoryInterface, ghRepository v1Repositories.Repository, projectsCLAGroupService projects_cla_groups.Repository, ghService v1GithubOrg.ServiceInterface, branchProtectionAuditor v1GithubOrg.BranchProtectionAuditor) Service {
 	return service{
 		repo:                    repo,
 		ghRepository:            ghRepository,
 		projectsCLAGroupService: projectsCLAGroupService,
 		ghService:               ghService,
+		branchProtectionAuditor: branchProtectionAuditor,
 	}
 }
 
@@ -156,6 +181,7 @@ func (s service) GetGithubOrganizations(ctx context.Context, projectSFID string)
 			AutoEnableCLAGroupID:    org.AutoEnabledClaGroupID,
 			AutoEnabledCLAGroupName: autoEnabledCLAGroupName,
 			BranchProtectionEnabled: org.BranchProtectionEnabled,
+			BranchProtectionPolicy:  v2BranchProtectionPolicyModel(org.BranchProtectionPolicy),
 			ConnectionStatus:        "", // updated below
 			GithubOrganizationName:  org.OrganizationName,
 			Repositories:            make([]*models.ProjectGithubRepository, 0),
@@ -309,8 +335,22 @@ func (s service) AddGithubOrganization(ctx context.Context, projectSFID string,
 	return v2GithubOrganizationModel(resp)
 }
 
-func (s service) UpdateGithubOrganization(ctx context.Context, projectSFID string, organizationName string, autoEnabled bool, autoEnabledClaGroupID string, branchProtectionEnabled bool) error {
-	return s.repo.UpdateGithubOrganization(ctx, projectSFID, organizationName, autoEnabled, autoEnabledClaGroupID, branchProtectionEnabled, nil)
+func (s service) UpdateGithubOrganization(ctx context.Context, projectSFID string, organizationName string, autoEnabled bool, autoEnabledClaGroupID string, branchProtectionEnabled bool, branchProtectionPolicy *models.GithubBranchProtectionPolicy) error {
+	var policy *v1Models.GithubBranchProtectionPolicy
+	if branchProtectionPolicy != nil {
+		policy = &v1Models.GithubBranchProtectionPolicy{
+			BranchPatterns:       branchProtectionPolicy.BranchPatterns,
+			RequiredStatusChecks: branchProtectionPolicy.RequiredStatusChecks,
+			EnforceAdmin:         branchProtectionPolicy.EnforceAdmin,
+		}
+		// the empty policy clears the policy of the organization
+		if !v1GithubOrg.IsBranchProtectionPolicyCleared(policy) {
+			if err := branch_protection.ValidateBranchProtectionPolicy(v1GithubOrg.ToBranchProtectionPolicy(policy)); err != nil {
+				return err
+			}
+		}
+	}
+	return s.repo.UpdateGithubOrganization(ctx, projectSFID, organizationName, autoEnabled, autoEnabledClaGroupID, branchProtectionEnabled, policy, nil)
 }
 
 func (s service) DeleteGithubOrganization(ctx context.Context, projectSFID string, githubOrgName string) error {
@@ -339,3 +379,64 @@ func (s service) DeleteGithubOrganization(ctx context.Context, projectSFID strin
 	log.WithFields(f).Debug("deleting github github organization...")
 	return s.repo.DeleteGithubOrganization(ctx, projectSFID, githubOrgName)
 }
+
+// GetBranchProtectionAudit returns the last branch protection audit of the github organization of the project
+func (s service) GetBranchProtectionAudit(ctx context.Context, projectSFID string, organizationName string) (*models.GithubOrganizationBranchProtectionAudit, error) {
+	f := logrus.Fields{
+		"functionName":     "v2.github_organizations.service.GetBranchProtectionAudit",
+		utils.XREQUESTID:   ctx.Value(utils.XREQUESTID),
+		"projectSFID":      projectSFID,
+		"organizationName": organizationName,
+	}
+
+	githubOrg, err := s.projectGithubOrganization(ctx, projectSFID, organizationName)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem loading the github organization of the project")
+		return nil, err
+	}
+
+	audit, err := s.repo.GetBranchProtectionAudit(ctx, githubOrg.OrganizationName)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem loading the branch protection audit of the github organization")
+		return nil, err
+	}
+	return v2BranchProtectionAuditModel(audit)
+}
+
+// AuditBranchProtection audits the branch protection of the github organization of the project now
+func (s service) AuditBranchProtection(ctx context.Context, projectSFID string, organizationName string, remediate bool) (*models.GithubOrganizationBranchProtectionAudit, error) {
+	f := logrus.Fields{
+		"functionName":     "v2.github_organizations.service.AuditBranchProtection",
+		utils.XREQUESTID:   ctx.Value(utils.XREQUESTID),
+		"projectSFID":      projectSFID,
+		"organizationName": organizationName,
+		"remediate":        remediate,
+	}
+
+	githubOrg, err := s.projectGithubOrganization(ctx, projectSFID, organizationName)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem loading the github organization of the project")
+		return nil, err
+	}
+
+	audit, err := s.branchProtectionAuditor.AuditOrganization(ctx, githubOrg, remediate)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem auditing the branch protection of the github organization")
+		return nil, err
+	}
+	return v2BranchProtectionAuditModel(audit)
+}
+
+// projectGithubOrganization returns the enabled github organization added to the project or to its children
+func (s service) projectGithubOrganization(ctx context.Context, projectSFID string, organizationName string) (*v1Models.GithubOrganization, error) {
+	githubOrgs, err := s.repo.GetGithubOrganizationByName(ctx, organizationName)
+	if err != nil {
+		return nil, err
+	}
+	for _, githubOrg := range githubOrgs.List {
+		if githubOrg.Enabled && (githubOrg.ProjectSFID == projectSFID || githubOrg.OrganizationSfid == projectSFID) {
+			return githubOrg, nil
+		}
+	}
+	return nil, v1GithubOrg.ErrOrganizationDoesNotExist
+}
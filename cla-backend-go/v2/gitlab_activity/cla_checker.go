@@ -0,0 +1,69 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package gitlab_activity
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gitlab"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// CLAChecker determines whether the commit author is covered by a CLA of the CLA Group
+type CLAChecker interface {
+	HasSignedCLA(ctx context.Context, claGroupID string, author *gitlab.User) (bool, error)
+}
+
+type claChecker struct {
+	usersService     users.Service
+	signatureService signatures.SignatureService
+}
+
+// NewCLAChecker creates a new CLA checker which checks the ICLA of the user, then the employee acknowledgement and
+// the approval list of the CCLA of the user's company
+func NewCLAChecker(usersService users.Service, signatureService signatures.SignatureService) CLAChecker {
+	return &claChecker{
+		usersService:     usersService,
+		signatureService: signatureService,
+	}
+}
+
+// HasSignedCLA returns true if the user linked to the GitLab account of the commit author has signed the ICLA or has
+// acknowledged the CCLA of their company and is on its approval list. The user is located by the GitLab user ID, the
+// commit author email is not trusted.
+func (c *claChecker) HasSignedCLA(ctx context.Context, claGroupID string, author *gitlab.User) (bool, error) {
+	f := logrus.Fields{
+		"functionName":   "v2.gitlab_activity.cla_checker.HasSignedCLA",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+	}
+	if author == nil || author.ID == 0 {
+		log.WithFields(f).Debug("the commit author has no GitLab account")
+		return false, nil
+	}
+	f["gitLabID"] = author.ID
+	f["gitLabUsername"] = author.Username
+
+	userModel, err := c.usersService.GetUserByGitLabID(strconv.FormatInt(author.ID, 10))
+	if err != nil || userModel == nil {
+		log.WithFields(f).Debugf("no user record found for the GitLab account of the commit author: %v", err)
+		return false, nil
+	}
+
+	match, err := c.signatureService.CheckUserCLA(ctx, claGroupID, userModel, signatures.ApprovalCandidate{
+		Emails:         utils.RemoveDuplicates(append([]string{userModel.LfEmail}, userModel.Emails...)),
+		GitHubUsername: userModel.GithubUsername,
+	})
+	if err != nil {
+		return false, err
+	}
+	log.WithFields(f).Debugf("CLA status of the commit author : %s", match.Reason)
+	return match.Approved, nil
+}
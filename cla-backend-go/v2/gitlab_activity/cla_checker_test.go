@@ -0,0 +1,90 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package gitlab_activity
+
+import (
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gitlab"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubUsersService returns the user linked to any GitLab account
+type stubUsersService struct {
+	users.Service
+	user *models.User
+}
+
+func (s stubUsersService) GetUserByGitLabID(gitLabID string) (*models.User, error) {
+	return s.user, nil
+}
+
+func TestCLACheckerRequiresTheEmployeeAcknowledgement(t *testing.T) {
+	ctx := utils.NewContext()
+	signaturesRepo := signatures.NewMemoryRepository(company.NewMemoryRepository(), users.NewMemoryRepository(), nil, nil, nil, nil)
+	userModel := &models.User{
+		UserID:    "user-jdoe",
+		CompanyID: "company-acme",
+		LfEmail:   "jdoe@acme.org",
+	}
+	checker := NewCLAChecker(stubUsersService{user: userModel}, signatures.NewService(signaturesRepo, nil, nil, nil, false))
+	author := &gitlab.User{ID: 1001, Username: "jdoe"}
+
+	// the user is on the approval list of the CCLA of the company
+	assert.NoError(t, signaturesRepo.PutSignature(ctx, signatures.ItemSignature{
+		SignatureID:            "ccla-acme",
+		SignatureProjectID:     "cla-group",
+		SignatureReferenceID:   "company-acme",
+		SignatureReferenceType: utils.SignatureReferenceTypeCompany,
+		SignatureType:          utils.SignatureTypeCCLA,
+		SignatureApproved:      true,
+		SignatureSigned:        true,
+		DomainWhitelist:        []string{"acme.org"},
+	}))
+
+	// the approval list alone doesn't cover a user who never acknowledged the CCLA
+	signed, err := checker.HasSignedCLA(ctx, "cla-group", author)
+	assert.NoError(t, err)
+	assert.False(t, signed)
+
+	// neither does the acknowledgement of the CCLA of another company
+	assert.NoError(t, signaturesRepo.PutSignature(ctx, signatures.ItemSignature{
+		SignatureID:            "ecla-other",
+		SignatureProjectID:     "cla-group",
+		SignatureReferenceID:   "user-jdoe",
+		SignatureReferenceType: utils.SignatureReferenceTypeUser,
+		SignatureType:          utils.SignatureTypeCLA,
+		SignatureUserCompanyID: "company-other",
+		SignatureApproved:      true,
+		SignatureSigned:        true,
+	}))
+	signed, err = checker.HasSignedCLA(ctx, "cla-group", author)
+	assert.NoError(t, err)
+	assert.False(t, signed)
+
+	assert.NoError(t, signaturesRepo.PutSignature(ctx, signatures.ItemSignature{
+		SignatureID:            "ecla-acme",
+		SignatureProjectID:     "cla-group",
+		SignatureReferenceID:   "user-jdoe",
+		SignatureReferenceType: utils.SignatureReferenceTypeUser,
+		SignatureType:          utils.SignatureTypeCLA,
+		SignatureUserCompanyID: "company-acme",
+		SignatureApproved:      true,
+		SignatureSigned:        true,
+	}))
+	signed, err = checker.HasSignedCLA(ctx, "cla-group", author)
+	assert.NoError(t, err)
+	assert.True(t, signed)
+
+	// the acknowledgement doesn't cover a user who is no longer on the approval list
+	userModel.LfEmail = "jdoe@example.org"
+	signed, err = checker.HasSignedCLA(ctx, "cla-group", author)
+	assert.NoError(t, err)
+	assert.False(t, signed)
+}
@@ -0,0 +1,91 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package gitlab_activity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/go-openapi/runtime/middleware"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations/gitlab_activity"
+	"github.com/communitybridge/easycla/cla-backend-go/gitlab"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// tokenCheckMiddleware validates the webhook secret token of the raw http request
+func tokenCheckMiddleware(webhookSecret string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			payload, err := gitlab.ReadPayload(r, webhookSecret)
+			if err != nil {
+				log.Warnf("gitlab webhook token check failed : %v", err)
+				http.Error(w, "token check failure", 401)
+				return
+			}
+			defer r.Body.Close()
+			r.Body = ioutil.NopCloser(bytes.NewBuffer(payload))
+			// call the next middleware
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Configure setups handlers on api with service
+func Configure(api *operations.EasyclaAPI, service Service, webhookSecret string) {
+	if webhookSecret == "" {
+		log.Warn("no gitlab webhook secret configured - the gitlab activity events are rejected")
+	}
+	api.GitlabActivityGitlabActivityHandler = gitlab_activity.GitlabActivityHandlerFunc(
+		func(params gitlab_activity.GitlabActivityParams) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+
+			gitlabEvent := utils.StringValue(params.XGITLABEVENT)
+			if gitlabEvent == "" {
+				return gitlab_activity.NewGitlabActivityBadRequest().WithPayload(&models.ErrorResponse{
+					Code:    "400",
+					Message: "missing gitlab event",
+				})
+			}
+
+			payload, err := json.Marshal(params.GitlabActivityInput)
+			if err != nil {
+				return gitlab_activity.NewGitlabActivityBadRequest().WithPayload(&models.ErrorResponse{
+					Code:    "400",
+					Message: "json marshall",
+				})
+			}
+
+			event, err := gitlab.ParseWebHook(gitlabEvent, payload)
+			if err != nil {
+				log.Warnf("unsupported gitlab event sent : %s : %v", gitlabEvent, err)
+				// unsupported system hook events are acknowledged so gitlab doesn't disable the hook
+				return gitlab_activity.NewGitlabActivityOK()
+			}
+
+			var processError error
+			switch event := event.(type) {
+			case *gitlab.ProjectEvent:
+				processError = service.ProcessProjectEvent(ctx, event)
+			case *gitlab.MergeRequestEvent:
+				processError = service.ProcessMergeRequestEvent(ctx, event)
+			default:
+				log.Warnf("unsupported event sent : %s", gitlabEvent)
+			}
+
+			if processError != nil {
+				log.Warnf("processing event : %s failed with : %v", gitlabEvent, processError)
+			}
+
+			return gitlab_activity.NewGitlabActivityOK()
+		})
+	api.AddMiddlewareFor("POST", "/gitlab/activity", tokenCheckMiddleware(webhookSecret))
+}
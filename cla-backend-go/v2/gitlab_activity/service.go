@@ -0,0 +1,516 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package gitlab_activity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/swag"
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gitlab"
+	"github.com/communitybridge/easycla/cla-backend-go/gitlab_organizations"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// CommitStatusName is the name of the commit status reported on the merge requests
+const CommitStatusName = "EasyCLA"
+
+// maxStatusDescriptionLength is the maximum length of the commit status description we send to gitlab
+const maxStatusDescriptionLength = 140
+
+// ErrAutoEnabledOff is returned when the gitlab group of the project doesn't have the auto enabled flag set
+var ErrAutoEnabledOff = errors.New("autoEnabled is off for the gitlab group")
+
+// Service is responsible for handling the gitlab activity events
+type Service interface {
+	ProcessProjectEvent(ctx context.Context, event *gitlab.ProjectEvent) error
+	ProcessMergeRequestEvent(ctx context.Context, event *gitlab.MergeRequestEvent) error
+}
+
+type eventHandlerService struct {
+	repositoriesRepo repositories.Repository
+	gitlabGroupRepo  gitlab_organizations.RepositoryInterface
+	claGroupRepo     projects_cla_groups.Repository
+	eventService     events.Service
+	gitlabClient     gitlab.Client
+	claChecker       CLAChecker
+	signURL          string
+}
+
+// NewService creates a new instance of the GitLab Event Handler Service
+func NewService(repositoriesRepo repositories.Repository,
+	gitlabGroupRepo gitlab_organizations.RepositoryInterface,
+	claGroupRepo projects_cla_groups.Repository,
+	eventService events.Service,
+	gitlabClient gitlab.Client,
+	claChecker CLAChecker,
+	signURL string) Service {
+	return &eventHandlerService{
+		repositoriesRepo: repositoriesRepo,
+		gitlabGroupRepo:  gitlabGroupRepo,
+		claGroupRepo:     claGroupRepo,
+		eventService:     eventService,
+		gitlabClient:     gitlabClient,
+		claChecker:       claChecker,
+		signURL:          signURL,
+	}
+}
+
+// ProcessProjectEvent handles the gitlab system hook project events - the GitLab equivalent of the GitHub repository
+// events
+func (s *eventHandlerService) ProcessProjectEvent(ctx context.Context, event *gitlab.ProjectEvent) error {
+	f := logrus.Fields{
+		"functionName":   "v2.gitlab_activity.service.ProcessProjectEvent",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+	}
+	if event == nil || event.EventName == "" {
+		return fmt.Errorf("no event name found in event payload")
+	}
+	if event.ProjectID == 0 {
+		return fmt.Errorf("missing project id")
+	}
+	if event.PathWithNamespace == "" {
+		return fmt.Errorf("missing project path")
+	}
+
+	log.WithFields(f).Debugf("ProcessProjectEvent called for event : %s for project : %s", event.EventName, event.PathWithNamespace)
+	switch event.EventName {
+	case gitlab.ProjectCreate:
+		return s.handleProjectCreated(ctx, event)
+	case gitlab.ProjectRename, gitlab.ProjectUpdate:
+		return s.handleProjectRenamed(ctx, event)
+	case gitlab.ProjectTransfer:
+		return s.handleProjectTransferred(ctx, event)
+	case gitlab.ProjectDestroy:
+		return s.handleProjectDestroyed(ctx, event)
+	default:
+		log.WithFields(f).Warnf("no handler for event : %s", event.EventName)
+	}
+
+	return nil
+}
+
+// handleProjectCreated adds the new project to the CLA Group of its gitlab group when the group has auto enabled set
+func (s *eventHandlerService) handleProjectCreated(ctx context.Context, event *gitlab.ProjectEvent) error {
+	f := logrus.Fields{
+		"functionName":   "v2.gitlab_activity.service.handleProjectCreated",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"projectPath":    event.PathWithNamespace,
+	}
+
+	repoModel, err := s.createAutoEnabledRepository(ctx, event)
+	if err != nil {
+		if errors.Is(err, ErrAutoEnabledOff) || errors.Is(err, gitlab_organizations.ErrGroupDoesNotExist) {
+			log.WithFields(f).Warnf("autoEnable is off for this project : %s can't continue", event.PathWithNamespace)
+			return nil
+		}
+		return err
+	}
+
+	log.WithFields(f).Debugf("sending RepositoryAdded Event for project %s", event.PathWithNamespace)
+	s.eventService.LogEventWithContext(ctx, &events.LogEventArgs{
+		EventType: events.RepositoryAdded,
+		ProjectID: repoModel.RepositoryProjectID,
+		UserID:    eventUser(event),
+		EventData: &events.RepositoryAddedEventData{
+			RepositoryName: event.PathWithNamespace,
+		},
+	})
+
+	return nil
+}
+
+// createAutoEnabledRepository adds the repository record of the project to the auto enabled CLA Group of the owning
+// gitlab group
+func (s *eventHandlerService) createAutoEnabledRepository(ctx context.Context, event *gitlab.ProjectEvent) (*models.GithubRepository, error) {
+	f := logrus.Fields{
+		"functionName":   "v2.gitlab_activity.service.createAutoEnabledRepository",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"projectPath":    event.PathWithNamespace,
+	}
+
+	group, err := s.findGroup(ctx, event.PathWithNamespace)
+	if err != nil {
+		return nil, err
+	}
+	if !group.AutoEnabled || group.AutoEnabledClaGroupID == "" {
+		log.WithFields(f).Warnf("skipping adding the project, autoEnabled flag is off for group : %s", group.GroupFullPath)
+		return nil, ErrAutoEnabledOff
+	}
+
+	claGroupModel, err := s.claGroupRepo.GetCLAGroup(ctx, group.AutoEnabledClaGroupID)
+	if err != nil {
+		log.WithFields(f).Warnf("fetching the cla group for cla group id : %s failed : %v", group.AutoEnabledClaGroupID, err)
+		return nil, err
+	}
+
+	projectSFID := claGroupModel.ProjectSFID
+	if projectSFID == "" {
+		projectSFID = group.ProjectSFID
+	}
+
+	return s.repositoriesRepo.AddGithubRepository(ctx, claGroupModel.ProjectExternalID, projectSFID, &models.GithubRepositoryInput{
+		RepositoryProjectID:        swag.String(group.AutoEnabledClaGroupID),
+		RepositoryName:             swag.String(event.PathWithNamespace),
+		RepositoryType:             swag.String(utils.GitLabType),
+		RepositoryURL:              swag.String(projectURL(group, event.PathWithNamespace)),
+		RepositoryOrganizationName: swag.String(group.GroupFullPath),
+		RepositoryExternalID:       swag.String(strconv.FormatInt(event.ProjectID, 10)),
+	})
+}
+
+// handleProjectRenamed renames the repository in our records when the project was renamed or its path was updated
+func (s *eventHandlerService) handleProjectRenamed(ctx context.Context, event *gitlab.ProjectEvent) error {
+	f := logrus.Fields{
+		"functionName":   "v2.gitlab_activity.service.handleProjectRenamed",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"projectPath":    event.PathWithNamespace,
+	}
+
+	repoModel, err := s.getRepository(ctx, event)
+	if err != nil || repoModel == nil {
+		return err
+	}
+	if repoModel.RepositoryName == event.PathWithNamespace {
+		log.WithFields(f).Debugf("project path unchanged, nothing to do")
+		return nil
+	}
+
+	log.WithFields(f).Infof("renaming GitLab Repository from : %s to : %s", repoModel.RepositoryName, event.PathWithNamespace)
+	if _, err := s.repositoriesRepo.UpdateGithubRepository(ctx, repoModel.RepositoryID, &models.GithubRepositoryInput{
+		RepositoryName: swag.String(event.PathWithNamespace),
+		RepositoryURL:  swag.String(replacePath(repoModel.RepositoryURL, repoModel.RepositoryName, event.PathWithNamespace)),
+		Note:           "repository was renamed externally",
+	}); err != nil {
+		log.WithFields(f).Warnf("renaming project : %s failed : %v", event.PathWithNamespace, err)
+		return err
+	}
+
+	s.eventService.LogEventWithContext(ctx, &events.LogEventArgs{
+		EventType: events.RepositoryRenamed,
+		ProjectID: repoModel.RepositoryProjectID,
+		UserID:    eventUser(event),
+		EventData: &events.RepositoryRenamedEventData{
+			NewRepositoryName: event.PathWithNamespace,
+			OldRepositoryName: repoModel.RepositoryName,
+		},
+	})
+
+	return nil
+}
+
+// handleProjectTransferred moves the repository to the new gitlab group when the group is part of the same CLA Group,
+// otherwise the repository is disabled
+func (s *eventHandlerService) handleProjectTransferred(ctx context.Context, event *gitlab.ProjectEvent) error {
+	f := logrus.Fields{
+		"functionName":   "v2.gitlab_activity.service.handleProjectTransferred",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"projectPath":    event.PathWithNamespace,
+		"oldProjectPath": event.OldPathWithNamespace,
+	}
+
+	repoModel, err := s.getRepository(ctx, event)
+	if err != nil || repoModel == nil {
+		return err
+	}
+
+	oldGroupName := repoModel.RepositoryOrganizationName
+	newGroup, err := s.findGroup(ctx, event.PathWithNamespace)
+	if err != nil && !errors.Is(err, gitlab_organizations.ErrGroupDoesNotExist) {
+		return err
+	}
+
+	if newGroup == nil || (newGroup.AutoEnabledClaGroupID != repoModel.RepositoryProjectID && newGroup.ProjectSFID != repoModel.ProjectSFID) {
+		log.WithFields(f).Infof("project moved outside of the groups of the CLA Group, disabling repo : %s", repoModel.RepositoryID)
+		return s.disableRepository(ctx, event, repoModel)
+	}
+
+	log.WithFields(f).Infof("running transfer for project : %s from GitLab group : %s to GitLab group : %s", event.PathWithNamespace, oldGroupName, newGroup.GroupFullPath)
+	if _, err := s.repositoriesRepo.UpdateGithubRepository(ctx, repoModel.RepositoryID, &models.GithubRepositoryInput{
+		RepositoryName:             swag.String(event.PathWithNamespace),
+		RepositoryOrganizationName: swag.String(newGroup.GroupFullPath),
+		RepositoryURL:              swag.String(projectURL(newGroup, event.PathWithNamespace)),
+		Note:                       fmt.Sprintf("repository was transferred from group : %s to group : %s", oldGroupName, newGroup.GroupFullPath),
+	}); err != nil {
+		log.WithFields(f).Warnf("transferring project : %s failed : %v", event.PathWithNamespace, err)
+		return err
+	}
+
+	s.eventService.LogEventWithContext(ctx, &events.LogEventArgs{
+		EventType: events.RepositoryTransferred,
+		ProjectID: repoModel.RepositoryProjectID,
+		UserID:    eventUser(event),
+		EventData: &events.RepositoryTransferredEventData{
+			RepositoryName:   event.PathWithNamespace,
+			OldGithubOrgName: oldGroupName,
+			NewGithubOrgName: newGroup.GroupFullPath,
+		},
+	})
+
+	return nil
+}
+
+// handleProjectDestroyed disables the repository when the project was deleted
+func (s *eventHandlerService) handleProjectDestroyed(ctx context.Context, event *gitlab.ProjectEvent) error {
+	repoModel, err := s.getRepository(ctx, event)
+	if err != nil || repoModel == nil {
+		return err
+	}
+	return s.disableRepository(ctx, event, repoModel)
+}
+
+func (s *eventHandlerService) disableRepository(ctx context.Context, event *gitlab.ProjectEvent, repoModel *models.GithubRepository) error {
+	f := logrus.Fields{
+		"functionName":   "v2.gitlab_activity.service.disableRepository",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"repositoryID":   repoModel.RepositoryID,
+	}
+
+	log.WithFields(f).Infof("disabling repo : %s", repoModel.RepositoryID)
+	if err := s.repositoriesRepo.DisableRepository(ctx, repoModel.RepositoryID); err != nil {
+		log.WithFields(f).Warnf("disabling repo : %s failed : %v", repoModel.RepositoryName, err)
+		return err
+	}
+
+	s.eventService.LogEventWithContext(ctx, &events.LogEventArgs{
+		EventType: events.RepositoryDisabled,
+		ProjectID: repoModel.RepositoryProjectID,
+		UserID:    eventUser(event),
+		EventData: &events.RepositoryDisabledEventData{
+			RepositoryName: repoModel.RepositoryName,
+		},
+	})
+
+	return nil
+}
+
+// getRepository returns the enabled repository record of the project, nil if the project is not part of any CLA Group
+func (s *eventHandlerService) getRepository(ctx context.Context, event *gitlab.ProjectEvent) (*models.GithubRepository, error) {
+	f := logrus.Fields{
+		"functionName":   "v2.gitlab_activity.service.getRepository",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"projectPath":    event.PathWithNamespace,
+	}
+
+	repositoryExternalID := strconv.FormatInt(event.ProjectID, 10)
+	repoModel, err := s.repositoriesRepo.GetRepositoryByExternalID(ctx, utils.GitLabType, repositoryExternalID, true)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRepositoryDoesNotExist) {
+			log.WithFields(f).Warnf("event for non existing local repo : %s, nothing to do", event.PathWithNamespace)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("fetching the repo : %s by external id : %s failed : %v", event.PathWithNamespace, repositoryExternalID, err)
+	}
+	return repoModel, nil
+}
+
+// findGroup returns the closest enabled gitlab group registered in cla for the project path, walking up the sub-groups
+func (s *eventHandlerService) findGroup(ctx context.Context, projectPath string) (*gitlab_organizations.GitLabGroup, error) {
+	parts := strings.Split(projectPath, "/")
+	for i := len(parts) - 1; i > 0; i-- {
+		group, err := s.gitlabGroupRepo.GetGitLabGroup(ctx, strings.Join(parts[:i], "/"))
+		if err != nil {
+			if errors.Is(err, gitlab_organizations.ErrGroupDoesNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		if group.Enabled {
+			return group, nil
+		}
+	}
+	return nil, gitlab_organizations.ErrGroupDoesNotExist
+}
+
+// ProcessMergeRequestEvent checks the CLA status of the commit authors of the merge request and reports it as a commit
+// status of the head commit
+func (s *eventHandlerService) ProcessMergeRequestEvent(ctx context.Context, event *gitlab.MergeRequestEvent) error {
+	f := logrus.Fields{
+		"functionName":   "v2.gitlab_activity.service.ProcessMergeRequestEvent",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+	}
+	if event == nil || event.ObjectAttributes == nil {
+		return fmt.Errorf("missing merge request object in event payload")
+	}
+	mr := event.ObjectAttributes
+
+	switch mr.Action {
+	case gitlab.MergeRequestOpen, gitlab.MergeRequestReopen, gitlab.MergeRequestUpdate:
+	default:
+		log.WithFields(f).Debugf("no handler for merge request action : %s", mr.Action)
+		return nil
+	}
+
+	targetProjectID := mr.TargetProjectID
+	if targetProjectID == 0 && event.Project != nil {
+		targetProjectID = event.Project.ID
+	}
+	if targetProjectID == 0 || mr.IID == 0 {
+		return fmt.Errorf("missing project id or merge request iid")
+	}
+	f["projectID"] = targetProjectID
+	f["mergeRequestIID"] = mr.IID
+
+	repoModel, err := s.repositoriesRepo.GetRepositoryByExternalID(ctx, utils.GitLabType, strconv.FormatInt(targetProjectID, 10), true)
+	if err != nil {
+		if errors.Is(err, repositories.ErrRepositoryDoesNotExist) {
+			log.WithFields(f).Debugf("merge request for a project which is not part of a CLA Group, nothing to do")
+			return nil
+		}
+		return err
+	}
+
+	commits, err := s.gitlabClient.ListMergeRequestCommits(ctx, targetProjectID, mr.IID)
+	if err != nil {
+		log.WithFields(f).Warnf("listing the merge request commits failed : %v", err)
+		return err
+	}
+
+	headSHA := ""
+	if mr.LastCommit != nil {
+		headSHA = mr.LastCommit.ID
+	}
+	if headSHA == "" && len(commits) > 0 {
+		// gitlab returns the merge request commits newest first
+		headSHA = commits[0].ID
+	}
+	if headSHA == "" {
+		return fmt.Errorf("unable to determine the head commit of the merge request")
+	}
+
+	missing, err := s.missingAuthors(ctx, repoModel.RepositoryProjectID, commits)
+	if err != nil {
+		return err
+	}
+
+	status := &gitlab.CommitStatusOptions{
+		State:       gitlab.CommitStatusSuccess,
+		Name:        CommitStatusName,
+		TargetURL:   s.signTargetURL(repoModel, targetProjectID, mr.IID),
+		Description: "All commit authors have signed the CLA.",
+	}
+	if len(missing) > 0 {
+		status.State = gitlab.CommitStatusFailed
+		status.Description = truncate(fmt.Sprintf("Missing CLA authorization for: %s", strings.Join(missing, ", ")), maxStatusDescriptionLength)
+	}
+
+	statusProjectID := mr.SourceProjectID
+	if statusProjectID == 0 {
+		statusProjectID = targetProjectID
+	}
+	log.WithFields(f).Debugf("setting commit status : %s on commit : %s", status.State, headSHA)
+	if _, err := s.gitlabClient.SetCommitStatus(ctx, statusProjectID, headSHA, status); err != nil {
+		log.WithFields(f).Warnf("setting the commit status failed : %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// missingAuthors returns the sorted list of the commit author emails which are not authorized by the CLA Group. The
+// commit author email is only used to find the GitLab account owning it, a commit without a GitLab account is not
+// authorized.
+func (s *eventHandlerService) missingAuthors(ctx context.Context, claGroupID string, commits []*gitlab.Commit) ([]string, error) {
+	f := logrus.Fields{
+		"functionName":   "v2.gitlab_activity.service.missingAuthors",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+	}
+
+	checked := map[string]bool{}
+	var missing []string
+	for _, commit := range commits {
+		email := strings.ToLower(strings.TrimSpace(commit.AuthorEmail))
+		if _, ok := checked[email]; ok {
+			continue
+		}
+		if email == "" {
+			checked[email] = false
+			missing = append(missing, fmt.Sprintf("%s (missing author email)", commit.ShortID))
+			continue
+		}
+		author, err := s.gitlabClient.FindUserByEmail(ctx, email)
+		if err != nil {
+			log.WithFields(f).Warnf("looking up the GitLab account of the commit author failed : %v", err)
+			return nil, err
+		}
+		if author == nil {
+			checked[email] = false
+			missing = append(missing, fmt.Sprintf("%s (no GitLab account)", email))
+			continue
+		}
+		signed, err := s.claChecker.HasSignedCLA(ctx, claGroupID, author)
+		if err != nil {
+			return nil, err
+		}
+		checked[email] = signed
+		if !signed {
+			missing = append(missing, email)
+		}
+	}
+	sort.Strings(missing)
+	return missing, nil
+}
+
+// signTargetURL returns the link of the commit status pointing the contributors to the CLA signing flow
+func (s *eventHandlerService) signTargetURL(repoModel *models.GithubRepository, projectID, mergeRequestIID int64) string {
+	if s.signURL == "" {
+		return ""
+	}
+	query := url.Values{}
+	query.Set("repository_id", repoModel.RepositoryID)
+	query.Set("project_id", strconv.FormatInt(projectID, 10))
+	query.Set("merge_request_iid", strconv.FormatInt(mergeRequestIID, 10))
+	separator := "?"
+	if strings.Contains(s.signURL, "?") {
+		separator = "&"
+	}
+	return s.signURL + separator + query.Encode()
+}
+
+// projectURL returns the web url of the project based on the web url of the gitlab group
+func projectURL(group *gitlab_organizations.GitLabGroup, projectPath string) string {
+	if group.GroupURL == "" {
+		return "https://gitlab.com/" + projectPath
+	}
+	return replacePath(group.GroupURL, group.GroupFullPath, projectPath)
+}
+
+// replacePath replaces the path suffix of the web url
+func replacePath(webURL, oldPath, newPath string) string {
+	if strings.HasSuffix(webURL, "/"+oldPath) {
+		return strings.TrimSuffix(webURL, oldPath) + newPath
+	}
+	return "https://gitlab.com/" + newPath
+}
+
+// eventUser returns the user we record the event for, system hooks only contain the owner of the project
+func eventUser(event *gitlab.ProjectEvent) string {
+	if event.OwnerEmail != "" {
+		return event.OwnerEmail
+	}
+	if event.OwnerName != "" {
+		return event.OwnerName
+	}
+	return "gitlab"
+}
+
+func truncate(value string, maxLength int) string {
+	if len(value) <= maxLength {
+		return value
+	}
+	return value[:maxLength-3] + "..."
+}
@@ -0,0 +1,251 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package gitlab_activity
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gitlab"
+	"github.com/communitybridge/easycla/cla-backend-go/gitlab_organizations"
+	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories/mock"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/go-openapi/swag"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubCLAGroupRepo struct {
+	projects_cla_groups.Repository
+	claGroup *projects_cla_groups.ProjectClaGroup
+}
+
+func (r stubCLAGroupRepo) GetCLAGroup(ctx context.Context, claGroupID string) (*projects_cla_groups.ProjectClaGroup, error) {
+	return r.claGroup, nil
+}
+
+// stubCLAChecker returns the CLA status of the GitLab accounts by username
+type stubCLAChecker map[string]bool
+
+func (c stubCLAChecker) HasSignedCLA(ctx context.Context, claGroupID string, author *gitlab.User) (bool, error) {
+	return c[author.Username], nil
+}
+
+// fakeGitLab is a minimal GitLab API serving the merge request commits and the accounts, and recording the commit
+// statuses
+type fakeGitLab struct {
+	commits  []*gitlab.Commit
+	users    []*gitlab.User
+	statuses map[string]*gitlab.CommitStatusOptions
+}
+
+func (g *fakeGitLab) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("PRIVATE-TOKEN") != "token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/42/merge_requests/7/commits":
+		_ = json.NewEncoder(w).Encode(g.commits)
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v4/users":
+		var users []*gitlab.User
+		for _, user := range g.users {
+			if strings.EqualFold(user.Email, r.URL.Query().Get("search")) {
+				users = append(users, user)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(users)
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v4/projects/42/statuses/abc123":
+		var options gitlab.CommitStatusOptions
+		_ = json.NewDecoder(r.Body).Decode(&options)
+		g.statuses[r.URL.Path] = &options
+		_ = json.NewEncoder(w).Encode(gitlab.CommitStatus{ID: 1, SHA: "abc123", Status: options.State, Name: options.Name})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"404 Not Found"}`))
+	}
+}
+
+func mergeRequestEvent(action string) *gitlab.MergeRequestEvent {
+	return &gitlab.MergeRequestEvent{
+		ObjectKind: "merge_request",
+		ObjectAttributes: &gitlab.MergeRequestAttribute{
+			IID:             7,
+			Action:          action,
+			SourceProjectID: 42,
+			TargetProjectID: 42,
+			LastCommit:      &gitlab.MergeRequestCommit{ID: "abc123"},
+		},
+	}
+}
+
+func TestProcessMergeRequestEventSetsCommitStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fake := &fakeGitLab{
+		commits: []*gitlab.Commit{
+			{ID: "abc123", ShortID: "abc123", AuthorEmail: "signed@example.org"},
+			{ID: "def456", ShortID: "def456", AuthorEmail: "Unsigned@example.org"},
+			{ID: "fed789", ShortID: "fed789", AuthorEmail: "signed@example.org"},
+			{ID: "aaa000", ShortID: "aaa000", AuthorEmail: "ghost@example.org"},
+		},
+		users: []*gitlab.User{
+			{ID: 1, Username: "signed", Email: "signed@example.org"},
+			{ID: 2, Username: "unsigned", Email: "unsigned@example.org"},
+		},
+		statuses: map[string]*gitlab.CommitStatusOptions{},
+	}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	repositoriesRepo := mock.NewMockRepository(ctrl)
+	repositoriesRepo.EXPECT().
+		GetRepositoryByExternalID(gomock.Any(), utils.GitLabType, "42", true).
+		Return(&models.GithubRepository{RepositoryID: "repo-1", RepositoryProjectID: "cla-group-1"}, nil).
+		Times(3)
+
+	checker := stubCLAChecker{"signed": true}
+	service := NewService(repositoriesRepo, gitlab_organizations.NewMemoryRepository(), nil, nil,
+		gitlab.NewClient(server.URL+"/api/v4", "token", nil), checker, "https://cla.example.org/gitlab/sign")
+
+	assert.Nil(t, service.ProcessMergeRequestEvent(context.Background(), mergeRequestEvent(gitlab.MergeRequestOpen)))
+	status := fake.statuses["/api/v4/projects/42/statuses/abc123"]
+	if assert.NotNil(t, status) {
+		assert.Equal(t, gitlab.CommitStatusFailed, status.State)
+		assert.Equal(t, CommitStatusName, status.Name)
+		assert.Equal(t, "Missing CLA authorization for: ghost@example.org (no GitLab account), unsigned@example.org", status.Description)
+		assert.Contains(t, status.TargetURL, "https://cla.example.org/gitlab/sign?")
+		assert.Contains(t, status.TargetURL, "merge_request_iid=7")
+	}
+
+	// the contributor signs and pushes an update, the commit without a GitLab account is still not authorized
+	checker["unsigned"] = true
+	assert.Nil(t, service.ProcessMergeRequestEvent(context.Background(), mergeRequestEvent(gitlab.MergeRequestUpdate)))
+	assert.Equal(t, "Missing CLA authorization for: ghost@example.org (no GitLab account)", fake.statuses["/api/v4/projects/42/statuses/abc123"].Description)
+
+	// the commit is rewritten with the email of the contributor account
+	fake.commits[3].AuthorEmail = "unsigned@example.org"
+	assert.Nil(t, service.ProcessMergeRequestEvent(context.Background(), mergeRequestEvent(gitlab.MergeRequestUpdate)))
+	assert.Equal(t, gitlab.CommitStatusSuccess, fake.statuses["/api/v4/projects/42/statuses/abc123"].State)
+
+	// closed merge requests are ignored
+	assert.Nil(t, service.ProcessMergeRequestEvent(context.Background(), mergeRequestEvent("close")))
+}
+
+func TestProcessMergeRequestEventUnknownProject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repositoriesRepo := mock.NewMockRepository(ctrl)
+	repositoriesRepo.EXPECT().
+		GetRepositoryByExternalID(gomock.Any(), utils.GitLabType, "42", true).
+		Return(nil, repositories.ErrRepositoryDoesNotExist)
+
+	// no gitlab calls are expected - the client points to a closed server
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	service := NewService(repositoriesRepo, gitlab_organizations.NewMemoryRepository(), nil, nil,
+		gitlab.NewClient(server.URL, "token", nil), stubCLAChecker{}, "")
+
+	assert.Nil(t, service.ProcessMergeRequestEvent(context.Background(), mergeRequestEvent(gitlab.MergeRequestOpen)))
+}
+
+func TestProcessProjectEventCreateInAutoEnabledSubGroup(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	groupsRepo := gitlab_organizations.NewMemoryRepository()
+	_, err := groupsRepo.AddGitLabGroup(ctx, &gitlab_organizations.GitLabGroup{
+		GroupID:               10,
+		GroupFullPath:         "Acme",
+		GroupURL:              "https://gitlab.example.org/Acme",
+		ProjectSFID:           "project-sfid",
+		AutoEnabled:           true,
+		AutoEnabledClaGroupID: "cla-group-1",
+	})
+	assert.Nil(t, err)
+
+	repositoriesRepo := mock.NewMockRepository(ctrl)
+	repositoriesRepo.EXPECT().
+		AddGithubRepository(gomock.Any(), "external-1", "project-sfid", &models.GithubRepositoryInput{
+			RepositoryProjectID:        swag.String("cla-group-1"),
+			RepositoryName:             swag.String("acme/tools/cli"),
+			RepositoryType:             swag.String(utils.GitLabType),
+			RepositoryURL:              swag.String("https://gitlab.example.org/acme/tools/cli"),
+			RepositoryOrganizationName: swag.String("Acme"),
+			RepositoryExternalID:       swag.String("99"),
+		}).
+		Return(&models.GithubRepository{RepositoryID: "repo-1", RepositoryProjectID: "cla-group-1"}, nil)
+
+	eventsService := events.NewMockService(ctrl)
+	eventsService.EXPECT().
+		LogEventWithContext(gomock.Any(), &events.LogEventArgs{
+			EventType: events.RepositoryAdded,
+			ProjectID: "cla-group-1",
+			UserID:    "owner@example.org",
+			EventData: &events.RepositoryAddedEventData{RepositoryName: "acme/tools/cli"},
+		})
+
+	claGroupRepo := stubCLAGroupRepo{claGroup: &projects_cla_groups.ProjectClaGroup{ProjectExternalID: "external-1"}}
+	service := NewService(repositoriesRepo, groupsRepo, claGroupRepo, eventsService, nil, stubCLAChecker{}, "")
+
+	assert.Nil(t, service.ProcessProjectEvent(ctx, &gitlab.ProjectEvent{
+		EventName:         gitlab.ProjectCreate,
+		PathWithNamespace: "acme/tools/cli",
+		ProjectID:         99,
+		OwnerEmail:        "owner@example.org",
+	}))
+
+	// projects created outside of the registered groups are skipped
+	assert.Nil(t, service.ProcessProjectEvent(ctx, &gitlab.ProjectEvent{
+		EventName:         gitlab.ProjectCreate,
+		PathWithNamespace: "other/cli",
+		ProjectID:         100,
+	}))
+}
+
+func TestProcessProjectEventTransferOutOfCLAGroupDisablesRepository(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	repositoriesRepo := mock.NewMockRepository(ctrl)
+	repositoriesRepo.EXPECT().
+		GetRepositoryByExternalID(gomock.Any(), utils.GitLabType, "99", true).
+		Return(&models.GithubRepository{
+			RepositoryID:               "repo-1",
+			RepositoryName:             "acme/cli",
+			RepositoryOrganizationName: "acme",
+			RepositoryProjectID:        "cla-group-1",
+			ProjectSFID:                "project-sfid",
+		}, nil)
+	repositoriesRepo.EXPECT().DisableRepository(gomock.Any(), "repo-1").Return(nil)
+
+	eventsService := events.NewMockService(ctrl)
+	eventsService.EXPECT().
+		LogEventWithContext(gomock.Any(), &events.LogEventArgs{
+			EventType: events.RepositoryDisabled,
+			ProjectID: "cla-group-1",
+			UserID:    "gitlab",
+			EventData: &events.RepositoryDisabledEventData{RepositoryName: "acme/cli"},
+		})
+
+	service := NewService(repositoriesRepo, gitlab_organizations.NewMemoryRepository(), nil, eventsService, nil, stubCLAChecker{}, "")
+	assert.Nil(t, service.ProcessProjectEvent(ctx, &gitlab.ProjectEvent{
+		EventName:            gitlab.ProjectTransfer,
+		PathWithNamespace:    "personal/cli",
+		OldPathWithNamespace: "acme/cli",
+		ProjectID:            99,
+	}))
+}
@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
 
@@ -23,6 +24,7 @@ import (
 	"github.com/communitybridge/easycla/cla-backend-go/v2/organization-service/client"
 	"github.com/communitybridge/easycla/cla-backend-go/v2/organization-service/client/organizations"
 	"github.com/communitybridge/easycla/cla-backend-go/v2/organization-service/models"
+	platform_client "github.com/communitybridge/easycla/cla-backend-go/v2/platform-client"
 	runtimeClient "github.com/go-openapi/runtime/client"
 	"github.com/go-openapi/strfmt"
 )
@@ -44,12 +46,9 @@ var (
 // InitClient initializes the user_service client
 func InitClient(APIGwURL string, eventService events.Service) {
 	APIGwURL = strings.ReplaceAll(APIGwURL, "https://", "")
+	dependency := platform_client.NewDependency(platform_client.DefaultConfig("organization-service", 15*time.Second))
 	organizationServiceClient = &Client{
-		cl: client.NewHTTPClientWithConfig(strfmt.Default, &client.TransportConfig{
-			Host:     APIGwURL,
-			BasePath: "organization-service",
-			Schemes:  []string{"https"},
-		}),
+		cl: client.New(dependency.SwaggerTransport(APIGwURL, "organization-service"), strfmt.Default),
 	}
 	v1EventService = eventService
 }
@@ -0,0 +1,126 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package platform_client
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without calling the dependency while its circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open - the platform dependency is unavailable")
+
+// circuit breaker states
+const (
+	StateClosed   = "closed"
+	StateOpen     = "open"
+	StateHalfOpen = "half-open"
+)
+
+// breaker opens after consecutive failures and rejects the calls for the cool down, then lets a single trial call
+// through - the breaker closes again when the trial call succeeds
+type breaker struct {
+	lock                sync.Mutex
+	failureThreshold    int
+	coolDown            time.Duration
+	now                 func() time.Time
+	state               string
+	consecutiveFailures int
+	openedAt            time.Time
+	trialInFlight       bool
+	lastError           error
+	lastFailureAt       time.Time
+	lastSuccessAt       time.Time
+}
+
+// newBreaker creates a closed breaker
+func newBreaker(failureThreshold int, coolDown time.Duration, now func() time.Time) *breaker {
+	return &breaker{
+		failureThreshold: failureThreshold,
+		coolDown:         coolDown,
+		now:              now,
+		state:            StateClosed,
+	}
+}
+
+// allow returns true when the call may be made, the caller must report the outcome with success or failure
+func (b *breaker) allow() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if b.now().Sub(b.openedAt) < b.coolDown {
+			return false
+		}
+		b.state = StateHalfOpen
+		b.trialInFlight = true
+		return true
+	case StateHalfOpen:
+		// only a single trial call until it completes
+		if b.trialInFlight {
+			return false
+		}
+		b.trialInFlight = true
+		return true
+	default:
+		return true
+	}
+}
+
+// success records a successful call and closes the breaker
+func (b *breaker) success() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.state = StateClosed
+	b.consecutiveFailures = 0
+	b.trialInFlight = false
+	b.lastSuccessAt = b.now()
+}
+
+// failure records a failed call, the breaker opens when the threshold is reached or when the trial call fails
+func (b *breaker) failure(err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.consecutiveFailures++
+	b.lastError = err
+	b.lastFailureAt = b.now()
+	if b.state == StateHalfOpen || b.consecutiveFailures >= b.failureThreshold {
+		b.state = StateOpen
+		b.openedAt = b.now()
+	}
+	b.trialInFlight = false
+}
+
+// release ends the call without an outcome, e.g. when the caller cancelled it
+func (b *breaker) release() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.trialInFlight = false
+}
+
+// status returns the state of the breaker, an open breaker past its cool down is reported as half-open
+func (b *breaker) status(name string) Status {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	status := Status{
+		Name:                name,
+		State:               b.state,
+		ConsecutiveFailures: b.consecutiveFailures,
+		LastFailureAt:       b.lastFailureAt,
+		LastSuccessAt:       b.lastSuccessAt,
+	}
+	if status.State == StateOpen && b.now().Sub(b.openedAt) >= b.coolDown {
+		status.State = StateHalfOpen
+	}
+	if b.lastError != nil {
+		status.LastError = b.lastError.Error()
+	}
+	return status
+}
@@ -0,0 +1,123 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package platform_client
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache is a TTL cache of the lookups of the platform services, safe for concurrent use - the lambda functions keep
+// the entries across the invocations of a warm instance
+type Cache interface {
+	Get(key string) (interface{}, bool)
+	Set(key string, value interface{})
+	Delete(key string)
+	GetOrLoad(key string, load func() (interface{}, error)) (interface{}, error)
+	Len() int
+}
+
+// cacheEntry is a cached value with its expiry
+type cacheEntry struct {
+	value   interface{}
+	expires time.Time
+}
+
+// ttlCache is the Cache implementation
+type ttlCache struct {
+	lock       sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+	entries    map[string]cacheEntry
+}
+
+// NewCache creates a cache keeping the entries for the ttl, up to maxEntries - the expired entries are evicted
+// first when the cache is full, then an arbitrary one
+func NewCache(ttl time.Duration, maxEntries int) Cache {
+	return newCache(ttl, maxEntries, time.Now)
+}
+
+// newCache creates a cache with the clock
+func newCache(ttl time.Duration, maxEntries int, now func() time.Time) *ttlCache {
+	return &ttlCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        now,
+		entries:    map[string]cacheEntry{},
+	}
+}
+
+// Get returns the value of the key when it is cached and not expired
+func (c *ttlCache) Get(key string) (interface{}, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.value, true
+}
+
+// Set caches the value of the key for the ttl
+func (c *ttlCache) Set(key string, value interface{}) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	now := c.now()
+	if _, exists := c.entries[key]; !exists && c.maxEntries > 0 && len(c.entries) >= c.maxEntries {
+		c.evict(now)
+	}
+	c.entries[key] = cacheEntry{value: value, expires: now.Add(c.ttl)}
+}
+
+// evict removes the expired entries, or an arbitrary entry when none has expired - the lock must be held
+func (c *ttlCache) evict(now time.Time) {
+	for key, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+	if len(c.entries) < c.maxEntries {
+		return
+	}
+	for key := range c.entries {
+		delete(c.entries, key)
+		return
+	}
+}
+
+// Delete removes the key, e.g. after the cached resource was updated
+func (c *ttlCache) Delete(key string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.entries, key)
+}
+
+// GetOrLoad returns the cached value of the key or caches the value returned by load, the errors are not cached
+func (c *ttlCache) GetOrLoad(key string, load func() (interface{}, error)) (interface{}, error) {
+	if value, ok := c.Get(key); ok {
+		return value, nil
+	}
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+	c.Set(key, value)
+	return value, nil
+}
+
+// Len returns the number of cached entries, including the expired entries not evicted yet
+func (c *ttlCache) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return len(c.entries)
+}
@@ -0,0 +1,144 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package platform_client
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	runtimeClient "github.com/go-openapi/runtime/client"
+
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
+)
+
+// Config of the calls to a platform dependency
+type Config struct {
+	// Name identifies the dependency in the health checks and the logs, e.g. acs-service
+	Name string
+	// Timeout of a single attempt
+	Timeout time.Duration
+	// MaxRetries is the number of retries of the failed idempotent calls
+	MaxRetries int
+	// InitialBackoff is the wait before the first retry, doubled for each retry up to MaxBackoff - jitter is added
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// FailureThreshold is the number of consecutive failures opening the circuit breaker
+	FailureThreshold int
+	// CoolDown is how long the open circuit breaker rejects the calls before a trial call is let through
+	CoolDown time.Duration
+}
+
+// DefaultConfig returns the config of the dependency with the timeout - 2 retries and a breaker opening after 5
+// consecutive failures for 30 seconds
+func DefaultConfig(name string, timeout time.Duration) Config {
+	return Config{
+		Name:             name,
+		Timeout:          timeout,
+		MaxRetries:       2,
+		InitialBackoff:   100 * time.Millisecond,
+		MaxBackoff:       2 * time.Second,
+		FailureThreshold: 5,
+		CoolDown:         30 * time.Second,
+	}
+}
+
+// Status of a platform dependency reported by the health check
+type Status struct {
+	Name                string
+	State               string
+	ConsecutiveFailures int
+	LastError           string
+	LastFailureAt       time.Time
+	LastSuccessAt       time.Time
+}
+
+// Healthy returns false while the circuit breaker rejects the calls
+func (s Status) Healthy() bool {
+	return s.State != StateOpen
+}
+
+// Dependency is a platform service called by EasyCLA, its calls share the timeouts, retries and circuit breaker
+type Dependency interface {
+	Name() string
+	Transport(base http.RoundTripper) http.RoundTripper
+	SwaggerTransport(host, basePath string) *runtimeClient.Runtime
+	HTTPClient() *http.Client
+	Status() Status
+}
+
+// dependency is the Dependency implementation
+type dependency struct {
+	config  Config
+	breaker *breaker
+	sleep   func(time.Duration) <-chan time.Time
+}
+
+// registry holds the dependencies reported by the health check
+var registry = struct {
+	lock         sync.Mutex
+	dependencies map[string]Dependency
+}{dependencies: map[string]Dependency{}}
+
+// NewDependency creates the dependency and registers it for the health check, a dependency created again with
+// the same name replaces the previous one
+func NewDependency(config Config) Dependency {
+	d := &dependency{
+		config:  config,
+		breaker: newBreaker(config.FailureThreshold, config.CoolDown, time.Now),
+		sleep:   time.After,
+	}
+
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+	registry.dependencies[config.Name] = d
+	return d
+}
+
+// Statuses returns the status of the registered dependencies sorted by name
+func Statuses() []Status {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+
+	statuses := make([]Status, 0, len(registry.dependencies))
+	for _, d := range registry.dependencies {
+		statuses = append(statuses, d.Status())
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// Name returns the name of the dependency
+func (d *dependency) Name() string {
+	return d.config.Name
+}
+
+// Transport returns the round tripper applying the timeouts, retries and circuit breaker of the dependency to the
+// calls made with the base round tripper
+func (d *dependency) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{dependency: d, base: base}
+}
+
+// SwaggerTransport returns the go-swagger client transport of the dependency, each attempt is traced
+func (d *dependency) SwaggerTransport(host, basePath string) *runtimeClient.Runtime {
+	swaggerTransport := telemetry.NewSwaggerTransport(host, basePath, []string{"https"})
+	swaggerTransport.Transport = d.Transport(swaggerTransport.Transport)
+	return swaggerTransport
+}
+
+// HTTPClient returns the client of the manual requests to the dependency, each attempt is traced
+func (d *dependency) HTTPClient() *http.Client {
+	return &http.Client{Transport: d.Transport(telemetry.NewHTTPTransport(nil))}
+}
+
+// Status returns the status of the dependency
+func (d *dependency) Status() Status {
+	return d.breaker.status(d.config.Name)
+}
@@ -0,0 +1,168 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package platform_client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// transport applies the timeouts, retries and circuit breaker of the dependency
+type transport struct {
+	dependency *dependency
+	base       http.RoundTripper
+}
+
+// RoundTrip executes the request, the failed idempotent requests are retried with backoff and jitter
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f := logrus.Fields{
+		"functionName":   "v2.platform-client.transport.RoundTrip",
+		utils.XREQUESTID: req.Context().Value(utils.XREQUESTID),
+		"dependency":     t.dependency.config.Name,
+		"method":         req.Method,
+		"url":            req.URL.Redacted(),
+	}
+
+	maxAttempts := 1
+	if isRetryableRequest(req) {
+		maxAttempts += t.dependency.config.MaxRetries
+	}
+
+	for attempt := 1; ; attempt++ {
+		if !t.dependency.breaker.allow() {
+			log.WithFields(f).Warnf("circuit breaker open - rejecting the call to %s", t.dependency.config.Name)
+			return nil, ErrCircuitOpen
+		}
+
+		resp, err := t.attempt(req, attempt)
+		if req.Context().Err() != nil {
+			// cancelled by the caller, says nothing about the dependency
+			t.dependency.breaker.release()
+			return resp, err
+		}
+		if !isFailure(resp, err) {
+			t.dependency.breaker.success()
+			return resp, err
+		}
+		t.dependency.breaker.failure(failureError(resp, err))
+
+		if attempt >= maxAttempts || !isRetryableFailure(resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			// the connection is reused when the body is drained
+			_, _ = io.Copy(ioutil.Discard, resp.Body) // nolint
+			_ = resp.Body.Close()                     // nolint
+		}
+
+		backoff := t.dependency.backoff(attempt)
+		log.WithFields(f).Debugf("attempt %d of %d failed - retrying in %v", attempt, maxAttempts, backoff)
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-t.dependency.sleep(backoff):
+		}
+	}
+}
+
+// attempt executes a single attempt with the timeout of the dependency, the timeout covers reading the body
+func (t *transport) attempt(req *http.Request, attempt int) (*http.Response, error) {
+	ctx := req.Context()
+	cancel := context.CancelFunc(func() {})
+	if t.dependency.config.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, t.dependency.config.Timeout)
+	}
+
+	attemptReq := req.Clone(ctx)
+	if attempt > 1 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		attemptReq.Body = body
+	}
+
+	resp, err := t.base.RoundTrip(attemptReq)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// backoff returns the wait before the retry following the attempt - half of it is random
+func (d *dependency) backoff(attempt int) time.Duration {
+	backoff := d.config.InitialBackoff
+	for i := 1; i < attempt && backoff < d.config.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > d.config.MaxBackoff {
+		backoff = d.config.MaxBackoff
+	}
+	if backoff <= 1 {
+		return backoff
+	}
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1)) // nolint
+}
+
+// isRetryableRequest returns true for the idempotent methods whose body, if any, can be sent again
+func isRetryableRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// isFailure returns true when the call failed because of the dependency - the client errors are successful calls
+func isFailure(resp *http.Response, err error) bool {
+	return err != nil || resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+}
+
+// isRetryableFailure returns true for the transport errors and the transient status codes
+func isRetryableFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// failureError returns the error recorded by the circuit breaker
+func failureError(resp *http.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("unexpected response status: %s", resp.Status)
+}
+
+// cancelOnClose releases the timeout of the attempt when the body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and releases the timeout
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
@@ -8,11 +8,13 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	platform_client "github.com/communitybridge/easycla/cla-backend-go/v2/platform-client"
 
 	"github.com/go-openapi/runtime"
 
@@ -30,27 +32,28 @@ const (
 	NA  = "N/A"
 )
 
+// projectCacheTTL is how long the project lookups are cached, the updates made through this client are seen
+// immediately
+const projectCacheTTL = 10 * time.Minute
+
 // Client is client for user_service
 type Client struct {
-	cl *client.PMM
+	cl           *client.PMM
+	projectCache platform_client.Cache
 }
 
 var (
 	projectServiceClient *Client
-	// Short term cache - only for the lifetime of this lambda
-	projectServiceModels = make(map[string]*models.ProjectOutputDetailed)
 	apiGWHost            string
 )
 
 // InitClient initializes the user_service client
 func InitClient(APIGwURL string) {
 	apiGWHost = strings.ReplaceAll(APIGwURL, "https://", "")
+	dependency := platform_client.NewDependency(platform_client.DefaultConfig("project-service", 15*time.Second))
 	projectServiceClient = &Client{
-		cl: client.NewHTTPClientWithConfig(strfmt.Default, &client.TransportConfig{
-			Host:     apiGWHost,
-			BasePath: "project-service",
-			Schemes:  []string{"https"},
-		}),
+		cl:           client.New(dependency.SwaggerTransport(apiGWHost, "project-service"), strfmt.Default),
+		projectCache: platform_client.NewCache(projectCacheTTL, 1000),
 	}
 }
 
@@ -69,7 +72,7 @@ func (pmm *Client) getProject(projectSFID string, auth runtime.ClientAuthInfoWri
 	return result.Payload, nil
 }
 
-// GetProject returns project details
+// GetProject returns project details, the projects are cached for projectCacheTTL
 func (pmm *Client) GetProject(projectSFID string) (*models.ProjectOutputDetailed, error) {
 	f := logrus.Fields{
 		"functionName": "v2.project-service.client.GetProject",
@@ -77,33 +80,20 @@ func (pmm *Client) GetProject(projectSFID string) (*models.ProjectOutputDetailed
 		"apiGWHost":    apiGWHost,
 	}
 
-	// Lookup in cache first
-	existingModel, exists := projectServiceModels[projectSFID]
-	if exists {
-		log.WithFields(f).Debugf("cache hit - cache size: %d", len(projectServiceModels))
-		return existingModel, nil
-	}
-	log.WithFields(f).Debugf("cache miss - cache size: %d", len(projectServiceModels))
-
-	tok, err := token.GetToken()
-	if err != nil {
-		return nil, err
-	}
-	clientAuth := runtimeClient.BearerToken(tok)
-
-	// Lookup the project
-	log.WithFields(f).Debugf("cache miss - looking up project in the service for: %s...", projectSFID)
-	projectModel, err := pmm.getProject(projectSFID, clientAuth)
+	projectModel, err := pmm.projectCache.GetOrLoad(projectSFID, func() (interface{}, error) {
+		log.WithFields(f).Debugf("cache miss - looking up project in the service for: %s...", projectSFID)
+		tok, err := token.GetToken()
+		if err != nil {
+			return nil, err
+		}
+		return pmm.getProject(projectSFID, runtimeClient.BearerToken(tok))
+	})
 	if err != nil {
 		log.WithFields(f).WithError(err).Warnf("unable to lookup project in the project service for: %s", projectSFID)
 		return nil, err
 	}
 
-	// Update our cache for next time
-	projectServiceModels[projectSFID] = projectModel
-	log.WithFields(f).Debugf("added project model to cache - cache size: %d", len(projectServiceModels))
-
-	return projectModel, nil
+	return projectModel.(*models.ProjectOutputDetailed), nil
 }
 
 // GetProjectByName returns project details for the associated project name
@@ -141,14 +131,6 @@ func (pmm *Client) GetParentProject(projectSFID string) (string, error) {
 		"apiGWHost":    apiGWHost,
 	}
 
-	// Lookup in cache first
-	existingModel, exists := projectServiceModels[projectSFID]
-	if exists {
-		log.WithFields(f).Debugf("cache hit - cache size: %d", len(projectServiceModels))
-		return utils.StringValue(existingModel.Parent), nil
-	}
-	log.WithFields(f).Debugf("cache miss - cache size: %d", len(projectServiceModels))
-
 	log.WithFields(f).Debug("looking up projectModel in SF by projectSFID")
 	projectModel, err := pmm.GetProject(projectSFID)
 	if err != nil {
@@ -156,13 +138,8 @@ func (pmm *Client) GetParentProject(projectSFID string) (string, error) {
 		return "", err
 	}
 
-	// Update our cache for next time
-	projectServiceModels[projectSFID] = projectModel
-	log.WithFields(f).Debugf("added project model to cache - cache size: %d", len(projectServiceModels))
-
 	// Do they have a parent?
-	if utils.StringValue(projectModel.Parent) == "" || (projectModel.Foundation != nil &&
-		(projectModel.Foundation.Name == utils.TheLinuxFoundation || projectModel.Foundation.Name == utils.LFProjectsLLC)) {
+	if !hasParent(projectModel) {
 		log.WithFields(f).Debugf("no parent for projectSFID or %s or %s is the parent...", utils.TheLinuxFoundation, utils.LFProjectsLLC)
 		return projectSFID, nil
 	}
@@ -179,74 +156,33 @@ func (pmm *Client) GetParentProjectModel(projectSFID string) (*models.ProjectOut
 		"apiGWHost":    apiGWHost,
 	}
 
-	// Lookup in cache first
-	var exists bool
-	var existingModel *models.ProjectOutputDetailed
-	var existingParentModel *models.ProjectOutputDetailed
-
-	// Current project in the cache?
-	existingModel, exists = projectServiceModels[projectSFID]
-	if exists {
-		log.WithFields(f).Debugf("cache hit - cache size: %d", len(projectServiceModels))
-
-		// Parent in the cache?
-		existingParentModel, exists = projectServiceModels[utils.StringValue(existingModel.Parent)]
-		if exists {
-			return existingParentModel, nil
-		}
-
-		// Parent project not in the cache - lookup
-		parentProjectModel, err := pmm.GetProject(utils.StringValue(existingModel.Parent))
-		if err != nil {
-			log.WithFields(f).WithError(err).Warnf("unable to lookup parentProjectModel projectSFID: '%s'", utils.StringValue(existingModel.Parent))
-			return nil, err
-		}
-
-		// Update our cache for next time
-		projectServiceModels[utils.StringValue(existingModel.Parent)] = parentProjectModel
-		log.WithFields(f).Debugf("added project model to cache - cache size: %d", len(projectServiceModels))
-
-		return parentProjectModel, nil
-	}
-
-	log.WithFields(f).Debugf("cache miss - looking up projectModel in projectSFID: %s", projectSFID)
 	projectModel, err := pmm.GetProject(projectSFID)
 	if err != nil {
 		log.WithFields(f).Warnf("unable to lookup projectModel in projectModel service by projectSFID, error: %+v", err)
 		return nil, err
 	}
 
-	// Update our cache for next time
-	projectServiceModels[projectSFID] = projectModel
-	log.WithFields(f).Debugf("added project model to cache - cache size: %d", len(projectServiceModels))
-
 	// Do they have a parent?
-	if utils.StringValue(projectModel.Parent) == "" || (projectModel.Foundation != nil &&
-		(projectModel.Foundation.Name == utils.TheLinuxFoundation || projectModel.Foundation.Name == utils.LFProjectsLLC)) {
+	if !hasParent(projectModel) {
 		log.WithFields(f).Debugf("no parent for projectSFID or %s or %s is the parent...", utils.TheLinuxFoundation, utils.LFProjectsLLC)
 		return nil, nil
 	}
 
-	// Parent in the cache?
-	existingParentModel, exists = projectServiceModels[utils.StringValue(projectModel.Parent)]
-	if exists {
-		return existingParentModel, nil
-	}
-
-	// Parent project not in the cache - lookup
 	parentProjectModel, err := pmm.GetProject(utils.StringValue(projectModel.Parent))
 	if err != nil {
 		log.WithFields(f).WithError(err).Warnf("unable to lookup parentProjectModel projectSFID: '%s'", utils.StringValue(projectModel.Parent))
 		return nil, err
 	}
 
-	// Update our cache for next time
-	projectServiceModels[utils.StringValue(existingModel.Parent)] = parentProjectModel
-	log.WithFields(f).Debugf("added project model to cache - cache size: %d", len(projectServiceModels))
-
 	return parentProjectModel, nil
 }
 
+// hasParent returns false for the root projects and the projects under The Linux Foundation or LF Projects
+func hasParent(projectModel *models.ProjectOutputDetailed) bool {
+	return utils.StringValue(projectModel.Parent) != "" && (projectModel.Foundation == nil ||
+		(projectModel.Foundation.Name != utils.TheLinuxFoundation && projectModel.Foundation.Name != utils.LFProjectsLLC))
+}
+
 // IsTheLinuxFoundation returns true if the specified project SFID is the The Linux Foundation project
 func (pmm *Client) IsTheLinuxFoundation(projectSFID string) (bool, error) {
 	f := logrus.Fields{
@@ -372,6 +308,8 @@ func (pmm *Client) updateEnabledServices(ctx context.Context, projectSFID string
 	}
 
 	_, err := pmm.cl.Project.UpdateProject(params, clientAuth) //nolint
+	// the cached project has the previous enabled services
+	pmm.projectCache.Delete(projectSFID)
 	if err != nil {
 		log.WithFields(f).WithError(err).Warnf("problem updating project enabled services")
 	}
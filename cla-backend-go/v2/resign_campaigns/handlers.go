@@ -0,0 +1,246 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package resign_campaigns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations/resign_campaigns"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	campaigns "github.com/communitybridge/easycla/cla-backend-go/resign_campaigns"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// Configure setups handlers on api with service
+func Configure(api *operations.EasyclaAPI, service campaigns.Service, claGroupService project.Service) {
+	// isAuthorized checks the user is an admin of the project tree of the CLA group
+	isAuthorized := func(ctx context.Context, authUser *auth.User, claGroupID string) (bool, error) {
+		claGroupModel, err := claGroupService.GetCLAGroupByID(ctx, claGroupID)
+		if err != nil {
+			return false, err
+		}
+		if claGroupModel == nil {
+			return false, fmt.Errorf("cla group %s does not exist", claGroupID)
+		}
+		return utils.IsUserAuthorizedForProjectTree(ctx, authUser, claGroupModel.ProjectExternalID, utils.ALLOW_ADMIN_SCOPE), nil
+	}
+
+	api.ResignCampaignsListClaGroupResignCampaignsHandler = resign_campaigns.ListClaGroupResignCampaignsHandlerFunc(
+		func(params resign_campaigns.ListClaGroupResignCampaignsParams, authUser *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+			f := logrus.Fields{
+				"functionName":   "v2.resign_campaigns.handlers.ResignCampaignsListClaGroupResignCampaignsHandler",
+				utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+				"authUser":       authUser.UserName,
+				"claGroupID":     params.ClaGroupID,
+			}
+
+			authorized, err := isAuthorized(ctx, authUser, params.ClaGroupID)
+			if err != nil {
+				msg := fmt.Sprintf("unable to load the CLA group: %s", params.ClaGroupID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				return resign_campaigns.NewListClaGroupResignCampaignsNotFound().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseNotFoundWithError(reqID, msg, err))
+			}
+			if !authorized {
+				msg := fmt.Sprintf("user %s does not have access to List Re-sign Campaigns of the CLA Group %s", authUser.UserName, params.ClaGroupID)
+				log.WithFields(f).Debug(msg)
+				return resign_campaigns.NewListClaGroupResignCampaignsForbidden().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseForbidden(reqID, msg))
+			}
+
+			results, err := service.ListCampaigns(ctx, params.ClaGroupID)
+			if err != nil {
+				msg := fmt.Sprintf("unable to list the re-sign campaigns of the CLA group: %s", params.ClaGroupID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				return resign_campaigns.NewListClaGroupResignCampaignsInternalServerError().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+			}
+			return resign_campaigns.NewListClaGroupResignCampaignsOK().WithXRequestID(reqID).WithPayload(toCampaignList(results))
+		})
+
+	api.ResignCampaignsCreateClaGroupResignCampaignHandler = resign_campaigns.CreateClaGroupResignCampaignHandlerFunc(
+		func(params resign_campaigns.CreateClaGroupResignCampaignParams, authUser *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+			f := logrus.Fields{
+				"functionName":   "v2.resign_campaigns.handlers.ResignCampaignsCreateClaGroupResignCampaignHandler",
+				utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+				"authUser":       authUser.UserName,
+				"claGroupID":     params.ClaGroupID,
+			}
+
+			authorized, err := isAuthorized(ctx, authUser, params.ClaGroupID)
+			if err != nil {
+				msg := fmt.Sprintf("unable to load the CLA group: %s", params.ClaGroupID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				return resign_campaigns.NewCreateClaGroupResignCampaignNotFound().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseNotFoundWithError(reqID, msg, err))
+			}
+			if !authorized {
+				msg := fmt.Sprintf("user %s does not have access to Create Re-sign Campaigns of the CLA Group %s", authUser.UserName, params.ClaGroupID)
+				log.WithFields(f).Debug(msg)
+				return resign_campaigns.NewCreateClaGroupResignCampaignForbidden().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseForbidden(reqID, msg))
+			}
+
+			campaign, err := service.CreateCampaign(ctx, &campaigns.CampaignInput{
+				CLAGroupID:         params.ClaGroupID,
+				ClaType:            utils.StringValue(params.Body.ClaType),
+				GracePeriodDays:    int(params.Body.GracePeriodDays),
+				InvalidateOnExpiry: params.Body.InvalidateOnExpiry,
+				CreatedBy:          authUser.UserName,
+			})
+			if err != nil {
+				msg := fmt.Sprintf("unable to create the re-sign campaign of the CLA group: %s", params.ClaGroupID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				if errors.Is(err, campaigns.ErrCampaignAlreadyActive) {
+					return resign_campaigns.NewCreateClaGroupResignCampaignConflict().WithXRequestID(reqID).WithPayload(
+						utils.ErrorResponseConflictWithError(reqID, msg, err))
+				}
+				return resign_campaigns.NewCreateClaGroupResignCampaignBadRequest().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseBadRequestWithError(reqID, msg, err))
+			}
+			return resign_campaigns.NewCreateClaGroupResignCampaignOK().WithXRequestID(reqID).WithPayload(toCampaign(campaign))
+		})
+
+	api.ResignCampaignsGetResignCampaignHandler = resign_campaigns.GetResignCampaignHandlerFunc(
+		func(params resign_campaigns.GetResignCampaignParams, authUser *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+			f := logrus.Fields{
+				"functionName":   "v2.resign_campaigns.handlers.ResignCampaignsGetResignCampaignHandler",
+				utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+				"authUser":       authUser.UserName,
+				"campaignID":     params.CampaignID,
+			}
+
+			campaign, err := service.GetCampaign(ctx, params.CampaignID)
+			if err != nil {
+				msg := fmt.Sprintf("unable to load the re-sign campaign: %s", params.CampaignID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				return resign_campaigns.NewGetResignCampaignNotFound().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseNotFoundWithError(reqID, msg, err))
+			}
+			if authorized, _ := isAuthorized(ctx, authUser, campaign.CLAGroupID); !authorized {
+				msg := fmt.Sprintf("user %s does not have access to Get the Re-sign Campaign %s", authUser.UserName, params.CampaignID)
+				log.WithFields(f).Debug(msg)
+				return resign_campaigns.NewGetResignCampaignForbidden().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseForbidden(reqID, msg))
+			}
+
+			progress, err := service.GetCampaignProgress(ctx, params.CampaignID)
+			if err != nil {
+				msg := fmt.Sprintf("unable to load the progress of the re-sign campaign: %s", params.CampaignID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				return resign_campaigns.NewGetResignCampaignInternalServerError().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+			}
+			return resign_campaigns.NewGetResignCampaignOK().WithXRequestID(reqID).WithPayload(toCampaignProgress(progress))
+		})
+
+	api.ResignCampaignsCancelResignCampaignHandler = resign_campaigns.CancelResignCampaignHandlerFunc(
+		func(params resign_campaigns.CancelResignCampaignParams, authUser *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+			f := logrus.Fields{
+				"functionName":   "v2.resign_campaigns.handlers.ResignCampaignsCancelResignCampaignHandler",
+				utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+				"authUser":       authUser.UserName,
+				"campaignID":     params.CampaignID,
+			}
+
+			campaign, err := service.GetCampaign(ctx, params.CampaignID)
+			if err != nil {
+				msg := fmt.Sprintf("unable to load the re-sign campaign: %s", params.CampaignID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				return resign_campaigns.NewCancelResignCampaignNotFound().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseNotFoundWithError(reqID, msg, err))
+			}
+			if authorized, _ := isAuthorized(ctx, authUser, campaign.CLAGroupID); !authorized {
+				msg := fmt.Sprintf("user %s does not have access to Cancel the Re-sign Campaign %s", authUser.UserName, params.CampaignID)
+				log.WithFields(f).Debug(msg)
+				return resign_campaigns.NewCancelResignCampaignForbidden().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseForbidden(reqID, msg))
+			}
+
+			campaign, err = service.CancelCampaign(ctx, params.CampaignID, authUser.UserName)
+			if err != nil {
+				msg := fmt.Sprintf("unable to cancel the re-sign campaign: %s", params.CampaignID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				if errors.Is(err, campaigns.ErrCampaignNotActive) {
+					return resign_campaigns.NewCancelResignCampaignConflict().WithXRequestID(reqID).WithPayload(
+						utils.ErrorResponseConflictWithError(reqID, msg, err))
+				}
+				return resign_campaigns.NewCancelResignCampaignInternalServerError().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+			}
+			return resign_campaigns.NewCancelResignCampaignOK().WithXRequestID(reqID).WithPayload(toCampaign(campaign))
+		})
+}
+
+func toCampaign(campaign *campaigns.Campaign) *models.ResignCampaign {
+	return &models.ResignCampaign{
+		CampaignID:         campaign.CampaignID,
+		ClaGroupID:         campaign.CLAGroupID,
+		ClaGroupName:       campaign.CLAGroupName,
+		ClaType:            campaign.ClaType,
+		MajorVersion:       int64(campaign.MajorVersion),
+		GracePeriodEnd:     campaign.GracePeriodEnd,
+		InvalidateOnExpiry: campaign.InvalidateOnExpiry,
+		ReminderSent:       campaign.ReminderSent,
+		Status:             campaign.Status,
+		CreatedBy:          campaign.CreatedBy,
+		DateCreated:        campaign.DateCreated,
+		DateModified:       campaign.DateModified,
+	}
+}
+
+func toCampaignList(results []*campaigns.Campaign) *models.ResignCampaignList {
+	list := make([]*models.ResignCampaign, 0, len(results))
+	for _, campaign := range results {
+		list = append(list, toCampaign(campaign))
+	}
+	return &models.ResignCampaignList{List: list}
+}
+
+func toSignerCounts(counts campaigns.SignerCounts) *models.ResignCampaignSignerCounts {
+	return &models.ResignCampaignSignerCounts{
+		Total:       int64(counts.Total),
+		Pending:     int64(counts.Pending),
+		Resigned:    int64(counts.Resigned),
+		Expired:     int64(counts.Expired),
+		Invalidated: int64(counts.Invalidated),
+	}
+}
+
+func toCampaignProgress(progress *campaigns.CampaignProgress) *models.ResignCampaignProgress {
+	companies := make([]*models.ResignCampaignCompanyProgress, 0, len(progress.Companies))
+	for _, company := range progress.Companies {
+		companies = append(companies, &models.ResignCampaignCompanyProgress{
+			CompanyID:   company.CompanyID,
+			CompanyName: company.CompanyName,
+			Counts:      toSignerCounts(company.SignerCounts),
+		})
+	}
+	return &models.ResignCampaignProgress{
+		Campaign:  toCampaign(progress.Campaign),
+		Counts:    toSignerCounts(progress.SignerCounts),
+		Companies: companies,
+	}
+}
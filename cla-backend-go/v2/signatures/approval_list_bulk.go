@@ -0,0 +1,354 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	signatureService "github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// approval list document formats
+const (
+	ApprovalListFormatCSV  = "csv"
+	ApprovalListFormatJSON = "json"
+)
+
+// approval list entry types used in the CSV documents and the import errors
+const (
+	approvalListEntryEmail          = "email"
+	approvalListEntryDomain         = "domain"
+	approvalListEntryGitHubUsername = "github_username"
+	approvalListEntryGitHubOrg      = "github_org"
+)
+
+// approvalListCsvHeader is the header of the approval list CSV documents
+var approvalListCsvHeader = []string{"type", "value", "expires"}
+
+// approvalListEntryTypes is the list of approval list entry types, in export order
+var approvalListEntryTypes = []string{
+	approvalListEntryEmail,
+	approvalListEntryDomain,
+	approvalListEntryGitHubUsername,
+	approvalListEntryGitHubOrg,
+}
+
+// approvalListEntryCriteria maps the approval list entry type to the approval criteria used to validate the entry
+var approvalListEntryCriteria = map[string]string{
+	approvalListEntryEmail:          utils.EmailCriteria,
+	approvalListEntryDomain:         utils.EmailDomainCriteria,
+	approvalListEntryGitHubUsername: utils.GitHubUsernameCriteria,
+	approvalListEntryGitHubOrg:      utils.GitHubOrgCriteria,
+}
+
+// approvalListEntry is a single entry of an imported approval list document
+type approvalListEntry struct {
+	row       int64
+	entryType string
+	value     string
+	expires   string
+}
+
+// approvalListDocumentEntries returns the entries of the approval list document keyed by the entry type
+func approvalListDocumentEntries(doc *models.ApprovalListDocument) map[string][]string {
+	return map[string][]string{
+		approvalListEntryEmail:          doc.EmailApprovalList,
+		approvalListEntryDomain:         doc.DomainApprovalList,
+		approvalListEntryGitHubUsername: doc.GithubUsernameApprovalList,
+		approvalListEntryGitHubOrg:      doc.GithubOrgApprovalList,
+	}
+}
+
+// approvalListDocumentColumns maps the approval list entry type to the signature approval list column
+var approvalListDocumentColumns = map[string]string{
+	approvalListEntryEmail:          signatureService.EmailApprovalListColumn,
+	approvalListEntryDomain:         signatureService.DomainApprovalListColumn,
+	approvalListEntryGitHubUsername: signatureService.GitHubUsernameApprovalListColumn,
+	approvalListEntryGitHubOrg:      signatureService.GitHubOrgApprovalListColumn,
+}
+
+// approvalListDocumentExpiryKey returns the key of the entry in the expirations map of the approval list document,
+// keyed by column and entry like the signature expirations
+func approvalListDocumentExpiryKey(entryType, entry string) string {
+	return signatureService.ApprovalListExpirationKey(approvalListDocumentColumns[entryType], entry)
+}
+
+// approvalListDocumentFromSignature returns the full approval list of the CCLA signature
+func approvalListDocumentFromSignature(sig *v1Models.Signature) *models.ApprovalListDocument {
+	doc := &models.ApprovalListDocument{
+		EmailApprovalList:          sortedCopy(sig.EmailApprovalList),
+		DomainApprovalList:         sortedCopy(sig.DomainApprovalList),
+		GithubUsernameApprovalList: sortedCopy(sig.GithubUsernameApprovalList),
+		GithubOrgApprovalList:      sortedCopy(sig.GithubOrgApprovalList),
+	}
+
+	for entryType, entries := range approvalListDocumentEntries(doc) {
+		for _, entry := range entries {
+			key := approvalListDocumentExpiryKey(entryType, entry)
+			expiry, ok := sig.ApprovalListExpirations[key]
+			if !ok {
+				continue
+			}
+			if doc.ApprovalListExpirations == nil {
+				doc.ApprovalListExpirations = map[string]string{}
+			}
+			doc.ApprovalListExpirations[key] = expiry
+		}
+	}
+
+	return doc
+}
+
+// approvalListCsv returns the approval list document as a CSV document
+func approvalListCsv(doc *models.ApprovalListDocument) ([]byte, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	if err := w.Write(approvalListCsvHeader); err != nil {
+		return nil, err
+	}
+
+	entries := approvalListDocumentEntries(doc)
+	for _, entryType := range approvalListEntryTypes {
+		for _, entry := range entries[entryType] {
+			if err := w.Write([]string{entryType, entry, doc.ApprovalListExpirations[approvalListDocumentExpiryKey(entryType, entry)]}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// parseApprovalListCsv returns the entries of the approval list CSV document, the first row must be the header
+func parseApprovalListCsv(content string) ([]approvalListEntry, []*models.ApprovalListImportError) {
+	r := csv.NewReader(strings.NewReader(content))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, []*models.ApprovalListImportError{{Row: 1, Message: fmt.Sprintf("unable to read the CSV header: %v", err)}}
+	}
+	if len(header) < 2 || !strings.EqualFold(strings.TrimSpace(header[0]), "type") || !strings.EqualFold(strings.TrimSpace(header[1]), "value") {
+		return nil, []*models.ApprovalListImportError{{Row: 1, Message: fmt.Sprintf("invalid CSV header - expecting: %s", strings.Join(approvalListCsvHeader, ","))}}
+	}
+
+	var entries []approvalListEntry
+	var importErrors []*models.ApprovalListImportError
+	row := int64(1)
+	for {
+		record, readErr := r.Read()
+		if readErr == io.EOF {
+			break
+		}
+		row++
+		if readErr != nil {
+			importErrors = append(importErrors, &models.ApprovalListImportError{Row: row, Message: fmt.Sprintf("unable to read the CSV row: %v", readErr)})
+			continue
+		}
+		// Skip the empty lines
+		if len(record) == 0 || (len(record) == 1 && strings.TrimSpace(record[0]) == "") {
+			continue
+		}
+		if len(record) < 2 || len(record) > len(approvalListCsvHeader) {
+			importErrors = append(importErrors, &models.ApprovalListImportError{Row: row, Message: fmt.Sprintf("expecting %d or %d columns, found %d", 2, len(approvalListCsvHeader), len(record))})
+			continue
+		}
+
+		entry := approvalListEntry{
+			row:       row,
+			entryType: strings.ToLower(strings.TrimSpace(record[0])),
+			value:     strings.TrimSpace(record[1]),
+		}
+		if len(record) == len(approvalListCsvHeader) {
+			entry.expires = strings.TrimSpace(record[2])
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, importErrors
+}
+
+// parseApprovalListJSON returns the entries of the approval list JSON document, the entries are numbered in document order
+func parseApprovalListJSON(content string) ([]approvalListEntry, []*models.ApprovalListImportError) {
+	var doc models.ApprovalListDocument
+	decoder := json.NewDecoder(strings.NewReader(content))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, []*models.ApprovalListImportError{{Row: 1, Message: fmt.Sprintf("unable to decode the JSON document: %v", err)}}
+	}
+
+	var entries []approvalListEntry
+	var importErrors []*models.ApprovalListImportError
+	row := int64(0)
+	docEntries := approvalListDocumentEntries(&doc)
+	for _, entryType := range approvalListEntryTypes {
+		for _, value := range docEntries[entryType] {
+			row++
+			entries = append(entries, approvalListEntry{
+				row:       row,
+				entryType: entryType,
+				value:     strings.TrimSpace(value),
+				expires:   doc.ApprovalListExpirations[approvalListDocumentExpiryKey(entryType, value)],
+			})
+		}
+	}
+
+	// Every expiry must belong to an entry of the document
+	var expiryKeys []string
+	for key := range doc.ApprovalListExpirations {
+		expiryKeys = append(expiryKeys, key)
+	}
+	sort.Strings(expiryKeys)
+	for _, key := range expiryKeys {
+		found := false
+		for _, entry := range entries {
+			if approvalListDocumentExpiryKey(entry.entryType, entry.value) == strings.TrimSpace(key) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			importErrors = append(importErrors, &models.ApprovalListImportError{Value: key, Message: "expiry set for an entry which is not in the approval list"})
+		}
+	}
+
+	return entries, importErrors
+}
+
+// validateImportedApprovalListEntries returns the validation errors of the imported entries
+func validateImportedApprovalListEntries(entries []approvalListEntry, now time.Time) []*models.ApprovalListImportError {
+	var importErrors []*models.ApprovalListImportError
+	seen := map[string]int64{}
+	for _, entry := range entries {
+		importError := func(msg string) {
+			importErrors = append(importErrors, &models.ApprovalListImportError{Row: entry.row, Type: entry.entryType, Value: entry.value, Message: msg})
+		}
+
+		criteria, ok := approvalListEntryCriteria[entry.entryType]
+		if !ok {
+			importError(fmt.Sprintf("invalid type - expecting one of: %s", strings.Join(approvalListEntryTypes, ", ")))
+			continue
+		}
+		if entry.value == "" {
+			importError("missing value")
+			continue
+		}
+		if msg, valid := signatureService.ValidateApprovalEntry(criteria, entry.value); !valid {
+			importError(msg)
+			continue
+		}
+
+		key := entry.entryType + ":" + entry.value
+		if firstRow, duplicate := seen[key]; duplicate {
+			importError(fmt.Sprintf("duplicate entry - first seen in row %d", firstRow))
+			continue
+		}
+		seen[key] = entry.row
+
+		if entry.expires != "" {
+			expiresAt, err := time.Parse(time.RFC3339, entry.expires)
+			if err != nil {
+				importError(fmt.Sprintf("invalid expiry %s - expecting an RFC3339 timestamp", entry.expires))
+				continue
+			}
+			if !expiresAt.After(now) {
+				importError(fmt.Sprintf("expiry %s is in the past", entry.expires))
+			}
+		}
+	}
+
+	return importErrors
+}
+
+// approvalListImportChanges returns the approval list update turning the current approval list into the imported one.
+// Entries with a new or removed expiry are added again so that their expiry is updated.
+func approvalListImportChanges(current *models.ApprovalListDocument, entries []approvalListEntry) *v1Models.ApprovalList {
+	imported := map[string][]approvalListEntry{}
+	for _, entry := range entries {
+		imported[entry.entryType] = append(imported[entry.entryType], entry)
+	}
+
+	changes := &v1Models.ApprovalList{}
+	adds := map[string]*[]string{
+		approvalListEntryEmail:          &changes.AddEmailApprovalList,
+		approvalListEntryDomain:         &changes.AddDomainApprovalList,
+		approvalListEntryGitHubUsername: &changes.AddGithubUsernameApprovalList,
+		approvalListEntryGitHubOrg:      &changes.AddGithubOrgApprovalList,
+	}
+	removes := map[string]*[]string{
+		approvalListEntryEmail:          &changes.RemoveEmailApprovalList,
+		approvalListEntryDomain:         &changes.RemoveDomainApprovalList,
+		approvalListEntryGitHubUsername: &changes.RemoveGithubUsernameApprovalList,
+		approvalListEntryGitHubOrg:      &changes.RemoveGithubOrgApprovalList,
+	}
+
+	currentEntries := approvalListDocumentEntries(current)
+	for _, entryType := range approvalListEntryTypes {
+		var importedValues []string
+		for _, entry := range imported[entryType] {
+			importedValues = append(importedValues, entry.value)
+			key := approvalListDocumentExpiryKey(entryType, entry.value)
+			expires := normalizeApprovalListExpiry(entry.expires)
+			if utils.StringInSlice(entry.value, currentEntries[entryType]) && expires == normalizeApprovalListExpiry(current.ApprovalListExpirations[key]) {
+				continue
+			}
+			*adds[entryType] = append(*adds[entryType], entry.value)
+			if expires != "" {
+				if changes.ApprovalListExpirations == nil {
+					changes.ApprovalListExpirations = map[string]string{}
+				}
+				changes.ApprovalListExpirations[key] = expires
+			}
+		}
+		for _, value := range currentEntries[entryType] {
+			if !utils.StringInSlice(value, importedValues) {
+				*removes[entryType] = append(*removes[entryType], value)
+			}
+		}
+	}
+
+	return changes
+}
+
+// approvalListHasChanges returns true if the approval list update adds or removes at least one entry
+func approvalListHasChanges(changes *v1Models.ApprovalList) bool {
+	return len(changes.AddEmailApprovalList) > 0 || len(changes.RemoveEmailApprovalList) > 0 ||
+		len(changes.AddDomainApprovalList) > 0 || len(changes.RemoveDomainApprovalList) > 0 ||
+		len(changes.AddGithubUsernameApprovalList) > 0 || len(changes.RemoveGithubUsernameApprovalList) > 0 ||
+		len(changes.AddGithubOrgApprovalList) > 0 || len(changes.RemoveGithubOrgApprovalList) > 0
+}
+
+// normalizeApprovalListExpiry returns the expiry as a UTC RFC3339 timestamp so that expiries can be compared
+func normalizeApprovalListExpiry(expiry string) string {
+	if expiry == "" {
+		return ""
+	}
+	expiresAt, err := time.Parse(time.RFC3339, expiry)
+	if err != nil {
+		return expiry
+	}
+	return expiresAt.UTC().Format(time.RFC3339)
+}
+
+// sortedCopy returns a sorted copy of the list
+func sortedCopy(list []string) []string {
+	sorted := append([]string{}, list...)
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,114 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"testing"
+	"time"
+
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/v1/models"
+	signatureService "github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApprovalListCsvRoundTrip(t *testing.T) {
+	expiry := time.Now().Add(48 * time.Hour).UTC().Format(time.RFC3339)
+	sig := &v1Models.Signature{
+		EmailApprovalList:          []string{"john@example.com", "jane@example.com"},
+		DomainApprovalList:         []string{"regex:(eng|ops)\\.example\\.com,v2"},
+		GithubUsernameApprovalList: []string{"contractor"},
+		ApprovalListExpirations: map[string]string{
+			signatureService.GitHubUsernameApprovalListColumn + ":contractor": expiry,
+		},
+	}
+
+	doc := approvalListDocumentFromSignature(sig)
+	assert.Equal(t, []string{"jane@example.com", "john@example.com"}, doc.EmailApprovalList)
+	assert.Equal(t, expiry, doc.ApprovalListExpirations[signatureService.GitHubUsernameApprovalListColumn+":contractor"])
+
+	content, err := approvalListCsv(doc)
+	assert.NoError(t, err)
+
+	entries, importErrors := parseApprovalListCsv(string(content))
+	assert.Empty(t, importErrors)
+	assert.Len(t, entries, 4)
+	assert.Equal(t, approvalListEntryDomain, entries[2].entryType)
+	assert.Equal(t, "regex:(eng|ops)\\.example\\.com,v2", entries[2].value)
+	assert.Equal(t, expiry, entries[3].expires)
+
+	// Importing the exported document does not change anything
+	changes := approvalListImportChanges(doc, entries)
+	assert.False(t, approvalListHasChanges(changes))
+}
+
+func TestApprovalListImportChanges(t *testing.T) {
+	expiry := time.Now().Add(48 * time.Hour).UTC().Format(time.RFC3339)
+	current := approvalListDocumentFromSignature(&v1Models.Signature{
+		EmailApprovalList:  []string{"jane@example.com", "john@example.com"},
+		DomainApprovalList: []string{"example.com"},
+	})
+
+	entries, importErrors := parseApprovalListCsv("type,value,expires\n" +
+		"email,jane@example.com,\n" +
+		"email,new@example.com\n" +
+		"email,john@example.com," + expiry + "\n" +
+		"github_org,my-org,\n")
+	assert.Empty(t, importErrors)
+	assert.Empty(t, validateImportedApprovalListEntries(entries, time.Now()))
+
+	changes := approvalListImportChanges(current, entries)
+	assert.Equal(t, []string{"new@example.com", "john@example.com"}, changes.AddEmailApprovalList)
+	assert.Empty(t, changes.RemoveEmailApprovalList)
+	assert.Equal(t, []string{"example.com"}, changes.RemoveDomainApprovalList)
+	assert.Equal(t, []string{"my-org"}, changes.AddGithubOrgApprovalList)
+	assert.Equal(t, map[string]string{signatureService.EmailApprovalListColumn + ":john@example.com": expiry}, changes.ApprovalListExpirations)
+}
+
+func TestApprovalListImportValidationErrors(t *testing.T) {
+	entries, importErrors := parseApprovalListCsv("type,value,expires\n" +
+		"email,jane@example.com,\n" +
+		"email,not-an-email,\n" +
+		"phone,555-1234,\n" +
+		"email,jane@example.com,\n" +
+		"domain,example.com,2001-01-01T00:00:00Z\n" +
+		"github_username,bot,tomorrow\n" +
+		"email\n")
+	assert.Len(t, importErrors, 1)
+	assert.Equal(t, int64(8), importErrors[0].Row)
+
+	validationErrors := validateImportedApprovalListEntries(entries, time.Now())
+	var rows []int64
+	for _, validationError := range validationErrors {
+		rows = append(rows, validationError.Row)
+	}
+	assert.Equal(t, []int64{3, 4, 5, 6, 7}, rows)
+
+	_, importErrors = parseApprovalListCsv("email,value\n")
+	assert.Len(t, importErrors, 1)
+}
+
+func TestParseApprovalListJSON(t *testing.T) {
+	entries, importErrors := parseApprovalListJSON(`{
+		"emailApprovalList": ["jane@example.com"],
+		"githubUsernameApprovalList": ["bot-*"],
+		"githubOrgApprovalList": ["my-org"],
+		"approvalListExpirations": {
+			"github_whitelist:bot-*": "2099-01-01T00:00:00Z",
+			"email_whitelist:bot-*": "2099-01-01T00:00:00Z",
+			"unknown": "2099-01-01T00:00:00Z"
+		}
+	}`)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, approvalListEntryGitHubUsername, entries[1].entryType)
+	assert.Equal(t, "2099-01-01T00:00:00Z", entries[1].expires)
+	assert.Empty(t, entries[2].expires)
+	// the expiries are keyed by column and entry, an expiry for an entry of another list is reported
+	if assert.Len(t, importErrors, 2) {
+		assert.Equal(t, "email_whitelist:bot-*", importErrors[0].Value)
+		assert.Equal(t, "unknown", importErrors[1].Value)
+	}
+
+	_, importErrors = parseApprovalListJSON(`{"emails": []}`)
+	assert.Len(t, importErrors, 1)
+}
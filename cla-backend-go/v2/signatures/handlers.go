@@ -40,7 +40,7 @@ import (
 )
 
 // Configure setups handlers on api with service
-func Configure(api *operations.EasyclaAPI, claGroupService project.Service, projectRepo project.ProjectRepository, companyService company.IService, v1SignatureService signatureService.SignatureService, sessionStore *dynastore.Store, eventsService events.Service, v2service ServiceInterface, projectClaGroupsRepo projects_cla_groups.Repository) { //nolint
+func Configure(api *operations.EasyclaAPI, claGroupService project.Service, projectRepo project.ProjectRepository, companyService company.IService, v1SignatureService signatureService.SignatureService, sessionStore *dynastore.Store, eventsService events.Service, v2service ServiceInterface, projectClaGroupsRepo projects_cla_groups.Repository, documentIntegrityService signatureService.DocumentIntegrityService) { //nolint
 
 	const problemLoadingCLAGroupByID = "problem loading cla group by ID"
 	const iclaNotSupportedForCLAGroup = "individual contribution is not supported for this project"
@@ -186,6 +186,173 @@ func Configure(api *operations.EasyclaAPI, claGroupService project.Service, proj
 		return signatures.NewUpdateApprovalListOK().WithXRequestID(reqID).WithPayload(&v2Sig)
 	})
 
+	// Export the full approval list as a CSV or JSON document
+	api.SignaturesExportApprovalListHandler = signatures.ExportApprovalListHandlerFunc(func(params signatures.ExportApprovalListParams, authUser *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(context.Background(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+		f := logrus.Fields{
+			"functionName":   "v2.signatures.handlers.SignaturesExportApprovalListHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"claGroupID":     params.ClaGroupID,
+			"projectSFID":    params.ProjectSFID,
+			"companyID":      params.CompanyID,
+			"format":         utils.StringValue(params.Format),
+		}
+
+		companyModel, err := companyService.GetCompany(ctx, params.CompanyID)
+		if err != nil {
+			msg := fmt.Sprintf("unable to load company by ID: %s", params.CompanyID)
+			log.WithFields(f).WithError(err).Warn(msg)
+			if _, ok := err.(*utils.CompanyNotFound); ok {
+				return signatures.NewExportApprovalListNotFound().WithXRequestID(reqID).WithPayload(utils.ErrorResponseNotFoundWithError(reqID, msg, err))
+			}
+			return signatures.NewExportApprovalListBadRequest().WithXRequestID(reqID).WithPayload(utils.ErrorResponseBadRequestWithError(reqID, msg, err))
+		}
+
+		// Must be in the Project|Organization Scope to see this
+		if !utils.IsUserAuthorizedForProjectOrganizationTree(ctx, authUser, params.ProjectSFID, companyModel.CompanyExternalID, utils.DISALLOW_ADMIN_SCOPE) {
+			msg := fmt.Sprintf("user '%s' does not have access to export Project Company Approval List with Project|Organization scope of %s | %s",
+				authUser.UserName, params.ProjectSFID, params.CompanyID)
+			log.WithFields(f).Warn(msg)
+			return signatures.NewExportApprovalListForbidden().WithXRequestID(reqID).WithPayload(utils.ErrorResponseForbidden(reqID, msg))
+		}
+
+		doc, err := v2service.GetApprovalListDocument(ctx, params.ClaGroupID, params.CompanyID)
+		if err != nil {
+			msg := fmt.Sprintf("unable to load the approval list using CLA Group ID: %s", params.ClaGroupID)
+			log.WithFields(f).WithError(err).Warn(msg)
+			return signatures.NewExportApprovalListBadRequest().WithXRequestID(reqID).WithPayload(utils.ErrorResponseBadRequestWithError(reqID, msg, err))
+		}
+
+		if utils.StringValue(params.Format) == ApprovalListFormatJSON {
+			return signatures.NewExportApprovalListOK().WithXRequestID(reqID).WithPayload(doc)
+		}
+
+		result, err := approvalListCsv(doc)
+		if err != nil {
+			msg := "unable to generate the approval list CSV"
+			log.WithFields(f).WithError(err).Warn(msg)
+			return signatures.NewExportApprovalListInternalServerError().WithXRequestID(reqID).WithPayload(utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+		}
+
+		log.WithFields(f).Debug("returning CSV response...")
+		return middleware.ResponderFunc(func(rw http.ResponseWriter, pr runtime.Producer) {
+			rw.Header().Set("Content-Type", "text/csv")
+			rw.Header().Set(utils.XREQUESTID, reqID)
+			rw.WriteHeader(http.StatusOK)
+			_, err := rw.Write(result)
+			if err != nil {
+				log.WithFields(f).WithError(err).Warn("error writing csv file")
+			}
+		})
+	})
+
+	// Import a full approval list document - previewing or applying the changes
+	api.SignaturesImportApprovalListHandler = signatures.ImportApprovalListHandlerFunc(func(params signatures.ImportApprovalListParams, authUser *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(context.Background(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+		f := logrus.Fields{
+			"functionName":   "v2.signatures.handlers.SignaturesImportApprovalListHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"claGroupID":     params.ClaGroupID,
+			"projectSFID":    params.ProjectSFID,
+			"companyID":      params.CompanyID,
+		}
+
+		companyModel, err := companyService.GetCompany(ctx, params.CompanyID)
+		if err != nil {
+			msg := fmt.Sprintf("unable to load company by ID: %s", params.CompanyID)
+			log.WithFields(f).WithError(err).Warn(msg)
+			if _, ok := err.(*utils.CompanyNotFound); ok {
+				return signatures.NewImportApprovalListNotFound().WithXRequestID(reqID).WithPayload(utils.ErrorResponseNotFoundWithError(reqID, msg, err))
+			}
+			return signatures.NewImportApprovalListBadRequest().WithXRequestID(reqID).WithPayload(utils.ErrorResponseBadRequestWithError(reqID, msg, err))
+		}
+
+		// Must be in the Project|Organization Scope to see this - signature ACL is double-checked in the service level when the signature is loaded
+		if !utils.IsUserAuthorizedForProjectOrganizationTree(ctx, authUser, params.ProjectSFID, companyModel.CompanyExternalID, utils.DISALLOW_ADMIN_SCOPE) {
+			msg := fmt.Sprintf("user '%s' does not have access to import Project Company Approval List with Project|Organization scope of %s | %s",
+				authUser.UserName, params.ProjectSFID, params.CompanyID)
+			log.WithFields(f).Warn(msg)
+			return signatures.NewImportApprovalListForbidden().WithXRequestID(reqID).WithPayload(utils.ErrorResponseForbidden(reqID, msg))
+		}
+
+		claGroupModel, projErr := claGroupService.GetCLAGroupByID(ctx, params.ClaGroupID)
+		if projErr != nil || claGroupModel == nil {
+			msg := fmt.Sprintf("unable to locate project by CLA Group ID: %s", params.ClaGroupID)
+			log.WithFields(f).Warn(msg)
+			return signatures.NewImportApprovalListNotFound().WithXRequestID(reqID).WithPayload(utils.ErrorResponseNotFound(reqID, msg))
+		}
+
+		result, importErr := v2service.ImportApprovalList(ctx, authUser, claGroupModel, companyModel, params.Body)
+		if importErr != nil {
+			msg := fmt.Sprintf("unable to import the approval list using CLA Group ID: %s", params.ClaGroupID)
+			log.WithFields(f).WithError(importErr).Warn(msg)
+			if forbiddenErr, ok := importErr.(*signatureService.ForbiddenError); ok {
+				return signatures.NewImportApprovalListForbidden().WithXRequestID(reqID).WithPayload(utils.ErrorResponseForbiddenWithError(reqID, msg, forbiddenErr))
+			}
+			return signatures.NewImportApprovalListBadRequest().WithXRequestID(reqID).WithPayload(utils.ErrorResponseBadRequestWithError(reqID, msg, importErr))
+		}
+
+		log.WithFields(f).Debugf("approval list import - dry run: %t, applied: %t, %d validation errors", result.DryRun, result.Applied, len(result.Errors))
+		return signatures.NewImportApprovalListOK().WithXRequestID(reqID).WithPayload(result)
+	})
+
+	// Explain why a user is approved, denied or not matched by the approval list
+	api.SignaturesExplainApprovalHandler = signatures.ExplainApprovalHandlerFunc(func(params signatures.ExplainApprovalParams, authUser *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(context.Background(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+		f := logrus.Fields{
+			"functionName":   "v2.signatures.handlers.SignaturesExplainApprovalHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"claGroupID":     params.ClaGroupID,
+			"projectSFID":    params.ProjectSFID,
+			"companyID":      params.CompanyID,
+		}
+
+		companyModel, err := companyService.GetCompany(ctx, params.CompanyID)
+		if err != nil {
+			msg := fmt.Sprintf("unable to load company by ID: %s", params.CompanyID)
+			log.WithFields(f).WithError(err).Warn(msg)
+			if _, ok := err.(*utils.CompanyNotFound); ok {
+				return signatures.NewExplainApprovalNotFound().WithXRequestID(reqID).WithPayload(utils.ErrorResponseNotFoundWithError(reqID, msg, err))
+			}
+			return signatures.NewExplainApprovalBadRequest().WithXRequestID(reqID).WithPayload(utils.ErrorResponseBadRequestWithError(reqID, msg, err))
+		}
+
+		// Must be in the Project|Organization Scope to see this
+		if !utils.IsUserAuthorizedForProjectOrganizationTree(ctx, authUser, params.ProjectSFID, companyModel.CompanyExternalID, utils.DISALLOW_ADMIN_SCOPE) {
+			msg := fmt.Sprintf("user '%s' does not have access to the Project Company Approval List with Project|Organization scope of %s | %s",
+				authUser.UserName, params.ProjectSFID, params.CompanyID)
+			log.WithFields(f).Warn(msg)
+			return signatures.NewExplainApprovalForbidden().WithXRequestID(reqID).WithPayload(utils.ErrorResponseForbidden(reqID, msg))
+		}
+
+		match, err := v1SignatureService.ExplainApproval(ctx, params.ClaGroupID, params.CompanyID, signatureService.ApprovalCandidate{
+			Emails:         params.Body.Emails,
+			GitHubUsername: params.Body.GithubUsername,
+			GitHubOrgs:     params.Body.GithubOrgs,
+		})
+		if err != nil {
+			msg := fmt.Sprintf("unable to evaluate the approval list using CLA Group ID: %s", params.ClaGroupID)
+			log.WithFields(f).WithError(err).Warn(msg)
+			return signatures.NewExplainApprovalBadRequest().WithXRequestID(reqID).WithPayload(utils.ErrorResponseBadRequestWithError(reqID, msg, err))
+		}
+
+		log.WithFields(f).Debugf("approval list explain - approved: %t, denied: %t", match.Approved, match.Denied)
+		return signatures.NewExplainApprovalOK().WithXRequestID(reqID).WithPayload(&models.ApprovalMatch{
+			Approved: match.Approved,
+			Denied:   match.Denied,
+			Criteria: match.Criteria,
+			Entry:    match.Entry,
+			Value:    match.Value,
+			Reason:   match.Reason,
+		})
+	})
+
 	// Retrieve GitHub Approval Entries
 	api.SignaturesGetGitHubOrgWhitelistHandler = signatures.GetGitHubOrgWhitelistHandlerFunc(func(params signatures.GetGitHubOrgWhitelistParams, authUser *auth.User) middleware.Responder {
 		reqID := utils.GetRequestID(params.XREQUESTID)
@@ -1022,6 +1189,59 @@ func Configure(api *operations.EasyclaAPI, claGroupService project.Service, proj
 		return signatures.NewGetSignatureSignedDocumentOK().WithXRequestID(reqID).WithPayload(doc)
 	})
 
+	api.SignaturesVerifySignatureDocumentHandler = signatures.VerifySignatureDocumentHandlerFunc(func(params signatures.VerifySignatureDocumentParams, authUser *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(context.Background(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+		f := logrus.Fields{
+			"functionName":   "v2.signatures.handlers.SignaturesVerifySignatureDocumentHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"signatureID":    params.SignatureID,
+		}
+
+		log.WithFields(f).Debug("loading signature by ID...")
+		signatureModel, err := v1SignatureService.GetSignature(ctx, params.SignatureID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading signature")
+			return signatures.NewVerifySignatureDocumentBadRequest().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		if signatureModel == nil {
+			log.WithFields(f).Warn("problem loading signature - signature not found")
+			return signatures.NewVerifySignatureDocumentNotFound().WithXRequestID(reqID).WithPayload(errorResponse(reqID, errors.New("signature not found")))
+		}
+
+		haveAccess, err := isUserHaveAccessOfSignedSignaturePDF(ctx, authUser, signatureModel, companyService, projectClaGroupsRepo, projectRepo)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem determining signature access")
+			return signatures.NewVerifySignatureDocumentBadRequest().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		if !haveAccess {
+			return signatures.NewVerifySignatureDocumentForbidden().WithXRequestID(reqID).WithPayload(
+				utils.ErrorResponseForbidden(reqID, fmt.Sprintf("user %s does not have access to the specified signature", authUser.UserName)))
+		}
+
+		result, err := documentIntegrityService.VerifySignatureDocument(ctx, signatureModel.SignatureID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem verifying signed document")
+			switch err {
+			case signatureService.ErrSignatureNotFound:
+				return signatures.NewVerifySignatureDocumentNotFound().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			case signatureService.ErrNoSignedDocument:
+				return signatures.NewVerifySignatureDocumentBadRequest().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			}
+			return signatures.NewVerifySignatureDocumentInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+
+		log.WithFields(f).Debugf("signed document integrity: %s", result.Status)
+		return signatures.NewVerifySignatureDocumentOK().WithXRequestID(reqID).WithPayload(&models.SignatureDocumentIntegrity{
+			SignatureID:    result.SignatureID,
+			Status:         result.Status,
+			ExpectedSha256: result.ExpectedSHA256,
+			ActualSha256:   result.ActualSHA256,
+			VerifiedOn:     result.VerifiedOn,
+		})
+	})
+
 	api.SignaturesDownloadProjectSignatureICLAsHandler = signatures.DownloadProjectSignatureICLAsHandlerFunc(func(params signatures.DownloadProjectSignatureICLAsParams, authUser *auth.User) middleware.Responder {
 		reqID := utils.GetRequestID(params.XREQUESTID)
 		ctx := context.WithValue(context.Background(), utils.XREQUESTID, reqID) // nolint
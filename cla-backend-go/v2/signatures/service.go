@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/LF-Engineering/lfx-kit/auth"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -55,6 +56,8 @@ type ServiceInterface interface {
 	GetSignedIclaZipPdf(claGroupID string) (*models.URLObject, error)
 	GetSignedCclaZipPdf(claGroupID string) (*models.URLObject, error)
 	InvalidateICLA(ctx context.Context, claGroupID string, userID string, authUser *auth.User, eventsService events.Service, eventArgs *events.LogEventArgs) error
+	GetApprovalListDocument(ctx context.Context, claGroupID, companyID string) (*models.ApprovalListDocument, error)
+	ImportApprovalList(ctx context.Context, authUser *auth.User, claGroupModel *v1Models.ClaGroup, companyModel *v1Models.Company, importRequest *models.ApprovalListImport) (*models.ApprovalListImportResult, error)
 }
 
 // Service structure/model
@@ -379,3 +382,115 @@ func (s *Service) InvalidateICLA(ctx context.Context, claGroupID string, userID
 
 	return nil
 }
+
+// getCorporateSignature returns the signed and approved CCLA signature of the company for the specified CLA Group
+func (s *Service) getCorporateSignature(ctx context.Context, claGroupID, companyID string) (*v1Models.Signature, error) {
+	signed, approved := true, true
+	sig, err := s.v1SignatureService.GetProjectCompanySignature(ctx, companyID, claGroupID, &approved, &signed, nil, aws.Int64(1))
+	if err != nil {
+		return nil, err
+	}
+	if sig == nil {
+		return nil, signatures.NewBadRequestError(fmt.Sprintf("unable to locate signature for company ID: %s CLA Group ID: %s, type: ccla, signed: %t, approved: %t",
+			companyID, claGroupID, signed, approved))
+	}
+	return sig, nil
+}
+
+// GetApprovalListDocument returns the full approval list of the company CCLA signature for the specified CLA Group
+func (s *Service) GetApprovalListDocument(ctx context.Context, claGroupID, companyID string) (*models.ApprovalListDocument, error) {
+	f := logrus.Fields{
+		"functionName":   "v2.signatures.service.GetApprovalListDocument",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+		"companyID":      companyID,
+	}
+
+	sig, err := s.getCorporateSignature(ctx, claGroupID, companyID)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the CCLA signature")
+		return nil, err
+	}
+
+	return approvalListDocumentFromSignature(sig), nil
+}
+
+// ImportApprovalList replaces the company approval list with the imported document. The document is compared with the
+// current approval list and every row is validated - the changes are only applied, in a single approval list update,
+// when the request is not a dry run and the document has no validation errors.
+func (s *Service) ImportApprovalList(ctx context.Context, authUser *auth.User, claGroupModel *v1Models.ClaGroup, companyModel *v1Models.Company, importRequest *models.ApprovalListImport) (*models.ApprovalListImportResult, error) {
+	f := logrus.Fields{
+		"functionName":   "v2.signatures.service.ImportApprovalList",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupModel.ProjectID,
+		"companyID":      companyModel.CompanyID,
+		"format":         utils.StringValue(importRequest.Format),
+		"dryRun":         importRequest.DryRun,
+	}
+
+	sig, err := s.getCorporateSignature(ctx, claGroupModel.ProjectID, companyModel.CompanyID)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the CCLA signature")
+		return nil, err
+	}
+
+	// Only the CLA Managers may preview or apply changes to the approval list
+	if !utils.CurrentUserInACL(authUser, sig.SignatureACL) {
+		return nil, signatures.NewForbiddenError(fmt.Sprintf("EasyCLA - 403 Forbidden - CLA Manager %s / %s is not authorized to import the approval list for company ID: %s, CLA Group ID: %s",
+			authUser.UserName, authUser.Email, companyModel.CompanyID, claGroupModel.ProjectID))
+	}
+
+	var entries []approvalListEntry
+	var importErrors []*models.ApprovalListImportError
+	switch utils.StringValue(importRequest.Format) {
+	case ApprovalListFormatCSV:
+		entries, importErrors = parseApprovalListCsv(utils.StringValue(importRequest.Content))
+	case ApprovalListFormatJSON:
+		entries, importErrors = parseApprovalListJSON(utils.StringValue(importRequest.Content))
+	default:
+		return nil, signatures.NewBadRequestError(fmt.Sprintf("unsupported approval list format: %s", utils.StringValue(importRequest.Format)))
+	}
+	importErrors = append(importErrors, validateImportedApprovalListEntries(entries, time.Now())...)
+
+	v1Changes := approvalListImportChanges(approvalListDocumentFromSignature(sig), entries)
+	var changes models.ApprovalList
+	if copyErr := copier.Copy(&changes, v1Changes); copyErr != nil {
+		log.WithFields(f).WithError(copyErr).Warn("unable to convert v1 to v2 approval list")
+		return nil, copyErr
+	}
+
+	result := &models.ApprovalListImportResult{
+		DryRun:  importRequest.DryRun,
+		Changes: &changes,
+		Errors:  importErrors,
+	}
+
+	if importRequest.DryRun || len(importErrors) > 0 || !approvalListHasChanges(v1Changes) {
+		log.WithFields(f).Debugf("not applying the approval list import - %d validation errors", len(importErrors))
+		var v2Sig models.Signature
+		if copyErr := copier.Copy(&v2Sig, sig); copyErr != nil {
+			log.WithFields(f).WithError(copyErr).Warn("unable to convert v1 to v2 signature")
+			return nil, copyErr
+		}
+		result.Signature = &v2Sig
+		return result, nil
+	}
+
+	// Apply the changes using the regular approval list update - invalidating the signatures, logging the events and
+	// notifying the CLA Managers and contributors
+	log.WithFields(f).Debugf("applying the approval list import changes: %+v", v1Changes)
+	updatedSig, err := s.v1SignatureService.UpdateApprovalList(ctx, authUser, claGroupModel, companyModel, claGroupModel.ProjectID, v1Changes)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to apply the approval list import")
+		return nil, err
+	}
+
+	var v2Sig models.Signature
+	if copyErr := copier.Copy(&v2Sig, updatedSig); copyErr != nil {
+		log.WithFields(f).WithError(copyErr).Warn("unable to convert v1 to v2 signature")
+		return nil, copyErr
+	}
+	result.Applied = true
+	result.Signature = &v2Sig
+	return result, nil
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations/signatures"
+	signatureService "github.com/communitybridge/easycla/cla-backend-go/signatures"
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
 	"github.com/go-openapi/runtime/middleware"
 )
@@ -38,71 +39,30 @@ func hasApprovalListUpdates(params signatures.UpdateApprovalListParams) bool {
 	return false
 }
 
-// entriesAreValid returns true if the values in the approval list are valid, returns false and a message otherwise
+// entriesAreValid returns true if the values in the approval list are valid, returns false and a message otherwise.
+// Entries may be wildcard, regex or deny patterns - see signatureService.ValidateApprovalEntry
 func entriesAreValid(params signatures.UpdateApprovalListParams) (string, bool) {
 	var listOfErrors []string
-	isValid := true
-	// Ensure the email address are valid
-	for _, email := range params.Body.AddEmailApprovalList {
-		if !utils.ValidEmail(email) {
-			isValid = false
-			listOfErrors = append(listOfErrors, fmt.Sprintf("invalid add approval list email %s", email))
-		}
-	}
-	for _, email := range params.Body.RemoveEmailApprovalList {
-		if !utils.ValidEmail(email) {
-			isValid = false
-			listOfErrors = append(listOfErrors, fmt.Sprintf("invalid remove approval list email %s", email))
-		}
-	}
-
-	// Ensure the domains are valid
-	for _, domain := range params.Body.AddDomainApprovalList {
-		msg, valid := utils.ValidDomain(domain, true)
-		if !valid {
-			isValid = false
-			listOfErrors = append(listOfErrors, fmt.Sprintf("invalid add approval list domain %s - %s", domain, msg))
-		}
-	}
-	for _, domain := range params.Body.RemoveDomainApprovalList {
-		msg, valid := utils.ValidDomain(domain, true)
-		if !valid {
-			isValid = false
-			listOfErrors = append(listOfErrors, fmt.Sprintf("invalid remove approval list domain %s - %s", domain, msg))
-		}
-	}
-
-	// Ensure the github usernames are valid
-	for _, githubUsername := range params.Body.AddGithubUsernameApprovalList {
-		msg, valid := utils.ValidGitHubUsername(githubUsername)
-		if !valid {
-			isValid = false
-			listOfErrors = append(listOfErrors, fmt.Sprintf("invalid add approval list GitHub Username %s - %s", githubUsername, msg))
-		}
-	}
-	for _, githubUsername := range params.Body.RemoveGithubUsernameApprovalList {
-		msg, valid := utils.ValidGitHubUsername(githubUsername)
-		if !valid {
-			isValid = false
-			listOfErrors = append(listOfErrors, fmt.Sprintf("invalid remove approval list GitHub Username %s - %s", githubUsername, msg))
-		}
-	}
-
-	// Ensure the github Organization values are valid
-	for _, githubOrg := range params.Body.AddGithubOrgApprovalList {
-		msg, valid := utils.ValidGitHubOrg(githubOrg)
-		if !valid {
-			isValid = false
-			listOfErrors = append(listOfErrors, fmt.Sprintf("invalid add approval list GitHub Org %s - %s", githubOrg, msg))
-		}
-	}
-	for _, githubOrg := range params.Body.RemoveGithubOrgApprovalList {
-		msg, valid := utils.ValidGitHubOrg(githubOrg)
-		if !valid {
-			isValid = false
-			listOfErrors = append(listOfErrors, fmt.Sprintf("invalid remove approval list GitHub Org %s - %s", githubOrg, msg))
+	for _, list := range []struct {
+		action   string
+		criteria string
+		entries  []string
+	}{
+		{"add", utils.EmailCriteria, params.Body.AddEmailApprovalList},
+		{"remove", utils.EmailCriteria, params.Body.RemoveEmailApprovalList},
+		{"add", utils.EmailDomainCriteria, params.Body.AddDomainApprovalList},
+		{"remove", utils.EmailDomainCriteria, params.Body.RemoveDomainApprovalList},
+		{"add", utils.GitHubUsernameCriteria, params.Body.AddGithubUsernameApprovalList},
+		{"remove", utils.GitHubUsernameCriteria, params.Body.RemoveGithubUsernameApprovalList},
+		{"add", utils.GitHubOrgCriteria, params.Body.AddGithubOrgApprovalList},
+		{"remove", utils.GitHubOrgCriteria, params.Body.RemoveGithubOrgApprovalList},
+	} {
+		for _, entry := range list.entries {
+			if msg, valid := signatureService.ValidateApprovalEntry(list.criteria, entry); !valid {
+				listOfErrors = append(listOfErrors, fmt.Sprintf("%s: %s", list.action, msg))
+			}
 		}
 	}
 
-	return strings.Join(listOfErrors, ", "), isValid
+	return strings.Join(listOfErrors, ", "), len(listOfErrors) == 0
 }
@@ -0,0 +1,289 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// zip format signatures and record lengths, see APPNOTE.TXT
+const (
+	directoryHeaderSignature    = 0x02014b50
+	directoryEndSignature       = 0x06054b50
+	directory64LocSignature     = 0x07064b50
+	directory64EndSignature     = 0x06064b50
+	directoryHeaderLen          = 46
+	directoryEndLen             = 22
+	directory64LocLen           = 20
+	directory64EndLen           = 56
+	maxZipCommentLen            = 0xffff
+	uint16max                   = 0xffff
+	uint32max                   = 0xffffffff
+	zip64VersionNeededToExtract = 45
+)
+
+// ErrInvalidZipArchive is returned when the central directory of an existing archive can not be located
+var ErrInvalidZipArchive = errors.New("invalid zip archive")
+
+// zipDirectory describes the central directory of an archive
+type zipDirectory struct {
+	// offset of the first central directory record, the new entries are written from this offset
+	offset  int64
+	size    int64
+	entries int64
+}
+
+// readZipDirectory locates the central directory of the archive from the end of central directory records, only
+// the tail of the archive is read
+func readZipDirectory(r io.ReaderAt, size int64) (*zipDirectory, error) {
+	tailLen := int64(directoryEndLen + maxZipCommentLen + directory64LocLen)
+	if tailLen > size {
+		tailLen = size
+	}
+	tail := make([]byte, tailLen)
+	if _, err := r.ReadAt(tail, size-tailLen); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	eocd := findDirectoryEnd(tail)
+	if eocd < 0 {
+		return nil, ErrInvalidZipArchive
+	}
+	eocdOffset := size - tailLen + int64(eocd)
+	record := tail[eocd:]
+	dir := &zipDirectory{
+		entries: int64(binary.LittleEndian.Uint16(record[10:])),
+		size:    int64(binary.LittleEndian.Uint32(record[12:])),
+		offset:  int64(binary.LittleEndian.Uint32(record[16:])),
+	}
+
+	// zip64 archives have a locator right before the end of central directory record
+	if eocd >= directory64LocLen && binary.LittleEndian.Uint32(tail[eocd-directory64LocLen:]) == directory64LocSignature {
+		zip64EndOffset := int64(binary.LittleEndian.Uint64(tail[eocd-directory64LocLen+8:]))
+		if zip64EndOffset < 0 || zip64EndOffset+directory64EndLen > eocdOffset {
+			return nil, ErrInvalidZipArchive
+		}
+		zip64End := make([]byte, directory64EndLen)
+		if _, err := r.ReadAt(zip64End, zip64EndOffset); err != nil && err != io.EOF {
+			return nil, err
+		}
+		if binary.LittleEndian.Uint32(zip64End) != directory64EndSignature {
+			return nil, ErrInvalidZipArchive
+		}
+		dir.entries = int64(binary.LittleEndian.Uint64(zip64End[32:]))
+		dir.size = int64(binary.LittleEndian.Uint64(zip64End[40:]))
+		dir.offset = int64(binary.LittleEndian.Uint64(zip64End[48:]))
+		eocdOffset = zip64EndOffset
+	}
+
+	if dir.offset < 0 || dir.size < 0 || dir.offset+dir.size > eocdOffset {
+		return nil, ErrInvalidZipArchive
+	}
+	return dir, nil
+}
+
+// findDirectoryEnd returns the position of the end of central directory record in the tail of the archive or -1
+func findDirectoryEnd(tail []byte) int {
+	for i := len(tail) - directoryEndLen; i >= 0; i-- {
+		if binary.LittleEndian.Uint32(tail[i:]) != directoryEndSignature {
+			continue
+		}
+		// the comment must span until the end of the archive
+		commentLen := int(binary.LittleEndian.Uint16(tail[i+20:]))
+		if i+directoryEndLen+commentLen == len(tail) {
+			return i
+		}
+	}
+	return -1
+}
+
+// directoryNames returns the file names of the central directory records
+func directoryNames(records []byte) ([]string, error) {
+	var names []string
+	_, err := directoryLen(records, func(name string) {
+		names = append(names, name)
+	})
+	return names, err
+}
+
+// directoryLen calls fn for each central directory record and returns the length of the records, the walk stops
+// at the first bytes which are not a central directory record (e.g. the end of central directory record)
+func directoryLen(records []byte, fn func(name string)) (int, error) {
+	pos := 0
+	for len(records)-pos >= 4 && binary.LittleEndian.Uint32(records[pos:]) == directoryHeaderSignature {
+		if len(records)-pos < directoryHeaderLen {
+			return 0, ErrInvalidZipArchive
+		}
+		nameLen := int(binary.LittleEndian.Uint16(records[pos+28:]))
+		extraLen := int(binary.LittleEndian.Uint16(records[pos+30:]))
+		commentLen := int(binary.LittleEndian.Uint16(records[pos+32:]))
+		recordLen := directoryHeaderLen + nameLen + extraLen + commentLen
+		if len(records)-pos < recordLen {
+			return 0, ErrInvalidZipArchive
+		}
+		if fn != nil {
+			fn(string(records[pos+directoryHeaderLen : pos+directoryHeaderLen+nameLen]))
+		}
+		pos += recordLen
+	}
+	return pos, nil
+}
+
+// countingWriter counts the bytes written to the archive, the zip writer output is captured instead of written
+// while the central directory is produced
+type countingWriter struct {
+	w       io.Writer
+	count   int64
+	capture *bytes.Buffer
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.capture != nil {
+		return c.capture.Write(p)
+	}
+	n, err := c.w.Write(p)
+	c.count += int64(n)
+	return n, err
+}
+
+// zipAppender appends entries to an archive without reading its content - the archive bytes up to the central
+// directory offset must have been written to the output, the old central directory records are re-written after
+// the new entries on Close
+type zipAppender struct {
+	out     *countingWriter
+	zw      *zip.Writer
+	base    *zipDirectory
+	entries int64
+}
+
+// newZipAppender returns an appender of the entries to the archive described by base, a zero base starts a new
+// archive
+func newZipAppender(w io.Writer, base *zipDirectory) *zipAppender {
+	out := &countingWriter{w: w}
+	zw := zip.NewWriter(out)
+	// the local header offsets of the new central directory records are relative to the start of the archive
+	zw.SetOffset(base.offset)
+	return &zipAppender{
+		out:  out,
+		zw:   zw,
+		base: base,
+	}
+}
+
+// Add writes the compressed file to the archive
+func (a *zipAppender) Add(filename string, content []byte) error {
+	header := &zip.FileHeader{
+		Name:   filename,
+		Method: zip.Deflate,
+	}
+	header.SetMode(0644)
+	f, err := a.zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(content); err != nil {
+		return err
+	}
+	a.entries++
+	return nil
+}
+
+// Close writes the old central directory records, the records of the added entries and the end of central
+// directory records
+func (a *zipAppender) Close(oldRecords []byte) error {
+	if int64(len(oldRecords)) != a.base.size {
+		return ErrInvalidZipArchive
+	}
+	// the zip writer flushes the end of the last entry, then writes the central directory of the new entries only
+	a.out.capture = &bytes.Buffer{}
+	if err := a.zw.Close(); err != nil {
+		return err
+	}
+	captured := a.out.capture.Bytes()
+	a.out.capture = nil
+	recordsStart, recordsEnd, err := newDirectoryRecords(captured)
+	if err != nil {
+		return err
+	}
+
+	if _, err = a.out.Write(captured[:recordsStart]); err != nil {
+		return err
+	}
+	offset := a.base.offset + a.out.count
+	if _, err = a.out.Write(oldRecords); err != nil {
+		return err
+	}
+	if _, err = a.out.Write(captured[recordsStart:recordsEnd]); err != nil {
+		return err
+	}
+	_, err = a.out.Write(directoryEnd(a.base.entries+a.entries, a.base.size+int64(recordsEnd-recordsStart), offset))
+	return err
+}
+
+// newDirectoryRecords returns the position of the central directory records in the output of the zip writer
+// Close, the records are followed by the end of central directory records
+func newDirectoryRecords(output []byte) (int, int, error) {
+	eocd := findDirectoryEnd(output)
+	if eocd < 0 {
+		return 0, 0, ErrInvalidZipArchive
+	}
+	recordsEnd := eocd
+	size := int(binary.LittleEndian.Uint32(output[eocd+12:]))
+	if eocd >= directory64LocLen+directory64EndLen && binary.LittleEndian.Uint32(output[eocd-directory64LocLen:]) == directory64LocSignature {
+		recordsEnd = eocd - directory64LocLen - directory64EndLen
+		size = int(binary.LittleEndian.Uint64(output[recordsEnd+40:]))
+	}
+	if size > recordsEnd {
+		return 0, 0, ErrInvalidZipArchive
+	}
+	return recordsEnd - size, recordsEnd, nil
+}
+
+// directoryEnd returns the end of central directory record, preceded by the zip64 records when the archive needs
+// them
+func directoryEnd(entries, size, offset int64) []byte {
+	buf := &bytes.Buffer{}
+	le := binary.LittleEndian
+	if entries >= uint16max || size >= uint32max || offset >= uint32max {
+		// zip64 end of central directory record
+		b := make([]byte, directory64EndLen)
+		le.PutUint32(b, directory64EndSignature)
+		le.PutUint64(b[4:], directory64EndLen-12)
+		le.PutUint16(b[12:], zip64VersionNeededToExtract)
+		le.PutUint16(b[14:], zip64VersionNeededToExtract)
+		le.PutUint64(b[24:], uint64(entries))
+		le.PutUint64(b[32:], uint64(entries))
+		le.PutUint64(b[40:], uint64(size))
+		le.PutUint64(b[48:], uint64(offset))
+		buf.Write(b)
+
+		// zip64 end of central directory locator
+		b = make([]byte, directory64LocLen)
+		le.PutUint32(b, directory64LocSignature)
+		le.PutUint64(b[8:], uint64(offset+size))
+		le.PutUint32(b[16:], 1)
+		buf.Write(b)
+
+		entries, size, offset = min64(entries, uint16max), min64(size, uint32max), min64(offset, uint32max)
+	}
+	b := make([]byte, directoryEndLen)
+	le.PutUint32(b, directoryEndSignature)
+	le.PutUint16(b[8:], uint16(entries))
+	le.PutUint16(b[10:], uint16(entries))
+	le.PutUint32(b[12:], uint32(size))
+	le.PutUint32(b[16:], uint32(offset))
+	buf.Write(b)
+	return buf.Bytes()
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,94 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// appendToZip appends the files to the archive the same way the zip builder does, by copying the bytes up to the
+// central directory
+func appendToZip(t *testing.T, archive []byte, files map[string]string, names ...string) []byte {
+	base := &zipDirectory{}
+	var oldRecords []byte
+	if len(archive) > 0 {
+		var err error
+		base, err = readZipDirectory(bytes.NewReader(archive), int64(len(archive)))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		oldRecords = archive[base.offset : base.offset+base.size]
+	}
+
+	out := bytes.NewBuffer(append([]byte{}, archive[:base.offset]...))
+	appender := newZipAppender(out, base)
+	for _, name := range names {
+		assert.NoError(t, appender.Add(name, []byte(files[name])))
+	}
+	assert.NoError(t, appender.Close(oldRecords))
+	return out.Bytes()
+}
+
+func TestZipAppender(t *testing.T) {
+	files := map[string]string{
+		"a.pdf": "first signature",
+		"b.pdf": "second signature",
+		"c.pdf": "third signature",
+		"d.pdf": "fourth signature",
+	}
+
+	// an archive with a comment written by another zip writer
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	w, err := zw.Create("a.pdf")
+	assert.NoError(t, err)
+	_, err = w.Write([]byte(files["a.pdf"]))
+	assert.NoError(t, err)
+	assert.NoError(t, zw.SetComment("signed cla archive"))
+	assert.NoError(t, zw.Close())
+
+	archive := appendToZip(t, buf.Bytes(), files, "b.pdf")
+	archive = appendToZip(t, archive, files, "c.pdf", "d.pdf")
+
+	dir, err := readZipDirectory(bytes.NewReader(archive), int64(len(archive)))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), dir.entries)
+	names, err := directoryNames(archive[dir.offset : dir.offset+dir.size])
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a.pdf", "b.pdf", "c.pdf", "d.pdf"}, names)
+
+	r, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if !assert.NoError(t, err) || !assert.Len(t, r.File, 4) {
+		return
+	}
+	for _, f := range r.File {
+		rc, err := f.Open()
+		assert.NoError(t, err)
+		content, err := ioutil.ReadAll(rc)
+		assert.NoError(t, err)
+		assert.Equal(t, files[f.Name], string(content))
+	}
+
+	// a new archive
+	archive = appendToZip(t, nil, files, "d.pdf")
+	r, err = zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	assert.NoError(t, err)
+	assert.Len(t, r.File, 1)
+}
+
+func TestZip64DirectoryEnd(t *testing.T) {
+	// 70000 entries need the zip64 end of central directory record
+	archive := append(make([]byte, 1000), directoryEnd(70000, 600, 400)...)
+	dir, err := readZipDirectory(bytes.NewReader(archive), int64(len(archive)))
+	assert.NoError(t, err)
+	assert.Equal(t, &zipDirectory{offset: 400, size: 600, entries: 70000}, dir)
+
+	_, err = readZipDirectory(bytes.NewReader(archive[:len(archive)-1]), int64(len(archive)-1))
+	assert.Equal(t, ErrInvalidZipArchive, err)
+}
@@ -4,27 +4,31 @@
 package signatures
 
 import (
-	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
 
-	"github.com/juju/zip"
-
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 
-	"github.com/aws/aws-sdk-go/service/s3/s3manager"
-
 	"github.com/aws/aws-sdk-go/aws/awserr"
 
 	"github.com/aws/aws-sdk-go/aws"
 
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 )
 
 // constants
@@ -32,25 +36,49 @@ const (
 	ICLA               = "icla"
 	CCLA               = "ccla"
 	ParallelDownloader = 100
+	// MaxFilesPerRun is the number of files archived by a run, the following runs resume from the saved archive
+	MaxFilesPerRun = 5000
+	// DeadlineMargin is the time kept to save the archive before the context deadline (e.g. the lambda timeout)
+	DeadlineMargin = 2 * time.Minute
 )
 
+// ErrNoFileArchived is returned when none of the signed documents could be downloaded
+var ErrNoFileArchived = errors.New("unable to archive any of the signed documents")
+
 // Zipper implements ZipBuilder interface
 type Zipper struct {
-	s3         *s3.S3
-	bucketName string
+	s3             s3iface.S3API
+	bucketName     string
+	signatures     *signatureMetadataLookup
+	partSize       int
+	maxFilesPerRun int
+	deadlineMargin time.Duration
+}
+
+// BuildZipResult is the outcome of a zip builder run
+type BuildZipResult struct {
+	FilesAdded int
+	FilesInZip int64
+	// Complete is false when the run saved the archive before all the signed documents were added, the next run
+	// resumes from the saved archive
+	Complete bool
 }
 
 // ZipBuilder provides method to build ICLA/CCLA zip
 type ZipBuilder interface {
-	BuildICLAZip(claGroupID string) error
-	BuildCCLAZip(claGroupID string) error
+	BuildICLAZip(ctx context.Context, claGroupID string) (*BuildZipResult, error)
+	BuildCCLAZip(ctx context.Context, claGroupID string) (*BuildZipResult, error)
 }
 
 // NewZipBuilder returns the ZipBuilder
-func NewZipBuilder(awsSession *session.Session, bucketName string) ZipBuilder {
+func NewZipBuilder(awsSession *session.Session, stage string, bucketName string) ZipBuilder {
 	return &Zipper{
-		s3:         s3.New(awsSession),
-		bucketName: bucketName,
+		s3:             s3.New(awsSession),
+		bucketName:     bucketName,
+		signatures:     newSignatureMetadataLookup(dynamodb.New(awsSession), stage),
+		partSize:       DefaultPartSize,
+		maxFilesPerRun: MaxFilesPerRun,
+		deadlineMargin: DeadlineMargin,
 	}
 }
 
@@ -58,230 +86,323 @@ func s3ZipFilepath(claType string, claGroupID string) string {
 	return fmt.Sprintf("contract-group/%s/%s.zip", claGroupID, claType)
 }
 
+func s3ManifestFilepath(claType string, claGroupID string) string {
+	return fmt.Sprintf("contract-group/%s/%s.manifest.csv", claGroupID, claType)
+}
+
 func s3ZipPrefix(claType string, claGroupID string) string {
 	return fmt.Sprintf("contract-group/%s/%s/", claGroupID, claType)
 }
 
 // BuildICLAZip builds icla pdfs zip for cla-group and upload it on s3
-func (z *Zipper) BuildICLAZip(claGroupID string) error {
-	return z.buildZip(ICLA, claGroupID)
+func (z *Zipper) BuildICLAZip(ctx context.Context, claGroupID string) (*BuildZipResult, error) {
+	return z.buildZip(ctx, ICLA, claGroupID)
 }
 
 // BuildCCLAZip builds ccla pdfs zip for cla-group and upload it on s3
-func (z *Zipper) BuildCCLAZip(claGroupID string) error {
-	return z.buildZip(CCLA, claGroupID)
+func (z *Zipper) BuildCCLAZip(ctx context.Context, claGroupID string) (*BuildZipResult, error) {
+	return z.buildZip(ctx, CCLA, claGroupID)
+}
+
+// archiveState is the saved archive and manifest the run appends to
+type archiveState struct {
+	directory    *zipDirectory
+	records      []byte
+	zipETag      *string
+	manifestETag *string
+	manifestSize int64
+	files        *utils.StringSet
 }
 
-func (z *Zipper) buildZip(claType string, claGroupID string) error {
-	f := logrus.Fields{"cla_group_id": claGroupID, "cla_type": claType}
-	// get zip file from s3
-	buff, err := z.getZipFileFromS3(claType, claGroupID)
+// buildZip appends the signed documents missing from the archive. The archive is streamed to S3 with a multipart
+// upload: the bytes of the saved archive are copied server side, followed by the new files and the central
+// directory - only the central directory of the saved archive is downloaded. The manifest listing the archived
+// files is appended the same way.
+func (z *Zipper) buildZip(ctx context.Context, claType string, claGroupID string) (*BuildZipResult, error) {
+	f := logrus.Fields{
+		"functionName":   "v2.signatures.zip_builder.buildZip",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"cla_group_id":   claGroupID,
+		"cla_type":       claType,
+	}
+	zipKey := s3ZipFilepath(claType, claGroupID)
+	manifestKey := s3ManifestFilepath(claType, claGroupID)
+	abortStaleUploads(ctx, z.s3, z.bucketName, zipKey)
+	abortStaleUploads(ctx, z.s3, z.bucketName, manifestKey)
+
+	state, err := z.getArchiveState(ctx, zipKey, manifestKey)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	var files *utils.StringSet
-	if len(buff.Bytes()) != 0 {
-		// read files already present in zip
-		log.Debug("reading files present in zip")
-		files, err = getZipFiles(buff)
-		if err != nil {
-			return err
+
+	log.WithFields(f).Debug("getting s3 files")
+	var pending []*DownloadFileInput
+	err = z.s3.ListObjectsPagesWithContext(ctx, &s3.ListObjectsInput{
+		Bucket: aws.String(z.bucketName),
+		Prefix: aws.String(s3ZipPrefix(claType, claGroupID)),
+	}, func(output *s3.ListObjectsOutput, b bool) bool {
+		for _, obj := range output.Contents {
+			key := utils.StringValue(obj.Key)
+			tmp := strings.Split(key, "/")
+			if len(tmp) != 5 {
+				continue
+			}
+			filename := tmp[4]
+			if state.files.Include(filename) {
+				// skip files which are already present in zip
+				continue
+			}
+			pending = append(pending, &DownloadFileInput{
+				filename:    filename,
+				signatureID: strings.TrimSuffix(filename, ".pdf"),
+				key:         obj.Key,
+			})
 		}
+		return true
+	})
+	if err != nil {
+		return nil, err
 	}
-	log.WithFields(f).Debug("getting zip writer")
-	writer, err := getZipWriter(buff)
+	result := &BuildZipResult{FilesInZip: state.directory.entries, Complete: true}
+	if len(pending) == 0 {
+		log.WithFields(f).Debug("zip file is up to date")
+		return result, nil
+	}
+	batch := pending
+	if len(batch) > z.maxFilesPerRun {
+		batch = batch[:z.maxFilesPerRun]
+		result.Complete = false
+	}
+	log.WithFields(f).Debugf("adding %d of the %d missing files to %s", len(batch), len(pending), zipKey)
+
+	zipWriter, err := newMultipartWriter(ctx, z.s3, z.bucketName, zipKey, z.partSize, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	var zipUpdated bool
-	log.WithFields(f).Debug("getting s3 files")
+	zipCompleted := false
+	defer func() {
+		if !zipCompleted {
+			zipWriter.Abort()
+		}
+	}()
+	if err = zipWriter.copyRange(state.zipETag, 0, state.directory.offset); err != nil {
+		return nil, err
+	}
+
+	appender := newZipAppender(zipWriter, state.directory)
+	entries, stopped, err := z.addFiles(ctx, claType, appender, batch)
+	if err != nil {
+		return nil, err
+	}
+	if stopped {
+		result.Complete = false
+	}
+	if len(entries) == 0 {
+		if stopped {
+			log.WithFields(f).Warn("the deadline was reached before any file was added to the zip")
+			return result, nil
+		}
+		return nil, ErrNoFileArchived
+	}
+	if err = appender.Close(state.records); err != nil {
+		return nil, err
+	}
+	if err = zipWriter.Complete(); err != nil {
+		return nil, err
+	}
+	zipCompleted = true
+
+	result.FilesAdded = len(entries)
+	result.FilesInZip = state.directory.entries + int64(len(entries))
+	if err = z.appendManifest(ctx, manifestKey, state, entries, result.FilesInZip); err != nil {
+		// the entry count does not match anymore, the next run rebuilds the zip and the manifest
+		log.WithFields(f).WithError(err).Warnf("unable to update manifest file %s", manifestKey)
+		return nil, err
+	}
+	log.WithFields(f).Debugf("added %d files to %s, %d files archived", result.FilesAdded, zipKey, result.FilesInZip)
+	return result, nil
+}
+
+// getArchiveState returns the saved archive to append to. A new archive is started when there is none, when it
+// has no manifest or when the manifest does not match the archive (e.g. a run failed in between the uploads).
+func (z *Zipper) getArchiveState(ctx context.Context, zipKey string, manifestKey string) (*archiveState, error) {
+	f := logrus.Fields{
+		"functionName":   "v2.signatures.zip_builder.getArchiveState",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"key":            zipKey,
+	}
+	state := &archiveState{directory: &zipDirectory{}, files: utils.NewStringSet()}
+	zipObject, err := z.headObject(ctx, zipKey)
+	if err != nil || zipObject == nil {
+		return state, err
+	}
+	manifestObject, err := z.headObject(ctx, manifestKey)
+	if err != nil {
+		return nil, err
+	}
+	if manifestObject == nil {
+		log.WithFields(f).Debug("zip file has no manifest, rebuilding it")
+		return state, nil
+	}
+
+	reader := &s3ReaderAt{ctx: ctx, s3: z.s3, bucket: z.bucketName, key: zipKey, etag: zipObject.ETag}
+	directory, err := readZipDirectory(reader, aws.Int64Value(zipObject.ContentLength))
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to read the zip central directory, rebuilding it")
+		return state, nil
+	}
+	manifestEntries := manifestObject.Metadata[EntryCountMetadata]
+	if manifestEntries == nil || *manifestEntries != strconv.FormatInt(directory.entries, 10) {
+		log.WithFields(f).Warnf("manifest lists %s files instead of %d, rebuilding zip", utils.StringValue(manifestEntries), directory.entries)
+		return state, nil
+	}
+
+	log.WithFields(f).Debugf("reading the %d files present in zip", directory.entries)
+	records := make([]byte, directory.size)
+	if _, err = reader.ReadAt(records, directory.offset); err != nil {
+		return nil, err
+	}
+	names, err := directoryNames(records)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		state.files.Add(name)
+	}
+	state.directory = directory
+	state.records = records
+	state.zipETag = zipObject.ETag
+	state.manifestETag = manifestObject.ETag
+	state.manifestSize = aws.Int64Value(manifestObject.ContentLength)
+	return state, nil
+}
+
+// headObject returns the object attributes or nil if the object does not exist
+func (z *Zipper) headObject(ctx context.Context, key string) (*s3.HeadObjectOutput, error) {
+	output, err := z.s3.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(z.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		// HEAD responses have no body, the error code is the status text
+		if aerr, ok := err.(awserr.Error); ok && (aerr.Code() == s3.ErrCodeNoSuchKey || aerr.Code() == "NotFound") {
+			log.Debugf("file %s does not exist on s3", key)
+			return nil, nil
+		}
+		return nil, err
+	}
+	return output, nil
+}
+
+// addFiles downloads the files concurrently and adds them to the archive until the deadline margin is reached,
+// the files which can not be downloaded are left for the next run
+func (z *Zipper) addFiles(ctx context.Context, claType string, appender *zipAppender, files []*DownloadFileInput) ([]*manifestEntry, bool, error) {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	downloaderInputChan := make(chan *DownloadFileInput)
 	downloaderOutputChan := make(chan *FileContent)
 	var wg sync.WaitGroup
 	wg.Add(ParallelDownloader)
 	for i := 1; i <= ParallelDownloader; i++ {
-		go z.downloader(&wg, downloaderInputChan, downloaderOutputChan)
+		go z.downloader(runCtx, &wg, downloaderInputChan, downloaderOutputChan)
 	}
 	go func() {
 		wg.Wait()
 		close(downloaderOutputChan)
 	}()
 	go func() {
-		err = z.s3.ListObjectsPages(&s3.ListObjectsInput{
-			Bucket: aws.String(z.bucketName),
-			Prefix: aws.String(s3ZipPrefix(claType, claGroupID)),
-		}, func(output *s3.ListObjectsOutput, b bool) bool {
-			for _, obj := range output.Contents {
-				key := utils.StringValue(obj.Key)
-				tmp := strings.Split(key, "/")
-				if len(tmp) != 5 {
-					continue
-				}
-				filename := tmp[4]
-				if files != nil && files.Include(filename) {
-					// skip files which are already present in zip
-					log.Debugf("file %s already present in zip", filename)
-					continue
-				}
-				downloaderInputChan <- &DownloadFileInput{
-					filename: filename,
-					key:      obj.Key,
-				}
+		defer close(downloaderInputChan)
+		for _, file := range files {
+			select {
+			case downloaderInputChan <- file:
+			case <-runCtx.Done():
+				return
 			}
-			return true
-		})
-		close(downloaderInputChan)
+		}
 	}()
-	zipUpdated = writeFileToZip(writer, downloaderOutputChan)
+
+	var entries []*manifestEntry
+	for fileContent := range downloaderOutputChan {
+		if z.deadlineReached(ctx) {
+			return entries, true, nil
+		}
+		log.Debugf("Adding file : %s to zip", fileContent.filename)
+		if err := appender.Add(fileContent.filename, fileContent.content); err != nil {
+			return nil, false, err
+		}
+		checksum := sha256.Sum256(fileContent.content)
+		entries = append(entries, newManifestEntry(claType, fileContent.signatureID, fileContent.filename,
+			fileContent.signature, hex.EncodeToString(checksum[:]), len(fileContent.content)))
+	}
+	return entries, false, nil
+}
+
+func (z *Zipper) deadlineReached(ctx context.Context) bool {
+	deadline, ok := ctx.Deadline()
+	return ok && time.Until(deadline) < z.deadlineMargin
+}
+
+// appendManifest appends the rows of the new entries to the saved manifest
+func (z *Zipper) appendManifest(ctx context.Context, manifestKey string, state *archiveState, entries []*manifestEntry, total int64) error {
+	metadata := map[string]*string{EntryCountMetadata: aws.String(strconv.FormatInt(total, 10))}
+	manifestWriter, err := newMultipartWriter(ctx, z.s3, z.bucketName, manifestKey, z.partSize, metadata)
 	if err != nil {
 		return err
 	}
-	writer.Close()
-	if zipUpdated {
-		remoteZipFileKey := s3ZipFilepath(claType, claGroupID)
-		log.Debugf("Uploading zip file %s", remoteZipFileKey)
-		err := z.uploadFile(buff, remoteZipFileKey)
-		if err != nil {
-			log.Warnf("Uploading zip file %s failed. error = %s", remoteZipFileKey, err.Error())
-			return err
-		}
-		log.Debugf("Uploaded zip file %s", remoteZipFileKey)
+	if err = manifestWriter.copyRange(state.manifestETag, 0, state.manifestSize); err == nil {
+		err = writeManifestEntries(manifestWriter, state.manifestSize == 0, entries)
+	}
+	if err == nil {
+		err = manifestWriter.Complete()
 	}
-	return nil
+	if err != nil {
+		manifestWriter.Abort()
+	}
+	return err
 }
 
 // FileContent contains file content of s3 file
 type FileContent struct {
-	buff     *aws.WriteAtBuffer
-	filename string
+	content     []byte
+	filename    string
+	signatureID string
+	signature   *signatureMetadata
 }
 
 // DownloadFileInput is input to downloader
 type DownloadFileInput struct {
-	filename string
-	key      *string
+	filename    string
+	signatureID string
+	key         *string
 }
 
-func writeFileToZip(writer *zip.Writer, filesInput chan *FileContent) bool {
-	var zipUpdated bool
-	for fileContent := range filesInput {
-		filename := fileContent.filename
-		buff := fileContent.buff
-		log.Debugf("Adding file : %s to zip", filename)
-		header := &zip.FileHeader{
-			Name:   filename,
-			Method: zip.Deflate,
-		}
-		header.SetMode(0644)
-		f, err := writer.CreateHeader(header)
-		if err != nil {
-			log.WithField("file", filename).Error("unable to write file header in zip")
-			continue
-		}
-		_, err = f.Write(buff.Bytes())
-		if err != nil {
-			log.WithField("file", filename).Error("unable to write file data in zip")
-			continue
-		}
-		zipUpdated = true
-	}
-	return zipUpdated
-}
-
-func (z *Zipper) downloader(wg *sync.WaitGroup, inputChan chan *DownloadFileInput, outputChan chan *FileContent) {
+func (z *Zipper) downloader(ctx context.Context, wg *sync.WaitGroup, inputChan chan *DownloadFileInput, outputChan chan *FileContent) {
 	defer wg.Done()
 	for in := range inputChan {
 		log.Debugf("Downloading file : %s", in.filename)
-		buff := &aws.WriteAtBuffer{}
-		downloader := s3manager.NewDownloaderWithClient(z.s3)
-		_, err := downloader.Download(buff,
-			&s3.GetObjectInput{
-				Bucket: aws.String(z.bucketName),
-				Key:    in.key,
-			})
+		output, err := z.s3.GetObjectWithContext(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(z.bucketName),
+			Key:    in.key,
+		})
 		if err != nil {
 			log.WithField("key", utils.StringValue(in.key)).Error("unable to download file from s3", err)
 			continue
 		}
-		outputChan <- &FileContent{
-			buff:     buff,
-			filename: in.filename,
-		}
-	}
-}
-
-func getZipFiles(buff *bytes.Buffer) (*utils.StringSet, error) {
-	reader := bytes.NewReader(buff.Bytes())
-	files := utils.NewStringSet()
-	r, err := zip.NewReader(reader, reader.Size())
-	if err != nil {
-		return nil, err
-	}
-	for _, file := range r.File {
-		files.Add(file.Name)
-	}
-	return files, nil
-}
-
-func getZipWriter(buff *bytes.Buffer) (*zip.Writer, error) {
-	var writer *zip.Writer
-	if len(buff.Bytes()) == 0 {
-		writer = zip.NewWriter(buff)
-	} else {
-		reader := bytes.NewReader(buff.Bytes())
-		r, err := zip.NewReader(reader, reader.Size())
+		content, err := ioutil.ReadAll(output.Body)
+		output.Body.Close() // nolint
 		if err != nil {
-			return nil, err
+			log.WithField("key", utils.StringValue(in.key)).Error("unable to download file from s3", err)
+			continue
 		}
-		writer = r.Append(buff)
-	}
-	return writer, nil
-}
-
-func (z *Zipper) getZipFileFromS3(claType string, claGroupID string) (*bytes.Buffer, error) {
-	var buff aws.WriteAtBuffer
-	remoteFileKey := s3ZipFilepath(claType, claGroupID)
-	_, err := z.s3.GetObject(&s3.GetObjectInput{
-		Bucket: aws.String(z.bucketName),
-		Key:    aws.String(remoteFileKey),
-	})
-	if err != nil {
-		aerr, ok := err.(awserr.Error)
-		if ok && aerr.Code() == s3.ErrCodeNoSuchKey {
-			log.Debugf("zip file %s does not exist on s3", remoteFileKey)
-			return bytes.NewBuffer(buff.Bytes()), nil
+		signature, err := z.signatures.getSignatureMetadata(ctx, in.signatureID)
+		if err != nil || signature == nil {
+			log.WithField("signature_id", in.signatureID).Warn("unable to load the signature details of the manifest", err)
+		}
+		select {
+		case outputChan <- &FileContent{content: content, filename: in.filename, signatureID: in.signatureID, signature: signature}:
+		case <-ctx.Done():
+			return
 		}
-		return nil, err
-	}
-	log.Debugf("Downloading zip file %s", remoteFileKey)
-
-	downloader := s3manager.NewDownloaderWithClient(z.s3)
-
-	_, err = downloader.Download(&buff,
-		&s3.GetObjectInput{
-			Bucket: aws.String(z.bucketName),
-			Key:    aws.String(remoteFileKey),
-		})
-	if err != nil {
-		return nil, err
-	}
-	log.Debugf("Downloading zip file %s completed", remoteFileKey)
-	return bytes.NewBuffer(buff.Bytes()), nil
-}
-
-func (z *Zipper) uploadFile(localFileContent *bytes.Buffer, s3ZipFile string) error {
-	uploader := s3manager.NewUploaderWithClient(z.s3)
-	// Upload the file to S3.
-	_, err := uploader.Upload(&s3manager.UploadInput{
-		Bucket: aws.String(z.bucketName),
-		Key:    aws.String(s3ZipFile),
-		Body:   localFileContent,
-	})
-
-	//in case it fails to upload
-	if err != nil {
-		log.Warnf("failed to upload file %s. error = %v", s3ZipFile, err)
-		return err
 	}
-	return nil
 }
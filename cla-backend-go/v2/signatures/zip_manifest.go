@@ -0,0 +1,158 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+)
+
+// EntryCountMetadata is the S3 metadata of the manifest holding the number of archived files, the manifest is
+// rebuilt with the zip when it does not match the zip
+const EntryCountMetadata = "Entry-Count"
+
+// manifestHeader is the header row of the manifest csv
+var manifestHeader = []string{"signature_id", "file_name", "signer", "company", "document_version", "signed_on", "sha256", "size_bytes"}
+
+// signatureMetadata is the signature record subset listed in the manifest
+type signatureMetadata struct {
+	SignatureID                   string `json:"signature_id"`
+	SignatureReferenceName        string `json:"signature_reference_name"`
+	SignatureDocumentMajorVersion string `json:"signature_document_major_version"`
+	SignatureDocumentMinorVersion string `json:"signature_document_minor_version"`
+	UserName                      string `json:"user_name"`
+	SignatoryName                 string `json:"signatory_name"`
+	UserDocusignName              string `json:"user_docusign_name"`
+	SignedOn                      string `json:"signed_on"`
+}
+
+// manifestEntry is a row of the manifest
+type manifestEntry struct {
+	signatureID string
+	filename    string
+	signer      string
+	company     string
+	version     string
+	signedOn    string
+	sha256      string
+	size        int
+}
+
+// newManifestEntry returns the manifest row of the archived file, the signature details are left empty when the
+// signature record is not available
+func newManifestEntry(claType string, signatureID string, filename string, signature *signatureMetadata, sha256 string, size int) *manifestEntry {
+	entry := &manifestEntry{
+		signatureID: signatureID,
+		filename:    filename,
+		sha256:      sha256,
+		size:        size,
+	}
+	if signature == nil {
+		return entry
+	}
+	if claType == CCLA {
+		entry.signer = firstNonEmpty(signature.SignatoryName, signature.UserDocusignName)
+		entry.company = signature.SignatureReferenceName
+	} else {
+		entry.signer = firstNonEmpty(signature.UserName, signature.SignatureReferenceName, signature.UserDocusignName)
+	}
+	if signature.SignatureDocumentMajorVersion != "" {
+		entry.version = fmt.Sprintf("%s.%s", signature.SignatureDocumentMajorVersion, signature.SignatureDocumentMinorVersion)
+	}
+	entry.signedOn = signature.SignedOn
+	return entry
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// writeManifestEntries writes the csv rows, with the header row for a new manifest
+func writeManifestEntries(w io.Writer, header bool, entries []*manifestEntry) error {
+	writer := csv.NewWriter(w)
+	if header {
+		if err := writer.Write(manifestHeader); err != nil {
+			return err
+		}
+	}
+	for _, entry := range entries {
+		err := writer.Write([]string{
+			entry.signatureID,
+			entry.filename,
+			entry.signer,
+			entry.company,
+			entry.version,
+			entry.signedOn,
+			entry.sha256,
+			strconv.Itoa(entry.size),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// signatureMetadataLookup loads the signature details of the manifest from the signatures table
+type signatureMetadataLookup struct {
+	dynamoDBClient dynamodbiface.DynamoDBAPI
+	tableName      string
+}
+
+func newSignatureMetadataLookup(dynamoDBClient dynamodbiface.DynamoDBAPI, stage string) *signatureMetadataLookup {
+	return &signatureMetadataLookup{
+		dynamoDBClient: dynamoDBClient,
+		tableName:      fmt.Sprintf("cla-%s-signatures", stage),
+	}
+}
+
+// getSignatureMetadata returns the signature details, nil if the signature does not exist
+func (l *signatureMetadataLookup) getSignatureMetadata(ctx context.Context, signatureID string) (*signatureMetadata, error) {
+	projection := expression.NamesList(
+		expression.Name("signature_id"),
+		expression.Name("signature_reference_name"),
+		expression.Name("signature_document_major_version"),
+		expression.Name("signature_document_minor_version"),
+		expression.Name("user_name"),
+		expression.Name("signatory_name"),
+		expression.Name("user_docusign_name"),
+		expression.Name("signed_on"),
+	)
+	expr, err := expression.NewBuilder().WithProjection(projection).Build()
+	if err != nil {
+		return nil, err
+	}
+	output, err := l.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName:                aws.String(l.tableName),
+		Key:                      map[string]*dynamodb.AttributeValue{"signature_id": {S: aws.String(signatureID)}},
+		ExpressionAttributeNames: expr.Names(),
+		ProjectionExpression:     expr.Projection(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(output.Item) == 0 {
+		return nil, nil
+	}
+	var signature signatureMetadata
+	if err = dynamodbattribute.UnmarshalMap(output.Item, &signature); err != nil {
+		return nil, err
+	}
+	return &signature, nil
+}
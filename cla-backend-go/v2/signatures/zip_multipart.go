@@ -0,0 +1,232 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// multipart upload limits
+const (
+	// DefaultPartSize is the size of the uploaded parts, S3 requires at least 5MB except for the last part
+	DefaultPartSize = 16 * 1024 * 1024
+	// copyPartSize is the size of the parts copied from the previous version of the object
+	copyPartSize = 512 * 1024 * 1024
+	// staleUploadAge is the age after which an unfinished upload is considered abandoned by a timed out run
+	staleUploadAge = time.Hour
+)
+
+// errPendingBytes is returned when the server side copy does not start on a part boundary
+var errPendingBytes = errors.New("copy of the object range must start on a part boundary")
+
+// multipartWriter streams an S3 object through a multipart upload, only the current part is kept in memory
+type multipartWriter struct {
+	ctx      context.Context
+	s3       s3iface.S3API
+	bucket   string
+	key      string
+	uploadID *string
+	partSize int
+	buff     bytes.Buffer
+	parts    []*s3.CompletedPart
+}
+
+// newMultipartWriter starts the multipart upload of the object
+func newMultipartWriter(ctx context.Context, s3Client s3iface.S3API, bucket, key string, partSize int, metadata map[string]*string) (*multipartWriter, error) {
+	output, err := s3Client.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		Metadata: metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &multipartWriter{
+		ctx:      ctx,
+		s3:       s3Client,
+		bucket:   bucket,
+		key:      key,
+		uploadID: output.UploadId,
+		partSize: partSize,
+	}, nil
+}
+
+// Write buffers the bytes and uploads a part each time the buffer reaches the part size
+func (m *multipartWriter) Write(p []byte) (int, error) {
+	n, _ := m.buff.Write(p)
+	if m.buff.Len() >= m.partSize {
+		if err := m.flush(); err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
+
+func (m *multipartWriter) flush() error {
+	partNumber := aws.Int64(int64(len(m.parts) + 1))
+	output, err := m.s3.UploadPartWithContext(m.ctx, &s3.UploadPartInput{
+		Bucket:     aws.String(m.bucket),
+		Key:        aws.String(m.key),
+		UploadId:   m.uploadID,
+		PartNumber: partNumber,
+		Body:       bytes.NewReader(m.buff.Bytes()),
+	})
+	if err != nil {
+		return err
+	}
+	m.parts = append(m.parts, &s3.CompletedPart{ETag: output.ETag, PartNumber: partNumber})
+	m.buff.Reset()
+	return nil
+}
+
+// copyRange writes the [start, end) range of the previous version of the object, the large ranges are copied
+// server side and the remainder smaller than a part is downloaded
+func (m *multipartWriter) copyRange(etag *string, start, end int64) error {
+	if m.buff.Len() > 0 {
+		return errPendingBytes
+	}
+	for end-start >= int64(m.partSize) {
+		size := end - start
+		if size > copyPartSize {
+			size = copyPartSize
+		}
+		partNumber := aws.Int64(int64(len(m.parts) + 1))
+		output, err := m.s3.UploadPartCopyWithContext(m.ctx, &s3.UploadPartCopyInput{
+			Bucket:            aws.String(m.bucket),
+			Key:               aws.String(m.key),
+			UploadId:          m.uploadID,
+			PartNumber:        partNumber,
+			CopySource:        aws.String(fmt.Sprintf("%s/%s", m.bucket, m.key)),
+			CopySourceIfMatch: etag,
+			CopySourceRange:   aws.String(fmt.Sprintf("bytes=%d-%d", start, start+size-1)),
+		})
+		if err != nil {
+			return err
+		}
+		m.parts = append(m.parts, &s3.CompletedPart{ETag: output.CopyPartResult.ETag, PartNumber: partNumber})
+		start += size
+	}
+	if start == end {
+		return nil
+	}
+
+	body, err := getObjectRange(m.ctx, m.s3, m.bucket, m.key, etag, start, end)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	_, err = io.Copy(m, body)
+	return err
+}
+
+// Complete uploads the last part and completes the upload
+func (m *multipartWriter) Complete() error {
+	if m.buff.Len() > 0 || len(m.parts) == 0 {
+		if err := m.flush(); err != nil {
+			return err
+		}
+	}
+	_, err := m.s3.CompleteMultipartUploadWithContext(m.ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(m.bucket),
+		Key:             aws.String(m.key),
+		UploadId:        m.uploadID,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: m.parts},
+	})
+	return err
+}
+
+// Abort discards the uploaded parts, the object is left unchanged
+func (m *multipartWriter) Abort() {
+	// the run context may be expired already
+	_, err := m.s3.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(m.bucket),
+		Key:      aws.String(m.key),
+		UploadId: m.uploadID,
+	})
+	if err != nil {
+		log.WithFields(logrus.Fields{"key": m.key}).WithError(err).Warn("unable to abort multipart upload")
+	}
+}
+
+// getObjectRange returns the [start, end) range of the object, the etag makes sure the object did not change
+// since it was inspected
+func getObjectRange(ctx context.Context, s3Client s3iface.S3API, bucket, key string, etag *string, start, end int64) (io.ReadCloser, error) {
+	output, err := s3Client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket:  aws.String(bucket),
+		Key:     aws.String(key),
+		IfMatch: etag,
+		Range:   aws.String(fmt.Sprintf("bytes=%d-%d", start, end-1)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.Body, nil
+}
+
+// s3ReaderAt reads an object with ranged requests
+type s3ReaderAt struct {
+	ctx    context.Context
+	s3     s3iface.S3API
+	bucket string
+	key    string
+	etag   *string
+}
+
+// ReadAt implements io.ReaderAt
+func (r *s3ReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	body, err := getObjectRange(r.ctx, r.s3, r.bucket, r.key, r.etag, off, off+int64(len(p)))
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+	return io.ReadFull(body, p)
+}
+
+// abortStaleUploads aborts the multipart uploads of the key left behind by the runs which timed out, S3 keeps
+// (and bills) their parts until they are aborted
+func abortStaleUploads(ctx context.Context, s3Client s3iface.S3API, bucket, key string) {
+	f := logrus.Fields{
+		"functionName":   "v2.signatures.zip_multipart.abortStaleUploads",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"key":            key,
+	}
+	output, err := s3Client.ListMultipartUploadsWithContext(ctx, &s3.ListMultipartUploadsInput{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(key),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to list multipart uploads")
+		return
+	}
+	for _, upload := range output.Uploads {
+		if utils.StringValue(upload.Key) != key || upload.Initiated == nil || time.Since(*upload.Initiated) < staleUploadAge {
+			continue
+		}
+		log.WithFields(f).Debugf("aborting multipart upload initiated at %s", upload.Initiated)
+		_, err = s3Client.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(bucket),
+			Key:      aws.String(key),
+			UploadId: upload.UploadId,
+		})
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("unable to abort multipart upload")
+		}
+	}
+}
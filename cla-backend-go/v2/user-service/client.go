@@ -11,11 +11,13 @@ import (
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
 	"github.com/sirupsen/logrus"
 
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	platform_client "github.com/communitybridge/easycla/cla-backend-go/v2/platform-client"
 	"github.com/communitybridge/easycla/cla-backend-go/v2/user-service/client/staff"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -35,9 +37,10 @@ var (
 
 // Client is client for user_service
 type Client struct {
-	cl       *client.UserService
-	apiKey   string
-	apiGwURL string
+	cl         *client.UserService
+	httpClient *http.Client
+	apiKey     string
+	apiGwURL   string
 }
 
 var (
@@ -47,14 +50,12 @@ var (
 // InitClient initializes the user_service client
 func InitClient(APIGwURL string, apiKey string) {
 	APIGwURL = strings.ReplaceAll(APIGwURL, "https://", "")
+	dependency := platform_client.NewDependency(platform_client.DefaultConfig("user-service", 10*time.Second))
 	userServiceClient = &Client{
-		apiKey:   apiKey,
-		apiGwURL: APIGwURL,
-		cl: client.NewHTTPClientWithConfig(strfmt.Default, &client.TransportConfig{
-			Host:     APIGwURL,
-			BasePath: "user-service/v1",
-			Schemes:  []string{"https"},
-		}),
+		apiKey:     apiKey,
+		apiGwURL:   APIGwURL,
+		httpClient: dependency.HTTPClient(),
+		cl:         client.New(dependency.SwaggerTransport(APIGwURL, "user-service/v1"), strfmt.Default),
 	}
 }
 
@@ -141,7 +142,7 @@ func (usc *Client) SearchUsers(firstName string, lastName string, email string)
 	request.Header.Set("Authorization", "Bearer "+tok)
 	request.Header.Set("Content-Type", "application/json")
 
-	response, err := http.DefaultClient.Do(request)
+	response, err := usc.httpClient.Do(request)
 	if err != nil {
 		log.WithFields(f).WithError(err).Warn("problem searching user")
 		return nil, err
@@ -3,14 +3,58 @@
 package authorizer
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"strings"
+	"sync"
+	"time"
 
-	auth0 "github.com/auth0-community/go-auth0"
 	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
 )
 
+const (
+	// keysCacheTTL is how long the fetched signing keys of an issuer are used before they are fetched again
+	keysCacheTTL = time.Hour
+	// keysRefreshInterval limits how often the signing keys are fetched again for a token signed with an unknown key
+	keysRefreshInterval = time.Minute
+)
+
+var (
+	// ErrTokenNotFound is returned when the request has no bearer token
+	ErrTokenNotFound = errors.New("token not found")
+	// ErrUntrustedIssuer is returned when the issuer of the token is not one of the trusted issuers
+	ErrUntrustedIssuer = errors.New("untrusted issuer")
+	// ErrInvalidAudience is returned when the token was not issued for one of the audiences of the issuer
+	ErrInvalidAudience = errors.New("invalid audience")
+	// ErrKeyNotFound is returned when the token is not signed with one of the signing keys of the issuer
+	ErrKeyNotFound = errors.New("unable to find appropriate key")
+)
+
+// IssuerConfig is the configuration of a trusted OIDC issuer, the OIDC_ISSUERS environment variable is a JSON array of
+// the configurations, e.g. [{"issuer": "https://sso.example.org/", "audiences": ["easycla"]}]
+type IssuerConfig struct {
+	// Issuer is the iss claim of the tokens
+	Issuer string `json:"issuer"`
+	// Audiences are the accepted aud claim values, at least one is required - the tokens of the issuer for the
+	// other applications are rejected
+	Audiences []string `json:"audiences"`
+	// Algorithms are the accepted signing algorithms, RS256 when empty
+	Algorithms []string `json:"algorithms"`
+	// DiscoveryURL is the OpenID configuration document of the issuer, the /.well-known/openid-configuration of the issuer when empty
+	DiscoveryURL string `json:"discovery_url"`
+	// JWKSURL is the signing keys URL - when set, the discovery document isn't fetched
+	JWKSURL string `json:"jwks_url"`
+	// the claims mapped to the email, the email_verified and the sub claims, the standard claims when empty
+	EmailClaim         string `json:"email_claim"`
+	EmailVerifiedClaim string `json:"email_verified_claim"`
+	SubjectClaim       string `json:"subject_claim"`
+}
+
 // ValidatorMaker is the interface for interacting with the validator layer.
 type ValidatorMaker interface {
 	NewTokenValidator() (TokenValidator, error)
@@ -24,42 +68,324 @@ func NewValidatorMaker() *validatorContainer {
 	return &validatorContainer{}
 }
 
-// NewTokenValidator creates a token validator, reading configuration from the environment.
+// NewTokenValidator creates a token validator, reading configuration from the environment. The auth0 tenant and the
+// OIDC_ISSUERS are trusted.
 func (vc *validatorContainer) NewTokenValidator() (TokenValidator, error) {
 	fields := make(map[string]interface{})
 	fields["function_name"] = "authorizer.validator.NewTokenValidator"
 	log.Print(fields, "Entered function")
 
+	var issuers []IssuerConfig
 	authDomain := os.Getenv("AUTH0_DOMAIN")
-	if len(authDomain) == 0 {
-		errMsg := "couldn't find auth0 URI"
-		log.Print(fields, errMsg)
-		return nil, errors.New(errMsg)
+	if len(authDomain) != 0 {
+		aud := os.Getenv("AUTH0_CLIENT_ID")
+		if len(aud) == 0 {
+			errMsg := "couldn't find auth0 client id"
+			log.Print(fields, errMsg)
+			return nil, errors.New(errMsg)
+		}
+		url := "https://" + authDomain + "/"
+		issuers = append(issuers, IssuerConfig{
+			Issuer:    url,
+			Audiences: []string{aud},
+			JWKSURL:   url + ".well-known/jwks.json",
+		})
+	}
+
+	if oidcIssuers := os.Getenv("OIDC_ISSUERS"); len(oidcIssuers) != 0 {
+		var configs []IssuerConfig
+		if err := json.Unmarshal([]byte(oidcIssuers), &configs); err != nil {
+			errMsg := fmt.Sprintf("invalid OIDC_ISSUERS: %v", err)
+			log.Print(fields, errMsg)
+			return nil, errors.New(errMsg)
+		}
+		issuers = append(issuers, configs...)
 	}
 
-	url := "https://" + authDomain + "/"
-	aud := os.Getenv("AUTH0_CLIENT_ID")
-	if len(aud) == 0 {
-		errMsg := "couldn't find auth0 client id"
+	if len(issuers) == 0 {
+		errMsg := "couldn't find auth0 URI or OIDC issuers"
 		log.Print(fields, errMsg)
 		return nil, errors.New(errMsg)
 	}
-	return vc.createTokenValidator(url, aud), nil
+	return vc.createTokenValidator(issuers, &http.Client{Timeout: 10 * time.Second})
 }
 
-// newTokenValidator creates a token validator,
-func (vc *validatorContainer) createTokenValidator(domain string, audience string) TokenValidator {
+// createTokenValidator creates a token validator of the tokens of the specified issuers. The signing keys of the
+// issuers are fetched on the first token and cached - they are fetched again after an hour or when a token is signed
+// with an unknown key, which is the case once the issuer rotates its keys.
+func (vc *validatorContainer) createTokenValidator(issuers []IssuerConfig, client *http.Client) (TokenValidator, error) {
 	fields := make(map[string]interface{})
-	fields["function_name"] = "authorizer.validator.NewTokenValidator"
+	fields["function_name"] = "authorizer.validator.createTokenValidator"
 	log.Print(fields, "Entered function")
 
-	uri := domain + ".well-known/jwks.json"
-	client := auth0.NewJWKClient(auth0.JWKClientOptions{URI: uri}, nil)
-	log.Print(fields, "Initialized JWKClient")
+	validator := &oidcValidator{}
+	for _, config := range issuers {
+		if len(config.Issuer) == 0 {
+			return nil, errors.New("missing issuer")
+		}
+		issuer := strings.TrimSuffix(config.Issuer, "/")
+		if validator.issuer(issuer) != nil {
+			return nil, fmt.Errorf("duplicate issuer: %s", config.Issuer)
+		}
+		if len(config.Audiences) == 0 {
+			return nil, fmt.Errorf("missing audiences for the issuer: %s", config.Issuer)
+		}
+
+		if len(config.Algorithms) == 0 {
+			config.Algorithms = []string{string(jose.RS256)}
+		}
+		algorithms := map[string]bool{}
+		for _, algorithm := range config.Algorithms {
+			switch jose.SignatureAlgorithm(algorithm) {
+			case jose.RS256, jose.RS384, jose.RS512, jose.PS256, jose.PS384, jose.PS512, jose.ES256, jose.ES384, jose.ES512:
+				algorithms[algorithm] = true
+			default:
+				return nil, fmt.Errorf("unsupported algorithm: %s for the issuer: %s", algorithm, config.Issuer)
+			}
+		}
 
-	configuration := auth0.NewConfiguration(client, []string{audience}, domain, jose.RS256)
-	validator := auth0.NewValidator(configuration, nil)
+		if len(config.DiscoveryURL) == 0 {
+			config.DiscoveryURL = issuer + "/.well-known/openid-configuration"
+		}
+		if len(config.EmailClaim) == 0 {
+			config.EmailClaim = "email"
+		}
+		if len(config.EmailVerifiedClaim) == 0 {
+			config.EmailVerifiedClaim = "email_verified"
+		}
+		if len(config.SubjectClaim) == 0 {
+			config.SubjectClaim = "sub"
+		}
+
+		validator.issuers = append(validator.issuers, &trustedIssuer{
+			name:       issuer,
+			config:     config,
+			algorithms: algorithms,
+			keys:       &keySet{client: client, issuer: issuer, discoveryURL: config.DiscoveryURL, jwksURL: config.JWKSURL},
+		})
+		log.Print(fields, "Trusting the issuer: "+config.Issuer)
+	}
 
 	log.Print(fields, "Successfully created validator")
-	return validator
+	return validator, nil
+}
+
+// oidcValidator validates the tokens of the trusted issuers
+type oidcValidator struct {
+	issuers []*trustedIssuer
+}
+
+type trustedIssuer struct {
+	name       string
+	config     IssuerConfig
+	algorithms map[string]bool
+	keys       *keySet
+}
+
+// ValidateRequest validates the bearer token of the request
+func (v *oidcValidator) ValidateRequest(r *http.Request) (*jwt.JSONWebToken, error) {
+	header := r.Header.Get("Authorization")
+	if len(header) <= 7 || !strings.EqualFold(header[:7], "Bearer ") {
+		return nil, ErrTokenNotFound
+	}
+	token, err := jwt.ParseSigned(header[7:])
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err = v.verify(token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// Claims verifies the token and reads its claims into the values - the email, the email_verified and the sub claims
+// of a claims map are set from the claims mapped by the issuer
+func (v *oidcValidator) Claims(r *http.Request, token *jwt.JSONWebToken, values ...interface{}) error {
+	issuer, key, err := v.verify(token)
+	if err != nil {
+		return err
+	}
+	if err = token.Claims(key, values...); err != nil {
+		return err
+	}
+	for _, value := range values {
+		if claims, ok := value.(*map[string]interface{}); ok && *claims != nil {
+			mapping := map[string]string{
+				"email":          issuer.config.EmailClaim,
+				"email_verified": issuer.config.EmailVerifiedClaim,
+				"sub":            issuer.config.SubjectClaim,
+			}
+			mapped := map[string]interface{}{}
+			for claim, from := range mapping {
+				if claimValue, exists := (*claims)[from]; exists {
+					mapped[claim] = claimValue
+				} else {
+					delete(*claims, claim)
+				}
+			}
+			for claim, claimValue := range mapped {
+				(*claims)[claim] = claimValue
+			}
+		}
+	}
+	return nil
+}
+
+// verify checks the token is signed by a trusted issuer, is valid now and was issued for one of the audiences of the
+// issuer - it returns the issuer and the signing key of the token
+func (v *oidcValidator) verify(token *jwt.JSONWebToken) (*trustedIssuer, interface{}, error) {
+	if len(token.Headers) != 1 {
+		return nil, nil, errors.New("unexpected token headers")
+	}
+	unverified := jwt.Claims{}
+	if err := token.UnsafeClaimsWithoutVerification(&unverified); err != nil {
+		return nil, nil, err
+	}
+	issuer := v.issuer(strings.TrimSuffix(unverified.Issuer, "/"))
+	if issuer == nil {
+		return nil, nil, ErrUntrustedIssuer
+	}
+	if !issuer.algorithms[token.Headers[0].Algorithm] {
+		return nil, nil, fmt.Errorf("unexpected signing algorithm: %s", token.Headers[0].Algorithm)
+	}
+	key, err := issuer.keys.key(token.Headers[0].KeyID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	claims := jwt.Claims{}
+	if err = token.Claims(key, &claims); err != nil {
+		return nil, nil, err
+	}
+	if err = claims.Validate(jwt.Expected{Time: time.Now()}); err != nil {
+		return nil, nil, err
+	}
+	if !issuer.allowsAudience(claims.Audience) {
+		return nil, nil, ErrInvalidAudience
+	}
+	return issuer, key, nil
+}
+
+func (v *oidcValidator) issuer(name string) *trustedIssuer {
+	for _, issuer := range v.issuers {
+		if issuer.name == name {
+			return issuer
+		}
+	}
+	return nil
+}
+
+// allowsAudience returns true if the aud claim has one of the audiences of the issuer
+func (ti *trustedIssuer) allowsAudience(audience jwt.Audience) bool {
+	for _, aud := range ti.config.Audiences {
+		if audience.Contains(aud) {
+			return true
+		}
+	}
+	return false
+}
+
+// keySet holds the cached signing keys of an issuer
+type keySet struct {
+	client       *http.Client
+	issuer       string
+	discoveryURL string
+
+	lock            sync.Mutex
+	jwksURL         string
+	keys            *jose.JSONWebKeySet
+	fetched         time.Time
+	lastMissRefresh time.Time
+}
+
+// key returns the signing key with the specified key ID
+func (ks *keySet) key(kid string) (interface{}, error) {
+	fields := make(map[string]interface{})
+	fields["function_name"] = "authorizer.validator.key"
+	ks.lock.Lock()
+	defer ks.lock.Unlock()
+
+	refreshed := false
+	if ks.keys == nil || time.Since(ks.fetched) > keysCacheTTL {
+		if err := ks.refresh(); err != nil {
+			if ks.keys == nil {
+				return nil, err
+			}
+			log.Print(fields, "unable to refresh the signing keys - using the cached keys: "+err.Error())
+		} else {
+			refreshed = true
+		}
+	}
+	if key, ok := ks.lookup(kid); ok {
+		return key, nil
+	}
+
+	// the issuer may have rotated its keys since they were fetched
+	if refreshed || time.Since(ks.lastMissRefresh) < keysRefreshInterval {
+		return nil, ErrKeyNotFound
+	}
+	ks.lastMissRefresh = time.Now()
+	if err := ks.refresh(); err != nil {
+		return nil, err
+	}
+	if key, ok := ks.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, ErrKeyNotFound
+}
+
+// lookup returns the cached key - a token without a key ID is accepted when the issuer has a single key
+func (ks *keySet) lookup(kid string) (interface{}, bool) {
+	if len(kid) == 0 && len(ks.keys.Keys) == 1 {
+		return ks.keys.Keys[0].Key, true
+	}
+	for _, key := range ks.keys.Key(kid) {
+		if len(key.Use) == 0 || key.Use == "sig" {
+			return key.Key, true
+		}
+	}
+	return nil, false
+}
+
+// refresh fetches the signing keys, the signing keys URL is read from the discovery document when not configured
+func (ks *keySet) refresh() error {
+	if len(ks.jwksURL) == 0 {
+		var document struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := ks.getJSON(ks.discoveryURL, &document); err != nil {
+			return err
+		}
+		if strings.TrimSuffix(document.Issuer, "/") != ks.issuer {
+			return fmt.Errorf("discovery document issuer: %s does not match the issuer: %s", document.Issuer, ks.issuer)
+		}
+		if len(document.JWKSURI) == 0 {
+			return errors.New("discovery document is missing the jwks_uri")
+		}
+		ks.jwksURL = document.JWKSURI
+	}
+
+	keys := jose.JSONWebKeySet{}
+	if err := ks.getJSON(ks.jwksURL, &keys); err != nil {
+		return err
+	}
+	if len(keys.Keys) == 0 {
+		return fmt.Errorf("no signing keys found at: %s", ks.jwksURL)
+	}
+	ks.keys = &keys
+	ks.fetched = time.Now()
+	return nil
+}
+
+func (ks *keySet) getJSON(url string, out interface{}) error {
+	resp, err := ks.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %d fetching: %s", resp.StatusCode, url)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
 }
@@ -0,0 +1,165 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+package authorizer
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+)
+
+// testIssuer is a stand-in identity provider serving the discovery document and the signing keys
+type testIssuer struct {
+	server      *httptest.Server
+	url         string
+	keys        []jose.JSONWebKey
+	keyRequests int
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	issuer := &testIssuer{}
+	issuer.rotateKey(t)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]string{"issuer": issuer.url, "jwks_uri": issuer.server.URL + "/jwks"}))
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		issuer.keyRequests++
+		keys := jose.JSONWebKeySet{}
+		for _, key := range issuer.keys {
+			keys.Keys = append(keys.Keys, key.Public())
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(keys))
+	})
+	issuer.server = httptest.NewServer(mux)
+	issuer.url = issuer.server.URL + "/"
+	return issuer
+}
+
+// rotateKey makes a new key the signing key, the previous key is still published
+func (ti *testIssuer) rotateKey(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	key := jose.JSONWebKey{Key: privateKey, KeyID: fmt.Sprintf("key-%d", len(ti.keys)+1), Algorithm: string(jose.RS256), Use: "sig"}
+	ti.keys = append([]jose.JSONWebKey{key}, ti.keys...)
+}
+
+func (ti *testIssuer) sign(t *testing.T, claims map[string]interface{}) string {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: ti.keys[0]}, nil)
+	assert.NoError(t, err)
+	payload := map[string]interface{}{
+		"iss":            ti.url,
+		"sub":            "auth0|jdoe",
+		"email":          "jdoe@example.org",
+		"email_verified": true,
+		"exp":            time.Now().Add(time.Hour).Unix(),
+	}
+	for name, value := range claims {
+		payload[name] = value
+	}
+	token, err := jwt.Signed(signer).Claims(payload).CompactSerialize()
+	assert.NoError(t, err)
+	return token
+}
+
+func TestTokenValidatorIssuers(t *testing.T) {
+	corporate := newTestIssuer(t)
+	defer corporate.server.Close()
+	community := newTestIssuer(t)
+	defer community.server.Close()
+	untrusted := newTestIssuer(t)
+	defer untrusted.server.Close()
+
+	assert.NoError(t, os.Setenv("AUTH0_DOMAIN", ""))
+	assert.NoError(t, os.Setenv("OIDC_ISSUERS", fmt.Sprintf(
+		`[{"issuer": %q, "audiences": ["easycla"]}, {"issuer": %q, "audiences": ["community"], "email_claim": "mail", "email_verified_claim": "mail_verified"}]`,
+		corporate.url, community.url)))
+	defer os.Unsetenv("OIDC_ISSUERS") // nolint
+	validator, err := NewValidatorMaker().NewTokenValidator()
+	if !assert.NoError(t, err) {
+		return
+	}
+	usecases := NewUsecases(validator)
+
+	tokenInfo, err := usecases.ValidateToken("Bearer " + corporate.sign(t, map[string]interface{}{"aud": "easycla"}))
+	assert.NoError(t, err)
+	assert.Equal(t, TokenInfo{Email: "jdoe@example.org", EmailVerified: true, Subject: "auth0|jdoe"}, tokenInfo)
+
+	// the claims are mapped with the claim mapping of the issuer
+	tokenInfo, err = usecases.ValidateToken("Bearer " + community.sign(t, map[string]interface{}{
+		"aud":           []string{"portal", "community"},
+		"email":         "unverified@example.org",
+		"mail":          "jroe@example.org",
+		"mail_verified": false,
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, TokenInfo{Email: "jroe@example.org", EmailVerified: false, Subject: "auth0|jdoe"}, tokenInfo)
+	_, err = usecases.ValidateToken("Bearer " + community.sign(t, map[string]interface{}{"aud": "community"}))
+	assert.EqualError(t, err, "token missing email claim")
+
+	// the tokens issued for the other applications of the issuer are rejected
+	_, err = usecases.ValidateToken("Bearer " + corporate.sign(t, map[string]interface{}{"aud": "portal"}))
+	assert.Equal(t, ErrInvalidAudience, err)
+	_, err = usecases.ValidateToken("Bearer " + corporate.sign(t, nil))
+	assert.Equal(t, ErrInvalidAudience, err)
+	_, err = usecases.ValidateToken("Bearer " + community.sign(t, map[string]interface{}{"aud": "easycla"}))
+	assert.Equal(t, ErrInvalidAudience, err)
+	_, err = usecases.ValidateToken("Bearer " + untrusted.sign(t, nil))
+	assert.Equal(t, ErrUntrustedIssuer, err)
+	_, err = usecases.ValidateToken("Bearer " + untrusted.sign(t, map[string]interface{}{"iss": community.url}))
+	assert.Error(t, err)
+	_, err = usecases.ValidateToken("Bearer " + corporate.sign(t, map[string]interface{}{
+		"aud": "easycla",
+		"exp": time.Now().Add(-time.Hour).Unix(),
+	}))
+	assert.Equal(t, jwt.ErrExpired, err)
+	_, err = usecases.ValidateToken(corporate.sign(t, map[string]interface{}{"aud": "easycla"}))
+	assert.Equal(t, ErrTokenNotFound, err)
+}
+
+func TestTokenValidatorKeyRotation(t *testing.T) {
+	issuer := newTestIssuer(t)
+	defer issuer.server.Close()
+
+	// every issuer requires an audience
+	_, err := NewValidatorMaker().createTokenValidator([]IssuerConfig{{Issuer: issuer.url}}, http.DefaultClient)
+	assert.EqualError(t, err, "missing audiences for the issuer: "+issuer.url)
+
+	validator, err := NewValidatorMaker().createTokenValidator([]IssuerConfig{{Issuer: issuer.url, Audiences: []string{"easycla"}}}, http.DefaultClient)
+	if !assert.NoError(t, err) {
+		return
+	}
+	usecases := NewUsecases(validator)
+	claims := map[string]interface{}{"aud": "easycla"}
+
+	before := "Bearer " + issuer.sign(t, claims)
+	for i := 0; i < 3; i++ {
+		_, err = usecases.ValidateToken(before)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 1, issuer.keyRequests)
+
+	// a token signed with the new key makes the validator fetch the keys again
+	issuer.rotateKey(t)
+	_, err = usecases.ValidateToken("Bearer " + issuer.sign(t, claims))
+	assert.NoError(t, err)
+	_, err = usecases.ValidateToken(before)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, issuer.keyRequests)
+
+	// the keys aren't fetched again for each token signed with an unknown key
+	issuer.rotateKey(t)
+	_, err = usecases.ValidateToken("Bearer " + issuer.sign(t, claims))
+	assert.Equal(t, ErrKeyNotFound, err)
+	assert.Equal(t, 2, issuer.keyRequests)
+}
@@ -0,0 +1,337 @@
+# Copyright The Linux Foundation and each contributor to CommunityBridge.
+# SPDX-License-Identifier: MIT
+
+"""
+Matching of the CCLA approval list entries, the same rules the Go backend applies (signatures/approval_rules.go).
+
+Besides literal values, an approval list entry may be:
+
+- a glob pattern using * and ?, e.g. *@example.com, jane.*@example.com or bot-?
+- for the domains, a *. or . prefix, e.g. *.example.com or .example.com, matching the domain and its subdomains
+- a regular expression with the regex: prefix, e.g. regex:^[a-z]+@(eng|ops)\\.example\\.com$
+- a deny entry with the ! prefix, e.g. !contractor@example.com or !*.contractors.example.com
+
+Matching is case insensitive. Deny entries override allow entries in any of the lists.
+
+The regular expressions are evaluated with the backtracking re module, they are limited to the syntax both backends
+evaluate the same way and can't repeat a repetition or an alternation - see check_portable_regex.
+"""
+
+import re
+from typing import Iterable, List, Optional
+
+import cla
+
+APPROVAL_DENY_PREFIX = '!'
+APPROVAL_REGEX_PREFIX = 'regex:'
+
+# The limits of the regular expressions, the same as the Go backend
+MAX_APPROVAL_REGEX_LENGTH = 256
+MAX_APPROVAL_REGEX_REPEATS = 3
+MAX_APPROVAL_REGEX_COUNT = 1000
+
+REGEX_ESCAPE_PUNCTUATION = '!"#$%&\'()*+,-./:;<=>?@[\\]^_`{|}~'
+REGEX_ESCAPE_CLASSES = 'dDwWsS'
+
+REGEX_ATOM_NONE = 0
+REGEX_ATOM_SIMPLE = 1
+REGEX_ATOM_REPEATED_GROUP = 2
+REGEX_ATOM_QUANTIFIED = 3
+
+EMAIL_CRITERIA = 'Email Criteria'
+EMAIL_DOMAIN_CRITERIA = 'Email Domain Criteria'
+GITHUB_USERNAME_CRITERIA = 'GitHubUsername'
+GITHUB_ORG_CRITERIA = 'GitHub Org Criteria'
+
+
+def check_portable_regex(expr: str):
+    """
+    Checks the approval list regular expression uses the syntax the Go and the Python backends evaluate the same way -
+    literals, ., character classes without the POSIX classes, the \\d \\w \\s escapes, ^ and $, (...) and (?:...)
+    groups, | and the * + ? {n,m} repetitions. A repetition can't apply to a group which repeats or alternates and the
+    number of repetitions is limited, the expression can't backtrack catastrophically. The same checks as
+    checkPortableRegex of the Go backend (signatures/approval_rules.go).
+
+    :raises ValueError: if the expression isn't supported
+    """
+    if len(expr) > MAX_APPROVAL_REGEX_LENGTH:
+        raise ValueError(f'longer than {MAX_APPROVAL_REGEX_LENGTH} characters')
+
+    # each group records whether it repeats and whether it alternates
+    groups = [[False, False]]
+    last = REGEX_ATOM_NONE
+    repeats = 0
+    i = 0
+    while i < len(expr):
+        c = expr[i]
+        if c == '\\':
+            if i + 1 == len(expr):
+                raise ValueError('trailing backslash')
+            i += 1
+            if not _portable_regex_escape(expr[i]):
+                raise ValueError(f'unsupported escape \\{expr[i]}')
+            last = REGEX_ATOM_SIMPLE
+        elif c == '[':
+            i = _scan_regex_char_class(expr, i)
+            last = REGEX_ATOM_SIMPLE
+        elif c == '(':
+            if expr[i + 1:i + 2] == '?':
+                if expr[i + 2:i + 3] != ':':
+                    raise ValueError('unsupported group - only the (...) and (?:...) groups are supported')
+                i += 2
+            groups.append([False, False])
+            last = REGEX_ATOM_NONE
+        elif c == ')':
+            if len(groups) == 1:
+                raise ValueError('unexpected )')
+            group = groups.pop()
+            groups[-1][0] = groups[-1][0] or group[0]
+            groups[-1][1] = groups[-1][1] or group[1]
+            last = REGEX_ATOM_REPEATED_GROUP if group[0] or group[1] else REGEX_ATOM_SIMPLE
+        elif c == '|':
+            groups[-1][1] = True
+            last = REGEX_ATOM_NONE
+        elif c in '^$':
+            last = REGEX_ATOM_NONE
+        elif c in '*+?{':
+            loop = c != '?'
+            if c == '{':
+                i, upper = _scan_regex_repeat(expr, i)
+                loop = upper != 1
+            if last == REGEX_ATOM_NONE:
+                raise ValueError(f'missing argument to repetition operator {c}')
+            if last == REGEX_ATOM_QUANTIFIED:
+                raise ValueError('nested repetition operator')
+            if last == REGEX_ATOM_REPEATED_GROUP and loop:
+                raise ValueError('repetition of a group which repeats or alternates')
+            if loop:
+                repeats += 1
+                if repeats > MAX_APPROVAL_REGEX_REPEATS:
+                    raise ValueError(f'more than {MAX_APPROVAL_REGEX_REPEATS} repetitions')
+                groups[-1][0] = True
+            # the non-greedy modifier
+            if expr[i + 1:i + 2] == '?':
+                i += 1
+            last = REGEX_ATOM_QUANTIFIED
+        else:
+            last = REGEX_ATOM_SIMPLE
+        i += 1
+    if len(groups) != 1:
+        raise ValueError('missing )')
+
+
+def _portable_regex_escape(c: str) -> bool:
+    """
+    Returns True if the escaped character is supported - the ASCII punctuation and the \\d \\w \\s classes and their
+    negation.
+    """
+    return c in REGEX_ESCAPE_PUNCTUATION or c in REGEX_ESCAPE_CLASSES
+
+
+def _scan_regex_char_class(expr: str, start: int) -> int:
+    """
+    Returns the index of the ] closing the character class starting at the index.
+    """
+    i = start + 1
+    if expr[i:i + 1] == '^':
+        i += 1
+    # a leading ] is a literal
+    if expr[i:i + 1] == ']':
+        i += 1
+    while i < len(expr):
+        c = expr[i]
+        if c == '\\':
+            if i + 1 == len(expr) or not _portable_regex_escape(expr[i + 1]):
+                raise ValueError('unsupported escape in character class')
+            i += 1
+        elif c == '[':
+            raise ValueError("unsupported [ in character class - POSIX classes aren't supported, escape the literal [")
+        elif c == ']':
+            return i
+        i += 1
+    raise ValueError('missing ]')
+
+
+def _scan_regex_repeat(expr: str, start: int):
+    """
+    Parses the {n}, {n,} or {n,m} repetition starting at the index, returns the index of the closing } and the maximum
+    count, -1 when unbounded.
+    """
+    invalid = ValueError('invalid repetition - escape the literal {')
+    end = expr.find('}', start)
+    if end < 0:
+        raise invalid
+    bounds = expr[start + 1:end].split(',', 1)
+    lower = _parse_regex_count(bounds[0])
+    if lower is None:
+        raise invalid
+    if len(bounds) == 1:
+        return end, lower
+    if bounds[1] == '':
+        return end, -1
+    upper = _parse_regex_count(bounds[1])
+    if upper is None or upper < lower:
+        raise invalid
+    return end, upper
+
+
+def _parse_regex_count(value: str) -> Optional[int]:
+    """
+    Parses the count of a repetition, only the ASCII digits are allowed.
+    """
+    if not value or value.strip('0123456789'):
+        return None
+    count = int(value)
+    if count > MAX_APPROVAL_REGEX_COUNT:
+        return None
+    return count
+
+
+class ApprovalRule:
+    """
+    A single compiled approval list entry.
+    """
+
+    def __init__(self, criteria: str, entry: str):
+        self.criteria = criteria
+        self.entry = entry
+        self.deny = False
+        self.literal = None
+        self.pattern = None
+
+        value = entry.strip()
+        if value.startswith(APPROVAL_DENY_PREFIX):
+            self.deny = True
+            value = value[len(APPROVAL_DENY_PREFIX):].strip()
+        if not value:
+            raise ValueError(f'empty {criteria} approval list entry')
+
+        # Regular expressions must match the whole value - the \d \w \s classes are ASCII only, like in Go
+        if value.startswith(APPROVAL_REGEX_PREFIX):
+            expr = value[len(APPROVAL_REGEX_PREFIX):]
+            try:
+                check_portable_regex(expr)
+                self.pattern = re.compile('^(?:' + expr + ')$', re.IGNORECASE | re.ASCII)
+            except (ValueError, re.error) as err:
+                raise ValueError(f'invalid {criteria} approval list regular expression {entry} - {err}')
+            return
+
+        # A domain with the *. or . prefix matches the domain itself and any of its subdomains
+        subdomains = False
+        if criteria == EMAIL_DOMAIN_CRITERIA:
+            for prefix in ('*.', '.'):
+                if value.startswith(prefix):
+                    subdomains = True
+                    value = value[len(prefix):]
+                    break
+            if not value:
+                raise ValueError(f'empty {criteria} approval list entry')
+
+        if not subdomains and '*' not in value and '?' not in value:
+            self.literal = value.lower()
+            return
+
+        expr = '^'
+        if subdomains:
+            expr += r'(?:.*\.)?'
+        for c in value:
+            if c == '*':
+                expr += '.*'
+            elif c == '?':
+                expr += '.'
+            else:
+                expr += re.escape(c)
+        self.pattern = re.compile(expr + '$', re.IGNORECASE)
+
+    def matches(self, value: Optional[str]) -> bool:
+        """
+        Returns True if the value matches the rule.
+        """
+        value = (value or '').strip()
+        if not value:
+            return False
+        if self.pattern is not None:
+            return self.pattern.fullmatch(value) is not None
+        return self.literal == value.lower()
+
+
+class ApprovalRules:
+    """
+    The compiled set of approval list entries of a CCLA signature - invalid entries are logged and skipped.
+    """
+
+    def __init__(self, emails: Optional[Iterable[str]] = None, domains: Optional[Iterable[str]] = None,
+                 github_usernames: Optional[Iterable[str]] = None, github_orgs: Optional[Iterable[str]] = None):
+        fn = 'approval_rules.ApprovalRules'
+        self.rules: List[ApprovalRule] = []
+        for criteria, entries in ((EMAIL_CRITERIA, emails),
+                                  (EMAIL_DOMAIN_CRITERIA, domains),
+                                  (GITHUB_USERNAME_CRITERIA, github_usernames),
+                                  (GITHUB_ORG_CRITERIA, github_orgs)):
+            for entry in entries or []:
+                try:
+                    self.rules.append(ApprovalRule(criteria, entry))
+                except ValueError as err:
+                    cla.log.warning(f'{fn} - skipping invalid {criteria} approval list entry: {entry} - {err}')
+
+    @classmethod
+    def from_signature(cls, ccla_signature):
+        """
+        Compiles the approval list entries of the CCLA signature.
+
+        :param ccla_signature: The CCLA signature.
+        :type ccla_signature: cla.models.model_interfaces.Signature
+        """
+        return cls(emails=ccla_signature.get_email_whitelist(),
+                   domains=ccla_signature.get_domain_whitelist(),
+                   github_usernames=ccla_signature.get_github_whitelist(),
+                   github_orgs=ccla_signature.get_github_org_whitelist())
+
+    def has_criteria(self, criteria: str) -> bool:
+        """
+        Returns True if the rule set contains at least one entry of the criteria.
+        """
+        return any(rule.criteria == criteria for rule in self.rules)
+
+    def explain(self, emails: Optional[Iterable[str]] = None, github_username: Optional[str] = None,
+                github_orgs: Optional[Iterable[str]] = None) -> dict:
+        """
+        Evaluates the user attributes against the rules - deny entries are checked first and override any allow
+        entry. Returns the same fields as the Go ApprovalMatch: approved, denied, criteria, entry, value and reason.
+        """
+        emails = [email.strip() for email in emails or [] if email]
+        values = {
+            EMAIL_CRITERIA: emails,
+            EMAIL_DOMAIN_CRITERIA: [email[email.rindex('@') + 1:] for email in emails if '@' in email],
+            GITHUB_USERNAME_CRITERIA: [github_username] if github_username else [],
+            GITHUB_ORG_CRITERIA: list(github_orgs or []),
+        }
+        for deny in (True, False):
+            for rule in self.rules:
+                if rule.deny != deny:
+                    continue
+                for value in values[rule.criteria]:
+                    if not rule.matches(value):
+                        continue
+                    action = 'denied' if rule.deny else 'approved'
+                    return {
+                        'approved': not rule.deny,
+                        'denied': rule.deny,
+                        'criteria': rule.criteria,
+                        'entry': rule.entry,
+                        'value': value,
+                        'reason': f'{value} is {action} by the {rule.criteria} entry {rule.entry}',
+                    }
+
+        return {
+            'approved': False,
+            'denied': False,
+            'reason': 'no approval list entry matches the user',
+        }
+
+    def is_approved(self, emails: Optional[Iterable[str]] = None, github_username: Optional[str] = None,
+                    github_orgs: Optional[Iterable[str]] = None) -> bool:
+        """
+        Returns True if an allow entry and no deny entry matches the user attributes.
+        """
+        return self.explain(emails, github_username, github_orgs)['approved']
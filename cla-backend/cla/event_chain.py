@@ -0,0 +1,206 @@
+# Copyright The Linux Foundation and each contributor to CommunityBridge.
+# SPDX-License-Identifier: MIT
+
+"""
+Hash chaining of the events, the same chain the Go backend appends to (events/chain.go).
+
+The events of a CLA group are chained in the CLA group stream, the other events of a company are chained in the
+company stream. Every event records its sequence in the stream, the hash of the previous event and its own hash, the
+head of the stream is kept in the event chains table. The event and the new head are written in a single
+transaction conditioned on the previous head, a concurrent append cancels the transaction and the event is linked
+again.
+"""
+
+import datetime
+import hashlib
+import json
+import os
+from typing import Optional
+
+import boto3
+from botocore.exceptions import ClientError
+
+import cla
+
+CHAIN_STREAM_TYPE_CLA_GROUP = 'cla-group'
+CHAIN_STREAM_TYPE_COMPANY = 'company'
+
+# The previous hash of the first event of a stream
+CHAIN_GENESIS_HASH = '0' * 64
+
+# The number of times an event is linked again after a concurrent append to its stream
+MAX_CHAIN_APPEND_ATTEMPTS = 5
+
+stage = os.environ.get('STAGE', '')
+
+
+def chain_stream(event_model) -> Optional[str]:
+    """
+    Returns the key of the stream the event is chained in, None when the event belongs to neither a CLA group nor a
+    company.
+    """
+    if event_model.event_cla_group_id:
+        return '{}#{}'.format(CHAIN_STREAM_TYPE_CLA_GROUP, event_model.event_cla_group_id)
+    if event_model.event_company_id:
+        return '{}#{}'.format(CHAIN_STREAM_TYPE_COMPANY, event_model.event_company_id)
+    return None
+
+
+def _go_json_dumps(content: dict) -> str:
+    """
+    Returns the JSON encoding of the content as the Go encoding/json package writes it - compact, UTF-8 and with the
+    HTML characters and the line and paragraph separators escaped.
+    """
+    encoded = json.dumps(content, separators=(',', ':'), ensure_ascii=False)
+    for char, escaped in (('<', '\\u003c'), ('>', '\\u003e'), ('&', '\\u0026'),
+                          ('\u2028', '\\u2028'), ('\u2029', '\\u2029')):
+        encoded = encoded.replace(char, escaped)
+    return encoded
+
+
+def chain_event_hash(item: dict) -> str:
+    """
+    Returns the hex encoded SHA-256 of the event content and its chain fields. The item holds the stored values of
+    the event, keyed by the attribute names - the content and its order are the ones of chainedEventContent in Go.
+    """
+    content = {
+        'stream': item.get('event_chain_stream') or '',
+        'sequence': int(item.get('event_chain_sequence') or 0),
+        'prev_hash': item.get('event_prev_hash') or '',
+        'event_id': item.get('event_id') or '',
+        'event_type': item.get('event_type') or '',
+        'user_id': item.get('event_user_id') or '',
+        'user_name': item.get('event_user_name') or '',
+        'lf_username': item.get('event_lf_username') or '',
+        'cla_group_id': item.get('event_cla_group_id') or '',
+        'company_id': item.get('event_company_id') or '',
+        'project_id': item.get('event_project_id') or '',
+        'event_time': item.get('event_time') or '',
+        'event_time_epoch': int(item.get('event_time_epoch') or 0),
+        'event_data': item.get('event_data') or '',
+        'event_summary': item.get('event_summary') or '',
+        'contains_pii': bool(item.get('contains_pii')),
+    }
+    return hashlib.sha256(_go_json_dumps(content).encode('utf-8')).hexdigest()
+
+
+def link_event(head: Optional[dict], stream: str, event_model) -> dict:
+    """
+    Sets the chain fields of the event appended after the head, a None head starts the stream. Returns the new head
+    of the stream.
+    """
+    event_model.event_chain_stream = stream
+    event_model.event_chain_sequence = 1
+    event_model.event_prev_hash = CHAIN_GENESIS_HASH
+    if head is not None:
+        event_model.event_chain_sequence = int(head['chain_sequence']) + 1
+        event_model.event_prev_hash = head['chain_head_hash']
+    stored = {name: value for name, _, value in _stored_attributes(event_model)}
+    event_model.event_hash = chain_event_hash(stored)
+
+    return {
+        'chain_stream': stream,
+        'chain_sequence': event_model.event_chain_sequence,
+        'chain_head_hash': event_model.event_hash,
+        'chain_head_event_id': event_model.event_id,
+        'date_modified': stored['event_time'],
+    }
+
+
+def _stored_attributes(event_model):
+    """
+    Yields the name, the type and the serialized value of the attributes of the event, the attributes without a
+    value are skipped the same way the model skips them when it is saved.
+    """
+    for name, attr in event_model.get_attributes().items():
+        value = getattr(event_model, name)
+        if value is None:
+            continue
+        serialized = attr.serialize(value)
+        if serialized is None:
+            continue
+        yield attr.attr_name, attr.attr_type, serialized
+
+
+def _dynamodb_client():
+    if stage == 'local':
+        return boto3.client('dynamodb', endpoint_url='http://localhost:8000')
+    return boto3.client('dynamodb')
+
+
+def get_chain_head(client, stream: str) -> Optional[dict]:
+    """
+    Returns the head of the event stream, None when no event was appended to the stream.
+    """
+    result = client.get_item(
+        TableName='cla-{}-event-chains'.format(stage),
+        Key={'chain_stream': {'S': stream}},
+        ConsistentRead=True,
+    )
+    item = result.get('Item')
+    if not item:
+        return None
+    return {
+        'chain_stream': item['chain_stream']['S'],
+        'chain_sequence': int(item['chain_sequence']['N']),
+        'chain_head_hash': item['chain_head_hash']['S'],
+    }
+
+
+def append_chained_event(event_model, client=None) -> None:
+    """
+    Links the event to the head of its stream and writes the event along with the new head in a single transaction.
+
+    :param event_model: The event to append, its stream is the one of its CLA group or company
+    :type event_model: cla.models.dynamo_models.EventModel
+    """
+    fn = 'cla.event_chain.append_chained_event'
+    stream = chain_stream(event_model)
+    if stream is None:
+        raise ValueError('the event belongs to neither a CLA group nor a company')
+    if client is None:
+        client = _dynamodb_client()
+
+    # the chained content is the one stored, the event time is the time of the append as in the Go backend
+    now = datetime.datetime.utcnow()
+    event_model.event_time = now
+    event_model.event_time_epoch = int(now.replace(tzinfo=datetime.timezone.utc).timestamp())
+
+    attempt = 1
+    while True:
+        head = get_chain_head(client, stream)
+        new_head = link_event(head, stream, event_model)
+
+        head_put = {
+            'TableName': 'cla-{}-event-chains'.format(stage),
+            'Item': {
+                'chain_stream': {'S': new_head['chain_stream']},
+                'chain_sequence': {'N': str(new_head['chain_sequence'])},
+                'chain_head_hash': {'S': new_head['chain_head_hash']},
+                'chain_head_event_id': {'S': new_head['chain_head_event_id']},
+                'date_modified': {'S': new_head['date_modified']},
+            },
+        }
+        if head is None:
+            head_put['ConditionExpression'] = 'attribute_not_exists(chain_stream)'
+        else:
+            head_put['ConditionExpression'] = 'chain_sequence = :sequence'
+            head_put['ExpressionAttributeValues'] = {':sequence': {'N': str(head['chain_sequence'])}}
+
+        try:
+            client.transact_write_items(TransactItems=[
+                {'Put': head_put},
+                {'Put': {
+                    'TableName': event_model.Meta.table_name,
+                    'Item': {name: {attr_type: value} for name, attr_type, value in _stored_attributes(event_model)},
+                }},
+            ])
+            return
+        except ClientError as err:
+            if err.response.get('Error', {}).get('Code') == 'TransactionCanceledException' \
+                    and attempt < MAX_CHAIN_APPEND_ATTEMPTS:
+                cla.log.debug(f'{fn} - concurrent append to the event stream: {stream} - '
+                              f'linking the event again, attempt: {attempt}')
+                attempt += 1
+                continue
+            raise
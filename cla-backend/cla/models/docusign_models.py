@@ -9,6 +9,7 @@ https://developers.docusign.com/esign-rest-api/guides/post-go-live
 
 """
 
+import hashlib
 import io
 import os
 import urllib.request
@@ -1830,6 +1831,17 @@ class DocuSign(signing_service_interface.SigningService):
         cla.log.debug(f'send_to_s3 - uploading document with filename: {filename}')
         self.s3storage.store(filename, document_data)
 
+        # Record the hash of the stored document - the document integrity checks compare the stored document with it
+        document_sha256 = hashlib.sha256(document_data).hexdigest()
+        try:
+            signature = Signature()
+            signature.load(str(signature_id))
+            signature.set_signature_document_sha256(document_sha256)
+            signature.save()
+            cla.log.debug(f'send_to_s3 - recorded document sha256: {document_sha256} on signature: {signature_id}')
+        except DoesNotExist:
+            cla.log.warning(f'send_to_s3 - unable to record document sha256 - signature not found: {signature_id}')
+
     def get_document_resource(self, url):  # pylint: disable=no-self-use
         """
         Mockable method to fetch the PDF for signing.
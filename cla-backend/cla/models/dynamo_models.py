@@ -8,7 +8,6 @@ Easily access CLA models backed by DynamoDB using pynamodb.
 import base64
 import datetime
 import os
-import re
 import time
 import uuid
 from typing import Optional, List
@@ -29,6 +28,8 @@ from pynamodb.indexes import GlobalSecondaryIndex, AllProjection
 from pynamodb.models import Model
 
 import cla
+from cla.approval_rules import ApprovalRules, GITHUB_ORG_CRITERIA
+from cla.event_chain import append_chained_event, chain_stream
 from cla.models import model_interfaces, key_value_store_interface, DoesNotExist
 from cla.models.event_types import EventType
 from cla.models.model_interfaces import User, Signature, ProjectCLAGroup, Repository, Gerrit
@@ -1448,6 +1449,9 @@ class UserModel(BaseModel):
     user_ldap_id = UnicodeAttribute(null=True)
     user_github_id_index = GitHubUserIndex()
     github_user_external_id_index = GithubUserExternalIndex()
+    # GitLab account of the user, the merge request CLA checks locate the user by the GitLab user ID
+    user_gitlab_id = UnicodeAttribute(null=True)
+    user_gitlab_username = UnicodeAttribute(null=True)
     note = UnicodeAttribute(null=True)
     lf_email = UnicodeAttribute(null=True)
     lf_username = UnicodeAttribute(null=True)
@@ -1814,74 +1818,33 @@ class User(model_interfaces.User):  # pylint: disable=too-many-public-methods
         :rtype: bool
         """
         fn = 'dynamo_models.preprocess_pattern'
-        for pattern in patterns:
-            if pattern.startswith("*."):
-                pattern = pattern.replace("*.", ".*")
-            elif pattern.startswith("*"):
-                pattern = pattern.replace("*", ".*")
-            elif pattern.startswith("."):
-                pattern = pattern.replace(".", ".*")
-
-            preprocessed_pattern = "^.*@" + pattern + "$"
-            pat = re.compile(preprocessed_pattern)
-            for email in emails:
-                if pat.match(email) is not None:
-                    self.log_debug(f'{fn} - found user email in email approval pattern')
-                    return True
+        if ApprovalRules(domains=patterns).is_approved(emails=emails):
+            self.log_debug(f'{fn} - found user email in email approval pattern')
+            return True
         return False
 
     # Accepts a Signature object
 
     def is_approved(self, ccla_signature: Signature) -> bool:
         """
-        Helper function to determine whether at least one of the user's email
-        addresses are whitelisted for a particular ccla signature.
+        Helper function to determine whether the user is approved by the approval lists of a particular ccla
+        signature. The entries are matched like the Go backend does, see cla.approval_rules - a deny entry
+        overrides any matching entry.
 
         :param ccla_signature: The ccla signature to check against.
         :type ccla_signature: cla.models.Signature
-        :return: True if at least one email is whitelisted, False otherwise.
+        :return: True if the user is approved, False otherwise.
         :rtype: bool
         """
         fn = 'dynamo_models.is_approved'
+        rules = ApprovalRules.from_signature(ccla_signature)
+
         # Returns the union of lf_emails and emails (separate columns)
         emails = self.get_all_user_emails()
         if len(emails) > 0:
             # remove leading and trailing whitespace before checking emails
             emails = [email.strip() for email in emails]
 
-        # First, we check email whitelist
-        whitelist = ccla_signature.get_email_whitelist()
-        cla.log.debug(f'{fn} - testing user emails: {emails} with '
-                      f'CCLA approval emails: {whitelist}')
-
-        if whitelist is not None:
-            for email in emails:
-                # Case insensitive match
-                if email.lower() in (s.lower() for s in whitelist):
-                    cla.log.debug(f'{fn} - found user email in email approval list')
-                    return True
-        else:
-            cla.log.debug(f'{fn} - no email whitelist match for user: {self}')
-
-        # Secondly, let's check domain whitelist
-        # If a naked domain (e.g. google.com) is provided, we prefix it with '^.*@',
-        # so that sub-domains are not allowed.
-        # If a '*', '*.' or '.' prefix is provided, we replace the prefix with '.*\.',
-        # which will allow subdomains.
-        patterns = ccla_signature.get_domain_whitelist()
-        cla.log.debug(f'{fn} - testing user email domains: {emails} with '
-                      f'domain approval values: {patterns}')
-
-        if patterns is not None:
-            if self.preprocess_pattern(emails, patterns):
-                return True
-            else:
-                self.log_debug(f'{fn} - did not match email: {emails} with domain: {patterns}')
-        else:
-            cla.log.debug(f'{fn} - no domain approval patterns defined - '
-                          'skipping domain approval checks')
-
-        # Third and Forth, check github whitelists
         github_username = self.get_user_github_username()
         github_id = self.get_user_github_id()
 
@@ -1909,54 +1872,23 @@ class User(model_interfaces.User):  # pylint: disable=too-many-public-methods
                 self.set_user_github_id(github_id)
                 self.save()
 
-        # GitHub username approval list processing
         if github_username is not None:
             # remove leading and trailing whitespace from github username
             github_username = github_username.strip()
-            github_whitelist = ccla_signature.get_github_whitelist()
-            cla.log.debug(f'{fn} - testing user github username: {github_username} with '
-                          f'CCLA github approval list: {github_whitelist}')
-
-            if github_whitelist is not None:
-                # case insensitive search
-                if github_username.lower() in (s.lower() for s in github_whitelist):
-                    cla.log.debug(f'{fn} - found github username in github approval list')
-                    return True
-        else:
-            cla.log.debug(f'{fn} - users github_username is not defined - '
-                          'skipping github username approval list check')
 
-        # Check github org approval list
-        if github_username is not None:
-            # Load the github org approval list for this CCLA signature record
-            github_org_approval_list = ccla_signature.get_github_org_whitelist()
-            if github_org_approval_list is not None:
-                # Fetch the list of orgs associated with this user
-                cla.log.debug(f'{fn} - determining if github user {github_username} is associated '
-                              f'with any of the github organizations: {github_org_approval_list}')
-                github_orgs = cla.utils.lookup_github_organizations(github_username)
-                if "error" not in github_orgs:
-                    cla.log.debug(f'{fn} - testing user github org: {github_orgs} with '
-                                  f'CCLA github org approval list: {github_org_approval_list}')
-
-                    for dynamo_github_org in github_org_approval_list:
-                        # case insensitive search
-                        if dynamo_github_org.lower() in (s.lower() for s in github_orgs):
-                            cla.log.debug(f'{fn} - found matching github organization for user')
-                            return True
-                        else:
-                            cla.log.debug(f'{fn} - user {github_username} is not in the '
-                                          f'organization: {dynamo_github_org}')
-                else:
-                    cla.log.warning(f'{fn} - unable to lookup github organizations for the user: {github_username}: '
-                                    f'{github_orgs}')
-            else:
-                cla.log.debug(f'{fn} - no github organization approval list defined for this CCLA')
-        else:
-            cla.log.debug(f'{fn} - user\'s github_username is not defined - skipping github org approval list check')
+        # Fetch the list of orgs associated with this user only when the approval list has github org entries
+        github_orgs = []
+        if github_username is not None and rules.has_criteria(GITHUB_ORG_CRITERIA):
+            github_orgs = cla.utils.lookup_github_organizations(github_username)
+            if "error" in github_orgs:
+                cla.log.warning(f'{fn} - unable to lookup github organizations for the user: {github_username}: '
+                                f'{github_orgs}')
+                github_orgs = []
 
-        cla.log.debug(f'{fn} - unable to find user in any whitelist')
-        return False
+        match = rules.explain(emails=emails, github_username=github_username, github_orgs=github_orgs)
+        cla.log.debug(f'{fn} - testing user emails: {emails}, github username: {github_username} and '
+                      f'github orgs: {github_orgs} with the CCLA approval lists - {match["reason"]}')
+        return match['approved']
 
     def get_users_by_company(self, company_id):
         user_generator = self.model.scan(user_company_id__eq=str(company_id))
@@ -2296,6 +2228,12 @@ class SignatureModel(BaseModel):  # pylint: disable=too-many-instance-attributes
     email_whitelist = ListAttribute(null=True)
     github_whitelist = ListAttribute(null=True)
     github_org_whitelist = ListAttribute(null=True)
+    # expiry of the time-bounded approval list entries, managed by the Go backend - declared so that saving the
+    # signature keeps them, the partition and next expiry are the keys of the sparse approval list expiry index
+    approval_list_expirations = MapAttribute(null=True)
+    approval_list_expiry_warnings_sent = ListAttribute(null=True)
+    approval_list_expiry_partition = UnicodeAttribute(null=True)
+    approval_list_next_expiry = UnicodeAttribute(null=True)
 
     # Additional attributes for ICLAs
     user_email = UnicodeAttribute(null=True)
@@ -2306,6 +2244,12 @@ class SignatureModel(BaseModel):  # pylint: disable=too-many-instance-attributes
     user_docusign_date_signed = UnicodeAttribute(null=True)
     user_docusign_raw_xml = UnicodeAttribute(null=True)
 
+    # SHA-256 hash of the signed document stored on S3
+    signature_document_sha256 = UnicodeAttribute(null=True)
+    # Result and time of the last integrity check of the signed document, declared so a save doesn't drop them
+    signature_document_integrity_status = UnicodeAttribute(null=True)
+    signature_document_verified_on = UnicodeAttribute(null=True)
+
 
 class Signature(model_interfaces.Signature):  # pylint: disable=too-many-public-methods
     """
@@ -2607,6 +2551,15 @@ class Signature(model_interfaces.Signature):  # pylint: disable=too-many-public-
     def get_user_docusign_raw_xml(self):
         return self.model.user_docusign_raw_xml
 
+    def get_signature_document_sha256(self):
+        return self.model.signature_document_sha256
+
+    def get_signature_document_integrity_status(self):
+        return self.model.signature_document_integrity_status
+
+    def get_signature_document_verified_on(self):
+        return self.model.signature_document_verified_on
+
     def set_signature_id(self, signature_id):
         self.model.signature_id = str(signature_id)
 
@@ -2747,6 +2700,15 @@ class Signature(model_interfaces.Signature):  # pylint: disable=too-many-public-
     def set_user_docusign_raw_xml(self, user_docusign_raw_xml):
         self.model.user_docusign_raw_xml = user_docusign_raw_xml
 
+    def set_signature_document_sha256(self, signature_document_sha256):
+        self.model.signature_document_sha256 = signature_document_sha256
+
+    def set_signature_document_integrity_status(self, signature_document_integrity_status):
+        self.model.signature_document_integrity_status = signature_document_integrity_status
+
+    def set_signature_document_verified_on(self, signature_document_verified_on):
+        self.model.signature_document_verified_on = signature_document_verified_on
+
     def get_signatures_by_reference(
             self,  # pylint: disable=too-many-arguments
             reference_id,
@@ -4243,6 +4205,13 @@ class EventModel(BaseModel):
     event_date_and_contains_pii = UnicodeAttribute(null=True)
     company_id_external_project_id = UnicodeAttribute(null=True)
     contains_pii = BooleanAttribute(null=True)
+
+    # the hash chain of the CLA group or company stream of the event, see cla/event_chain.py
+    event_chain_stream = UnicodeAttribute(null=True)
+    event_chain_sequence = NumberAttribute(null=True)
+    event_prev_hash = UnicodeAttribute(null=True)
+    event_hash = UnicodeAttribute(null=True)
+
     user_id_index = EventUserIndex()
     event_type_index = EventTypeIndex()
 
@@ -4337,6 +4306,10 @@ class Event(model_interfaces.Event):
 
     def save(self) -> None:
         self.model.date_modified = datetime.datetime.utcnow()
+        # a new event of a CLA group or a company is appended to the hash chain of its stream
+        if self.model.event_chain_stream is None and chain_stream(self.model) is not None:
+            append_chained_event(self.model)
+            return
         self.model.save()
 
     def delete(self):
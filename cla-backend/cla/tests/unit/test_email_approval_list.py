@@ -5,6 +5,7 @@ from unittest.mock import patch, MagicMock
 
 import pytest
 
+from cla.approval_rules import ApprovalRule, ApprovalRules, EMAIL_CRITERIA
 from cla.models.dynamo_models import Signature, User, UserModel
 
 
@@ -80,3 +81,56 @@ def test_gerrit_project_approval_listing(create_user):
     signature.get_email_whitelist = MagicMock(return_value={"phillip.leigh@amdocs.com"})
     create_user.get_all_user_emails = MagicMock(return_value=["phillip.leigh@amdocs.com"])
     assert create_user.is_approved(signature) == True
+
+
+def test_deny_entry_overrides_approval(create_user):
+    """Test a deny entry of any approval list overrides a matching entry"""
+    signature = Signature()
+    signature.get_email_whitelist = MagicMock(return_value=["!*@contractors.bar.com"])
+    signature.get_domain_whitelist = MagicMock(return_value=["*.bar.com"])
+    signature.get_github_whitelist = MagicMock(return_value=None)
+    signature.get_github_org_whitelist = MagicMock(return_value=None)
+    create_user.get_user_github_username = MagicMock(return_value=None)
+    create_user.get_user_github_id = MagicMock(return_value=None)
+    create_user.get_all_user_emails = MagicMock(return_value=["harold@contractors.bar.com"])
+    assert create_user.is_approved(signature) == False
+    create_user.get_all_user_emails = MagicMock(return_value=["harold@help.bar.com"])
+    assert create_user.is_approved(signature) == True
+
+
+def test_regex_and_glob_entries():
+    """Test the regex: and glob entries match the whole value, case insensitive"""
+    rules = ApprovalRules(emails=["jane.*@bar.com", "regex:[a-z]+@(eng|ops)\\.bar\\.net"], github_usernames=["bot-?"])
+    assert rules.is_approved(emails=["Jane.Doe@bar.com"])
+    assert not rules.is_approved(emails=["harold@bar.com"])
+    assert rules.is_approved(emails=["harold@ops.bar.net"])
+    assert not rules.is_approved(emails=["harold1@ops.bar.net"])
+    assert rules.is_approved(github_username="bot-1")
+    assert not rules.is_approved(github_username="bot-12")
+
+
+def test_literal_entries_are_not_patterns():
+    """Test the regular expression characters of a literal entry are matched literally"""
+    rules = ApprovalRules(domains=["bar.com"], github_orgs=["foo-org"])
+    assert not rules.is_approved(emails=["harold@barxcom"])
+    assert rules.is_approved(github_orgs=["Foo-Org"])
+    match = rules.explain(emails=["harold@foo.com"], github_orgs=["other-org"])
+    assert match["approved"] == False
+    assert match["denied"] == False
+
+
+def test_regex_entries_limited_to_the_portable_syntax():
+    """Test the regex: entries which backtrack catastrophically or which Go evaluates differently are rejected"""
+    for expr in ["(a+)+$", "(a|aa)*@bar\\.com", "[a-z]*[a-z]*[a-z]*[a-z]*@bar\\.com", "\\p{L}+@bar\\.com",
+                 "(?P<name>[a-z]+)@bar\\.com", "[[:alpha:]]+@bar\\.com", "(?i)harold@bar\\.com", "a{,3}@bar\\.com"]:
+        with pytest.raises(ValueError):
+            ApprovalRule(EMAIL_CRITERIA, "regex:" + expr)
+
+    # the rejected entries are skipped, like in the Go backend
+    rules = ApprovalRules(emails=["regex:(a+)+$", "regex:^[a-z]+\\.[a-z]+@(?:eng|ops)\\.bar\\.net$"],
+                          github_usernames=["regex:bot-\\d{1,3}"])
+    assert len(rules.rules) == 2
+    assert not rules.is_approved(emails=["a" * 64 + "!"])
+    assert rules.is_approved(emails=["Harold.Doe@eng.bar.net"])
+    assert rules.is_approved(github_username="bot-123")
+    assert not rules.is_approved(github_username="bot-1234")
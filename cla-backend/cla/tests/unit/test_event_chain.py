@@ -0,0 +1,108 @@
+# Copyright The Linux Foundation and each contributor to CommunityBridge.
+# SPDX-License-Identifier: MIT
+
+from unittest.mock import MagicMock
+
+from botocore.exceptions import ClientError
+
+from cla.event_chain import CHAIN_GENESIS_HASH, append_chained_event, chain_event_hash, chain_stream
+from cla.models.dynamo_models import EventModel
+
+CLA_GROUP_ID = "d5412fa7-0a29-4a6b-a0d1-6a8d4e6ebf4c"
+COMPANY_ID = "0b1e0a4c-8d3b-4b55-9a3e-1d3c2a4f5e6d"
+
+
+def create_event_model(**kwargs) -> EventModel:
+    model = EventModel()
+    model.event_id = "8f1b5a8e-5b0a-4c8e-9d6a-2f3e4d5c6b7a"
+    model.event_type = "AddCLAManager"
+    model.event_user_id = "user-1234"
+    model.event_data = "jdoe added as CLA manager"
+    for name, value in kwargs.items():
+        setattr(model, name, value)
+    return model
+
+
+def transact_event_item(client, call: int = -1) -> dict:
+    items = client.transact_write_items.call_args_list[call][1]["TransactItems"]
+    return {name: list(value.values())[0] for name, value in items[1]["Put"]["Item"].items()}
+
+
+def test_chain_event_hash_matches_the_go_backend():
+    """ Test the hash of the event content is the one of the Go backend (events/chain_test.go) """
+    item = {
+        "event_chain_stream": "cla-group#" + CLA_GROUP_ID,
+        "event_chain_sequence": "7",
+        "event_prev_hash": CHAIN_GENESIS_HASH,
+        "event_id": "8f1b5a8e-5b0a-4c8e-9d6a-2f3e4d5c6b7a",
+        "event_type": "AddCLAManager",
+        "event_user_id": "user-1234",
+        "event_user_name": "Jane Doë",
+        "event_cla_group_id": CLA_GROUP_ID,
+        "event_company_id": COMPANY_ID,
+        "event_time": "2020-03-04T05:06:07.000008+0000",
+        "event_time_epoch": "1583298367",
+        "event_data": "<jdoe@example.org> & \"jane\" added as CLA manager",
+        "event_summary": "CLA manager added\ttwice",
+    }
+    assert chain_event_hash(item) == "219db0a84878e5e81ba16c00398fcc734916e3dcdd773d3838fc692909503ced"
+
+
+def test_chain_stream():
+    """ Test the events of a CLA group are chained in the CLA group stream, the other company events in the company stream """
+    assert chain_stream(create_event_model(event_cla_group_id=CLA_GROUP_ID, event_company_id=COMPANY_ID)) == \
+        "cla-group#" + CLA_GROUP_ID
+    assert chain_stream(create_event_model(event_company_id=COMPANY_ID)) == "company#" + COMPANY_ID
+    assert chain_stream(create_event_model()) is None
+
+
+def test_append_chained_event_links_the_stream():
+    """ Test the event is written along with the new head of its stream """
+    client = MagicMock()
+    client.get_item.return_value = {}
+    first = create_event_model(event_cla_group_id=CLA_GROUP_ID)
+    append_chained_event(first, client)
+
+    assert first.event_chain_sequence == 1
+    assert first.event_prev_hash == CHAIN_GENESIS_HASH
+    stored = transact_event_item(client)
+    assert stored["event_chain_stream"] == "cla-group#" + CLA_GROUP_ID
+    assert chain_event_hash(stored) == stored["event_hash"]
+    head_put = client.transact_write_items.call_args[1]["TransactItems"][0]["Put"]
+    assert head_put["ConditionExpression"] == "attribute_not_exists(chain_stream)"
+    assert head_put["Item"]["chain_head_hash"] == {"S": first.event_hash}
+
+    client.get_item.return_value = {"Item": {
+        "chain_stream": {"S": "cla-group#" + CLA_GROUP_ID},
+        "chain_sequence": {"N": "1"},
+        "chain_head_hash": {"S": first.event_hash},
+    }}
+    second = create_event_model(event_id="1c9d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f", event_cla_group_id=CLA_GROUP_ID)
+    append_chained_event(second, client)
+
+    assert second.event_chain_sequence == 2
+    assert second.event_prev_hash == first.event_hash
+    head_put = client.transact_write_items.call_args[1]["TransactItems"][0]["Put"]
+    assert head_put["ConditionExpression"] == "chain_sequence = :sequence"
+    assert head_put["ExpressionAttributeValues"] == {":sequence": {"N": "1"}}
+
+
+def test_append_chained_event_after_a_concurrent_append():
+    """ Test the event is linked again to the head written by a concurrent append """
+    client = MagicMock()
+    client.get_item.side_effect = [{}, {"Item": {
+        "chain_stream": {"S": "company#" + COMPANY_ID},
+        "chain_sequence": {"N": "1"},
+        "chain_head_hash": {"S": "a" * 64},
+    }}]
+    client.transact_write_items.side_effect = [
+        ClientError({"Error": {"Code": "TransactionCanceledException"}}, "TransactWriteItems"),
+        {},
+    ]
+    event = create_event_model(event_company_id=COMPANY_ID)
+    append_chained_event(event, client)
+
+    assert client.transact_write_items.call_count == 2
+    assert event.event_chain_sequence == 2
+    assert event.event_prev_hash == "a" * 64
+    assert transact_event_item(client)["event_hash"] == event.event_hash
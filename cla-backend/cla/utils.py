@@ -20,6 +20,7 @@ from hug.middleware import SessionMiddleware
 from requests_oauthlib import OAuth2Session
 
 import cla
+from cla.approval_rules import ApprovalRules, GITHUB_ORG_CRITERIA
 from cla.middleware import CLALogMiddleware
 from cla.models import DoesNotExist
 from cla.models.dynamo_models import User, Signature, Repository, \
@@ -1431,67 +1432,28 @@ def is_approved(ccla_signature: Signature, email=None, github_username=None, git
     :param github_id: A given github id checked against ccla signature github/github-org whitelists
     """
     fn = 'utils.is_approved'
-
-    if email:
-        # Checking email whitelist
-        whitelist = ccla_signature.get_email_whitelist()
-        cla.log.debug(f'{fn} - testing email: {email} with CCLA approval list emails: {whitelist}')
-        if whitelist is not None:
-            if email.lower() in (s.lower() for s in whitelist):
-                cla.log.debug(f'{fn} found user email in email approval list')
-                return True
-
-        # Checking domain whitelist
-        patterns = ccla_signature.get_domain_whitelist()
-        cla.log.debug(f"{fn} - testing user email domain: {email} with "
-                      f"domain approval list values in database: {patterns}")
-        if patterns is not None:
-            if get_user_instance().preprocess_pattern([email], patterns):
-                return True
-            else:
-                cla.log.debug(f"{fn} - did not match email: {email} with domain: {patterns}")
-        else:
-            cla.log.debug(f'{fn} - no domain approval list patterns defined - skipping domain approval list check')
+    rules = ApprovalRules.from_signature(ccla_signature)
 
     if github_id:
         github_username = lookup_user_github_username(github_id)
 
-    # Github username whitelist
     if github_username is not None:
         # remove leading and trailing whitespace from github username
         github_username = github_username.strip()
-        github_approval_list = ccla_signature.get_github_whitelist()
-        cla.log.debug(f"{fn} - testing user github username: {github_username} with "
-                      f"CCLA github approval list: {github_approval_list}")
-
-        if github_approval_list is not None:
-            # case insensitive search
-            if github_username.lower() in (s.lower() for s in github_approval_list):
-                cla.log.debug(f'{fn} - found github username in github approval list')
-                return True
-    else:
-        cla.log.debug(f'{fn} - users github_username is not defined - skipping github username approval list check')
 
-    # Check github org approval list
-    if github_username is not None:
+    # Fetch the list of orgs this user is part of only when the approval list has github org entries
+    github_orgs = []
+    if github_username is not None and rules.has_criteria(GITHUB_ORG_CRITERIA):
         github_orgs = cla.utils.lookup_github_organizations(github_username)
-        if "error" not in github_orgs:
-            # Fetch the list of orgs this user is part of
-            github_org_approval_list = ccla_signature.get_github_org_whitelist()
-            cla.log.debug(f'{fn} - testing user github orgs: {github_orgs} with '
-                          f'CCLA github org approval list values: {github_org_approval_list}')
-
-            if github_org_approval_list is not None:
-                for dynamo_github_org in github_org_approval_list:
-                    # case insensitive search
-                    if dynamo_github_org.lower() in (s.lower() for s in github_orgs):
-                        cla.log.debug(f'{fn} - found matching github org for user')
-                        return True
-    else:
-        cla.log.debug(f'{fn} - users github_username is not defined - skipping github org approval list check')
-
-    cla.log.debug(f'{fn} - unable to find user in any approval list')
-    return False
+        if "error" in github_orgs:
+            cla.log.warning(f'{fn} - unable to lookup github organizations for the user: {github_username}: '
+                            f'{github_orgs}')
+            github_orgs = []
+
+    match = rules.explain(emails=[email] if email else [], github_username=github_username, github_orgs=github_orgs)
+    cla.log.debug(f'{fn} - testing email: {email}, github username: {github_username} and github orgs: '
+                  f'{github_orgs} with the CCLA approval lists - {match["reason"]}')
+    return match['approved']
 
 
 def audit_event(func):
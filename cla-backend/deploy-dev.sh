@@ -10,6 +10,14 @@ declare -a golang_files=( "backend-aws-lambda"
    "dynamo-events-lambda"
    "zipbuilder-scheduler-lambda"
    "zipbuilder-lambda"
+   "approval-list-expiry-lambda"
+   "event-webhooks-lambda"
+   "signature-integrity-lambda"
+   "resign-campaigns-lambda"
+   "gerrit-reconcile-lambda"
+   "branch-protection-audit-lambda"
+   "event-chain-checkpoint-lambda"
+   "event-search-reindex-lambda"
    "functional-tests")
 
 echo "Installing dependencies..."
@@ -22,6 +30,14 @@ if [[ ! -f "backend-aws-lambda" ]] || \
   [[ ! -f "dynamo-events-lambda" ]] || \
   [[ ! -f "zipbuilder-scheduler-lambda" ]] || \
   [[ ! -f "zipbuilder-lambda" ]] || \
+  [[ ! -f "approval-list-expiry-lambda" ]] || \
+  [[ ! -f "event-webhooks-lambda" ]] || \
+  [[ ! -f "signature-integrity-lambda" ]] || \
+  [[ ! -f "resign-campaigns-lambda" ]] || \
+  [[ ! -f "gerrit-reconcile-lambda" ]] || \
+  [[ ! -f "branch-protection-audit-lambda" ]] || \
+  [[ ! -f "event-chain-checkpoint-lambda" ]] || \
+  [[ ! -f "event-search-reindex-lambda" ]] || \
   [[ ! -f "functional-tests" ]]; then
     echo "Missing one or more golang files - building golang binaries..."
     pushd "../cla-backend-go"
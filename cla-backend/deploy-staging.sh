@@ -9,7 +9,15 @@ declare -a lambdas=("backend-aws-lambda"
   "metrics-aws-lambda"
   "dynamo-events-lambda"
   "zipbuilder-scheduler-lambda"
-  "zipbuilder-lambda")
+  "zipbuilder-lambda"
+  "approval-list-expiry-lambda"
+  "event-webhooks-lambda"
+  "signature-integrity-lambda"
+  "resign-campaigns-lambda"
+  "gerrit-reconcile-lambda"
+  "branch-protection-audit-lambda"
+  "event-chain-checkpoint-lambda"
+  "event-search-reindex-lambda")
 
 echo "Installing dependencies..."
 yarn install